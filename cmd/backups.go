@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	backupruns "github.com/developerkunal/OpenMorph/internal/backup"
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupsKeep int
+	backupsDir  string
+)
+
+var backupsCmd = &cobra.Command{
+	Use:   "backups",
+	Short: "List, prune and restore timestamped backups",
+	Long: `Manage the run folders --backup creates with backups.naming: timestamped. Each run folder is
+named after the time of the run and mirrors the paths of the files it overwrote.
+
+The folder is backups.dir from the config file (--config, or .openapirc.yaml), or --dir.`,
+}
+
+var backupsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the backed-up runs, oldest first",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		dir, _ := backupsSettings()
+		runs, err := backupruns.List(dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Backup error:", err)
+			os.Exit(2)
+		}
+		if len(runs) == 0 {
+			fmt.Printf("ℹ️  %sNo backups in %s%s\n", colorYellow, dir, colorReset)
+			return
+		}
+		for _, run := range runs {
+			fmt.Printf("%s%s%s  %d files\n", colorBold, run.ID, colorReset, len(run.Files))
+		}
+	},
+}
+
+var backupsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove all but the newest backed-up runs",
+	Long: `Remove all but the newest --keep runs. Without --keep, backups.max_backups from the config
+file is used.`,
+	Example: `  openmorph backups prune --keep 5`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dir, maxBackups := backupsSettings()
+		keep := maxBackups
+		if cmd.Flags().Changed("keep") {
+			keep = backupsKeep
+		}
+		if keep <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: set --keep or backups.max_backups to the number of runs to keep")
+			os.Exit(1)
+		}
+
+		removed, err := backupruns.Prune(dir, keep)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Backup error:", err)
+			os.Exit(2)
+		}
+		for _, run := range removed {
+			fmt.Printf("🗑️  Removed %s\n", run.ID)
+		}
+		fmt.Printf("%s✅ Pruned %d runs, kept up to %d%s\n", colorGreen, len(removed), keep, colorReset)
+	},
+}
+
+var backupsRestoreCmd = &cobra.Command{
+	Use:   "restore [run-id]",
+	Short: "Write the files of a backed-up run back to their original paths",
+	Long: `Write every file of the run back to where it was backed up from, overwriting the current
+contents. Without a run ID, the newest run is restored.`,
+	Example: `  openmorph backups restore
+  openmorph backups restore 20260101T120000Z`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		dir, _ := backupsSettings()
+		id := ""
+		if len(args) == 1 {
+			id = args[0]
+		}
+		run, err := backupruns.Restore(dir, id)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Backup error:", err)
+			os.Exit(2)
+		}
+		for _, original := range run.Files {
+			fmt.Printf("♻️  Restored %s\n", original)
+		}
+		fmt.Printf("%s✅ Restored %d files from %s%s\n", colorGreen, len(run.Files), run.ID, colorReset)
+	},
+}
+
+// backupsSettings returns the backup folder and max_backups from --dir and the config file
+func backupsSettings() (string, int) {
+	var backups config.Backups
+	path := configFile
+	if path == "" {
+		if _, err := os.Stat(".openapirc.yaml"); err == nil {
+			path = ".openapirc.yaml"
+		}
+	}
+	if path != "" && !noConfig {
+		cfg, err := config.LoadConfigFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		backups = cfg.Backups
+	}
+	if backupsDir != "" {
+		backups.Dir = backupsDir
+	}
+	return transform.BackupDir(backups), backups.MaxBackups
+}
+
+func init() {
+	backupsCmd.PersistentFlags().StringVar(&backupsDir, "dir", "", "Backup folder (default backups.dir, or "+backupruns.DefaultDir+")")
+	backupsPruneCmd.Flags().IntVar(&backupsKeep, "keep", 0, "Number of newest runs to keep")
+	backupsCmd.AddCommand(backupsListCmd, backupsPruneCmd, backupsRestoreCmd)
+	rootCmd.AddCommand(backupsCmd)
+}
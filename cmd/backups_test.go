@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_BackupsRestore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "specs")
+	if err := os.MkdirAll(input, 0750); err != nil {
+		t.Fatalf("failed to create input: %v", err)
+	}
+	file := filepath.Join(input, "api.yaml")
+	original := "openapi: 3.0.0\ninfo:\n  x-a: 1\n"
+	if err := os.WriteFile(file, []byte(original), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	backupDir := filepath.Join(dir, "backups")
+	configPath := filepath.Join(dir, "openmorph.yaml")
+	config := "mappings:\n  x-a: x-z\nbackups:\n  naming: timestamped\n  dir: " + backupDir + "\n"
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", input, "--config", configPath, "--backup")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("transform failed: %v\n%s", err, out)
+	} else if !strings.Contains(string(out), "Backed up 1 files to "+backupDir) {
+		t.Errorf("expected the backup folder in the output:\n%s", out)
+	}
+	if data, _ := os.ReadFile(file); !strings.Contains(string(data), "x-z") {
+		t.Fatalf("expected the file to be transformed, got:\n%s", data)
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "backups", "restore", "--config", configPath)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("restore failed: %v\n%s", err, out)
+	}
+	if data, _ := os.ReadFile(file); string(data) != original {
+		t.Errorf("expected the original to be restored, got:\n%s", data)
+	}
+}
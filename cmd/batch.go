@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/notify"
+	"github.com/developerkunal/OpenMorph/internal/report"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	batchManifest string
+	batchReport   string
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch --manifest <file> [flags]",
+	Short: "Run many input→output jobs from a manifest",
+	Long: `Run every job of a batch manifest in order and print one consolidated report. Each job has
+its own input, output, config file and vendor profile; relative paths are resolved against the
+manifest's directory:
+
+  config: configs/base.yaml     # config for jobs that don't set their own
+  jobs:
+    - name: users
+      input: specs/users.yaml
+      output: out/users.yaml    # omit to transform in place
+      profile: fern             # vendor provider, or "none"
+    - name: billing
+      input: specs/billing
+      output: out/billing
+      config: configs/billing.yaml
+
+Config files shared by several jobs are loaded once, and a job whose input files, config and
+profile match an earlier job reuses that job's output instead of running the pipeline again
+(jobs transformed in place always run).
+A failing job does not stop the others; the command exits with status 2 if any job failed.
+A notify: section in the manifest posts one summary of all jobs after the run.`,
+	Example: `  openmorph batch --manifest jobs.yaml
+  openmorph batch --manifest jobs.yaml --dry-run --report batch-report.json`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		checkAnnotationsFormat()
+		setupTelemetry()
+		manifest, err := config.LoadBatchManifest(batchManifest)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Manifest error:", err)
+			os.Exit(1)
+		}
+		if err := notify.Validate(manifest.Notify); err != nil {
+			fmt.Fprintln(os.Stderr, "Manifest error:", err)
+			os.Exit(1)
+		}
+
+		results := transform.RunBatch(manifest, transform.BatchOptions{DryRun: dryRun, Backup: backup, Strict: strict, Lock: !noLock, Version: GetVersion()})
+		flushTelemetry()
+		printBatchResults(results, dryRun)
+
+		if batchReport != "" {
+			if err := report.WriteBatchReportFile(batchReport, results, GetVersion()); err != nil {
+				fmt.Fprintln(os.Stderr, "Report error:", err)
+				os.Exit(1)
+			}
+			fmt.Printf("📝 Batch report written to %s\n", batchReport)
+		}
+
+		var locations []transform.ChangeLocation
+		var annotated []*transform.TransformationResults
+		var strictIssues []transform.StrictIssue
+		for _, job := range results.Jobs {
+			var strictErr *transform.StrictError
+			if errors.As(job.Err, &strictErr) {
+				strictIssues = append(strictIssues, strictErr.Issues...)
+			}
+			if job.Results != nil {
+				locations = append(locations, job.Results.AllLocations()...)
+				annotated = append(annotated, job.Results)
+			}
+		}
+		writeSARIFReport(locations)
+		writeAnnotations(annotated...)
+		if annotationsFormat != "" && len(strictIssues) > 0 {
+			printAnnotations(report.StrictAnnotations(strictIssues))
+		}
+		writeMetricsFile(annotated...)
+
+		var runErr error
+		if failed := results.Failed(); failed > 0 {
+			runErr = fmt.Errorf("%d of %d jobs failed", failed, len(results.Jobs))
+		}
+		if !dryRun {
+			sendNotifications(manifest.Notify, batchManifest, notify.ValidationSkipped, runErr, annotated...)
+		}
+		if runErr != nil {
+			fmt.Fprintln(os.Stderr, runErr)
+			os.Exit(2)
+		}
+		fmt.Printf("\n%s🎉 OpenMorph batch completed successfully!%s\n", colorGreen, colorReset)
+	},
+}
+
+func init() {
+	batchCmd.Flags().StringVar(&batchManifest, "manifest", "", "Batch manifest file (.yaml or .json)")
+	batchCmd.Flags().StringVar(&batchReport, "report", "", "Write a consolidated JSON report of every job to this file")
+	_ = batchCmd.MarkFlagRequired("manifest")
+	rootCmd.AddCommand(batchCmd)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_Batch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: bar
+      responses:
+        "200":
+          description: Success
+`
+	files := map[string]string{
+		"base.yaml":       "mappings:\n  x-foo: x-bar\n",
+		"specs/a.yaml":    spec,
+		"specs/b.yaml":    spec,
+		"specs/dir/c.yml": spec,
+		"jobs.yaml": `config: base.yaml
+jobs:
+  - name: a
+    input: specs/a.yaml
+    output: out/a.yaml
+  - name: b
+    input: specs/b.yaml
+    output: out/b.yaml
+  - name: dir
+    input: specs/dir
+    output: out/dir
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	reportPath := filepath.Join(tempDir, "report.json")
+
+	cmd := exec.Command("go", "run", "../main.go", "batch", "--manifest", filepath.Join(tempDir, "jobs.yaml"), "--report", reportPath)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("batch failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "Batch Summary") {
+		t.Errorf("expected consolidated summary in output:\n%s", out)
+	}
+
+	for _, output := range []string{"out/a.yaml", "out/b.yaml", "out/dir/c.yml"} {
+		data, err := os.ReadFile(filepath.Join(tempDir, output))
+		if err != nil {
+			t.Fatalf("expected output %s: %v", output, err)
+		}
+		if !strings.Contains(string(data), "x-bar: bar") {
+			t.Errorf("expected %s to be transformed:\n%s", output, data)
+		}
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	var report struct {
+		Summary map[string]int `json:"summary"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid report: %v", err)
+	}
+	if report.Summary["jobs"] != 3 || report.Summary["transformed"] != 2 || report.Summary["cached"] != 1 {
+		t.Errorf("unexpected summary: %v", report.Summary)
+	}
+
+	// A failing job makes the command fail
+	if err := os.WriteFile(filepath.Join(tempDir, "jobs.yaml"), []byte("jobs:\n  - input: specs/missing.yaml\n"), 0600); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+	cmd = exec.Command("go", "run", "../main.go", "batch", "--manifest", filepath.Join(tempDir, "jobs.yaml"))
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected failure for missing input, got success:\n%s", out)
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+// exitChangeBudget is the exit status of a run stopped by --max-changed-operations
+const exitChangeBudget = 6
+
+// maxListedOperations is how many of the operations over the budget are listed
+const maxListedOperations = 20
+
+var (
+	maxChangedOperations int
+	assumeYes            bool
+)
+
+// changeBudget returns the --max-changed-operations limit, or max_changed_operations from the
+// config, 0 for none
+func changeBudget(cmd *cobra.Command, cfg *config.Config) int {
+	if cmd.Flag("max-changed-operations") != nil && cmd.Flag("max-changed-operations").Changed {
+		return maxChangedOperations
+	}
+	return cfg.MaxChangedOperations
+}
+
+// checkChangeBudget previews the run with execute on a staged copy of the input and stops it before
+// anything is written when it would change more operations than the budget allows, unless --yes is set
+func checkChangeBudget(budget int, cfg *config.Config, inputPath, outputFile string,
+	execute func(*transform.TransformationPipeline, string) (*transform.TransformationResults, error)) {
+	if budget <= 0 {
+		return
+	}
+	pipeline := transform.NewTransformationPipeline(cfg, vendorProviders, false, false, outputFile)
+	pipeline.KeepGoing = keepGoing
+	pipeline.Log = io.Discard // only the real run's warnings are shown
+
+	operations, err := pipeline.PreviewChangedOperations(inputPath, execute)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Transform error:", err)
+		os.Exit(2)
+	}
+
+	if len(operations) <= budget {
+		fmt.Printf("🛡️  %s%d operation(s) to change, within the limit of %d%s\n", colorCyan, len(operations), budget, colorReset)
+		return
+	}
+	if assumeYes {
+		fmt.Printf("⚠️  %s%d operation(s) to change, over the limit of %d; continuing because of --yes%s\n", colorYellow, len(operations), budget, colorReset)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "%s❌ This run would change %d operation(s), over the limit of %d:%s\n", colorRed, len(operations), budget, colorReset)
+	for i, operation := range operations {
+		if i == maxListedOperations {
+			fmt.Fprintf(os.Stderr, "   ... and %d more\n", len(operations)-maxListedOperations)
+			break
+		}
+		fmt.Fprintf(os.Stderr, "   %s\n", operation)
+	}
+	fmt.Fprintln(os.Stderr, "Nothing was written. Check the config, preview the changes with --dry-run, then raise the limit or rerun with --yes.")
+	os.Exit(exitChangeBudget)
+}
+
+func init() {
+	rootCmd.PersistentFlags().IntVar(&maxChangedOperations, "max-changed-operations", 0, "Stop before writing anything when a run would change more operations than this, exit status 6 (0 for no limit)")
+	rootCmd.PersistentFlags().BoolVar(&assumeYes, "yes", false, "Go ahead with a run over the --max-changed-operations limit")
+}
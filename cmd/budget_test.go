@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_MaxChangedOperations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: users
+      responses:
+        "200":
+          description: Success
+  /orders:
+    get:
+      x-foo: orders
+      responses:
+        "200":
+          description: Success
+components:
+  schemas:
+    User:
+      x-foo: user
+      type: object
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	out, err := exec.Command("go", "run", "../main.go", "--input", tempDir, "--no-config", "--map", "x-foo=x-bar", "--max-changed-operations", "1").CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the run over the limit to fail, got:\n%s", out)
+	}
+	if !strings.Contains(string(out), "would change 2 operation(s), over the limit of 1") || !strings.Contains(string(out), "GET /orders") {
+		t.Errorf("expected the operations over the limit to be listed, got:\n%s", out)
+	}
+	if data, _ := os.ReadFile(inputFile); string(data) != input {
+		t.Errorf("expected the input to be left alone, got:\n%s", data)
+	}
+
+	out, err = exec.Command("go", "run", "../main.go", "--input", tempDir, "--no-config", "--map", "x-foo=x-bar", "--max-changed-operations", "1", "--yes").CombinedOutput()
+	if err != nil {
+		t.Fatalf("transform with --yes failed: %v\n%s", err, out)
+	}
+	if data, _ := os.ReadFile(inputFile); strings.Contains(string(data), "x-foo") {
+		t.Errorf("expected --yes to go ahead with the run, got:\n%s", data)
+	}
+}
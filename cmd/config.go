@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Work with the config file",
+}
+
+var configDocsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Print a Markdown reference of every config key",
+	Long: `Print a Markdown reference of every key of the config file, with its type, default value,
+description and examples. The reference is generated from the config structs of this build, so it
+always matches the keys this version understands.`,
+	Example: `  openmorph config docs > CONFIG.md`,
+	Args:    cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if err := config.WriteMarkdown(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configDocsCmd)
+	rootCmd.AddCommand(configCmd)
+}
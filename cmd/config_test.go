@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCLI_ConfigDocs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	out, err := exec.Command("go", "run", "../main.go", "config", "docs").Output()
+	if err != nil {
+		t.Fatalf("config docs failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"# OpenMorph Configuration Reference",
+		"| `vendor_extensions.providers.<name>.extension_name` | string |",
+		"| `default_values.rules.<name>.priority` | integer |",
+		"| `strip_internal.extension` | string | `x-internal` |",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected the reference to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var convertTo string
+
+var convertCmd = &cobra.Command{
+	Use:   "convert [file]",
+	Short: "Convert a spec between YAML and JSON",
+	Long: `Convert a YAML or JSON document to the other serialization without applying any
+transformation, so no config is needed. Key order is preserved, numbers and booleans keep their
+values (0x1F becomes 31, never a string), strings YAML 1.1 readers would take for booleans such as
+"yes" stay quoted, and long strings are never folded. The converted document is parsed again and
+compared with the input; the command fails rather than write a document that reads differently.
+Merge keys (<<) and non-finite numbers (.inf, .nan) cannot be converted.
+
+--to defaults to the format of the --output extension. Without --output the converted document is
+written to stdout.`,
+	Example: `  openmorph convert api.yaml --to json > api.json
+  openmorph convert --input api.json -o api.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		inputPath := inputDir
+		if len(args) == 1 {
+			inputPath = args[0]
+		}
+		if inputPath == "" {
+			fmt.Fprintln(os.Stderr, "Error: No input file specified. Use --input <file> or pass the file as an argument.")
+			os.Exit(1)
+		}
+
+		format := convertTo
+		if format == "" && outputFile != "" {
+			format = transform.OutputFormatYAML
+			if transform.IsJSON(outputFile) {
+				format = transform.OutputFormatJSON
+			}
+		}
+		if format != transform.OutputFormatYAML && format != transform.OutputFormatJSON {
+			fmt.Fprintf(os.Stderr, "Error: invalid --to %q (expected json or yaml)\n", format)
+			os.Exit(1)
+		}
+
+		data, err := os.ReadFile(inputPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Convert error:", err)
+			os.Exit(2)
+		}
+		output, err := transform.ConvertDocument(data, format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Convert error: %s: %v\n", inputPath, err)
+			os.Exit(2)
+		}
+
+		if outputFile == "" {
+			fmt.Print(string(output))
+			return
+		}
+		if err := os.WriteFile(outputFile, output, 0600); err != nil {
+			fmt.Fprintln(os.Stderr, "Convert error:", err)
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "Converted %s to %s\n", inputPath, outputFile)
+	},
+}
+
+func init() {
+	convertCmd.Flags().StringVar(&convertTo, "to", "", "Format to convert to: json or yaml (default: from the --output extension)")
+	rootCmd.AddCommand(convertCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_Convert(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	jsonFile := filepath.Join(tempDir, "api.json")
+
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+x-limit: 0x10
+x-enabled: "on"
+paths: {}
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	// --to is taken from the output extension; no config is needed
+	cmd := exec.Command("go", "run", "../main.go", "convert", inputFile, "--no-config", "--output", jsonFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("convert to json failed: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	for _, want := range []string{`"openapi": "3.0.0"`, `"x-limit": 16`, `"x-enabled": "on"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in the JSON output:\n%s", want, data)
+		}
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "convert", "--input", jsonFile, "--to", "yaml")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("convert to yaml failed: %v", err)
+	}
+	for _, want := range []string{"x-limit: 16", `x-enabled: "on"`, "version: 1.0.0"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected %q in the YAML output:\n%s", want, out)
+		}
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "convert", inputFile, "--to", "toml")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected an invalid --to to fail, got:\n%s", out)
+	}
+}
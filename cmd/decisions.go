@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+var recordDecisions bool
+
+// recordPaginationDecisions saves the strategy the pagination step selected for each operation from
+// the priority list as exact endpoint rules in the config file, or only lists them with preview.
+// Operations already decided by an endpoint rule are left out. It returns at once when
+// --record-decisions is off, and only lists the rules when no config file was loaded, such as
+// under --no-config, rather than creating one in the working directory.
+func recordPaginationDecisions(cfg *config.Config, results *transform.TransformationResults, preview bool) {
+	if !recordDecisions {
+		return
+	}
+	var decisions []transform.PaginationDecision
+	if results.PaginationResult != nil {
+		decisions = results.PaginationResult.Decisions
+	}
+	rules, conflicts := transform.DecisionRules(decisions)
+
+	for _, conflict := range conflicts {
+		fmt.Printf("⚠️  %sNot recorded, decided differently across files:%s %s\n", colorYellow, colorReset, conflict)
+	}
+	if len(rules) == 0 {
+		fmt.Printf("ℹ️  %sNo pagination decisions to record%s\n", colorYellow, colorReset)
+		return
+	}
+
+	path := cfg.Source
+	if path == "" {
+		fmt.Printf("\n⚠️  %sNo config file loaded, %d endpoint rules not recorded (pass --config):%s\n", colorYellow, len(rules), colorReset)
+	} else if preview {
+		fmt.Printf("\n📝 %sWould add %d endpoint rules to %s:%s\n", colorCyan, len(rules), path, colorReset)
+	} else {
+		if err := config.AddEndpointRules(path, rules); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n%s✅ Added %d endpoint rules to %s:%s\n", colorGreen, len(rules), path, colorReset)
+	}
+	for _, rule := range rules {
+		fmt.Printf("   %s %s -> %s\n", rule.Method, rule.Endpoint, rule.Pagination)
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&recordDecisions, "record-decisions", false, "Save the pagination strategy selected for each operation from pagination_priority as endpoint_pagination rules in the config")
+}
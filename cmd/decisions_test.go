@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_RecordDecisions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+        - name: page
+          in: query
+      responses:
+        "200":
+          description: Success
+  /orders:
+    get:
+      parameters:
+        - name: offset
+          in: query
+      responses:
+        "200":
+          description: Success
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "api.yaml"), []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+	config := "pagination_priority: [cursor, offset]\nendpoint_pagination:\n  - endpoint: /orders\n    method: GET\n    pagination: offset\n"
+	if err := os.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	out, err := exec.Command("go", "run", "../main.go", "--input", tempDir, "--config", configFile, "--record-decisions", "--dry-run").CombinedOutput()
+	if err != nil {
+		t.Fatalf("dry run failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "Would add 1 endpoint rules") || !strings.Contains(string(out), "GET /users -> cursor") {
+		t.Errorf("expected the decision to be previewed, got:\n%s", out)
+	}
+	if data, _ := os.ReadFile(configFile); string(data) != config {
+		t.Errorf("expected the dry run to leave the config alone, got:\n%s", data)
+	}
+
+	out, err = exec.Command("go", "run", "../main.go", "--input", tempDir, "--config", configFile, "--record-decisions").CombinedOutput()
+	if err != nil {
+		t.Fatalf("transform failed: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "endpoint_pagination:\n    - endpoint: /users\n      method: GET\n      pagination: cursor\n    - endpoint: /orders\n"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("expected the decision for GET /users only, got:\n%s", data)
+	}
+
+	// Without a config file the rules are listed, and no .openapirc.yaml is created
+	t.Cleanup(func() { _ = os.Remove(".openapirc.yaml") })
+	out, err = exec.Command("go", "run", "../main.go", "--input", tempDir, "--no-config", "--pagination-priority", "cursor,offset", "--record-decisions").CombinedOutput()
+	if err != nil {
+		t.Fatalf("transform without config failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "endpoint rules not recorded (pass --config)") {
+		t.Errorf("expected a warning without a config file, got:\n%s", out)
+	}
+	if _, err := os.Stat(".openapirc.yaml"); !os.IsNotExist(err) {
+		t.Errorf("expected no .openapirc.yaml in the working directory, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var defaultsCmd = &cobra.Command{
+	Use:   "defaults",
+	Short: "Inspect the default value rules of the config file",
+}
+
+var defaultsExplainCmd = &cobra.Command{
+	Use:   "explain <rule> [path]",
+	Short: "Show what a single defaults rule or vendor provider would change, and why it skips the rest",
+	Long: `Evaluate one default_values rule, or one vendor_extensions provider, against the input on its
+own and print every target it would change, followed by every target it leaves alone with the
+reason. Nothing is written and no other step runs, so this is quick to repeat while working on
+the conditions of a rule. The rule is evaluated even when its section of the config is disabled.`,
+	Example: `  openmorph defaults explain query_limit_defaults specs/ --config openmorph.yaml
+  openmorph defaults explain fern --input api.yaml`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(_ *cobra.Command, args []string) {
+		inputPath := inputDir
+		if len(args) == 2 {
+			inputPath = args[1]
+		}
+		cfg, err := config.LoadConfig(configFile, inlineMaps, inputPath, "", noConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		applyRuleFlags(cfg)
+
+		explanation, err := transform.ExplainRule(cfg, args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		printRuleExplanation(explanation)
+	},
+}
+
+// printRuleExplanation prints the matches and skipped targets of a rule, file by file
+func printRuleExplanation(explanation *transform.RuleExplanation) {
+	printHeader(fmt.Sprintf("%s %s", explanation.Kind, explanation.Name), "🔍")
+
+	fmt.Printf("✅ %sWould change:%s %s%d%s\n", colorGreen, colorReset, colorBold, countItems(explanation.Matches), colorReset)
+	printItemsByFile(explanation.Matches, colorGreen)
+
+	skipped := transform.SkipMessagesByFile(explanation.Skipped)
+	fmt.Printf("\n⏭️  %sSkipped:%s %s%d%s\n", colorYellow, colorReset, colorBold, countItems(skipped), colorReset)
+	printItemsByFile(skipped, colorYellow)
+}
+
+// printItemsByFile prints the items of each file, in file order
+func printItemsByFile(items map[string][]string, itemColor string) {
+	files := make([]string, 0, len(items))
+	for file := range items {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		printFileHeader(file)
+		for _, item := range items[file] {
+			printListItem(item, itemColor)
+		}
+	}
+}
+
+// countItems counts the items of every file
+func countItems(items map[string][]string) int {
+	count := 0
+	for _, fileItems := range items {
+		count += len(fileItems)
+	}
+	return count
+}
+
+func init() {
+	defaultsCmd.AddCommand(defaultsExplainCmd)
+	rootCmd.AddCommand(defaultsCmd)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_DefaultsExplain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	configFile := filepath.Join(tempDir, "config.yaml")
+
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: sort
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: Success
+`
+	config := `default_values:
+  enabled: true
+  rules:
+    limits:
+      target:
+        location: parameter
+      condition:
+        property_name: "^limit$"
+      value: 20
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+	if err := os.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "defaults", "explain", "limits", inputFile, "--config", configFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("defaults explain failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"GET /users: default = 20 (rule: limits)",
+		"GET /users parameter sort: parameter name doesn't match pattern '^limit$'",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatalf("failed to read input file: %v", err)
+	}
+	if string(data) != input {
+		t.Error("expected explain to leave the input untouched")
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <file> <pointer>",
+	Short: "Show everything the pipeline decides for one operation",
+	Long: `Explain what a run with the config would do to a single operation of file, given as a JSON
+pointer such as /paths/~1users/get or as "GET /users": the pagination detected on it with the
+evidence, the strategy selected and why, every enabled vendor provider and defaults rule with
+the changes it would make or the reason it skips the operation, and the operation before and
+after the whole pipeline as a diff. Nothing is written.`,
+	Example: `  openmorph explain api.yaml '/paths/~1users/get' --config openmorph.yaml
+  openmorph explain api.yaml 'GET /users/{id}'`,
+	Args: cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig(configFile, inlineMaps, args[0], "", noConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		applyRuleFlags(cfg)
+
+		// The steps print their own previews; only the explanation is shown
+		restore := silenceStdout()
+		explanation, err := transform.ExplainOperation(cfg, vendorProviders, args[0], args[1])
+		restore()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		printOperationExplanation(explanation)
+	},
+}
+
+// printOperationExplanation prints the pagination, rule verdicts and diff of an operation
+func printOperationExplanation(explanation *transform.OperationExplanation) {
+	printHeader(explanation.Operation(), "🔍")
+	fmt.Printf("%s📍 %s%s\n", colorBlue, explanation.Position(), colorReset)
+
+	fmt.Printf("\n📄 %sPagination:%s\n", colorCyan, colorReset)
+	switch pagination := explanation.Pagination; {
+	case pagination == nil:
+		printInfo("Pagination step disabled (no pagination_priority)")
+	default:
+		for _, evidence := range pagination.Evidence {
+			printListItem(evidence, colorCyan)
+		}
+		strategy := pagination.Strategy
+		if strategy == "" {
+			strategy = "unchanged"
+		}
+		fmt.Printf("   %sSelected:%s %s%s%s (%s)\n", colorBold, colorReset, colorGreen, strategy, colorReset, pagination.Reason)
+	}
+
+	printRuleVerdicts("🏷️  Vendor providers:", "Vendor extensions disabled", explanation.Providers)
+	printRuleVerdicts("🔧 Defaults rules:", "Default values disabled", explanation.Defaults)
+
+	fmt.Printf("\n📝 %sDiff:%s\n", colorCyan, colorReset)
+	switch {
+	case explanation.Moved:
+		printInfo("The operation is no longer at " + explanation.Operation() + " after the run; see the path_variants, tenant_paths and strip_internal steps")
+	case len(explanation.Diff) == 0:
+		printInfo("No changes")
+	default:
+		for _, line := range explanation.Diff {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				fmt.Printf("   %s%s%s\n", colorGreen, line, colorReset)
+			case strings.HasPrefix(line, "-"):
+				fmt.Printf("   %s%s%s\n", colorRed, line, colorReset)
+			default:
+				fmt.Printf("   %s\n", line)
+			}
+		}
+	}
+}
+
+// printRuleVerdicts prints whether each rule matches the operation, with its changes or skip reasons
+func printRuleVerdicts(title, disabled string, verdicts []transform.RuleVerdict) {
+	fmt.Printf("\n%s%s%s\n", colorCyan, title, colorReset)
+	if len(verdicts) == 0 {
+		printInfo(disabled)
+		return
+	}
+	for _, verdict := range verdicts {
+		switch {
+		case len(verdict.Changes) > 0:
+			fmt.Printf("   ✅ %s%s%s matched\n", colorBold, verdict.Name, colorReset)
+			for _, change := range verdict.Changes {
+				printListItem(change, colorGreen)
+			}
+		case len(verdict.Skipped) > 0:
+			fmt.Printf("   ⏭️  %s%s%s skipped\n", colorBold, verdict.Name, colorReset)
+			for _, reason := range verdict.Skipped {
+				printListItem(reason, colorYellow)
+			}
+		default:
+			fmt.Printf("   ➖ %s%s%s does not apply\n", colorBold, verdict.Name, colorReset)
+		}
+	}
+}
+
+// silenceStdout sends what is printed to stdout nowhere until the returned function restores it
+func silenceStdout() func() {
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return func() {}
+	}
+	os.Stdout = devNull
+	return func() {
+		os.Stdout = stdout
+		devNull.Close()
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
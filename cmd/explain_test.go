@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_Explain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	configFile := filepath.Join(tempDir, "config.yaml")
+
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: sort
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: Success
+`
+	config := `pagination_priority: [offset]
+default_values:
+  enabled: true
+  rules:
+    limits:
+      target:
+        location: parameter
+      condition:
+        property_name: "^limit$"
+      value: 20
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+	if err := os.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "explain", inputFile, "/paths/~1users/get", "--config", configFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("explain failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"offset: params limit",
+		"first detected strategy in pagination_priority [offset]",
+		"GET /users: default = 20 (rule: limits)",
+		"default: 20",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatalf("failed to read input file: %v", err)
+	}
+	if string(data) != input {
+		t.Error("expected explain to leave the input untouched")
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "explain", inputFile, "DELETE /users", "--config", configFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	if out, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "has no operation DELETE /users") {
+		t.Errorf("expected explain to fail for a missing operation, got %v:\n%s", err, out)
+	}
+}
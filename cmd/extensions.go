@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	extensionsTarget    string
+	extensionsLocations bool
+)
+
+var extensionsCmd = &cobra.Command{
+	Use:   "extensions [path]",
+	Short: "List the vendor extension keys in the specs and suggest mappings",
+	Long: `List every vendor extension key used in the OpenAPI documents under the input with its
+number of occurrences. With --target, keys of other generator families are matched against the
+target's extensions and a starter mappings: block is printed for the config file.
+
+Known equivalents (e.g. x-fern-pagination and x-speakeasy-pagination) are suggested as is; other
+keys of a known family only get the target prefix and are commented out until verified.`,
+	Example: `  openmorph extensions specs/
+  openmorph extensions --input api.yaml --target speakeasy --locations`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		inputPath := inputDir
+		if len(args) == 1 {
+			inputPath = args[0]
+		}
+		cfg, err := config.LoadConfig(configFile, inlineMaps, inputPath, "", noConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+
+		usages, err := transform.ScanExtensions(cfg.Input, cfg.Files, cfg.Mappings)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Scan error:", err)
+			os.Exit(2)
+		}
+		if extensionsTarget != "" {
+			if err := transform.SuggestMappings(usages, extensionsTarget); err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				os.Exit(1)
+			}
+		}
+		printExtensionUsages(usages)
+	},
+}
+
+// printExtensionUsages prints each extension key with its count, mapping and locations, then the
+// starter mappings block
+func printExtensionUsages(usages []transform.ExtensionUsage) {
+	if len(usages) == 0 {
+		fmt.Printf("ℹ️  %sNo vendor extensions found%s\n", colorYellow, colorReset)
+		return
+	}
+
+	fmt.Printf("\n%s🧩 Vendor Extensions (%d keys)%s\n", colorBold, len(usages), colorReset)
+	for _, usage := range usages {
+		line := fmt.Sprintf("   %s●%s %s%s%s: %d", colorYellow, colorReset, colorBold, usage.Name, colorReset, len(usage.Occurrences))
+		switch {
+		case usage.MappedTo != "":
+			line += fmt.Sprintf(" %s(mapped to %s)%s", colorGreen, usage.MappedTo, colorReset)
+		case usage.Suggestion != "" && usage.Guessed:
+			line += fmt.Sprintf(" %s(maybe %s)%s", colorYellow, usage.Suggestion, colorReset)
+		case usage.Suggestion != "":
+			line += fmt.Sprintf(" %s(→ %s)%s", colorCyan, usage.Suggestion, colorReset)
+		}
+		fmt.Println(line)
+		if extensionsLocations {
+			for _, occurrence := range usage.Occurrences {
+				fmt.Printf("     %s▸%s %s %s\n", colorCyan, colorReset, occurrence.Position(), occurrence.Value)
+			}
+		}
+	}
+
+	if extensionsTarget == "" {
+		return
+	}
+	if block := transform.StarterMappings(usages); block != "" {
+		fmt.Printf("\n%s📝 Suggested mappings for %s:%s\n\n", colorBold, extensionsTarget, colorReset)
+		fmt.Print(strings.TrimSuffix(block, "\n") + "\n")
+	} else {
+		fmt.Printf("\n%s✅ No mapping suggestions for %s%s\n", colorGreen, extensionsTarget, colorReset)
+	}
+}
+
+func init() {
+	extensionsCmd.Flags().StringVar(&extensionsTarget, "target", "", "Generator family to suggest mappings for (autorest, codegen, fern, speakeasy, stainless)")
+	extensionsCmd.Flags().BoolVar(&extensionsLocations, "locations", false, "List every occurrence with its file, line and column")
+	rootCmd.AddCommand(extensionsCmd)
+}
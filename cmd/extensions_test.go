@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_ExtensionsSuggestions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-fern-sdk-group-name: users
+      responses:
+        "200":
+          description: Success
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "extensions", "--no-config", "--target", "speakeasy", "--locations", inputFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("extensions failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{inputFile + ":8:7 users", "mappings:\n  x-fern-sdk-group-name: x-speakeasy-group\n"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
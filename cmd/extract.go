@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	extractTags         []string
+	extractPaths        []string
+	extractOperationIDs []string
+)
+
+var extractCmd = &cobra.Command{
+	Use:   "extract [file]",
+	Short: "Extract a subset of a spec by tag, path or operationId",
+	Long: `Extract a subset of an OpenAPI spec containing only the selected operations and the
+transitive closure of components they reference. Values within one filter are alternatives;
+when several filters are given, an operation must match all of them.
+
+Without --output, or with --output -, the subset is written to stdout.`,
+	Example: `  openmorph extract --input master.yaml --tags billing --paths '/v1/invoices*' -o billing.yaml
+  openmorph extract master.yaml --operation-ids listUsers,getUser`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		inputPath := inputDir
+		if len(args) == 1 {
+			inputPath = args[0]
+		}
+		if inputPath == "" {
+			fmt.Fprintln(os.Stderr, "Error: No input file specified. Use --input <file> or pass the file as an argument.")
+			os.Exit(1)
+		}
+
+		opts := transform.ExtractOptions{
+			Tags:         extractTags,
+			Paths:        extractPaths,
+			OperationIDs: extractOperationIDs,
+		}
+
+		if outputFile == "" || outputFile == "-" {
+			output, result, err := transform.ExtractSubsetBytes(inputPath, opts, transform.IsJSON(inputPath))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Extract error:", err)
+				os.Exit(2)
+			}
+			fmt.Print(string(output))
+			fmt.Fprintf(os.Stderr, "Extracted %d operations, %d components\n",
+				len(result.KeptOperations), len(result.KeptComponents))
+			return
+		}
+
+		result, err := transform.ExtractSubset(inputPath, outputFile, opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Extract error:", err)
+			os.Exit(2)
+		}
+		printExtractResults(result, outputFile)
+	},
+}
+
+func init() {
+	extractCmd.Flags().StringSliceVar(&extractTags, "tags", nil, "Keep operations with any of these tags (comma-separated or repeatable)")
+	extractCmd.Flags().StringSliceVar(&extractPaths, "paths", nil, "Keep operations whose path matches any of these patterns, e.g. '/v1/invoices/**'")
+	extractCmd.Flags().StringSliceVar(&extractOperationIDs, "operation-ids", nil, "Keep operations with any of these operationIds")
+	rootCmd.AddCommand(extractCmd)
+}
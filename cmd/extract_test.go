@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_Extract(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "master.yaml")
+	outputFile := filepath.Join(tempDir, "billing.yaml")
+
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /v1/invoices:
+    get:
+      tags: [billing]
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Invoice'
+  /v1/users:
+    get:
+      tags: [users]
+      responses:
+        "200":
+          description: Success
+components:
+  schemas:
+    Invoice:
+      type: object
+    User:
+      type: object
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "extract", "--input", inputFile, "--tags", "billing", "--output", outputFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("extract failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	output := string(data)
+	if !strings.Contains(output, "/v1/invoices") || !strings.Contains(output, "Invoice:") {
+		t.Errorf("expected billing operation and its schema in output:\n%s", output)
+	}
+	if strings.Contains(output, "/v1/users") || strings.Contains(output, "User:") {
+		t.Errorf("expected users operation and schema to be removed:\n%s", output)
+	}
+
+	// "-" writes the subset to stdout rather than to a file named "-"
+	cmd = exec.Command("go", "run", "../main.go", "extract", "--input", inputFile, "--tags", "billing", "--output", "-")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	stdout, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("extract to stdout failed: %v", err)
+	}
+	if string(stdout) != output {
+		t.Errorf("expected the subset on stdout, got:\n%s", stdout)
+	}
+	if _, err := os.Stat("-"); !os.IsNotExist(err) {
+		_ = os.Remove("-")
+		t.Errorf("expected no file named -, got %v", err)
+	}
+
+	original, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatalf("failed to read input file: %v", err)
+	}
+	if string(original) != input {
+		t.Error("expected input file to be left untouched")
+	}
+}
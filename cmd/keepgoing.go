@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+// exitFileErrors is the exit status of a --keep-going run in which files failed
+const exitFileErrors = 5
+
+var keepGoing bool
+
+// exitOnFileErrors lists the files a --keep-going run left alone because a step failed on them,
+// passes the failure to notifyFailure, if any, then exits with exitFileErrors. It returns when
+// every file was processed.
+func exitOnFileErrors(notifyFailure func(error), results ...*transform.TransformationResults) {
+	var fileErrors []transform.FileError
+	for _, r := range results {
+		fileErrors = append(fileErrors, r.FileErrors...)
+	}
+	if len(fileErrors) == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "\n%s❌ %d file(s) failed and were left unchanged:%s\n", colorRed, len(fileErrors), colorReset)
+	for _, fileError := range fileErrors {
+		fmt.Fprintf(os.Stderr, "   %s [%s] %s\n", fileError.Position(), fileError.Step, fileError.Message)
+	}
+	if notifyFailure != nil {
+		notifyFailure(fmt.Errorf("%d file(s) failed and were left unchanged", len(fileErrors)))
+	}
+	os.Exit(exitFileErrors)
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&keepGoing, "keep-going", false, "Skip files that fail to parse or transform, report them and exit with status 5 instead of stopping at the first one")
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/report"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [path]",
+	Short: "Validate extension values against the schemas in extension_schemas",
+	Long: `Validate every occurrence of the extensions registered under extension_schemas in the
+config file against their JSON Schemas, and report each violation with its location.
+
+Transformation runs perform the same check on their output and print violations as warnings;
+lint exits with status 1 when any value is invalid.`,
+	Example: `  openmorph lint --config openmorph.yaml specs/
+  openmorph lint --input api.yaml --annotations github`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		checkAnnotationsFormat()
+		inputPath := inputDir
+		if len(args) == 1 {
+			inputPath = args[0]
+		}
+		cfg, err := config.LoadConfig(configFile, inlineMaps, inputPath, "", noConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		inputPath = cfg.Input
+
+		schemas, err := transform.LoadExtensionSchemas(cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if len(schemas) == 0 {
+			fmt.Printf("ℹ️  %sNo extension_schemas configured, nothing to lint%s\n", colorYellow, colorReset)
+			return
+		}
+
+		issues, err := transform.CheckExtensions(inputPath, cfg.Files, schemas)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Lint error:", err)
+			os.Exit(2)
+		}
+		if len(issues) == 0 {
+			fmt.Printf("%s✅ All extension values match their schemas (%d extensions checked)%s\n", colorGreen, len(schemas), colorReset)
+			return
+		}
+
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", issue.Position(), issue.Message)
+		}
+		if annotationsFormat != "" {
+			printAnnotations(report.ExtensionAnnotations(issues, report.AnnotationError))
+		}
+		fmt.Fprintf(os.Stderr, "%s❌ %d extension values do not match their schemas%s\n", colorRed, len(issues), colorReset)
+		os.Exit(1)
+	},
+}
+
+// warnInvalidExtensions prints the extension values under each path that do not match their
+// registered schemas. Violations never fail a transformation run; use openmorph lint for that.
+func warnInvalidExtensions(cfg *config.Config, paths ...string) {
+	schemas, err := transform.LoadExtensionSchemas(cfg)
+	if err != nil || len(schemas) == 0 {
+		return
+	}
+
+	var issues []transform.StrictIssue
+	for _, path := range paths {
+		found, err := transform.CheckExtensions(path, cfg.Files, schemas)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Extension schema check error:", err)
+			return
+		}
+		issues = append(issues, found...)
+	}
+	if len(issues) == 0 {
+		return
+	}
+
+	fmt.Printf("\n⚠️  %sExtension values not matching their schemas:%s\n", colorYellow, colorReset)
+	for _, issue := range issues {
+		fmt.Printf("   • %s: %s\n", issue.Position(), issue.Message)
+	}
+	if annotationsFormat != "" {
+		printAnnotations(report.ExtensionAnnotations(issues, report.AnnotationWarning))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
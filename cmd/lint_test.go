@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_Lint(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	configFile := filepath.Join(tempDir, "openmorph.yaml")
+
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-sdk-group: Users
+      responses:
+        "200":
+          description: Success
+`
+	config := `extension_schemas:
+  x-sdk-group:
+    schema:
+      type: string
+      pattern: "^[a-z]+$"
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+	if err := os.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "lint", "--config", configFile, inputFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected lint to fail, got:\n%s", out)
+	}
+	want := inputFile + `:8:20: x-sdk-group: "Users" does not match pattern ^[a-z]+$`
+	if !strings.Contains(string(out), want) {
+		t.Errorf("expected output to contain %q, got:\n%s", want, out)
+	}
+}
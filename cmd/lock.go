@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/lock"
+)
+
+var noLock bool
+
+// acquireLock keeps other runs from writing to the input root of inputPath until the returned
+// function is called. When another run holds the lock it exits with the holder; with --no-lock it
+// does nothing.
+func acquireLock(inputPath string) func() {
+	if noLock {
+		return func() {}
+	}
+	held, err := lock.Acquire(inputPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Lock error:", err)
+		var heldErr *lock.HeldError
+		if errors.As(err, &heldErr) {
+			fmt.Fprintf(os.Stderr, "Wait for that run to finish, or delete %s if it crashed. --no-lock skips locking.\n", heldErr.Path)
+		}
+		os.Exit(1)
+	}
+	return func() {
+		if err := held.Release(); err != nil {
+			fmt.Fprintln(os.Stderr, "Lock error:", err)
+		}
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noLock, "no-lock", false, "Don't lock the input against other runs writing to it at the same time")
+}
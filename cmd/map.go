@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+	"github.com/developerkunal/OpenMorph/internal/tui"
+
+	"github.com/spf13/cobra"
+)
+
+// discoverSamples is the number of occurrences shown for each key in the discovery wizard
+const discoverSamples = 3
+
+var discoverTarget string
+
+var mapCmd = &cobra.Command{
+	Use:   "map",
+	Short: "Manage the key mappings of the config file",
+}
+
+var mapDiscoverCmd = &cobra.Command{
+	Use:   "discover [path]",
+	Short: "Interactively map the vendor extension keys that have no mapping yet",
+	Long: `Scan the specs for vendor extension keys without a mapping and present each one with sample
+occurrences. Type the replacement key to accept it, or leave the input empty to skip the key.
+Accepted pairs are appended to the mappings: section of the config file (--config, or
+.openapirc.yaml when no config file is given).
+
+With --target, suggestions for that generator family are offered and keys that already belong to
+it are not asked about.`,
+	Example: `  openmorph map discover specs/ --config openmorph.yaml
+  openmorph map discover --input api.yaml --target speakeasy`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		inputPath := inputDir
+		if len(args) == 1 {
+			inputPath = args[0]
+		}
+		cfg, err := config.LoadConfig(configFile, inlineMaps, inputPath, "", noConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+
+		usages, err := transform.ScanExtensions(cfg.Input, cfg.Files, cfg.Mappings)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Scan error:", err)
+			os.Exit(2)
+		}
+		if discoverTarget != "" {
+			if err := transform.SuggestMappings(usages, discoverTarget); err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				os.Exit(1)
+			}
+		}
+
+		candidates := mappingCandidates(usages, cfg.Mappings, discoverTarget)
+		if len(candidates) == 0 {
+			fmt.Printf("%s✅ Every vendor extension key is mapped%s\n", colorGreen, colorReset)
+			return
+		}
+
+		accepted, err := tui.RunDiscover(candidates)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "TUI error:", err)
+			os.Exit(1)
+		}
+		if len(accepted) == 0 {
+			fmt.Printf("ℹ️  %sNo mappings accepted, config unchanged%s\n", colorYellow, colorReset)
+			return
+		}
+
+		path := cfg.Source
+		if path == "" {
+			path = ".openapirc.yaml"
+		}
+		if err := config.AppendMappings(path, accepted); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s✅ Added %d mappings to %s%s\n", colorGreen, len(accepted), path, colorReset)
+	},
+}
+
+// mappingCandidates returns the scanned keys that are neither mapped nor the target of a mapping,
+// skipping keys of the target family when one is given
+func mappingCandidates(usages []transform.ExtensionUsage, mappings map[string]string, target string) []tui.MappingCandidate {
+	targets := make(map[string]bool, len(mappings))
+	for _, to := range mappings {
+		targets[to] = true
+	}
+	targetPrefix := transform.ExtensionFamilies[target]
+
+	var candidates []tui.MappingCandidate
+	for _, usage := range usages {
+		if usage.MappedTo != "" || targets[usage.Name] || (targetPrefix != "" && strings.HasPrefix(usage.Name, targetPrefix)) {
+			continue
+		}
+		candidate := tui.MappingCandidate{
+			Key:        usage.Name,
+			Count:      len(usage.Occurrences),
+			Suggestion: usage.Suggestion,
+		}
+		for _, occurrence := range usage.Occurrences[:min(discoverSamples, len(usage.Occurrences))] {
+			candidate.Samples = append(candidate.Samples, occurrence.Position()+" "+occurrence.Value)
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+func init() {
+	mapDiscoverCmd.Flags().StringVar(&discoverTarget, "target", "", "Generator family to suggest replacements for (autorest, codegen, fern, speakeasy, stainless)")
+	mapCmd.AddCommand(mapDiscoverCmd)
+	rootCmd.AddCommand(mapCmd)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeConflict string
+	mergePrefixes []string
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <spec> <spec>... [flags]",
+	Short: "Merge multiple specs into one document",
+	Long: `Merge paths, webhooks, components, tags, servers and security requirements of several
+OpenAPI specs into one document. The first spec provides info, the openapi version and any other
+top-level fields. Identical components and operations are merged silently. Swagger 2.0 specs merge
+definitions, parameters, responses and securityDefinitions like components; specs of different
+major versions cannot be merged.
+
+Conflicting components are handled by --conflict:
+  error          fail on the first conflict (default)
+  rename-prefix  rename the later component with a per-input prefix and rewrite its refs
+  prefer-first   keep the definition from the earliest input
+
+Conflicting operations cannot be renamed, so they fail unless --conflict prefer-first is used.
+Without --output the merged spec is written to stdout.`,
+	Example: `  openmorph merge users.yaml billing.yaml -o combined.yaml
+  openmorph merge users.yaml billing.yaml --conflict rename-prefix --prefixes Users,Billing -o combined.yaml`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		opts := transform.MergeOptions{
+			ConflictStrategy: mergeConflict,
+			Prefixes:         mergePrefixes,
+		}
+
+		if outputFile == "" {
+			output, result, err := transform.MergeSpecsBytes(args, opts, transform.IsJSON(args[0]))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Merge error:", err)
+				os.Exit(2)
+			}
+			fmt.Print(string(output))
+			fmt.Fprintf(os.Stderr, "Merged %d specs: %d paths, %d components\n",
+				len(result.Inputs), result.Paths, result.Components)
+			return
+		}
+
+		result, err := transform.MergeSpecs(args, outputFile, opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Merge error:", err)
+			os.Exit(2)
+		}
+		printMergeResults(result, outputFile)
+	},
+}
+
+func init() {
+	mergeCmd.Flags().StringVar(&mergeConflict, "conflict", transform.MergeConflictError, "Component conflict strategy: error, rename-prefix or prefer-first")
+	mergeCmd.Flags().StringSliceVar(&mergePrefixes, "prefixes", nil, "Component prefixes for rename-prefix, one per input in order (defaults to PascalCased file names)")
+	rootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_Merge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	first := filepath.Join(tempDir, "users.yaml")
+	second := filepath.Join(tempDir, "billing.yaml")
+	outputFile := filepath.Join(tempDir, "combined.yaml")
+
+	spec := `openapi: 3.0.0
+info:
+  title: %s API
+  version: 1.0.0
+paths:
+  /%s:
+    get:
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+components:
+  schemas:
+    Item:
+      type: %s
+`
+	if err := os.WriteFile(first, []byte(fmt.Sprintf(spec, "Users", "users", "object")), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+	if err := os.WriteFile(second, []byte(fmt.Sprintf(spec, "Billing", "invoices", "string")), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	// Conflicting components fail with the default strategy
+	cmd := exec.Command("go", "run", "../main.go", "merge", first, second, "-o", outputFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("expected merge conflict error, got success:\n%s", out)
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "merge", first, second, "--conflict", "rename-prefix", "-o", outputFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("merge failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	output := string(data)
+	for _, want := range []string{"/users:", "/invoices:", "BillingItem:", "$ref: '#/components/schemas/BillingItem'"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected merged output to contain %q:\n%s", want, output)
+		}
+	}
+}
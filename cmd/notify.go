@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/notify"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+// sendNotifications posts the run summary to the webhooks under notify:. Webhook failures are
+// reported but never change the outcome of the run.
+func sendNotifications(cfg config.Notify, input, validation string, runErr error, results ...*transform.TransformationResults) {
+	if len(cfg.Webhooks) == 0 {
+		return
+	}
+	summary := notify.NewSummary(input, GetVersion(), validation, runErr, results...)
+	for _, err := range notify.Send(cfg, summary) {
+		fmt.Fprintf(os.Stderr, "%s⚠️  Notification failed:%s %v\n", colorYellow, colorReset, err)
+	}
+}
+
+// validationStatus returns the notification validation status of a run that got past validation
+func validationStatus(enabled bool) string {
+	if !enabled {
+		return notify.ValidationSkipped
+	}
+	return notify.ValidationPassed
+}
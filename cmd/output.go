@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/developerkunal/OpenMorph/internal/config"
 	"github.com/developerkunal/OpenMorph/internal/transform"
@@ -43,6 +46,12 @@ func printCoreSettings(cfg *config.Config, outputFile string) {
 	if outputFile != "" {
 		fmt.Printf("   📄 %sOutput:%s        %s%s%s\n", colorCyan, colorReset, colorGreen, outputFile, colorReset)
 	}
+	for _, variant := range cfg.Outputs {
+		fmt.Printf("   📦 %sOutput:%s        %s%s%s (%s)\n", colorCyan, colorReset, colorGreen, variant.Dir, colorReset, variant.Profile)
+	}
+	if len(cfg.OutputFormats) > 0 {
+		fmt.Printf("   🗂️  %sFormats:%s       %s%v%s\n", colorCyan, colorReset, colorGreen, cfg.OutputFormats, colorReset)
+	}
 	fmt.Printf("   💾 %sBackup:%s        %s%v%s\n", colorCyan, colorReset, getStatusColor(cfg.Backup), cfg.Backup, colorReset)
 	fmt.Printf("   ✅ %sValidate:%s      %s%v%s\n", colorCyan, colorReset, getStatusColor(cfg.Validate), cfg.Validate, colorReset)
 	fmt.Printf("   🔄 %sFlatten:%s       %s%v%s\n", colorCyan, colorReset, getStatusColor(cfg.FlattenResponses), cfg.FlattenResponses, colorReset)
@@ -50,28 +59,128 @@ func printCoreSettings(cfg *config.Config, outputFile string) {
 
 // printAdditionalSettings prints additional configuration settings
 func printAdditionalSettings(cfg *config.Config) {
-	if len(cfg.Exclude) > 0 || len(cfg.PaginationPriority) > 0 {
+	hasFileFilter := len(cfg.Files.Include) > 0 || len(cfg.Files.Exclude) > 0 || len(cfg.Files.Steps) > 0
+	if len(cfg.Exclude) > 0 || hasFileFilter || len(cfg.PaginationPriority) > 0 || len(cfg.OnlyPaths) > 0 || cfg.AsyncAPI.Enabled || len(cfg.Notify.Webhooks) > 0 {
 		fmt.Printf("\n%s⚙️  Additional Settings%s\n", colorBold, colorReset)
 
 		if len(cfg.Exclude) > 0 {
 			fmt.Printf("   🚫 %sExclude:%s       %s%v%s\n", colorCyan, colorReset, colorYellow, cfg.Exclude, colorReset)
 		}
 
+		if hasFileFilter {
+			printFileFilter(cfg.Files)
+		}
+
 		if len(cfg.PaginationPriority) > 0 {
 			fmt.Printf("   📊 %sPagination:%s    %s%v%s\n", colorCyan, colorReset, colorPurple, cfg.PaginationPriority, colorReset)
 		}
+
+		if protect := cfg.Protect; len(protect.Paths)+len(protect.Components)+len(protect.Extensions) > 0 {
+			fmt.Printf("   🛡️  %sProtected:%s     %s%d paths, %d components, %d extensions%s\n", colorCyan, colorReset, colorPurple,
+				len(protect.Paths), len(protect.Components), len(protect.Extensions), colorReset)
+		}
+
+		if len(cfg.OnlyPaths) > 0 {
+			fmt.Printf("   🎯 %sOnly paths:%s    %s%v%s\n", colorCyan, colorReset, colorPurple, cfg.OnlyPaths, colorReset)
+		}
+
+		if cfg.AsyncAPI.Enabled {
+			fmt.Printf("   📨 %sAsyncAPI:%s      %sstrip_internal and default_values included%s\n", colorCyan, colorReset, colorGreen, colorReset)
+		}
+
+		if len(cfg.Notify.Webhooks) > 0 {
+			fmt.Printf("   🔔 %sNotify:%s        %s%d webhook(s)%s\n", colorCyan, colorReset, colorGreen, len(cfg.Notify.Webhooks), colorReset)
+		}
+	}
+}
+
+// printFileFilter prints the file include/exclude patterns and the steps that override them
+func printFileFilter(files config.FileFilter) {
+	if len(files.Include) > 0 {
+		fmt.Printf("   📂 %sInclude files:%s %s%v%s\n", colorCyan, colorReset, colorGreen, files.Include, colorReset)
+	}
+	if len(files.Exclude) > 0 {
+		fmt.Printf("   📂 %sExclude files:%s %s%v%s\n", colorCyan, colorReset, colorYellow, files.Exclude, colorReset)
+	}
+	steps := make([]string, 0, len(files.Steps))
+	for step := range files.Steps {
+		steps = append(steps, step)
+	}
+	sort.Strings(steps)
+	for _, step := range steps {
+		patterns := files.ForStep(step)
+		fmt.Printf("      %s↳ %s:%s include %v, exclude %v\n", colorBlue, step, colorReset, patterns.Include, patterns.Exclude)
 	}
 }
 
 // printEnabledFeatures prints the enabled features section
 func printEnabledFeatures(cfg *config.Config, vendorProviders []string) {
-	featureEnabled := cfg.VendorExtensions.Enabled || cfg.DefaultValues.Enabled
+	featureEnabled := cfg.StripInternal.Enabled || cfg.PathVariants.Enabled || cfg.TenantPaths.Enabled || cfg.UnwrapEnvelopes.Enabled || cfg.ParameterInjection.Enabled ||
+		cfg.VendorExtensions.Enabled || cfg.DefaultValues.Enabled || cfg.SchemaConstraints.Enabled ||
+		cfg.Nullability.Enabled || cfg.ComponentDedup.Enabled || cfg.ComponentRenames.Enabled || cfg.SchemaTitles.Enabled ||
+		cfg.Consistency.Enabled || cfg.Examples.Enabled || cfg.Canonicalize.Enabled
 	if !featureEnabled {
 		return
 	}
 
 	fmt.Printf("\n%s🚀 Enabled Features%s\n", colorBold, colorReset)
 
+	// Internal content stripping
+	if cfg.StripInternal.Enabled {
+		extension := cfg.StripInternal.Extension
+		if extension == "" {
+			extension = "x-internal"
+		}
+		fmt.Printf("   🔒 %sStrip Internal%s\n", colorGreen, colorReset)
+		fmt.Printf("      %s↳ Marker:%s       %s%s: true%s\n", colorBlue, colorReset, colorGreen, extension, colorReset)
+	}
+
+	// Path variant merging
+	if cfg.PathVariants.Enabled {
+		slash := cfg.PathVariants.TrailingSlash
+		if slash == "" {
+			slash = transform.TrailingSlashStrip
+		}
+		fmt.Printf("   🔀 %sPath Variants%s\n", colorGreen, colorReset)
+		fmt.Printf("      %s↳ Trailing /:%s   %s%s%s\n", colorBlue, colorReset, colorGreen, slash, colorReset)
+		if len(cfg.PathVariants.VersionPrefixes) > 0 {
+			fmt.Printf("      %s↳ Versions:%s     %s%s%s\n", colorBlue, colorReset, colorGreen, strings.Join(cfg.PathVariants.VersionPrefixes, ", "), colorReset)
+		}
+	}
+
+	// Tenant path segment
+	if cfg.TenantPaths.Enabled {
+		mode := cfg.TenantPaths.Mode
+		if mode == "" {
+			mode = transform.TenantPathsInject
+		}
+		parameter := cfg.TenantPaths.Parameter
+		if parameter == "" {
+			parameter = "tenantId"
+		}
+		fmt.Printf("   🏢 %sTenant Paths%s\n", colorGreen, colorReset)
+		fmt.Printf("      %s↳ Mode:%s         %s%s /{%s}%s\n", colorBlue, colorReset, colorGreen, mode, parameter, colorReset)
+	}
+
+	// Response envelope unwrapping
+	if cfg.UnwrapEnvelopes.Enabled {
+		fmt.Printf("   📭 %sUnwrap Response Envelopes%s\n", colorGreen, colorReset)
+		if n := len(cfg.UnwrapEnvelopes.Envelopes); n > 0 {
+			fmt.Printf("      %s↳ Envelopes:%s    %s%d configured%s\n", colorBlue, colorReset, colorGreen, n, colorReset)
+		} else {
+			fmt.Printf("      %s↳ Envelopes:%s    %sdata + code, message (default)%s\n", colorBlue, colorReset, colorGreen, colorReset)
+		}
+		if cfg.UnwrapEnvelopes.Extension != "" {
+			fmt.Printf("      %s↳ Recorded in:%s  %s%s%s\n", colorBlue, colorReset, colorGreen, cfg.UnwrapEnvelopes.Extension, colorReset)
+		}
+	}
+
+	// Parameter injection
+	if cfg.ParameterInjection.Enabled {
+		fmt.Printf("   💉 %sParameter Injection%s\n", colorGreen, colorReset)
+		fmt.Printf("      %s↳ Parameters:%s   %s%d configured%s\n", colorBlue, colorReset, colorGreen, len(cfg.ParameterInjection.Parameters), colorReset)
+	}
+
 	// Vendor extensions
 	if cfg.VendorExtensions.Enabled {
 		printVendorExtensionFeature(cfg, vendorProviders)
@@ -81,6 +190,116 @@ func printEnabledFeatures(cfg *config.Config, vendorProviders []string) {
 	if cfg.DefaultValues.Enabled {
 		printDefaultValuesFeature(cfg)
 	}
+
+	// Schema constraints
+	if cfg.SchemaConstraints.Enabled {
+		fmt.Printf("   📏 %sSchema Constraints%s\n", colorGreen, colorReset)
+		if len(cfg.SchemaConstraints.Rules) > 0 {
+			fmt.Printf("      %s↳ Rules:%s        %s%d configured%s\n", colorBlue, colorReset, colorGreen, len(cfg.SchemaConstraints.Rules), colorReset)
+		}
+		if cfg.SchemaConstraints.ExclusiveBounds != "" {
+			fmt.Printf("      %s↳ Exclusive:%s    %s%s form%s\n", colorBlue, colorReset, colorGreen, cfg.SchemaConstraints.ExclusiveBounds, colorReset)
+		}
+	}
+
+	// Nullability policy
+	if cfg.Nullability.Enabled {
+		printNullabilityFeature(cfg)
+	}
+
+	// Component deduplication
+	if cfg.ComponentDedup.Enabled {
+		printComponentDedupFeature(cfg)
+	}
+
+	// Component renames
+	if cfg.ComponentRenames.Enabled {
+		printComponentRenamesFeature(cfg)
+	}
+
+	// Schema titles
+	if cfg.SchemaTitles.Enabled {
+		casing := cfg.SchemaTitles.Casing
+		if casing == "" {
+			casing = transform.CasingTitle
+		}
+		existing := "kept"
+		if cfg.SchemaTitles.Normalize {
+			existing = "normalized"
+		}
+		fmt.Printf("   🏷️  %sSchema Titles%s\n", colorGreen, colorReset)
+		fmt.Printf("      %s↳ Casing:%s       %s%s%s\n", colorBlue, colorReset, colorGreen, casing, colorReset)
+		fmt.Printf("      %s↳ Existing:%s     %s%s%s\n", colorBlue, colorReset, colorGreen, existing, colorReset)
+	}
+
+	// Cross-file component consistency
+	if cfg.Consistency.Enabled {
+		action := "report"
+		if cfg.Consistency.Harmonize {
+			prefer := cfg.Consistency.Prefer
+			if prefer == "" {
+				prefer = transform.ConsistencyPreferMostCommon
+			}
+			action = fmt.Sprintf("harmonize (prefer %s)", prefer)
+		}
+		fmt.Printf("   🧩 %sComponent Consistency%s\n", colorGreen, colorReset)
+		fmt.Printf("      %s↳ Conflicts:%s    %s%s%s\n", colorBlue, colorReset, colorGreen, action, colorReset)
+	}
+
+	// Example repair
+	if cfg.Examples.Enabled {
+		mode := cfg.Examples.Mode
+		if mode == "" {
+			mode = transform.ExamplesModePrune
+		}
+		fmt.Printf("   🧪 %sExample Repair%s\n", colorGreen, colorReset)
+		fmt.Printf("      %s↳ Mode:%s         %s%s%s\n", colorBlue, colorReset, colorGreen, mode, colorReset)
+	}
+
+	// Canonical ordering
+	if cfg.Canonicalize.Enabled {
+		order := "default"
+		if len(cfg.Canonicalize.OperationKeyOrder) > 0 {
+			order = strings.Join(cfg.Canonicalize.OperationKeyOrder, ", ")
+		}
+		fmt.Printf("   🔤 %sCanonicalize%s\n", colorGreen, colorReset)
+		fmt.Printf("      %s↳ Key order:%s    %s%s%s\n", colorBlue, colorReset, colorGreen, order, colorReset)
+	}
+}
+
+// printNullabilityFeature prints nullability policy feature details
+func printNullabilityFeature(cfg *config.Config) {
+	policy := cfg.Nullability
+	fmt.Printf("   ∅  %sNullability Policy%s\n", colorGreen, colorReset)
+	if policy.Strip {
+		fmt.Printf("      %s↳ Policy:%s       %sstrip nullability%s\n", colorBlue, colorReset, colorGreen, colorReset)
+	}
+	if policy.OptionalProperties != "" {
+		fmt.Printf("      %s↳ Optional:%s     %s%s%s\n", colorBlue, colorReset, colorGreen, policy.OptionalProperties, colorReset)
+	}
+	if policy.Form != "" {
+		fmt.Printf("      %s↳ Form:%s         %s%s%s\n", colorBlue, colorReset, colorGreen, policy.Form, colorReset)
+	}
+}
+
+// printComponentDedupFeature prints component deduplication feature details
+func printComponentDedupFeature(cfg *config.Config) {
+	prefer := cfg.ComponentDedup.Prefer
+	if prefer == "" {
+		prefer = transform.DedupPreferShortest
+	}
+	fmt.Printf("   🧬 %sComponent Dedup%s\n", colorGreen, colorReset)
+	fmt.Printf("      %s↳ Prefer:%s       %s%s%s\n", colorBlue, colorReset, colorGreen, prefer, colorReset)
+}
+
+// printComponentRenamesFeature prints component rename feature details
+func printComponentRenamesFeature(cfg *config.Config) {
+	renames := cfg.ComponentRenames
+	fmt.Printf("   ✏️  %sComponent Renames%s\n", colorGreen, colorReset)
+	ruleCount := len(renames.Map) + len(renames.StripPrefixes) + len(renames.StripSuffixes) + len(renames.Patterns)
+	if ruleCount > 0 {
+		fmt.Printf("      %s↳ Rules:%s        %s%d configured%s\n", colorBlue, colorReset, colorGreen, ruleCount, colorReset)
+	}
 }
 
 // printVendorExtensionFeature prints vendor extension feature details
@@ -115,6 +334,22 @@ func printMappingsSection(cfg *config.Config) {
 		for k, v := range cfg.Mappings {
 			fmt.Printf("   %s%s%s %s→%s %s%s%s\n", colorYellow, k, colorReset, colorGreen, colorReset, colorBlue, v, colorReset)
 		}
+		if cfg.MappingCollisions != "" {
+			fmt.Printf("   💥 %sOn collision:%s  %s%s%s\n", colorCyan, colorReset, colorPurple, cfg.MappingCollisions, colorReset)
+		}
+	}
+
+	// Extension value lookup tables
+	if len(cfg.ExtensionValues) > 0 {
+		fmt.Printf("\n%s🏷️  Extension Value Mappings%s\n", colorBold, colorReset)
+		extensions := make([]string, 0, len(cfg.ExtensionValues))
+		for extension := range cfg.ExtensionValues {
+			extensions = append(extensions, extension)
+		}
+		sort.Strings(extensions)
+		for _, extension := range extensions {
+			fmt.Printf("   %s%s%s %s→%s %s%d values%s\n", colorYellow, extension, colorReset, colorGreen, colorReset, colorBlue, len(cfg.ExtensionValues[extension]), colorReset)
+		}
 	}
 }
 
@@ -176,6 +411,76 @@ func printPaginationResults(paginationResult *transform.PaginationResult) {
 			}
 		}
 
+		if len(paginationResult.MergedParams) > 0 {
+			fmt.Printf("\n%s🔗 Merged Duplicate Parameters%s\n", colorCyan, colorReset)
+			for operation, params := range paginationResult.MergedParams {
+				fmt.Printf("   %s●%s %s%s%s\n", colorYellow, colorReset, colorBold, operation, colorReset)
+				for _, param := range params {
+					fmt.Printf("     %s▸%s %s\n", colorCyan, colorReset, param)
+				}
+			}
+		}
+
+		if len(paginationResult.MovedParams) > 0 {
+			fmt.Printf("\n%s↘️  Path-Level Parameters Moved to Operations%s\n", colorCyan, colorReset)
+			for operation, params := range paginationResult.MovedParams {
+				fmt.Printf("   %s●%s %s%s%s\n", colorYellow, colorReset, colorBold, operation, colorReset)
+				for _, param := range params {
+					fmt.Printf("     %s▸%s %s\n", colorCyan, colorReset, param)
+				}
+			}
+		}
+
+		if len(paginationResult.RemovedLinks) > 0 {
+			fmt.Printf("\n%s🔗 Removed Response Links%s\n", colorRed, colorReset)
+			for operation, links := range paginationResult.RemovedLinks {
+				fmt.Printf("   %s●%s %s%s%s\n", colorYellow, colorReset, colorBold, operation, colorReset)
+				for _, link := range links {
+					fmt.Printf("     %s▸%s %s%s%s\n", colorRed, colorReset, colorRed, link, colorReset)
+				}
+			}
+		}
+
+		if len(paginationResult.PageComponents) > 0 {
+			fmt.Printf("\n%s📦 Responses Using Page Components%s\n", colorCyan, colorReset)
+			for operation, entries := range paginationResult.PageComponents {
+				fmt.Printf("   %s●%s %s%s%s\n", colorYellow, colorReset, colorBold, operation, colorReset)
+				for _, entry := range entries {
+					fmt.Printf("     %s▸%s %s\n", colorCyan, colorReset, entry)
+				}
+			}
+		}
+
+		if len(paginationResult.Metadata) > 0 {
+			fmt.Printf("\n%s🏷️  Pagination Metadata%s\n", colorCyan, colorReset)
+			for operation, entries := range paginationResult.Metadata {
+				fmt.Printf("   %s●%s %s%s%s\n", colorYellow, colorReset, colorBold, operation, colorReset)
+				for _, entry := range entries {
+					fmt.Printf("     %s▸%s %s\n", colorCyan, colorReset, entry)
+				}
+			}
+		}
+
+		if len(paginationResult.Headers) > 0 {
+			fmt.Printf("\n%s📨 Pagination Response Headers%s\n", colorCyan, colorReset)
+			for operation, entries := range paginationResult.Headers {
+				fmt.Printf("   %s●%s %s%s%s\n", colorYellow, colorReset, colorBold, operation, colorReset)
+				for _, entry := range entries {
+					fmt.Printf("     %s▸%s %s\n", colorCyan, colorReset, entry)
+				}
+			}
+		}
+
+		if len(paginationResult.ScrubbedSentences) > 0 {
+			fmt.Printf("\n%s✂️  Paging Sentences Removed from Descriptions%s\n", colorCyan, colorReset)
+			for operation, sentences := range paginationResult.ScrubbedSentences {
+				fmt.Printf("   %s●%s %s%s%s\n", colorYellow, colorReset, colorBold, operation, colorReset)
+				for _, sentence := range sentences {
+					fmt.Printf("     %s▸%s %s\n", colorCyan, colorReset, sentence)
+				}
+			}
+		}
+
 		fmt.Printf("\n%s┌─────────────────────────────────────────────────────────────────┐%s\n", colorGreen, colorReset)
 		fmt.Printf("%s│%s %s✅ Pagination cleanup completed successfully%s %s              │%s\n", colorGreen, colorReset, colorBold, colorReset, colorGreen, colorReset)
 		fmt.Printf("%s└─────────────────────────────────────────────────────────────────┘%s\n", colorGreen, colorReset)
@@ -190,9 +495,11 @@ func printFlattenResultsImproved(flattenResult *transform.FlattenResult) {
 		printFlattenHeader(flattenResult)
 		printFlattenedRefs(flattenResult.FlattenedRefs)
 		printRemovedComponents(flattenResult.RemovedComponents)
+		printSkippedFlattening(flattenResult.SkippedSchemas)
 		printSuccess("Response flattening completed successfully")
 	} else {
 		printInfo("No flattening changes needed")
+		printSkippedFlattening(flattenResult.SkippedSchemas)
 	}
 }
 
@@ -319,12 +626,29 @@ func printRemovedComponents(removedComponents map[string][]string) {
 	}
 }
 
+// printSkippedFlattening prints the schemas flattening left alone, with the reason
+func printSkippedFlattening(skippedSchemas map[string][]transform.Skip) {
+	if len(skippedSchemas) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s⏭️  Not Flattened%s\n", colorYellow, colorReset)
+	for file, schemas := range skippedSchemas {
+		fmt.Printf("   %s●%s %s%s%s\n", colorYellow, colorReset, colorBold, file, colorReset)
+		for _, schema := range schemas {
+			fmt.Printf("     %s▸%s %s\n", colorYellow, colorReset, schema.Message)
+		}
+	}
+}
+
 // Vendor extension results printing
 func printVendorExtensionResults(vendorResult *transform.VendorExtensionResult) {
 	if vendorResult.Changed {
 		printVendorExtensionHeader(vendorResult)
 		printAddedExtensions(vendorResult.AddedExtensions)
 		printSkippedOperations(vendorResult.SkippedOperations)
+		printResultsFields(vendorResult.ResultsFields)
+		printProviderOperations(vendorResult.OperationsTouched)
 		printSuccess("Vendor extensions added successfully")
 	} else {
 		printInfo("No vendor extension changes needed")
@@ -350,6 +674,46 @@ func printAddedExtensions(addedExtensions map[string][]string) {
 	}
 }
 
+// printResultsFields lists, with --verbose, the auto-detected results fields that were picked over
+// other array fields of the response
+func printResultsFields(resultsFields map[string][]string) {
+	if !verbose || len(resultsFields) == 0 {
+		return
+	}
+
+	files := make([]string, 0, len(resultsFields))
+	for file := range resultsFields {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	fmt.Printf("\n🔎 %sAuto-detected Results Fields%s\n", colorCyan, colorReset)
+	for _, file := range files {
+		printFileHeader(file)
+		for _, choice := range resultsFields[file] {
+			printListItem(choice, colorCyan)
+		}
+	}
+}
+
+// printProviderOperations prints how many operations received each provider's extension
+func printProviderOperations(operationsTouched map[string]int) {
+	if len(operationsTouched) == 0 {
+		return
+	}
+
+	providers := make([]string, 0, len(operationsTouched))
+	for provider := range operationsTouched {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	fmt.Printf("\n📊 %sOperations per Provider%s\n", colorCyan, colorReset)
+	for _, provider := range providers {
+		printListItem(fmt.Sprintf("%s: %d operations", provider, operationsTouched[provider]), colorGreen)
+	}
+}
+
 func groupExtensionsByStrategy(extensions []string) map[string][]string {
 	strategies := make(map[string][]string)
 	for _, ext := range extensions {
@@ -394,7 +758,7 @@ func printStrategyExtension(ext string) {
 	}
 }
 
-func printSkippedOperations(skippedOperations map[string][]string) {
+func printSkippedOperations(skippedOperations map[string][]transform.Skip) {
 	if len(skippedOperations) == 0 {
 		return
 	}
@@ -410,7 +774,7 @@ func printSkippedOperations(skippedOperations map[string][]string) {
 			if len(operations) > 0 {
 				printFileHeader(file)
 				for _, op := range operations {
-					printListItem(op, colorYellow)
+					printListItem(op.Message, colorYellow)
 				}
 			}
 		}
@@ -424,6 +788,12 @@ func printSkippedOperations(skippedOperations map[string][]string) {
 func printDefaultsResults(defaultsResult *transform.DefaultsResult) {
 	if defaultsResult.Changed {
 		printDefaultsHeader(defaultsResult)
+		if verbose && len(defaultsResult.RuleOrder) > 0 {
+			fmt.Printf("\n📋 %sRule Order%s\n", colorCyan, colorReset)
+			for _, rule := range defaultsResult.RuleOrder {
+				printListItem(rule, colorCyan)
+			}
+		}
 		printAppliedDefaults(defaultsResult.AppliedDefaults)
 		printSkippedTargets(defaultsResult.SkippedTargets)
 		printSuccess("Default values added successfully")
@@ -452,7 +822,7 @@ func printAppliedDefaults(appliedDefaults map[string][]string) {
 	}
 }
 
-func printSkippedTargets(skippedTargets map[string][]string) {
+func printSkippedTargets(skippedTargets map[string][]transform.Skip) {
 	if len(skippedTargets) == 0 {
 		return
 	}
@@ -468,7 +838,7 @@ func printSkippedTargets(skippedTargets map[string][]string) {
 			if len(targets) > 0 {
 				printFileHeader(file)
 				for _, targetInfo := range targets {
-					printListItem(targetInfo, colorYellow)
+					printListItem(targetInfo.Message, colorYellow)
 				}
 			}
 		}
@@ -477,3 +847,1024 @@ func printSkippedTargets(skippedTargets map[string][]string) {
 			colorYellow, colorBold, totalSkipped, colorReset)
 	}
 }
+
+// printPipelineResults prints the results of every transformation step that ran
+func printPipelineResults(results *transform.TransformationResults) {
+	printMappingCollisions(results.KeyChanges)
+	if results.InternalResult != nil {
+		printInternalResults(results.InternalResult)
+	}
+	if results.PathVariantsResult != nil {
+		printPathVariantsResults(results.PathVariantsResult)
+	}
+	if results.TenantPathsResult != nil {
+		printTenantPathsResults(results.TenantPathsResult)
+	}
+	if results.EnvelopeResult != nil {
+		printEnvelopeResults(results.EnvelopeResult)
+	}
+	if results.PaginationResult != nil {
+		printPaginationResults(results.PaginationResult)
+	}
+	if results.FlattenResult != nil {
+		printFlattenResultsImproved(results.FlattenResult)
+	}
+	if results.InjectionResult != nil {
+		printInjectionResults(results.InjectionResult)
+	}
+	if results.VendorResult != nil {
+		printVendorExtensionResults(results.VendorResult)
+	}
+	if results.ExtensionValuesResult != nil {
+		printExtensionValuesResults(results.ExtensionValuesResult)
+	}
+	if results.DefaultsResult != nil {
+		printDefaultsResults(results.DefaultsResult)
+	}
+	if results.ConstraintsResult != nil {
+		printConstraintsResults(results.ConstraintsResult)
+	}
+	if results.NullabilityResult != nil {
+		printNullabilityResults(results.NullabilityResult)
+	}
+	if results.DedupResult != nil {
+		printDedupResults(results.DedupResult)
+	}
+	if results.RenameResult != nil {
+		printRenameResults(results.RenameResult)
+	}
+	if results.SchemaTitlesResult != nil {
+		printSchemaTitlesResults(results.SchemaTitlesResult)
+	}
+	if results.ConsistencyResult != nil {
+		printConsistencyResults(results.ConsistencyResult)
+	}
+	if results.ExamplesResult != nil {
+		printExamplesResults(results.ExamplesResult)
+	}
+	if results.CanonicalizeResult != nil {
+		printCanonicalizeResults(results.CanonicalizeResult)
+	}
+	if results.LinkResult != nil && results.LinkResult.Changed {
+		printLinkSyncResults(results.LinkResult)
+	}
+	if results.ArazzoResult != nil {
+		printArazzoResults(results.ArazzoResult)
+	}
+	printChangeLocations(results.AllLocations())
+	printFileMetrics(results.FileMetrics)
+	printSkippedFiles(results.SkippedFiles)
+	printSkipReasons(results.SkipGroups())
+	printProtectedSkips(results.ProtectedSkips)
+	printUnresolvedRefs(results.PaginationResult)
+	printDanglingRefs(results.DanglingRefs)
+	printEmptySchemas(results.EmptySchemas)
+	printPrunedContainers(results.PrunedContainers)
+	printInvariantViolations(results.InvariantViolations)
+	if len(results.StampedFiles) > 0 {
+		fmt.Printf("\n📜 %sStamped the provenance of %d files%s\n", colorCyan, len(results.StampedFiles), colorReset)
+	}
+	if len(results.FormatOutputs) > 0 {
+		fmt.Printf("\n🗂️  %sWrote %d files in another format%s\n", colorCyan, len(results.FormatOutputs), colorReset)
+		for _, file := range results.FormatOutputs {
+			printListItem(file, colorGreen)
+		}
+	}
+	if results.BackupRun != nil {
+		fmt.Printf("\n💾 %sBacked up %d files to %s%s\n", colorCyan, len(results.BackupRun.Files), results.BackupRun.Dir, colorReset)
+	}
+}
+
+// printMappingCollisions reports the mappings whose target key already existed and how each was resolved
+func printMappingCollisions(changes []transform.KeyChange) {
+	var collisions []transform.KeyChange
+	for _, change := range changes {
+		if change.Collision != "" {
+			collisions = append(collisions, change)
+		}
+	}
+	if len(collisions) == 0 {
+		return
+	}
+
+	printHeader("Mapping Collisions", "💥")
+	for _, change := range collisions {
+		location := transform.ChangeLocation{File: change.File, Line: change.Line, Column: change.Column}
+		printListItem(fmt.Sprintf("%s %s -> %s (%s)", location.Position(), change.OldKey, change.NewKey, change.Collision), colorYellow)
+	}
+}
+
+// reportMappingUsage prints how many keys each configured mapping matched during the run and flags
+// the mappings that matched nothing, with document keys that look like they were meant instead
+func reportMappingUsage(cfg *config.Config, inputPath string, changes []transform.KeyChange) {
+	if len(cfg.Mappings) == 0 {
+		return
+	}
+	stats := transform.MappingStats(cfg.Mappings, changes)
+	if err := transform.FindNearMisses(inputPath, cfg.Files, cfg.Mappings, stats); err != nil {
+		fmt.Fprintln(os.Stderr, "Mapping usage scan error:", err)
+	}
+
+	printHeader("Mapping Usage", "📊")
+	for _, stat := range stats {
+		if stat.Matches > 0 {
+			printListItem(fmt.Sprintf("%s → %s: %d matches", stat.From, stat.To, stat.Matches), colorGreen)
+			continue
+		}
+		printListItem(fmt.Sprintf("%s⚠️  %s → %s: no matches%s", colorYellow, stat.From, stat.To, colorReset), colorYellow)
+		for _, miss := range stat.NearMisses {
+			fmt.Printf("         did you mean %s%s%s? %d occurrences, first at %s\n",
+				colorBold, miss.Name, colorReset, len(miss.Occurrences), miss.Occurrences[0].Position())
+		}
+	}
+}
+
+// printSkippedFiles reports the YAML/JSON files the OpenAPI steps skipped without parsing
+func printSkippedFiles(skipped []transform.SkippedFile) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	if verbose {
+		fmt.Printf("\n⏭️  %sSkipped Files:%s %s%d%s\n", colorYellow, colorReset, colorBold, len(skipped), colorReset)
+		for _, file := range skipped {
+			printListItem(fmt.Sprintf("%s (%s)", file.File, file.Reason), colorYellow)
+		}
+	} else {
+		fmt.Printf("\n⏭️  %sSkipped Files: %s%d%s not OpenAPI or marked x-openmorph: ignore (use --verbose for details)\n",
+			colorYellow, colorBold, len(skipped), colorReset)
+	}
+}
+
+// skipExamples is how many items --explain-skips lists per reason without --verbose
+const skipExamples = 3
+
+// printSkipReasons counts the items the steps skipped per reason, or with --explain-skips groups
+// them by reason
+func printSkipReasons(groups []transform.SkipGroup) {
+	if len(groups) == 0 {
+		return
+	}
+
+	total := 0
+	counts := make([]string, len(groups))
+	for i, group := range groups {
+		total += len(group.Items)
+		counts[i] = fmt.Sprintf("%s %d", group.Code, len(group.Items))
+	}
+	if !explainSkips {
+		fmt.Printf("\n⏭️  %sSkip Reasons: %s%d%s (%s, use --explain-skips for details)\n",
+			colorYellow, colorBold, total, colorReset, strings.Join(counts, ", "))
+		return
+	}
+
+	printHeader("Skip Reasons", "⏭️")
+	for _, group := range groups {
+		label := string(group.Code)
+		if len(group.Steps) > 0 {
+			label += ", " + strings.Join(group.Steps, ", ")
+		}
+		fmt.Printf("   %s●%s %s%s: %d occurrences%s (%s)\n",
+			colorYellow, colorReset, colorBold, group.Code.Description(), len(group.Items), colorReset, label)
+		items := group.Items
+		if !verbose && len(items) > skipExamples {
+			items = items[:skipExamples]
+		}
+		for _, item := range items {
+			fmt.Printf("     %s▸%s %s\n", colorYellow, colorReset, item)
+		}
+		if len(items) < len(group.Items) {
+			fmt.Printf("     %s… %d more (use --verbose for all)%s\n", colorYellow, len(group.Items)-len(items), colorReset)
+		}
+	}
+}
+
+// printProtectedSkips reports the changes to protected items that were undone
+func printProtectedSkips(skips []transform.ProtectedSkip) {
+	if len(skips) == 0 {
+		return
+	}
+
+	printHeader("Protected", "🛡️")
+	for _, skip := range skips {
+		printListItem(fmt.Sprintf("%s: %s skipped by %s (%s)", skip.File, skip.Item, skip.Step, skip.Reason), colorYellow)
+	}
+}
+
+// printUnresolvedRefs lists the $refs the pagination step followed but could not resolve, and the
+// $dynamicRefs it resolved best-effort
+func printUnresolvedRefs(paginationResult *transform.PaginationResult) {
+	if paginationResult == nil {
+		return
+	}
+	if len(paginationResult.DynamicRefs) > 0 {
+		printHeader("Dynamic $refs", "⚠️")
+		for _, ref := range paginationResult.DynamicRefs {
+			printListItem(fmt.Sprintf("%s: %s (followed for %s)", ref.Position(), ref.Message, ref.Operation), colorYellow)
+		}
+	}
+	if len(paginationResult.UnresolvedRefs) == 0 {
+		return
+	}
+
+	printHeader("Unresolved $refs", "⚠️")
+	for _, ref := range paginationResult.UnresolvedRefs {
+		printListItem(fmt.Sprintf("%s: %s (followed for %s)", ref.Position(), ref.Message, ref.Operation), colorYellow)
+	}
+	fmt.Printf("   %sPagination behind these references was not detected; --strict fails on them%s\n", colorYellow, colorReset)
+}
+
+// printDanglingRefs lists the $refs that steps left pointing at nodes that no longer exist
+func printDanglingRefs(refs []transform.DanglingRef) {
+	if len(refs) == 0 {
+		return
+	}
+
+	printHeader("Dangling $refs", "⚠️")
+	for _, ref := range refs {
+		printListItem(fmt.Sprintf("%s: %s (after %s)", ref.Position(), ref.Message, ref.Step), colorYellow)
+	}
+}
+
+// printEmptySchemas lists the object schemas and response contents that steps left empty
+func printEmptySchemas(schemas []transform.EmptySchema) {
+	if len(schemas) == 0 {
+		return
+	}
+
+	printHeader("Empty Schemas", "⚠️")
+	for _, schema := range schemas {
+		message := fmt.Sprintf("%s: %s (after %s)", schema.Position(), schema.Message, schema.Step)
+		if schema.Fixed {
+			message += ", fixed"
+		}
+		printListItem(message, colorYellow)
+	}
+}
+
+// printPrunedContainers lists the path items, request bodies and parameters lists removed after
+// steps left them empty
+func printPrunedContainers(containers []transform.PrunedContainer) {
+	if len(containers) == 0 {
+		return
+	}
+
+	printHeader("Pruned Empty Containers", "🧹")
+	for _, container := range containers {
+		printListItem(fmt.Sprintf("%s: %s (after %s), removed", container.Position(), container.Message, container.Step), colorGreen)
+	}
+}
+
+// printInvariantViolations lists the operations whose pagination is inconsistent after the pipeline
+func printInvariantViolations(violations []transform.InvariantViolation) {
+	if len(violations) == 0 {
+		return
+	}
+
+	printHeader("Pagination Invariants", "⚠️")
+	for _, violation := range violations {
+		printListItem(fmt.Sprintf("%s: %s", violation.Position(), violation.Message), colorYellow)
+	}
+}
+
+// printChangeLocations lists every change as file:line:column so terminals and editors can jump to it
+func printChangeLocations(locations []transform.ChangeLocation) {
+	if !verbose || len(locations) == 0 {
+		return
+	}
+
+	fmt.Printf("\n📍 %sChange Locations:%s %s%d%s\n", colorCyan, colorReset, colorBold, len(locations), colorReset)
+	for _, location := range locations {
+		fmt.Printf("   %s%s%s [%s] %s\n", colorBold, location.Position(), colorReset, location.Step, location.Message)
+	}
+}
+
+// printFileMetrics lists, with --verbose, how long each step took on each file and how it changed the
+// file's size, files sorted and steps in the order they ran
+func printFileMetrics(metrics []transform.FileMetric) {
+	if !verbose || len(metrics) == 0 {
+		return
+	}
+
+	byFile := make(map[string][]transform.FileMetric)
+	for _, metric := range metrics {
+		byFile[metric.File] = append(byFile[metric.File], metric)
+	}
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	fmt.Printf("\n⏱️  %sStep Timings:%s %s%d%s files\n", colorCyan, colorReset, colorBold, len(files), colorReset)
+	for _, file := range files {
+		printFileHeader(file)
+		for _, metric := range byFile[file] {
+			delta := metric.SizeDelta()
+			deltaColor := colorReset
+			switch {
+			case delta > 0:
+				deltaColor = colorYellow
+			case delta < 0:
+				deltaColor = colorGreen
+			}
+			fmt.Printf("      %-24s %10s  %d → %d bytes (%s%+d%s)\n", metric.Step, metric.Duration.Round(time.Microsecond),
+				metric.BytesBefore, metric.BytesAfter, deltaColor, delta, colorReset)
+		}
+	}
+}
+
+// Component deduplication results printing
+func printDedupResults(dedupResult *transform.DedupResult) {
+	if !dedupResult.Changed {
+		printInfo("No duplicate components found")
+		return
+	}
+
+	printHeader("Component Deduplication Results", "🧬")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(dedupResult.ProcessedFiles), colorReset)
+	fmt.Printf("\n✅ %sMerged Duplicates%s\n", colorGreen, colorReset)
+	for file, merges := range dedupResult.MergedComponents {
+		printFileHeader(file)
+		for _, merge := range merges {
+			printListItem(merge, colorGreen)
+		}
+	}
+	printSuccess("Duplicate components merged successfully")
+}
+
+// Component rename results printing
+func printRenameResults(renameResult *transform.RenameResult) {
+	if renameResult.Changed {
+		printHeader("Component Rename Results", "✏️")
+		fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+			colorCyan, colorReset, colorGreen, len(renameResult.ProcessedFiles), colorReset)
+		printRenamedComponents(renameResult.RenamedComponents)
+		printSkippedRenames(renameResult.SkippedRenames)
+		printSuccess("Components renamed successfully")
+	} else {
+		printInfo("No component renames needed")
+		printSkippedRenames(renameResult.SkippedRenames)
+	}
+}
+
+// Component consistency results printing
+func printConsistencyResults(consistencyResult *transform.ConsistencyResult) {
+	if len(consistencyResult.Conflicts) == 0 {
+		printInfo("No components differ between files")
+		return
+	}
+
+	printHeader("Component Consistency Results", "🧩")
+	components := make([]string, 0, len(consistencyResult.Conflicts))
+	for component := range consistencyResult.Conflicts {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	fmt.Printf("\n⚠️  %sComponents Defined Differently Across Files%s\n", colorYellow, colorReset)
+	for _, component := range components {
+		printFileHeader(component)
+		for _, definition := range consistencyResult.Conflicts[component] {
+			printListItem(definition, colorYellow)
+		}
+	}
+
+	if len(consistencyResult.HarmonizedComponents) > 0 {
+		fmt.Printf("\n✅ %sHarmonized Components%s\n", colorGreen, colorReset)
+		for file, components := range consistencyResult.HarmonizedComponents {
+			printFileHeader(file)
+			for _, component := range components {
+				printListItem(component, colorGreen)
+			}
+		}
+	}
+	if len(consistencyResult.SkippedComponents) > 0 {
+		fmt.Printf("\n⏭️  %sLeft As They Were%s\n", colorYellow, colorReset)
+		for file, components := range consistencyResult.SkippedComponents {
+			printFileHeader(file)
+			for _, component := range components {
+				printListItem(component.Message, colorYellow)
+			}
+		}
+	}
+	if consistencyResult.Changed {
+		printSuccess("Components harmonized successfully")
+	}
+}
+
+func printRenamedComponents(renamedComponents map[string][]string) {
+	if len(renamedComponents) == 0 {
+		return
+	}
+
+	fmt.Printf("\n✅ %sRenamed Components%s\n", colorGreen, colorReset)
+	for file, renames := range renamedComponents {
+		printFileHeader(file)
+		for _, rename := range renames {
+			printListItem(rename, colorGreen)
+		}
+	}
+}
+
+func printSkippedRenames(skippedRenames map[string][]transform.Skip) {
+	if len(skippedRenames) == 0 {
+		return
+	}
+
+	totalSkipped := 0
+	for _, renames := range skippedRenames {
+		totalSkipped += len(renames)
+	}
+
+	if verbose {
+		fmt.Printf("\n⏭️  %sSkipped Renames:%s %s%d%s\n", colorYellow, colorReset, colorBold, totalSkipped, colorReset)
+		for file, renames := range skippedRenames {
+			if len(renames) > 0 {
+				printFileHeader(file)
+				for _, rename := range renames {
+					printListItem(rename.Message, colorYellow)
+				}
+			}
+		}
+	} else {
+		fmt.Printf("\n⏭️  %sSkipped Renames: %s%d%s (use --verbose for details)\n",
+			colorYellow, colorBold, totalSkipped, colorReset)
+	}
+}
+
+// Extract results printing
+func printExtractResults(result *transform.ExtractResult, outputPath string) {
+	printHeader("Subset Extraction Results", "✂️")
+	fmt.Printf("📄 %sOutput file:%s %s%s%s\n", colorCyan, colorReset, colorGreen, outputPath, colorReset)
+	fmt.Printf("✅ %sKept operations:%s %s%d%s (removed %d)\n",
+		colorGreen, colorReset, colorBold, len(result.KeptOperations), colorReset, result.RemovedOperations)
+	fmt.Printf("🧩 %sKept components:%s %s%d%s (removed %d)\n",
+		colorGreen, colorReset, colorBold, len(result.KeptComponents), colorReset, len(result.RemovedComponents))
+
+	if verbose {
+		for _, op := range result.KeptOperations {
+			printListItem(op, colorGreen)
+		}
+		if len(result.RemovedTags) > 0 {
+			fmt.Printf("\n🏷️  %sRemoved tags:%s %v\n", colorYellow, colorReset, result.RemovedTags)
+		}
+	}
+	if len(result.SecondaryRemovals) > 0 {
+		fmt.Printf("\n🔗 %sRemoved dangling references:%s\n", colorYellow, colorReset)
+		for _, item := range result.SecondaryRemovals {
+			printListItem(item, colorYellow)
+		}
+	}
+	printSuccess("Subset extracted successfully")
+}
+
+// printDryRunStepHeader prints a numbered dry-run step header and advances the step counter
+func printDryRunStepHeader(step *int, title string) {
+	fmt.Printf("\033[1;36m[STEP %d] %s\033[0m\n", *step, title)
+	*step++
+}
+
+// printDryRunSteps prints the independent dry-run preview of every transformation step, ending
+// with the validation step header
+func printDryRunSteps(cfg *config.Config, results *transform.TransformationResults) {
+	step := 1
+	if results.InternalResult != nil {
+		printDryRunStepHeader(&step, "Internal content stripping changes")
+		printInternalResults(results.InternalResult)
+		fmt.Println()
+	}
+	if results.PathVariantsResult != nil {
+		printDryRunStepHeader(&step, "Path variant changes")
+		printPathVariantsResults(results.PathVariantsResult)
+		fmt.Println()
+	}
+	if results.TenantPathsResult != nil {
+		printDryRunStepHeader(&step, "Tenant path changes")
+		printTenantPathsResults(results.TenantPathsResult)
+		fmt.Println()
+	}
+	if results.EnvelopeResult != nil {
+		printDryRunStepHeader(&step, "Response envelope changes")
+		printEnvelopeResults(results.EnvelopeResult)
+		fmt.Println()
+	}
+	if results.PaginationResult != nil {
+		printDryRunStepHeader(&step, fmt.Sprintf("Pagination changes with priority: %v", cfg.PaginationPriority))
+		printPaginationResults(results.PaginationResult)
+		fmt.Println()
+	}
+	if results.VendorResult != nil {
+		printDryRunStepHeader(&step, "Vendor extensions changes")
+		printVendorExtensionResults(results.VendorResult)
+		fmt.Println()
+	}
+	if results.ExtensionValuesResult != nil {
+		printDryRunStepHeader(&step, "Extension value replacements")
+		printExtensionValuesResults(results.ExtensionValuesResult)
+		fmt.Println()
+	}
+	if results.DefaultsResult != nil {
+		printDryRunStepHeader(&step, "Default values changes")
+		printDefaultsResults(results.DefaultsResult)
+		fmt.Println()
+	}
+	if results.ConstraintsResult != nil {
+		printDryRunStepHeader(&step, "Schema constraint changes")
+		printConstraintsResults(results.ConstraintsResult)
+		fmt.Println()
+	}
+	if results.NullabilityResult != nil {
+		printDryRunStepHeader(&step, "Nullability policy changes")
+		printNullabilityResults(results.NullabilityResult)
+		fmt.Println()
+	}
+	if results.FlattenResult != nil {
+		printDryRunStepHeader(&step, "Response flattening changes")
+		fmt.Printf("\033[1;31m⚠️  CRITICAL: This preview operates on the ORIGINAL file.\033[0m\n")
+		fmt.Printf("\033[1;31m   Real execution will show SIGNIFICANTLY MORE changes\033[0m\n")
+		fmt.Printf("\033[1;31m   because pagination creates new schemas to flatten!\033[0m\n")
+		printFlattenResultsImproved(results.FlattenResult)
+		fmt.Println()
+	}
+	if results.InjectionResult != nil {
+		printDryRunStepHeader(&step, "Parameter injection changes")
+		printInjectionResults(results.InjectionResult)
+		fmt.Println()
+	}
+	if results.DedupResult != nil {
+		printDryRunStepHeader(&step, "Component deduplication changes")
+		printDedupResults(results.DedupResult)
+		fmt.Println()
+	}
+	if results.RenameResult != nil {
+		printDryRunStepHeader(&step, "Component rename changes")
+		printRenameResults(results.RenameResult)
+		fmt.Println()
+	}
+	if results.SchemaTitlesResult != nil {
+		printDryRunStepHeader(&step, "Schema title changes")
+		printSchemaTitlesResults(results.SchemaTitlesResult)
+		fmt.Println()
+	}
+	if results.ConsistencyResult != nil {
+		printDryRunStepHeader(&step, "Component consistency changes")
+		printConsistencyResults(results.ConsistencyResult)
+		fmt.Println()
+	}
+	if results.ExamplesResult != nil {
+		printDryRunStepHeader(&step, "Example repairs")
+		printExamplesResults(results.ExamplesResult)
+		fmt.Println()
+	}
+	if results.CanonicalizeResult != nil {
+		printDryRunStepHeader(&step, "Canonical ordering changes")
+		printCanonicalizeResults(results.CanonicalizeResult)
+		fmt.Println()
+	}
+	if results.LinkResult != nil && results.LinkResult.Changed {
+		printDryRunStepHeader(&step, "Link sync")
+		printLinkSyncResults(results.LinkResult)
+		fmt.Println()
+	}
+	if results.ArazzoResult != nil {
+		printDryRunStepHeader(&step, "Arazzo workflow sync")
+		printArazzoResults(results.ArazzoResult)
+		fmt.Println()
+	}
+	printChangeLocations(results.AllLocations())
+	printFileMetrics(results.FileMetrics)
+	printSkippedFiles(results.SkippedFiles)
+	printSkipReasons(results.SkipGroups())
+	printProtectedSkips(results.ProtectedSkips)
+	printUnresolvedRefs(results.PaginationResult)
+	printDanglingRefs(results.DanglingRefs)
+	printEmptySchemas(results.EmptySchemas)
+
+	printDryRunStepHeader(&step, "Validation")
+}
+
+// Link sync results printing
+func printLinkSyncResults(linkResult *transform.LinkSyncResult) {
+	printHeader("Link Sync Results", "🔗")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(linkResult.ProcessedFiles), colorReset)
+
+	if len(linkResult.UpdatedLinks) > 0 {
+		fmt.Printf("\n✅ %sUpdated Links%s\n", colorGreen, colorReset)
+		for file, updates := range linkResult.UpdatedLinks {
+			printFileHeader(file)
+			for _, update := range updates {
+				printListItem(update, colorGreen)
+			}
+		}
+	}
+	if len(linkResult.RemovedLinks) > 0 {
+		fmt.Printf("\n🗑️  %sRemoved Links%s\n", colorYellow, colorReset)
+		for file, removals := range linkResult.RemovedLinks {
+			printFileHeader(file)
+			for _, removal := range removals {
+				printListItem(removal, colorYellow)
+			}
+		}
+	}
+	printSuccess("Links updated successfully")
+}
+
+// Arazzo workflow sync results printing
+func printArazzoResults(arazzoResult *transform.ArazzoResult) {
+	fmt.Printf("🔗 %sArazzo documents detected:%s %s%d%s (OpenAPI-only steps skipped)\n",
+		colorCyan, colorReset, colorGreen, len(arazzoResult.Documents), colorReset)
+	if !arazzoResult.Changed {
+		printInfo("No Arazzo workflow references needed updating")
+		return
+	}
+
+	printHeader("Arazzo Workflow Sync Results", "🔗")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(arazzoResult.ProcessedFiles), colorReset)
+
+	fmt.Printf("\n✅ %sUpdated Step References%s\n", colorGreen, colorReset)
+	for file, updates := range arazzoResult.UpdatedReferences {
+		printFileHeader(file)
+		for _, update := range updates {
+			printListItem(update, colorGreen)
+		}
+	}
+	printSuccess("Arazzo workflows updated successfully")
+}
+
+// Parameter injection results printing
+func printInjectionResults(injectionResult *transform.InjectionResult) {
+	if !injectionResult.Changed {
+		printInfo("No parameters needed injecting")
+		return
+	}
+
+	printHeader("Parameter Injection Results", "💉")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(injectionResult.ProcessedFiles), colorReset)
+
+	if len(injectionResult.CreatedComponents) > 0 {
+		fmt.Printf("\n🧩 %sCreated Components%s\n", colorCyan, colorReset)
+		for file, components := range injectionResult.CreatedComponents {
+			printFileHeader(file)
+			for _, component := range components {
+				printListItem(component, colorCyan)
+			}
+		}
+	}
+
+	fmt.Printf("\n✅ %sInjected Parameters%s\n", colorGreen, colorReset)
+	for file, params := range injectionResult.InjectedParams {
+		printFileHeader(file)
+		for _, param := range params {
+			printListItem(param, colorGreen)
+		}
+	}
+	printSuccess("Parameters injected successfully")
+}
+
+// Internal content stripping results printing
+func printInternalResults(internalResult *transform.InternalResult) {
+	if !internalResult.Changed {
+		printInfo("No internal-only content found")
+		return
+	}
+
+	printHeader("Internal Content Stripping Results", "🔒")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(internalResult.ProcessedFiles), colorReset)
+
+	fmt.Printf("\n✅ %sRemoved Internal Content%s\n", colorGreen, colorReset)
+	for file, items := range internalResult.RemovedItems {
+		printFileHeader(file)
+		for _, item := range items {
+			printListItem(item, colorGreen)
+		}
+	}
+
+	if len(internalResult.PrunedComponents) > 0 {
+		fmt.Printf("\n🗑️  %sPruned Unused Components%s\n", colorYellow, colorReset)
+		for file, components := range internalResult.PrunedComponents {
+			printFileHeader(file)
+			for _, component := range components {
+				printListItem(component, colorYellow)
+			}
+		}
+	}
+
+	if len(internalResult.SecondaryRemovals) > 0 {
+		fmt.Printf("\n🔗 %sRemoved Dangling References%s\n", colorYellow, colorReset)
+		for file, items := range internalResult.SecondaryRemovals {
+			printFileHeader(file)
+			for _, item := range items {
+				printListItem(item, colorYellow)
+			}
+		}
+	}
+	printSuccess("Internal-only content removed successfully")
+}
+
+// Response envelope unwrapping results printing
+func printEnvelopeResults(envelopeResult *transform.EnvelopeResult) {
+	if !envelopeResult.Changed {
+		printInfo("No response envelopes found")
+		return
+	}
+
+	printHeader("Response Envelope Results", "📭")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(envelopeResult.ProcessedFiles), colorReset)
+
+	fmt.Printf("\n✅ %sUnwrapped Responses%s\n", colorGreen, colorReset)
+	for file, responses := range envelopeResult.UnwrappedResponses {
+		printFileHeader(file)
+		for _, response := range responses {
+			printListItem(response, colorGreen)
+		}
+	}
+
+	if len(envelopeResult.RemovedComponents) > 0 {
+		fmt.Printf("\n🗑️  %sRemoved Envelope Components%s\n", colorYellow, colorReset)
+		for file, components := range envelopeResult.RemovedComponents {
+			printFileHeader(file)
+			for _, component := range components {
+				printListItem(component, colorYellow)
+			}
+		}
+	}
+	printSuccess("Response envelopes unwrapped successfully")
+}
+
+// Schema constraint normalization results printing
+func printConstraintsResults(constraintsResult *transform.ConstraintsResult) {
+	if !constraintsResult.Changed {
+		printInfo("No schema constraints needed normalizing")
+		return
+	}
+
+	printHeader("Schema Constraint Results", "📏")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(constraintsResult.ProcessedFiles), colorReset)
+
+	fmt.Printf("\n✅ %sNormalized Constraints%s\n", colorGreen, colorReset)
+	for file, changes := range constraintsResult.NormalizedConstraints {
+		printFileHeader(file)
+		for _, change := range changes {
+			printListItem(change, colorGreen)
+		}
+	}
+	printSuccess("Schema constraints normalized successfully")
+}
+
+// Extension value replacement results printing
+func printExtensionValuesResults(valuesResult *transform.ExtensionValuesResult) {
+	if !valuesResult.Changed {
+		printInfo("No extension values needed replacing")
+		return
+	}
+
+	printHeader("Extension Value Results", "🏷️")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(valuesResult.ProcessedFiles), colorReset)
+
+	fmt.Printf("\n✅ %sReplaced Values%s\n", colorGreen, colorReset)
+	for file, changes := range valuesResult.ReplacedValues {
+		printFileHeader(file)
+		for _, change := range changes {
+			printListItem(change, colorGreen)
+		}
+	}
+	printSuccess("Extension values replaced successfully")
+}
+
+// Nullability policy results printing
+func printNullabilityResults(nullabilityResult *transform.NullabilityResult) {
+	if !nullabilityResult.Changed {
+		printInfo("No schemas needed nullability changes")
+		return
+	}
+
+	printHeader("Nullability Policy Results", "∅")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(nullabilityResult.ProcessedFiles), colorReset)
+
+	fmt.Printf("\n✅ %sNullability Changes%s\n", colorGreen, colorReset)
+	for file, changes := range nullabilityResult.NullabilityChanges {
+		printFileHeader(file)
+		for _, change := range changes {
+			printListItem(change, colorGreen)
+		}
+	}
+	printSuccess("Nullability policy enforced successfully")
+}
+
+// Example repair results printing
+func printExamplesResults(examplesResult *transform.ExamplesResult) {
+	if !examplesResult.Changed {
+		printInfo("All examples match their schemas")
+		return
+	}
+
+	printHeader("Example Repair Results", "🧪")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(examplesResult.ProcessedFiles), colorReset)
+
+	fmt.Printf("\n✅ %sRepaired Examples%s\n", colorGreen, colorReset)
+	for file, repairs := range examplesResult.RepairedExamples {
+		printFileHeader(file)
+		for _, repair := range repairs {
+			printListItem(repair, colorGreen)
+		}
+	}
+	printSuccess("Examples repaired successfully")
+}
+
+// Path variant results printing
+func printPathVariantsResults(variantsResult *transform.PathVariantsResult) {
+	if !variantsResult.Changed {
+		printInfo("No operations duplicated under path variants")
+		return
+	}
+
+	printHeader("Path Variant Results", "🔀")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(variantsResult.ProcessedFiles), colorReset)
+
+	fmt.Printf("\n✅ %sMerged Paths%s\n", colorGreen, colorReset)
+	for file, merges := range variantsResult.MergedPaths {
+		printFileHeader(file)
+		for _, merge := range merges {
+			printListItem(merge, colorGreen)
+		}
+	}
+	printSuccess("Path variants merged successfully")
+}
+
+// Tenant path results printing
+func printTenantPathsResults(tenantResult *transform.TenantPathsResult) {
+	if !tenantResult.Changed {
+		printInfo("No paths to rewrite for the tenant segment")
+		return
+	}
+
+	printHeader("Tenant Path Results", "🏢")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(tenantResult.ProcessedFiles), colorReset)
+
+	fmt.Printf("\n✅ %sRewritten Paths%s\n", colorGreen, colorReset)
+	for file, rewrites := range tenantResult.RewrittenPaths {
+		printFileHeader(file)
+		for _, rewrite := range rewrites {
+			printListItem(rewrite, colorGreen)
+		}
+	}
+	if len(tenantResult.ChangedComponents) > 0 {
+		fmt.Printf("\n🧩 %sParameter Components%s\n", colorBlue, colorReset)
+		for file, components := range tenantResult.ChangedComponents {
+			printFileHeader(file)
+			for _, component := range components {
+				printListItem(component, colorBlue)
+			}
+		}
+	}
+	printSuccess("Tenant paths rewritten successfully")
+}
+
+// Schema title results printing
+func printSchemaTitlesResults(titlesResult *transform.SchemaTitlesResult) {
+	if !titlesResult.Changed {
+		printInfo("Every schema already has a title in the configured casing")
+		return
+	}
+
+	printHeader("Schema Title Results", "🏷️")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(titlesResult.ProcessedFiles), colorReset)
+
+	if len(titlesResult.AddedTitles) > 0 {
+		fmt.Printf("\n✅ %sAdded Titles%s\n", colorGreen, colorReset)
+		for file, titles := range titlesResult.AddedTitles {
+			printFileHeader(file)
+			for _, title := range titles {
+				printListItem(title, colorGreen)
+			}
+		}
+	}
+	if len(titlesResult.NormalizedTitles) > 0 {
+		fmt.Printf("\n✏️  %sNormalized Titles%s\n", colorBlue, colorReset)
+		for file, titles := range titlesResult.NormalizedTitles {
+			printFileHeader(file)
+			for _, title := range titles {
+				printListItem(title, colorBlue)
+			}
+		}
+	}
+	printSuccess("Schema titles updated successfully")
+}
+
+// Canonicalization results printing
+func printCanonicalizeResults(canonicalizeResult *transform.CanonicalizeResult) {
+	if !canonicalizeResult.Changed {
+		printInfo("Documents are already in canonical order")
+		return
+	}
+
+	printHeader("Canonicalization Results", "🔤")
+	fmt.Printf("📄 %sProcessed files:%s %s%d%s\n",
+		colorCyan, colorReset, colorGreen, len(canonicalizeResult.ProcessedFiles), colorReset)
+
+	fmt.Printf("\n✅ %sSorted Sections%s\n", colorGreen, colorReset)
+	for file, sections := range canonicalizeResult.SortedSections {
+		printFileHeader(file)
+		for _, section := range sections {
+			printListItem(section, colorGreen)
+		}
+	}
+	printSuccess("Documents canonicalized successfully")
+}
+
+// Merge results printing
+func printMergeResults(result *transform.MergeResult, outputPath string) {
+	printHeader("Spec Merge Results", "🔗")
+	fmt.Printf("📄 %sInputs:%s %s%v%s\n", colorCyan, colorReset, colorGreen, result.Inputs, colorReset)
+	fmt.Printf("📄 %sOutput file:%s %s%s%s\n", colorCyan, colorReset, colorGreen, outputPath, colorReset)
+	fmt.Printf("✅ %sMerged:%s %s%d%s paths, %s%d%s components, %d tags, %d servers\n",
+		colorGreen, colorReset, colorBold, result.Paths, colorReset, colorBold, result.Components, colorReset,
+		result.Tags, result.Servers)
+
+	if result.DuplicateIdentical > 0 {
+		printInfo(fmt.Sprintf("%d identical components/operations merged", result.DuplicateIdentical))
+	}
+	if len(result.RenamedComponents) > 0 {
+		fmt.Printf("\n✏️  %sRenamed Conflicting Components%s\n", colorYellow, colorReset)
+		for _, rename := range result.RenamedComponents {
+			printListItem(rename, colorYellow)
+		}
+	}
+	if len(result.SkippedComponents) > 0 || len(result.SkippedOperations) > 0 {
+		fmt.Printf("\n⏭️  %sKept First Definition For%s\n", colorYellow, colorReset)
+		for _, skipped := range result.SkippedComponents {
+			printListItem(skipped, colorYellow)
+		}
+		for _, skipped := range result.SkippedOperations {
+			printListItem(skipped, colorYellow)
+		}
+	}
+	printSuccess("Specs merged successfully")
+}
+
+// Output variants results printing
+func printVariantsResults(results *transform.VariantsResults, dryRun bool) {
+	printHeader("Shared Steps", "🧱")
+	printPipelineResults(results.Shared)
+
+	for _, variant := range results.Variants {
+		printHeader(fmt.Sprintf("Output: %s (%s)", variant.Dir, variant.Profile), "📦")
+		printPipelineResults(variant.Results)
+	}
+
+	printHeader("Output Summary", "📊")
+	for _, variant := range results.Variants {
+		status := "written"
+		if dryRun {
+			status = "dry-run, not written"
+		}
+		fmt.Printf("   📦 %s%-12s%s → %s%s%s (%s)\n",
+			colorCyan, variant.Profile, colorReset, colorGreen, variant.Dir, colorReset, status)
+	}
+}
+
+// Batch results printing
+func printBatchResults(results *transform.BatchResults, dryRun bool) {
+	for _, job := range results.Jobs {
+		if job.Results == nil {
+			continue
+		}
+		printHeader(fmt.Sprintf("Job: %s", job.Job.Name), "📦")
+		printPipelineResults(job.Results)
+	}
+
+	printHeader("Batch Summary", "📊")
+	for _, job := range results.Jobs {
+		statusColor := colorGreen
+		detail := job.Output
+		switch job.Status() {
+		case transform.BatchStatusFailed:
+			statusColor = colorRed
+			detail = job.Err.Error()
+		case transform.BatchStatusCached:
+			statusColor = colorCyan
+			detail = fmt.Sprintf("%s (reused output of %s)", job.Output, job.CachedFrom)
+		case transform.BatchStatusUnchanged:
+			statusColor = colorYellow
+		}
+		fmt.Printf("   📦 %s%-20s%s %s%-11s%s %s\n",
+			colorCyan, job.Job.Name, colorReset, statusColor, job.Status(), colorReset, detail)
+	}
+	if dryRun {
+		printInfo("Dry-run: no outputs were written")
+	}
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+var (
+	endpointRuleFlags []string
+	defaultFlags      []string
+)
+
+// applyRuleFlags merges --endpoint-rule and --default into the config, exiting on a malformed one
+func applyRuleFlags(cfg *config.Config) {
+	if err := config.ApplyRuleOverrides(cfg, endpointRuleFlags, defaultFlags); err != nil {
+		fmt.Fprintln(os.Stderr, "Config error:", err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringArrayVar(&endpointRuleFlags, "endpoint-rule", nil, "Endpoint pagination rule (METHOD /path=strategy, e.g. 'GET /users=cursor'), repeatable; wins over endpoint_pagination")
+	rootCmd.PersistentFlags().StringArrayVar(&defaultFlags, "default", nil, "Default value rule (location[:type]:name=value, e.g. 'query:int:limit=50'), repeatable; enables default_values")
+}
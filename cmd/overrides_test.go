@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_RuleFlags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: offset
+          in: query
+          schema:
+            type: integer
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: Success
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", tempDir, "--no-config",
+		"--pagination-priority", "cursor,offset",
+		"--endpoint-rule", "GET /users=offset",
+		"--default", "query:int:limit=50")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("transform failed: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if strings.Contains(content, "name: cursor") || !strings.Contains(content, "name: offset") {
+		t.Errorf("expected the endpoint rule to keep offset pagination, got:\n%s", content)
+	}
+	if !strings.Contains(content, "default: 50") {
+		t.Errorf("expected the limit default, got:\n%s", content)
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "--input", tempDir, "--no-config", "--default", "body:limit=50")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err = cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(out), `unknown location "body"`) {
+		t.Errorf("expected a config error for the location, got %v:\n%s", err, out)
+	}
+}
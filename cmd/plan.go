@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/report"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+var dryRunFormat string
+
+// planOutput receives the --format json plan: the real stdout, kept free of everything else
+var planOutput io.Writer
+
+// setupPlanFormat checks --format and, for json, sends everything printed to stderr so the plan is
+// the only thing on stdout
+func setupPlanFormat(cfg *config.Config) {
+	switch dryRunFormat {
+	case "text":
+		return
+	case "json":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid --format %q (expected text or json)\n", dryRunFormat)
+		os.Exit(1)
+	}
+	if !dryRun {
+		fmt.Fprintln(os.Stderr, "Error: --format json requires --dry-run")
+		os.Exit(1)
+	}
+	if interactive || vendorDryRun || vendorOnly || len(cfg.Outputs) > 0 {
+		fmt.Fprintln(os.Stderr, "Error: --format json cannot be combined with --interactive, --vendor-dry-run, --vendor-only or 'outputs'")
+		os.Exit(1)
+	}
+	planOutput = os.Stdout
+	os.Stdout = os.Stderr
+}
+
+// writeDryRunPlan writes the plan of a dry run to stdout when --format json is set
+func writeDryRunPlan(results *transform.TransformationResults) {
+	if planOutput == nil {
+		return
+	}
+	if err := report.WritePlan(planOutput, results, GetVersion()); err != nil {
+		fmt.Fprintln(os.Stderr, "Report error:", err)
+		os.Exit(2)
+	}
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&dryRunFormat, "format", "text", "Output format of --dry-run: text, or json for a machine-readable plan on stdout")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCLI_DryRunJSONPlan(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: true
+      responses:
+        "200":
+          description: Success
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", tempDir, "--no-config", "--map", "x-foo=x-bar", "--dry-run", "--format", "json")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+
+	var plan struct {
+		Changes int `json:"changes"`
+		Files   []struct {
+			File  string `json:"file"`
+			Steps []struct {
+				Step    string `json:"step"`
+				Changes []struct {
+					Line    int    `json:"line"`
+					Message string `json:"message"`
+				} `json:"changes"`
+			} `json:"steps"`
+		} `json:"files"`
+		Metrics []struct {
+			File        string `json:"file"`
+			Step        string `json:"step"`
+			BytesBefore int64  `json:"bytes_before"`
+			SizeDelta   int64  `json:"size_delta"`
+		} `json:"metrics"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &plan); err != nil {
+		t.Fatalf("expected only the JSON plan on stdout: %v\n%s", err, stdout.String())
+	}
+	if plan.Changes != 1 || len(plan.Files) != 1 || plan.Files[0].File != inputFile {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	step := plan.Files[0].Steps[0]
+	if step.Step != "mappings" || step.Changes[0].Line != 8 || step.Changes[0].Message != "x-foo -> x-bar" {
+		t.Errorf("unexpected step %+v", step)
+	}
+	if len(plan.Metrics) != 1 || plan.Metrics[0].Step != "mappings" || plan.Metrics[0].BytesBefore != int64(len(input)) || plan.Metrics[0].SizeDelta <= 0 {
+		t.Errorf("expected the size the mappings step would write, got %+v", plan.Metrics)
+	}
+	if data, _ := os.ReadFile(inputFile); string(data) != input {
+		t.Error("expected the dry run to leave the input alone")
+	}
+
+	out, err := exec.Command("go", "run", "../main.go", "--input", tempDir, "--no-config", "--format", "json").CombinedOutput()
+	if err == nil || !bytes.Contains(out, []byte("--format json requires --dry-run")) {
+		t.Errorf("expected --format json without --dry-run to fail, got %v:\n%s", err, out)
+	}
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/progress"
+)
+
+var noProgress bool
+
+// newProgressReporter returns the reporter showing how far the pipeline is on stderr: a status
+// line on a terminal, periodic log lines otherwise. It returns nil with --no-progress, or when
+// --trace-file - already writes spans to stderr.
+func newProgressReporter() *progress.Reporter {
+	if noProgress || traceFile == "-" {
+		return nil
+	}
+	return progress.New(os.Stderr, progress.IsTerminal(os.Stderr))
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "Hide the progress of long runs (a status line on terminals, a log line every few seconds otherwise)")
+}
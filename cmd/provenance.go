@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var provenanceCmd = &cobra.Command{
+	Use:   "provenance [path]",
+	Short: "Show which pipeline produced each document and whether it was edited since",
+	Long: `List the provenance stamp of every OpenAPI document under path: the OpenMorph version, the hash
+of the config and the time of the run that last changed it. Stamps are written by runs with
+provenance.enabled, under an x-openmorph root key or in a <file>.provenance sidecar.
+
+Each stamp carries a checksum of the document as the run wrote it. A document whose contents no
+longer match, because it was edited by hand since, is reported as edited; formatting, comments
+and key order don't count. provenance exits with status 1 when any document was edited.`,
+	Example: `  openmorph provenance specs/
+  openmorph provenance --input api.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		inputPath := inputDir
+		if len(args) == 1 {
+			inputPath = args[0]
+		}
+		cfg, err := config.LoadConfig(configFile, inlineMaps, inputPath, "", noConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+
+		checks, err := transform.CheckProvenance(cfg.Input, cfg.Files)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Provenance error:", err)
+			os.Exit(2)
+		}
+
+		edited := 0
+		for _, check := range checks {
+			if check.Stamp == nil {
+				fmt.Printf("%s: %snot stamped%s\n", check.File, colorYellow, colorReset)
+				continue
+			}
+			status := colorGreen + "unchanged" + colorReset
+			if check.Edited {
+				status = colorRed + "edited since stamped" + colorReset
+				edited++
+			}
+			fmt.Printf("%s: %s %s, config %s, generated %s, %s\n", check.File, check.Stamp.Tool, check.Stamp.Version,
+				shortHash(check.Stamp.Config), check.Stamp.Generated, status)
+		}
+		if edited > 0 {
+			fmt.Fprintf(os.Stderr, "%s❌ %d documents edited since they were stamped%s\n", colorRed, edited, colorReset)
+			os.Exit(1)
+		}
+	},
+}
+
+// shortHash abbreviates a "sha256:<hex>" hash for display
+func shortHash(hash string) string {
+	if digest, ok := strings.CutPrefix(hash, "sha256:"); ok && len(digest) > 12 {
+		return "sha256:" + digest[:12]
+	}
+	return hash
+}
+
+func init() {
+	rootCmd.AddCommand(provenanceCmd)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_Provenance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: true
+      responses:
+        "200":
+          description: Success
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+	if err := os.WriteFile(configFile, []byte("mappings:\n  x-foo: x-bar\nprovenance:\n  enabled: true\n"), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	out, err := exec.Command("go", "run", "../main.go", "--input", tempDir, "--config", configFile).CombinedOutput()
+	if err != nil {
+		t.Fatalf("transform failed: %v\n%s", err, out)
+	}
+
+	out, err = exec.Command("go", "run", "../main.go", "provenance", "--no-config", inputFile).CombinedOutput()
+	if err != nil {
+		t.Fatalf("expected the stamped document to check out: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), inputFile+": openmorph ") || !strings.Contains(string(out), "unchanged") {
+		t.Errorf("expected the stamp to be listed as unchanged, got:\n%s", out)
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data), "description: Success", "description: Edited", 1)
+	if err := os.WriteFile(inputFile, []byte(edited), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, err = exec.Command("go", "run", "../main.go", "provenance", "--no-config", inputFile).CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "edited since stamped") {
+		t.Errorf("expected provenance to fail on the edited document, got %v:\n%s", err, out)
+	}
+}
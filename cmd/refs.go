@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/report"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var refsCmd = &cobra.Command{
+	Use:   "refs [path]",
+	Short: "Check that every $ref resolves",
+	Long: `Check that every $ref in the OpenAPI and AsyncAPI documents under path points at an existing
+node, in the same file or in another file, and report each dangling reference with its location.
+Remote (URL) references are not checked. This is much faster than a full OpenAPI validation.
+
+Transformation runs perform the same check after every step that removes, renames or replaces
+content and print the references it left dangling as warnings, unless --no-ref-check is set.
+refs exits with status 1 when any reference is dangling.`,
+	Example: `  openmorph refs specs/
+  openmorph refs --input api.yaml --annotations github`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		checkAnnotationsFormat()
+		inputPath := inputDir
+		if len(args) == 1 {
+			inputPath = args[0]
+		}
+		cfg, err := config.LoadConfig(configFile, inlineMaps, inputPath, "", noConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		inputPath = cfg.Input
+
+		issues, err := transform.CheckRefs(inputPath, cfg.Files)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Ref check error:", err)
+			os.Exit(2)
+		}
+		if len(issues) == 0 {
+			fmt.Printf("%s✅ All $refs resolve%s\n", colorGreen, colorReset)
+			return
+		}
+
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", issue.Position(), issue.Message)
+		}
+		if annotationsFormat != "" {
+			printAnnotations(report.RefAnnotations(issues, report.AnnotationError))
+		}
+		fmt.Fprintf(os.Stderr, "%s❌ %d dangling $refs%s\n", colorRed, len(issues), colorReset)
+		os.Exit(1)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(refsCmd)
+}
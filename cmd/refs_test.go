@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_Refs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "refs", "--no-config", inputFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected refs to fail, got:\n%s", out)
+	}
+	want := inputFile + `:14:23: unresolved $ref #/components/schemas/User`
+	if !strings.Contains(string(out), want) {
+		t.Errorf("expected output to contain %q, got:\n%s", want, out)
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+// exitRequiredSkips is the exit status of a run in which a required step skipped items
+const exitRequiredSkips = 7
+
+var failOnSkipped bool
+
+// exitOnRequiredSkips lists the items required steps skipped for a reason in their fail_on, passes
+// the failure to notifyFailure, if any, then exits with exitRequiredSkips. It returns when there
+// are none.
+func exitOnRequiredSkips(cfg *config.Config, notifyFailure func(error), results ...*transform.TransformationResults) {
+	skips := transform.RequiredSkips(cfg, results...)
+	if len(skips) == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "\n%s❌ %d item(s) skipped by required steps:%s\n", colorRed, len(skips), colorReset)
+	for _, skip := range skips {
+		fmt.Fprintf(os.Stderr, "   [%s] %s: %s (%s)\n", skip.Step, skip.File, skip.Message, skip.Code)
+	}
+	if notifyFailure != nil {
+		notifyFailure(fmt.Errorf("%d item(s) skipped by required steps", len(skips)))
+	}
+	os.Exit(exitRequiredSkips)
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&failOnSkipped, "fail-on-skipped", false, "Mark vendor_extensions and default_values as required: exit with status 7 when they skip items for a reason in their fail_on")
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_FailOnSkipped(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+      responses:
+        "200":
+          description: Success
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `vendor_extensions:
+  enabled: true
+  providers:
+    fern:
+      extension_name: x-fern-pagination
+      field_mapping:
+        request_params:
+          cursor: [cursor]
+      strategies:
+        cursor:
+          template:
+            cursor: $request.{cursor_param}
+            results: $response.{results_field}
+          required_fields: [cursor_param, results_field]
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", tempDir, "--config", configFile, "--dry-run")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("expected the skip to pass without --fail-on-skipped: %v\n%s", err, out)
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "--input", tempDir, "--config", configFile, "--dry-run", "--fail-on-skipped")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected the run to fail, got %v:\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "1 item(s) skipped by required steps") ||
+		!strings.Contains(string(out), "GET /users: missing required fields results_field for fern (cursor strategy) (missing_required_fields)") {
+		t.Errorf("expected the skipped operation to be listed, got:\n%s", out)
+	}
+
+	// Outside a dry run, the webhooks that post on failure hear about it before the exit
+	payloads := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payloads <- string(body)
+	}))
+	defer server.Close()
+	notifyConfig := configContent + fmt.Sprintf("notify:\n  webhooks:\n    - url: %s\n      on: failure\n", server.URL)
+	if err := os.WriteFile(configFile, []byte(notifyConfig), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	cmd = exec.Command("go", "run", "../main.go", "--input", tempDir, "--config", configFile, "--fail-on-skipped")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err = cmd.CombinedOutput()
+	if !errors.As(err, &exitErr) || !strings.Contains(string(out), "exit status 7") {
+		t.Fatalf("expected the run to exit with status 7, got %v:\n%s", err, out)
+	}
+	select {
+	case payload := <-payloads:
+		for _, want := range []string{`"status":"failed"`, `"error":"1 item(s) skipped by required steps"`} {
+			if !strings.Contains(payload, want) {
+				t.Errorf("expected payload to contain %s: %s", want, payload)
+			}
+		}
+	default:
+		t.Fatalf("expected a webhook request\n%s", out)
+	}
+}
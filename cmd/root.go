@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/notify"
+	"github.com/developerkunal/OpenMorph/internal/report"
 	"github.com/developerkunal/OpenMorph/internal/transform"
 	"github.com/developerkunal/OpenMorph/internal/tui"
 
@@ -26,14 +29,31 @@ var (
 	noConfig              bool
 	interactive           bool
 	paginationPriorityStr string
+	onlyPaths             []string
 	flattenResponses      bool
 	verbose               bool
+	explainSkips          bool
 
 	// Vendor extension flags
 	vendorProviders []string
+	vendorDryRun    bool
+	vendorOnly      bool
 
 	// Default values flags
 	setDefaults bool
+
+	// Internal content stripping flags
+	stripInternal bool
+
+	// Report flags
+	sarifFile         string
+	annotationsFormat string
+
+	// Strict mode flags
+	strict bool
+
+	// Ref check flags
+	noRefCheck bool
 )
 
 var rootCmd = &cobra.Command{
@@ -41,16 +61,156 @@ var rootCmd = &cobra.Command{
 	Short:   "Transform OpenAPI vendor extension keys via mapping",
 	Long:    `OpenMorph: Transform OpenAPI vendor extension keys in YAML/JSON files via mapping config or inline args. Features vendor extensions, default values, response flattening, and more.`,
 	Version: GetVersion(),
+	PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+		if cmd != updateCmd {
+			checkForNewerVersion()
+		}
+	},
 	Run: func(cmd *cobra.Command, _ []string) {
 		if cmd.Flag("version") != nil && cmd.Flag("version").Changed {
 			fmt.Println("OpenMorph version:", GetVersion())
 			return
 		}
+		checkAnnotationsFormat()
+		setupTelemetry()
 		cfg, err := config.LoadConfig(configFile, inlineMaps, inputDir, outputFile, noConfig)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, "Config error:", err)
 			os.Exit(1)
 		}
+		applyRuleFlags(cfg)
+		if err := notify.Validate(cfg.Notify); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateFileFilter(cfg.Files); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidatePathVariants(cfg.PathVariants); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateTenantPaths(cfg.TenantPaths); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateUnwrapEnvelopes(cfg.UnwrapEnvelopes); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateParameterInjection(cfg.ParameterInjection); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateMappings(cfg.Mappings); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateBackups(cfg.Backups); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateProtect(cfg.Protect); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateMappingCollisions(cfg.MappingCollisions); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateSharedFields(cfg.SharedFields); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidatePaginationComponents(cfg.PageComponents); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidatePaginationMetadata(cfg.PaginationMetadata); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidatePaginationHeaders(cfg.PaginationHeaders); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidatePaginationCleanup(cfg.PaginationCleanup); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateSchemaConstraints(cfg.SchemaConstraints); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateProviderModes(cfg.VendorExtensions); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateTemplateFilters(cfg.VendorExtensions); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateComponentNaming(cfg.ComponentNaming); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateFlattenAllOf(cfg.FlattenAllOf); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateFlattenMetadata(cfg.FlattenMetadata); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateComponentConsistency(cfg.Consistency); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateExamples(cfg.Examples); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateNullability(cfg.Nullability); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateExtensionValues(cfg.ExtensionValues); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateExtensionPlacement(cfg.ExtensionPlacement); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateOutputFormats(cfg.OutputFormats); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateSchemaTitles(cfg.SchemaTitles); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateCanonicalize(cfg.Canonicalize); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateProvenance(cfg.Provenance); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateExtensionSchemas(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateConditions(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		if err := transform.ValidateRequiredSkips(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
 
 		var actualInputPath string
 		if inputDir != "" {
@@ -92,34 +252,52 @@ var rootCmd = &cobra.Command{
 				os.Exit(1)
 			}
 		}
-		// Merge CLI --exclude, --validate, --backup, and --flatten-responses with config
-		if len(exclude) > 0 {
-			cfg.Exclude = append(cfg.Exclude, exclude...)
-		}
-		if validate {
-			cfg.Validate = true
+		applyFlagOverrides(cmd, cfg)
+		if err := transform.ValidatePaths(cfg.OnlyPaths); err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
 		}
-		if cmd.Flag("backup") != nil && cmd.Flag("backup").Changed {
-			cfg.Backup = backup
+
+		// Multiple output variants write to their own directories
+		if len(cfg.Outputs) > 0 && (actualOutputFile != "" || interactive) {
+			fmt.Fprintln(os.Stderr, "Error: 'outputs' cannot be combined with --output or --interactive")
+			os.Exit(1)
 		}
-		if cmd.Flag("flatten-responses") != nil && cmd.Flag("flatten-responses").Changed {
-			cfg.FlattenResponses = flattenResponses
+		if (vendorDryRun || vendorOnly) && (interactive || len(cfg.Outputs) > 0) {
+			fmt.Fprintln(os.Stderr, "Error: --vendor-dry-run and --vendor-only cannot be combined with --interactive or 'outputs'")
+			os.Exit(1)
 		}
-		if cmd.Flag("set-defaults") != nil && cmd.Flag("set-defaults").Changed {
-			cfg.DefaultValues.Enabled = setDefaults
+		if recordDecisions && (vendorDryRun || vendorOnly || len(cfg.Outputs) > 0) {
+			fmt.Fprintln(os.Stderr, "Error: --record-decisions cannot be combined with --vendor-dry-run, --vendor-only or 'outputs'")
+			os.Exit(1)
 		}
-		if paginationPriorityStr != "" {
-			// Parse comma-separated pagination priority
-			priorities := strings.Split(paginationPriorityStr, ",")
-			for i, p := range priorities {
-				priorities[i] = strings.TrimSpace(p)
-			}
-			cfg.PaginationPriority = priorities
+
+		budget := changeBudget(cmd, cfg)
+		if budget < 0 {
+			fmt.Fprintln(os.Stderr, "Config error: max_changed_operations must not be negative")
+			os.Exit(1)
 		}
 
+		setupPlanFormat(cfg)
+
+		// Settle the pagination strategy of tied operations before anything runs
+		resolvePaginationTies(cfg)
+
 		// Print config summary
 		printConfigSummary(cfg, vendorProviders, actualOutputFile)
 
+		// In strict mode, fail before anything is previewed or written
+		checkStrictMode(cfg, actualInputPath)
+
+		// Vendor extensions alone, independently of the rest of the pipeline
+		if vendorDryRun || vendorOnly {
+			if !vendorDryRun && !dryRun {
+				checkChangeBudget(budget, cfg, actualInputPath, actualOutputFile, (*transform.TransformationPipeline).ExecuteVendorExtensions)
+			}
+			runVendorExtensionsOnly(cfg, actualInputPath, actualOutputFile)
+			return
+		}
+
 		// If interactive flag is set, launch TUI for preview/approval BEFORE any transformation
 		if interactive {
 			// Collect key changes for each file (but do not transform yet)
@@ -131,7 +309,9 @@ var rootCmd = &cobra.Command{
 				if info.IsDir() {
 					return nil
 				}
-				if transform.IsYAML(path) || transform.IsJSON(path) {
+				if (transform.IsYAML(path) || transform.IsJSON(path)) &&
+					transform.IncludesFile(cfg.Files, transform.StepMappings, actualInputPath, path) &&
+					!transform.IsIgnoredFile(path) {
 					inputFiles = append(inputFiles, path)
 				}
 				return nil
@@ -140,10 +320,11 @@ var rootCmd = &cobra.Command{
 			for _, f := range inputFiles {
 				var changes []transform.KeyChange
 				_, _ = transform.FileWithChanges(f, transform.Options{
-					Mappings: cfg.Mappings,
-					Exclude:  cfg.Exclude,
-					DryRun:   true,
-					Backup:   false,
+					Mappings:   cfg.Mappings,
+					Exclude:    transform.ProtectedExclude(cfg),
+					DryRun:     true,
+					Backup:     false,
+					Collisions: cfg.MappingCollisions,
 				}, &changes)
 				if len(changes) > 0 {
 					fileKeyChanges[f] = changes
@@ -182,15 +363,18 @@ var rootCmd = &cobra.Command{
 				fmt.Fprintln(os.Stderr, "TUI error:", err)
 				os.Exit(4)
 			}
-			// Only transform accepted files
+			// Only transform accepted files, holding the lock until the whole run has finished
+			release := acquireLock(actualInputPath)
+			defer release()
 			var actuallyChanged []string
 			for _, f := range inputFiles {
 				if accepted[f] {
 					ok, err := transform.File(f, transform.Options{
-						Mappings: cfg.Mappings,
-						Exclude:  cfg.Exclude,
-						DryRun:   false,
-						Backup:   cfg.Backup,
+						Mappings:   cfg.Mappings,
+						Exclude:    transform.ProtectedExclude(cfg),
+						DryRun:     false,
+						Backup:     cfg.Backup,
+						Collisions: cfg.MappingCollisions,
 					})
 					if err != nil {
 						fmt.Fprintf(os.Stderr, "Transform error for %s: %v\n", f, err)
@@ -246,25 +430,21 @@ var rootCmd = &cobra.Command{
 
 				// Use unified pipeline for remaining transformations
 				pipeline := transform.NewTransformationPipeline(cfg, vendorProviders, false, cfg.Backup, "")
+				pipeline.Progress = newProgressReporter()
 				results, err := pipeline.ExecuteFullPipeline(cfg.Input)
+				flushTelemetry()
 				if err != nil {
 					fmt.Fprintln(os.Stderr, "Additional transformations error:", err)
+					release()
 					os.Exit(2)
 				}
 
 				// Print results for each transformation step
-				if results.PaginationResult != nil {
-					printPaginationResults(results.PaginationResult)
-				}
-				if results.FlattenResult != nil {
-					printFlattenResultsImproved(results.FlattenResult)
-				}
-				if results.VendorResult != nil {
-					printVendorExtensionResults(results.VendorResult)
-				}
-				if results.DefaultsResult != nil {
-					printDefaultsResults(results.DefaultsResult)
-				}
+				printPipelineResults(results)
+				recordPaginationDecisions(cfg, results, false)
+				writeSARIFReport(results.AllLocations())
+				writeAnnotations(results)
+				writeMetricsFile(results)
 			}
 
 			// Run validation if requested (for interactive mode)
@@ -272,6 +452,7 @@ var rootCmd = &cobra.Command{
 				fmt.Printf("\n🔍 %sValidating OpenAPI specifications...%s\n", colorCyan, colorReset)
 				if err := RunSwaggerValidate(cfg.Input); err != nil {
 					fmt.Fprintf(os.Stderr, "%s❌ Validation failed:%s %v\n", colorRed, colorReset, err)
+					release()
 					os.Exit(3)
 				}
 				fmt.Printf("%s✅ Validation passed successfully%s\n", colorGreen, colorReset)
@@ -281,6 +462,11 @@ var rootCmd = &cobra.Command{
 
 		// Non-interactive path: Use unified transformation pipeline
 
+		if len(cfg.Outputs) > 0 {
+			runOutputVariants(cfg, actualInputPath)
+			return
+		}
+
 		// In dry-run mode, skip the first execution and go directly to detailed preview
 		if dryRun {
 			fmt.Printf("\033[1;33m╭─────────────────────────────────────────────────────────────╮\033[0m\n")
@@ -290,51 +476,28 @@ var rootCmd = &cobra.Command{
 			fmt.Printf("\033[1;31m   In actual execution, steps are CUMULATIVE (each builds on the previous).\033[0m\n")
 			fmt.Printf("\033[1;31m   Flattening results will differ significantly in real execution!\033[0m\n\n")
 
-			// Use unified pipeline for dry-run preview
-			dryRunPipeline := transform.NewTransformationPipeline(cfg, vendorProviders, true, cfg.Backup, "")
+			// Use unified pipeline for dry-run preview, on a copy of the input when writing to an output file
+			dryRunPipeline := transform.NewTransformationPipeline(cfg, vendorProviders, true, cfg.Backup, actualOutputFile)
+			dryRunPipeline.Progress = newProgressReporter()
+			dryRunPipeline.KeepGoing = keepGoing
 			dryRunResults, err := dryRunPipeline.ExecuteFullPipeline(actualInputPath)
+			flushTelemetry()
 			if err != nil {
 				fmt.Fprintln(os.Stderr, "Dry-run preview error:", err)
 				os.Exit(2)
 			}
 
-			// Print results for each transformation step
-			if dryRunResults.PaginationResult != nil {
-				fmt.Printf("\033[1;36m[STEP 1] Pagination changes with priority: %v\033[0m\n", cfg.PaginationPriority)
-				printPaginationResults(dryRunResults.PaginationResult)
-				fmt.Println()
-			}
-			if dryRunResults.VendorResult != nil {
-				fmt.Printf("\033[1;36m[STEP 2] Vendor extensions changes\033[0m\n")
-				printVendorExtensionResults(dryRunResults.VendorResult)
-				fmt.Println()
-			}
-			if dryRunResults.DefaultsResult != nil {
-				stepNum := 2
-				if cfg.VendorExtensions.Enabled {
-					stepNum = 3
-				}
-				fmt.Printf("\033[1;36m[STEP %d] Default values changes\033[0m\n", stepNum)
-				printDefaultsResults(dryRunResults.DefaultsResult)
-				fmt.Println()
-			}
-			if dryRunResults.FlattenResult != nil {
-				stepNum := 2
-				if cfg.VendorExtensions.Enabled {
-					stepNum = 3
-				}
-				if cfg.DefaultValues.Enabled {
-					stepNum = 4
-				}
-				fmt.Printf("\033[1;36m[STEP %d] Response flattening changes\033[0m\n", stepNum)
-				fmt.Printf("\033[1;31m⚠️  CRITICAL: This preview operates on the ORIGINAL file.\033[0m\n")
-				fmt.Printf("\033[1;31m   Real execution will show SIGNIFICANTLY MORE changes\033[0m\n")
-				fmt.Printf("\033[1;31m   because pagination creates new schemas to flatten!\033[0m\n")
-				printFlattenResultsImproved(dryRunResults.FlattenResult)
-				fmt.Println()
-			}
+			writeDryRunPlan(dryRunResults)
 
-			fmt.Printf("\033[1;36m[STEP %d] Validation\033[0m\n", 5)
+			// Print results for each transformation step
+			printDryRunSteps(cfg, dryRunResults)
+			reportMappingUsage(cfg, actualInputPath, dryRunResults.KeyChanges)
+			recordPaginationDecisions(cfg, dryRunResults, true)
+			writeSARIFReport(dryRunResults.AllLocations())
+			writeAnnotations(dryRunResults)
+			writeMetricsFile(dryRunResults)
+			exitOnFileErrors(nil, dryRunResults) // dry runs send no notifications
+			exitOnRequiredSkips(cfg, nil, dryRunResults)
 			fmt.Printf("⏭️  %sSkipping validation in dry-run mode%s\n", colorYellow, colorReset)
 			fmt.Println()
 
@@ -359,15 +522,25 @@ var rootCmd = &cobra.Command{
 		}
 
 		pipeline := transform.NewTransformationPipeline(cfg, vendorProviders, false, cfg.Backup, actualOutputFile)
+		pipeline.Progress = newProgressReporter()
+		pipeline.KeepGoing = keepGoing
+		pipeline.Version = GetVersion()
 
 		if actualOutputFile != "" {
 			fmt.Printf("Input file: %s\n", actualInputPath)
 			fmt.Printf("Output file: %s\n", actualOutputFile)
 		}
 
+		// Stop a run that would change more operations than expected before it writes anything
+		checkChangeBudget(budget, cfg, actualInputPath, actualOutputFile, (*transform.TransformationPipeline).ExecuteFullPipeline)
+
+		release := acquireLock(actualInputPath)
 		results, transformErr := pipeline.ExecuteFullPipeline(actualInputPath)
+		release()
+		flushTelemetry()
 		if transformErr != nil {
 			fmt.Fprintln(os.Stderr, "Transform error:", transformErr)
+			sendNotifications(cfg.Notify, actualInputPath, notify.ValidationSkipped, transformErr)
 			os.Exit(2)
 		}
 
@@ -378,82 +551,295 @@ var rootCmd = &cobra.Command{
 				// Display detailed transformation results for single file output (same as directory mode)
 				if !dryRun {
 					// Print detailed results for each transformation step using the same functions as directory mode
-					if results.PaginationResult != nil {
-						printPaginationResults(results.PaginationResult)
-					}
-					if results.FlattenResult != nil {
-						printFlattenResultsImproved(results.FlattenResult)
-					}
-					if results.VendorResult != nil {
-						printVendorExtensionResults(results.VendorResult)
-					}
-					if results.DefaultsResult != nil {
-						printDefaultsResults(results.DefaultsResult)
-					}
+					printPipelineResults(results)
 				}
 			} else {
-				fmt.Printf("ℹ️  %sNo transformations needed%s\n", colorYellow, colorReset)
+				fmt.Printf("ℹ️  %sNo transformations needed, the output is a copy of the input%s\n", colorYellow, colorReset)
 			}
 		} else {
 			fmt.Printf("Files detected for transform: %v\n", results.Changed)
 			fmt.Printf("Transformed files: %v\n", results.Changed)
 
 			// Print results for directory processing
-			if results.PaginationResult != nil {
-				printPaginationResults(results.PaginationResult)
-			}
-			if results.FlattenResult != nil {
-				printFlattenResultsImproved(results.FlattenResult)
-			}
-			if results.VendorResult != nil {
-				printVendorExtensionResults(results.VendorResult)
-			}
-			if results.DefaultsResult != nil {
-				printDefaultsResults(results.DefaultsResult)
-			}
+			printPipelineResults(results)
+		}
+		reportMappingUsage(cfg, actualInputPath, results.KeyChanges)
+		recordPaginationDecisions(cfg, results, false)
+		writeSARIFReport(results.AllLocations())
+		writeAnnotations(results)
+		writeMetricsFile(results)
+		notifyFailure := func(err error) {
+			sendNotifications(cfg.Notify, actualInputPath, notify.ValidationSkipped, err, results)
+		}
+		exitOnFileErrors(notifyFailure, results)
+		exitOnRequiredSkips(cfg, notifyFailure, results)
+
+		validationPath := actualInputPath
+		if actualOutputFile != "" {
+			validationPath = actualOutputFile
 		}
+		warnInvalidExtensions(cfg, validationPath)
 
 		// Run validation if requested
 		if cfg.Validate && !dryRun {
 			fmt.Printf("\n🔍 %sValidating OpenAPI specifications...%s\n", colorCyan, colorReset)
-			var validationPath string
-			if actualOutputFile != "" {
-				validationPath = actualOutputFile
-			} else {
-				validationPath = actualInputPath
-			}
 			if validationErr := RunSwaggerValidate(validationPath); validationErr != nil {
 				fmt.Fprintf(os.Stderr, "%s❌ Validation failed:%s %v\n", colorRed, colorReset, validationErr)
+				sendNotifications(cfg.Notify, actualInputPath, notify.ValidationFailed, nil, results)
 				os.Exit(3)
 			}
 			fmt.Printf("%s✅ Validation passed successfully%s\n", colorGreen, colorReset)
 		}
+		sendNotifications(cfg.Notify, actualInputPath, validationStatus(cfg.Validate), nil, results)
 
 		// Final completion message
 		fmt.Printf("\n%s🎉 OpenMorph transformation completed successfully!%s\n", colorGreen, colorReset)
 	},
 }
 
+// runVendorExtensionsOnly applies only the vendor extensions step, or previews it with
+// --vendor-dry-run or --dry-run, and reports how many operations each provider touched
+func runVendorExtensionsOnly(cfg *config.Config, inputPath, outputFile string) {
+	preview := vendorDryRun || dryRun
+	pipeline := transform.NewTransformationPipeline(cfg, vendorProviders, preview, cfg.Backup, outputFile)
+	release := func() {}
+	if !preview {
+		release = acquireLock(inputPath)
+	}
+	results, err := pipeline.ExecuteVendorExtensions(inputPath)
+	release()
+	flushTelemetry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Transform error:", err)
+		os.Exit(2)
+	}
+
+	if preview {
+		fmt.Printf("\n🔍 %sVendor extensions preview: other steps skipped, no files written%s\n", colorYellow, colorReset)
+	}
+	printVendorExtensionResults(results.VendorResult)
+	writeSARIFReport(results.AllLocations())
+	writeAnnotations(results)
+	writeMetricsFile(results)
+	exitOnRequiredSkips(cfg, nil, results) // the vendor extensions step alone sends no notifications
+
+	if preview {
+		printSuccess("Vendor extensions preview completed")
+		return
+	}
+	fmt.Printf("\n%s🎉 Vendor extensions applied successfully!%s\n", colorGreen, colorReset)
+}
+
+// runOutputVariants generates every configured output variant and prints a combined report
+func runOutputVariants(cfg *config.Config, inputPath string) {
+	pipeline := transform.NewTransformationPipeline(cfg, vendorProviders, dryRun, false, "")
+	pipeline.Progress = newProgressReporter()
+	pipeline.KeepGoing = keepGoing
+	pipeline.Version = GetVersion()
+	results, err := pipeline.ExecuteVariants(inputPath, cfg.Outputs)
+	flushTelemetry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Transform error:", err)
+		if !dryRun {
+			sendNotifications(cfg.Notify, inputPath, notify.ValidationSkipped, err)
+		}
+		os.Exit(2)
+	}
+
+	printVariantsResults(results, dryRun)
+
+	locations := results.Shared.AllLocations()
+	annotated := []*transform.TransformationResults{results.Shared}
+	for _, variant := range results.Variants {
+		locations = append(locations, variant.Results.AllLocations()...)
+		annotated = append(annotated, variant.Results)
+	}
+	writeSARIFReport(locations)
+	writeAnnotations(annotated...)
+	writeMetricsFile(annotated...)
+	var notifyFailure func(error)
+	if !dryRun {
+		notifyFailure = func(err error) {
+			sendNotifications(cfg.Notify, inputPath, notify.ValidationSkipped, err, annotated...)
+		}
+	}
+	exitOnFileErrors(notifyFailure, annotated...)
+	exitOnRequiredSkips(cfg, notifyFailure, annotated...)
+	if !dryRun {
+		var dirs []string
+		for _, variant := range results.Variants {
+			dirs = append(dirs, variant.Dir)
+		}
+		warnInvalidExtensions(cfg, dirs...)
+	}
+
+	if cfg.Validate && !dryRun {
+		fmt.Printf("\n🔍 %sValidating OpenAPI specifications...%s\n", colorCyan, colorReset)
+		for _, variant := range results.Variants {
+			if err := RunSwaggerValidate(variant.Dir); err != nil {
+				fmt.Fprintf(os.Stderr, "%s❌ Validation failed:%s %v\n", colorRed, colorReset, err)
+				sendNotifications(cfg.Notify, inputPath, notify.ValidationFailed, nil, annotated...)
+				os.Exit(3)
+			}
+		}
+		fmt.Printf("%s✅ Validation passed successfully%s\n", colorGreen, colorReset)
+	}
+	if !dryRun {
+		sendNotifications(cfg.Notify, inputPath, validationStatus(cfg.Validate), nil, annotated...)
+	}
+
+	fmt.Printf("\n%s🎉 OpenMorph transformation completed successfully!%s\n", colorGreen, colorReset)
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&inputDir, "input", "i", "", "Directory containing OpenAPI specs (optional - can be specified in config file)")
 	rootCmd.PersistentFlags().StringVarP(&outputFile, "output", "o", "", "Output file path (optional - if not provided, files are modified in place)")
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Mapping config file (.yaml or .json)")
 	rootCmd.PersistentFlags().StringArrayVar(&inlineMaps, "map", nil, "Inline key mappings (from=to), repeatable")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Show what would change without writing files (Note: multi-step transformations shown independently, use --interactive for cumulative preview)")
-	rootCmd.PersistentFlags().BoolVar(&backup, "backup", false, "Save a .bak copy before overwriting")
+	rootCmd.PersistentFlags().BoolVar(&backup, "backup", false, "Back up files before overwriting (.bak copies, or timestamped runs with backups.naming)")
 	rootCmd.PersistentFlags().StringArrayVar(&exclude, "exclude", nil, "Keys to exclude from transformation (repeatable)")
 	rootCmd.PersistentFlags().BoolVar(&validate, "validate", false, "Run swagger-cli validate after transforming")
 	rootCmd.PersistentFlags().BoolVar(&interactive, "interactive", false, "Launch a TUI for interactive preview and approval")
 	rootCmd.PersistentFlags().BoolVar(&noConfig, "no-config", false, "Ignore all config files and use only CLI flags")
 	rootCmd.PersistentFlags().StringVar(&paginationPriorityStr, "pagination-priority", "", "Pagination strategy priority order (e.g., checkpoint,offset,page,cursor,none)")
+	rootCmd.Flags().StringSliceVar(&onlyPaths, "paths", nil, "Limit pagination, vendor extensions, defaults and flattening to operations whose path matches, e.g. '/users/**,/orgs/*'")
 	rootCmd.PersistentFlags().BoolVar(&flattenResponses, "flatten-responses", false, "Flatten oneOf/anyOf/allOf with single $ref after pagination processing")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed output including skipped targets and operations")
+	rootCmd.PersistentFlags().BoolVar(&explainSkips, "explain-skips", false, "Group the items every step skipped by reason, with their counts and examples")
 
 	// Vendor extension flags
 	rootCmd.PersistentFlags().StringArrayVar(&vendorProviders, "vendor-providers", nil, "Specific vendor providers to apply (e.g., fern,speakeasy). If empty, applies all configured providers")
+	rootCmd.PersistentFlags().BoolVar(&vendorDryRun, "vendor-dry-run", false, "Preview only the vendor extensions step, with a per-provider summary, without writing files")
+	rootCmd.PersistentFlags().BoolVar(&vendorOnly, "vendor-only", false, "Apply only the vendor extensions step, skipping the rest of the pipeline")
 
 	// Default values flags
 	rootCmd.PersistentFlags().BoolVar(&setDefaults, "set-defaults", false, "Enable default value setting (requires configuration via config file)")
+
+	// Internal content stripping flags
+	rootCmd.PersistentFlags().BoolVar(&stripInternal, "strip-internal", false, "Remove operations, components, properties and tags marked x-internal: true (publish mode)")
+
+	// Report flags
+	rootCmd.PersistentFlags().StringVar(&sarifFile, "sarif", "", "Write every change with its file, line and column to a SARIF 2.1.0 report")
+	rootCmd.PersistentFlags().StringVar(&annotationsFormat, "annotations", "", "Print skipped items and validation failures as CI annotations (github)")
+
+	// Strict mode flags
+	rootCmd.PersistentFlags().BoolVar(&strict, "strict", false, "Fail on unresolved $refs, including those the pagination step cannot follow, unknown pagination strategies and vendor strategies without a template")
+
+	// Ref check flags
+	rootCmd.PersistentFlags().BoolVar(&noRefCheck, "no-ref-check", false, "Skip checking for dangling $refs after steps that remove, rename or replace content")
+
+	// Observability flags
+	rootCmd.PersistentFlags().StringVar(&traceFile, "trace-file", "", "Write OpenTelemetry spans for every step and file as JSON to this file (- for stderr)")
+	rootCmd.PersistentFlags().StringVar(&metricsFile, "metrics-file", "", "Write step durations and change counts to this file in Prometheus textfile format")
+}
+
+// applyFlagOverrides merges the transformation flags given on the command line into the config
+func applyFlagOverrides(cmd *cobra.Command, cfg *config.Config) {
+	// Merge CLI --exclude, --validate, --backup, and --flatten-responses with config
+	if len(exclude) > 0 {
+		cfg.Exclude = append(cfg.Exclude, exclude...)
+	}
+	if validate {
+		cfg.Validate = true
+	}
+	if cmd.Flag("backup") != nil && cmd.Flag("backup").Changed {
+		cfg.Backup = backup
+	}
+	if cmd.Flag("flatten-responses") != nil && cmd.Flag("flatten-responses").Changed {
+		cfg.FlattenResponses = flattenResponses
+	}
+	if cmd.Flag("set-defaults") != nil && cmd.Flag("set-defaults").Changed {
+		cfg.DefaultValues.Enabled = setDefaults
+	}
+	if cmd.Flag("strip-internal") != nil && cmd.Flag("strip-internal").Changed {
+		cfg.StripInternal.Enabled = stripInternal
+	}
+	if noRefCheck {
+		cfg.RefCheck.Disabled = true
+	}
+	if failOnSkipped {
+		cfg.VendorExtensions.Required = true
+		cfg.DefaultValues.Required = true
+	}
+	if paginationPriorityStr != "" {
+		// Parse comma-separated pagination priority
+		priorities := strings.Split(paginationPriorityStr, ",")
+		for i, p := range priorities {
+			priorities[i] = strings.TrimSpace(p)
+		}
+		cfg.PaginationPriority = priorities
+	}
+	if len(onlyPaths) > 0 {
+		cfg.OnlyPaths = onlyPaths
+	}
+}
+
+// writeSARIFReport writes the change locations to the --sarif file, if requested
+func writeSARIFReport(locations []transform.ChangeLocation) {
+	if sarifFile == "" {
+		return
+	}
+	if err := report.WriteSARIFFile(sarifFile, locations, GetVersion()); err != nil {
+		fmt.Fprintln(os.Stderr, "Report error:", err)
+		os.Exit(2)
+	}
+	fmt.Printf("📝 %sSARIF report:%s %s (%d changes)\n", colorCyan, colorReset, sarifFile, len(locations))
+}
+
+// checkStrictMode exits with the problems found under inputPath when --strict is set
+func checkStrictMode(cfg *config.Config, inputPath string) {
+	if !strict {
+		return
+	}
+	pipeline := transform.NewTransformationPipeline(cfg, vendorProviders, true, false, "")
+	pipeline.Strict = true
+	pipeline.KeepGoing = keepGoing
+	if err := pipeline.CheckStrict(inputPath); err != nil {
+		printStrictError(err)
+		os.Exit(2)
+	}
+}
+
+// printStrictError prints a strict mode failure, along with --annotations for each problem
+func printStrictError(err error) {
+	fmt.Fprintf(os.Stderr, "%s❌ %v%s\n", colorRed, err, colorReset)
+	var strictErr *transform.StrictError
+	if annotationsFormat != "" && errors.As(err, &strictErr) {
+		printAnnotations(report.StrictAnnotations(strictErr.Issues))
+	}
+}
+
+// checkAnnotationsFormat exits if --annotations names an unsupported format
+func checkAnnotationsFormat() {
+	if annotationsFormat != "" && annotationsFormat != report.AnnotationsGitHub {
+		fmt.Fprintf(os.Stderr, "Error: unsupported --annotations format %q (supported: %s)\n", annotationsFormat, report.AnnotationsGitHub)
+		os.Exit(1)
+	}
+}
+
+// writeAnnotations prints the items skipped by the pipeline as --annotations workflow commands, if requested
+func writeAnnotations(results ...*transform.TransformationResults) {
+	if annotationsFormat == "" {
+		return
+	}
+	var annotations []report.Annotation
+	for _, r := range results {
+		annotations = append(annotations, report.SkippedAnnotations(r)...)
+		annotations = append(annotations, report.DanglingRefAnnotations(r)...)
+		annotations = append(annotations, report.UnresolvedRefAnnotations(r)...)
+		annotations = append(annotations, report.EmptySchemaAnnotations(r)...)
+		annotations = append(annotations, report.InvariantAnnotations(r)...)
+		annotations = append(annotations, report.FileErrorAnnotations(r)...)
+	}
+	printAnnotations(annotations)
+}
+
+// printAnnotations writes annotations to stdout, where the CI runner picks up workflow commands
+func printAnnotations(annotations []report.Annotation) {
+	if err := report.WriteGitHubAnnotations(os.Stdout, annotations); err != nil {
+		fmt.Fprintln(os.Stderr, "Report error:", err)
+	}
 }
 
 // Execute runs the root command.
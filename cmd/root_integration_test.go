@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/developerkunal/OpenMorph/internal/lock"
 )
 
 func TestCLI_TransformDryRun(t *testing.T) {
@@ -117,3 +125,519 @@ func mustGetwd() string {
 	}
 	return wd
 }
+
+func TestCLI_SARIFReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(file, []byte("openapi: 3.0.0\ninfo:\n  x-a: 1\n"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	sarifPath := filepath.Join(dir, "report.sarif")
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", dir, "--map", "x-a=x-z", "--no-config", "--sarif", sarifPath)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("transform failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(sarifPath)
+	if err != nil {
+		t.Fatalf("expected SARIF report to be written: %v", err)
+	}
+	report := string(data)
+	for _, want := range []string{`"version": "2.1.0"`, `"ruleId": "mappings"`, `"startLine": 3`, `"startColumn": 3`, `"text": "x-a -> x-z"`} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected SARIF report to contain %s\n%s", want, report)
+		}
+	}
+}
+
+func TestCLI_GitHubAnnotations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api.yaml")
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            default: 5
+      responses:
+        "200":
+          description: Success
+`
+	configPath := filepath.Join(dir, "config.yaml")
+	cfg := `default_values:
+  enabled: true
+  rules:
+    limit:
+      target:
+        location: parameter
+      condition:
+        type: integer
+      value: 20
+`
+	if err := os.WriteFile(file, []byte(spec), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte(cfg), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", file, "--config", configPath, "--annotations", "github")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("transform failed: %v\n%s", err, out)
+	}
+	want := "title=Default value skipped::GET /users parameter limit: default already exists"
+	if !strings.Contains(string(out), "::warning file=") || !strings.Contains(string(out), want) {
+		t.Errorf("expected a warning annotation containing %q:\n%s", want, out)
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "--input", file, "--no-config", "--annotations", "gitlab")
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected unsupported annotations format to fail:\n%s", out)
+	}
+}
+
+func TestCLI_NotifyWebhook(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	payloads := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payloads <- string(body)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(file, []byte("openapi: 3.0.0\ninfo:\n  x-a: 1\n"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := fmt.Sprintf("mappings:\n  x-a: x-z\nnotify:\n  webhooks:\n    - url: %s\n      on: changes\n", server.URL)
+	if err := os.WriteFile(configPath, []byte(cfg), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", dir, "--config", configPath)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("transform failed: %v\n%s", err, out)
+	}
+
+	select {
+	case payload := <-payloads:
+		for _, want := range []string{`"status":"success"`, `"changes":1`, `"validation":"skipped"`, "api.yaml"} {
+			if !strings.Contains(payload, want) {
+				t.Errorf("expected payload to contain %s: %s", want, payload)
+			}
+		}
+	default:
+		t.Fatalf("expected a webhook request\n%s", out)
+	}
+}
+
+func TestCLI_MetricsAndTraceFiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api.yaml")
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+x-foo: bar
+paths: {}
+`
+	if err := os.WriteFile(file, []byte(spec), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	metricsPath := filepath.Join(dir, "openmorph.prom")
+	tracePath := filepath.Join(dir, "trace.json")
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", file, "--map", "x-foo=x-bar", "--no-config",
+		"--metrics-file", metricsPath, "--trace-file", tracePath)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("transform failed: %v\n%s", err, out)
+	}
+
+	metrics, err := os.ReadFile(metricsPath)
+	if err != nil {
+		t.Fatalf("metrics file not written: %v", err)
+	}
+	for _, want := range []string{`openmorph_step_changes{step="mappings"} 1`, "openmorph_files_changed 1"} {
+		if !strings.Contains(string(metrics), want) {
+			t.Errorf("metrics file missing %q:\n%s", want, metrics)
+		}
+	}
+
+	trace, err := os.ReadFile(tracePath)
+	if err != nil {
+		t.Fatalf("trace file not written: %v", err)
+	}
+	for _, want := range []string{"openmorph.pipeline", "openmorph.step mappings", "openmorph.file"} {
+		if !strings.Contains(string(trace), want) {
+			t.Errorf("trace file missing span %q", want)
+		}
+	}
+}
+
+func TestCLI_Strict(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api.yaml")
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+x-foo: bar
+components:
+  schemas:
+    User:
+      $ref: '#/components/schemas/Missing'
+`
+	if err := os.WriteFile(file, []byte(spec), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", file, "--map", "x-foo=x-bar", "--no-config",
+		"--pagination-priority", "cursor,pages", "--strict", "--annotations", "github")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected strict mode to fail, got:\n%s", out)
+	}
+	for _, want := range []string{
+		file + ":9:13: unresolved $ref #/components/schemas/Missing",
+		`unknown pagination strategy "pages"`,
+		"::error file=" + file + ",line=9,col=13,title=Strict mode::",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	data, _ := os.ReadFile(file)
+	if string(data) != spec {
+		t.Errorf("expected strict mode to leave the file untouched, got:\n%s", data)
+	}
+}
+
+func TestCLI_VendorDryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	configFile := filepath.Join(tempDir, "openmorph.yaml")
+
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: array
+                    items:
+                      type: object
+`
+	config := `mappings:
+  x-a: x-b
+vendor_extensions:
+  enabled: true
+  providers:
+    fern:
+      extension_name: x-fern-pagination
+      target_level: operation
+      field_mapping:
+        request_params:
+          cursor: [cursor]
+      strategies:
+        cursor:
+          template:
+            cursor: $request.{cursor_param}
+            results: $response.{results_field}
+          required_fields: [cursor_param, results_field]
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+	if err := os.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", inputFile, "--config", configFile, "--vendor-providers", "fern", "--vendor-dry-run")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("vendor dry run failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "fern: 1 operations") {
+		t.Errorf("expected a per-provider summary, got:\n%s", out)
+	}
+
+	data, _ := os.ReadFile(inputFile)
+	if string(data) != input {
+		t.Errorf("expected the input to be unchanged, got:\n%s", data)
+	}
+}
+
+func TestCLI_MappingUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api.yaml")
+	input := "openapi: 3.0.0\ninfo:\n  title: Test\n  version: 1.0.0\n  x-a: 1\n  x-b: 2\n  x-speakeasy-groups: users\npaths: {}\n"
+	if err := os.WriteFile(file, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "--input", dir, "--map", "x-a=x-z", "--map", "x-b=x-y",
+		"--map", "x-speakeasy-group=x-fern-sdk-group-name", "--no-config", "--dry-run")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("dry run failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{"x-a → x-z: 1 matches", "x-speakeasy-group → x-fern-sdk-group-name: no matches", "did you mean", "x-speakeasy-groups"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCLI_ExplainSkips(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api.yaml")
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            default: 5
+        - name: offset
+          in: query
+          schema:
+            type: integer
+            default: 0
+      responses:
+        "200":
+          description: Success
+`
+	configPath := filepath.Join(dir, "config.yaml")
+	cfg := `default_values:
+  enabled: true
+  rules:
+    integers:
+      target:
+        location: parameter
+      condition:
+        type: integer
+      value: 20
+`
+	if err := os.WriteFile(file, []byte(spec), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte(cfg), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	out, err := exec.Command("go", "run", "../main.go", "--input", file, "--config", configPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("transform failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "default_exists 2, use --explain-skips for details") {
+		t.Errorf("expected skip counts in the summary:\n%s", out)
+	}
+
+	out, err = exec.Command("go", "run", "../main.go", "--input", file, "--config", configPath, "--explain-skips").CombinedOutput()
+	if err != nil {
+		t.Fatalf("transform failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{"default already exists: 2 occurrences", "(default_exists, defaults)", "GET /users parameter offset: default already exists"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected --explain-skips output to contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCLI_KeepGoing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.yaml")
+	broken := filepath.Join(dir, "broken.yaml")
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: true
+      responses:
+        "200":
+          description: Success
+`
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(good, []byte(spec), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(broken, []byte("openapi: 3.0.0\ninfo: {title: Test API\n"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("mappings:\n  x-foo: x-bar\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	out, err := exec.Command("go", "run", "../main.go", "--input", dir, "--config", configPath).CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "Transform error:") {
+		t.Fatalf("expected the broken file to abort the run, got %v:\n%s", err, out)
+	}
+
+	// The failed files still notify the webhooks that post on failure
+	payloads := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payloads <- string(body)
+	}))
+	defer server.Close()
+	cfg := fmt.Sprintf("mappings:\n  x-foo: x-bar\nnotify:\n  webhooks:\n    - url: %s\n      on: failure\n", server.URL)
+	if err := os.WriteFile(configPath, []byte(cfg), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	out, err = exec.Command("go", "run", "../main.go", "--input", dir, "--config", configPath, "--keep-going").CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected --keep-going to fail the run:\n%s", out)
+	}
+	for _, want := range []string{"1 file(s) failed and were left unchanged", broken + ":1 [mappings]", "exit status 5"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected --keep-going output to contain %q:\n%s", want, out)
+		}
+	}
+	select {
+	case payload := <-payloads:
+		for _, want := range []string{`"status":"failed"`, `"error":"1 file(s) failed and were left unchanged"`} {
+			if !strings.Contains(payload, want) {
+				t.Errorf("expected payload to contain %s: %s", want, payload)
+			}
+		}
+	default:
+		t.Fatalf("expected a webhook request\n%s", out)
+	}
+	data, err := os.ReadFile(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "x-bar") {
+		t.Errorf("expected the good file to be transformed, got:\n%s", data)
+	}
+}
+
+func TestCLI_Lock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api.yaml")
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: true
+      responses:
+        "200":
+          description: Success
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte(spec), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("mappings:\n  x-foo: x-bar\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	// A run on another host that started a minute ago
+	holder := fmt.Sprintf(`{"pid": 1, "host": "elsewhere", "started": %q}`, time.Now().Add(-time.Minute).UTC().Format(time.RFC3339))
+	if err := os.WriteFile(filepath.Join(dir, lock.FileName), []byte(holder), 0600); err != nil {
+		t.Fatalf("failed to write lock: %v", err)
+	}
+
+	out, err := exec.Command("go", "run", "../main.go", "--input", dir, "--config", configPath).CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "is held by another run (pid 1 on elsewhere") {
+		t.Fatalf("expected the held lock to stop the run, got %v:\n%s", err, out)
+	}
+	data, _ := os.ReadFile(file)
+	if strings.Contains(string(data), "x-bar") {
+		t.Errorf("expected the locked input to be left unchanged")
+	}
+
+	out, err = exec.Command("go", "run", "../main.go", "--input", dir, "--config", configPath, "--no-lock").CombinedOutput()
+	if err != nil {
+		t.Fatalf("expected --no-lock to ignore the lock: %v\n%s", err, out)
+	}
+	data, _ = os.ReadFile(file)
+	if !strings.Contains(string(data), "x-bar") {
+		t.Errorf("expected the input to be transformed with --no-lock")
+	}
+}
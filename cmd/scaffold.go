@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	scaffoldLanguages []string
+	scaffoldSpecPath  string
+)
+
+var scaffoldCmd = &cobra.Command{
+	Use:   "scaffold <fern|speakeasy> [file]",
+	Short: "Generate a starter SDK generator config from a spec",
+	Long: `Inspect an OpenAPI spec, usually the output of openmorph, and print a starter config for an
+SDK generator: a Fern generators.yml or a Speakeasy workflow.yaml with one SDK per language. The
+tags, servers, auth schemes and pagination found in the spec are listed at the top, along with
+what is missing for the generator, such as paginated operations without its pagination
+extension.
+
+Without --output the config is written to stdout.`,
+	Example: `  openmorph scaffold fern --input openapi.yaml --spec-path ../openapi.yaml -o fern/generators.yml
+  openmorph scaffold speakeasy openapi.yaml --languages typescript,go`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(_ *cobra.Command, args []string) {
+		inputPath := inputDir
+		if len(args) == 2 {
+			inputPath = args[1]
+		}
+		if inputPath == "" {
+			fmt.Fprintln(os.Stderr, "Error: No input file specified. Use --input <file> or pass the file as an argument.")
+			os.Exit(1)
+		}
+
+		opts := transform.ScaffoldOptions{Generator: args[0], Languages: scaffoldLanguages, SpecPath: scaffoldSpecPath}
+		if err := transform.ValidateScaffoldOptions(opts); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+
+		output, profile, err := transform.ScaffoldGeneratorConfig(inputPath, opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Scaffold error:", err)
+			os.Exit(2)
+		}
+		if outputFile == "" {
+			fmt.Print(string(output))
+			return
+		}
+		if err := os.WriteFile(outputFile, output, 0600); err != nil {
+			fmt.Fprintln(os.Stderr, "Scaffold error:", err)
+			os.Exit(2)
+		}
+		printSuccess(fmt.Sprintf("Wrote %s config for %d operations to %s", args[0], profile.Operations, outputFile))
+	},
+}
+
+func init() {
+	scaffoldCmd.Flags().StringSliceVar(&scaffoldLanguages, "languages", []string{"typescript", "python"}, "SDK languages to configure: typescript, python, go, java")
+	scaffoldCmd.Flags().StringVar(&scaffoldSpecPath, "spec-path", "", "Path the config refers to the spec by, relative to the config (default: the input path)")
+	rootCmd.AddCommand(scaffoldCmd)
+}
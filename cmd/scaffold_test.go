@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_ScaffoldFern(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      tags: [Users]
+      responses:
+        "200":
+          description: Success
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+	outputPath := filepath.Join(tempDir, "generators.yml")
+
+	cmd := exec.Command("go", "run", "../main.go", "scaffold", "fern", inputFile, "--no-config", "--languages", "python", "-o", outputPath)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("scaffold failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Tags: Users", "fernapi/fern-python-sdk", "client_class_name: TestAPI"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected the config to contain %q, got:\n%s", want, data)
+		}
+	}
+}
+
+func TestCLI_ScaffoldUnknownGenerator(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "scaffold", "openapi-generator", "api.yaml", "--no-config")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected scaffold to fail for an unknown generator:\n%s", out)
+	}
+	if !strings.Contains(string(out), `unknown generator "openapi-generator"`) {
+		t.Errorf("expected an unknown generator error, got:\n%s", out)
+	}
+}
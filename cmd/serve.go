@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/server"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveHTTPAddr     string
+	serveMaxBodyBytes int64
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [flags]",
+	Short: "Run a JSON-RPC server for editor integrations",
+	Long: `Run a long-running JSON-RPC 2.0 server that transforms documents sent by an editor and
+returns the transformed content with diagnostics, so plugins can show OpenMorph's changes and lint
+findings live while a spec is edited.
+
+By default requests and responses are exchanged over stdin/stdout, one JSON object per line.
+With --http the server accepts one request per POST instead, rejecting bodies over
+--max-body-bytes with 413 Request Entity Too Large.
+
+Methods:
+  initialize  returns the server name, version and supported methods
+  transform   params {filepath, content, config?}; returns {content, changed, diagnostics}
+  shutdown    stops a stdio server
+
+The server uses the config file, .openapirc.yaml and transformation flags the usual way; a
+transform request may pass its own config object (same keys as the config file) instead.
+Files are never written.`,
+	Example: `  openmorph serve --config openmorph.yaml
+  openmorph serve --http 127.0.0.1:7420`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		// Documents arrive with each request, so the input path is only a placeholder
+		cfg, err := config.LoadConfig(configFile, inlineMaps, ".", "", noConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		applyFlagOverrides(cmd, cfg)
+		setupTelemetry()
+		defer flushTelemetry()
+
+		srv := server.New(cfg, vendorProviders, GetVersion())
+		srv.MaxBodyBytes = serveMaxBodyBytes
+		// Transformation steps print warnings while they run; keep stdout free for responses
+		srv.Log = os.Stderr
+
+		if serveHTTPAddr != "" {
+			fmt.Fprintf(os.Stderr, "OpenMorph server listening on http://%s\n", serveHTTPAddr)
+			httpServer := &http.Server{
+				Addr:              serveHTTPAddr,
+				Handler:           srv.Handler(),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			if err := httpServer.ListenAndServe(); err != nil {
+				fmt.Fprintln(os.Stderr, "Server error:", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if err := srv.ServeStdio(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Server error:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveHTTPAddr, "http", "", "Serve JSON-RPC over HTTP on this address instead of stdio (e.g. 127.0.0.1:7420)")
+	serveCmd.Flags().Int64Var(&serveMaxBodyBytes, "max-body-bytes", server.DefaultMaxBodyBytes, "Largest HTTP request body accepted with --http, in bytes")
+	rootCmd.AddCommand(serveCmd)
+}
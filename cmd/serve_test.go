@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCLI_ServeStdio(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: bar
+      responses:
+        "200":
+          description: Success
+`
+	request, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "transform",
+		"params":  map[string]string{"filepath": "api.yaml", "content": spec},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "serve", "--no-config", "--map", "x-foo=x-bar")
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	cmd.Stdin = strings.NewReader(string(request) + "\n" + `{"jsonrpc":"2.0","id":2,"method":"shutdown"}` + "\n")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("serve failed: %v\n%s", err, out)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 response lines on stdout, got %d:\n%s", len(lines), out)
+	}
+	var resp struct {
+		Result struct {
+			Content     string `json:"content"`
+			Changed     bool   `json:"changed"`
+			Diagnostics []struct {
+				Line    int    `json:"line"`
+				Message string `json:"message"`
+			} `json:"diagnostics"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &resp); err != nil {
+		t.Fatalf("invalid response %q: %v", lines[0], err)
+	}
+	if !resp.Result.Changed || !strings.Contains(resp.Result.Content, "x-bar: bar") {
+		t.Errorf("expected transformed content, got %+v", resp.Result)
+	}
+	if len(resp.Result.Diagnostics) != 1 || resp.Result.Diagnostics[0].Line != 8 {
+		t.Errorf("expected one diagnostic on line 8, got %+v", resp.Result.Diagnostics)
+	}
+}
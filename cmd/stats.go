@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+var statsFormat string
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [path]",
+	Short: "Print an inventory of the specs: operations, schemas, extensions and pagination",
+	Long: `Print per-file statistics for every OpenAPI document under the input: operation, schema and
+parameter counts, extension usage by key, the distribution of pagination strategies over the
+operations, and the deepest oneOf/anyOf/allOf nesting. Files are never modified.
+
+Each file also gets a health score from 0 to 100 with a breakdown of the factors lowering it:
+missing descriptions, unused components, duplicate schemas, inconsistent pagination and deep
+composition. Compare the scores of the JSON reports of successive runs to track spec debt.
+
+Use --format json for a machine-readable report, e.g. for dashboards.`,
+	Example: `  openmorph stats specs/
+  openmorph stats --input api.yaml --format json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if statsFormat != "text" && statsFormat != "json" {
+			fmt.Fprintf(os.Stderr, "Error: invalid --format %q (expected text or json)\n", statsFormat)
+			os.Exit(1)
+		}
+		inputPath := inputDir
+		if len(args) == 1 {
+			inputPath = args[0]
+		}
+		cfg, err := config.LoadConfig(configFile, inlineMaps, inputPath, "", noConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+
+		files, total, err := transform.CollectStats(cfg.Input, cfg.Files)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Stats error:", err)
+			os.Exit(2)
+		}
+
+		if statsFormat == "json" {
+			report := struct {
+				Files []*transform.SpecStats `json:"files"`
+				Total *transform.SpecStats   `json:"total"`
+			}{files, total}
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Stats error:", err)
+				os.Exit(2)
+			}
+			fmt.Println(string(data))
+			return
+		}
+		printStats(files, total)
+	},
+}
+
+// printStats prints the inventory of each file and, for several files, their totals
+func printStats(files []*transform.SpecStats, total *transform.SpecStats) {
+	if len(files) == 0 {
+		fmt.Printf("ℹ️  %sNo OpenAPI documents found%s\n", colorYellow, colorReset)
+		return
+	}
+	for _, stats := range files {
+		fmt.Printf("\n%s📄 %s%s\n", colorBold, stats.File, colorReset)
+		printSpecStats(stats)
+	}
+	if len(files) > 1 {
+		fmt.Printf("\n%s📊 Total (%d files)%s\n", colorBold, total.Files, colorReset)
+		printSpecStats(total)
+	}
+	if len(total.SkippedFiles) > 0 {
+		fmt.Printf("\n%s⏭️  %d files are not OpenAPI documents%s\n", colorYellow, len(total.SkippedFiles), colorReset)
+	}
+}
+
+// printSpecStats prints the counts of one inventory
+func printSpecStats(stats *transform.SpecStats) {
+	fmt.Printf("   Operations: %s%d%s\n", colorGreen, stats.Operations, colorReset)
+	fmt.Printf("   Schemas: %s%d%s\n", colorGreen, stats.Schemas, colorReset)
+	fmt.Printf("   Parameters: %s%d%s\n", colorGreen, stats.Parameters, colorReset)
+	fmt.Printf("   Max composition depth: %s%d%s\n", colorGreen, stats.MaxCompositionDepth, colorReset)
+	if len(stats.Pagination) > 0 {
+		fmt.Printf("   Pagination:\n")
+		for _, strategy := range transform.SortedCounts(stats.Pagination) {
+			fmt.Printf("     %s▸%s %s: %d\n", colorCyan, colorReset, strategy, stats.Pagination[strategy])
+		}
+	}
+	if len(stats.Extensions) > 0 {
+		fmt.Printf("   Extensions:\n")
+		for _, key := range transform.SortedCounts(stats.Extensions) {
+			fmt.Printf("     %s▸%s %s: %d\n", colorCyan, colorReset, key, stats.Extensions[key])
+		}
+	}
+	printSpecHealth(stats.Health)
+}
+
+// printSpecHealth prints a health score and the factors lowering it
+func printSpecHealth(health transform.SpecHealth) {
+	color := colorGreen
+	switch {
+	case health.Score < 50:
+		color = colorRed
+	case health.Score < 80:
+		color = colorYellow
+	}
+	fmt.Printf("   Health score: %s%d/100%s\n", color, health.Score, colorReset)
+	for _, factor := range health.Breakdown {
+		if factor.Issues == 0 {
+			continue
+		}
+		fmt.Printf("     %s▸%s %s: %d of %d (-%d)\n", colorCyan, colorReset,
+			strings.ReplaceAll(factor.Name, "_", " "), factor.Issues, factor.Checked, factor.Penalty)
+	}
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsFormat, "format", "text", "Output format: text or json")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCLI_StatsJSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-sdk-group: users
+      parameters:
+        - name: page
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: Success
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "stats", "--no-config", "--format", "json", inputFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("stats failed: %v\n%s", err, out)
+	}
+
+	var report struct {
+		Total struct {
+			Operations int            `json:"operations"`
+			Extensions map[string]int `json:"extensions"`
+			Pagination map[string]int `json:"pagination"`
+			Health     struct {
+				Score     int `json:"score"`
+				Breakdown []struct {
+					Name   string `json:"name"`
+					Issues int    `json:"issues"`
+				} `json:"breakdown"`
+			} `json:"health"`
+		} `json:"total"`
+	}
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("expected JSON output, got %v:\n%s", err, out)
+	}
+	if report.Total.Operations != 1 || report.Total.Extensions["x-sdk-group"] != 1 || report.Total.Pagination["page"] != 1 {
+		t.Errorf("unexpected totals: %+v", report.Total)
+	}
+	// The operation has no summary or description, its parameter has no description
+	if report.Total.Health.Score != 75 || len(report.Total.Health.Breakdown) != 5 || report.Total.Health.Breakdown[0].Issues != 2 {
+		t.Errorf("unexpected health: %+v", report.Total.Health)
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/developerkunal/OpenMorph/internal/report"
+	"github.com/developerkunal/OpenMorph/internal/telemetry"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+var (
+	traceFile   string
+	metricsFile string
+
+	runStarted        time.Time
+	telemetryShutdown func(context.Context) error
+)
+
+// setupTelemetry starts the run clock and installs the span exporters selected by --trace-file
+// and the standard OTEL_EXPORTER_OTLP_* variables
+func setupTelemetry() {
+	runStarted = time.Now()
+	shutdown, err := telemetry.Setup(context.Background(), telemetry.Options{
+		TraceFile: traceFile,
+		OTLP:      telemetry.OTLPConfigured(),
+		Version:   GetVersion(),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Telemetry error:", err)
+		os.Exit(1)
+	}
+	telemetryShutdown = shutdown
+}
+
+// flushTelemetry exports pending spans. Call it once the pipeline has finished, before any exit.
+func flushTelemetry() {
+	if telemetryShutdown == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := telemetryShutdown(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "%s⚠️  Failed to export traces:%s %v\n", colorYellow, colorReset, err)
+	}
+	telemetryShutdown = nil
+}
+
+// writeMetricsFile writes step durations and change counts to the --metrics-file, if requested
+func writeMetricsFile(results ...*transform.TransformationResults) {
+	if metricsFile == "" {
+		return
+	}
+	metrics := report.NewRunMetrics(time.Since(runStarted), time.Now(), results...)
+	if err := report.WritePrometheusFile(metricsFile, metrics); err != nil {
+		fmt.Fprintln(os.Stderr, "Report error:", err)
+		os.Exit(2)
+	}
+	fmt.Printf("📈 %sMetrics:%s %s\n", colorCyan, colorReset, metricsFile)
+}
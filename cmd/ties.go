@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+	"github.com/developerkunal/OpenMorph/internal/tui"
+)
+
+var resolveTies bool
+
+// resolvePaginationTies lets the user pick the pagination strategy of the operations the config
+// does not decide, saves the picks as endpoint rules in the config file and applies them to this
+// run. It returns without a prompt when --resolve-ties is off or nothing is tied.
+func resolvePaginationTies(cfg *config.Config) {
+	if !resolveTies {
+		return
+	}
+	ties, err := transform.FindPaginationTies(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if len(ties) == 0 {
+		fmt.Printf("%s✅ No pagination ties to resolve%s\n", colorGreen, colorReset)
+		return
+	}
+
+	choices := make([]tui.TieChoice, 0, len(ties))
+	for _, tie := range ties {
+		choices = append(choices, tui.TieChoice{
+			Operation: tie.Operation(),
+			Position:  tie.Position(),
+			Reason:    tie.Reason(),
+			Evidence:  tie.Evidence(),
+			Choices:   tie.Candidates,
+		})
+	}
+	picked, err := tui.RunTieResolver(choices)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "TUI error:", err)
+		os.Exit(1)
+	}
+	if len(picked) == 0 {
+		fmt.Printf("ℹ️  %sNo ties resolved, config unchanged%s\n", colorYellow, colorReset)
+		return
+	}
+
+	var rules []config.EndpointPaginationRule
+	for i, tie := range ties {
+		if strategy, ok := picked[i]; ok {
+			rules = append(rules, transform.TieRule(tie, strategy))
+		}
+	}
+	path := cfg.Source
+	if path == "" {
+		path = ".openapirc.yaml"
+	}
+	if err := config.AddEndpointRules(path, rules); err != nil {
+		fmt.Fprintln(os.Stderr, "Config error:", err)
+		os.Exit(1)
+	}
+	cfg.EndpointPagination = append(rules, cfg.EndpointPagination...)
+	fmt.Printf("%s✅ Added %d endpoint rules to %s%s\n", colorGreen, len(rules), path, colorReset)
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&resolveTies, "resolve-ties", false, "Pick the pagination strategy of operations the config leaves undecided in a TUI, and save the picks as endpoint_pagination rules")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/developerkunal/OpenMorph/internal/update"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	versionCheck bool
+	updateCheck  bool
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update openmorph to the latest release",
+	Long: `Look up the latest OpenMorph release on GitHub and, when it is newer than this binary, download
+the archive for this platform, verify it against the release checksums and replace the running
+executable with it. With --check, only report whether a newer release exists.
+
+Binaries installed by Homebrew, Scoop or Docker are better updated through those. Development
+builds are never updated.`,
+	Example: `  openmorph update --check
+  openmorph update`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		current := GetVersion()
+		release, err := update.Latest(update.LatestReleaseURL)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Update error:", err)
+			os.Exit(2)
+		}
+		if !update.IsRelease(current) {
+			fmt.Printf("openmorph %s is a development build and is not updated; the latest release is %s: %s\n", current, release.Tag, release.URL)
+			return
+		}
+		if !update.IsNewer(current, release.Tag) {
+			fmt.Printf("%s✅ openmorph %s is up to date (latest release %s)%s\n", colorGreen, current, release.Tag, colorReset)
+			return
+		}
+		fmt.Printf("⬆️  openmorph %s is available (this is %s): %s\n", release.Tag, current, release.URL)
+		if updateCheck {
+			return
+		}
+
+		exe, err := os.Executable()
+		if err == nil {
+			exe, err = filepath.EvalSymlinks(exe)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Update error:", err)
+			os.Exit(2)
+		}
+		if err := update.Apply(release, exe); err != nil {
+			fmt.Fprintln(os.Stderr, "Update error:", err)
+			os.Exit(2)
+		}
+		fmt.Printf("%s✅ Updated %s to %s%s\n", colorGreen, exe, release.Tag, colorReset)
+	},
+}
+
+// checkForNewerVersion prints a notice on stderr when --version-check is set and a newer release
+// exists. Lookup failures are ignored so the check never gets in the way of a run.
+func checkForNewerVersion() {
+	if !versionCheck {
+		return
+	}
+	release, err := update.Latest(update.LatestReleaseURL)
+	if err != nil || !update.IsNewer(GetVersion(), release.Tag) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s⬆️  openmorph %s is available (this is %s), run 'openmorph update'%s\n", colorYellow, release.Tag, GetVersion(), colorReset)
+}
+
+func init() {
+	updateCmd.Flags().BoolVar(&updateCheck, "check", false, "Only report whether a newer release exists")
+	rootCmd.PersistentFlags().BoolVar(&versionCheck, "version-check", false, "Check GitHub for a newer release at startup and report it on stderr")
+	rootCmd.AddCommand(updateCmd)
+}
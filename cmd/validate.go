@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/report"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+
+	"github.com/spf13/cobra"
+)
+
+// externalValidator is the rule name of the issues reported by swagger-cli
+const externalValidator = "swagger-cli"
+
+var (
+	validateFormat   string
+	validateExternal bool
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Validate the specs without transforming them",
+	Long: `Validate every OpenAPI document under the input with the built-in validator: the document
+parses, declares the required fields, uses unique operationIds and well-formed parameters whose
+path parameters match the path templates, every $ref resolves, and extension values match the
+schemas under extension_schemas. Files are never modified.
+
+--external, or validate: true in the config file, also runs swagger-cli validate on each document.
+The command exits with status 1 when any document is invalid, so CI can gate on it.`,
+	Example: `  openmorph validate specs/
+  openmorph validate --config openmorph.yaml --external
+  openmorph validate --input api.yaml --format sarif > validation.sarif
+  openmorph validate --input api.yaml --format junit > validation.xml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		switch validateFormat {
+		case "text", "json", "sarif", "junit":
+		default:
+			fmt.Fprintf(os.Stderr, "Error: invalid --format %q (expected text, json, sarif or junit)\n", validateFormat)
+			os.Exit(1)
+		}
+		checkAnnotationsFormat()
+		inputPath := inputDir
+		if len(args) == 1 {
+			inputPath = args[0]
+		}
+		cfg, err := config.LoadConfig(configFile, inlineMaps, inputPath, "", noConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+		schemas, err := transform.LoadExtensionSchemas(cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Config error:", err)
+			os.Exit(1)
+		}
+
+		validation, err := transform.ValidateSpecs(cfg.Input, cfg.Files, schemas)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Validation error:", err)
+			os.Exit(2)
+		}
+		if validateExternal || cfg.Validate {
+			if err := runExternalValidator(validation); err != nil {
+				fmt.Fprintln(os.Stderr, "Validation error:", err)
+				os.Exit(2)
+			}
+		}
+
+		if err := writeValidation(validation); err != nil {
+			fmt.Fprintln(os.Stderr, "Report error:", err)
+			os.Exit(2)
+		}
+		if len(validation.Issues) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// runExternalValidator runs swagger-cli validate on every document that parsed and records the
+// documents it rejects
+func runExternalValidator(validation *transform.SpecValidation) error {
+	unparsed := make(map[string]bool)
+	for _, issue := range validation.Issues {
+		if issue.Rule == transform.RuleParse {
+			unparsed[issue.File] = true
+		}
+	}
+	for _, file := range validation.Files {
+		if unparsed[file] {
+			continue
+		}
+		switch code := RunShellSilent(fmt.Sprintf("swagger-cli validate %q", file)); code {
+		case 0:
+		case 127:
+			return fmt.Errorf("swagger-cli not found; install it with npm install -g @apidevtools/swagger-cli")
+		default:
+			validation.Issues = append(validation.Issues, transform.SpecIssue{
+				File:    file,
+				Rule:    externalValidator,
+				Message: fmt.Sprintf("swagger-cli validate exited with status %d", code),
+			})
+		}
+	}
+	return nil
+}
+
+// writeValidation prints a validation in the format --format selects
+func writeValidation(validation *transform.SpecValidation) error {
+	switch validateFormat {
+	case "json":
+		if validation.Files == nil {
+			validation.Files = []string{}
+		}
+		if validation.Issues == nil {
+			validation.Issues = []transform.SpecIssue{}
+		}
+		out := struct {
+			Valid bool `json:"valid"`
+			*transform.SpecValidation
+		}{len(validation.Issues) == 0, validation}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "sarif":
+		return report.WriteValidationSARIF(os.Stdout, validation, GetVersion())
+	case "junit":
+		return report.WriteValidationJUnit(os.Stdout, validation)
+	default:
+		printValidation(validation)
+		if annotationsFormat != "" {
+			printAnnotations(report.ValidationAnnotations(validation.Issues))
+		}
+	}
+	return nil
+}
+
+// printValidation prints the outcome of every validated document
+func printValidation(validation *transform.SpecValidation) {
+	if len(validation.Files) == 0 {
+		fmt.Printf("ℹ️  %sNo OpenAPI documents found%s\n", colorYellow, colorReset)
+		return
+	}
+
+	byFile := make(map[string][]transform.SpecIssue)
+	for _, issue := range validation.Issues {
+		byFile[issue.File] = append(byFile[issue.File], issue)
+	}
+	invalid := 0
+	for _, file := range validation.Files {
+		issues := byFile[file]
+		if len(issues) == 0 {
+			fmt.Printf("%s✅ %s is valid%s\n", colorGreen, file, colorReset)
+			continue
+		}
+		invalid++
+		fmt.Printf("%s❌ %s: %d issue(s)%s\n", colorRed, file, len(issues), colorReset)
+		for _, issue := range issues {
+			fmt.Printf("   • %s: %s[%s]%s %s\n", issue.Position(), colorCyan, issue.Rule, colorReset, issue.Message)
+		}
+	}
+
+	if invalid == 0 {
+		fmt.Printf("\n%s✅ All %d documents are valid%s\n", colorGreen, len(validation.Files), colorReset)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n%s❌ %d of %d documents are invalid%s\n", colorRed, invalid, len(validation.Files), colorReset)
+}
+
+func init() {
+	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "Output format: text, json, sarif or junit")
+	validateCmd.Flags().BoolVar(&validateExternal, "external", false, "Also run swagger-cli validate on each document")
+	rootCmd.AddCommand(validateCmd)
+}
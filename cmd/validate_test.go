@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCLI_ValidateJSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping CLI integration test in short mode")
+	}
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "api.yaml")
+	input := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      responses:
+        "200":
+          description: Success
+`
+	if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", "../main.go", "validate", "--no-config", "--format", "json", inputFile)
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("expected exit status 1, got %v\n%s", err, out)
+	}
+
+	var report struct {
+		Valid  bool `json:"valid"`
+		Issues []struct {
+			Rule    string `json:"rule"`
+			Message string `json:"message"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("expected JSON output, got %v:\n%s", err, out)
+	}
+	if report.Valid || len(report.Issues) != 1 || report.Issues[0].Message != "GET /users/{id}: path parameter {id} is not declared" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil || string(data) != input {
+		t.Errorf("expected the input to be left alone, got %v:\n%s", err, data)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 
+	"github.com/developerkunal/OpenMorph/internal/report"
 	"github.com/developerkunal/OpenMorph/internal/transform"
 )
 
@@ -32,6 +33,14 @@ func RunSwaggerValidate(inputDir string) error {
 		fmt.Printf("   %s🔍 Validating:%s %s\n", colorBlue, colorReset, f)
 
 		if code := RunShellSilent(cmd); code != 0 {
+			if annotationsFormat != "" {
+				printAnnotations([]report.Annotation{{
+					Level:   report.AnnotationError,
+					File:    f,
+					Title:   "OpenAPI validation failed",
+					Message: fmt.Sprintf("swagger-cli validate exited with status %d", code),
+				}})
+			}
 			return fmt.Errorf("swagger-cli validate failed for %s", f)
 		}
 		fmt.Printf("   %s✅ %s is valid%s\n", colorGreen, f, colorReset)
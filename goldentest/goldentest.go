@@ -0,0 +1,147 @@
+// Package goldentest runs the transformation pipeline on fixture documents and compares the output
+// with golden files, so regression cases need no Go assertions of their own.
+//
+// Every subdirectory of a cases directory is a case:
+//
+//	testdata/<case>/input.yaml     document the pipeline runs on
+//	testdata/<case>/config.yaml    config of the run (optional, empty when missing)
+//	testdata/<case>/expected.yaml  output the pipeline must produce
+//
+// Running the tests with -update rewrites expected.yaml with the actual output.
+package goldentest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+// Files of a case
+const (
+	InputFile    = "input.yaml"
+	ConfigFile   = "config.yaml"
+	ExpectedFile = "expected.yaml"
+)
+
+var update = flag.Bool("update", false, "rewrite the expected.yaml of golden cases with the actual output")
+
+// Run runs every case under dir as a subtest named after its directory
+func Run(t *testing.T, dir string) {
+	t.Helper()
+	cases, err := Cases(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatalf("no golden cases under %s", dir)
+	}
+	for _, name := range cases {
+		t.Run(name, func(t *testing.T) {
+			RunCase(t, filepath.Join(dir, name))
+		})
+	}
+}
+
+// Cases returns the names of the case directories under dir, in order
+func Cases(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var cases []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, entry.Name(), InputFile)); err == nil {
+			cases = append(cases, entry.Name())
+		}
+	}
+	sort.Strings(cases)
+	return cases, nil
+}
+
+// RunCase runs the pipeline on the input of the case in dir and compares the output with its
+// expected.yaml, or rewrites expected.yaml with -update
+func RunCase(t *testing.T, dir string) {
+	t.Helper()
+	got, err := Transform(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPath := filepath.Join(dir, ExpectedFile)
+	if *update {
+		if err := os.WriteFile(expectedPath, got, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(expectedPath)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("%s is missing; run the tests with -update to create it", expectedPath)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := firstDifference(string(want), string(got)); diff != "" {
+		t.Errorf("output differs from %s %s\n\ngot:\n%s\nrun the tests with -update to accept the output", expectedPath, diff, got)
+	}
+}
+
+// Transform runs the full pipeline with the config of the case in dir on its input, leaving the
+// files of the case untouched, and returns the output
+func Transform(dir string) ([]byte, error) {
+	inputPath := filepath.Join(dir, InputFile)
+	input, err := os.ReadFile(inputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	configPath := filepath.Join(dir, ConfigFile)
+	if _, err := os.Stat(configPath); errors.Is(err, os.ErrNotExist) {
+		configPath = ""
+	}
+	cfg, err := config.LoadConfig(configPath, nil, inputPath, "", configPath == "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %v", configPath, err)
+	}
+
+	pipeline := transform.NewTransformationPipeline(cfg, nil, false, false, "")
+	output, _, err := pipeline.TransformBytes(inputPath, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform %s: %v", inputPath, err)
+	}
+	return output, nil
+}
+
+// firstDifference describes the first line where got departs from want, or returns "" when they
+// are equal
+func firstDifference(want, got string) string {
+	if want == got {
+		return ""
+	}
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var wantLine, gotLine string
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if i >= len(wantLines) || i >= len(gotLines) || wantLine != gotLine {
+			return fmt.Sprintf("at line %d:\n  want: %q\n  got:  %q", i+1, wantLine, gotLine)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package goldentest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCases(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b", "a", "no_input"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"a", "b"} {
+		if err := os.WriteFile(filepath.Join(dir, name, InputFile), []byte("openapi: 3.0.3\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases, err := Cases(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(cases, want) {
+		t.Errorf("expected %v, got %v", want, cases)
+	}
+}
+
+func TestFirstDifference(t *testing.T) {
+	if got := firstDifference("a\nb\n", "a\nb\n"); got != "" {
+		t.Errorf("expected no difference, got %q", got)
+	}
+	if got, want := firstDifference("a\nb\n", "a\nc\n"), "at line 2:\n  want: \"b\"\n  got:  \"c\""; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got := firstDifference("a\n", "a"); got == "" {
+		t.Error("expected a missing trailing newline to be a difference")
+	}
+}
@@ -0,0 +1,181 @@
+// Package backup keeps copies of the files a run overwrites in per-run folders that mirror their
+// paths, and lists, prunes and restores those runs
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDir is where runs are kept when the config does not name a folder
+const DefaultDir = ".openmorph/backups"
+
+// manifestFile lists the files of a run and where they came from. Neither it nor the backed-up
+// files have a YAML/JSON extension, so runs kept under an input folder are not transformed.
+const manifestFile = "run.manifest"
+
+// backupSuffix is appended to the mirrored path of every backed-up file
+const backupSuffix = ".bak"
+
+// runIDLayout names run folders, so they sort by the time of the run
+const runIDLayout = "20060102T150405Z"
+
+// Run is one backed-up run
+type Run struct {
+	ID      string            `json:"id"`
+	Created time.Time         `json:"created"`
+	Files   map[string]string `json:"files"` // backup path relative to the run folder -> absolute original path
+	Dir     string            `json:"-"`     // the run folder
+}
+
+// Save copies the original contents of files, keyed by path, into a new run folder under root and
+// returns the run. Paths under the working directory are mirrored relative to it, others from the
+// file system root, with a .bak suffix.
+func Save(root string, originals map[string][]byte, now time.Time) (*Run, error) {
+	if len(originals) == 0 {
+		return nil, nil
+	}
+	run := &Run{ID: now.UTC().Format(runIDLayout), Created: now.UTC(), Files: make(map[string]string, len(originals))}
+	for n := 2; ; n++ {
+		run.Dir = filepath.Join(root, run.ID)
+		if _, err := os.Stat(run.Dir); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		run.ID = fmt.Sprintf("%s-%d", now.UTC().Format(runIDLayout), n)
+	}
+
+	for path, data := range originals {
+		original, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		mirrored := mirrorPath(original) + backupSuffix
+		target := filepath.Join(run.Dir, mirrored)
+		if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return nil, fmt.Errorf("failed to create backup folder: %w", err)
+		}
+		if err := os.WriteFile(target, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+		run.Files[filepath.ToSlash(mirrored)] = original
+	}
+
+	manifest, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(run.Dir, manifestFile), append(manifest, '\n'), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	return run, nil
+}
+
+// mirrorPath returns the relative path a file is kept under in a run folder
+func mirrorPath(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return rel
+		}
+	}
+	return strings.TrimLeft(strings.TrimPrefix(path, filepath.VolumeName(path)), `/\`)
+}
+
+// List returns the runs under root, oldest first. A missing root has no runs.
+func List(root string) ([]Run, error) {
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []Run
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+		if err != nil {
+			continue // Not a run folder
+		}
+		var run Run
+		if err := json.Unmarshal(data, &run); err != nil {
+			return nil, fmt.Errorf("invalid backup manifest in %s: %w", dir, err)
+		}
+		run.Dir = dir
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].Created.Equal(runs[j].Created) {
+			return runs[i].Created.Before(runs[j].Created)
+		}
+		return runs[i].ID < runs[j].ID
+	})
+	return runs, nil
+}
+
+// Prune removes all but the newest keep runs under root and returns the removed runs
+func Prune(root string, keep int) ([]Run, error) {
+	if keep < 0 {
+		return nil, fmt.Errorf("cannot keep %d backups", keep)
+	}
+	runs, err := List(root)
+	if err != nil || len(runs) <= keep {
+		return nil, err
+	}
+
+	removed := runs[:len(runs)-keep]
+	for _, run := range removed {
+		if err := os.RemoveAll(run.Dir); err != nil {
+			return nil, fmt.Errorf("failed to remove backup %s: %w", run.ID, err)
+		}
+	}
+	return removed, nil
+}
+
+// Restore writes the files of the run with the given ID, or of the newest run when id is empty,
+// back to their original paths and returns the run
+func Restore(root, id string) (*Run, error) {
+	runs, err := List(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no backups in %s", root)
+	}
+
+	run := &runs[len(runs)-1]
+	if id != "" {
+		run = nil
+		for i := range runs {
+			if runs[i].ID == id {
+				run = &runs[i]
+			}
+		}
+		if run == nil {
+			return nil, fmt.Errorf("no backup %q in %s", id, root)
+		}
+	}
+
+	for mirrored, original := range run.Files {
+		data, err := os.ReadFile(filepath.Join(run.Dir, filepath.FromSlash(mirrored)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup of %s: %w", original, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(original), 0750); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(original, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", original, err)
+		}
+	}
+	return run, nil
+}
@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveListRestore(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "backups")
+	spec := filepath.Join(dir, "specs", "api.yaml")
+	if err := os.MkdirAll(filepath.Dir(spec), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(spec, []byte("changed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	run, err := Save(root, map[string][]byte{spec: []byte("original")}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.ID != "20260102T030405Z" || len(run.Files) != 1 {
+		t.Fatalf("unexpected run %+v", run)
+	}
+	again, err := Save(root, map[string][]byte{spec: []byte("second")}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != "20260102T030405Z-2" {
+		t.Errorf("expected a suffixed ID for a run in the same second, got %s", again.ID)
+	}
+
+	runs, err := List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ID != run.ID {
+		t.Fatalf("unexpected runs %+v", runs)
+	}
+
+	if _, err := Restore(root, run.ID); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(spec); string(data) != "original" {
+		t.Errorf("expected the original to be restored, got %q", data)
+	}
+	if _, err := Restore(root, ""); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(spec); string(data) != "second" {
+		t.Errorf("expected the newest run to be restored, got %q", data)
+	}
+	if _, err := Restore(root, "missing"); err == nil {
+		t.Error("expected an error for an unknown run")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "api.yaml")
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if _, err := Save(dir, map[string][]byte{spec: []byte("v")}, start.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := Prune(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 2 || removed[0].ID != "20260101T000000Z" {
+		t.Errorf("expected the two oldest runs to be removed, got %+v", removed)
+	}
+	runs, _ := List(dir)
+	if len(runs) != 1 || runs[0].ID != "20260101T020000Z" {
+		t.Errorf("expected the newest run to be kept, got %+v", runs)
+	}
+}
@@ -0,0 +1,232 @@
+// Package condition implements the operation conditions shared by the transformation steps:
+// path patterns, HTTP methods, tags and extension values.
+//
+// A path pattern is a glob or a regular expression. Patterns may say which with a glob: or
+// regex: prefix; unprefixed patterns use the syntax of the setting they belong to, so existing
+// configurations keep their meaning. Globs match a whole path segment by segment: * matches one
+// segment or part of one, ** any number of segments including none, ? one character and [...] a
+// character class. Path parameters match whatever they are named, so /users/{id} matches
+// /users/{user_id}. Regular expressions are unanchored; use ^ and $ to match the whole path.
+package condition
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Pattern syntaxes
+const (
+	Glob  = "glob"
+	Regex = "regex"
+)
+
+// Condition selects operations. Empty fields match every operation.
+type Condition struct {
+	Paths      []string          // path patterns, any of which must match
+	Methods    []string          // HTTP methods, case-insensitive; * matches any
+	Tags       []string          // the operation must carry any of these tags
+	Extensions map[string]string // extension -> value the operation must have; * for any value
+	Has        []string          // extensions the operation must carry, whatever their value
+	Missing    []string          // extensions the operation must not carry
+	Syntax     string            // syntax of unprefixed path patterns, Glob when empty
+
+	ExcludePaths   []string // path patterns, none of which may match
+	ExcludeMethods []string // HTTP methods the operation must not use
+}
+
+// Matches reports whether the operation at path and method matches every part of the condition
+func (c Condition) Matches(pathName, method string, operation *yaml.Node) bool {
+	return MatchPath(pathName, c.Paths, c.Syntax) &&
+		MatchMethod(method, c.Methods) &&
+		MatchTags(operation, c.Tags) &&
+		MatchExtensions(operation, c.Extensions) &&
+		MatchPresence(operation, c.Has, c.Missing) &&
+		!c.Excludes(pathName, method)
+}
+
+// Excludes reports whether the operation at path and method is excluded by the condition
+func (c Condition) Excludes(pathName, method string) bool {
+	return (len(c.ExcludePaths) > 0 && MatchPath(pathName, c.ExcludePaths, c.Syntax)) ||
+		(len(c.ExcludeMethods) > 0 && MatchMethod(method, c.ExcludeMethods))
+}
+
+// MatchPath reports whether pathName matches any of the patterns, or whether there are none
+func MatchPath(pathName string, patterns []string, syntax string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if MatchPattern(pathName, pattern, syntax) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPattern reports whether pathName matches a single pattern. Invalid patterns match nothing.
+func MatchPattern(pathName, pattern, syntax string) bool {
+	syntax, pattern = patternSyntax(pattern, syntax)
+	if syntax == Regex {
+		matched, err := regexp.MatchString(pattern, pathName)
+		return err == nil && matched
+	}
+	return matchGlob(pathName, pattern)
+}
+
+// matchGlob matches a path against a glob pattern segment by segment
+func matchGlob(pathName, pattern string) bool {
+	if pathName == pattern {
+		return true
+	}
+	return matchSegments(strings.Split(pathName, "/"), strings.Split(pattern, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where ** matches any number of
+// segments, including none
+func matchSegments(segments, patterns []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			for len(patterns) > 0 && patterns[0] == "**" {
+				patterns = patterns[1:]
+			}
+			if len(patterns) == 0 {
+				return true
+			}
+			for i := range segments {
+				if matchSegments(segments[i:], patterns) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 || !matchSegment(segments[0], patterns[0]) {
+			return false
+		}
+		segments, patterns = segments[1:], patterns[1:]
+	}
+	return len(segments) == 0
+}
+
+// matchSegment matches a single path segment. Path parameters match whatever they are named, so
+// /users/{id} matches /users/{user_id}.
+func matchSegment(segment, pattern string) bool {
+	segment, pattern = pathParam.ReplaceAllString(segment, "{}"), pathParam.ReplaceAllString(pattern, "{}")
+	if segment == pattern {
+		return true
+	}
+	matched, err := path.Match(pattern, segment)
+	return err == nil && matched
+}
+
+// pathParam matches a path template parameter such as {id}
+var pathParam = regexp.MustCompile(`\{[^{}/]*\}`)
+
+// patternSyntax strips a glob: or regex: prefix from a pattern and returns the syntax to read it with
+func patternSyntax(pattern, syntax string) (string, string) {
+	if rest, ok := strings.CutPrefix(pattern, Glob+":"); ok {
+		return Glob, rest
+	}
+	if rest, ok := strings.CutPrefix(pattern, Regex+":"); ok {
+		return Regex, rest
+	}
+	if syntax == "" {
+		syntax = Glob
+	}
+	return syntax, pattern
+}
+
+// ValidatePatterns checks that every pattern is a valid glob or regular expression
+func ValidatePatterns(patterns []string, syntax string) error {
+	for _, pattern := range patterns {
+		patternType, rest := patternSyntax(pattern, syntax)
+		if patternType == Regex {
+			if _, err := regexp.Compile(rest); err != nil {
+				return fmt.Errorf("invalid regular expression %q: %v", pattern, err)
+			}
+			continue
+		}
+		if _, err := path.Match(rest, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// MatchMethod reports whether method is one of methods, or whether there are none
+func MatchMethod(method string, methods []string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, m := range methods {
+		if m == "*" || strings.EqualFold(method, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchTags reports whether the operation carries any of the tags, or whether there are none
+func MatchTags(operation *yaml.Node, tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	operationTags := mappingValue(operation, "tags")
+	if operationTags == nil || operationTags.Kind != yaml.SequenceNode {
+		return false
+	}
+	for _, tag := range operationTags.Content {
+		for _, want := range tags {
+			if tag.Value == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MatchExtensions reports whether the operation has every extension with the wanted value. A
+// wanted value of * accepts any value; other values are compared with scalar extension values.
+func MatchExtensions(operation *yaml.Node, extensions map[string]string) bool {
+	for name, want := range extensions {
+		value := mappingValue(operation, name)
+		if value == nil {
+			return false
+		}
+		if want != "*" && (value.Kind != yaml.ScalarNode || value.Value != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchPresence reports whether the operation carries every extension of has and none of missing
+func MatchPresence(operation *yaml.Node, has, missing []string) bool {
+	for _, name := range has {
+		if mappingValue(operation, name) == nil {
+			return false
+		}
+	}
+	for _, name := range missing {
+		if mappingValue(operation, name) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
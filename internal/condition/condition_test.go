@@ -0,0 +1,120 @@
+package condition
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		pattern  string
+		syntax   string
+		expected bool
+	}{
+		{name: "wildcard pattern matches", path: "/api/v1/users", pattern: "/api/v1/*", expected: true},
+		{name: "wildcard matches a single segment", path: "/api/v1/users/{id}", pattern: "/api/v1/*", expected: false},
+		{name: "wildcard does not match the base path", path: "/api/v1", pattern: "/api/v1/*", expected: false},
+		{name: "double wildcard matches nested paths", path: "/api/v1/users/{id}", pattern: "/api/v1/**", expected: true},
+		{name: "double wildcard matches the base path", path: "/api/v1", pattern: "/api/v1/**", expected: true},
+		{name: "inner double wildcard", path: "/api/v1/users/{id}/roles", pattern: "/api/**/roles", expected: true},
+		{name: "inner double wildcard needs the suffix", path: "/api/v1/users/{id}", pattern: "/api/**/roles", expected: false},
+		{name: "path parameter names are ignored", path: "/users/{id}", pattern: "/users/{user_id}", expected: true},
+		{name: "path parameter inside a segment", path: "/files/{name}.json", pattern: "/files/{file}.json", expected: true},
+		{name: "path parameter does not match a literal", path: "/users/me", pattern: "/users/{id}", expected: false},
+		{name: "wildcard matches a path parameter", path: "/users/{id}/roles", pattern: "/users/*/roles", expected: true},
+		{name: "wildcard pattern does not match", path: "/other/path", pattern: "/api/v1/*", expected: false},
+		{name: "exact match", path: "/api/users", pattern: "/api/users", expected: true},
+		{name: "exact no match", path: "/api/users", pattern: "/api/posts", expected: false},
+		{name: "partial prefix without wildcard", path: "/api/v1/users", pattern: "/api/v1", expected: false},
+		{name: "inner wildcard stays within a segment", path: "/api/v1/users/{id}", pattern: "/api/*/users", expected: false},
+		{name: "inner wildcard", path: "/api/v2/users", pattern: "/api/*/users", expected: true},
+		{name: "regex is unanchored", path: "/api/v1/users", pattern: "users", syntax: Regex, expected: true},
+		{name: "anchored regex", path: "/api/v1/users", pattern: "^/users$", syntax: Regex, expected: false},
+		{name: "glob prefix overrides regex syntax", path: "/api/v1/users", pattern: "glob:/api/**", syntax: Regex, expected: true},
+		{name: "regex prefix overrides glob syntax", path: "/api/v1/users", pattern: "regex:^/api/v[0-9]+/", expected: true},
+		{name: "invalid regex matches nothing", path: "/api", pattern: "regex:(", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchPattern(tt.path, tt.pattern, tt.syntax); got != tt.expected {
+				t.Errorf("MatchPattern(%q, %q, %q) = %v, want %v", tt.path, tt.pattern, tt.syntax, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchPathWithoutPatterns(t *testing.T) {
+	if !MatchPath("/anything", nil, Glob) {
+		t.Error("expected no patterns to match every path")
+	}
+	if MatchPath("/users", []string{"/orders", "/items/*"}, Glob) {
+		t.Error("expected /users to match none of the patterns")
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	if err := ValidatePatterns([]string{"/api/*", "regex:^/v[0-9]+", "glob:/users"}, Glob); err != nil {
+		t.Errorf("expected valid patterns, got %v", err)
+	}
+	if err := ValidatePatterns([]string{"/api/["}, Glob); err == nil {
+		t.Error("expected an error for an invalid glob")
+	}
+	if err := ValidatePatterns([]string{"^/api/("}, Regex); err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+}
+
+func TestMatchMethod(t *testing.T) {
+	if !MatchMethod("get", []string{"GET"}) {
+		t.Error("expected methods to match case-insensitively")
+	}
+	if !MatchMethod("delete", []string{"*"}) {
+		t.Error("expected * to match any method")
+	}
+	if MatchMethod("post", []string{"get", "put"}) {
+		t.Error("expected post not to match")
+	}
+}
+
+func TestConditionMatches(t *testing.T) {
+	var operation yaml.Node
+	if err := yaml.Unmarshal([]byte("tags: [Users, Admin]\nx-beta: true\nx-owner: billing\n"), &operation); err != nil {
+		t.Fatal(err)
+	}
+	op := operation.Content[0]
+
+	tests := []struct {
+		name      string
+		condition Condition
+		expected  bool
+	}{
+		{name: "empty condition", condition: Condition{}, expected: true},
+		{name: "all parts match", condition: Condition{
+			Paths: []string{"/users/{user_id}"}, Methods: []string{"get"}, Tags: []string{"Admin"},
+			Extensions: map[string]string{"x-beta": "true", "x-owner": "*"},
+		}, expected: true},
+		{name: "tag missing", condition: Condition{Tags: []string{"Orders"}}, expected: false},
+		{name: "extension value differs", condition: Condition{Extensions: map[string]string{"x-owner": "search"}}, expected: false},
+		{name: "extension missing", condition: Condition{Extensions: map[string]string{"x-internal": "*"}}, expected: false},
+		{name: "method differs", condition: Condition{Methods: []string{"post"}}, expected: false},
+		{name: "has extension", condition: Condition{Has: []string{"x-beta"}}, expected: true},
+		{name: "has extension missing", condition: Condition{Has: []string{"x-beta", "x-curated"}}, expected: false},
+		{name: "missing extension", condition: Condition{Missing: []string{"x-fern-pagination"}}, expected: true},
+		{name: "missing extension present", condition: Condition{Missing: []string{"x-owner"}}, expected: false},
+		{name: "excluded path", condition: Condition{Paths: []string{"/users/**"}, ExcludePaths: []string{"/users/*"}}, expected: false},
+		{name: "other path excluded", condition: Condition{ExcludePaths: []string{"/orders/**"}}, expected: true},
+		{name: "excluded method", condition: Condition{Methods: []string{"*"}, ExcludeMethods: []string{"GET"}}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.condition.Matches("/users/{id}", "get", op); got != tt.expected {
+				t.Errorf("Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BatchManifest lists the jobs run by `openmorph batch`. Relative paths are resolved against the
+// manifest's directory.
+//
+// Example:
+//
+//	config: configs/base.yaml       # config for jobs that don't set their own
+//	jobs:
+//	  - name: users
+//	    input: specs/users.yaml
+//	    output: out/users.yaml      # file for a file input, directory for a directory input
+//	    profile: fern               # vendor provider to apply, or "none"
+//	  - name: billing
+//	    input: specs/billing
+//	    output: out/billing
+//	    config: configs/billing.yaml
+//	notify:                         # run summary of all jobs, same keys as the config file's notify
+//	  webhooks:
+//	    - url: ${SLACK_WEBHOOK_URL}
+//	      format: slack
+type BatchManifest struct {
+	Config string     `yaml:"config" json:"config"`
+	Jobs   []BatchJob `yaml:"jobs" json:"jobs"`
+	Notify Notify     `yaml:"notify" json:"notify"`
+}
+
+// BatchJob is one input→output transformation of a batch manifest
+type BatchJob struct {
+	Name    string `yaml:"name" json:"name"`       // defaults to the input path
+	Input   string `yaml:"input" json:"input"`     // spec file or directory
+	Output  string `yaml:"output" json:"output"`   // transformed in place when empty
+	Config  string `yaml:"config" json:"config"`   // overrides the manifest's config
+	Profile string `yaml:"profile" json:"profile"` // vendor provider applied to this job, all configured providers when empty
+}
+
+// LoadBatchManifest loads a batch manifest (YAML/JSON), resolves its paths and checks every job
+func LoadBatchManifest(path string) (*BatchManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &BatchManifest{}
+	if err := yaml.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %v", path, err)
+	}
+	if len(manifest.Jobs) == 0 {
+		return nil, errors.New("manifest has no jobs")
+	}
+
+	baseDir := filepath.Dir(path)
+	manifest.Config = resolveManifestPath(baseDir, manifest.Config)
+
+	names := make(map[string]bool)
+	for i := range manifest.Jobs {
+		job := &manifest.Jobs[i]
+		if job.Input == "" {
+			return nil, fmt.Errorf("jobs[%d]: input is required", i)
+		}
+		if job.Name == "" {
+			job.Name = job.Input
+		}
+		if names[job.Name] {
+			return nil, fmt.Errorf("jobs[%d]: duplicate job name %q", i, job.Name)
+		}
+		names[job.Name] = true
+
+		job.Input = resolveManifestPath(baseDir, job.Input)
+		job.Output = resolveManifestPath(baseDir, job.Output)
+		job.Config = resolveManifestPath(baseDir, job.Config)
+		if job.Config == "" {
+			job.Config = manifest.Config
+		}
+	}
+
+	return manifest, nil
+}
+
+// LoadConfigFile loads a single config file (YAML/JSON) without CLI overrides or .openapirc.yaml
+func LoadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	cfg.Source = path
+	return cfg, nil
+}
+
+// resolveManifestPath resolves a manifest path relative to the manifest's directory
+func resolveManifestPath(baseDir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadBatchManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "jobs.yaml")
+	manifest := `config: configs/base.yaml
+jobs:
+  - input: specs/users.yaml
+    output: out/users.yaml
+    profile: fern
+  - name: billing
+    input: /abs/billing
+    config: configs/billing.yaml
+`
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0600); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	m, err := LoadBatchManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("LoadBatchManifest failed: %v", err)
+	}
+	if len(m.Jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(m.Jobs))
+	}
+
+	users := m.Jobs[0]
+	if users.Name != "specs/users.yaml" {
+		t.Errorf("expected name to default to the input, got %q", users.Name)
+	}
+	if users.Input != filepath.Join(dir, "specs/users.yaml") || users.Output != filepath.Join(dir, "out/users.yaml") {
+		t.Errorf("expected paths relative to the manifest, got %+v", users)
+	}
+	if users.Config != filepath.Join(dir, "configs/base.yaml") || users.Profile != "fern" {
+		t.Errorf("expected manifest config and profile, got %+v", users)
+	}
+
+	billing := m.Jobs[1]
+	if billing.Input != "/abs/billing" || billing.Output != "" {
+		t.Errorf("expected absolute input and in-place output, got %+v", billing)
+	}
+	if billing.Config != filepath.Join(dir, "configs/billing.yaml") {
+		t.Errorf("expected job config to override the manifest config, got %q", billing.Config)
+	}
+}
+
+func TestLoadBatchManifestErrors(t *testing.T) {
+	tests := []struct {
+		name, manifest, want string
+	}{
+		{"no jobs", "jobs: []\n", "no jobs"},
+		{"missing input", "jobs:\n  - name: a\n", "jobs[0]: input is required"},
+		{"duplicate name", "jobs:\n  - input: a.yaml\n  - input: a.yaml\n", "duplicate job name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "jobs.yaml")
+			if err := os.WriteFile(path, []byte(tt.manifest), 0600); err != nil {
+				t.Fatalf("failed to write manifest: %v", err)
+			}
+			_, err := LoadBatchManifest(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
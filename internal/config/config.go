@@ -9,17 +9,239 @@ import (
 
 // Config represents the complete OpenMorph configuration
 type Config struct {
-	Input              string                   `yaml:"input" json:"input"`
-	Output             string                   `yaml:"output" json:"output"`
-	Backup             bool                     `yaml:"backup" json:"backup"`
-	Validate           bool                     `yaml:"validate" json:"validate"`
-	Exclude            []string                 `yaml:"exclude" json:"exclude"`
-	Mappings           map[string]string        `yaml:"mappings" json:"mappings"`
-	PaginationPriority []string                 `yaml:"pagination_priority" json:"pagination_priority"` // Global pagination strategy priority
-	EndpointPagination []EndpointPaginationRule `yaml:"endpoint_pagination" json:"endpoint_pagination"` // Endpoint-specific pagination overrides
-	FlattenResponses   bool                     `yaml:"flatten_responses" json:"flatten_responses"`
-	VendorExtensions   VendorExtensions         `yaml:"vendor_extensions" json:"vendor_extensions"`
-	DefaultValues      DefaultValues            `yaml:"default_values" json:"default_values"`
+	Input                string                       `yaml:"input" json:"input"`                                                   // spec file or directory to transform, overridden by --input
+	Output               string                       `yaml:"output" json:"output"`                                                 // file the result of a single-file input is written to instead of the input, overridden by --output
+	Backup               bool                         `yaml:"backup" json:"backup"`                                                 // keep the original of every file a run overwrites, same as --backup
+	Backups              Backups                      `yaml:"backups" json:"backups"`                                               // how --backup keeps the originals
+	Validate             bool                         `yaml:"validate" json:"validate"`                                             // validate the specs with swagger-cli after the run, same as --validate
+	Exclude              []string                     `yaml:"exclude" json:"exclude"`                                               // keys excluded from transformation, same as --exclude
+	Mappings             map[string]string            `yaml:"mappings" json:"mappings"`                                             // key -> replacement renamed everywhere in the specs; dotted keys move a value to another path
+	MappingCollisions    string                       `yaml:"mapping_collisions" json:"mapping_collisions" default:"keep_existing"` // keep_existing (default), overwrite, merge or error when a mapping's target key exists
+	ExtensionValues      map[string]map[string]string `yaml:"extension_values" json:"extension_values"`                             // extension -> value -> replacement rewritten everywhere in the specs, after mappings
+	PaginationPriority   []string                     `yaml:"pagination_priority" json:"pagination_priority"`                       // Global pagination strategy priority
+	EndpointPagination   []EndpointPaginationRule     `yaml:"endpoint_pagination" json:"endpoint_pagination"`                       // Endpoint-specific pagination overrides
+	PaginationCleanup    PaginationCleanup            `yaml:"pagination_cleanup" json:"pagination_cleanup"`
+	SharedFields         map[string]SharedFieldRule   `yaml:"pagination_shared_fields" json:"pagination_shared_fields"` // selected strategy -> response fields always kept or removed
+	ResultsFields        map[string]string            `yaml:"results_fields" json:"results_fields"`                     // path pattern -> response property holding the results, overriding auto-detection
+	PageComponents       PaginationComponents         `yaml:"pagination_components" json:"pagination_components"`
+	PaginationMetadata   PaginationMetadata           `yaml:"pagination_metadata" json:"pagination_metadata"`
+	PaginationHeaders    PaginationHeaders            `yaml:"pagination_headers" json:"pagination_headers"`
+	ComponentNaming      ComponentNaming              `yaml:"component_naming" json:"component_naming"`                   // how generated components are named
+	FlattenResponses     bool                         `yaml:"flatten_responses" json:"flatten_responses"`                 // flatten oneOf/anyOf/allOf with a single $ref after pagination processing, same as --flatten-responses
+	FlattenExclude       []string                     `yaml:"flatten_exclude" json:"flatten_exclude"`                     // component schemas never flattened or chain-collapsed
+	FlattenAllOf         FlattenAllOf                 `yaml:"flatten_allof" json:"flatten_allof"`                         // merge allOf: [$ref] with sibling properties
+	FlattenMetadata      string                       `yaml:"flatten_metadata" json:"flatten_metadata" default:"wrapper"` // whose title, description, deprecated and extensions win when a single-member composition is collapsed: wrapper (default) or target
+	VendorExtensions     VendorExtensions             `yaml:"vendor_extensions" json:"vendor_extensions"`
+	DefaultValues        DefaultValues                `yaml:"default_values" json:"default_values"`
+	ComponentRenames     ComponentRenames             `yaml:"component_renames" json:"component_renames"`
+	ComponentDedup       ComponentDedup               `yaml:"component_dedup" json:"component_dedup"`
+	SchemaTitles         SchemaTitles                 `yaml:"schema_titles" json:"schema_titles"`                 // titles of component schemas filled in from their names
+	Consistency          ComponentConsistency         `yaml:"component_consistency" json:"component_consistency"` // identically named components that differ between files
+	StripInternal        StripInternal                `yaml:"strip_internal" json:"strip_internal"`
+	PathVariants         PathVariants                 `yaml:"path_variants" json:"path_variants"` // operations duplicated under /users and /users/
+	TenantPaths          TenantPaths                  `yaml:"tenant_paths" json:"tenant_paths"`   // /{tenantId} prefix added to or removed from every path
+	ParameterInjection   ParameterInjection           `yaml:"parameter_injection" json:"parameter_injection"`
+	UnwrapEnvelopes      UnwrapEnvelopes              `yaml:"unwrap_envelopes" json:"unwrap_envelopes"`
+	SchemaConstraints    SchemaConstraints            `yaml:"schema_constraints" json:"schema_constraints"`
+	Nullability          Nullability                  `yaml:"nullability" json:"nullability"`
+	Examples             Examples                     `yaml:"examples" json:"examples"`
+	Canonicalize         Canonicalize                 `yaml:"canonicalize" json:"canonicalize"`
+	ExtensionSchemas     map[string]ExtensionSchema   `yaml:"extension_schemas" json:"extension_schemas"` // extension name -> schema its values must match
+	Outputs              []OutputVariant              `yaml:"outputs" json:"outputs"`                     // multiple output variants generated in one run
+	OutputFormats        []string                     `yaml:"output_formats" json:"output_formats"`       // serializations (yaml, json) every transformed document is also written in
+	AsyncAPI             AsyncAPI                     `yaml:"asyncapi" json:"asyncapi"`
+	Notify               Notify                       `yaml:"notify" json:"notify"`
+	Files                FileFilter                   `yaml:"files" json:"files"`                                   // which files under the input the steps process
+	Protect              Protect                      `yaml:"protect" json:"protect"`                               // items no step may modify or delete
+	RefCheck             RefCheck                     `yaml:"ref_check" json:"ref_check"`                           // dangling $ref check after destructive steps
+	EmptySchemas         EmptySchemas                 `yaml:"empty_schemas" json:"empty_schemas"`                   // empty schema check after destructive steps
+	PruneEmpty           PruneEmpty                   `yaml:"prune_empty" json:"prune_empty"`                       // empty path item pruning after destructive steps
+	PaginationInvariants PaginationInvariants         `yaml:"pagination_invariants" json:"pagination_invariants"`   // pagination checks after the pipeline
+	OnlyPaths            []string                     `yaml:"only_paths" json:"only_paths"`                         // glob patterns limiting pagination, vendor extensions, defaults and flattening to matching paths
+	Provenance           Provenance                   `yaml:"provenance" json:"provenance"`                         // stamp changed documents with the pipeline that produced them
+	MaxChangedOperations int                          `yaml:"max_changed_operations" json:"max_changed_operations"` // stop a run that would change more operations than this, same as --max-changed-operations
+	ExtensionPlacement   string                       `yaml:"extension_placement" json:"extension_placement"`       // where steps insert extensions into operations: end (default), top, after_operation_id or alphabetical
+	Source               string                       `yaml:"-" json:"-"`                                           // config file the settings were loaded from, if any
+}
+
+// FlattenAllOf configures merging `allOf: [$ref]` with sibling properties, or with inline members
+// next to the reference, into one schema when responses are flattened
+//
+// Example:
+//
+//	flatten_allof:
+//	  enabled: true
+//	  mode: inline   # inline copies the referenced schema in, preserve keeps the $ref and moves the siblings next to it
+type FlattenAllOf struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"`
+	Mode    string `yaml:"mode" json:"mode" default:"inline"` // inline (default) or preserve
+}
+
+// RefCheck configures the $ref check that runs after every step that removes, renames or replaces
+// content, reporting the references the step left dangling
+//
+// Example:
+//
+//	ref_check:
+//	  disabled: true   # same as --no-ref-check
+type RefCheck struct {
+	Disabled bool `yaml:"disabled" json:"disabled"`
+}
+
+// EmptySchemas configures the check that runs after every step that removes, renames or replaces
+// content, reporting the object schemas left without properties and the responses left with an
+// empty content
+//
+// Example:
+//
+//	empty_schemas:
+//	  fix: true        # add additionalProperties: true to the objects and remove the empty contents
+//	  disabled: false
+type EmptySchemas struct {
+	Disabled bool `yaml:"disabled" json:"disabled"`
+	Fix      bool `yaml:"fix" json:"fix"`
+}
+
+// PruneEmpty configures the pruning that runs after every step that removes, renames or replaces
+// content, removing the path items left without operations, the request bodies left without
+// content and the parameters lists left empty. It is on by default.
+//
+// Example:
+//
+//	prune_empty:
+//	  disabled: true
+type PruneEmpty struct {
+	Disabled bool `yaml:"disabled" json:"disabled"`
+}
+
+// PaginationInvariants configures the check that runs after the pipeline, reporting operations whose
+// pagination the pagination and vendor extension steps left inconsistent: parameters of several
+// strategies, a pagination extension naming a parameter that does not exist, or response fields
+// of a strategy that was not selected
+//
+// Example:
+//
+//	pagination_invariants:
+//	  fail: true       # fail the run instead of warning
+//	  disabled: false
+type PaginationInvariants struct {
+	Disabled bool `yaml:"disabled" json:"disabled"`
+	Fail     bool `yaml:"fail" json:"fail"`
+}
+
+// Provenance stamps every document a run changed with the tool version, a hash of the config and
+// the time of the run, along with a checksum of the document so later edits can be detected
+//
+// Example:
+//
+//	provenance:
+//	  enabled: true
+//	  mode: sidecar   # extension (default) adds an x-openmorph root key, sidecar writes <file>.provenance next to the document
+type Provenance struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"`
+	Mode    string `yaml:"mode" json:"mode" default:"extension"`
+}
+
+// Protect lists the items no step may modify or delete; changes to them are undone after each
+// step and reported
+//
+// Example:
+//
+//	protect:
+//	  paths: ["/billing/**"]            # path items, glob patterns as in --paths
+//	  components: [Money, schemas/Page] # component names, in any section or as section/name
+//	  extensions: [x-internal-id]       # extension keys, wherever they occur
+type Protect struct {
+	Paths      []string `yaml:"paths" json:"paths"`
+	Components []string `yaml:"components" json:"components"`
+	Extensions []string `yaml:"extensions" json:"extensions"`
+}
+
+// Backups selects how --backup keeps the original of each file a run overwrites
+//
+// Example:
+//
+//	backups:
+//	  naming: timestamped        # "bak" (default): file.bak next to the file, overwritten each run
+//	  dir: .openmorph/backups    # where timestamped runs are kept, one <run-id>/ folder each
+//	  max_backups: 10            # runs kept after each run, 0 keeps all
+type Backups struct {
+	Naming     string `yaml:"naming" json:"naming" default:"bak"`
+	Dir        string `yaml:"dir" json:"dir" default:".openmorph/backups"`
+	MaxBackups int    `yaml:"max_backups" json:"max_backups"`
+}
+
+// FileFilter selects the files under a directory input that the pipeline steps process. Patterns
+// are matched against slash-separated paths relative to the input directory; `**` matches any
+// number of directories and a pattern without a `/` matches the file name at any depth.
+//
+// Example:
+//
+//	files:
+//	  include: ["specs/**"]            # only files matching one of these (default: every YAML/JSON file)
+//	  exclude: ["**/fixtures/**", ".github/**"]
+//	  steps:                           # per-step overrides, keyed by step name
+//	    vendor_extensions:
+//	      exclude: ["specs/internal/**"]   # replaces the top-level exclude for this step only
+type FileFilter struct {
+	Include []string                `yaml:"include" json:"include"`
+	Exclude []string                `yaml:"exclude" json:"exclude"`
+	Steps   map[string]FilePatterns `yaml:"steps" json:"steps"`
+}
+
+// FilePatterns are the include and exclude patterns of one step. A list that is set replaces the
+// corresponding top-level list; an unset list inherits it.
+type FilePatterns struct {
+	Include []string `yaml:"include" json:"include"`
+	Exclude []string `yaml:"exclude" json:"exclude"`
+}
+
+// ForStep returns the patterns that apply to the named step
+func (f FileFilter) ForStep(step string) FilePatterns {
+	patterns := FilePatterns{Include: f.Include, Exclude: f.Exclude}
+	if override, ok := f.Steps[step]; ok {
+		if override.Include != nil {
+			patterns.Include = override.Include
+		}
+		if override.Exclude != nil {
+			patterns.Exclude = override.Exclude
+		}
+	}
+	return patterns
+}
+
+// Notify configuration for posting a run summary to webhooks after the pipeline runs
+//
+// Example:
+//
+//	notify:
+//	  webhooks:
+//	    - url: ${SLACK_WEBHOOK_URL}   # environment variables are expanded in url and headers
+//	      format: slack               # "json" (default) posts the summary object, "slack" a {"text": ...} message
+//	      on: changes                 # "always" (default), "changes" or "failure"
+//	    - url: https://ci.example.com/hooks/openmorph
+//	      headers:
+//	        Authorization: Bearer ${CI_TOKEN}
+type Notify struct {
+	Webhooks []Webhook `yaml:"webhooks" json:"webhooks"`
+}
+
+// Webhook is one notification target
+type Webhook struct {
+	URL     string            `yaml:"url" json:"url"`
+	Format  string            `yaml:"format" json:"format" default:"json"` // "json" or "slack", defaults to "json"
+	On      string            `yaml:"on" json:"on" default:"always"`       // "always", "changes" or "failure", defaults to "always"
+	Headers map[string]string `yaml:"headers" json:"headers"`              // extra request headers
+}
+
+// AsyncAPI configuration for running document-agnostic steps on AsyncAPI documents as well
+//
+// Example:
+//
+//	asyncapi:
+//	  enabled: true   # strip_internal and default_values also process AsyncAPI 2.x/3.x documents
+type AsyncAPI struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
 }
 
 // EndpointPaginationRule defines pagination configuration for specific endpoints
@@ -27,7 +249,7 @@ type Config struct {
 //
 // Example:
 //
-//	endpoint: "/api/v1/users/*"  # Supports wildcard patterns
+//	endpoint: "/api/v1/users/**" # Supports wildcard patterns
 //	method: "GET"                # HTTP method (case-insensitive)
 //	pagination: "cursor"         # Strategy: cursor, offset, page, checkpoint, none
 type EndpointPaginationRule struct {
@@ -37,25 +259,86 @@ type EndpointPaginationRule struct {
 }
 
 // VendorExtensions configuration for adding vendor-specific extensions
+//
+// Example:
+//
+//	vendor_extensions:
+//	  enabled: true
+//	  providers:
+//	    fern:
+//	      extension_name: x-fern-pagination
+//	      target_level: operation
+//	      methods: [get]
+//	      field_mapping:
+//	        request_params:
+//	          cursor: [cursor, after]
+//	          limit: [limit, size]
+//	      strategies:
+//	        cursor:
+//	          template:
+//	            type: cursor
+//	            cursor_param: $request.{cursor_param}
+//	            page_size_param: $request.{limit_param}
+//	            results_path: $response.{results_field}
+//	          required_fields: [cursor_param, results_field]
 type VendorExtensions struct {
 	Enabled   bool                      `yaml:"enabled" json:"enabled"`
 	Providers map[string]ProviderConfig `yaml:"providers" json:"providers"`
+	Required  bool                      `yaml:"required" json:"required"`                                   // fail the run when operations are skipped for a reason in fail_on
+	FailOn    []string                  `yaml:"fail_on" json:"fail_on" default:"[missing_required_fields]"` // skip codes that fail a required run
 }
 
 // ProviderConfig defines configuration for a specific provider
 type ProviderConfig struct {
-	ExtensionName string                    `yaml:"extension_name" json:"extension_name"`
-	TargetLevel   string                    `yaml:"target_level" json:"target_level"`   // "operation", "path", "schema"
-	Methods       []string                  `yaml:"methods" json:"methods"`             // ["get", "post"] or empty for all
-	PathPatterns  []string                  `yaml:"path_patterns" json:"path_patterns"` // ["/api/v1/*"] or empty for all
-	FieldMapping  FieldMapping              `yaml:"field_mapping" json:"field_mapping"`
-	Strategies    map[string]StrategyConfig `yaml:"strategies" json:"strategies"`
+	ExtensionName    string                    `yaml:"extension_name" json:"extension_name"`
+	Mode             string                    `yaml:"mode" json:"mode" default:"pagination"`      // "pagination" (default) or "tag_group"
+	TargetLevel      string                    `yaml:"target_level" json:"target_level"`           // "operation", "path", "schema"
+	Methods          []string                  `yaml:"methods" json:"methods"`                     // ["get", "post"] or empty for all
+	PathPatterns     []string                  `yaml:"path_patterns" json:"path_patterns"`         // ["/api/v1/**"] or empty for all
+	ExcludeMethods   []string                  `yaml:"exclude_methods" json:"exclude_methods"`     // methods left alone even if methods matches
+	ExcludePaths     []string                  `yaml:"exclude_paths" json:"exclude_paths"`         // ["/api/v1/exports/**"], paths left alone even if path_patterns matches
+	Tags             []string                  `yaml:"tags" json:"tags"`                           // operations carrying any of these tags, or empty for all
+	Extensions       map[string]string         `yaml:"extensions" json:"extensions"`               // extension -> value operations must have, "*" for any value
+	HasExtension     StringList                `yaml:"has_extension" json:"has_extension"`         // operations carrying all of these extensions
+	MissingExtension StringList                `yaml:"missing_extension" json:"missing_extension"` // operations carrying none of these extensions
+	MediaTypes       []string                  `yaml:"media_types" json:"media_types"`             // ["application/json"] or empty for all; matched against 2xx response content
+	FieldMapping     FieldMapping              `yaml:"field_mapping" json:"field_mapping"`
+	Strategies       map[string]StrategyConfig `yaml:"strategies" json:"strategies"`
+	TagGroup         TagGroup                  `yaml:"tag_group" json:"tag_group"` // settings of the tag_group mode
+}
+
+// TagGroup configures the tag_group provider mode, which sets an SDK grouping extension such as
+// x-fern-sdk-group-name from each operation's first tag
+//
+// Example:
+//
+//	providers:
+//	  fern-groups:
+//	    mode: tag_group
+//	    extension_name: x-fern-sdk-group-name
+//	    target_level: operation          # or "path"
+//	    tag_group:
+//	      casing: snake                  # snake, kebab, camel, pascal, or empty to keep the tag
+//	      overrides:
+//	        "Billing & Invoices": billing
+type TagGroup struct {
+	Casing    string            `yaml:"casing" json:"casing"`
+	Overrides map[string]string `yaml:"overrides" json:"overrides"` // tag -> group name, used as written
 }
 
 // FieldMapping defines how to map request/response fields
 type FieldMapping struct {
 	RequestParams  map[string][]string `yaml:"request_params" json:"request_params"`
 	ResponseFields map[string][]string `yaml:"response_fields" json:"response_fields"`
+	ResultsRanking ResultsRanking      `yaml:"results_ranking" json:"results_ranking"`
+}
+
+// ResultsRanking ranks the response array fields {results_field} is auto-detected from when
+// response_fields does not name it: arrays of objects or $refs before arrays of scalars, then the
+// preferred names in order, then the other fields in schema order, and the ignored names last
+type ResultsRanking struct {
+	Preferred []string `yaml:"preferred" json:"preferred" default:"[data, items, results, {resource}]"` // {resource} is the last static segment of the path
+	Ignored   []string `yaml:"ignored" json:"ignored" default:"[errors, warnings, messages]"`
 }
 
 // StrategyConfig defines the template for a pagination strategy
@@ -63,12 +346,61 @@ type StrategyConfig struct {
 	Template       map[string]interface{} `yaml:"template" json:"template"`
 	RequiredFields []string               `yaml:"required_fields" json:"required_fields"`
 	OptionalFields []string               `yaml:"optional_fields" json:"optional_fields"`
+	TemplateNode   *yaml.Node             `yaml:"-" json:"-"` // template as written, so extensions keep its key order
+}
+
+// UnmarshalYAML decodes a strategy and keeps the template node, whose key order Template loses
+func (s *StrategyConfig) UnmarshalYAML(value *yaml.Node) error {
+	type plain StrategyConfig
+	if err := value.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if value.Content[i].Value == "template" && value.Content[i+1].Kind == yaml.MappingNode {
+			s.TemplateNode = value.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// StringList is a list of strings that may also be written as a single string
+type StringList []string
+
+// UnmarshalYAML decodes a single string or a list of strings
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = StringList{value.Value}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
 }
 
 // DefaultValues configuration for setting defaults in OpenAPI specs
+//
+// Example:
+//
+//	default_values:
+//	  enabled: true
+//	  rules:
+//	    query_limit_defaults:
+//	      target:
+//	        location: parameter
+//	      condition:
+//	        parameter_in: query
+//	        type: integer
+//	        property_name: "(limit|size|page_size|per_page)"
+//	      value: 20
+//	      priority: 10   # higher priority rules win when several match
 type DefaultValues struct {
-	Enabled bool                   `yaml:"enabled" json:"enabled"`
-	Rules   map[string]DefaultRule `yaml:"rules" json:"rules"`
+	Enabled  bool                   `yaml:"enabled" json:"enabled"`
+	Rules    map[string]DefaultRule `yaml:"rules" json:"rules"`
+	Required bool                   `yaml:"required" json:"required"`                                       // fail the run when targets are skipped for a reason in fail_on
+	FailOn   []string               `yaml:"fail_on" json:"fail_on" default:"[invalid_value, invalid_rule]"` // skip codes that fail a required run
 }
 
 // DefaultRule defines a rule for setting default values
@@ -82,21 +414,428 @@ type DefaultRule struct {
 
 // DefaultTarget specifies where the default should be applied
 type DefaultTarget struct {
-	Location string `yaml:"location" json:"location"` // "parameter", "request_body", "response", "component", "array", "enum"
-	Property string `yaml:"property" json:"property"` // specific property name (optional)
+	Location string `yaml:"location" json:"location"` // "parameter", "request_body", "response", "component", "server_variable", "security_scheme", "message" (AsyncAPI), "array", "enum"
+	Property string `yaml:"property" json:"property"` // specific property name (optional); the field to fill for security_scheme
 	Path     string `yaml:"path" json:"path"`         // JSONPath-like selector (optional)
 }
 
 // DefaultCondition specifies when the default should be applied
 type DefaultCondition struct {
-	Type         string   `yaml:"type" json:"type"`                   // type constraint (e.g., "string", "integer", "boolean")
-	ParameterIn  string   `yaml:"parameter_in" json:"parameter_in"`   // for parameters: "query", "path", "header", "cookie"
-	HTTPMethods  []string `yaml:"http_methods" json:"http_methods"`   // which HTTP methods to target
-	PathPatterns []string `yaml:"path_patterns" json:"path_patterns"` // which API paths to target
-	HasEnum      bool     `yaml:"has_enum" json:"has_enum"`           // only apply if field has enum values
-	IsArray      bool     `yaml:"is_array" json:"is_array"`           // only apply if field is array
-	PropertyName string   `yaml:"property_name" json:"property_name"` // match specific property names
-	Required     *bool    `yaml:"required" json:"required"`           // apply only to required/optional fields
+	Type             string            `yaml:"type" json:"type"`                           // type constraint (e.g., "string", "integer", "boolean")
+	Format           string            `yaml:"format" json:"format"`                       // format constraint (e.g., "int32", "date-time")
+	ParameterIn      string            `yaml:"parameter_in" json:"parameter_in"`           // for parameters: "query", "path", "header", "cookie"
+	HTTPMethods      []string          `yaml:"http_methods" json:"http_methods"`           // which HTTP methods to target
+	PathPatterns     []string          `yaml:"path_patterns" json:"path_patterns"`         // which API paths to target
+	HasEnum          bool              `yaml:"has_enum" json:"has_enum"`                   // only apply if field has enum values
+	IsArray          bool              `yaml:"is_array" json:"is_array"`                   // only apply if field is array
+	PropertyName     string            `yaml:"property_name" json:"property_name"`         // match specific property names
+	Required         *bool             `yaml:"required" json:"required"`                   // apply only to required/optional fields
+	Tags             []string          `yaml:"tags" json:"tags"`                           // operations carrying any of these tags
+	Extensions       map[string]string `yaml:"extensions" json:"extensions"`               // operations having these extension values, "*" for any value
+	HasExtension     StringList        `yaml:"has_extension" json:"has_extension"`         // operations carrying all of these extensions
+	MissingExtension StringList        `yaml:"missing_extension" json:"missing_extension"` // operations carrying none of these extensions
+}
+
+// SchemaConstraints configuration for normalizing numeric and string constraints of schemas
+//
+// Example:
+//
+//	schema_constraints:
+//	  enabled: true
+//	  exclusive_bounds: auto           # "3.0", "3.1", or "auto" to match each document's version
+//	  rules:
+//	    bounded_strings:
+//	      condition: {type: string}
+//	      set: {maxLength: 255}        # filled in only where missing
+//	    no_patterns_for_fern:
+//	      providers: [fern]            # only when these vendor providers are applied
+//	      strip: [pattern]
+type SchemaConstraints struct {
+	Enabled         bool                      `yaml:"enabled" json:"enabled"`
+	ExclusiveBounds string                    `yaml:"exclusive_bounds" json:"exclusive_bounds"` // empty leaves exclusiveMinimum/exclusiveMaximum as they are
+	Rules           map[string]ConstraintRule `yaml:"rules" json:"rules"`
+}
+
+// ConstraintRule sets missing constraints on, and strips unsupported constraints from, the schemas
+// matching its condition
+type ConstraintRule struct {
+	Condition DefaultCondition       `yaml:"condition" json:"condition"`
+	Set       map[string]interface{} `yaml:"set" json:"set"`             // constraints added when the schema does not have them
+	Strip     []string               `yaml:"strip" json:"strip"`         // constraints removed from the schema
+	Providers []string               `yaml:"providers" json:"providers"` // vendor providers the rule is limited to, empty for all runs
+	Priority  int                    `yaml:"priority" json:"priority"`
+}
+
+// Nullability configuration for enforcing one nullability policy across inline and component schemas
+//
+// Example:
+//
+//	nullability:
+//	  enabled: true
+//	  optional_properties: nullable   # mark every optional object property nullable
+//	  form: auto                      # "3.0" (nullable: true), "3.1" (type: [T, "null"]) or "auto"
+type Nullability struct {
+	Enabled            bool   `yaml:"enabled" json:"enabled"`
+	OptionalProperties string `yaml:"optional_properties" json:"optional_properties"` // "nullable" or empty to leave them alone
+	Strip              bool   `yaml:"strip" json:"strip"`                             // remove nullable and "null" types entirely
+	Form               string `yaml:"form" json:"form"`                               // empty keeps nullability as written
+}
+
+// PaginationCleanup configuration for tidying operations whose pagination is removed by the "none"
+// strategy. Empty parameter lists are always dropped; descriptions are only scrubbed when enabled.
+//
+// Example:
+//
+//	pagination_cleanup:
+//	  scrub_descriptions: true
+//	  description_patterns: ["(?i)results are paged"]   # empty uses the built-in paging vocabulary
+type PaginationCleanup struct {
+	ScrubDescriptions   bool     `yaml:"scrub_descriptions" json:"scrub_descriptions"`
+	DescriptionPatterns []string `yaml:"description_patterns" json:"description_patterns"` // regular expressions matched against each sentence
+}
+
+// PaginationComponents configuration for replacing the inline paginated response schemas left by
+// the pagination step with generated envelope components, one per strategy and item schema
+//
+// Example:
+//
+//	pagination_components:
+//	  enabled: true
+//	  name_template: "{Strategy}Page_{Item}"   # default; {strategy} is the lowercase strategy name
+type PaginationComponents struct {
+	Enabled      bool   `yaml:"enabled" json:"enabled"`
+	NameTemplate string `yaml:"name_template" json:"name_template" default:"{Strategy}Page_{Item}"`
+}
+
+// PaginationMetadata configures recording the pagination strategy each operation is left with by
+// the pagination step as a vendor-neutral extension holding the strategy name and the parameters
+// and response fields it uses, for in-house tooling that reads the detection results from the spec
+//
+// Example:
+//
+//	pagination_metadata:
+//	  enabled: true
+//	  extension: x-pagination   # default
+type PaginationMetadata struct {
+	Enabled   bool   `yaml:"enabled" json:"enabled"`
+	Extension string `yaml:"extension" json:"extension" default:"x-pagination"` // extension set on each paginated operation
+}
+
+// PaginationHeaders configures adding the response headers an API documents its pagination with,
+// such as Link or X-Next-Page, to the success responses of the operations each strategy is left
+// on, for specs that omit them. Header definitions are templates: {strategy} is the strategy name,
+// {param} the parameter of the operation selecting the page and {param_type} the type of its
+// schema. Strategies without headers of their own get the default ones.
+//
+// Example:
+//
+//	pagination_headers:
+//	  enabled: true
+//	  headers:
+//	    cursor:
+//	      X-Next-Cursor:
+//	        description: Value of the {param} parameter for the next page
+//	        schema: {type: "{param_type}"}
+//	    page:
+//	      Link:
+//	        description: RFC 8288 links to the next and previous pages
+//	        schema: {type: string}
+type PaginationHeaders struct {
+	Enabled bool                                         `yaml:"enabled" json:"enabled"`
+	Headers map[string]map[string]map[string]interface{} `yaml:"headers" json:"headers"` // strategy -> header name -> header object
+}
+
+// ComponentNaming configures how the components generated by the steps are named, after their
+// name template is rendered
+//
+// Example:
+//
+//	component_naming:
+//	  casing: pascal            # pascal, camel, snake, kebab, or empty to keep the rendered name
+//	  reserved_words: [Page]    # names to avoid on top of the built-in ones such as Object and Error
+//	  reserved_suffix: Model    # appended to reserved names, defaults to Model
+//	  collision_suffix: "{n}"   # appended when the name holds a different component, e.g. CursorPage_User2
+type ComponentNaming struct {
+	Casing          string   `yaml:"casing" json:"casing"`
+	ReservedWords   []string `yaml:"reserved_words" json:"reserved_words"`
+	ReservedSuffix  string   `yaml:"reserved_suffix" json:"reserved_suffix" default:"Model"`
+	CollisionSuffix string   `yaml:"collision_suffix" json:"collision_suffix"` // must contain {n}; empty keeps the envelope inline on a collision
+}
+
+// SharedFieldRule pins which response fields are kept or removed when a strategy is selected.
+// Fields such as total or limit belong to several strategies, and without a rule their fate is
+// guessed from the sibling fields of each schema.
+//
+// Example:
+//
+//	pagination_shared_fields:
+//	  page:
+//	    keep: [total]
+//	  offset:
+//	    remove: [total]
+type SharedFieldRule struct {
+	Keep   []string `yaml:"keep" json:"keep"`
+	Remove []string `yaml:"remove" json:"remove"`
+}
+
+// Examples configuration for repairing example and examples blocks that no longer match their
+// schema, typically after pagination cleanup or flattening removed fields
+//
+// Example:
+//
+//	examples:
+//	  enabled: true
+//	  mode: prune   # "prune" drops the invalid parts, "regenerate" replaces invalid examples with minimal ones
+type Examples struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"`
+	Mode    string `yaml:"mode" json:"mode" default:"prune"` // empty prunes
+}
+
+// Canonicalize configuration for sorting document sections into a stable order, so specs that are
+// regenerated from code produce minimal diffs
+//
+// Example:
+//
+//	canonicalize:
+//	  enabled: true
+//	  operation_key_order: [summary, description, operationId, parameters, requestBody, responses]
+type Canonicalize struct {
+	Enabled           bool     `yaml:"enabled" json:"enabled"`
+	OperationKeyOrder []string `yaml:"operation_key_order" json:"operation_key_order"` // empty uses the default order; extensions go where extension_placement puts them
+}
+
+// PathVariants configuration for merging the operations duplicated under variants of the same path,
+// such as /users and /users/, which break several generators
+//
+// Example:
+//
+//	path_variants:
+//	  enabled: true
+//	  trailing_slash: strip       # strip (default) merges /users/ into /users, add merges /users into /users/
+//	  version_prefixes: ["/v1"]   # /v1/users and /users are variants too
+//	  keep_version_prefix: true   # merge into /v1/users rather than /users
+type PathVariants struct {
+	Enabled           bool     `yaml:"enabled" json:"enabled"`
+	TrailingSlash     string   `yaml:"trailing_slash" json:"trailing_slash" default:"strip"` // strip (default) or add
+	VersionPrefixes   []string `yaml:"version_prefixes" json:"version_prefixes"`
+	KeepVersionPrefix bool     `yaml:"keep_version_prefix" json:"keep_version_prefix"`
+}
+
+// TenantPaths configuration for prefixing every path with a tenant segment, or removing it, to
+// turn a single-tenant spec into the spec of a multi-tenant gateway and back. The tenant parameter
+// is defined once under components/parameters and path items reference it with $ref.
+//
+// Example:
+//
+//	tenant_paths:
+//	  enabled: true
+//	  mode: inject                  # inject (default) turns /users into /{tenantId}/users, strip reverses it
+//	  parameter: tenantId           # name of the path parameter, defaults to tenantId
+//	  component: TenantId           # components/parameters entry, defaults to TenantId
+//	  description: Tenant the request is scoped to
+//	  schema: {type: string, format: uuid}  # defaults to {type: string}
+//	  exclude_paths: ["/health"]    # glob patterns of paths left alone
+type TenantPaths struct {
+	Enabled      bool                   `yaml:"enabled" json:"enabled"`
+	Mode         string                 `yaml:"mode" json:"mode" default:"inject"`             // inject (default) or strip
+	Parameter    string                 `yaml:"parameter" json:"parameter" default:"tenantId"` // path parameter name
+	Component    string                 `yaml:"component" json:"component" default:"TenantId"` // parameter component name
+	Description  string                 `yaml:"description" json:"description"`                // description of the parameter component, inject only
+	Schema       map[string]interface{} `yaml:"schema" json:"schema"`                          // schema of the parameter component, inject only
+	ExcludePaths []string               `yaml:"exclude_paths" json:"exclude_paths"`            // glob patterns of paths left alone
+}
+
+// SchemaTitles configuration for filling in the title of component schemas from their names and
+// normalizing existing titles, since several documentation generators use title as the display name
+//
+// Example:
+//
+//	schema_titles:
+//	  enabled: true
+//	  casing: title              # title (default) "Billing Invoice", sentence "Billing invoice", pascal, camel, snake or kebab
+//	  word_split: case           # case (default) splits at separators and case changes, separators only at _ - . and spaces
+//	  acronyms: [API, ID, URL]   # spelling of words kept whatever the casing
+//	  normalize: true            # also rewrite existing titles in the casing
+type SchemaTitles struct {
+	Enabled   bool     `yaml:"enabled" json:"enabled"`
+	Casing    string   `yaml:"casing" json:"casing" default:"title"`
+	WordSplit string   `yaml:"word_split" json:"word_split" default:"case"`
+	Acronyms  []string `yaml:"acronyms" json:"acronyms"`
+	Normalize bool     `yaml:"normalize" json:"normalize"` // existing titles are left alone unless set
+}
+
+// ExtensionSchema is the JSON Schema every value of a vendor extension must match, given inline or
+// as a JSON/YAML file relative to the config file
+//
+// Example:
+//
+//	extension_schemas:
+//	  x-codegen-request-body-name:
+//	    schema: {type: string, pattern: "^[a-z][a-zA-Z]*$"}
+//	  x-fern-pagination:
+//	    file: schemas/fern-pagination.json
+type ExtensionSchema struct {
+	Schema map[string]interface{} `yaml:"schema" json:"schema"`
+	File   string                 `yaml:"file" json:"file"`
+}
+
+// ComponentRenames configuration for renaming components and rewriting every $ref that points at them
+//
+// Example:
+//
+//	component_renames:
+//	  enabled: true
+//	  strip_suffixes: ["V2"]            # UserV2 -> User
+//	  patterns:
+//	    - match: "^Legacy(.*)$"          # LegacyOrder -> Order
+//	      replace: "$1"
+//	  map:
+//	    ListUsersResponseContent: UserList  # explicit renames win over suffixes/patterns
+type ComponentRenames struct {
+	Enabled       bool              `yaml:"enabled" json:"enabled"`
+	Sections      []string          `yaml:"sections" json:"sections" default:"[schemas]"` // component sections to rename, defaults to ["schemas"]
+	Map           map[string]string `yaml:"map" json:"map"`                               // explicit old -> new component names
+	StripPrefixes []string          `yaml:"strip_prefixes" json:"strip_prefixes"`         // prefixes removed from component names
+	StripSuffixes []string          `yaml:"strip_suffixes" json:"strip_suffixes"`         // suffixes removed from component names (e.g. "V2")
+	Patterns      []RenamePattern   `yaml:"patterns" json:"patterns"`                     // regex rewrites applied in order
+}
+
+// RenamePattern defines a regex-based component rename
+type RenamePattern struct {
+	Match   string `yaml:"match" json:"match"`     // regular expression matched against the component name
+	Replace string `yaml:"replace" json:"replace"` // replacement, supports $1-style capture groups
+}
+
+// ComponentDedup configuration for merging structurally identical components under one canonical name
+//
+// Example:
+//
+//	component_dedup:
+//	  enabled: true
+//	  prefer: shortest                # shortest, alphabetical or first (document order)
+//	  canonical: ["User"]             # names that always win when they are part of a duplicate group
+//	  ignore_keys: ["description"]    # keys ignored when comparing components
+type ComponentDedup struct {
+	Enabled    bool     `yaml:"enabled" json:"enabled"`
+	Sections   []string `yaml:"sections" json:"sections" default:"[schemas]"` // component sections to deduplicate, defaults to ["schemas"]
+	Prefer     string   `yaml:"prefer" json:"prefer" default:"shortest"`      // canonical name preference, defaults to "shortest"
+	Canonical  []string `yaml:"canonical" json:"canonical"`                   // preferred canonical names, in priority order
+	IgnoreKeys []string `yaml:"ignore_keys" json:"ignore_keys"`               // keys ignored when comparing (e.g. description, title, example)
+}
+
+// ComponentConsistency configuration for finding components that are defined under the same name
+// but with different shapes in several files of the input, and optionally harmonizing them
+//
+// Example:
+//
+//	component_consistency:
+//	  enabled: true
+//	  harmonize: true                         # replace the other definitions with the canonical one
+//	  prefer: most_common                     # most_common (ties go to the first file) or first
+//	  canonical:                              # component -> file whose definition wins
+//	    schemas/User: users/openapi.yaml
+//	  ignore_keys: ["description", "example"] # keys ignored when comparing definitions
+type ComponentConsistency struct {
+	Enabled    bool              `yaml:"enabled" json:"enabled"`
+	Sections   []string          `yaml:"sections" json:"sections"` // component sections to check, defaults to all of them
+	Harmonize  bool              `yaml:"harmonize" json:"harmonize"`
+	Prefer     string            `yaml:"prefer" json:"prefer" default:"most_common"`
+	Canonical  map[string]string `yaml:"canonical" json:"canonical"`     // section/name -> file relative to the input directory
+	IgnoreKeys []string          `yaml:"ignore_keys" json:"ignore_keys"` // keys ignored when comparing (e.g. description, example)
+}
+
+// StripInternal configuration for removing internal-only content before publishing a spec
+//
+// Example:
+//
+//	strip_internal:
+//	  enabled: true
+//	  extension: x-internal   # operations, path items, parameters, components, properties and tags marked with `x-internal: true`
+type StripInternal struct {
+	Enabled   bool   `yaml:"enabled" json:"enabled"`
+	Extension string `yaml:"extension" json:"extension" default:"x-internal"` // marker extension, defaults to "x-internal"
+}
+
+// ParameterInjection configuration for adding standard parameters to every matching operation.
+// Each parameter is defined once under components/parameters and operations reference it with $ref.
+//
+// Example:
+//
+//	parameter_injection:
+//	  enabled: true
+//	  parameters:
+//	    - component: RequestId         # components/parameters entry, created if missing; path parameters default to required: true
+//	      definition:
+//	        name: X-Request-Id
+//	        in: header
+//	        schema: {type: string}
+//	    - component: TenantId
+//	      definition:
+//	        name: tenant_id
+//	        in: query
+//	        required: true
+//	        schema: {type: string}
+//	      condition:
+//	        path_patterns: ["^/tenants/"]
+//	        http_methods: ["get"]
+type ParameterInjection struct {
+	Enabled    bool                `yaml:"enabled" json:"enabled"`
+	Parameters []InjectedParameter `yaml:"parameters" json:"parameters"`
+}
+
+// InjectedParameter defines a parameter added to the operations matching its condition
+type InjectedParameter struct {
+	Component  string                 `yaml:"component" json:"component"`   // name under components/parameters
+	Definition map[string]interface{} `yaml:"definition" json:"definition"` // parameter object; needs name and in
+	Condition  InjectionCondition     `yaml:"condition" json:"condition"`
+}
+
+// InjectionCondition selects the operations a parameter is injected into; empty lists match everything
+type InjectionCondition struct {
+	HTTPMethods      []string          `yaml:"http_methods" json:"http_methods"`           // which HTTP methods to target
+	PathPatterns     []string          `yaml:"path_patterns" json:"path_patterns"`         // regular expressions matched against the path
+	Tags             []string          `yaml:"tags" json:"tags"`                           // operations carrying any of these tags
+	Extensions       map[string]string `yaml:"extensions" json:"extensions"`               // operations having these extension values, "*" for any value
+	HasExtension     StringList        `yaml:"has_extension" json:"has_extension"`         // operations carrying all of these extensions
+	MissingExtension StringList        `yaml:"missing_extension" json:"missing_extension"` // operations carrying none of these extensions
+}
+
+// UnwrapEnvelopes configuration for replacing enveloped success responses ({code, message, data: T})
+// with the schema of their data field
+//
+// Example:
+//
+//	unwrap_envelopes:
+//	  enabled: true
+//	  envelopes:
+//	    - data_field: data
+//	      fields: [code, message]       # other properties the envelope may have
+//	  extension: x-response-envelope    # optional: record the removed envelope on the media type
+type UnwrapEnvelopes struct {
+	Enabled   bool            `yaml:"enabled" json:"enabled"`
+	Envelopes []EnvelopeShape `yaml:"envelopes" json:"envelopes"` // defaults to data_field data with fields code and message
+	Extension string          `yaml:"extension" json:"extension"` // vendor extension recording the envelope, empty to drop it
+}
+
+// EnvelopeShape describes a response envelope: an object with a data property and only the listed
+// other properties
+type EnvelopeShape struct {
+	DataField string   `yaml:"data_field" json:"data_field"` // property holding the payload
+	Fields    []string `yaml:"fields" json:"fields"`         // other properties allowed in the envelope
+}
+
+// OutputVariant defines one output generated from the same input, e.g. one directory per SDK vendor
+//
+// Example:
+//
+//	outputs:
+//	  - profile: fern          # vendor extension provider applied to this variant ("none" for no vendor extensions)
+//	    dir: out/fern
+//	  - profile: speakeasy
+//	    dir: out/speakeasy
+type OutputVariant struct {
+	Profile string `yaml:"profile" json:"profile"`
+	Dir     string `yaml:"dir" json:"dir"`
 }
 
 // LoadConfig loads config from file (YAML/JSON) and merges with inline flags. If noConfig is true, ignores all config files and uses only CLI flags.
@@ -129,6 +868,7 @@ func loadConfigFiles(cfg *Config, configPath string) error {
 		if err := yaml.Unmarshal(data, cfg); err != nil {
 			return err
 		}
+		cfg.Source = configPath
 	}
 
 	// 2. Load from .openapirc.yaml if present and not already loaded
@@ -139,6 +879,7 @@ func loadConfigFiles(cfg *Config, configPath string) error {
 				if err := yaml.Unmarshal(data, cfg); err != nil {
 					return err
 				}
+				cfg.Source = ".openapirc.yaml"
 			}
 		}
 	}
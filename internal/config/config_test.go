@@ -2,7 +2,10 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestLoadConfig_FileAndInline(t *testing.T) {
@@ -82,3 +85,153 @@ func TestSplitMap(t *testing.T) {
 		t.Error("expected nil for invalid map")
 	}
 }
+
+func TestFileFilterForStep(t *testing.T) {
+	f := "files.yaml"
+	cfgYaml := `input: specs
+files:
+  include: ["specs/**"]
+  exclude: ["**/fixtures/**"]
+  steps:
+    vendor_extensions:
+      exclude: ["specs/internal/**"]
+    defaults:
+      exclude: []
+`
+	if err := os.WriteFile(f, []byte(cfgYaml), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	defer os.Remove(f)
+	cfg, err := LoadConfig(f, nil, "", "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pagination := cfg.Files.ForStep("pagination")
+	if len(pagination.Include) != 1 || len(pagination.Exclude) != 1 || pagination.Exclude[0] != "**/fixtures/**" {
+		t.Errorf("expected top-level patterns for pagination, got %+v", pagination)
+	}
+	vendor := cfg.Files.ForStep("vendor_extensions")
+	if vendor.Include[0] != "specs/**" || len(vendor.Exclude) != 1 || vendor.Exclude[0] != "specs/internal/**" {
+		t.Errorf("expected vendor_extensions to override exclude only, got %+v", vendor)
+	}
+	if defaults := cfg.Files.ForStep("defaults"); len(defaults.Exclude) != 0 || len(defaults.Include) != 1 {
+		t.Errorf("expected an empty exclude list to clear the top-level one, got %+v", defaults)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	f := "locate.yaml"
+	cfgYaml := `pagination_priority:
+  - cursor
+  - offset
+vendor_extensions:
+  providers:
+    fern:
+      strategies:
+        cursor:
+          template: {}
+`
+	if err := os.WriteFile(f, []byte(cfgYaml), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	defer os.Remove(f)
+
+	if line, col := Locate(f, "pagination_priority", "1"); line != 3 || col != 5 {
+		t.Errorf("expected 3:5 for pagination_priority[1], got %d:%d", line, col)
+	}
+	if line, _ := Locate(f, "vendor_extensions", "providers", "fern", "strategies", "cursor"); line != 9 {
+		t.Errorf("expected line 9 for the cursor strategy, got %d", line)
+	}
+	if line, col := Locate(f, "vendor_extensions", "missing"); line != 0 || col != 0 {
+		t.Errorf("expected no position for a missing key, got %d:%d", line, col)
+	}
+}
+
+func TestAppendMappings(t *testing.T) {
+	path := t.TempDir() + "/openmorph.yaml"
+	original := "# shared config\ninput: specs\nmappings:\n  x-a: x-b # keep me\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AppendMappings(path, map[string]string{"x-d": "x-e", "x-c": "x-f", "x-a": "x-z"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# shared config\ninput: specs\nmappings:\n    x-a: x-z # keep me\n    x-c: x-f\n    x-d: x-e\n"
+	if string(data) != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, data)
+	}
+
+	created := t.TempDir() + "/new.json"
+	if err := AppendMappings(created, map[string]string{"x-a": "x-b"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "{\n  \"mappings\": {\n    \"x-a\": \"x-b\"\n  }\n}\n" {
+		t.Errorf("unexpected new JSON config:\n%s", data)
+	}
+}
+
+func TestAddEndpointRules(t *testing.T) {
+	path := t.TempDir() + "/openmorph.yaml"
+	original := "input: specs # keep me\nendpoint_pagination:\n  - endpoint: /users/*\n    method: GET\n    pagination: cursor\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := []EndpointPaginationRule{{Endpoint: "/users/list", Method: "GET", Pagination: "page"}}
+	if err := AddEndpointRules(path, rules); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "input: specs # keep me\nendpoint_pagination:\n    - endpoint: /users/list\n      method: GET\n      pagination: page\n" +
+		"    - endpoint: /users/*\n      method: GET\n      pagination: cursor\n"
+	if string(data) != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, data)
+	}
+
+	created := t.TempDir() + "/new.yaml"
+	if err := AddEndpointRules(created, rules); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(created, nil, "specs", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.EndpointPagination) != 1 || cfg.EndpointPagination[0] != rules[0] {
+		t.Errorf("unexpected rules in the new config: %+v", cfg.EndpointPagination)
+	}
+
+	invalid := t.TempDir() + "/invalid.yaml"
+	if err := os.WriteFile(invalid, []byte("endpoint_pagination: cursor\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddEndpointRules(invalid, rules); err == nil {
+		t.Error("expected an error when endpoint_pagination is not a list")
+	}
+}
+
+func TestStringListAcceptsScalarOrList(t *testing.T) {
+	var condition DefaultCondition
+	data := "has_extension: x-beta\nmissing_extension: [x-fern-pagination, x-curated]\n"
+	if err := yaml.Unmarshal([]byte(data), &condition); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(condition.HasExtension, ",") != "x-beta" {
+		t.Errorf("expected a single extension, got %v", condition.HasExtension)
+	}
+	if strings.Join(condition.MissingExtension, ",") != "x-fern-pagination,x-curated" {
+		t.Errorf("expected two extensions, got %v", condition.MissingExtension)
+	}
+}
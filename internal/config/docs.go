@@ -0,0 +1,297 @@
+package config
+
+import (
+	// embed is needed for the config source the reference is read from
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configSource is the source of the config structs, whose comments describe the keys
+//
+//go:embed config.go
+var configSource string
+
+// Option documents one key of the config file
+type Option struct {
+	Key         string // dotted path; <name> stands for a key of your choosing and [] for the items of a list
+	Type        string
+	Default     string // from the default tag of the field, empty for the zero value
+	Description string
+}
+
+// Section documents a top-level key of the config file and every key below it. The section of the
+// top-level keys that hold a single value has an empty Key.
+type Section struct {
+	Key         string
+	Description string
+	Options     []Option
+	Examples    []string // YAML examples from the docs of the types in the section
+}
+
+// typeDocs holds the comments of the config structs and their fields
+type typeDocs struct {
+	types  map[string]string // type name -> doc comment
+	fields map[string]string // Type.Field -> doc or line comment
+}
+
+// Reference documents every key of the config file, generated from the Config struct: keys and
+// types from its yaml tags, defaults from its default tags and descriptions from its comments
+func Reference() ([]Section, error) {
+	docs, err := parseTypeDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	general := Section{}
+	sections := []Section{}
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		key, ok := yamlKey(field)
+		if !ok {
+			continue
+		}
+		option := docs.option(configType, field, key)
+		nested := nestedStruct(field.Type)
+		if nested == nil {
+			general.Options = append(general.Options, option)
+			continue
+		}
+
+		section := Section{Key: key}
+		if section.Description, _ = docs.typeDoc(nested.Name()); section.Description == "" {
+			section.Description = option.Description
+		}
+		docs.walk(nested, childPrefix(key, field.Type), &section, map[string]bool{})
+		sections = append(sections, section)
+	}
+	return append([]Section{general}, sections...), nil
+}
+
+// WriteMarkdown writes the reference of the config file as Markdown
+func WriteMarkdown(w io.Writer) error {
+	sections, err := Reference()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("# OpenMorph Configuration Reference\n\n")
+	b.WriteString("Every key of the config file (`--config`, or `.openapirc.yaml`). Keys are written as dotted paths: `<name>` ")
+	b.WriteString("stands for a key of your choosing, such as a provider or rule name, and `[]` for the items of a list.\n\n")
+	b.WriteString("Generated by `openmorph config docs`.\n")
+	for _, section := range sections {
+		if section.Key == "" {
+			b.WriteString("\n## General\n")
+		} else {
+			fmt.Fprintf(&b, "\n## `%s`\n", section.Key)
+		}
+		if section.Description != "" {
+			fmt.Fprintf(&b, "\n%s\n", section.Description)
+		}
+		b.WriteString("\n| Key | Type | Default | Description |\n|-----|------|---------|-------------|\n")
+		for _, option := range section.Options {
+			def := ""
+			if option.Default != "" {
+				def = "`" + option.Default + "`"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", option.Key, option.Type, def, markdownCell(option.Description))
+		}
+		for _, example := range section.Examples {
+			fmt.Fprintf(&b, "\n```yaml\n%s\n```\n", example)
+		}
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// walk documents the fields of t under prefix, and of the structs they hold
+func (d typeDocs) walk(t reflect.Type, prefix string, section *Section, seen map[string]bool) {
+	if _, example := d.typeDoc(t.Name()); example != "" && !seen[t.Name()] {
+		section.Examples = append(section.Examples, example)
+	}
+	seen[t.Name()] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := yamlKey(field)
+		if !ok {
+			continue
+		}
+		key := prefix + "." + name
+		section.Options = append(section.Options, d.option(t, field, key))
+		if nested := nestedStruct(field.Type); nested != nil {
+			d.walk(nested, childPrefix(key, field.Type), section, seen)
+		}
+	}
+}
+
+// option documents one field of owner. A struct field without a comment of its own is described
+// by the doc of its type.
+func (d typeDocs) option(owner reflect.Type, field reflect.StructField, key string) Option {
+	description := d.fields[owner.Name()+"."+field.Name]
+	if description == "" {
+		if nested := nestedStruct(field.Type); nested != nil {
+			description, _ = d.typeDoc(nested.Name())
+		}
+	}
+	return Option{
+		Key:         key,
+		Type:        typeName(field.Type),
+		Default:     field.Tag.Get("default"),
+		Description: description,
+	}
+}
+
+// typeDoc returns the description of a type, without its leading type name, and its example
+func (d typeDocs) typeDoc(name string) (description, example string) {
+	doc := d.types[name]
+	if before, after, ok := strings.Cut(doc, "Example:\n"); ok {
+		doc = before
+		example = unindent(after)
+	}
+
+	description = strings.Join(strings.Fields(doc), " ")
+	return capitalize(strings.TrimPrefix(description, name+" ")), example
+}
+
+// capitalize upper-cases the first letter of a description
+func capitalize(text string) string {
+	if text == "" {
+		return text
+	}
+	r, size := utf8.DecodeRuneInString(text)
+	return string(unicode.ToUpper(r)) + text[size:]
+}
+
+// parseTypeDocs reads the comments of the types and fields of the config source
+func parseTypeDocs() (typeDocs, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "config.go", configSource, parser.ParseComments)
+	if err != nil {
+		return typeDocs{}, fmt.Errorf("failed to parse the config source: %w", err)
+	}
+
+	docs := typeDocs{types: map[string]string{}, fields: map[string]string{}}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			doc := typeSpec.Doc
+			if doc == nil {
+				doc = gen.Doc
+			}
+			docs.types[typeSpec.Name.Name] = doc.Text()
+
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, field := range structType.Fields.List {
+				comment := field.Comment
+				if comment == nil {
+					comment = field.Doc
+				}
+				for _, name := range field.Names {
+					docs.fields[typeSpec.Name.Name+"."+name.Name] = strings.TrimSpace(comment.Text())
+				}
+			}
+		}
+	}
+	return docs, nil
+}
+
+// yamlKey returns the key of a field in the config file
+func yamlKey(field reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" || name == "-" {
+		return "", false
+	}
+	return name, true
+}
+
+// nestedStruct returns the struct a field holds, directly or as the items of a list or map, or nil
+func nestedStruct(t reflect.Type) reflect.Type {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t != reflect.TypeOf(yaml.Node{}) {
+		return t
+	}
+	return nil
+}
+
+// childPrefix returns the key prefix of the fields of the struct a field of type t holds
+func childPrefix(key string, t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Slice:
+		return key + "[]"
+	case reflect.Map:
+		return key + ".<name>"
+	default:
+		return key
+	}
+}
+
+// typeName describes a Go type in the terms of the config file
+func typeName(t reflect.Type) string {
+	if t == reflect.TypeOf(StringList{}) {
+		return "string or list of strings"
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeName(t.Elem())
+	case reflect.Slice:
+		return "list of " + pluralTypeName(t.Elem())
+	case reflect.Map:
+		return "map of " + pluralTypeName(t.Elem())
+	case reflect.Struct:
+		return "object"
+	case reflect.Interface:
+		return "any"
+	case reflect.Int, reflect.Int64:
+		return "integer"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// pluralTypeName describes the items of a list or map
+func pluralTypeName(t reflect.Type) string {
+	name := typeName(t)
+	switch {
+	case name == "any":
+		return "values"
+	case strings.Contains(name, " "):
+		return name
+	default:
+		return name + "s"
+	}
+}
+
+// unindent removes the tab indenting an example in a doc comment
+func unindent(text string) string {
+	lines := strings.Split(strings.Trim(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownCell escapes text for a Markdown table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReferenceCoversEveryKey(t *testing.T) {
+	sections, err := Reference()
+	if err != nil {
+		t.Fatalf("Reference failed: %v", err)
+	}
+	options := map[string]Option{}
+	for _, section := range sections {
+		for _, option := range section.Options {
+			options[option.Key] = option
+		}
+	}
+
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		key, ok := yamlKey(configType.Field(i))
+		if !ok {
+			continue
+		}
+		found := false
+		for documented := range options {
+			if documented == key || strings.HasPrefix(documented, key+".") || strings.HasPrefix(documented, key+"[]") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected %s to be documented", key)
+		}
+	}
+
+	tests := []struct {
+		key, typ, def string
+	}{
+		{"mapping_collisions", "string", "keep_existing"},
+		{"vendor_extensions.providers.<name>.has_extension", "string or list of strings", ""},
+		{"default_values.rules.<name>.condition.required", "bool", ""},
+		{"endpoint_pagination[].method", "string", ""},
+		{"component_dedup.sections", "list of strings", "[schemas]"},
+	}
+	for _, tt := range tests {
+		option, ok := options[tt.key]
+		if !ok {
+			t.Errorf("expected %s to be documented", tt.key)
+			continue
+		}
+		if option.Type != tt.typ || option.Default != tt.def {
+			t.Errorf("%s: got type %q default %q, want %q %q", tt.key, option.Type, option.Default, tt.typ, tt.def)
+		}
+	}
+	if option := options["endpoint_pagination[].method"]; option.Description == "" {
+		t.Error("expected field comments to describe the keys")
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := WriteMarkdown(&b); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"## `provenance`",
+		"Stamps every document a run changed",
+		"| `provenance.mode` | string | `extension` |",
+		"```yaml\nprovenance:\n  enabled: true\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the reference to contain %q", want)
+		}
+	}
+	if strings.Contains(out, "template_node") || strings.Contains(out, "| `source`") {
+		t.Error("expected keys that are not read from the config file to be left out")
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AppendMappings adds the mappings to the mappings: section of the config file at path, creating
+// the file or the section when missing. The rest of a YAML file, including comments and key order,
+// is kept; JSON files are rewritten with sorted keys. Keys that are already mapped are updated.
+func AppendMappings(path string, mappings map[string]string) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	doc, root, err := loadConfigDocument(path)
+	if err != nil {
+		return err
+	}
+
+	section := mappingValue(root, "mappings")
+	if section == nil || section.Kind != yaml.MappingNode {
+		if section != nil {
+			return fmt.Errorf("%s: mappings must be a mapping", path)
+		}
+		section = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "mappings"}, section)
+	}
+
+	keys := make([]string, 0, len(mappings))
+	for key := range mappings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if existing := mappingValue(section, key); existing != nil {
+			existing.Value = mappings[key]
+			continue
+		}
+		section.Content = append(section.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: mappings[key]})
+	}
+
+	return writeConfigDocument(path, doc)
+}
+
+// AddEndpointRules inserts the rules at the top of the endpoint_pagination: section of the config
+// file at path, creating the file or the section when missing, so they take precedence over the
+// rules already there. The rest of the file is kept as AppendMappings keeps it.
+func AddEndpointRules(path string, rules []EndpointPaginationRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	doc, root, err := loadConfigDocument(path)
+	if err != nil {
+		return err
+	}
+	section := mappingValue(root, "endpoint_pagination")
+	if section == nil || section.Kind != yaml.SequenceNode {
+		if section != nil && section.Tag != "!!null" {
+			return fmt.Errorf("%s: endpoint_pagination must be a list", path)
+		}
+		if section == nil {
+			section = &yaml.Node{}
+			root.Content = append(root.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "endpoint_pagination"}, section)
+		}
+		*section = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+
+	items := make([]*yaml.Node, 0, len(rules)+len(section.Content))
+	for _, rule := range rules {
+		var item yaml.Node
+		if err := item.Encode(rule); err != nil {
+			return err
+		}
+		items = append(items, &item)
+	}
+	section.Content = append(items, section.Content...)
+	return writeConfigDocument(path, doc)
+}
+
+// loadConfigDocument parses the config file at path, returning an empty document when it does not
+// exist yet
+func loadConfigDocument(path string) (*yaml.Node, *yaml.Node, error) {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return nil, nil, err
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("%s: the config must be a mapping", path)
+	}
+	return &doc, root, nil
+}
+
+// writeConfigDocument writes a config document back to path, as JSON when the file is JSON
+func writeConfigDocument(path string, doc *yaml.Node) error {
+	var out []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var value interface{}
+		if err := doc.Decode(&value); err != nil {
+			return err
+		}
+		if out, err = json.MarshalIndent(value, "", "  "); err != nil {
+			return err
+		}
+		out = append(out, '\n')
+	} else if out, err = yaml.Marshal(doc); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0600)
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Locate returns the 1-based line and column of the value at keys in a YAML/JSON config file.
+// Sequence items are addressed by their index. It returns zeros when the file cannot be read or
+// the value does not exist.
+func Locate(path string, keys ...string) (line, column int) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return 0, 0
+	}
+
+	node := doc.Content[0]
+	for _, key := range keys {
+		node = child(node, key)
+		if node == nil {
+			return 0, 0
+		}
+	}
+	return node.Line, node.Column
+}
+
+// child returns the value of key in a mapping or the item at index key in a sequence
+func child(node *yaml.Node, key string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		if index, err := strconv.Atoi(key); err == nil && index >= 0 && index < len(node.Content) {
+			return node.Content[index]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Types a --default flag can constrain its target to, by the names it accepts
+var defaultFlagTypes = map[string]string{
+	"int": "integer", "integer": "integer",
+	"num": "number", "number": "number", "float": "number",
+	"bool": "boolean", "boolean": "boolean",
+	"str": "string", "string": "string",
+}
+
+// ApplyRuleOverrides merges rules given on the command line into the config. endpointRules are
+// "METHOD /path=strategy" specs that take precedence over the endpoint_pagination rules of the
+// config; defaults are "location[:type]:name=value" specs added as default_values rules ranked
+// above the configured ones, enabling the step.
+func ApplyRuleOverrides(cfg *Config, endpointRules, defaults []string) error {
+	var rules []EndpointPaginationRule
+	for _, spec := range endpointRules {
+		rule, err := ParseEndpointRule(spec)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) > 0 {
+		cfg.EndpointPagination = append(rules, cfg.EndpointPagination...)
+	}
+
+	if len(defaults) == 0 {
+		return nil
+	}
+	priority := 0
+	for _, rule := range cfg.DefaultValues.Rules {
+		if rule.Priority >= priority {
+			priority = rule.Priority + 1
+		}
+	}
+	if cfg.DefaultValues.Rules == nil {
+		cfg.DefaultValues.Rules = make(map[string]DefaultRule)
+	}
+	for _, spec := range defaults {
+		name, rule, err := ParseDefaultRule(spec)
+		if err != nil {
+			return err
+		}
+		rule.Priority = priority
+		cfg.DefaultValues.Rules[name] = rule
+	}
+	cfg.DefaultValues.Enabled = true
+	return nil
+}
+
+// ParseEndpointRule parses an --endpoint-rule spec such as "GET /users=cursor". Without a method,
+// as in "/users/*=none", the rule applies to every method.
+func ParseEndpointRule(spec string) (EndpointPaginationRule, error) {
+	target, strategy, ok := strings.Cut(spec, "=")
+	target, strategy = strings.TrimSpace(target), strings.TrimSpace(strategy)
+	if !ok || target == "" || strategy == "" {
+		return EndpointPaginationRule{}, fmt.Errorf("--endpoint-rule %q: expected METHOD /path=strategy", spec)
+	}
+
+	method, endpoint := "*", target
+	if fields := strings.Fields(target); len(fields) == 2 {
+		method, endpoint = strings.ToUpper(fields[0]), fields[1]
+	} else if len(fields) != 1 {
+		return EndpointPaginationRule{}, fmt.Errorf("--endpoint-rule %q: expected METHOD /path=strategy", spec)
+	}
+	if !strings.HasPrefix(endpoint, "/") {
+		return EndpointPaginationRule{}, fmt.Errorf("--endpoint-rule %q: path %q must start with /", spec, endpoint)
+	}
+	return EndpointPaginationRule{Endpoint: endpoint, Method: method, Pagination: strategy}, nil
+}
+
+// ParseDefaultRule parses a --default spec such as "query:int:limit=50" into a rule named after
+// the spec without its value. The location is a parameter location (query, path, header or
+// cookie) or one of request_body, response and component; the optional type (int, number, bool or
+// string) restricts the rule to targets of that type and decides how the value is read. Without a
+// type the value is read as a YAML scalar.
+func ParseDefaultRule(spec string) (string, DefaultRule, error) {
+	name, value, ok := strings.Cut(spec, "=")
+	parts := strings.Split(name, ":")
+	if !ok || len(parts) < 2 || len(parts) > 3 || parts[len(parts)-1] == "" {
+		return "", DefaultRule{}, fmt.Errorf("--default %q: expected location[:type]:name=value", spec)
+	}
+
+	var rule DefaultRule
+	switch location := parts[0]; location {
+	case "query", "path", "header", "cookie":
+		rule.Target.Location = "parameter"
+		rule.Condition.ParameterIn = location
+	case "request_body", "response", "component":
+		rule.Target.Location = location
+	default:
+		return "", DefaultRule{}, fmt.Errorf("--default %q: unknown location %q (query, path, header, cookie, request_body, response or component)", spec, location)
+	}
+	rule.Condition.PropertyName = "^" + regexp.QuoteMeta(parts[len(parts)-1]) + "$"
+
+	if len(parts) == 3 {
+		typ, ok := defaultFlagTypes[parts[1]]
+		if !ok {
+			return "", DefaultRule{}, fmt.Errorf("--default %q: unknown type %q (int, number, bool or string)", spec, parts[1])
+		}
+		rule.Condition.Type = typ
+		parsed, err := parseTypedValue(value, typ)
+		if err != nil {
+			return "", DefaultRule{}, fmt.Errorf("--default %q: %v", spec, err)
+		}
+		rule.Value = parsed
+		return name, rule, nil
+	}
+
+	rule.Value = value
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &node); err == nil && len(node.Content) == 1 && node.Content[0].Kind == yaml.ScalarNode {
+		_ = node.Content[0].Decode(&rule.Value)
+	}
+	return name, rule, nil
+}
+
+// parseTypedValue reads a --default value as typ
+func parseTypedValue(value, typ string) (interface{}, error) {
+	switch typ {
+	case "integer":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", value)
+		}
+		return n, nil
+	case "number":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", value)
+		}
+		return b, nil
+	}
+	return value, nil
+}
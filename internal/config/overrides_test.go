@@ -0,0 +1,94 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEndpointRule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    EndpointPaginationRule
+		wantErr bool
+	}{
+		{spec: "GET /users=cursor", want: EndpointPaginationRule{Endpoint: "/users", Method: "GET", Pagination: "cursor"}},
+		{spec: "post /search = offset", want: EndpointPaginationRule{Endpoint: "/search", Method: "POST", Pagination: "offset"}},
+		{spec: "/users/*=none", want: EndpointPaginationRule{Endpoint: "/users/*", Method: "*", Pagination: "none"}},
+		{spec: "GET /users", wantErr: true},
+		{spec: "GET users=cursor", wantErr: true},
+		{spec: "GET /users extra=cursor", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseEndpointRule(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %+v", tt.spec, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: expected %+v, got %+v (%v)", tt.spec, tt.want, got, err)
+		}
+	}
+}
+
+func TestParseDefaultRule(t *testing.T) {
+	name, rule, err := ParseDefaultRule("query:int:limit=50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultRule{
+		Target:    DefaultTarget{Location: "parameter"},
+		Condition: DefaultCondition{ParameterIn: "query", Type: "integer", PropertyName: "^limit$"},
+		Value:     50,
+	}
+	if name != "query:int:limit" || !reflect.DeepEqual(rule, want) {
+		t.Errorf("expected %+v named query:int:limit, got %+v named %s", want, rule, name)
+	}
+
+	values := map[string]interface{}{
+		"request_body:status=active": "active",
+		"response:enabled=true":      true,
+		"header:x-trace=a: b":        "a: b",
+		"component:bool:strict=no":   nil,
+		"cookie:num:ratio=0.5":       0.5,
+	}
+	for spec, value := range values {
+		_, rule, err := ParseDefaultRule(spec)
+		if value == nil {
+			if err == nil {
+				t.Errorf("%q: expected an error for the value", spec)
+			}
+			continue
+		}
+		if err != nil || rule.Value != value {
+			t.Errorf("%q: expected value %#v, got %#v (%v)", spec, value, rule.Value, err)
+		}
+	}
+
+	for _, spec := range []string{"limit=50", "body:limit=50", "query:long:limit=50", "query:int:limit", "query:=1"} {
+		if _, _, err := ParseDefaultRule(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
+
+func TestApplyRuleOverrides(t *testing.T) {
+	cfg := &Config{
+		EndpointPagination: []EndpointPaginationRule{{Endpoint: "/users", Method: "GET", Pagination: "offset"}},
+		DefaultValues:      DefaultValues{Rules: map[string]DefaultRule{"limits": {Priority: 4}}},
+	}
+	if err := ApplyRuleOverrides(cfg, []string{"GET /users=cursor"}, []string{"query:int:limit=50"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.EndpointPagination) != 2 || cfg.EndpointPagination[0].Pagination != "cursor" {
+		t.Errorf("expected the flag rule ahead of the configured one, got %+v", cfg.EndpointPagination)
+	}
+	if !cfg.DefaultValues.Enabled || cfg.DefaultValues.Rules["query:int:limit"].Priority != 5 {
+		t.Errorf("expected an enabled rule ranked above the configured ones, got %+v", cfg.DefaultValues)
+	}
+
+	if err := ApplyRuleOverrides(&Config{}, []string{"cursor"}, nil); err == nil {
+		t.Error("expected an error for an invalid endpoint rule")
+	}
+}
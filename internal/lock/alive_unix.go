@@ -0,0 +1,15 @@
+//go:build !windows
+
+package lock
+
+import (
+	"errors"
+	"syscall"
+)
+
+// alive reports whether a process with the given pid is running. Signal 0 checks without
+// signaling; EPERM means the process exists but belongs to another user.
+func alive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
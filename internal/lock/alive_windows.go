@@ -0,0 +1,16 @@
+//go:build windows
+
+package lock
+
+import "os"
+
+// alive reports whether a process with the given pid is running. On Windows, finding a process
+// opens a handle to it, which fails once it exited.
+func alive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = process.Release()
+	return true
+}
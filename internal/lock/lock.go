@@ -0,0 +1,145 @@
+// Package lock keeps two runs from transforming the same input at once. A run holds a lock file in
+// the input root, recording who holds it, and removes it when done. A lock left behind by a run
+// that crashed is detected as stale and taken over.
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the lock file created in the input root. It has no YAML/JSON extension, so the
+// steps never process it.
+const FileName = ".openmorph.lock"
+
+// StaleAfter is how long a lock is honored when its holder cannot be checked, such as a run on
+// another host sharing the directory
+const StaleAfter = 24 * time.Hour
+
+// Holder is the run holding a lock
+type Holder struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Started time.Time `json:"started"`
+}
+
+// HeldError is returned by Acquire when another run holds the lock
+type HeldError struct {
+	Path   string
+	Holder *Holder // nil when the lock file could not be read, such as while its holder writes it
+}
+
+func (e *HeldError) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("%s is held by another run", e.Path)
+	}
+	return fmt.Sprintf("%s is held by another run (pid %d on %s, started %s)",
+		e.Path, e.Holder.PID, e.Holder.Host, e.Holder.Started.Local().Format(time.DateTime))
+}
+
+// Lock is a lock held by this run
+type Lock struct {
+	path string
+}
+
+// Path returns the lock file guarding input: in the directory itself, or next to a single file
+func Path(input string) string {
+	if info, err := os.Stat(input); err == nil && info.IsDir() {
+		return filepath.Join(input, FileName)
+	}
+	return filepath.Join(filepath.Dir(input), FileName)
+}
+
+// Acquire locks the input root of input. It returns a *HeldError when another live run holds the
+// lock, and takes over a stale one.
+func Acquire(input string) (*Lock, error) {
+	path := Path(input)
+	host, _ := os.Hostname()
+	self := Holder{PID: os.Getpid(), Host: host, Started: time.Now().UTC()}
+
+	// A second attempt follows the removal of a stale lock, or a lock released in between
+	for attempt := 0; attempt < 2; attempt++ {
+		created, err := create(path, self)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return &Lock{path: path}, nil
+		}
+
+		holder, modified, err := read(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if !stale(holder, modified, host) {
+			return nil, &HeldError{Path: path, Holder: holder}
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale lock %s: %w", path, err)
+		}
+	}
+	holder, _, _ := read(path)
+	return nil, &HeldError{Path: path, Holder: holder}
+}
+
+// Release removes the lock file
+func (l *Lock) Release() error {
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to release lock %s: %w", l.path, err)
+	}
+	return nil
+}
+
+// create writes the lock file for holder, reporting false when it already exists
+func create(path string, holder Holder) (bool, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create lock %s: %w", path, err)
+	}
+	err = json.NewEncoder(file).Encode(holder)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return false, fmt.Errorf("failed to write lock %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// read returns the holder recorded in the lock file, nil when it cannot be parsed, and when the
+// file was last written
+func read(path string) (*Holder, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var holder Holder
+	if json.Unmarshal(data, &holder) != nil || holder.PID == 0 {
+		return nil, info.ModTime(), nil
+	}
+	return &holder, info.ModTime(), nil
+}
+
+// stale reports whether a lock can be taken over: its holder on this host is no longer running,
+// or it is older than StaleAfter
+func stale(holder *Holder, modified time.Time, host string) bool {
+	if holder == nil {
+		return time.Since(modified) > StaleAfter
+	}
+	if holder.Host == host && !alive(holder.PID) {
+		return true
+	}
+	return time.Since(holder.Started) > StaleAfter
+}
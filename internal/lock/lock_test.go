@@ -0,0 +1,98 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeHolder(t *testing.T, path string, holder Holder) {
+	t.Helper()
+	data, err := json.Marshal(holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(file, []byte("openapi: 3.0.0\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, FileName)
+	if got := Path(dir); got != want {
+		t.Errorf("Path(dir) = %q, want %q", got, want)
+	}
+	if got := Path(file); got != want {
+		t.Errorf("Path(file) = %q, want %q", got, want)
+	}
+}
+
+func TestAcquireAndRelease(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := Acquire(dir)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	_, err = Acquire(dir)
+	var held *HeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("expected a HeldError, got %v", err)
+	}
+	if held.Holder == nil || held.Holder.PID != os.Getpid() {
+		t.Errorf("expected this process as the holder, got %+v", held.Holder)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, FileName)); !os.IsNotExist(err) {
+		t.Errorf("expected the lock file to be removed, got %v", err)
+	}
+	lock, err = Acquire(dir)
+	if err != nil {
+		t.Fatalf("Acquire after Release failed: %v", err)
+	}
+	_ = lock.Release()
+}
+
+func TestAcquireTakesOverStaleLocks(t *testing.T) {
+	host, _ := os.Hostname()
+	tests := []struct {
+		name   string
+		holder Holder
+	}{
+		{"exited process", Holder{PID: 1 << 22, Host: host, Started: time.Now()}},
+		{"expired on another host", Holder{PID: 1, Host: "elsewhere", Started: time.Now().Add(-StaleAfter - time.Minute)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeHolder(t, filepath.Join(dir, FileName), tt.holder)
+			lock, err := Acquire(dir)
+			if err != nil {
+				t.Fatalf("expected the stale lock to be taken over, got %v", err)
+			}
+			_ = lock.Release()
+		})
+	}
+}
+
+func TestAcquireHonorsLiveLocks(t *testing.T) {
+	dir := t.TempDir()
+	writeHolder(t, filepath.Join(dir, FileName), Holder{PID: 1, Host: "elsewhere", Started: time.Now()})
+
+	_, err := Acquire(dir)
+	var held *HeldError
+	if !errors.As(err, &held) || held.Holder.Host != "elsewhere" {
+		t.Fatalf("expected the lock of another host to be honored, got %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+// Package notify posts run summaries to webhooks configured under `notify:`
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+// Webhook payload formats
+const (
+	FormatJSON  = "json"
+	FormatSlack = "slack"
+)
+
+// Webhook triggers
+const (
+	OnAlways  = "always"
+	OnChanges = "changes"
+	OnFailure = "failure"
+)
+
+// Run statuses
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// Validation statuses
+const (
+	ValidationPassed  = "passed"
+	ValidationFailed  = "failed"
+	ValidationSkipped = "skipped"
+)
+
+// maxSlackBreakingChanges limits how many breaking changes a Slack message lists
+const maxSlackBreakingChanges = 20
+
+// requestTimeout bounds every webhook request so a slow endpoint cannot hold up a scheduled run
+const requestTimeout = 10 * time.Second
+
+// Summary is the run summary posted to webhooks
+type Summary struct {
+	Tool            string   `json:"tool"`
+	Version         string   `json:"version,omitempty"`
+	Input           string   `json:"input"`
+	Status          string   `json:"status"`
+	Error           string   `json:"error,omitempty"`
+	FilesChanged    []string `json:"files_changed"`
+	Changes         int      `json:"changes"`
+	BreakingChanges []string `json:"breaking_changes"`
+	Validation      string   `json:"validation"`
+}
+
+// NewSummary summarizes the results of one or more pipeline runs over input. runErr is the error
+// that ended the run, if any; validation is one of the Validation* statuses.
+func NewSummary(input, version string, validation string, runErr error, results ...*transform.TransformationResults) Summary {
+	summary := Summary{
+		Tool:            "openmorph",
+		Version:         version,
+		Input:           input,
+		Status:          StatusSuccess,
+		FilesChanged:    []string{},
+		BreakingChanges: []string{},
+		Validation:      validation,
+	}
+	if runErr != nil {
+		summary.Status = StatusFailed
+		summary.Error = runErr.Error()
+	}
+
+	changed := make(map[string]bool)
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		for _, file := range r.Changed {
+			changed[file] = true
+		}
+		for _, location := range r.AllLocations() {
+			changed[location.File] = true
+		}
+		summary.Changes += len(r.AllLocations())
+		summary.BreakingChanges = append(summary.BreakingChanges, breakingChanges(r)...)
+	}
+	for file := range changed {
+		summary.FilesChanged = append(summary.FilesChanged, file)
+	}
+	sort.Strings(summary.FilesChanged)
+	return summary
+}
+
+// breakingChanges lists changes that remove or rename parts of the API contract: stripped
+// internal content, removed pagination parameters and responses, and renamed components
+func breakingChanges(r *transform.TransformationResults) []string {
+	var changes []string
+	if r.InternalResult != nil {
+		for _, file := range sortedKeys(r.InternalResult.RemovedItems) {
+			for _, item := range r.InternalResult.RemovedItems[file] {
+				changes = append(changes, fmt.Sprintf("%s: removed %s", file, item))
+			}
+		}
+	}
+	if r.PaginationResult != nil {
+		for _, operation := range sortedKeys(r.PaginationResult.RemovedParams) {
+			changes = append(changes, fmt.Sprintf("%s: removed parameters %s", operation,
+				strings.Join(r.PaginationResult.RemovedParams[operation], ", ")))
+		}
+		for _, operation := range sortedKeys(r.PaginationResult.RemovedResponses) {
+			changes = append(changes, fmt.Sprintf("%s: removed responses %s", operation,
+				strings.Join(r.PaginationResult.RemovedResponses[operation], ", ")))
+		}
+	}
+	if r.RenameResult != nil {
+		for _, file := range sortedKeys(r.RenameResult.RenamedComponents) {
+			for _, rename := range r.RenameResult.RenamedComponents[file] {
+				changes = append(changes, fmt.Sprintf("%s: renamed %s", file, rename))
+			}
+		}
+	}
+	return changes
+}
+
+// Send posts the summary to every webhook whose trigger matches and returns the errors of
+// webhooks that could not be notified
+func Send(cfg config.Notify, summary Summary) []error {
+	client := &http.Client{Timeout: requestTimeout}
+
+	var errs []error
+	for i, webhook := range cfg.Webhooks {
+		if !shouldNotify(webhook, summary) {
+			continue
+		}
+		if err := post(client, webhook, summary); err != nil {
+			errs = append(errs, fmt.Errorf("notify.webhooks[%d]: %w", i, err))
+		}
+	}
+	return errs
+}
+
+// Validate checks the format and trigger of every webhook
+func Validate(cfg config.Notify) error {
+	for i, webhook := range cfg.Webhooks {
+		if webhook.URL == "" {
+			return fmt.Errorf("notify.webhooks[%d]: url is required", i)
+		}
+		switch webhook.Format {
+		case "", FormatJSON, FormatSlack:
+		default:
+			return fmt.Errorf("notify.webhooks[%d]: unknown format %q (supported: %s, %s)", i, webhook.Format, FormatJSON, FormatSlack)
+		}
+		switch webhook.On {
+		case "", OnAlways, OnChanges, OnFailure:
+		default:
+			return fmt.Errorf("notify.webhooks[%d]: unknown trigger %q (supported: %s, %s, %s)", i, webhook.On, OnAlways, OnChanges, OnFailure)
+		}
+	}
+	return nil
+}
+
+// shouldNotify reports whether the webhook's trigger matches the run
+func shouldNotify(webhook config.Webhook, summary Summary) bool {
+	failed := summary.Status == StatusFailed || summary.Validation == ValidationFailed
+	switch webhook.On {
+	case OnFailure:
+		return failed
+	case OnChanges:
+		return failed || len(summary.FilesChanged) > 0
+	default:
+		return true
+	}
+}
+
+// post sends the summary to one webhook
+func post(client *http.Client, webhook config.Webhook, summary Summary) error {
+	var payload interface{} = summary
+	if webhook.Format == FormatSlack {
+		payload = map[string]string{"text": SlackText(summary)}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, os.ExpandEnv(webhook.URL), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook: %v", withoutURL(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range webhook.Headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", withoutURL(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// withoutURL drops the webhook URL from a request error. The URL often carries a token, and the
+// error ends up in CI logs, so webhooks are only ever reported by their index.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// SlackText renders the summary as a Slack message
+func SlackText(summary Summary) string {
+	var b strings.Builder
+	icon := ":white_check_mark:"
+	if summary.Status == StatusFailed || summary.Validation == ValidationFailed {
+		icon = ":x:"
+	}
+	fmt.Fprintf(&b, "%s *OpenMorph* run on `%s`: %s\n", icon, summary.Input, summary.Status)
+	if summary.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", summary.Error)
+	}
+	fmt.Fprintf(&b, "Files changed: %d, changes: %d, breaking changes: %d\n",
+		len(summary.FilesChanged), summary.Changes, len(summary.BreakingChanges))
+	fmt.Fprintf(&b, "Validation: %s", summary.Validation)
+	for i, change := range summary.BreakingChanges {
+		if i == maxSlackBreakingChanges {
+			fmt.Fprintf(&b, "\n…and %d more", len(summary.BreakingChanges)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n• %s", change)
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
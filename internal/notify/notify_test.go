@@ -0,0 +1,175 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+func testResults() *transform.TransformationResults {
+	return &transform.TransformationResults{
+		Changed:    []string{"specs/a.yaml"},
+		KeyChanges: []transform.KeyChange{{File: "specs/a.yaml", OldKey: "x-a", NewKey: "x-b", Line: 3}},
+		InternalResult: &transform.InternalResult{
+			RemovedItems: map[string][]string{"specs/b.yaml": {"operation GET /admin"}},
+		},
+		PaginationResult: &transform.PaginationResult{
+			RemovedParams:    map[string][]string{"GET /users": {"offset", "limit"}},
+			RemovedResponses: map[string][]string{},
+		},
+		RenameResult: &transform.RenameResult{
+			RenamedComponents: map[string][]string{"specs/a.yaml": {"schemas.UserV2 -> User"}},
+		},
+	}
+}
+
+func TestNewSummary(t *testing.T) {
+	summary := NewSummary("specs", "v1.0.0", ValidationPassed, nil, testResults(), nil)
+
+	if summary.Status != StatusSuccess || summary.Changes != 1 || summary.Validation != ValidationPassed {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if len(summary.FilesChanged) != 1 || summary.FilesChanged[0] != "specs/a.yaml" {
+		t.Errorf("unexpected files changed: %v", summary.FilesChanged)
+	}
+	want := []string{
+		"specs/b.yaml: removed operation GET /admin",
+		"GET /users: removed parameters offset, limit",
+		"specs/a.yaml: renamed schemas.UserV2 -> User",
+	}
+	if strings.Join(summary.BreakingChanges, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected breaking changes:\n%s", strings.Join(summary.BreakingChanges, "\n"))
+	}
+
+	failed := NewSummary("specs", "v1.0.0", ValidationSkipped, errors.New("boom"))
+	if failed.Status != StatusFailed || failed.Error != "boom" {
+		t.Errorf("expected failed summary, got %+v", failed)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var received []string
+	var authHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r.URL.Path+" "+string(body))
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("OPENMORPH_TEST_TOKEN", "secret")
+	cfg := config.Notify{Webhooks: []config.Webhook{
+		{URL: server.URL + "/json", Headers: map[string]string{"Authorization": "Bearer ${OPENMORPH_TEST_TOKEN}"}},
+		{URL: server.URL + "/slack", Format: FormatSlack, On: OnChanges},
+		{URL: server.URL + "/failure", On: OnFailure},
+		{URL: server.URL + "/broken"},
+	}}
+
+	errs := Send(cfg, NewSummary("specs", "v1.0.0", ValidationSkipped, nil, testResults()))
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "notify.webhooks[3]") {
+		t.Errorf("expected one error for the broken webhook, got %v", errs)
+	}
+	if len(received) != 3 {
+		t.Fatalf("expected 3 requests (failure-only webhook skipped), got %v", received)
+	}
+	if authHeaders[0] != "Bearer secret" {
+		t.Errorf("expected expanded Authorization header, got %q", authHeaders[0])
+	}
+
+	var summary Summary
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(received[0], "/json ")), &summary); err != nil {
+		t.Fatalf("expected JSON summary payload: %v", err)
+	}
+	if summary.Input != "specs" || len(summary.BreakingChanges) != 3 {
+		t.Errorf("unexpected JSON payload: %+v", summary)
+	}
+
+	var slack map[string]string
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(received[1], "/slack ")), &slack); err != nil {
+		t.Fatalf("expected Slack payload: %v", err)
+	}
+	if !strings.Contains(slack["text"], "Files changed: 1, changes: 1, breaking changes: 3") {
+		t.Errorf("unexpected Slack text: %s", slack["text"])
+	}
+}
+
+func TestSendKeepsURLOutOfErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	unreachable := server.URL
+	server.Close()
+
+	t.Setenv("OPENMORPH_TEST_TOKEN", "secret-token")
+	cfg := config.Notify{Webhooks: []config.Webhook{
+		{URL: unreachable + "/hooks/${OPENMORPH_TEST_TOKEN}"},
+		{URL: "http://exa mple.com/hooks/${OPENMORPH_TEST_TOKEN}"},
+	}}
+
+	errs := Send(cfg, NewSummary("specs", "v1.0.0", ValidationSkipped, nil, testResults()))
+	if len(errs) != 2 {
+		t.Fatalf("expected an error for both webhooks, got %v", errs)
+	}
+	for i, err := range errs {
+		if strings.Contains(err.Error(), "secret-token") || strings.Contains(err.Error(), "/hooks/") {
+			t.Errorf("expected the webhook URL to be left out, got %q", err)
+		}
+		if want := fmt.Sprintf("notify.webhooks[%d]", i); !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to name %s, got %q", want, err)
+		}
+	}
+}
+
+func TestShouldNotify(t *testing.T) {
+	unchanged := Summary{Status: StatusSuccess, Validation: ValidationPassed}
+	invalid := Summary{Status: StatusSuccess, Validation: ValidationFailed}
+	changed := Summary{Status: StatusSuccess, FilesChanged: []string{"a.yaml"}}
+
+	tests := []struct {
+		on      string
+		summary Summary
+		want    bool
+	}{
+		{"", unchanged, true},
+		{OnChanges, unchanged, false},
+		{OnChanges, changed, true},
+		{OnChanges, invalid, true},
+		{OnFailure, changed, false},
+		{OnFailure, invalid, true},
+	}
+	for _, tt := range tests {
+		if got := shouldNotify(config.Webhook{On: tt.on}, tt.summary); got != tt.want {
+			t.Errorf("shouldNotify(on=%q, %+v) = %v, want %v", tt.on, tt.summary, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		webhook config.Webhook
+		want    string
+	}{
+		{config.Webhook{URL: "https://example.com"}, ""},
+		{config.Webhook{}, "url is required"},
+		{config.Webhook{URL: "https://example.com", Format: "teams"}, "unknown format"},
+		{config.Webhook{URL: "https://example.com", On: "sometimes"}, "unknown trigger"},
+	}
+	for _, tt := range tests {
+		err := Validate(config.Notify{Webhooks: []config.Webhook{tt.webhook}})
+		if tt.want == "" && err != nil {
+			t.Errorf("unexpected error for %+v: %v", tt.webhook, err)
+		}
+		if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("expected error containing %q for %+v, got %v", tt.want, tt.webhook, err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// contentParamSchema returns the schema of a parameter serialized through content rather than
+// schema, such as a JSON-encoded query parameter, following the parameter's $ref. It returns nil
+// for other parameters.
+func contentParamSchema(param *yaml.Node, doc *yaml.Node) *yaml.Node {
+	if ref := getNodeValue(param, "$ref"); ref != nil {
+		param = resolveRef(ref.Value, doc)
+	}
+	content := getNodeValue(param, "content")
+	if content == nil || content.Kind != yaml.MappingNode || len(content.Content) < 2 {
+		return nil
+	}
+	return getNodeValue(content.Content[1], "schema")
+}
+
+// contentParamFields returns the property names of a content parameter's schema, so pagination
+// fields bundled into one serialized parameter are detected like separate parameters
+func contentParamFields(param *yaml.Node, doc *yaml.Node) []string {
+	return extractFieldsFromSchemaWithDoc(contentParamSchema(param, doc), doc)
+}
+
+// pruneContentParam removes the properties of a content parameter's inline schema that belong to
+// a strategy other than the selected one, returning them as "<param>.<property>". It reports
+// whether removing them left the schema without properties, in which case the parameter goes too.
+// Referenced parameters and schemas are shared, so they are left alone.
+func pruneContentParam(param *yaml.Node, paramName, selectedStrategy string, detected []DetectedPagination) ([]string, bool) {
+	if getNodeValue(param, "$ref") != nil {
+		return nil, false
+	}
+	schema := contentParamSchema(param, nil)
+	if schema == nil || getNodeValue(schema, "$ref") != nil {
+		return nil, false
+	}
+	properties := getNodeValue(schema, "properties")
+	if properties == nil || properties.Kind != yaml.MappingNode {
+		return nil, false
+	}
+
+	var removed []string
+	var kept []*yaml.Node
+	for i := 0; i+1 < len(properties.Content); i += 2 {
+		name := properties.Content[i].Value
+		if shouldKeepParameter(name, selectedStrategy, detected) {
+			kept = append(kept, properties.Content[i], properties.Content[i+1])
+			continue
+		}
+		removed = append(removed, paramName+"."+name)
+		removeRequired(schema, name)
+	}
+	if len(removed) == 0 {
+		return nil, false
+	}
+	properties.Content = kept
+	return removed, len(kept) == 0
+}
+
+// removeRequired drops a property from a schema's required list, and the list once it is empty
+func removeRequired(schema *yaml.Node, name string) {
+	required := getNodeValue(schema, "required")
+	if required == nil || required.Kind != yaml.SequenceNode {
+		return
+	}
+	var kept []*yaml.Node
+	for _, item := range required.Content {
+		if item.Value != name {
+			kept = append(kept, item)
+		}
+	}
+	required.Content = kept
+	if len(kept) == 0 {
+		removeKey(schema, "required")
+	}
+}
@@ -0,0 +1,136 @@
+package pagination
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const contentParamSpec = `
+paths:
+  /users:
+    get:
+      parameters:
+        - name: paging
+          in: query
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [cursor, offset]
+                properties:
+                  cursor:
+                    type: string
+                  size:
+                    type: integer
+                  offset:
+                    type: integer
+                  limit:
+                    type: integer
+        - name: filter
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+  /teams:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Page'
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+components:
+  parameters:
+    Page:
+      name: window
+      in: query
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/PageQuery'
+  schemas:
+    PageQuery:
+      type: object
+      properties:
+        offset:
+          type: integer
+        limit:
+          type: integer
+`
+
+func TestDetectPaginationInContentParams(t *testing.T) {
+	doc := parseDoc(t, contentParamSpec)
+	params := getNodeValue(getNodeValue(getNodeValue(getNodeValue(doc, "paths"), "/teams"), "get"), "parameters")
+
+	var strategies []string
+	for _, d := range DetectPaginationInParamsWithDoc(params, doc) {
+		strategies = append(strategies, d.Strategy)
+	}
+	if want := []string{"cursor", "offset"}; !reflect.DeepEqual(strategies, want) {
+		t.Errorf("expected strategies %v, got %v", want, strategies)
+	}
+}
+
+func TestProcessEndpointPrunesContentParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		priority    []string
+		wantRemoved []string
+		wantFields  []string
+	}{
+		{"keeps the selected strategy's fields", []string{"cursor", "offset"}, []string{"paging.offset", "paging.limit"}, []string{"cursor", "size"}},
+		{"drops the parameter when no field is left", []string{"none"}, []string{"paging"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseDoc(t, contentParamSpec)
+			operation := getNodeValue(getNodeValue(getNodeValue(doc, "paths"), "/users"), "get")
+
+			result, err := ProcessEndpointWithDoc(operation, doc, Options{Priority: tt.priority})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.RemovedParams, tt.wantRemoved) {
+				t.Fatalf("expected removed params %v, got %v", tt.wantRemoved, result.RemovedParams)
+			}
+
+			params := getNodeValue(operation, "parameters")
+			if tt.wantFields == nil {
+				if len(params.Content) != 1 || getStringValue(params.Content[0], "name") != "filter" {
+					t.Errorf("expected only the filter parameter to be left, got %d parameters", len(params.Content))
+				}
+				return
+			}
+			schema := contentParamSchema(params.Content[0], doc)
+			if got := extractFieldsFromSchema(schema); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("expected fields %v, got %v", tt.wantFields, got)
+			}
+			if got := getNodeValue(schema, "required"); len(got.Content) != 1 || got.Content[0].Value != "cursor" {
+				t.Errorf("expected offset to leave the required list")
+			}
+		})
+	}
+}
+
+func TestProcessEndpointLeavesReferencedContentParams(t *testing.T) {
+	doc := parseDoc(t, contentParamSpec)
+	operation := getNodeValue(getNodeValue(getNodeValue(doc, "paths"), "/teams"), "get")
+
+	result, err := ProcessEndpointWithDoc(operation, doc, Options{Priority: []string{"cursor"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.RemovedParams) != 0 {
+		t.Errorf("expected the shared parameter to be left alone, got %v", result.RemovedParams)
+	}
+	schema := getNodeValue(getNodeValue(getNodeValue(doc, "components"), "schemas"), "PageQuery")
+	if fields := strings.Join(extractFieldsFromSchema(schema), ","); fields != "offset,limit" {
+		t.Errorf("expected the shared schema to be left alone, got fields %q", fields)
+	}
+}
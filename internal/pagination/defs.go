@@ -0,0 +1,77 @@
+package pagination
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaScope holds the schemas enclosing the one being followed that declare $defs, innermost last.
+// OpenAPI 3.1 schemas can keep their own definitions, referenced as "#/$defs/Name" from within.
+type schemaScope []*yaml.Node
+
+// enter returns the scope for the subschemas of schema
+func (s schemaScope) enter(schema *yaml.Node) schemaScope {
+	if getNodeValue(schema, "$defs") == nil {
+		return s
+	}
+	return append(s[:len(s):len(s)], schema)
+}
+
+// resolveInScope resolves a $ref against the document, falling back, for a "#/$defs/..." reference,
+// to the $defs of the enclosing schemas, innermost first
+func resolveInScope(ref string, doc *yaml.Node, scope schemaScope) *yaml.Node {
+	if resolved := resolveRef(ref, doc); resolved != nil {
+		return resolved
+	}
+	if !strings.HasPrefix(ref, "#/$defs/") {
+		return nil
+	}
+	for i := len(scope) - 1; i >= 0; i-- {
+		if resolved := resolveRef(ref, scope[i]); resolved != nil {
+			return resolved
+		}
+	}
+	return nil
+}
+
+// resolveDynamicRef resolves a $dynamicRef on a best-effort basis. Its dynamic scope is not
+// evaluated: a "#name" reference resolves to the first schema declaring that $dynamicAnchor (or
+// $anchor) within the enclosing schemas, innermost first, then anywhere in the document. A JSON
+// pointer resolves like a $ref.
+func resolveDynamicRef(ref string, doc *yaml.Node, scope schemaScope) *yaml.Node {
+	if strings.HasPrefix(ref, "#/") {
+		return resolveInScope(ref, doc, scope)
+	}
+	anchor, ok := strings.CutPrefix(ref, "#")
+	if !ok || anchor == "" {
+		return nil
+	}
+	for i := len(scope) - 1; i >= 0; i-- {
+		if found := findAnchor(scope[i], anchor); found != nil {
+			return found
+		}
+	}
+	return findAnchor(doc, anchor)
+}
+
+// findAnchor returns the first mapping below node declaring anchor as its $dynamicAnchor or $anchor
+func findAnchor(node *yaml.Node, anchor string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.MappingNode && (getStringValue(node, "$dynamicAnchor") == anchor || getStringValue(node, "$anchor") == anchor) {
+		return node
+	}
+	for _, child := range node.Content {
+		if found := findAnchor(child, anchor); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// unescapePointerToken decodes the ~1 and ~0 escapes of a JSON pointer segment
+func unescapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
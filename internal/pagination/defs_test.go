@@ -0,0 +1,116 @@
+package pagination
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const defsSpec = `
+openapi: 3.1.0
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/UserPage'
+  /teams:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $dynamicRef: '#page'
+        "206":
+          description: Partial
+          content:
+            application/json:
+              schema:
+                $dynamicRef: '#missing'
+components:
+  schemas:
+    UserPage:
+      $defs:
+        Meta:
+          type: object
+          properties:
+            next_cursor:
+              type: string
+      allOf:
+        - $ref: '#/$defs/Meta'
+        - type: object
+          properties:
+            data:
+              type: array
+    TeamPage:
+      $dynamicAnchor: page
+      type: object
+      properties:
+        offset:
+          type: integer
+        children:
+          $dynamicRef: '#page'
+      anyOf:
+        - $dynamicRef: '#page'
+    "a/b":
+      properties:
+        page:
+          type: integer
+`
+
+func TestExtractFieldsFromSchemaWithDefs(t *testing.T) {
+	doc := parseDoc(t, defsSpec)
+	tests := []struct {
+		name string
+		ref  string
+		want []string
+	}{
+		{"component-scoped $defs", "#/components/schemas/UserPage", []string{"next_cursor", "data"}},
+		{"recursive $dynamicRef", "#/components/schemas/TeamPage", []string{"offset", "children"}},
+		{"escaped pointer", "#/components/schemas/a~1b", []string{"page"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := parseDoc(t, "$ref: '"+tt.ref+"'")
+			if got := extractFieldsFromSchemaWithDoc(schema, doc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected fields %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	responses := getNodeValue(getNodeValue(getNodeValue(getNodeValue(doc, "paths"), "/teams"), "get"), "responses")
+	var strategies []string
+	for _, d := range DetectPaginationInResponsesWithDoc(responses, doc) {
+		strategies = append(strategies, d.Strategy)
+	}
+	if want := []string{"offset"}; !reflect.DeepEqual(strategies, want) {
+		t.Errorf("expected the $dynamicRef response to be detected as %v, got %v", want, strategies)
+	}
+}
+
+func TestFollowRefsReportsDynamicRefs(t *testing.T) {
+	doc := parseDoc(t, defsSpec)
+	paths := getNodeValue(doc, "paths")
+
+	unresolved, dynamic := followRefs(nil, getNodeValue(getNodeValue(paths, "/users"), "get"), doc)
+	if len(unresolved) != 0 || len(dynamic) != 0 {
+		t.Errorf("expected the scoped $defs reference to resolve, got %v %v", unresolved, dynamic)
+	}
+
+	unresolved, dynamic = followRefs(nil, getNodeValue(getNodeValue(paths, "/teams"), "get"), doc)
+	if len(unresolved) != 1 || unresolved[0].Reason() != "unresolved $dynamicRef #missing" {
+		t.Errorf("expected the missing anchor to be unresolved, got %+v", unresolved)
+	}
+	if len(dynamic) != 3 {
+		t.Fatalf("expected three $dynamicRefs, got %+v", dynamic)
+	}
+	if warning := dynamic[0].Warning(); !strings.Contains(warning, "$dynamicRef #page resolved best-effort to the schema at line 44") {
+		t.Errorf("unexpected warning %q", warning)
+	}
+}
@@ -0,0 +1,190 @@
+package pagination
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// paramIdentity identifies a parameter the way OpenAPI does: by name and location, after
+// resolving $ref
+type paramIdentity struct {
+	name string
+	in   string
+}
+
+// identifyParameter returns a parameter's identity and its resolved definition. The definition
+// is nil when a $ref cannot be resolved.
+func identifyParameter(param *yaml.Node, doc *yaml.Node) (paramIdentity, *yaml.Node) {
+	resolved := param
+	if ref := getNodeValue(param, "$ref"); ref != nil {
+		resolved = resolveRef(ref.Value, doc)
+		if resolved == nil {
+			return paramIdentity{}, nil
+		}
+	}
+	return paramIdentity{name: getStringValue(resolved, "name"), in: getStringValue(resolved, "in")}, resolved
+}
+
+// mergeDuplicateParams collapses pagination parameters declared more than once in a parameters
+// list, inline or via $ref, into a single definition. A $ref definition is kept over inline ones
+// because it is shared with other operations; among inline definitions the first is kept and
+// gains any fields only the dropped ones set. Returns a description of every dropped definition.
+func mergeDuplicateParams(params *yaml.Node, doc *yaml.Node) []string {
+	if params == nil || params.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	groups := make(map[paramIdentity][]int)
+	var order []paramIdentity
+	for i, param := range params.Content {
+		if param.Kind != yaml.MappingNode {
+			continue
+		}
+		id, resolved := identifyParameter(param, doc)
+		if resolved == nil || id.name == "" || !isPaginationParam(id.name) {
+			continue
+		}
+		if _, seen := groups[id]; !seen {
+			order = append(order, id)
+		}
+		groups[id] = append(groups[id], i)
+	}
+
+	drop := make(map[int]bool)
+	var merged []string
+	for _, id := range order {
+		indexes := groups[id]
+		if len(indexes) < 2 {
+			continue
+		}
+
+		keep := indexes[0]
+		for _, i := range indexes {
+			if getNodeValue(params.Content[i], "$ref") != nil {
+				keep = i
+				break
+			}
+		}
+		kept := params.Content[keep]
+		for _, i := range indexes {
+			if i == keep {
+				continue
+			}
+			if getNodeValue(kept, "$ref") == nil {
+				mergeMissingFields(kept, params.Content[i])
+			}
+			drop[i] = true
+			merged = append(merged, fmt.Sprintf("%s: duplicate %s definition", describeParam(id), definitionKind(params.Content[i])))
+		}
+	}
+	if len(drop) == 0 {
+		return nil
+	}
+
+	var newContent []*yaml.Node
+	for i, param := range params.Content {
+		if !drop[i] {
+			newContent = append(newContent, param)
+		}
+	}
+	params.Content = newContent
+	return merged
+}
+
+// removeRedundantOperationParams removes pagination parameters an operation repeats from its
+// path item, since the path-level definition already applies. An operation-level definition
+// that differs from the path-level one is an override and is kept.
+func removeRedundantOperationParams(params, pathParams *yaml.Node, doc *yaml.Node) []string {
+	if params == nil || params.Kind != yaml.SequenceNode || pathParams == nil || pathParams.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	pathDefinitions := make(map[paramIdentity]*yaml.Node)
+	for _, param := range pathParams.Content {
+		if id, resolved := identifyParameter(param, doc); resolved != nil && id.name != "" {
+			pathDefinitions[id] = resolved
+		}
+	}
+
+	var removed []string
+	var newContent []*yaml.Node
+	for _, param := range params.Content {
+		id, resolved := identifyParameter(param, doc)
+		pathDefinition, declared := pathDefinitions[id]
+		if param.Kind != yaml.MappingNode || resolved == nil || !declared || !isPaginationParam(id.name) ||
+			!nodesEquivalent(resolved, pathDefinition) {
+			newContent = append(newContent, param)
+			continue
+		}
+		removed = append(removed, fmt.Sprintf("%s: repeats the path-level definition", describeParam(id)))
+	}
+	if len(removed) > 0 {
+		params.Content = newContent
+	}
+	return removed
+}
+
+// mergeMissingFields copies into dst the fields that only src sets
+func mergeMissingFields(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		if getNodeValue(dst, src.Content[i].Value) == nil {
+			dst.Content = append(dst.Content, src.Content[i], src.Content[i+1])
+		}
+	}
+}
+
+// nodesEquivalent reports whether two nodes hold the same value, ignoring mapping key order,
+// comments and positions
+func nodesEquivalent(a, b *yaml.Node) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil || a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+	switch a.Kind {
+	case yaml.ScalarNode:
+		return a.Value == b.Value
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			if !nodesEquivalent(a.Content[i+1], getNodeValue(b, a.Content[i].Value)) {
+				return false
+			}
+		}
+		return true
+	default:
+		for i := range a.Content {
+			if !nodesEquivalent(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func describeParam(id paramIdentity) string {
+	if id.in == "" {
+		return id.name
+	}
+	return fmt.Sprintf("%s (%s)", id.name, id.in)
+}
+
+func definitionKind(param *yaml.Node) string {
+	if ref := getNodeValue(param, "$ref"); ref != nil {
+		return "$ref " + ref.Value
+	}
+	return "inline"
+}
+
+// isPaginationParam reports whether a parameter name is used by any pagination strategy
+func isPaginationParam(paramName string) bool {
+	for _, strategy := range PaginationStrategies {
+		for _, strategyParam := range strategy.Params {
+			if matchesParam(paramName, strategyParam) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package pagination
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func parseDoc(t *testing.T, content string) *yaml.Node {
+	t.Helper()
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		t.Fatalf("failed to parse YAML: %v", err)
+	}
+	return doc.Content[0]
+}
+
+func paramNames(t *testing.T, params *yaml.Node, doc *yaml.Node) []string {
+	t.Helper()
+	var names []string
+	for _, param := range params.Content {
+		id, _ := identifyParameter(param, doc)
+		names = append(names, id.name)
+	}
+	return names
+}
+
+func TestProcessEndpointMergesDuplicateParams(t *testing.T) {
+	doc := parseDoc(t, `
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - $ref: '#/components/parameters/Cursor'
+        - name: size
+          in: query
+          schema:
+            type: integer
+        - name: size
+          in: query
+          description: Page size
+        - name: cursor
+          in: header
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+components:
+  parameters:
+    Cursor:
+      name: cursor
+      in: query
+      schema:
+        type: string
+`)
+	operation := getNodeValue(getNodeValue(getNodeValue(doc, "paths"), "/users"), "get")
+
+	result, err := ProcessEndpointInPathItem(nil, operation, doc, "/users", "get", Options{Priority: []string{"cursor"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed || len(result.MergedParams) != 2 {
+		t.Fatalf("expected 2 merged definitions, got %+v", result)
+	}
+
+	params := getNodeValue(operation, "parameters")
+	if len(params.Content) != 3 {
+		t.Fatalf("expected cursor (query), size and cursor (header), got %v", paramNames(t, params, doc))
+	}
+	if ref := getStringValue(params.Content[0], "$ref"); ref != "#/components/parameters/Cursor" {
+		t.Errorf("expected the $ref definition of cursor to be kept, got %q", ref)
+	}
+	size := params.Content[1]
+	if getStringValue(size, "description") != "Page size" || getNodeValue(size, "schema") == nil {
+		t.Errorf("expected the inline size definitions to be merged, got %v", size.Content)
+	}
+}
+
+func TestProcessEndpointRemovesParamsRepeatedFromPathItem(t *testing.T) {
+	doc := parseDoc(t, `
+paths:
+  /users:
+    parameters:
+      - name: cursor
+        in: query
+        schema:
+          type: string
+      - name: size
+        in: query
+        schema:
+          type: integer
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: size
+          in: query
+          schema:
+            type: integer
+            maximum: 50
+      responses:
+        "200":
+          description: OK
+`)
+	pathItem := getNodeValue(getNodeValue(doc, "paths"), "/users")
+	operation := getNodeValue(pathItem, "get")
+
+	result, err := ProcessEndpointInPathItem(pathItem, operation, doc, "/users", "get", Options{Priority: []string{"cursor"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.MergedParams) != 1 || result.MergedParams[0] != "cursor (query): repeats the path-level definition" {
+		t.Fatalf("expected the repeated cursor definition to be removed, got %v", result.MergedParams)
+	}
+	names := paramNames(t, getNodeValue(operation, "parameters"), doc)
+	if len(names) != 1 || names[0] != "size" {
+		t.Errorf("expected the overriding size definition to be kept, got %v", names)
+	}
+}
+
+func TestNodesEquivalent(t *testing.T) {
+	a := parseDoc(t, "name: limit\nin: query\nschema: {type: integer}\n")
+	b := parseDoc(t, "in: query\nschema:\n  type: integer\nname: limit # comment\n")
+	c := parseDoc(t, "name: limit\nin: query\nschema: {type: string}\n")
+	if !nodesEquivalent(a, b) {
+		t.Error("expected key order and comments to be ignored")
+	}
+	if nodesEquivalent(a, c) {
+		t.Error("expected different schemas to differ")
+	}
+}
@@ -1,6 +1,7 @@
 package pagination
 
 import (
+	"regexp"
 	"strings"
 	"testing"
 
@@ -71,12 +72,17 @@ func TestPatternMatching(t *testing.T) {
 		{"exact match", "/api/v1/users", "/api/v1/users", true},
 		{"wildcard match", "/api/v1/users/123", "/api/v1/users/*", true},
 		{"no match", "/api/v1/posts", "/api/v1/users/*", false},
-		{"base path with wildcard", "/api/v1/users", "/api/v1/users/*", true},
-		{"complex path match", "/api/v1/users/123/posts", "/api/v1/users/*", true},
+		{"base path with double wildcard", "/api/v1/users", "/api/v1/users/**", true},
+		{"base path with single wildcard", "/api/v1/users", "/api/v1/users/*", false},
+		{"complex path match", "/api/v1/users/123/posts", "/api/v1/users/**", true},
+		{"single wildcard stays in one segment", "/api/v1/users/123/posts", "/api/v1/users/*", false},
+		{"inner wildcard", "/api/v1/users/123/posts", "/api/*/users/*/posts", true},
+		{"inner double wildcard", "/api/v1/users/123/posts", "/api/**/posts", true},
+		{"path parameter names are ignored", "/api/v1/users/{user_id}", "/api/v1/users/{id}", true},
 		{"wildcard without slash", "/api/v1/users123", "/api/v1/users*", true},
 		{"partial match fails", "/api/v1/user", "/api/v1/users/*", false},
 		{"root wildcard", "/", "/*", true},
-		{"deep nested match", "/api/v1/users/123/posts/456/comments", "/api/v1/users/*", true},
+		{"deep nested match", "/api/v1/users/123/posts/456/comments", "/api/v1/users/**", true},
 	}
 
 	for _, tc := range testCases {
@@ -274,12 +280,13 @@ func TestComplexWildcardPatterns(t *testing.T) {
 		endpoint string
 		expected bool
 	}{
-		{"root wildcard matches all", "/*", "/api/users", true},
+		{"root wildcard matches all", "/**", "/api/users", true},
+		{"root single wildcard matches one segment", "/*", "/api/users", false},
 		{"root wildcard matches root", "/*", "/", true},
 		{"versioned API wildcard", "/api/v1/*", "/api/v1/users", true},
-		{"versioned API wildcard exact", "/api/v1/*", "/api/v1", true},
+		{"versioned API wildcard exact", "/api/v1/**", "/api/v1", true},
 		{"no match different version", "/api/v1/*", "/api/v2/users", false},
-		{"deep nesting", "/api/v1/users/*", "/api/v1/users/123/posts/456", true},
+		{"deep nesting", "/api/v1/users/**", "/api/v1/users/123/posts/456", true},
 		{"empty pattern", "", "/api/users", false},
 		{"empty endpoint", "", "", true},
 		{"trailing slash pattern", "/api/", "/api", false},
@@ -518,18 +525,65 @@ responses:
 	}
 }
 
+func TestNoneStrategyCleansUpOperation(t *testing.T) {
+	operationYAML := `
+description: |-
+  Lists legacy records. Results are paginated with offset and limit.
+
+  Use the next page link to continue. Records are sorted by id.
+parameters:
+- name: offset
+  in: query
+  schema:
+    type: integer
+- name: limit
+  in: query
+  schema:
+    type: integer
+responses:
+  '200':
+    description: Success
+`
+
+	var opNode yaml.Node
+	if err := yaml.Unmarshal([]byte(operationYAML), &opNode); err != nil {
+		t.Fatalf("Failed to unmarshal operation YAML: %v", err)
+	}
+	operation := opNode.Content[0]
+
+	opts := Options{
+		Priority:            []string{"none"},
+		DescriptionPatterns: []*regexp.Regexp{regexp.MustCompile(`(?i)paginat`), regexp.MustCompile(`(?i)next page`)},
+	}
+	result, err := ProcessEndpointWithPathAndMethod(operation, nil, "/legacy", "GET", opts)
+	if err != nil {
+		t.Fatalf("ProcessEndpointWithPathAndMethod failed: %v", err)
+	}
+
+	if getNodeValue(operation, "parameters") != nil {
+		t.Error("Expected the emptied parameters list to be removed")
+	}
+	want := "Lists legacy records.\n\nRecords are sorted by id."
+	if got := getStringValue(operation, "description"); got != want {
+		t.Errorf("Expected description %q, got %q", want, got)
+	}
+	if len(result.ScrubbedSentences) != 2 {
+		t.Errorf("Expected 2 scrubbed sentences, got %v", result.ScrubbedSentences)
+	}
+}
+
 func TestWildcardPrecedenceRules(t *testing.T) {
 	// Test that more specific patterns should be placed first for expected behavior
 	// This test demonstrates the current behavior where first match wins
 
 	rules := []EndpointPaginationRule{
 		{
-			Endpoint:   "/api/*", // Broad wildcard
+			Endpoint:   "/api/**", // Broad wildcard
 			Method:     "GET",
 			Pagination: "offset",
 		},
 		{
-			Endpoint:   "/api/v1/*", // More specific
+			Endpoint:   "/api/v1/**", // More specific
 			Method:     "GET",
 			Pagination: "cursor",
 		},
@@ -578,12 +632,12 @@ func TestWildcardPrecedenceRules(t *testing.T) {
 			Pagination: "checkpoint",
 		},
 		{
-			Endpoint:   "/api/v1/*", // More specific
+			Endpoint:   "/api/v1/**", // More specific
 			Method:     "GET",
 			Pagination: "cursor",
 		},
 		{
-			Endpoint:   "/api/*", // Broad wildcard last
+			Endpoint:   "/api/**", // Broad wildcard last
 			Method:     "GET",
 			Pagination: "offset",
 		},
@@ -872,7 +926,8 @@ func TestAdvancedPatternMatching(t *testing.T) {
 		// Mixed with existing patterns
 		{"/api/v1/analytics", "/api/*/analytics", true, "Middle wildcard"},
 		{"/api/legacy/users", "/api/legacy/*", true, "Suffix wildcard"},
-		{"/api/legacy/users/123", "/api/legacy/*", true, "Suffix wildcard with path"},
+		{"/api/legacy/users/123", "/api/legacy/*", false, "Suffix wildcard with path"},
+		{"/api/legacy/users/123", "/api/legacy/**", true, "Suffix double wildcard with path"},
 	}
 
 	for _, tc := range testCases {
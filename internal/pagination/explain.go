@@ -0,0 +1,38 @@
+package pagination
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Selection is how the pagination strategy of an operation is selected
+type Selection struct {
+	Detected []DetectedPagination    // evidence of each strategy, params first
+	Rule     *EndpointPaginationRule // first endpoint rule matching the operation, nil when the priority list decides
+	Priority []string                // strategies tried in order: the rule's, or the priority list
+	Strategy string                  // selected strategy, empty when none is
+	Tie      *Tie                    // set when the config does not decide the strategy
+}
+
+// ExplainSelection returns how the strategy of an operation of the path item pathItem (which can
+// be nil) is selected, without changing anything. As when processing, operations without
+// pagination parameters have no strategy selected.
+func ExplainSelection(pathItem, operation *yaml.Node, doc *yaml.Node, endpoint, method string, opts Options) Selection {
+	var selection Selection
+	if operation == nil || operation.Kind != yaml.MappingNode {
+		return selection
+	}
+
+	params := withInheritedParams(getNodeValue(operation, "parameters"),
+		inheritedParams(getNodeValue(operation, "parameters"), getNodeValue(pathItem, "parameters"), doc))
+	strategies := detectPaginationStrategies(params, getNodeValue(operation, "responses"), doc)
+	selection.Detected = strategies.allPagination
+	if rules := matchingRules(endpoint, method, opts.EndpointRules); len(rules) > 0 {
+		selection.Rule = &rules[0]
+	}
+	selection.Priority = opts.GetPaginationStrategy(endpoint, method)
+	if len(strategies.paramStrategies) > 0 {
+		selection.Strategy = selectBestStrategy(strategies, Options{Priority: selection.Priority})
+	}
+	selection.Tie = FindTie(pathItem, operation, doc, endpoint, method, opts)
+	return selection
+}
@@ -0,0 +1,79 @@
+package pagination
+
+import (
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParamStrategies returns the strategies whose parameters an operation uses, sorted. Parameters
+// shared by several strategies, such as limit, do not count on their own.
+func ParamStrategies(params *yaml.Node, doc *yaml.Node) []string {
+	var strategies []string
+	for _, detected := range DetectPaginationInParamsWithDoc(params, doc) {
+		strategies = append(strategies, detected.Strategy)
+	}
+	sort.Strings(strategies)
+	return strategies
+}
+
+// StrayFields returns the fields of the success responses of an operation that belong to a single
+// strategy other than selected, sorted and without duplicates. Fields listed in keep are left out.
+// Once an operation was cleaned up for selected, none should remain.
+func StrayFields(responses *yaml.Node, selected string, keep []string, doc *yaml.Node) []string {
+	if responses == nil || responses.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var stray []string
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		if !isSuccessResponse(responses.Content[i].Value) {
+			continue
+		}
+		response := responses.Content[i+1]
+		if ref := getStringValue(response, "$ref"); ref != "" && doc != nil {
+			if resolved := resolveRef(ref, doc); resolved != nil {
+				response = resolved
+			}
+		}
+
+		for _, field := range extractFieldsFromResponseWithDoc(response, doc) {
+			if seen[field] || containsFold(keep, field) || fieldOwner(field) == "" || fieldOwner(field) == selected {
+				continue
+			}
+			seen[field] = true
+			stray = append(stray, field)
+		}
+	}
+	sort.Strings(stray)
+	return stray
+}
+
+// fieldOwner returns the only strategy a response field belongs to, or "" when it belongs to none
+// or to several
+func fieldOwner(field string) string {
+	owner := ""
+	for name, strategy := range PaginationStrategies {
+		for _, strategyField := range strategy.Fields {
+			if matchesField(field, strategyField) {
+				if owner != "" && owner != name {
+					return ""
+				}
+				owner = name
+			}
+		}
+	}
+	return owner
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package pagination
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const invariantsTestDoc = `
+parameters:
+- name: cursor
+  in: query
+- $ref: '#/components/parameters/Offset'
+- name: limit
+  in: query
+responses:
+  '200':
+    $ref: '#/components/responses/Users'
+  '400':
+    description: Bad request
+    content:
+      application/json:
+        schema:
+          type: object
+          properties:
+            page:
+              type: integer
+components:
+  parameters:
+    Offset:
+      name: offset
+      in: query
+  responses:
+    Users:
+      description: OK
+      content:
+        application/json:
+          schema:
+            type: object
+            properties:
+              next_cursor:
+                type: string
+              total_count:
+                type: integer
+              next_checkpoint:
+                type: string
+              total:
+                type: integer
+`
+
+func parseInvariantsTestDoc(t *testing.T) *yaml.Node {
+	t.Helper()
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(invariantsTestDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc.Content[0]
+}
+
+func TestParamStrategies(t *testing.T) {
+	root := parseInvariantsTestDoc(t)
+	got := ParamStrategies(getNodeValue(root, "parameters"), root)
+	if want := []string{"cursor", "offset"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParamStrategies() = %v, want %v", got, want)
+	}
+}
+
+func TestStrayFields(t *testing.T) {
+	root := parseInvariantsTestDoc(t)
+	responses := getNodeValue(root, "responses")
+
+	// total is shared by offset and page, and the error response is not checked
+	got := StrayFields(responses, "cursor", nil, root)
+	if want := []string{"next_checkpoint", "total_count"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("StrayFields() = %v, want %v", got, want)
+	}
+
+	got = StrayFields(responses, "cursor", []string{"Total_Count"}, root)
+	if want := []string{"next_checkpoint"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("StrayFields() with keep = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package pagination
+
+import (
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Response links often encode pagination: a nextPage link whose parameters feed a cursor from the
+// response body into the next request. Such links are a pagination signal like parameters and
+// response fields, and are removed along with the strategy they belong to.
+//
+//	links:
+//	  nextPage:
+//	    operationId: listUsers
+//	    parameters:
+//	      cursor: $response.body#/next_cursor
+
+// DetectPaginationInLinks detects pagination strategies in the links of operation responses
+func DetectPaginationInLinks(responses *yaml.Node, doc *yaml.Node) []DetectedPagination {
+	var detected []DetectedPagination
+	if responses == nil || responses.Kind != yaml.MappingNode {
+		return detected
+	}
+
+	strategyLinks := make(map[string][]string)
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		links := getNodeValue(responses.Content[i+1], "links")
+		if links == nil || links.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(links.Content); j += 2 {
+			for _, strategy := range linkStrategies(links.Content[j+1], doc) {
+				strategyLinks[strategy] = append(strategyLinks[strategy], links.Content[j].Value)
+			}
+		}
+	}
+
+	strategies := make([]string, 0, len(strategyLinks))
+	for strategy := range strategyLinks {
+		strategies = append(strategies, strategy)
+	}
+	sort.Strings(strategies)
+	for _, strategy := range strategies {
+		detected = append(detected, DetectedPagination{Strategy: strategy, Links: strategyLinks[strategy]})
+	}
+	return detected
+}
+
+// linkStrategies returns the pagination strategies a link belongs to. A link parameter named after
+// a strategy parameter decides it; links whose parameters are all shared between strategies fall
+// back to the response fields their runtime expressions read.
+func linkStrategies(link *yaml.Node, doc *yaml.Node) []string {
+	if ref := getStringValue(link, "$ref"); ref != "" {
+		link = resolveRef(ref, doc)
+	}
+	parameters := getNodeValue(link, "parameters")
+	if parameters == nil || parameters.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	shared := findSharedParams()
+	byParam := make(map[string]bool)
+	byField := make(map[string]bool)
+	for i := 0; i+1 < len(parameters.Content); i += 2 {
+		name := parameters.Content[i].Value
+		for strategyName, strategy := range PaginationStrategies {
+			for _, param := range strategy.Params {
+				if matchesParam(name, param) && !shared[param] {
+					byParam[strategyName] = true
+				}
+			}
+			if field := expressionField(parameters.Content[i+1].Value); field != "" {
+				for _, strategyField := range strategy.Fields {
+					if matchesField(field, strategyField) {
+						byField[strategyName] = true
+					}
+				}
+			}
+		}
+	}
+
+	matched := byParam
+	if len(matched) == 0 {
+		matched = byField
+	}
+	strategies := make([]string, 0, len(matched))
+	for strategy := range matched {
+		strategies = append(strategies, strategy)
+	}
+	sort.Strings(strategies)
+	return strategies
+}
+
+// expressionField returns the last property of the response body a runtime expression such as
+// $response.body#/meta/next_cursor reads, or "" for other expressions
+func expressionField(expression string) string {
+	const prefix = "$response.body#/"
+	if !strings.HasPrefix(expression, prefix) {
+		return ""
+	}
+	pointer := strings.TrimPrefix(expression, prefix)
+	return pointer[strings.LastIndex(pointer, "/")+1:]
+}
+
+// removeUnwantedLinks removes the response links that belong only to strategies other than the
+// selected one and returns them as "<status> <link>" entries. Links declared in shared response
+// components are left alone.
+func removeUnwantedLinks(responses *yaml.Node, selectedStrategy string, doc *yaml.Node) []string {
+	var removed []string
+	if responses == nil || responses.Kind != yaml.MappingNode {
+		return removed
+	}
+
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		code, response := responses.Content[i].Value, responses.Content[i+1]
+		links := getNodeValue(response, "links")
+		if links == nil || links.Kind != yaml.MappingNode {
+			continue
+		}
+
+		var kept []*yaml.Node
+		for j := 0; j+1 < len(links.Content); j += 2 {
+			strategies := linkStrategies(links.Content[j+1], doc)
+			if len(strategies) > 0 && !containsString(strategies, selectedStrategy) {
+				removed = append(removed, code+" "+links.Content[j].Value)
+				continue
+			}
+			kept = append(kept, links.Content[j], links.Content[j+1])
+		}
+		links.Content = kept
+		if len(kept) == 0 {
+			removeKey(response, "links")
+		}
+	}
+	return removed
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const linksTestDoc = `
+paths:
+  /users:
+    get:
+      parameters:
+      - name: cursor
+        in: query
+        schema:
+          type: string
+      - name: offset
+        in: query
+        schema:
+          type: integer
+      - name: limit
+        in: query
+        schema:
+          type: integer
+      responses:
+        '200':
+          description: OK
+          links:
+            nextPage:
+              operationId: listUsers
+              parameters:
+                cursor: $response.body#/next_cursor
+            nextOffset:
+              $ref: '#/components/links/NextOffset'
+            owner:
+              operationId: getUser
+              parameters:
+                id: $response.body#/owner_id
+components:
+  links:
+    NextOffset:
+      operationId: listUsers
+      parameters:
+        limit: $request.query.limit
+        offset: $response.body#/offset
+`
+
+func parseLinksTestDoc(t *testing.T) (*yaml.Node, *yaml.Node) {
+	t.Helper()
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(linksTestDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+	root := doc.Content[0]
+	operation := getNodeValue(getNodeValue(getNodeValue(root, "paths"), "/users"), "get")
+	return root, operation
+}
+
+func TestDetectPaginationInLinks(t *testing.T) {
+	root, operation := parseLinksTestDoc(t)
+
+	detected := DetectPaginationInLinks(getNodeValue(operation, "responses"), root)
+	want := []DetectedPagination{
+		{Strategy: "cursor", Links: []string{"nextPage"}},
+		{Strategy: "offset", Links: []string{"nextOffset"}},
+	}
+	if !reflect.DeepEqual(detected, want) {
+		t.Errorf("expected %+v, got %+v", want, detected)
+	}
+}
+
+func TestLinksOfRemovedStrategiesAreRemoved(t *testing.T) {
+	root, operation := parseLinksTestDoc(t)
+
+	result, err := ProcessEndpointWithDoc(operation, root, Options{Priority: []string{"cursor", "offset"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.RemovedLinks, []string{"200 nextOffset"}) {
+		t.Errorf("expected the offset link to be removed, got %v", result.RemovedLinks)
+	}
+
+	links := getNodeValue(getNodeValue(getNodeValue(operation, "responses"), "200"), "links")
+	var names []string
+	for i := 0; i < len(links.Content); i += 2 {
+		names = append(names, links.Content[i].Value)
+	}
+	if !reflect.DeepEqual(names, []string{"nextPage", "owner"}) {
+		t.Errorf("expected nextPage and owner to be kept, got %v", names)
+	}
+}
+
+func TestExpressionField(t *testing.T) {
+	tests := map[string]string{
+		"$response.body#/next_cursor":      "next_cursor",
+		"$response.body#/meta/next_cursor": "next_cursor",
+		"$request.query.limit":             "",
+	}
+	for expression, want := range tests {
+		if got := expressionField(expression); got != want {
+			t.Errorf("expressionField(%q) = %q, want %q", expression, got, want)
+		}
+	}
+}
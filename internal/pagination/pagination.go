@@ -3,9 +3,12 @@ package pagination
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
 )
 
 // Strategy defines a pagination strategy with its parameters and response fields
@@ -44,6 +47,24 @@ var PaginationStrategies = map[string]Strategy{
 type Options struct {
 	Priority      []string                 // Global ordered list of pagination strategies by priority
 	EndpointRules []EndpointPaginationRule // Endpoint-specific pagination rules that override global priority
+	// DescriptionPatterns select the sentences removed from the description of an operation
+	// whose pagination is removed by the "none" strategy. Descriptions are left alone when empty.
+	DescriptionPatterns []*regexp.Regexp
+	// SharedFields pins the fate of response fields per selected strategy, replacing the guess
+	// based on sibling fields for ambiguous schemas
+	SharedFields map[string]SharedFieldRule
+	// ResultsFields names the response property holding the results per path pattern, which
+	// cleanup never removes, see ResultsField
+	ResultsFields map[string]string
+}
+
+// SharedFieldRule lists the response fields that are always kept or always removed when a strategy
+// is selected
+type SharedFieldRule struct {
+	Keep   []string
+	Remove []string
+
+	results string // response property holding the results of the endpoint, from Options.ResultsFields
 }
 
 // EndpointPaginationRule defines pagination configuration for specific endpoints
@@ -59,14 +80,24 @@ type DetectedPagination struct {
 	Strategy   string
 	Parameters []string // parameter names found
 	Fields     []string // response field names found
+	Links      []string // response link names found
 }
 
 // ProcessResult contains the result of processing a single endpoint
 type ProcessResult struct {
-	Changed          bool
-	RemovedParams    []string
-	RemovedResponses []string
-	ModifiedSchemas  []string
+	Changed           bool
+	RemovedParams     []string
+	RemovedResponses  []string
+	ModifiedSchemas   []string
+	MergedParams      []string        // duplicate parameter definitions that were merged or removed
+	MovedParams       []string        // path-level parameters moved into the sibling operations that still use them
+	SelectedStrategy  string          // strategy the endpoint was cleaned up for, empty when it was left alone
+	Decision          string          // strategy selected for the endpoint, even when it needed no cleanup
+	DecidedByRule     bool            // whether an endpoint rule rather than the priority list made the decision
+	ScrubbedSentences []string        // sentences removed from the operation description
+	RemovedLinks      []string        // response links of removed strategies, as "<status> <link>"
+	UnresolvedRefs    []UnresolvedRef // $refs followed for the endpoint that do not resolve
+	DynamicRefs       []DynamicRef    // $dynamicRefs followed for the endpoint, resolved best-effort
 }
 
 // DetectPaginationInParams detects pagination strategies in operation parameters
@@ -105,11 +136,13 @@ func collectStrategyParams(params *yaml.Node, doc *yaml.Node) map[string][]strin
 			continue
 		}
 
-		// Check which strategies this parameter belongs to
-		for strategyName, strategy := range PaginationStrategies {
-			for _, strategyParam := range strategy.Params {
-				if matchesParam(paramName, strategyParam) {
-					strategyParams[strategyName] = append(strategyParams[strategyName], paramName)
+		// Check which strategies this parameter, or a field it serializes, belongs to
+		for _, name := range append([]string{paramName}, contentParamFields(param, doc)...) {
+			for strategyName, strategy := range PaginationStrategies {
+				for _, strategyParam := range strategy.Params {
+					if matchesParam(name, strategyParam) {
+						strategyParams[strategyName] = append(strategyParams[strategyName], name)
+					}
 				}
 			}
 		}
@@ -151,6 +184,7 @@ func filterWeakStrategies(strategyParams map[string][]string) []DetectedPaginati
 			})
 		}
 	}
+	sortDetected(detected)
 
 	return detected
 }
@@ -245,10 +279,16 @@ func DetectPaginationInResponsesWithDoc(responses *yaml.Node, doc *yaml.Node) []
 			Fields:   fields,
 		})
 	}
+	sortDetected(detected)
 
 	return detected
 }
 
+// sortDetected orders detected strategies by name, so map iteration doesn't leak into results
+func sortDetected(detected []DetectedPagination) {
+	sort.Slice(detected, func(i, j int) bool { return detected[i].Strategy < detected[j].Strategy })
+}
+
 // ProcessEndpoint processes a single endpoint based on pagination priority
 func ProcessEndpoint(operation *yaml.Node, opts Options) (*ProcessResult, error) {
 	return ProcessEndpointWithDoc(operation, nil, opts)
@@ -278,6 +318,14 @@ func ProcessEndpointWithDoc(operation *yaml.Node, doc *yaml.Node, opts Options)
 // - ProcessResult with details of what was changed/removed
 // - Error if processing failed
 func ProcessEndpointWithPathAndMethod(operation *yaml.Node, doc *yaml.Node, endpoint, method string, opts Options) (*ProcessResult, error) {
+	return ProcessEndpointInPathItem(nil, operation, doc, endpoint, method, opts)
+}
+
+// ProcessEndpointInPathItem processes an operation of the path item pathItem (which can be nil).
+// Pagination parameters declared more than once, inline and via $ref or at both the path and
+// operation level, are first merged into a single definition. Parameters the operation inherits
+// from the path item are detected and cleaned up along with its own.
+func ProcessEndpointInPathItem(pathItem, operation *yaml.Node, doc *yaml.Node, endpoint, method string, opts Options) (*ProcessResult, error) {
 	result := &ProcessResult{}
 
 	if operation == nil || operation.Kind != yaml.MappingNode {
@@ -286,18 +334,25 @@ func ProcessEndpointWithPathAndMethod(operation *yaml.Node, doc *yaml.Node, endp
 
 	params := getNodeValue(operation, "parameters")
 	responses := getNodeValue(operation, "responses")
+	pathParams := getNodeValue(pathItem, "parameters")
+	result.UnresolvedRefs, result.DynamicRefs = followRefs(pathItem, operation, doc)
+
+	result.MergedParams = append(mergeDuplicateParams(params, doc),
+		removeRedundantOperationParams(params, pathParams, doc)...)
+	if len(result.MergedParams) > 0 {
+		result.Changed = true
+	}
+
+	// Detection sees the parameters the operation inherits from its path item too
+	inherited := inheritedParams(params, pathParams, doc)
+	effectiveParams := withInheritedParams(params, inherited)
 
 	// Detect all pagination strategies present in this endpoint
-	strategies := detectPaginationStrategies(params, responses, doc)
+	strategies := detectPaginationStrategies(effectiveParams, responses, doc)
 	if len(strategies.paramStrategies) == 0 {
 		return result, nil // No pagination detected, nothing to do
 	}
 
-	// Check if this endpoint actually needs processing
-	if !needsProcessingCheck(strategies, params, responses, doc) {
-		return result, nil
-	}
-
 	// Get the pagination strategy for this specific endpoint
 	// This will use endpoint-specific rules if they match, otherwise global priority
 	paginationPriority := opts.GetPaginationStrategy(endpoint, method)
@@ -313,15 +368,114 @@ func ProcessEndpointWithPathAndMethod(operation *yaml.Node, doc *yaml.Node, endp
 	if selectedStrategy == "" {
 		return result, nil // No suitable strategy found
 	}
+	result.Decision = selectedStrategy
+	result.DecidedByRule = len(matchingRules(endpoint, method, opts.EndpointRules)) > 0
+
+	// Check if this endpoint actually needs processing; "none" always strips what is detected
+	if selectedStrategy != "none" && !needsProcessingCheck(strategies, effectiveParams, responses, doc) {
+		return result, nil
+	}
 
-	// Remove unwanted parameters and response fields
-	return processEndpointCleanup(params, responses, selectedStrategy, strategies.allPagination, doc, result)
+	// Remove unwanted inherited parameters from the path item, then the operation's own
+	// parameters and response fields
+	result.SelectedStrategy = selectedStrategy
+	removeInheritedParams(pathItem, operation, inherited, selectedStrategy, strategies.allPagination, doc, result)
+	rule := opts.SharedFields[selectedStrategy]
+	rule.results = ResultsField(opts.ResultsFields, endpoint)
+	if _, err := processEndpointCleanup(params, responses, selectedStrategy, rule, strategies.allPagination, doc, result); err != nil {
+		return result, err
+	}
+
+	// Removing every parameter leaves an empty list behind, which is noise in the output
+	if params != nil && params.Kind == yaml.SequenceNode && len(params.Content) == 0 && len(result.RemovedParams) > 0 {
+		removeKey(operation, "parameters")
+	}
+
+	if selectedStrategy == "none" && len(opts.DescriptionPatterns) > 0 {
+		result.ScrubbedSentences = scrubDescription(operation, opts.DescriptionPatterns)
+		if len(result.ScrubbedSentences) > 0 {
+			result.Changed = true
+		}
+	}
+	return result, nil
+}
+
+// scrubDescription removes the sentences matching one of patterns from the operation's
+// description, dropping the description when nothing is left, and returns the removed sentences
+func scrubDescription(operation *yaml.Node, patterns []*regexp.Regexp) []string {
+	description := getNodeValue(operation, "description")
+	if description == nil || description.Kind != yaml.ScalarNode {
+		return nil
+	}
+
+	var removed, lines []string
+	for _, line := range strings.Split(description.Value, "\n") {
+		var kept []string
+		sentences := splitSentences(line)
+		for _, sentence := range sentences {
+			if matchesAnyPattern(sentence, patterns) {
+				removed = append(removed, sentence)
+			} else {
+				kept = append(kept, sentence)
+			}
+		}
+		if len(kept) == len(sentences) {
+			lines = append(lines, line) // Untouched lines keep their original spacing
+		} else if len(kept) > 0 {
+			lines = append(lines, strings.Join(kept, " "))
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	value := strings.TrimSpace(strings.Join(lines, "\n"))
+	for strings.Contains(value, "\n\n\n") {
+		value = strings.ReplaceAll(value, "\n\n\n", "\n\n")
+	}
+	if value == "" {
+		removeKey(operation, "description")
+		return removed
+	}
+	if strings.HasSuffix(description.Value, "\n") {
+		value += "\n"
+	}
+	description.Value = value
+	return removed
+}
+
+// splitSentences splits text after each '.', '!' or '?' that is followed by whitespace
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text); i++ {
+		if (text[i] == '.' || text[i] == '!' || text[i] == '?') && (i+1 == len(text) || text[i+1] == ' ' || text[i+1] == '\t') {
+			if sentence := strings.TrimSpace(text[start : i+1]); sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+			start = i + 1
+		}
+	}
+	if sentence := strings.TrimSpace(text[start:]); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+	return sentences
+}
+
+// matchesAnyPattern reports whether text matches one of patterns
+func matchesAnyPattern(text string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
 }
 
 // detectPaginationStrategies extracts pagination strategies from params and responses
 func detectPaginationStrategies(params, responses *yaml.Node, doc *yaml.Node) *paginationStrategies {
 	paramPagination := DetectPaginationInParamsWithDoc(params, doc)
-	responsePagination := DetectPaginationInResponsesWithDoc(responses, doc)
+	responsePagination := append(DetectPaginationInResponsesWithDoc(responses, doc), DetectPaginationInLinks(responses, doc)...)
 
 	paramStrategies := make(map[string]bool)
 	for _, p := range paramPagination {
@@ -464,22 +618,27 @@ func selectBestStrategy(strategies *paginationStrategies, opts Options) string {
 }
 
 // processEndpointCleanup performs the actual cleanup of params and responses
-func processEndpointCleanup(params, responses *yaml.Node, selectedStrategy string, allPagination []DetectedPagination, doc *yaml.Node, result *ProcessResult) (*ProcessResult, error) {
+func processEndpointCleanup(params, responses *yaml.Node, selectedStrategy string, rule SharedFieldRule, allPagination []DetectedPagination, doc *yaml.Node, result *ProcessResult) (*ProcessResult, error) {
 	if params != nil {
 		removed := removeUnwantedParamsWithDoc(params, selectedStrategy, allPagination, doc)
-		result.RemovedParams = removed
+		result.RemovedParams = append(result.RemovedParams, removed...)
 		if len(removed) > 0 {
 			result.Changed = true
 		}
 	}
 
 	if responses != nil {
-		removed, modified := removeUnwantedResponsesWithDoc(responses, selectedStrategy, allPagination, doc)
+		removed, modified := removeUnwantedResponsesWithDoc(responses, selectedStrategy, rule, allPagination, doc)
 		result.RemovedResponses = removed
 		result.ModifiedSchemas = modified
 		if len(removed) > 0 || len(modified) > 0 {
 			result.Changed = true
 		}
+
+		result.RemovedLinks = removeUnwantedLinks(responses, selectedStrategy, doc)
+		if len(result.RemovedLinks) > 0 {
+			result.Changed = true
+		}
 	}
 
 	return result, nil
@@ -525,6 +684,12 @@ func removeUnwantedParamsWithDoc(params *yaml.Node, selectedStrategy string, det
 
 		shouldKeep := shouldKeepParameter(paramName, selectedStrategy, detected)
 		if shouldKeep {
+			fields, empty := pruneContentParam(param, paramName, selectedStrategy, detected)
+			if empty {
+				removed = append(removed, paramName)
+				continue
+			}
+			removed = append(removed, fields...)
 			newContent = append(newContent, param)
 		} else {
 			removed = append(removed, paramName)
@@ -604,7 +769,7 @@ func belongsToAnyPaginationStrategy(paramName, selectedStrategy string, detected
 // removeUnwantedResponses removes or modifies responses that contain unwanted pagination
 
 // removeUnwantedResponsesWithDoc removes or modifies responses with document context for $ref resolution
-func removeUnwantedResponsesWithDoc(responses *yaml.Node, selectedStrategy string, detected []DetectedPagination, doc *yaml.Node) ([]string, []string) {
+func removeUnwantedResponsesWithDoc(responses *yaml.Node, selectedStrategy string, rule SharedFieldRule, detected []DetectedPagination, doc *yaml.Node) ([]string, []string) {
 	var removedResponses []string
 	var modifiedSchemas []string
 
@@ -618,7 +783,7 @@ func removeUnwantedResponsesWithDoc(responses *yaml.Node, selectedStrategy strin
 		responseCode := responses.Content[i]
 		responseNode := responses.Content[i+1]
 
-		processResult := processResponseForCleanup(responseNode, selectedStrategy, detected, doc)
+		processResult := processResponseForCleanup(responseNode, selectedStrategy, rule, detected, doc)
 
 		newContent = append(newContent, responseCode, responseNode)
 		if len(processResult.modifications) > 0 {
@@ -636,7 +801,7 @@ type responseCleanupResult struct {
 }
 
 // processResponseForCleanup processes a single response for pagination cleanup
-func processResponseForCleanup(responseNode *yaml.Node, selectedStrategy string, detected []DetectedPagination, doc *yaml.Node) responseCleanupResult {
+func processResponseForCleanup(responseNode *yaml.Node, selectedStrategy string, rule SharedFieldRule, detected []DetectedPagination, doc *yaml.Node) responseCleanupResult {
 	var fields []string
 	if doc != nil {
 		fields = extractFieldsFromResponseWithDoc(responseNode, doc)
@@ -645,33 +810,33 @@ func processResponseForCleanup(responseNode *yaml.Node, selectedStrategy string,
 	}
 
 	if selectedStrategy == "none" {
-		return processResponseForNoneCleanup(responseNode, fields, detected, doc)
+		return processResponseForNoneCleanup(responseNode, fields, rule, detected, doc)
 	}
 
-	return processResponseForStrategyCleanup(responseNode, fields, selectedStrategy, detected, doc)
+	return processResponseForStrategyCleanup(responseNode, fields, selectedStrategy, rule, detected, doc)
 }
 
 // processResponseForNoneCleanup handles cleanup for "none" strategy
-func processResponseForNoneCleanup(responseNode *yaml.Node, fields []string, detected []DetectedPagination, doc *yaml.Node) responseCleanupResult {
+func processResponseForNoneCleanup(responseNode *yaml.Node, fields []string, rule SharedFieldRule, detected []DetectedPagination, doc *yaml.Node) responseCleanupResult {
 	containsPaginationFields := checkForPaginationFields(fields, detected)
 
 	var modifications []string
 	if containsPaginationFields {
-		modifications = cleanResponseSchemaWithDoc(responseNode, "none", detected, doc)
+		modifications = cleanResponseSchemaWithDoc(responseNode, "none", rule, detected, doc)
 	}
 
 	return responseCleanupResult{modifications: modifications}
 }
 
 // processResponseForStrategyCleanup handles cleanup for specific strategies
-func processResponseForStrategyCleanup(responseNode *yaml.Node, fields []string, selectedStrategy string, detected []DetectedPagination, doc *yaml.Node) responseCleanupResult {
+func processResponseForStrategyCleanup(responseNode *yaml.Node, fields []string, selectedStrategy string, rule SharedFieldRule, detected []DetectedPagination, doc *yaml.Node) responseCleanupResult {
 	containsUnwanted := checkForUnwantedFields(fields, selectedStrategy, detected)
 
 	var modifications []string
 	if containsUnwanted {
-		modifications = cleanResponseSchemaWithDoc(responseNode, selectedStrategy, detected, doc)
+		modifications = cleanResponseSchemaWithDoc(responseNode, selectedStrategy, rule, detected, doc)
 	} else if hasMixedCompositionInResponse(responseNode, doc) {
-		modifications = cleanResponseSchemaWithDoc(responseNode, selectedStrategy, detected, doc)
+		modifications = cleanResponseSchemaWithDoc(responseNode, selectedStrategy, rule, detected, doc)
 	}
 
 	return responseCleanupResult{modifications: modifications}
@@ -743,7 +908,7 @@ func fieldBelongsToNonSelectedPaginationStrategy(field, selectedStrategy string)
 // cleanResponseSchema removes unwanted pagination fields from response schemas
 
 // cleanResponseSchemaWithDoc removes unwanted pagination fields with document context
-func cleanResponseSchemaWithDoc(response *yaml.Node, selectedStrategy string, detected []DetectedPagination, doc *yaml.Node) []string {
+func cleanResponseSchemaWithDoc(response *yaml.Node, selectedStrategy string, rule SharedFieldRule, detected []DetectedPagination, doc *yaml.Node) []string {
 	var modified []string
 
 	// Navigate to schema content
@@ -760,7 +925,7 @@ func cleanResponseSchemaWithDoc(response *yaml.Node, selectedStrategy string, de
 
 			schema := getNodeValue(mediaTypeNode, "schema")
 			if schema != nil {
-				schemaModified := cleanSchemaNodeWithDoc(schema, selectedStrategy, detected, doc)
+				schemaModified := cleanSchemaNodeWithDoc(schema, selectedStrategy, rule, detected, doc)
 				if len(schemaModified) > 0 {
 					modified = append(modified, fmt.Sprintf("%s schema", mediaType))
 				}
@@ -774,7 +939,7 @@ func cleanResponseSchemaWithDoc(response *yaml.Node, selectedStrategy string, de
 // cleanSchemaNode recursively cleans a schema node
 
 // cleanSchemaNodeWithDoc recursively cleans a schema node with document context
-func cleanSchemaNodeWithDoc(schema *yaml.Node, selectedStrategy string, detected []DetectedPagination, doc *yaml.Node) []string {
+func cleanSchemaNodeWithDoc(schema *yaml.Node, selectedStrategy string, rule SharedFieldRule, detected []DetectedPagination, doc *yaml.Node) []string {
 	var modified []string
 
 	if schema.Kind != yaml.MappingNode {
@@ -787,33 +952,33 @@ func cleanSchemaNodeWithDoc(schema *yaml.Node, selectedStrategy string, detected
 		resolvedSchema := resolveRef(refPath, doc)
 		if resolvedSchema != nil {
 			// Process the resolved schema
-			return cleanSchemaNodeWithDoc(resolvedSchema, selectedStrategy, detected, doc)
+			return cleanSchemaNodeWithDoc(resolvedSchema, selectedStrategy, rule, detected, doc)
 		}
 		// If we can't resolve the ref, fall through to process the current schema
 	}
 
 	// Handle oneOf, anyOf, allOf
 	if oneOf := getNodeValue(schema, "oneOf"); oneOf != nil {
-		if cleanCompositionNodeWithDoc(oneOf, selectedStrategy, detected, doc) {
+		if cleanCompositionNodeWithDoc(oneOf, selectedStrategy, rule, detected, doc) {
 			modified = append(modified, "oneOf")
 		}
 	}
 
 	if anyOf := getNodeValue(schema, "anyOf"); anyOf != nil {
-		if cleanCompositionNodeWithDoc(anyOf, selectedStrategy, detected, doc) {
+		if cleanCompositionNodeWithDoc(anyOf, selectedStrategy, rule, detected, doc) {
 			modified = append(modified, "anyOf")
 		}
 	}
 
 	if allOf := getNodeValue(schema, "allOf"); allOf != nil {
-		if cleanCompositionNodeWithDoc(allOf, selectedStrategy, detected, doc) {
+		if cleanCompositionNodeWithDoc(allOf, selectedStrategy, rule, detected, doc) {
 			modified = append(modified, "allOf")
 		}
 	}
 
 	// Handle properties
 	if properties := getNodeValue(schema, "properties"); properties != nil {
-		if cleanPropertiesNode(properties, selectedStrategy, detected) {
+		if cleanPropertiesNode(properties, selectedStrategy, rule, detected) {
 			modified = append(modified, "properties")
 		}
 	}
@@ -824,7 +989,7 @@ func cleanSchemaNodeWithDoc(schema *yaml.Node, selectedStrategy string, detected
 // cleanCompositionNode cleans oneOf/anyOf/allOf nodes
 
 // cleanCompositionNodeWithDoc cleans oneOf/anyOf/allOf nodes with document context
-func cleanCompositionNodeWithDoc(composition *yaml.Node, selectedStrategy string, detected []DetectedPagination, doc *yaml.Node) bool {
+func cleanCompositionNodeWithDoc(composition *yaml.Node, selectedStrategy string, rule SharedFieldRule, detected []DetectedPagination, doc *yaml.Node) bool {
 	if composition.Kind != yaml.SequenceNode {
 		return false
 	}
@@ -833,7 +998,7 @@ func cleanCompositionNodeWithDoc(composition *yaml.Node, selectedStrategy string
 	modified := false
 
 	for _, item := range composition.Content {
-		if shouldKeepSchemaItemWithDoc(item, selectedStrategy, detected, doc) {
+		if shouldKeepSchemaItemWithDoc(item, selectedStrategy, rule, detected, doc) {
 			newContent = append(newContent, item)
 		} else {
 			modified = true
@@ -849,7 +1014,7 @@ func cleanCompositionNodeWithDoc(composition *yaml.Node, selectedStrategy string
 }
 
 // cleanPropertiesNode removes unwanted pagination properties
-func cleanPropertiesNode(properties *yaml.Node, selectedStrategy string, detected []DetectedPagination) bool {
+func cleanPropertiesNode(properties *yaml.Node, selectedStrategy string, rule SharedFieldRule, detected []DetectedPagination) bool {
 	if properties.Kind != yaml.MappingNode {
 		return false
 	}
@@ -861,7 +1026,7 @@ func cleanPropertiesNode(properties *yaml.Node, selectedStrategy string, detecte
 		propName := properties.Content[i].Value
 		propNode := properties.Content[i+1]
 
-		shouldRemove := shouldRemoveProperty(propName, selectedStrategy, detected, properties)
+		shouldRemove := shouldRemoveProperty(propName, selectedStrategy, rule, detected, properties)
 
 		if !shouldRemove {
 			newContent = append(newContent, properties.Content[i], propNode)
@@ -878,7 +1043,15 @@ func cleanPropertiesNode(properties *yaml.Node, selectedStrategy string, detecte
 }
 
 // shouldRemoveProperty determines if a property should be removed
-func shouldRemoveProperty(propName, selectedStrategy string, detected []DetectedPagination, properties *yaml.Node) bool {
+func shouldRemoveProperty(propName, selectedStrategy string, rule SharedFieldRule, detected []DetectedPagination, properties *yaml.Node) bool {
+	// Configured rules win over every guess
+	if propName == rule.results || containsString(rule.Keep, propName) {
+		return false
+	}
+	if containsString(rule.Remove, propName) {
+		return true
+	}
+
 	if selectedStrategy == "none" {
 		return shouldRemoveForNoneStrategy(propName, detected)
 	}
@@ -1035,7 +1208,7 @@ func belongsToAnyNonSelectedStrategy(propName, selectedStrategy string) bool {
 // shouldKeepSchemaItem determines if a schema item should be kept
 
 // shouldKeepSchemaItemWithDoc determines if a schema item should be kept with document context
-func shouldKeepSchemaItemWithDoc(item *yaml.Node, selectedStrategy string, _ []DetectedPagination, doc *yaml.Node) bool {
+func shouldKeepSchemaItemWithDoc(item *yaml.Node, selectedStrategy string, rule SharedFieldRule, _ []DetectedPagination, doc *yaml.Node) bool {
 	if item.Kind != yaml.MappingNode {
 		return true // Keep non-object items
 	}
@@ -1051,6 +1224,10 @@ func shouldKeepSchemaItemWithDoc(item *yaml.Node, selectedStrategy string, _ []D
 		return shouldKeepForNoneStrategy(fields)
 	}
 
+	// The member declaring the results is not pagination to clean up, whatever its siblings
+	if rule.results != "" && containsString(fields, rule.results) {
+		return true
+	}
 	return shouldKeepForOtherStrategy(fields, selectedStrategy)
 }
 
@@ -1394,18 +1571,32 @@ func extractFieldsFromSchema(schema *yaml.Node) []string {
 
 // extractFieldsFromSchemaWithDoc extracts fields from schema with document context for $ref resolution
 func extractFieldsFromSchemaWithDoc(schema *yaml.Node, doc *yaml.Node) []string {
+	return extractFieldsInScope(schema, doc, nil, make(map[*yaml.Node]bool))
+}
+
+// extractFieldsInScope extracts the fields of a schema, resolving $refs against the document and
+// the $defs of the enclosing schemas in scope, and $dynamicRefs on a best-effort basis. Schemas
+// already being extracted are skipped, so recursive definitions terminate.
+func extractFieldsInScope(schema *yaml.Node, doc *yaml.Node, scope schemaScope, active map[*yaml.Node]bool) []string {
 	var fields []string
 
-	if schema == nil || schema.Kind != yaml.MappingNode {
+	if schema == nil || schema.Kind != yaml.MappingNode || active[schema] {
 		return fields
 	}
+	active[schema] = true
+	defer delete(active, schema)
+	scope = scope.enter(schema)
 
-	// Handle $ref by resolving it
+	// Handle $ref and $dynamicRef by resolving them
 	if ref := getNodeValue(schema, "$ref"); ref != nil {
-		refPath := ref.Value
-		resolvedSchema := resolveRef(refPath, doc)
-		if resolvedSchema != nil {
-			return extractFieldsFromSchemaWithDoc(resolvedSchema, doc)
+		if resolvedSchema := resolveInScope(ref.Value, doc, scope); resolvedSchema != nil {
+			return extractFieldsInScope(resolvedSchema, doc, scope, active)
+		}
+		return fields
+	}
+	if ref := getNodeValue(schema, "$dynamicRef"); ref != nil {
+		if resolvedSchema := resolveDynamicRef(ref.Value, doc, scope); resolvedSchema != nil {
+			return extractFieldsInScope(resolvedSchema, doc, scope, active)
 		}
 		return fields
 	}
@@ -1416,14 +1607,12 @@ func extractFieldsFromSchemaWithDoc(schema *yaml.Node, doc *yaml.Node) []string
 	}
 
 	// Handle oneOf, anyOf, allOf
-	if oneOf := getNodeValue(schema, "oneOf"); oneOf != nil {
-		fields = append(fields, extractFieldsFromCompositionWithDoc(oneOf, doc)...)
-	}
-	if anyOf := getNodeValue(schema, "anyOf"); anyOf != nil {
-		fields = append(fields, extractFieldsFromCompositionWithDoc(anyOf, doc)...)
-	}
-	if allOf := getNodeValue(schema, "allOf"); allOf != nil {
-		fields = append(fields, extractFieldsFromCompositionWithDoc(allOf, doc)...)
+	for _, keyword := range []string{"oneOf", "anyOf", "allOf"} {
+		if composition := getNodeValue(schema, keyword); composition != nil && composition.Kind == yaml.SequenceNode {
+			for _, item := range composition.Content {
+				fields = append(fields, extractFieldsInScope(item, doc, scope, active)...)
+			}
+		}
 	}
 
 	return fields
@@ -1441,7 +1630,7 @@ func resolveRef(refPath string, doc *yaml.Node) *yaml.Node {
 
 	current := doc
 	for _, part := range parts {
-		current = getNodeValue(current, part)
+		current = getNodeValue(current, unescapePointerToken(part))
 		if current == nil {
 			return nil
 		}
@@ -1479,21 +1668,6 @@ func extractFieldsFromComposition(composition *yaml.Node) []string {
 	return fields
 }
 
-// extractFieldsFromCompositionWithDoc extracts fields from composition with document context for $ref resolution
-func extractFieldsFromCompositionWithDoc(composition *yaml.Node, doc *yaml.Node) []string {
-	var fields []string
-
-	if composition.Kind != yaml.SequenceNode {
-		return fields
-	}
-
-	for _, item := range composition.Content {
-		fields = append(fields, extractFieldsFromSchemaWithDoc(item, doc)...)
-	}
-
-	return fields
-}
-
 func matchesParam(paramName, strategyParam string) bool {
 	// Simple exact match for now, could be enhanced with fuzzy matching
 	return strings.EqualFold(paramName, strategyParam)
@@ -1549,25 +1723,14 @@ func (opts Options) GetPaginationStrategy(endpoint, method string) []string {
 	return opts.Priority
 }
 
-// matchesMethodPattern checks if a method matches a pattern (supports wildcards)
-// Supports exact matching (case-insensitive) and wildcard "*" for any method
+// matchesMethodPattern checks if a method matches a pattern: "*" matches any method, anything
+// else is compared case-insensitively
 func matchesMethodPattern(method, pattern string) bool {
-	// Wildcard matches any method
-	if pattern == "*" {
-		return true
-	}
-
-	// Case-insensitive exact match
-	return strings.EqualFold(method, pattern)
+	return condition.MatchMethod(method, []string{pattern})
 }
 
-// matchesEndpointPattern checks if an endpoint matches a pattern (supports wildcards)
-//
-// Supported patterns:
-// - Exact match: endpoint == pattern
-// - Suffix wildcard: pattern ends with "*" (e.g., "/api/users/*")
-// - Middle wildcard: pattern contains "*" in the middle (e.g., "/api/*/analytics")
-// - Base path matching: "/api/users/*" matches both "/api/users" and "/api/users/123"
+// matchesEndpointPattern checks if an endpoint matches a pattern, a glob unless prefixed with
+// regex: (see the condition package)
 //
 // Examples:
 // - matchesEndpointPattern("/api/users", "/api/users") → true
@@ -1576,146 +1739,5 @@ func matchesMethodPattern(method, pattern string) bool {
 // - matchesEndpointPattern("/api/v2/analytics", "/api/*/analytics") → true
 // - matchesEndpointPattern("/api/posts", "/api/users/*") → false
 func matchesEndpointPattern(endpoint, pattern string) bool {
-	// Handle exact match
-	if endpoint == pattern {
-		return true
-	}
-
-	// Handle patterns with wildcards
-	if strings.Contains(pattern, "*") {
-		return matchesWildcardPattern(endpoint, pattern)
-	}
-
-	return false
-}
-
-// matchesWildcardPattern handles pattern matching with wildcards
-func matchesWildcardPattern(endpoint, pattern string) bool {
-	// Handle simple cases first
-	if pattern == "*" {
-		return true // Single "*" matches everything
-	}
-
-	// Special case for suffix wildcard patterns like "/api/users/*"
-	// This should only apply when there's no other wildcards in the pattern
-	if strings.HasSuffix(pattern, "*") && strings.Count(pattern, "*") == 1 {
-		// This is a simple suffix wildcard, not a complex pattern
-		prefix := strings.TrimSuffix(pattern, "*")
-
-		// If the prefix ends with "/", also match without the trailing "/"
-		if strings.HasSuffix(prefix, "/") {
-			basePrefix := strings.TrimSuffix(prefix, "/")
-			return endpoint == basePrefix || strings.HasPrefix(endpoint, prefix)
-		}
-
-		return strings.HasPrefix(endpoint, prefix)
-	}
-
-	// For all other cases (including "/*", "/*/...", "...*...", etc.), use complex matching
-	return matchesComplexPattern(endpoint, pattern)
-}
-
-// matchesComplexPattern handles patterns with wildcards in various positions
-func matchesComplexPattern(endpoint, pattern string) bool {
-	// Split both into segments for comparison
-	endpointParts := strings.Split(strings.Trim(endpoint, "/"), "/")
-	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
-
-	// Handle empty endpoint
-	if endpoint == "/" || endpoint == "" {
-		endpointParts = []string{}
-	}
-
-	// Handle empty pattern
-	if pattern == "/" || pattern == "" {
-		patternParts = []string{}
-	}
-
-	return matchSegments(endpointParts, patternParts)
-}
-
-// matchSegments performs segment-by-segment matching with wildcard support
-func matchSegments(endpointParts, patternParts []string) bool {
-	// Check if this is a true suffix wildcard pattern (e.g., "/api/users/*")
-	// vs. a structured pattern with wildcards (e.g., "/*/*/*")
-	isSuffixWildcard := len(patternParts) > 0 &&
-		patternParts[len(patternParts)-1] == "*" &&
-		len(patternParts) > 1 && // More than just "*"
-		// Check if all preceding parts are literal (no wildcards)
-		func() bool {
-			for i := 0; i < len(patternParts)-1; i++ {
-				if patternParts[i] == "*" || strings.Contains(patternParts[i], "*") {
-					return false
-				}
-			}
-			return true
-		}()
-
-	if isSuffixWildcard {
-		// Remove the trailing "*" and check if the prefix matches
-		prefixPatternParts := patternParts[:len(patternParts)-1]
-
-		// Endpoint must have at least as many segments as the prefix pattern
-		if len(endpointParts) < len(prefixPatternParts) {
-			return false
-		}
-
-		// Check if the prefix matches
-		for i, patternPart := range prefixPatternParts {
-			if !matchesSegment(endpointParts[i], patternPart) {
-				return false
-			}
-		}
-		return true
-	}
-
-	// For patterns with structured wildcards (like /*/*/*), segment count must match exactly
-	if len(endpointParts) != len(patternParts) {
-		return false
-	}
-
-	// Check each segment
-	for i, patternPart := range patternParts {
-		if !matchesSegment(endpointParts[i], patternPart) {
-			return false
-		}
-	}
-
-	return true
-}
-
-// matchesSegment checks if an endpoint segment matches a pattern segment
-// Supports "*" (full wildcard) and patterns like "v*" (partial wildcard)
-func matchesSegment(endpointSegment, patternSegment string) bool {
-	// Full wildcard
-	if patternSegment == "*" {
-		return true
-	}
-
-	// Exact match
-	if endpointSegment == patternSegment {
-		return true
-	}
-
-	// Partial wildcard support (e.g., "v*" matches "v1", "v2", etc.)
-	if strings.Contains(patternSegment, "*") {
-		// Simple prefix/suffix wildcard matching
-		if strings.HasSuffix(patternSegment, "*") {
-			prefix := strings.TrimSuffix(patternSegment, "*")
-			return strings.HasPrefix(endpointSegment, prefix)
-		}
-		if strings.HasPrefix(patternSegment, "*") {
-			suffix := strings.TrimPrefix(patternSegment, "*")
-			return strings.HasSuffix(endpointSegment, suffix)
-		}
-
-		// For more complex patterns within segments, could add regex support here
-		// For now, just do simple contains check
-		parts := strings.Split(patternSegment, "*")
-		if len(parts) == 2 {
-			return strings.HasPrefix(endpointSegment, parts[0]) && strings.HasSuffix(endpointSegment, parts[1])
-		}
-	}
-
-	return false
+	return condition.MatchPattern(endpoint, pattern, condition.Glob)
 }
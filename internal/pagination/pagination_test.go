@@ -1,6 +1,7 @@
 package pagination
 
 import (
+	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -1693,3 +1694,61 @@ func TestParamsYAMLStructureFixed(t *testing.T) {
 		}
 	}
 }
+
+func TestSharedFieldRules(t *testing.T) {
+	operationYAML := `
+parameters:
+- name: page
+  in: query
+  schema:
+    type: integer
+- name: per_page
+  in: query
+  schema:
+    type: integer
+- name: offset
+  in: query
+  schema:
+    type: integer
+responses:
+  '200':
+    description: OK
+    content:
+      application/json:
+        schema:
+          type: object
+          properties:
+            total:
+              type: integer
+            limit:
+              type: integer
+            items:
+              type: array
+`
+	properties := func(rule SharedFieldRule) []string {
+		var opNode yaml.Node
+		if err := yaml.Unmarshal([]byte(operationYAML), &opNode); err != nil {
+			t.Fatal(err)
+		}
+		operation := opNode.Content[0]
+		opts := Options{Priority: []string{"page", "offset"}, SharedFields: map[string]SharedFieldRule{"page": rule}}
+		if _, err := ProcessEndpoint(operation, opts); err != nil {
+			t.Fatal(err)
+		}
+
+		schema := getNodeValue(getNodeValue(getNodeValue(getNodeValue(getNodeValue(operation, "responses"), "200"), "content"), "application/json"), "schema")
+		var names []string
+		props := getNodeValue(schema, "properties")
+		for i := 0; i < len(props.Content); i += 2 {
+			names = append(names, props.Content[i].Value)
+		}
+		return names
+	}
+
+	if got := strings.Join(properties(SharedFieldRule{}), ","); got != "total,limit,items" {
+		t.Errorf("expected shared fields to be kept without a rule, got %s", got)
+	}
+	if got := strings.Join(properties(SharedFieldRule{Remove: []string{"limit"}}), ","); got != "total,items" {
+		t.Errorf("expected limit to be removed by the rule, got %s", got)
+	}
+}
@@ -0,0 +1,172 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// httpMethods are the path item keys that hold operations
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// inheritedParams returns the path-level parameters that apply to an operation: those it does
+// not override with a parameter of the same name and location
+func inheritedParams(params, pathParams *yaml.Node, doc *yaml.Node) []*yaml.Node {
+	if pathParams == nil || pathParams.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	overridden := make(map[paramIdentity]bool)
+	if params != nil && params.Kind == yaml.SequenceNode {
+		for _, param := range params.Content {
+			if id, resolved := identifyParameter(param, doc); resolved != nil {
+				overridden[id] = true
+			}
+		}
+	}
+
+	var inherited []*yaml.Node
+	for _, param := range pathParams.Content {
+		id, resolved := identifyParameter(param, doc)
+		if param.Kind == yaml.MappingNode && (resolved == nil || !overridden[id]) {
+			inherited = append(inherited, param)
+		}
+	}
+	return inherited
+}
+
+// withInheritedParams returns a parameters list holding the operation's own parameters followed
+// by the inherited ones. The operation's list is returned as is when nothing is inherited.
+func withInheritedParams(params *yaml.Node, inherited []*yaml.Node) *yaml.Node {
+	if len(inherited) == 0 {
+		return params
+	}
+	effective := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	if params != nil && params.Kind == yaml.SequenceNode {
+		effective.Content = append(effective.Content, params.Content...)
+	}
+	effective.Content = append(effective.Content, inherited...)
+	return effective
+}
+
+// removeInheritedParams removes the inherited parameters that do not belong to the selected
+// strategy from the path item. The other operations of the path item still inherit them, so each
+// one that does not override the parameter gets its own copy first; they are cleaned up, or
+// kept, when those operations are processed.
+func removeInheritedParams(pathItem, operation *yaml.Node, inherited []*yaml.Node, selectedStrategy string, detected []DetectedPagination, doc *yaml.Node, result *ProcessResult) {
+	pathParams := getNodeValue(pathItem, "parameters")
+	if len(inherited) == 0 || pathParams == nil {
+		return
+	}
+
+	unwanted := make(map[*yaml.Node]bool)
+	for _, param := range inherited {
+		id, resolved := identifyParameter(param, doc)
+		if resolved == nil || id.name == "" || shouldKeepParameter(id.name, selectedStrategy, detected) {
+			continue
+		}
+		unwanted[param] = true
+
+		var movedTo []string
+		for _, sibling := range siblingOperations(pathItem, operation) {
+			if declaresParameter(sibling.node, id, doc) {
+				continue
+			}
+			addParameter(sibling.node, copyNode(param))
+			movedTo = append(movedTo, strings.ToUpper(sibling.method))
+		}
+		result.RemovedParams = append(result.RemovedParams, id.name)
+		if len(movedTo) > 0 {
+			result.MovedParams = append(result.MovedParams,
+				fmt.Sprintf("%s: moved from the path item to %s", describeParam(id), strings.Join(movedTo, ", ")))
+		}
+	}
+	if len(unwanted) == 0 {
+		return
+	}
+
+	var newContent []*yaml.Node
+	for _, param := range pathParams.Content {
+		if !unwanted[param] {
+			newContent = append(newContent, param)
+		}
+	}
+	pathParams.Content = newContent
+	if len(newContent) == 0 {
+		removeKey(pathItem, "parameters")
+	}
+	result.Changed = true
+}
+
+type pathOperation struct {
+	method string
+	node   *yaml.Node
+}
+
+// siblingOperations returns the operations of the path item other than operation
+func siblingOperations(pathItem, operation *yaml.Node) []pathOperation {
+	var siblings []pathOperation
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		method := strings.ToLower(pathItem.Content[i].Value)
+		node := pathItem.Content[i+1]
+		if node != operation && node.Kind == yaml.MappingNode && isHTTPMethod(method) {
+			siblings = append(siblings, pathOperation{method: method, node: node})
+		}
+	}
+	return siblings
+}
+
+// declaresParameter reports whether the operation declares a parameter with the given identity
+func declaresParameter(operation *yaml.Node, id paramIdentity, doc *yaml.Node) bool {
+	params := getNodeValue(operation, "parameters")
+	if params == nil {
+		return false
+	}
+	for _, param := range params.Content {
+		if other, resolved := identifyParameter(param, doc); resolved != nil && other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// addParameter appends a parameter to the operation, creating its parameters list if needed
+func addParameter(operation, param *yaml.Node) {
+	params := getNodeValue(operation, "parameters")
+	if params == nil || params.Kind != yaml.SequenceNode {
+		removeKey(operation, "parameters")
+		params = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		operation.Content = append(operation.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "parameters"}, params)
+	}
+	params.Content = append(params.Content, param)
+}
+
+func removeKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func isHTTPMethod(method string) bool {
+	for _, m := range httpMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// copyNode returns a deep copy of node
+func copyNode(node *yaml.Node) *yaml.Node {
+	copied := *node
+	copied.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied.Content[i] = copyNode(child)
+	}
+	return &copied
+}
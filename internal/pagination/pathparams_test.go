@@ -0,0 +1,96 @@
+package pagination
+
+import (
+	"testing"
+)
+
+const pathLevelSpec = `
+paths:
+  /users:
+    parameters:
+      - name: cursor
+        in: query
+        schema:
+          type: string
+      - name: offset
+        in: query
+        schema:
+          type: integer
+      - name: limit
+        in: query
+        schema:
+          type: integer
+    get:
+      parameters:
+        - name: size
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+    post:
+      responses:
+        "201":
+          description: Created
+  /teams:
+    parameters:
+      - name: page
+        in: query
+      - name: per_page
+        in: query
+      - name: offset
+        in: query
+    get:
+      responses:
+        "200":
+          description: OK
+`
+
+func TestProcessEndpointDetectsPathLevelParams(t *testing.T) {
+	doc := parseDoc(t, pathLevelSpec)
+	pathItem := getNodeValue(getNodeValue(doc, "paths"), "/teams")
+	operation := getNodeValue(pathItem, "get")
+
+	result, err := ProcessEndpointInPathItem(pathItem, operation, doc, "/teams", "get", Options{Priority: []string{"page", "offset"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed || len(result.RemovedParams) != 1 || result.RemovedParams[0] != "offset" {
+		t.Fatalf("expected the path-level offset param to be removed, got %+v", result)
+	}
+	names := paramNames(t, getNodeValue(pathItem, "parameters"), doc)
+	if len(names) != 2 || names[0] != "page" || names[1] != "per_page" {
+		t.Errorf("expected page and per_page to stay on the path item, got %v", names)
+	}
+	if len(result.MovedParams) != 0 || getNodeValue(operation, "parameters") != nil {
+		t.Errorf("expected nothing to move without sibling operations, got %v", result.MovedParams)
+	}
+}
+
+func TestProcessEndpointMovesPathLevelParamsSiblingsInherit(t *testing.T) {
+	doc := parseDoc(t, pathLevelSpec)
+	pathItem := getNodeValue(getNodeValue(doc, "paths"), "/users")
+	get := getNodeValue(pathItem, "get")
+	post := getNodeValue(pathItem, "post")
+
+	result, err := ProcessEndpointInPathItem(pathItem, get, doc, "/users", "get", Options{Priority: []string{"cursor"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.RemovedParams) != 2 {
+		t.Fatalf("expected offset and limit to be removed for GET, got %v", result.RemovedParams)
+	}
+	if names := paramNames(t, getNodeValue(pathItem, "parameters"), doc); len(names) != 1 || names[0] != "cursor" {
+		t.Errorf("expected only cursor to stay on the path item, got %v", names)
+	}
+	if names := paramNames(t, getNodeValue(get, "parameters"), doc); len(names) != 1 || names[0] != "size" {
+		t.Errorf("expected GET to keep only its own size param, got %v", names)
+	}
+	if names := paramNames(t, getNodeValue(post, "parameters"), doc); len(names) != 2 || names[0] != "offset" || names[1] != "limit" {
+		t.Errorf("expected POST to keep the params it inherited, got %v", names)
+	}
+	if len(result.MovedParams) != 2 || result.MovedParams[0] != "offset (query): moved from the path item to POST" {
+		t.Errorf("unexpected moved params %v", result.MovedParams)
+	}
+}
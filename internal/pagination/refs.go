@@ -0,0 +1,114 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnresolvedRef is a $ref detection and cleanup follow but cannot resolve. Whatever it points at
+// is invisible to them, so the pagination of the operation may be mis-detected.
+type UnresolvedRef struct {
+	Ref     string
+	Dynamic bool // a $dynamicRef rather than a $ref
+	Line    int  // position of the $ref value
+	Column  int
+}
+
+// Reason explains why the reference cannot be resolved
+func (r UnresolvedRef) Reason() string {
+	keyword := "$ref"
+	if r.Dynamic {
+		keyword = "$dynamicRef"
+	}
+	if !strings.HasPrefix(r.Ref, "#") {
+		return "unresolved " + keyword + " " + r.Ref + ": references to other documents are not followed"
+	}
+	return "unresolved " + keyword + " " + r.Ref
+}
+
+// DynamicRef is a $dynamicRef detection and cleanup follow. Its dynamic scope is not evaluated, so
+// the schema it resolves to may not be the one a validator would pick.
+type DynamicRef struct {
+	Ref        string
+	Line       int // position of the $dynamicRef value
+	Column     int
+	TargetLine int // position of the schema it was resolved to
+}
+
+// Warning explains how the reference was resolved
+func (r DynamicRef) Warning() string {
+	return fmt.Sprintf("$dynamicRef %s resolved best-effort to the schema at line %d; its dynamic scope is not evaluated", r.Ref, r.TargetLine)
+}
+
+// UnresolvedRefs returns the $refs and $dynamicRefs in the parameters and responses of an operation
+// and the parameters it inherits from pathItem (which can be nil) that do not resolve within doc,
+// following the ones that do into the components they point at. Each reference is reported once, in
+// the order it is found. Nothing is reported without a document to resolve against.
+func UnresolvedRefs(pathItem, operation *yaml.Node, doc *yaml.Node) []UnresolvedRef {
+	unresolved, _ := followRefs(pathItem, operation, doc)
+	return unresolved
+}
+
+// DynamicRefs returns the $dynamicRefs followed for an operation that resolve, on a best-effort
+// basis, within doc
+func DynamicRefs(pathItem, operation *yaml.Node, doc *yaml.Node) []DynamicRef {
+	_, dynamic := followRefs(pathItem, operation, doc)
+	return dynamic
+}
+
+// followRefs walks the parameters and responses of an operation, following their $refs and
+// $dynamicRefs, and returns those that don't resolve and the $dynamicRefs that do
+func followRefs(pathItem, operation *yaml.Node, doc *yaml.Node) ([]UnresolvedRef, []DynamicRef) {
+	if doc == nil || operation == nil || operation.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	var unresolved []UnresolvedRef
+	var dynamic []DynamicRef
+	visited := make(map[*yaml.Node]bool)
+	var walk func(node *yaml.Node, scope schemaScope)
+	walk = func(node *yaml.Node, scope schemaScope) {
+		if node == nil || visited[node] {
+			return
+		}
+		visited[node] = true
+		if node.Kind == yaml.MappingNode {
+			scope = scope.enter(node)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i].Value, node.Content[i+1]
+				if (key != "$ref" && key != "$dynamicRef") || value.Kind != yaml.ScalarNode {
+					walk(value, scope)
+					continue
+				}
+				var resolved *yaml.Node
+				if key == "$ref" {
+					resolved = resolveInScope(value.Value, doc, scope)
+				} else {
+					resolved = resolveDynamicRef(value.Value, doc, scope)
+				}
+				if visited[value] {
+					continue
+				}
+				visited[value] = true
+				switch {
+				case resolved == nil:
+					unresolved = append(unresolved, UnresolvedRef{Ref: value.Value, Dynamic: key == "$dynamicRef", Line: value.Line, Column: value.Column})
+				case key == "$dynamicRef":
+					dynamic = append(dynamic, DynamicRef{Ref: value.Value, Line: value.Line, Column: value.Column, TargetLine: resolved.Line})
+				}
+				walk(resolved, scope)
+			}
+			return
+		}
+		for _, child := range node.Content {
+			walk(child, scope)
+		}
+	}
+
+	walk(getNodeValue(pathItem, "parameters"), nil)
+	walk(getNodeValue(operation, "parameters"), nil)
+	walk(getNodeValue(operation, "responses"), nil)
+	return unresolved, dynamic
+}
@@ -0,0 +1,67 @@
+package pagination
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const refsTestDoc = `paths:
+  /users:
+    parameters:
+    - $ref: '#/components/parameters/Missing'
+    get:
+      parameters:
+      - $ref: '#/components/parameters/Cursor'
+      - $ref: 'common.yaml#/components/parameters/Limit'
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Page'
+components:
+  parameters:
+    Cursor:
+      name: cursor
+      in: query
+      schema:
+        $ref: '#/components/schemas/Token'
+  schemas:
+    Page:
+      type: object
+      properties:
+        next:
+          $ref: '#/components/schemas/Token'
+        self:
+          $ref: '#/components/schemas/Page'
+`
+
+func TestUnresolvedRefs(t *testing.T) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(refsTestDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+	root := doc.Content[0]
+	pathItem := getNodeValue(getNodeValue(root, "paths"), "/users")
+	operation := getNodeValue(pathItem, "get")
+
+	want := []UnresolvedRef{
+		{Ref: "#/components/parameters/Missing", Line: 4, Column: 13},
+		{Ref: "#/components/schemas/Token", Line: 22, Column: 15},
+		{Ref: "common.yaml#/components/parameters/Limit", Line: 8, Column: 15},
+		{Ref: "#/components/schemas/Token", Line: 28, Column: 17},
+	}
+	if got := UnresolvedRefs(pathItem, operation, root); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := UnresolvedRefs(pathItem, operation, nil); got != nil {
+		t.Errorf("expected nothing without a document, got %+v", got)
+	}
+
+	if got := want[2].Reason(); got != "unresolved $ref common.yaml#/components/parameters/Limit: references to other documents are not followed" {
+		t.Errorf("unexpected reason %q", got)
+	}
+}
@@ -0,0 +1,32 @@
+package pagination
+
+import (
+	"sort"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+)
+
+// ResultsField returns the response property holding the results of the endpoint, as configured
+// in fields (path pattern -> property name), or "" when no pattern matches. An exact path wins over
+// patterns, and the longest matching pattern over shorter ones.
+func ResultsField(fields map[string]string, endpoint string) string {
+	if field, ok := fields[endpoint]; ok {
+		return field
+	}
+	patterns := make([]string, 0, len(fields))
+	for pattern := range fields {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	for _, pattern := range patterns {
+		if condition.MatchPattern(endpoint, pattern, condition.Glob) {
+			return fields[pattern]
+		}
+	}
+	return ""
+}
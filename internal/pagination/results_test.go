@@ -0,0 +1,83 @@
+package pagination
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestResultsField(t *testing.T) {
+	fields := map[string]string{
+		"/network-acls":     "network_acls",
+		"/network-acls/*":   "acls",
+		"/network-acls/*/x": "entries",
+		"/*":                "items",
+	}
+	tests := map[string]string{
+		"/network-acls":        "network_acls",
+		"/network-acls/{id}":   "acls",
+		"/network-acls/{id}/x": "entries",
+		"/users":               "items",
+		"/users/{id}":          "",
+	}
+	for endpoint, want := range tests {
+		if got := ResultsField(fields, endpoint); got != want {
+			t.Errorf("%s: expected %q, got %q", endpoint, want, got)
+		}
+	}
+}
+
+const resultsFieldOperation = `parameters:
+- name: cursor
+  in: query
+- name: offset
+  in: query
+responses:
+  '200':
+    description: OK
+    content:
+      application/json:
+        schema:
+          allOf:
+          - properties:
+              network_acls:
+                type: array
+          - properties:
+              next_cursor:
+                type: string
+          - properties:
+              total:
+                type: integer
+`
+
+func TestResultsFieldSurvivesCleanup(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		fields  map[string]string
+		members int
+	}{
+		{"auto-detected", nil, 1},
+		{"configured", map[string]string{"/network-acls": "network_acls"}, 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var node yaml.Node
+			if err := yaml.Unmarshal([]byte(resultsFieldOperation), &node); err != nil {
+				t.Fatal(err)
+			}
+			operation := node.Content[0]
+			opts := Options{Priority: []string{"cursor", "offset"}, ResultsFields: tt.fields}
+			if _, err := ProcessEndpointInPathItem(nil, operation, &node, "/network-acls", "get", opts); err != nil {
+				t.Fatal(err)
+			}
+
+			schema := getNodeValue(getNodeValue(getNodeValue(getNodeValue(getNodeValue(operation, "responses"), "200"), "content"), "application/json"), "schema")
+			allOf := getNodeValue(schema, "allOf")
+			if len(allOf.Content) != tt.members {
+				t.Fatalf("expected %d allOf members, got %d", tt.members, len(allOf.Content))
+			}
+			if tt.fields != nil && getNodeValue(getNodeValue(allOf.Content[0], "properties"), "network_acls") == nil {
+				t.Error("expected the network_acls member to be kept")
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package pagination
+
+import (
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tie is an operation whose pagination strategy the configuration does not decide: it uses the
+// parameters of several strategies and none of them is in its priority list, or several endpoint
+// rules with different strategies match it
+type Tie struct {
+	Detected   []DetectedPagination     // evidence of each strategy, params first
+	Candidates []string                 // strategies to choose from, sorted, followed by "none"
+	Rules      []EndpointPaginationRule // the conflicting endpoint rules, in order of definition
+}
+
+// FindTie returns the tie of an operation of the path item pathItem (which can be nil), or nil
+// when its strategy is decided or it has no pagination
+func FindTie(pathItem, operation *yaml.Node, doc *yaml.Node, endpoint, method string, opts Options) *Tie {
+	if operation == nil || operation.Kind != yaml.MappingNode {
+		return nil
+	}
+	params := withInheritedParams(getNodeValue(operation, "parameters"),
+		inheritedParams(getNodeValue(operation, "parameters"), getNodeValue(pathItem, "parameters"), doc))
+	strategies := detectPaginationStrategies(params, getNodeValue(operation, "responses"), doc)
+	if len(strategies.paramStrategies) == 0 {
+		return nil
+	}
+
+	tie := &Tie{Detected: strategies.allPagination}
+	if rules := matchingRules(endpoint, method, opts.EndpointRules); conflicting(rules) {
+		tie.Rules = rules
+	} else if len(rules) > 0 || len(strategies.paramStrategies) < 2 {
+		return nil
+	} else if selectBestStrategy(strategies, opts) != "" {
+		return nil
+	}
+
+	candidates := make(map[string]bool)
+	for _, detected := range strategies.allPagination {
+		candidates[detected.Strategy] = true
+	}
+	for _, rule := range tie.Rules {
+		if _, ok := PaginationStrategies[rule.Pagination]; ok {
+			candidates[rule.Pagination] = true
+		}
+	}
+	delete(candidates, "none")
+	for strategy := range candidates {
+		tie.Candidates = append(tie.Candidates, strategy)
+	}
+	sort.Strings(tie.Candidates)
+	tie.Candidates = append(tie.Candidates, "none")
+	return tie
+}
+
+// matchingRules returns the endpoint rules matching an operation, in order of definition
+func matchingRules(endpoint, method string, rules []EndpointPaginationRule) []EndpointPaginationRule {
+	var matching []EndpointPaginationRule
+	for _, rule := range rules {
+		if matchesEndpointPattern(endpoint, rule.Endpoint) && matchesMethodPattern(method, rule.Method) {
+			matching = append(matching, rule)
+		}
+	}
+	return matching
+}
+
+// conflicting reports whether rules pick different strategies. A first rule naming the operation
+// exactly, as the resolved ties are saved, settles the conflict.
+func conflicting(rules []EndpointPaginationRule) bool {
+	if len(rules) > 0 && exactRule(rules[0]) {
+		return false
+	}
+	for _, rule := range rules[min(1, len(rules)):] {
+		if rule.Pagination != rules[0].Pagination {
+			return true
+		}
+	}
+	return false
+}
+
+// exactRule reports whether a rule names a single path and method rather than a pattern
+func exactRule(rule EndpointPaginationRule) bool {
+	return !strings.HasPrefix(rule.Endpoint, "regex:") && !strings.ContainsAny(rule.Endpoint, "*?[") && rule.Method != "*"
+}
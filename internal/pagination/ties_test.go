@@ -0,0 +1,74 @@
+package pagination
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFindTie(t *testing.T) {
+	src := `
+parameters:
+  - name: cursor
+    in: query
+  - name: size
+    in: query
+  - name: page
+    in: query
+  - name: per_page
+    in: query
+responses:
+  "200":
+    description: ok
+`
+	var operation yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &operation); err != nil {
+		t.Fatal(err)
+	}
+	op := operation.Content[0]
+
+	tests := []struct {
+		name  string
+		opts  Options
+		want  []string
+		rules int
+	}{
+		{"priority decides", Options{Priority: []string{"page", "cursor"}}, nil, 0},
+		{"priority misses", Options{Priority: []string{"offset"}}, []string{"cursor", "page", "none"}, 0},
+		{"single rule decides", Options{Priority: []string{"offset"}, EndpointRules: []EndpointPaginationRule{
+			{Endpoint: "/users/*", Method: "GET", Pagination: "cursor"},
+		}}, nil, 0},
+		{"conflicting rules", Options{Priority: []string{"page"}, EndpointRules: []EndpointPaginationRule{
+			{Endpoint: "/users/*", Method: "GET", Pagination: "cursor"},
+			{Endpoint: "/users/list", Method: "*", Pagination: "offset"},
+		}}, []string{"cursor", "offset", "page", "none"}, 2},
+		{"exact rule settles the conflict", Options{Priority: []string{"page"}, EndpointRules: []EndpointPaginationRule{
+			{Endpoint: "/users/list", Method: "GET", Pagination: "page"},
+			{Endpoint: "/users/*", Method: "GET", Pagination: "cursor"},
+		}}, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tie := FindTie(nil, op, nil, "/users/list", "get", tt.opts)
+			if tt.want == nil {
+				if tie != nil {
+					t.Errorf("expected no tie, got %+v", tie)
+				}
+				return
+			}
+			if tie == nil {
+				t.Fatal("expected a tie")
+			}
+			if !reflect.DeepEqual(tie.Candidates, tt.want) {
+				t.Errorf("expected candidates %v, got %v", tt.want, tie.Candidates)
+			}
+			if len(tie.Rules) != tt.rules {
+				t.Errorf("expected %d conflicting rules, got %v", tt.rules, tie.Rules)
+			}
+			if len(tie.Detected) != 2 {
+				t.Errorf("expected the evidence of 2 strategies, got %+v", tie.Detected)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Package progress reports how far a pipeline run is: the step, the files it processed out of the
+// total, the file in progress and an estimate of the time left. On a terminal it redraws a single
+// status line; elsewhere it falls back to a log line every few seconds so CI logs stay readable.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// LogInterval is the time between log lines when the output is not a terminal
+	LogInterval = 5 * time.Second
+	// redrawInterval is the time between redraws of the status line
+	redrawInterval = 100 * time.Millisecond
+	// showDelay keeps short runs quiet: the status line appears once a run took this long
+	showDelay = 500 * time.Millisecond
+	// barWidth is the number of cells of the progress bar
+	barWidth = 20
+)
+
+// Reporter shows the progress of a run. It is safe for concurrent use, so a step may process its
+// files in parallel; the file shown is the one started last. A nil Reporter reports nothing.
+type Reporter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	live     bool          // redraw a status line instead of writing log lines
+	interval time.Duration // time between log lines
+	now      func() time.Time
+
+	total       int // files in the run, each step processes at most this many
+	started     time.Time
+	step        string
+	stepStarted time.Time
+	done        int    // files of the step processed so far
+	current     string // file started last
+	shown       time.Time
+	logged      bool // a log line was written for the step
+	width       int  // length of the status line on screen, 0 when none is drawn
+}
+
+// New returns a Reporter writing to w. With live, w is a terminal the status line is redrawn on.
+func New(w io.Writer, live bool) *Reporter {
+	return &Reporter{w: w, live: live, interval: LogInterval, now: time.Now}
+}
+
+// IsTerminal reports whether f is a terminal rather than a pipe or a file
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Start begins a run over total files
+func (r *Reporter) Start(total int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.total = total
+	r.started = r.now()
+	r.shown = r.started
+}
+
+// StartStep begins a pipeline step
+func (r *Reporter) StartStep(step string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.step = step
+	r.stepStarted = r.now()
+	r.done = 0
+	r.current = ""
+	r.logged = false
+}
+
+// StartFile records that the step began processing path
+func (r *Reporter) StartFile(path string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = path
+	r.update()
+}
+
+// FinishFile records that the step finished processing a file
+func (r *Reporter) FinishFile() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done++
+	r.update()
+}
+
+// FinishStep ends the current step. When the step was logged, its completion is logged too.
+func (r *Reporter) FinishStep() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.live && r.logged {
+		fmt.Fprintf(r.w, "[openmorph] %s: done, %d files in %s\n", r.step, r.done, formatDuration(r.now().Sub(r.stepStarted)))
+	}
+	r.step = ""
+}
+
+// Finish ends the run and clears the status line
+func (r *Reporter) Finish() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clear()
+}
+
+// update shows the status when it is due; the caller holds the lock
+func (r *Reporter) update() {
+	now := r.now()
+	if r.live {
+		if now.Sub(r.started) < showDelay || now.Sub(r.shown) < redrawInterval {
+			return
+		}
+		r.draw(r.status(now))
+	} else {
+		if now.Sub(r.shown) < r.interval {
+			return
+		}
+		fmt.Fprintf(r.w, "[openmorph] %s\n", r.status(now))
+		r.logged = true
+	}
+	r.shown = now
+}
+
+// status describes the progress of the step, such as
+// "pagination [████░░░░] 12/40 files, ETA 3s, specs/users.yaml"
+func (r *Reporter) status(now time.Time) string {
+	total := r.total
+	if r.done > total {
+		total = r.done
+	}
+
+	parts := []string{fmt.Sprintf("%d/%d files", r.done, total)}
+	if r.done > 0 && total > r.done {
+		elapsed := now.Sub(r.stepStarted)
+		left := elapsed / time.Duration(r.done) * time.Duration(total-r.done)
+		parts = append(parts, "ETA "+formatDuration(left))
+	}
+	if r.current != "" {
+		parts = append(parts, r.current)
+	}
+
+	step := r.step
+	if r.live {
+		step += " " + bar(r.done, total)
+	}
+	return step + " " + strings.Join(parts, ", ")
+}
+
+// draw replaces the status line on the terminal
+func (r *Reporter) draw(line string) {
+	padding := ""
+	if n := len([]rune(line)); n < r.width {
+		padding = strings.Repeat(" ", r.width-n)
+	}
+	fmt.Fprintf(r.w, "\r%s%s", line, padding)
+	r.width = len([]rune(line))
+}
+
+// clear erases the status line, if one is drawn
+func (r *Reporter) clear() {
+	if r.width == 0 {
+		return
+	}
+	fmt.Fprintf(r.w, "\r%s\r", strings.Repeat(" ", r.width))
+	r.width = 0
+}
+
+// bar renders done out of total as a bar of barWidth cells
+func bar(done, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = done * barWidth / total
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled) + "]"
+}
+
+// formatDuration rounds d to what a person reading a status line cares about
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
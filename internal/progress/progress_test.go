@@ -0,0 +1,120 @@
+package progress
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock advances by step every time it is read
+type fakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *fakeClock) read() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func newTestReporter(live bool, step time.Duration) (*Reporter, *bytes.Buffer) {
+	var out bytes.Buffer
+	r := New(&out, live)
+	r.now = (&fakeClock{now: time.Unix(0, 0), step: step}).read
+	return r, &out
+}
+
+func TestReporterLogsPeriodically(t *testing.T) {
+	r, out := newTestReporter(false, time.Second)
+	r.Start(10)
+	r.StartStep("pagination")
+	for i := 0; i < 10; i++ {
+		path := fmt.Sprintf("specs/%d.yaml", i)
+		r.StartFile(path)
+		r.FinishFile()
+	}
+	r.FinishStep()
+	r.Finish()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected a few log lines, got:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[0], "[openmorph] pagination ") || !strings.Contains(lines[0], "/10 files, ETA ") {
+		t.Errorf("unexpected log line %q", lines[0])
+	}
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, "[openmorph] pagination: done, 10 files in ") {
+		t.Errorf("expected the step's completion to be logged, got %q", last)
+	}
+	if strings.Contains(out.String(), "\r") {
+		t.Error("expected no status line redraws outside a terminal")
+	}
+}
+
+func TestReporterQuietForShortSteps(t *testing.T) {
+	r, out := newTestReporter(false, time.Millisecond)
+	r.Start(2)
+	r.StartStep("defaults")
+	r.StartFile("a.yaml")
+	r.FinishFile()
+	r.FinishStep()
+	r.Finish()
+	if out.Len() != 0 {
+		t.Errorf("expected nothing for a short step, got %q", out.String())
+	}
+}
+
+func TestReporterDrawsStatusLine(t *testing.T) {
+	r, out := newTestReporter(true, 200*time.Millisecond)
+	r.Start(4)
+	r.StartStep("flatten")
+	r.StartFile("specs/users.yaml")
+	r.FinishFile()
+	r.StartFile("specs/orders.yaml")
+	r.Finish()
+
+	got := out.String()
+	if !strings.Contains(got, "\rflatten [█████░░░░░░░░░░░░░░░] 1/4 files, ETA ") || !strings.Contains(got, "specs/orders.yaml") {
+		t.Errorf("unexpected status line %q", got)
+	}
+	if !strings.HasSuffix(got, "\r") {
+		t.Errorf("expected the status line to be cleared, got %q", got)
+	}
+}
+
+func TestReporterConcurrentFiles(t *testing.T) {
+	r, _ := newTestReporter(false, time.Millisecond)
+	r.Start(50)
+	r.StartStep("vendor_extensions")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r.StartFile(fmt.Sprintf("%d.yaml", i))
+			r.FinishFile()
+		}(i)
+	}
+	wg.Wait()
+
+	if r.done != 50 {
+		t.Errorf("expected 50 files done, got %d", r.done)
+	}
+}
+
+func TestNilReporter(_ *testing.T) {
+	var r *Reporter
+	r.Start(1)
+	r.StartStep("mappings")
+	r.StartFile("a.yaml")
+	r.FinishFile()
+	r.FinishStep()
+	r.Finish()
+}
@@ -0,0 +1,233 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+// Annotation formats accepted by --annotations
+const AnnotationsGitHub = "github"
+
+// Annotation levels, matching the GitHub Actions workflow commands
+const (
+	AnnotationNotice  = "notice"
+	AnnotationWarning = "warning"
+	AnnotationError   = "error"
+)
+
+// Annotation is a finding attached to a file, and to a line when it is known
+type Annotation struct {
+	Level   string
+	File    string
+	Line    int
+	Column  int
+	Title   string
+	Message string
+}
+
+// SkippedAnnotations returns a warning for every item a step skipped with a reason
+func SkippedAnnotations(results *transform.TransformationResults) []Annotation {
+	var annotations []Annotation
+	add := func(title string, skipped map[string][]transform.Skip) {
+		for file, skips := range skipped {
+			for _, skip := range skips {
+				annotations = append(annotations, Annotation{Level: AnnotationWarning, File: file, Title: title, Message: skip.Message})
+			}
+		}
+	}
+
+	if results.VendorResult != nil {
+		add("Vendor extension skipped", results.VendorResult.SkippedOperations)
+	}
+	if results.DefaultsResult != nil {
+		add("Default value skipped", results.DefaultsResult.SkippedTargets)
+	}
+	if results.RenameResult != nil {
+		add("Component rename skipped", results.RenameResult.SkippedRenames)
+	}
+
+	sort.SliceStable(annotations, func(i, j int) bool {
+		if annotations[i].File != annotations[j].File {
+			return annotations[i].File < annotations[j].File
+		}
+		return annotations[i].Title < annotations[j].Title
+	})
+	return annotations
+}
+
+// StrictAnnotations returns an error for every problem found by strict mode, at its position
+func StrictAnnotations(issues []transform.StrictIssue) []Annotation {
+	annotations := make([]Annotation, 0, len(issues))
+	for _, issue := range issues {
+		annotations = append(annotations, Annotation{
+			Level:   AnnotationError,
+			File:    issue.File,
+			Line:    issue.Line,
+			Column:  issue.Column,
+			Title:   "Strict mode",
+			Message: issue.Message,
+		})
+	}
+	return annotations
+}
+
+// ExtensionAnnotations returns an annotation at the given level for every extension value that
+// does not match its registered schema
+func ExtensionAnnotations(issues []transform.StrictIssue, level string) []Annotation {
+	annotations := make([]Annotation, 0, len(issues))
+	for _, issue := range issues {
+		annotations = append(annotations, Annotation{
+			Level:   level,
+			File:    issue.File,
+			Line:    issue.Line,
+			Column:  issue.Column,
+			Title:   "Extension schema",
+			Message: issue.Message,
+		})
+	}
+	return annotations
+}
+
+// RefAnnotations returns an annotation at the given level for every dangling $ref, at its position
+func RefAnnotations(issues []transform.StrictIssue, level string) []Annotation {
+	annotations := make([]Annotation, 0, len(issues))
+	for _, issue := range issues {
+		annotations = append(annotations, Annotation{
+			Level:   level,
+			File:    issue.File,
+			Line:    issue.Line,
+			Column:  issue.Column,
+			Title:   "Dangling $ref",
+			Message: issue.Message,
+		})
+	}
+	return annotations
+}
+
+// DanglingRefAnnotations returns a warning for every $ref a step left dangling, naming the step
+func DanglingRefAnnotations(results *transform.TransformationResults) []Annotation {
+	issues := make([]transform.StrictIssue, 0, len(results.DanglingRefs))
+	for _, ref := range results.DanglingRefs {
+		issue := ref.StrictIssue
+		issue.Message = fmt.Sprintf("%s (after %s)", issue.Message, ref.Step)
+		issues = append(issues, issue)
+	}
+	return RefAnnotations(issues, AnnotationWarning)
+}
+
+// UnresolvedRefAnnotations returns a warning for every $ref the pagination step followed but could
+// not resolve, and every $dynamicRef it resolved best-effort, naming the operation that followed it
+func UnresolvedRefAnnotations(results *transform.TransformationResults) []Annotation {
+	if results.PaginationResult == nil {
+		return nil
+	}
+	annotations := make([]Annotation, 0, len(results.PaginationResult.UnresolvedRefs)+len(results.PaginationResult.DynamicRefs))
+	for _, ref := range results.PaginationResult.UnresolvedRefs {
+		annotations = append(annotations, refAnnotation(ref, "Unresolved $ref"))
+	}
+	for _, ref := range results.PaginationResult.DynamicRefs {
+		annotations = append(annotations, refAnnotation(ref, "Dynamic $ref"))
+	}
+	return annotations
+}
+
+// refAnnotation returns the warning for a reference the pagination step followed
+func refAnnotation(ref transform.UnresolvedRef, title string) Annotation {
+	return Annotation{
+		Level:   AnnotationWarning,
+		File:    ref.File,
+		Line:    ref.Line,
+		Column:  ref.Column,
+		Title:   title,
+		Message: fmt.Sprintf("%s (followed by the pagination step for %s)", ref.Message, ref.Operation),
+	}
+}
+
+// EmptySchemaAnnotations returns a warning for every object schema or response content a step left
+// empty, naming the step
+func EmptySchemaAnnotations(results *transform.TransformationResults) []Annotation {
+	annotations := make([]Annotation, 0, len(results.EmptySchemas))
+	for _, schema := range results.EmptySchemas {
+		message := fmt.Sprintf("%s (after %s)", schema.Message, schema.Step)
+		if schema.Fixed {
+			message += ", fixed"
+		}
+		annotations = append(annotations, Annotation{
+			Level:   AnnotationWarning,
+			File:    schema.File,
+			Line:    schema.Line,
+			Column:  schema.Column,
+			Title:   "Empty schema",
+			Message: message,
+		})
+	}
+	return annotations
+}
+
+// InvariantAnnotations returns a warning for every operation whose pagination is inconsistent
+// after the pipeline, naming the invariant
+func InvariantAnnotations(results *transform.TransformationResults) []Annotation {
+	annotations := make([]Annotation, 0, len(results.InvariantViolations))
+	for _, violation := range results.InvariantViolations {
+		annotations = append(annotations, Annotation{
+			Level:   AnnotationWarning,
+			File:    violation.File,
+			Line:    violation.Line,
+			Column:  violation.Column,
+			Title:   "Pagination invariant " + violation.Invariant,
+			Message: violation.Message,
+		})
+	}
+	return annotations
+}
+
+// FileErrorAnnotations returns an error for every file that failed and was left alone in a
+// keep-going run
+func FileErrorAnnotations(results *transform.TransformationResults) []Annotation {
+	annotations := make([]Annotation, 0, len(results.FileErrors))
+	for _, fileError := range results.FileErrors {
+		annotations = append(annotations, Annotation{
+			Level:   AnnotationError,
+			File:    fileError.File,
+			Line:    fileError.Line,
+			Title:   "File failed in step " + fileError.Step,
+			Message: fileError.Message,
+		})
+	}
+	return annotations
+}
+
+// WriteGitHubAnnotations writes every annotation as a GitHub Actions workflow command
+// (::warning file=...,line=...::message), which annotates the file inline in pull requests
+func WriteGitHubAnnotations(w io.Writer, annotations []Annotation) error {
+	for _, annotation := range annotations {
+		properties := []string{"file=" + escapeProperty(artifactURI(annotation.File))}
+		if annotation.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", annotation.Line))
+			if annotation.Column > 0 {
+				properties = append(properties, fmt.Sprintf("col=%d", annotation.Column))
+			}
+		}
+		if annotation.Title != "" {
+			properties = append(properties, "title="+escapeProperty(annotation.Title))
+		}
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", annotation.Level, strings.Join(properties, ","), escapeData(annotation.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeData escapes a workflow command message
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
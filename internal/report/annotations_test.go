@@ -0,0 +1,173 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+func TestSkippedAnnotations(t *testing.T) {
+	results := &transform.TransformationResults{
+		VendorResult: &transform.VendorExtensionResult{
+			SkippedOperations: map[string][]transform.Skip{"specs/b.yaml": {{Message: "GET /users: no pagination detected", Code: transform.SkipNoPagination}}},
+		},
+		DefaultsResult: &transform.DefaultsResult{
+			SkippedTargets: map[string][]transform.Skip{"specs/a.yaml": {{Message: "parameter limit: default already exists", Code: transform.SkipDefaultExists}}},
+		},
+		RenameResult: &transform.RenameResult{
+			SkippedRenames: map[string][]transform.Skip{"specs/a.yaml": {{Message: "schemas.UserV2: target User already exists", Code: transform.SkipNameCollision}}},
+		},
+	}
+
+	annotations := SkippedAnnotations(results)
+	if len(annotations) != 3 {
+		t.Fatalf("expected 3 annotations, got %+v", annotations)
+	}
+	want := []string{"Component rename skipped", "Default value skipped", "Vendor extension skipped"}
+	for i, title := range want {
+		if annotations[i].Title != title || annotations[i].Level != AnnotationWarning {
+			t.Errorf("annotation %d: expected warning %q, got %+v", i, title, annotations[i])
+		}
+	}
+}
+
+func TestStrictAnnotations(t *testing.T) {
+	issues := []transform.StrictIssue{
+		{File: "specs/api.yaml", Line: 12, Column: 15, Message: "unresolved $ref #/components/schemas/Missing"},
+	}
+	annotations := StrictAnnotations(issues)
+	if len(annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %+v", annotations)
+	}
+	a := annotations[0]
+	if a.Level != AnnotationError || a.File != "specs/api.yaml" || a.Line != 12 || a.Column != 15 || a.Title != "Strict mode" {
+		t.Errorf("unexpected annotation %+v", a)
+	}
+}
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	annotations := []Annotation{
+		{Level: AnnotationWarning, File: "specs/api.yaml", Line: 12, Column: 5, Title: "Default value skipped", Message: "GET /users: 100% done\nnext"},
+		{Level: AnnotationError, File: "specs/a,b.yaml", Message: "swagger-cli validate failed"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGitHubAnnotations(&buf, annotations); err != nil {
+		t.Fatalf("WriteGitHubAnnotations failed: %v", err)
+	}
+
+	want := "::warning file=specs/api.yaml,line=12,col=5,title=Default value skipped::GET /users: 100%25 done%0Anext\n" +
+		"::error file=specs/a%2Cb.yaml::swagger-cli validate failed\n"
+	if buf.String() != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestExtensionAnnotations(t *testing.T) {
+	issues := []transform.StrictIssue{
+		{File: "specs/api.yaml", Line: 9, Column: 17, Message: "x-pagination.cursor: expected string, got integer"},
+	}
+	annotations := ExtensionAnnotations(issues, AnnotationWarning)
+	if len(annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %+v", annotations)
+	}
+	a := annotations[0]
+	if a.Level != AnnotationWarning || a.Line != 9 || a.Column != 17 || a.Title != "Extension schema" {
+		t.Errorf("unexpected annotation %+v", a)
+	}
+}
+
+func TestDanglingRefAnnotations(t *testing.T) {
+	results := &transform.TransformationResults{DanglingRefs: []transform.DanglingRef{{
+		StrictIssue: transform.StrictIssue{File: "specs/api.yaml", Line: 7, Column: 9, Message: "unresolved $ref #/components/schemas/User"},
+		Step:        transform.StepComponentRenames,
+	}}}
+	annotations := DanglingRefAnnotations(results)
+	if len(annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %+v", annotations)
+	}
+	a := annotations[0]
+	if a.Level != AnnotationWarning || a.Line != 7 || a.Title != "Dangling $ref" ||
+		a.Message != "unresolved $ref #/components/schemas/User (after component_renames)" {
+		t.Errorf("unexpected annotation %+v", a)
+	}
+}
+
+func TestUnresolvedRefAnnotations(t *testing.T) {
+	results := &transform.TransformationResults{PaginationResult: &transform.PaginationResult{UnresolvedRefs: []transform.UnresolvedRef{{
+		StrictIssue: transform.StrictIssue{File: "specs/api.yaml", Line: 9, Column: 17, Message: "unresolved $ref #/components/parameters/Cursor"},
+		Operation:   "GET /users",
+	}}}}
+	annotations := UnresolvedRefAnnotations(results)
+	if len(annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %+v", annotations)
+	}
+	a := annotations[0]
+	if a.Level != AnnotationWarning || a.Line != 9 || a.Title != "Unresolved $ref" ||
+		a.Message != "unresolved $ref #/components/parameters/Cursor (followed by the pagination step for GET /users)" {
+		t.Errorf("unexpected annotation %+v", a)
+	}
+	if got := UnresolvedRefAnnotations(&transform.TransformationResults{}); len(got) != 0 {
+		t.Errorf("expected no annotations without a pagination result, got %+v", got)
+	}
+}
+
+func TestDynamicRefAnnotations(t *testing.T) {
+	results := &transform.TransformationResults{PaginationResult: &transform.PaginationResult{DynamicRefs: []transform.UnresolvedRef{{
+		StrictIssue: transform.StrictIssue{File: "specs/api.yaml", Line: 14, Column: 31, Message: "$dynamicRef #page resolved best-effort to the schema at line 40; its dynamic scope is not evaluated"},
+		Operation:   "GET /teams",
+	}}}}
+	annotations := UnresolvedRefAnnotations(results)
+	if len(annotations) != 1 || annotations[0].Title != "Dynamic $ref" || annotations[0].Line != 14 {
+		t.Errorf("unexpected annotations %+v", annotations)
+	}
+}
+
+func TestEmptySchemaAnnotations(t *testing.T) {
+	results := &transform.TransformationResults{EmptySchemas: []transform.EmptySchema{{
+		StrictIssue: transform.StrictIssue{File: "specs/api.yaml", Line: 12, Column: 15, Message: "component schemas.User: object schema has no properties"},
+		Step:        transform.StepStripInternal,
+		Fixed:       true,
+	}}}
+	annotations := EmptySchemaAnnotations(results)
+	if len(annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %+v", annotations)
+	}
+	a := annotations[0]
+	if a.Level != AnnotationWarning || a.Line != 12 || a.Title != "Empty schema" ||
+		a.Message != "component schemas.User: object schema has no properties (after strip_internal), fixed" {
+		t.Errorf("unexpected annotation %+v", a)
+	}
+}
+
+func TestInvariantAnnotations(t *testing.T) {
+	results := &transform.TransformationResults{InvariantViolations: []transform.InvariantViolation{{
+		StrictIssue: transform.StrictIssue{File: "specs/api.yaml", Line: 8, Column: 5, Message: "GET /users: parameters of several pagination strategies: cursor, offset"},
+		Invariant:   transform.InvariantSingleStrategy,
+	}}}
+	annotations := InvariantAnnotations(results)
+	if len(annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %+v", annotations)
+	}
+	a := annotations[0]
+	if a.Level != AnnotationWarning || a.Line != 8 || a.Title != "Pagination invariant single_strategy" ||
+		a.Message != "GET /users: parameters of several pagination strategies: cursor, offset" {
+		t.Errorf("unexpected annotation %+v", a)
+	}
+}
+
+func TestFileErrorAnnotations(t *testing.T) {
+	results := &transform.TransformationResults{FileErrors: []transform.FileError{{
+		File: "specs/broken.yaml", Step: transform.StepMappings, Line: 3, Message: "yaml: line 3: did not find expected key",
+	}}}
+	annotations := FileErrorAnnotations(results)
+	if len(annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %+v", annotations)
+	}
+	a := annotations[0]
+	if a.Level != AnnotationError || a.Line != 3 || a.Title != "File failed in step mappings" ||
+		a.Message != "yaml: line 3: did not find expected key" {
+		t.Errorf("unexpected annotation %+v", a)
+	}
+}
@@ -0,0 +1,91 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+type batchReport struct {
+	Tool    string           `json:"tool"`
+	Version string           `json:"version,omitempty"`
+	Summary batchSummary     `json:"summary"`
+	Jobs    []batchJobReport `json:"jobs"`
+}
+
+type batchSummary struct {
+	Jobs        int `json:"jobs"`
+	Transformed int `json:"transformed"`
+	Unchanged   int `json:"unchanged"`
+	Cached      int `json:"cached"`
+	Failed      int `json:"failed"`
+}
+
+type batchJobReport struct {
+	Name         string   `json:"name"`
+	Input        string   `json:"input"`
+	Output       string   `json:"output"`
+	Profile      string   `json:"profile,omitempty"`
+	Status       string   `json:"status"`
+	Error        string   `json:"error,omitempty"`
+	CachedFrom   string   `json:"cached_from,omitempty"`
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	Changes      int      `json:"changes"`
+}
+
+// WriteBatchReport writes a consolidated JSON report of a batch run with one entry per job
+func WriteBatchReport(w io.Writer, results *transform.BatchResults, version string) error {
+	report := batchReport{Tool: toolName, Version: version, Jobs: []batchJobReport{}}
+	for _, job := range results.Jobs {
+		entry := batchJobReport{
+			Name:       job.Job.Name,
+			Input:      job.Job.Input,
+			Output:     job.Output,
+			Profile:    job.Job.Profile,
+			Status:     job.Status(),
+			CachedFrom: job.CachedFrom,
+		}
+		if job.Err != nil {
+			entry.Error = job.Err.Error()
+		}
+		if job.Results != nil {
+			entry.ChangedFiles = job.Results.Changed
+			entry.Changes = len(job.Results.AllLocations())
+		}
+		report.Jobs = append(report.Jobs, entry)
+
+		report.Summary.Jobs++
+		switch entry.Status {
+		case transform.BatchStatusTransformed:
+			report.Summary.Transformed++
+		case transform.BatchStatusUnchanged:
+			report.Summary.Unchanged++
+		case transform.BatchStatusCached:
+			report.Summary.Cached++
+		case transform.BatchStatusFailed:
+			report.Summary.Failed++
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(report)
+}
+
+// WriteBatchReportFile writes a batch report to path
+func WriteBatchReportFile(path string, results *transform.BatchResults, version string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create batch report: %w", err)
+	}
+	defer file.Close()
+
+	if err := WriteBatchReport(file, results, version); err != nil {
+		return fmt.Errorf("failed to write batch report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+func TestWriteBatchReport(t *testing.T) {
+	results := &transform.BatchResults{Jobs: []transform.BatchJobResult{
+		{
+			Job:    config.BatchJob{Name: "users", Input: "specs/users.yaml", Profile: "fern"},
+			Output: "out/users.yaml",
+			Results: &transform.TransformationResults{
+				Changed:            []string{"out/users.yaml"},
+				KeyChanges:         []transform.KeyChange{{File: "out/users.yaml", OldKey: "x-a", NewKey: "x-b", Line: 3}},
+				AnyTransformations: true,
+			},
+		},
+		{Job: config.BatchJob{Name: "copy", Input: "specs/users.yaml"}, Output: "out/copy.yaml", CachedFrom: "users"},
+		{Job: config.BatchJob{Name: "broken", Input: "specs/missing.yaml"}, Output: "specs/missing.yaml", Err: errors.New("boom")},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteBatchReport(&buf, results, "v1.2.3"); err != nil {
+		t.Fatalf("WriteBatchReport failed: %v", err)
+	}
+
+	var report batchReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid report JSON: %v", err)
+	}
+	want := batchSummary{Jobs: 3, Transformed: 1, Cached: 1, Failed: 1}
+	if report.Summary != want {
+		t.Errorf("expected summary %+v, got %+v", want, report.Summary)
+	}
+	if report.Jobs[0].Changes != 1 || report.Jobs[0].Profile != "fern" {
+		t.Errorf("unexpected users entry: %+v", report.Jobs[0])
+	}
+	if report.Jobs[1].CachedFrom != "users" || report.Jobs[2].Error != "boom" {
+		t.Errorf("unexpected entries: %+v", report.Jobs[1:])
+	}
+}
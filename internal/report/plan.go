@@ -0,0 +1,127 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+// Plan is the machine-readable form of a dry run: what each step would change in each file
+type Plan struct {
+	Tool     string        `json:"tool"`
+	Version  string        `json:"version,omitempty"`
+	Changes  int           `json:"changes"`
+	Files    []PlanFile    `json:"files"`
+	Failures []PlanFailure `json:"failures,omitempty"` // files --keep-going left alone
+	Metrics  []PlanMetric  `json:"metrics,omitempty"`  // time and size change of each step on each file
+}
+
+// PlanFile lists the changes of one file, by step in pipeline order
+type PlanFile struct {
+	File  string     `json:"file"`
+	Steps []PlanStep `json:"steps"`
+}
+
+// PlanStep lists the changes one step would make to a file, in document order
+type PlanStep struct {
+	Step    string       `json:"step"`
+	Changes []PlanChange `json:"changes"`
+}
+
+// PlanChange is one operation, key or component that would change
+type PlanChange struct {
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// PlanFailure is a file a step failed on
+type PlanFailure struct {
+	File    string `json:"file"`
+	Step    string `json:"step"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// PlanMetric is how long a step took on a file and the size of the file before and after it
+type PlanMetric struct {
+	File        string  `json:"file"`
+	Step        string  `json:"step"`
+	DurationMS  float64 `json:"duration_ms"`
+	BytesBefore int64   `json:"bytes_before"`
+	BytesAfter  int64   `json:"bytes_after"`
+	SizeDelta   int64   `json:"size_delta"`
+}
+
+// NewPlan groups the change locations of a dry run by file, sorted, and by step, in pipeline order
+func NewPlan(results *transform.TransformationResults, version string) Plan {
+	plan := Plan{Tool: toolName, Version: version, Files: []PlanFile{}}
+
+	byFile := make(map[string]map[string][]PlanChange)
+	var steps []string
+	seenSteps := make(map[string]bool)
+	for _, location := range results.AllLocations() {
+		if !seenSteps[location.Step] {
+			seenSteps[location.Step] = true
+			steps = append(steps, location.Step)
+		}
+		if byFile[location.File] == nil {
+			byFile[location.File] = make(map[string][]PlanChange)
+		}
+		byFile[location.File][location.Step] = append(byFile[location.File][location.Step],
+			PlanChange{Line: location.Line, Column: location.Column, Message: location.Message})
+		plan.Changes++
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool {
+		return transform.StepOrder(steps[i]) < transform.StepOrder(steps[j])
+	})
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		planFile := PlanFile{File: file}
+		for _, step := range steps {
+			changes := byFile[file][step]
+			if len(changes) == 0 {
+				continue
+			}
+			planFile.Steps = append(planFile.Steps, PlanStep{Step: step, Changes: changes})
+		}
+		plan.Files = append(plan.Files, planFile)
+	}
+
+	for _, failure := range results.FileErrors {
+		plan.Failures = append(plan.Failures, PlanFailure{
+			File:    failure.File,
+			Step:    failure.Step,
+			Line:    failure.Line,
+			Message: failure.Message,
+		})
+	}
+
+	for _, metric := range results.FileMetrics {
+		plan.Metrics = append(plan.Metrics, PlanMetric{
+			File:        metric.File,
+			Step:        metric.Step,
+			DurationMS:  float64(metric.Duration.Microseconds()) / 1000,
+			BytesBefore: metric.BytesBefore,
+			BytesAfter:  metric.BytesAfter,
+			SizeDelta:   metric.SizeDelta(),
+		})
+	}
+	sort.SliceStable(plan.Metrics, func(i, j int) bool { return plan.Metrics[i].File < plan.Metrics[j].File })
+	return plan
+}
+
+// WritePlan writes the plan of a dry run as indented JSON
+func WritePlan(w io.Writer, results *transform.TransformationResults, version string) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(NewPlan(results, version))
+}
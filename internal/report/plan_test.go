@@ -0,0 +1,61 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+func TestWritePlan(t *testing.T) {
+	results := &transform.TransformationResults{
+		KeyChanges: []transform.KeyChange{{File: "specs/b.yaml", Line: 9, Column: 7, OldKey: "x-foo", NewKey: "x-bar"}},
+		PaginationResult: &transform.PaginationResult{Locations: []transform.ChangeLocation{
+			{File: "specs/b.yaml", Line: 12, Column: 5, Step: transform.StepPagination, Message: "GET /users"},
+			{File: "specs/a.yaml", Line: 4, Column: 5, Step: transform.StepPagination, Message: "GET /teams"},
+		}},
+		FileErrors: []transform.FileError{{File: "specs/c.yaml", Step: transform.StepMappings, Line: 3, Message: "bad indentation"}},
+		FileMetrics: []transform.FileMetric{
+			{Step: transform.StepMappings, File: "specs/b.yaml", Duration: 1500 * time.Microsecond, BytesBefore: 100, BytesAfter: 120},
+			{Step: transform.StepMappings, File: "specs/a.yaml", Duration: time.Millisecond, BytesBefore: 80, BytesAfter: 80},
+			{Step: transform.StepPagination, File: "specs/b.yaml", Duration: 2 * time.Millisecond, BytesBefore: 120, BytesAfter: 90},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePlan(&buf, results, "1.2.3"); err != nil {
+		t.Fatal(err)
+	}
+	var plan Plan
+	if err := json.Unmarshal(buf.Bytes(), &plan); err != nil {
+		t.Fatalf("invalid plan JSON: %v", err)
+	}
+
+	if plan.Tool != "openmorph" || plan.Version != "1.2.3" || plan.Changes != 3 {
+		t.Errorf("unexpected plan envelope: %+v", plan)
+	}
+	if len(plan.Files) != 2 || plan.Files[0].File != "specs/a.yaml" || plan.Files[1].File != "specs/b.yaml" {
+		t.Fatalf("expected files in sorted order, got %+v", plan.Files)
+	}
+	steps := plan.Files[1].Steps
+	if len(steps) != 2 || steps[0].Step != transform.StepMappings || steps[1].Step != transform.StepPagination {
+		t.Fatalf("expected steps in pipeline order, got %+v", steps)
+	}
+	if change := steps[0].Changes[0]; change.Line != 9 || change.Column != 7 || change.Message != "x-foo -> x-bar" {
+		t.Errorf("unexpected change %+v", change)
+	}
+	if len(plan.Failures) != 1 || plan.Failures[0].File != "specs/c.yaml" || plan.Failures[0].Line != 3 {
+		t.Errorf("unexpected failures %+v", plan.Failures)
+	}
+	if len(plan.Metrics) != 3 || plan.Metrics[0].File != "specs/a.yaml" || plan.Metrics[2].Step != transform.StepPagination {
+		t.Fatalf("expected metrics by file in run order, got %+v", plan.Metrics)
+	}
+	if metric := plan.Metrics[1]; metric.DurationMS != 1.5 || metric.SizeDelta != 20 {
+		t.Errorf("unexpected metric %+v", metric)
+	}
+	if metric := plan.Metrics[2]; metric.SizeDelta != -30 {
+		t.Errorf("expected pagination to shrink the file by 30 bytes, got %+v", metric)
+	}
+}
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+// RunMetrics summarizes a run for the Prometheus textfile output
+type RunMetrics struct {
+	Steps        []transform.StepMetric // one entry per step, summed over every result of the run
+	FilesChanged int
+	Duration     time.Duration
+	Finished     time.Time
+}
+
+// NewRunMetrics aggregates the step metrics and changed files of one or more pipeline runs
+func NewRunMetrics(duration time.Duration, finished time.Time, results ...*transform.TransformationResults) RunMetrics {
+	metrics := RunMetrics{Duration: duration, Finished: finished}
+
+	index := make(map[string]int)
+	changed := make(map[string]bool)
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		for _, step := range r.StepMetrics {
+			i, ok := index[step.Step]
+			if !ok {
+				index[step.Step] = len(metrics.Steps)
+				metrics.Steps = append(metrics.Steps, transform.StepMetric{Step: step.Step})
+				i = len(metrics.Steps) - 1
+			}
+			metrics.Steps[i].Duration += step.Duration
+			metrics.Steps[i].Changes += step.Changes
+		}
+		for _, file := range r.Changed {
+			changed[file] = true
+		}
+		for _, location := range r.AllLocations() {
+			changed[location.File] = true
+		}
+	}
+	metrics.FilesChanged = len(changed)
+	return metrics
+}
+
+// WritePrometheus writes the run metrics in the Prometheus text exposition format
+func WritePrometheus(w io.Writer, metrics RunMetrics) error {
+	if _, err := fmt.Fprint(w, "# HELP openmorph_step_duration_seconds Time spent in each pipeline step during the last run.\n"+
+		"# TYPE openmorph_step_duration_seconds gauge\n"); err != nil {
+		return err
+	}
+	for _, step := range metrics.Steps {
+		fmt.Fprintf(w, "openmorph_step_duration_seconds{step=%q} %g\n", step.Step, step.Duration.Seconds())
+	}
+
+	fmt.Fprint(w, "# HELP openmorph_step_changes Changes made by each pipeline step during the last run.\n"+
+		"# TYPE openmorph_step_changes gauge\n")
+	for _, step := range metrics.Steps {
+		fmt.Fprintf(w, "openmorph_step_changes{step=%q} %d\n", step.Step, step.Changes)
+	}
+
+	fmt.Fprintf(w, "# HELP openmorph_files_changed Files changed by the last run.\n"+
+		"# TYPE openmorph_files_changed gauge\n"+
+		"openmorph_files_changed %d\n", metrics.FilesChanged)
+	fmt.Fprintf(w, "# HELP openmorph_run_duration_seconds Duration of the last run.\n"+
+		"# TYPE openmorph_run_duration_seconds gauge\n"+
+		"openmorph_run_duration_seconds %g\n", metrics.Duration.Seconds())
+	_, err := fmt.Fprintf(w, "# HELP openmorph_last_run_timestamp_seconds Unix time the last run finished.\n"+
+		"# TYPE openmorph_last_run_timestamp_seconds gauge\n"+
+		"openmorph_last_run_timestamp_seconds %d\n", metrics.Finished.Unix())
+	return err
+}
+
+// WritePrometheusFile writes the run metrics to path for the node_exporter textfile collector.
+// The file is written next to path and renamed into place so the collector never reads a partial file.
+func WritePrometheusFile(path string, metrics RunMetrics) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create metrics file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := WritePrometheus(file, metrics); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	// CreateTemp uses 0600; the collector usually runs as another user
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	return nil
+}
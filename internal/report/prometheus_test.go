@@ -0,0 +1,88 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+func TestNewRunMetricsSumsSteps(t *testing.T) {
+	shared := &transform.TransformationResults{
+		Changed: []string{"api.yaml"},
+		StepMetrics: []transform.StepMetric{
+			{Step: transform.StepMappings, Duration: time.Second, Changes: 2},
+		},
+	}
+	variant := &transform.TransformationResults{
+		Changed: []string{"fern/api.yaml"},
+		StepMetrics: []transform.StepMetric{
+			{Step: transform.StepVendorExtensions, Duration: time.Second, Changes: 1},
+			{Step: transform.StepMappings, Duration: time.Second, Changes: 3},
+		},
+	}
+
+	metrics := NewRunMetrics(5*time.Second, time.Unix(1700000000, 0), shared, variant, nil)
+	if len(metrics.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %+v", metrics.Steps)
+	}
+	if metrics.Steps[0].Step != transform.StepMappings || metrics.Steps[0].Changes != 5 || metrics.Steps[0].Duration != 2*time.Second {
+		t.Errorf("unexpected mappings metric %+v", metrics.Steps[0])
+	}
+	if metrics.FilesChanged != 2 {
+		t.Errorf("expected 2 files changed, got %d", metrics.FilesChanged)
+	}
+}
+
+func TestWritePrometheusFile(t *testing.T) {
+	metrics := RunMetrics{
+		Steps:        []transform.StepMetric{{Step: transform.StepPagination, Duration: 1500 * time.Millisecond, Changes: 4}},
+		FilesChanged: 1,
+		Duration:     2 * time.Second,
+		Finished:     time.Unix(1700000000, 0),
+	}
+
+	path := filepath.Join(t.TempDir(), "openmorph.prom")
+	if err := WritePrometheusFile(path, metrics); err != nil {
+		t.Fatalf("WritePrometheusFile failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read metrics file: %v", err)
+	}
+
+	for _, want := range []string{
+		"# TYPE openmorph_step_duration_seconds gauge",
+		`openmorph_step_duration_seconds{step="pagination"} 1.5`,
+		`openmorph_step_changes{step="pagination"} 4`,
+		"openmorph_files_changed 1",
+		"openmorph_run_duration_seconds 2",
+		"openmorph_last_run_timestamp_seconds 1700000000",
+	} {
+		if !strings.Contains(string(data), want+"\n") {
+			t.Errorf("metrics file missing %q:\n%s", want, data)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the metrics file to remain, got %d entries", len(entries))
+	}
+}
+
+func TestWritePrometheusNoSteps(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf, RunMetrics{Finished: time.Unix(0, 0)}); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "{step=") {
+		t.Errorf("expected no step samples:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,162 @@
+// Package report renders transformation results in machine-readable formats
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "openmorph"
+	toolURI      = "https://github.com/developerkunal/OpenMorph"
+)
+
+// stepDescriptions describes every step that reports change locations, used as SARIF rules
+var stepDescriptions = map[string]string{
+	transform.StepMappings:         "Key renamed by a mapping",
+	transform.StepPagination:       "Lower-priority pagination removed",
+	transform.StepFlatten:          "Composition flattened",
+	transform.StepVendorExtensions: "Vendor extension added",
+	transform.StepDefaults:         "Default value applied",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// WriteSARIF writes every change location as a SARIF 2.1.0 log with one note-level result per change
+func WriteSARIF(w io.Writer, locations []transform.ChangeLocation, version string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			Version:        version,
+			InformationURI: toolURI,
+			Rules:          sarifRules(locations),
+		}},
+		Results: []sarifResult{},
+	}
+
+	for _, location := range locations {
+		physical := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: artifactURI(location.File)}}
+		if location.Line > 0 {
+			physical.Region = &sarifRegion{StartLine: location.Line, StartColumn: location.Column}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    location.Step,
+			Level:     "note",
+			Message:   sarifMessage{Text: location.Message},
+			Locations: []sarifLocation{{PhysicalLocation: physical}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+// WriteSARIFFile writes a SARIF log to path
+func WriteSARIFFile(path string, locations []transform.ChangeLocation, version string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create SARIF report: %w", err)
+	}
+	defer file.Close()
+
+	if err := WriteSARIF(file, locations, version); err != nil {
+		return fmt.Errorf("failed to write SARIF report: %w", err)
+	}
+	return nil
+}
+
+// sarifRules returns one rule per step that reported a location, in sorted order
+func sarifRules(locations []transform.ChangeLocation) []sarifRule {
+	seen := make(map[string]bool)
+	for _, location := range locations {
+		seen[location.Step] = true
+	}
+
+	rules := make([]sarifRule, 0, len(seen))
+	for step := range seen {
+		description := stepDescriptions[step]
+		if description == "" {
+			description = step
+		}
+		rules = append(rules, sarifRule{ID: step, ShortDescription: sarifMessage{Text: description}})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+// artifactURI returns a file path as a forward-slash URI, relative to the working directory when possible
+func artifactURI(path string) string {
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
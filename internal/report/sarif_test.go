@@ -0,0 +1,49 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	locations := []transform.ChangeLocation{
+		{File: "specs/api.yaml", Line: 12, Column: 7, Step: transform.StepDefaults, Message: "GET /users parameter limit: default = 20 (rule: limits)"},
+		{File: "specs/api.json", Step: transform.StepMappings, Message: "x-foo -> x-bar"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, locations, "1.2.3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope: %+v", log)
+	}
+
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "openmorph" || run.Tool.Driver.Version != "1.2.3" {
+		t.Errorf("unexpected driver: %+v", run.Tool.Driver)
+	}
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != transform.StepDefaults {
+		t.Errorf("expected sorted rules for both steps, got %+v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(run.Results))
+	}
+
+	first := run.Results[0].Locations[0].PhysicalLocation
+	if first.ArtifactLocation.URI != "specs/api.yaml" || first.Region == nil ||
+		first.Region.StartLine != 12 || first.Region.StartColumn != 7 {
+		t.Errorf("unexpected first location: %+v", first)
+	}
+	if run.Results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Error("expected no region for a location without a line")
+	}
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+// WriteValidationSARIF writes the issues of a spec validation as a SARIF 2.1.0 log with one
+// error-level result per issue
+func WriteValidationSARIF(w io.Writer, validation *transform.SpecValidation, version string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			Version:        version,
+			InformationURI: toolURI,
+			Rules:          validationRules(validation.Issues),
+		}},
+		Results: []sarifResult{},
+	}
+
+	for _, issue := range validation.Issues {
+		physical := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: artifactURI(issue.File)}}
+		if issue.Line > 0 {
+			physical.Region = &sarifRegion{StartLine: issue.Line, StartColumn: issue.Column}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    issue.Rule,
+			Level:     "error",
+			Message:   sarifMessage{Text: issue.Message},
+			Locations: []sarifLocation{{PhysicalLocation: physical}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+// validationRules returns one rule per validator rule that reported an issue, in sorted order
+func validationRules(issues []transform.SpecIssue) []sarifRule {
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		seen[issue.Rule] = true
+	}
+
+	rules := make([]sarifRule, 0, len(seen))
+	for rule := range seen {
+		description := transform.RuleDescriptions[rule]
+		if description == "" {
+			description = rule
+		}
+		rules = append(rules, sarifRule{ID: rule, ShortDescription: sarifMessage{Text: description}})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteValidationJUnit writes a spec validation as a JUnit XML report with one test case per
+// validated document, failing with every issue found in it
+func WriteValidationJUnit(w io.Writer, validation *transform.SpecValidation) error {
+	byFile := make(map[string][]transform.SpecIssue)
+	for _, issue := range validation.Issues {
+		byFile[issue.File] = append(byFile[issue.File], issue)
+	}
+
+	suite := junitTestSuite{Name: "openmorph validate", Tests: len(validation.Files)}
+	for _, file := range validation.Files {
+		testCase := junitTestCase{ClassName: "openmorph.validate", Name: artifactURI(file)}
+		if issues := byFile[file]; len(issues) > 0 {
+			lines := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				lines = append(lines, fmt.Sprintf("%s: [%s] %s", issue.Position(), issue.Rule, issue.Message))
+			}
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d validation issue(s)", len(issues)),
+				Type:    "validation",
+				Text:    strings.Join(lines, "\n"),
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	report := junitTestSuites{Name: suite.Name, Tests: suite.Tests, Failures: suite.Failures, Suites: []junitTestSuite{suite}}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ValidationAnnotations returns an error for every issue found by the spec validator, at its position
+func ValidationAnnotations(issues []transform.SpecIssue) []Annotation {
+	annotations := make([]Annotation, 0, len(issues))
+	for _, issue := range issues {
+		annotations = append(annotations, Annotation{
+			Level:   AnnotationError,
+			File:    issue.File,
+			Line:    issue.Line,
+			Column:  issue.Column,
+			Title:   "OpenAPI validation (" + issue.Rule + ")",
+			Message: issue.Message,
+		})
+	}
+	return annotations
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+var testValidation = &transform.SpecValidation{
+	Files: []string{"specs/api.yaml", "specs/other.yaml"},
+	Issues: []transform.SpecIssue{
+		{File: "specs/api.yaml", Line: 3, Column: 1, Rule: transform.RuleStructure, Message: "info.version is required"},
+		{File: "specs/api.yaml", Rule: transform.RuleRefs, Message: "unresolved $ref #/components/schemas/User"},
+	},
+}
+
+func TestWriteValidationSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteValidationSARIF(&buf, testValidation, "1.2.3"); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != transform.RuleRefs {
+		t.Errorf("expected sorted rules for both checks, got %+v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 || run.Results[0].Level != "error" || run.Results[0].Locations[0].PhysicalLocation.Region.StartLine != 3 {
+		t.Errorf("unexpected results: %+v", run.Results)
+	}
+	if run.Results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("expected no region for an issue without a line, got %+v", run.Results[1])
+	}
+}
+
+func TestWriteValidationJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteValidationJUnit(&buf, testValidation); err != nil {
+		t.Fatal(err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, buf.String())
+	}
+	if suites.Tests != 2 || suites.Failures != 1 || len(suites.Suites) != 1 {
+		t.Fatalf("unexpected totals: %+v", suites)
+	}
+	cases := suites.Suites[0].Cases
+	if cases[0].Failure == nil || cases[0].Failure.Message != "2 validation issue(s)" || cases[1].Failure != nil {
+		t.Errorf("expected only the first document to fail, got %+v", cases)
+	}
+	if want := "specs/api.yaml:3:1: [structure] info.version is required\nspecs/api.yaml: [refs] unresolved $ref #/components/schemas/User"; cases[0].Failure.Text != want {
+		t.Errorf("expected failure text %q, got %q", want, cases[0].Failure.Text)
+	}
+}
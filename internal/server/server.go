@@ -0,0 +1,314 @@
+// Package server exposes the transformation pipeline to editors over JSON-RPC 2.0
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/transform"
+)
+
+// Methods served by the server
+const (
+	MethodInitialize = "initialize"
+	MethodTransform  = "transform"
+	MethodShutdown   = "shutdown"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Diagnostic severities
+const (
+	SeverityInformation = "information"
+	SeverityWarning     = "warning"
+)
+
+// stepValidation is the step reported for composition validation findings
+const stepValidation = "validation"
+
+// DefaultMaxBodyBytes is the largest HTTP request body accepted when MaxBodyBytes is not set
+const DefaultMaxBodyBytes int64 = 10 << 20
+
+// Server answers transform requests with the configuration it was started with, unless a request
+// carries its own
+type Server struct {
+	Config          *config.Config
+	VendorProviders []string
+	Version         string
+	MaxBodyBytes    int64     // largest HTTP request body accepted, DefaultMaxBodyBytes when 0
+	Log             io.Writer // receives the warnings steps print while transforming, defaults to os.Stderr
+}
+
+// TransformParams are the parameters of a transform request
+type TransformParams struct {
+	Filepath string         `json:"filepath"`         // path of the document in the editor, decides YAML or JSON
+	Content  string         `json:"content"`          // current buffer contents
+	Config   *config.Config `json:"config,omitempty"` // effective config, replaces the server's config
+}
+
+// TransformResult is the result of a transform request
+type TransformResult struct {
+	Content     string       `json:"content"`
+	Changed     bool         `json:"changed"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// Diagnostic is a change or finding at a position of the submitted document. Line and Column are
+// 1-based; findings without a known position use line 0.
+type Diagnostic struct {
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Source   string `json:"source"`
+	Step     string `json:"step"`
+	Message  string `json:"message"`
+}
+
+// InitializeResult describes the server to a client
+type InitializeResult struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Methods []string `json:"methods"`
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// errShutdown ends ServeStdio after the shutdown response has been written
+var errShutdown = errors.New("shutdown requested")
+
+// New creates a server for the given configuration
+func New(cfg *config.Config, vendorProviders []string, version string) *Server {
+	return &Server{
+		Config:          cfg,
+		VendorProviders: vendorProviders,
+		Version:         version,
+	}
+}
+
+// ServeStdio reads newline-delimited JSON-RPC requests from r and writes one response line per
+// request to w until r is exhausted or a shutdown request is received
+func (s *Server) ServeStdio(r io.Reader, w io.Writer) error {
+	reader := bufio.NewReader(r)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			resp, err := s.handle(line)
+			if resp != nil {
+				if err := encoder.Encode(resp); err != nil {
+					return fmt.Errorf("failed to write response: %v", err)
+				}
+			}
+			if errors.Is(err, errShutdown) {
+				return nil
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read request: %v", readErr)
+		}
+	}
+}
+
+// Handler serves JSON-RPC requests POSTed one per HTTP request
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxBodyBytes())
+		body, err := io.ReadAll(r.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to read request", http.StatusBadRequest)
+			return
+		}
+
+		resp, _ := s.handle(body)
+		if resp == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		_ = encoder.Encode(resp)
+	})
+}
+
+// maxBodyBytes returns the largest HTTP request body the server accepts
+func (s *Server) maxBodyBytes() int64 {
+	if s.MaxBodyBytes <= 0 {
+		return DefaultMaxBodyBytes
+	}
+	return s.MaxBodyBytes
+}
+
+// log returns the writer step warnings go to. Stdout carries the stdio responses, so it is never
+// the default.
+func (s *Server) log() io.Writer {
+	if s.Log == nil {
+		return os.Stderr
+	}
+	return s.Log
+}
+
+// handle dispatches a single request. Notifications (requests without an id) get no response.
+func (s *Server) handle(data []byte) (*response, error) {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "parse error: "+err.Error()), nil
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(idOrNull(req.ID), codeInvalidRequest, "invalid request"), nil
+	}
+
+	var (
+		result interface{}
+		rpcErr *rpcError
+		err    error
+	)
+	switch req.Method {
+	case MethodInitialize:
+		result = InitializeResult{
+			Name:    "openmorph",
+			Version: s.Version,
+			Methods: []string{MethodInitialize, MethodTransform, MethodShutdown},
+		}
+	case MethodTransform:
+		result, rpcErr = s.transform(req.Params)
+	case MethodShutdown:
+		result = struct{}{}
+		err = errShutdown
+	default:
+		rpcErr = &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+
+	if len(req.ID) == 0 {
+		return nil, err
+	}
+	if rpcErr != nil {
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}, err
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}, err
+}
+
+// transform runs the pipeline on the submitted document
+func (s *Server) transform(raw json.RawMessage) (*TransformResult, *rpcError) {
+	var params TransformParams
+	if len(raw) == 0 {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "missing params"}
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	if params.Filepath == "" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "filepath is required"}
+	}
+
+	cfg := s.Config
+	if params.Config != nil {
+		cfg = params.Config
+	}
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	pipeline := transform.NewTransformationPipeline(cfg, s.VendorProviders, false, false, "")
+	pipeline.Log = s.log()
+	content, results, err := pipeline.TransformBytes(params.Filepath, []byte(params.Content))
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+
+	return &TransformResult{
+		Content:     string(content),
+		Changed:     string(content) != params.Content,
+		Diagnostics: diagnostics(params.Content, results),
+	}, nil
+}
+
+// diagnostics reports every change the pipeline made followed by composition validation findings
+// of the submitted document
+func diagnostics(content string, results *transform.TransformationResults) []Diagnostic {
+	diags := []Diagnostic{}
+	for _, location := range results.AllLocations() {
+		diags = append(diags, Diagnostic{
+			Line:     location.Line,
+			Column:   location.Column,
+			Severity: SeverityInformation,
+			Source:   "openmorph",
+			Step:     location.Step,
+			Message:  location.Message,
+		})
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil || len(doc.Content) == 0 {
+		return diags
+	}
+	validation := transform.ValidateCompositionStructures(doc.Content[0], "")
+	for _, validationErr := range validation.Errors {
+		diags = append(diags, Diagnostic{
+			Severity: SeverityWarning,
+			Source:   "openmorph",
+			Step:     stepValidation,
+			Message:  validationErr.Error(),
+		})
+	}
+	return diags
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
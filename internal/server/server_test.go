@@ -0,0 +1,220 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const testSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: bar
+      responses:
+        "200":
+          description: Success
+`
+
+func transformRequest(t *testing.T, id int, params TransformParams) string {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  MethodTransform,
+		"params":  params,
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	return string(data)
+}
+
+func decodeResponses(t *testing.T, output string) []map[string]json.RawMessage {
+	t.Helper()
+	var responses []map[string]json.RawMessage
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("invalid response line %q: %v", line, err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestServeStdio(t *testing.T) {
+	srv := New(&config.Config{Mappings: map[string]string{"x-foo": "x-bar"}}, nil, "test")
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize"}`,
+		`{"jsonrpc":"2.0","method":"initialize"}`,
+		transformRequest(t, 2, TransformParams{Filepath: "specs/api.yaml", Content: testSpec}),
+		`{"jsonrpc":"2.0","id":3,"method":"unknown"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":4,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","id":5,"method":"initialize"}`,
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := srv.ServeStdio(strings.NewReader(input), &out); err != nil {
+		t.Fatalf("ServeStdio failed: %v", err)
+	}
+
+	responses := decodeResponses(t, out.String())
+	if len(responses) != 5 {
+		t.Fatalf("expected 5 responses (notification skipped, nothing after shutdown), got %d:\n%s", len(responses), out.String())
+	}
+
+	var result TransformResult
+	if err := json.Unmarshal(responses[1]["result"], &result); err != nil {
+		t.Fatalf("failed to decode transform result: %v", err)
+	}
+	if !result.Changed || !strings.Contains(result.Content, "x-bar: bar") {
+		t.Errorf("expected transformed content, got changed=%v:\n%s", result.Changed, result.Content)
+	}
+	if len(result.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %+v", result.Diagnostics)
+	}
+	diag := result.Diagnostics[0]
+	if diag.Line != 8 || diag.Column != 7 || diag.Step != "mappings" || diag.Message != "x-foo -> x-bar" {
+		t.Errorf("unexpected diagnostic: %+v", diag)
+	}
+
+	for i, code := range map[int]string{2: "-32601", 3: "-32700"} {
+		if !strings.Contains(string(responses[i]["error"]), code) {
+			t.Errorf("expected error %s in response %d, got %s", code, i, responses[i]["error"])
+		}
+	}
+}
+
+func TestTransformRequestConfig(t *testing.T) {
+	srv := New(&config.Config{}, nil, "test")
+
+	// Without a mapping the document is returned unchanged
+	resp, _ := srv.handle([]byte(transformRequest(t, 1, TransformParams{Filepath: "api.yaml", Content: testSpec})))
+	result, ok := resp.Result.(*TransformResult)
+	if !ok || result.Changed || result.Content != testSpec {
+		t.Fatalf("expected unchanged document, got %+v", resp.Result)
+	}
+
+	// A request config replaces the server's config
+	params := TransformParams{
+		Filepath: "api",
+		Content:  `{"openapi":"3.0.0","info":{"title":"T","version":"1"},"paths":{"/a":{"get":{"x-foo":1}}}}`,
+		Config:   &config.Config{Mappings: map[string]string{"x-foo": "x-bar"}},
+	}
+	resp, _ = srv.handle([]byte(transformRequest(t, 2, params)))
+	result, ok = resp.Result.(*TransformResult)
+	if !ok || !strings.Contains(result.Content, `"x-bar":1`) {
+		t.Fatalf("expected JSON content with x-bar, got %+v", resp.Result)
+	}
+
+	resp, _ = srv.handle([]byte(transformRequest(t, 3, TransformParams{Content: testSpec})))
+	if resp.Error == nil || resp.Error.Code != codeInvalidParams {
+		t.Errorf("expected invalid params error without filepath, got %+v", resp)
+	}
+}
+
+func TestTransformDiagnosticsIncludeValidation(t *testing.T) {
+	srv := New(&config.Config{}, nil, "test")
+	spec := testSpec + `components:
+  schemas:
+    Pet:
+      oneOf: []
+`
+	resp, _ := srv.handle([]byte(transformRequest(t, 1, TransformParams{Filepath: "api.yaml", Content: spec})))
+	result, ok := resp.Result.(*TransformResult)
+	if !ok {
+		t.Fatalf("expected transform result, got %+v", resp)
+	}
+	found := false
+	for _, diag := range result.Diagnostics {
+		if diag.Step == stepValidation && diag.Severity == SeverityWarning {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a validation warning, got %+v", result.Diagnostics)
+	}
+}
+
+func TestTransformWarningsGoToLog(t *testing.T) {
+	var log bytes.Buffer
+	srv := New(&config.Config{FlattenResponses: true}, nil, "test")
+	srv.Log = &log
+	spec := testSpec + `components:
+  schemas:
+    Pet:
+      oneOf: []
+`
+	if resp, _ := srv.handle([]byte(transformRequest(t, 1, TransformParams{Filepath: "api.yaml", Content: spec}))); resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	if !strings.Contains(log.String(), "Validation warnings for") {
+		t.Errorf("expected the flatten warnings in the log, got %q", log.String())
+	}
+}
+
+func TestHandler(t *testing.T) {
+	srv := httptest.NewServer(New(&config.Config{}, nil, "test").Handler())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":"a","method":"initialize"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		ID     string           `json:"id"`
+		Result InitializeResult `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.ID != "a" || body.Result.Name != "openmorph" || len(body.Result.Methods) != 3 {
+		t.Errorf("unexpected initialize response: %+v", body)
+	}
+
+	getResp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	getResp.Body.Close()
+	if getResp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", getResp.StatusCode)
+	}
+}
+
+func TestHandlerBodyLimit(t *testing.T) {
+	handler := New(&config.Config{}, nil, "test")
+	handler.MaxBodyBytes = 64
+	srv := httptest.NewServer(handler.Handler())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL, "application/json", strings.NewReader(transformRequest(t, 1, TransformParams{Filepath: "api.yaml", Content: testSpec})))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for a body over the limit, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(srv.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 for a body within the limit, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,150 @@
+// Package telemetry instruments the pipeline with OpenTelemetry spans. Spans go to the global
+// tracer provider, which records nothing until Setup installs an exporter.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName  = "github.com/developerkunal/OpenMorph"
+	serviceName = "openmorph"
+)
+
+// Span attribute keys
+const (
+	AttrStep    = attribute.Key("openmorph.step")
+	AttrFile    = attribute.Key("openmorph.file")
+	AttrChanged = attribute.Key("openmorph.changed")
+	AttrChanges = attribute.Key("openmorph.changes")
+	AttrInput   = attribute.Key("openmorph.input")
+)
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// StartPipeline starts the root span of a pipeline run over input
+func StartPipeline(ctx context.Context, input string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "openmorph.pipeline", trace.WithAttributes(AttrInput.String(input)))
+}
+
+// StartStep starts the span of one pipeline step
+func StartStep(ctx context.Context, step string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "openmorph.step "+step, trace.WithAttributes(AttrStep.String(step)))
+}
+
+// StartFile starts the span of one file processed by a step
+func StartFile(ctx context.Context, step, path string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "openmorph.file", trace.WithAttributes(AttrStep.String(step), AttrFile.String(path)))
+}
+
+// EndStep records the step's change count and error and ends its span
+func EndStep(span trace.Span, changes int, err error) {
+	span.SetAttributes(AttrChanges.Int(changes))
+	End(span, err)
+}
+
+// EndFile records whether the file changed and ends its span
+func EndFile(span trace.Span, changed bool, err error) {
+	span.SetAttributes(AttrChanged.Bool(changed))
+	End(span, err)
+}
+
+// End records err, if any, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Options selects where spans are exported
+type Options struct {
+	TraceFile string // write spans as JSON to this file ("-" for stderr)
+	OTLP      bool   // export spans over OTLP/HTTP, configured by the standard OTEL_EXPORTER_OTLP_* variables
+	Version   string
+}
+
+// Enabled reports whether any exporter is selected
+func (o Options) Enabled() bool {
+	return o.TraceFile != "" || o.OTLP
+}
+
+// OTLPConfigured reports whether the standard OTLP endpoint variables are set
+func OTLPConfigured() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup installs a global tracer provider with the selected exporters. The returned shutdown
+// function flushes pending spans and must be called before the process exits.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if !opts.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var (
+		providerOpts []sdktrace.TracerProviderOption
+		closers      []io.Closer
+	)
+	if opts.TraceFile != "" {
+		var w io.Writer = os.Stderr
+		if opts.TraceFile != "-" {
+			file, err := os.Create(opts.TraceFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create trace file: %w", err)
+			}
+			closers = append(closers, file)
+			w = file
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create trace file exporter: %w", err)
+		}
+		// Export synchronously so the file is complete even if the run exits early
+		providerOpts = append(providerOpts, sdktrace.WithSyncer(exporter))
+	}
+	if opts.OTLP {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		providerOpts = append(providerOpts, sdktrace.WithBatcher(exporter))
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(opts.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telemetry resource: %w", err)
+	}
+	providerOpts = append(providerOpts, sdktrace.WithResource(res))
+
+	provider := sdktrace.NewTracerProvider(providerOpts...)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		for _, closer := range closers {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,94 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpansNestPipelineStepFile(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx, pipeline := StartPipeline(context.Background(), "specs")
+	ctx, step := StartStep(ctx, "pagination")
+	_, file := StartFile(ctx, "pagination", "specs/api.yaml")
+	EndFile(file, true, nil)
+	EndStep(step, 3, errors.New("boom"))
+	End(pipeline, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	fileSpan, stepSpan, pipelineSpan := spans[0], spans[1], spans[2]
+
+	if fileSpan.Parent.SpanID() != stepSpan.SpanContext.SpanID() {
+		t.Error("file span should be a child of the step span")
+	}
+	if stepSpan.Parent.SpanID() != pipelineSpan.SpanContext.SpanID() {
+		t.Error("step span should be a child of the pipeline span")
+	}
+	if stepSpan.Name != "openmorph.step pagination" {
+		t.Errorf("unexpected step span name %q", stepSpan.Name)
+	}
+	if stepSpan.Status.Code != codes.Error {
+		t.Errorf("expected step span to record the error, got status %v", stepSpan.Status)
+	}
+
+	attrs := make(map[string]string)
+	for _, attr := range fileSpan.Attributes {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attrs["openmorph.file"] != "specs/api.yaml" || attrs["openmorph.changed"] != "true" {
+		t.Errorf("unexpected file span attributes %v", attrs)
+	}
+}
+
+func TestSetupTraceFile(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	shutdown, err := Setup(context.Background(), Options{TraceFile: path, Version: "test"})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	_, span := StartStep(context.Background(), "flatten")
+	EndStep(span, 1, nil)
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read trace file: %v", err)
+	}
+	for _, want := range []string{`"Name":"openmorph.step flatten"`, `"openmorph"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("trace file missing %s:\n%s", want, data)
+		}
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown failed: %v", err)
+	}
+}
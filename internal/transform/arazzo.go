@@ -0,0 +1,350 @@
+package transform
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const sourceDescriptionsPrefix = "$sourceDescriptions."
+
+// ArazzoResult represents the result of keeping Arazzo workflow documents in sync with the
+// OpenAPI documents they reference
+type ArazzoResult struct {
+	Changed           bool
+	Documents         []string            // detected Arazzo documents (OpenAPI-only steps skip them)
+	ProcessedFiles    []string            // Arazzo documents whose references were updated
+	UpdatedReferences map[string][]string // file -> list of updated step references
+}
+
+// operationRef identifies one operation of an OpenAPI document
+type operationRef struct {
+	Path        string
+	Method      string
+	OperationID string
+}
+
+// operationRenames holds the operationId and path renames detected in one OpenAPI document
+type operationRenames struct {
+	operationIDs map[string]string // old operationId -> new operationId
+	paths        map[string]string // old path -> new path
+}
+
+// isArazzoDocument checks if the document is an Arazzo workflow specification
+func isArazzoDocument(root *yaml.Node) bool {
+	return root != nil && root.Kind == yaml.MappingNode && getNodeValue(root, "arazzo") != nil
+}
+
+// findArazzoDocuments returns every Arazzo document below dir
+func findArazzoDocuments(dir string) ([]string, error) {
+	var documents []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (!IsYAML(path) && !IsJSON(path)) {
+			return nil
+		}
+
+		doc, err := loadAndParseDocument(path)
+		if err != nil {
+			return nil // Unparseable files are reported by the steps that process them
+		}
+		if isArazzoDocument(getRootNode(doc)) {
+			documents = append(documents, path)
+		}
+		return nil
+	})
+	return documents, err
+}
+
+// snapshotOperations records the operations of every OpenAPI document below dir, keyed by absolute path
+func snapshotOperations(dir string) (map[string][]operationRef, error) {
+	snapshot := make(map[string][]operationRef)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (!IsYAML(path) && !IsJSON(path)) {
+			return nil
+		}
+
+		doc, err := loadAndParseDocument(path)
+		if err != nil {
+			return nil
+		}
+		root := getRootNode(doc)
+		if !isOpenAPIDocument(root) {
+			return nil
+		}
+
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		snapshot[absPath] = collectOperationRefs(root)
+		return nil
+	})
+	return snapshot, err
+}
+
+// collectOperationRefs lists the operations of a document in document order
+func collectOperationRefs(root *yaml.Node) []operationRef {
+	var operations []operationRef
+	paths := getNodeValue(root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return operations
+	}
+
+	for i := 0; i < len(paths.Content); i += 2 {
+		path := paths.Content[i].Value
+		pathItem := paths.Content[i+1]
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			method := pathItem.Content[j].Value
+			if !isHTTPMethod(method) {
+				continue
+			}
+			operations = append(operations, operationRef{
+				Path:        path,
+				Method:      strings.ToLower(method),
+				OperationID: getStringValue(pathItem.Content[j+1], "operationId"),
+			})
+		}
+	}
+	return operations
+}
+
+// diffOperationSnapshots detects operationId and path renames between two snapshots. Operations
+// are matched by operationId (detecting path renames) or by path and method (detecting
+// operationId renames); operations where both changed cannot be matched and are ignored.
+func diffOperationSnapshots(before, after map[string][]operationRef) map[string]*operationRenames {
+	renames := make(map[string]*operationRenames)
+
+	for file, beforeOps := range before {
+		afterOps, ok := after[file]
+		if !ok {
+			continue
+		}
+
+		byID := make(map[string]operationRef)
+		byPathMethod := make(map[string]operationRef)
+		for _, op := range afterOps {
+			if op.OperationID != "" {
+				byID[op.OperationID] = op
+			}
+			byPathMethod[op.Method+" "+op.Path] = op
+		}
+
+		fileRenames := &operationRenames{operationIDs: make(map[string]string), paths: make(map[string]string)}
+		for _, op := range beforeOps {
+			if match, ok := byID[op.OperationID]; ok && op.OperationID != "" {
+				if match.Path != op.Path && match.Method == op.Method {
+					fileRenames.paths[op.Path] = match.Path
+				}
+				continue
+			}
+			if match, ok := byPathMethod[op.Method+" "+op.Path]; ok && op.OperationID != "" && match.OperationID != "" &&
+				match.OperationID != op.OperationID {
+				fileRenames.operationIDs[op.OperationID] = match.OperationID
+			}
+		}
+
+		if len(fileRenames.operationIDs) > 0 || len(fileRenames.paths) > 0 {
+			renames[file] = fileRenames
+		}
+	}
+	return renames
+}
+
+// SyncArazzoDocuments updates operationId and operationPath references in Arazzo documents that
+// point at renamed operations of the OpenAPI documents they describe
+func SyncArazzoDocuments(documents []string, renames map[string]*operationRenames, dryRun bool) (*ArazzoResult, error) {
+	result := &ArazzoResult{
+		Documents:         documents,
+		ProcessedFiles:    []string{},
+		UpdatedReferences: make(map[string][]string),
+	}
+	if len(renames) == 0 {
+		return result, nil
+	}
+
+	for _, path := range documents {
+		changed, err := syncArazzoDocument(path, renames, dryRun, result)
+		if err != nil {
+			return result, fmt.Errorf("error processing %s: %w", path, err)
+		}
+		if changed {
+			result.Changed = true
+			result.ProcessedFiles = append(result.ProcessedFiles, path)
+		}
+	}
+	return result, nil
+}
+
+// syncArazzoDocument updates the step references of one Arazzo document
+func syncArazzoDocument(path string, renames map[string]*operationRenames, dryRun bool, result *ArazzoResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+	root := getRootNode(doc)
+
+	sources := resolveArazzoSources(root, filepath.Dir(path), renames)
+	if len(sources) == 0 {
+		return false, nil
+	}
+
+	workflows := getNodeValue(root, "workflows")
+	if workflows == nil || workflows.Kind != yaml.SequenceNode {
+		return false, nil
+	}
+
+	changed := false
+	for _, workflow := range workflows.Content {
+		workflowID := getStringValue(workflow, "workflowId")
+		steps := getNodeValue(workflow, "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, step := range steps.Content {
+			context := workflowID + "." + getStringValue(step, "stepId")
+			if syncStepOperationID(step, sources, context, path, result) {
+				changed = true
+			}
+			if syncStepOperationPath(step, sources, context, path, result) {
+				changed = true
+			}
+		}
+	}
+
+	if !changed || dryRun {
+		return changed, nil
+	}
+	return writeModifiedDocument(doc, path)
+}
+
+// arazzoSource is an OpenAPI source description whose document had operations renamed
+type arazzoSource struct {
+	name    string
+	renames *operationRenames
+}
+
+// resolveArazzoSources returns the OpenAPI source descriptions that point at renamed documents,
+// in declaration order
+func resolveArazzoSources(root *yaml.Node, baseDir string, renames map[string]*operationRenames) []arazzoSource {
+	var sources []arazzoSource
+	descriptions := getNodeValue(root, "sourceDescriptions")
+	if descriptions == nil || descriptions.Kind != yaml.SequenceNode {
+		return sources
+	}
+
+	for _, description := range descriptions.Content {
+		if sourceType := getStringValue(description, "type"); sourceType != "" && sourceType != "openapi" {
+			continue
+		}
+		url := getStringValue(description, "url")
+		if url == "" || strings.Contains(url, "://") {
+			continue
+		}
+		absPath, err := filepath.Abs(filepath.Join(baseDir, filepath.FromSlash(url)))
+		if err != nil {
+			continue
+		}
+		if fileRenames, ok := renames[absPath]; ok {
+			sources = append(sources, arazzoSource{name: getStringValue(description, "name"), renames: fileRenames})
+		}
+	}
+	return sources
+}
+
+// syncStepOperationID updates a step's operationId, which is either bare or qualified as
+// $sourceDescriptions.<name>.<operationId>
+func syncStepOperationID(step *yaml.Node, sources []arazzoSource, context, path string, result *ArazzoResult) bool {
+	node := getNodeValue(step, "operationId")
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return false
+	}
+
+	value := node.Value
+	if rest, ok := strings.CutPrefix(value, sourceDescriptionsPrefix); ok {
+		name, operationID, found := strings.Cut(rest, ".")
+		if !found {
+			return false
+		}
+		for _, source := range sources {
+			if source.name == name {
+				if newID, ok := source.renames.operationIDs[operationID]; ok {
+					node.Value = sourceDescriptionsPrefix + name + "." + newID
+					addArazzoUpdate(result, path, context, "operationId", value, node.Value)
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for _, source := range sources {
+		if newID, ok := source.renames.operationIDs[value]; ok {
+			node.Value = newID
+			addArazzoUpdate(result, path, context, "operationId", value, newID)
+			return true
+		}
+	}
+	return false
+}
+
+// syncStepOperationPath updates a step's operationPath, e.g.
+// {$sourceDescriptions.petstore.url}#/paths/~1pets~1{petId}/get
+func syncStepOperationPath(step *yaml.Node, sources []arazzoSource, context, path string, result *ArazzoResult) bool {
+	node := getNodeValue(step, "operationPath")
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return false
+	}
+
+	value := node.Value
+	source, pointer, found := strings.Cut(value, "#")
+	if !found {
+		return false
+	}
+	rest, ok := strings.CutPrefix(pointer, "/paths/")
+	if !ok {
+		return false
+	}
+	escapedPath, method, _ := strings.Cut(rest, "/")
+	apiPath := unescapeJSONPointer(escapedPath)
+
+	for _, candidate := range sources {
+		if !strings.Contains(source, sourceDescriptionsPrefix+candidate.name+".") {
+			continue
+		}
+		newPath, ok := candidate.renames.paths[apiPath]
+		if !ok {
+			return false
+		}
+		node.Value = source + "#/paths/" + escapeJSONPointer(newPath)
+		if method != "" {
+			node.Value += "/" + method
+		}
+		addArazzoUpdate(result, path, context, "operationPath", value, node.Value)
+		return true
+	}
+	return false
+}
+
+// escapeJSONPointer escapes a JSON pointer reference token
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// unescapeJSONPointer unescapes a JSON pointer reference token
+func unescapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+func addArazzoUpdate(result *ArazzoResult, filePath, context, field, oldValue, newValue string) {
+	result.UpdatedReferences[filePath] = append(result.UpdatedReferences[filePath],
+		fmt.Sprintf("%s %s: %s -> %s", context, field, oldValue, newValue))
+}
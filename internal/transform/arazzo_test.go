@@ -0,0 +1,165 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const arazzoTestSpec = `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  /v1/pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: Success
+  /v1/pets/{petId}:
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          description: Success
+`
+
+const arazzoTestWorkflow = `arazzo: 1.0.0
+info:
+  title: Adopt a pet
+  version: 1.0.0
+sourceDescriptions:
+  - name: petsApi
+    url: ./pets.yaml
+    type: openapi
+workflows:
+  - workflowId: adopt
+    steps:
+      - stepId: list
+        operationId: $sourceDescriptions.petsApi.listPets
+      - stepId: fetch
+        operationPath: '{$sourceDescriptions.petsApi.url}#/paths/~1v1~1pets~1{petId}/get'
+`
+
+func TestIsArazzoDocument(t *testing.T) {
+	arazzo := parseYAMLToNode(t, arazzoTestWorkflow)
+	if !isArazzoDocument(arazzo) {
+		t.Error("expected Arazzo document to be detected")
+	}
+	if isOpenAPIDocument(arazzo) {
+		t.Error("expected Arazzo document not to be treated as OpenAPI")
+	}
+	if isArazzoDocument(parseYAMLToNode(t, arazzoTestSpec)) {
+		t.Error("expected OpenAPI document not to be detected as Arazzo")
+	}
+}
+
+func TestDiffOperationSnapshots(t *testing.T) {
+	before := map[string][]operationRef{
+		"api.yaml": {
+			{Path: "/v1/pets", Method: "get", OperationID: "listPets"},
+			{Path: "/v1/pets", Method: "post", OperationID: "createPet"},
+			{Path: "/v1/owners", Method: "get", OperationID: "listOwners"},
+		},
+	}
+	after := map[string][]operationRef{
+		"api.yaml": {
+			{Path: "/pets", Method: "get", OperationID: "listPets"},
+			{Path: "/pets", Method: "post", OperationID: "pets_create"},
+			{Path: "/v1/owners", Method: "get", OperationID: "owners_list"},
+		},
+	}
+
+	renames := diffOperationSnapshots(before, after)["api.yaml"]
+	if renames == nil {
+		t.Fatal("expected renames for api.yaml")
+	}
+	if renames.paths["/v1/pets"] != "/pets" {
+		t.Errorf("expected path rename /v1/pets -> /pets, got %v", renames.paths)
+	}
+	if renames.operationIDs["listOwners"] != "owners_list" {
+		t.Errorf("expected operationId rename listOwners -> owners_list, got %v", renames.operationIDs)
+	}
+	if _, ok := renames.operationIDs["createPet"]; ok {
+		t.Error("expected operation with both path and operationId changed to be ignored")
+	}
+}
+
+func TestSyncArazzoDocuments(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "pets.yaml")
+	workflowPath := filepath.Join(dir, "adopt.arazzo.yaml")
+	if err := os.WriteFile(workflowPath, []byte(arazzoTestWorkflow), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	absSpec, err := filepath.Abs(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renames := map[string]*operationRenames{
+		absSpec: {
+			operationIDs: map[string]string{"listPets": "pets_list"},
+			paths:        map[string]string{"/v1/pets/{petId}": "/pets/{petId}"},
+		},
+	}
+
+	result, err := SyncArazzoDocuments([]string{workflowPath}, renames, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Changed || len(result.UpdatedReferences[workflowPath]) != 2 {
+		t.Fatalf("expected 2 updated references, got %v", result.UpdatedReferences)
+	}
+
+	data, err := os.ReadFile(workflowPath)
+	if err != nil {
+		t.Fatalf("failed to read workflow: %v", err)
+	}
+	output := string(data)
+	for _, want := range []string{
+		"operationId: $sourceDescriptions.petsApi.pets_list",
+		"operationPath: '{$sourceDescriptions.petsApi.url}#/paths/~1pets~1{petId}/get'",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected workflow to contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestPipelineSyncsArazzoOnPathMapping(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "pets.yaml")
+	workflowPath := filepath.Join(dir, "adopt.arazzo.yaml")
+	if err := os.WriteFile(specPath, []byte(arazzoTestSpec), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(workflowPath, []byte(arazzoTestWorkflow), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	cfg := &config.Config{Mappings: map[string]string{"/v1/pets/{petId}": "/pets/{petId}"}}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if results.ArazzoResult == nil || len(results.ArazzoResult.Documents) != 1 {
+		t.Fatalf("expected one detected Arazzo document, got %+v", results.ArazzoResult)
+	}
+	if !results.ArazzoResult.Changed {
+		t.Fatal("expected Arazzo workflow to be updated")
+	}
+
+	data, err := os.ReadFile(workflowPath)
+	if err != nil {
+		t.Fatalf("failed to read workflow: %v", err)
+	}
+	if !strings.Contains(string(data), "#/paths/~1pets~1{petId}/get") {
+		t.Errorf("expected operationPath to follow the renamed path\n%s", data)
+	}
+}
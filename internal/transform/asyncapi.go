@@ -0,0 +1,268 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// asyncAPIChannelOperations are the AsyncAPI 2.x operation keys of a channel item
+var asyncAPIChannelOperations = []string{"publish", "subscribe"}
+
+// isAsyncAPIDocument checks if the document is an AsyncAPI specification
+func isAsyncAPIDocument(root *yaml.Node) bool {
+	return root != nil && root.Kind == yaml.MappingNode && getNodeValue(root, "asyncapi") != nil
+}
+
+// stripChannels removes internal channels, AsyncAPI 2.x publish/subscribe operations and AsyncAPI 3.x
+// channel messages, and strips nested internal content from what remains
+func (s *internalStripper) stripChannels(root *yaml.Node) {
+	if !isAsyncAPIDocument(root) {
+		return
+	}
+	channels := getNodeValue(root, "channels")
+	if channels == nil || channels.Kind != yaml.MappingNode {
+		return
+	}
+
+	var kept []*yaml.Node
+	for i := 0; i < len(channels.Content); i += 2 {
+		name := channels.Content[i].Value
+		channel := channels.Content[i+1]
+		if s.isInternal(channel) {
+			s.removedChannels[name] = true
+			s.record("channel %s", name)
+			continue
+		}
+		if channel.Kind != yaml.MappingNode {
+			kept = append(kept, channels.Content[i], channel)
+			continue
+		}
+
+		var content []*yaml.Node
+		for j := 0; j < len(channel.Content); j += 2 {
+			key := channel.Content[j].Value
+			value := channel.Content[j+1]
+			switch {
+			case contains(asyncAPIChannelOperations, key) && s.isInternal(value):
+				s.record("operation %s %s", key, name)
+				continue
+			case contains(asyncAPIChannelOperations, key):
+				s.stripOperationTags(value)
+			case key == "messages" && value.Kind == yaml.MappingNode:
+				s.stripChannelMessages(value, name)
+			}
+			s.stripNested(value, "channel "+name)
+			content = append(content, channel.Content[j], value)
+		}
+		channel.Content = content
+		kept = append(kept, channels.Content[i], channel)
+	}
+	channels.Content = kept
+}
+
+// stripChannelMessages removes internal messages from an AsyncAPI 3.x channel
+func (s *internalStripper) stripChannelMessages(messages *yaml.Node, channel string) {
+	var kept []*yaml.Node
+	for i := 0; i < len(messages.Content); i += 2 {
+		if s.isInternal(messages.Content[i+1]) {
+			s.record("message %s (channel %s)", messages.Content[i].Value, channel)
+			continue
+		}
+		kept = append(kept, messages.Content[i], messages.Content[i+1])
+	}
+	messages.Content = kept
+}
+
+// stripAsyncOperations removes internal AsyncAPI 3.x operations and operations whose channel was removed
+func (s *internalStripper) stripAsyncOperations(root *yaml.Node) {
+	if !isAsyncAPIDocument(root) {
+		return
+	}
+	operations := getNodeValue(root, "operations")
+	if operations == nil || operations.Kind != yaml.MappingNode {
+		return
+	}
+
+	var kept []*yaml.Node
+	for i := 0; i < len(operations.Content); i += 2 {
+		name := operations.Content[i].Value
+		operation := operations.Content[i+1]
+		if s.isInternal(operation) {
+			s.record("operation %s", name)
+			continue
+		}
+		channelRef := getStringValue(getNodeValue(operation, "channel"), "$ref")
+		if channel, ok := strings.CutPrefix(channelRef, "#/channels/"); ok && s.removedChannels[unescapeJSONPointer(channel)] {
+			s.record("operation %s (channel %s removed)", name, unescapeJSONPointer(channel))
+			continue
+		}
+		s.stripOperationTags(operation)
+		s.stripNested(operation, "operation "+name)
+		kept = append(kept, operations.Content[i], operation)
+	}
+	operations.Content = kept
+}
+
+// processAsyncAPIDefaults applies default value rules to an AsyncAPI document: parameter rules
+// target channel parameters, message rules target message payloads and component rules target
+// components/schemas. Request body and response rules do not apply.
+func processAsyncAPIDefaults(doc, root *yaml.Node, path string, opts DefaultsOptions, result *DefaultsResult) (bool, error) {
+	changed := false
+
+	for _, ruleEntry := range getSortedDefaultRules(opts.DefaultValues.Rules) {
+		ruleName := ruleEntry.Name
+		rule := ruleEntry.Rule
+
+		switch rule.Target.Location {
+		case "parameter":
+			if processChannelParameterDefaults(root, ruleName, rule, path, result) {
+				changed = true
+			}
+		case "message":
+			if processMessageDefaults(root, ruleName, rule, path, result) {
+				changed = true
+			}
+		case "component":
+			if processComponentDefaults(root, ruleName, rule, path, result) {
+				changed = true
+			}
+		}
+	}
+
+	if changed {
+		return writeStepDocument(opts.Options, doc, path)
+	}
+
+	return false, nil
+}
+
+// processChannelParameterDefaults processes default values for channel parameters, matching
+// path_patterns against channel names
+func processChannelParameterDefaults(root *yaml.Node, ruleName string, rule config.DefaultRule, filePath string, result *DefaultsResult) bool {
+	channels := getNodeValue(root, "channels")
+	if channels == nil || channels.Kind != yaml.MappingNode {
+		return false
+	}
+
+	changed := false
+	for i := 0; i < len(channels.Content); i += 2 {
+		channelName := channels.Content[i].Value
+		if !matchesPathPattern(channelName, rule.Condition.PathPatterns) {
+			continue
+		}
+
+		parameters := getNodeValue(channels.Content[i+1], "parameters")
+		if parameters == nil || parameters.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j < len(parameters.Content); j += 2 {
+			context := fmt.Sprintf("channel %s parameter %s", channelName, parameters.Content[j].Value)
+			if applyChannelParameterDefault(parameters.Content[j+1], parameters.Content[j].Value, context, ruleName, rule, filePath, result) {
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+// applyChannelParameterDefault applies a default to a channel parameter. AsyncAPI 2.x parameters
+// carry a schema; AsyncAPI 3.x parameters are always strings and hold default and enum directly.
+func applyChannelParameterDefault(param *yaml.Node, name, context, ruleName string, rule config.DefaultRule, filePath string, result *DefaultsResult) bool {
+	if param.Kind != yaml.MappingNode || getNodeValue(param, "$ref") != nil {
+		return false
+	}
+
+	target := getNodeValue(param, "schema")
+	if target == nil {
+		if rule.Condition.Type != "" && rule.Condition.Type != "string" {
+			addSkippedTarget(result, filePath, context, SkipTypeMismatch,
+				fmt.Sprintf("type 'string' doesn't match rule condition '%s'", rule.Condition.Type))
+			return false
+		}
+		target = param
+	} else if !shouldApplyDefaultToProperty(target, name, rule, context, filePath, result) {
+		return false
+	}
+
+	if target == param {
+		if getNodeValue(param, "default") != nil {
+			addSkippedTarget(result, filePath, context, SkipDefaultExists, "default already exists")
+			return false
+		}
+		if rule.Condition.PropertyName != "" && !matchesPropertyName(name, rule.Condition.PropertyName) {
+			addSkippedTarget(result, filePath, context, SkipNameMismatch, fmt.Sprintf("property name doesn't match pattern '%s'", rule.Condition.PropertyName))
+			return false
+		}
+		if rule.Condition.HasEnum && getNodeValue(param, "enum") == nil {
+			addSkippedTarget(result, filePath, context, SkipMissingEnum, "no enum found but required by rule")
+			return false
+		}
+	}
+
+	defaultValue := determineDefaultValue(rule, target, param)
+	if defaultValue == nil {
+		return false
+	}
+	return addDefaultToSchema(target, defaultValue, context, name, ruleName, filePath, result)
+}
+
+// processMessageDefaults processes default values for message payload schemas in components/messages
+// and in messages defined inline on channels (AsyncAPI 3.x) or their operations (AsyncAPI 2.x)
+func processMessageDefaults(root *yaml.Node, ruleName string, rule config.DefaultRule, filePath string, result *DefaultsResult) bool {
+	changed := false
+
+	messages := getNodeValue(getNodeValue(root, "components"), "messages")
+	if messages != nil && messages.Kind == yaml.MappingNode {
+		for i := 0; i < len(messages.Content); i += 2 {
+			if processPayloadDefaults(messages.Content[i+1], "message "+messages.Content[i].Value, ruleName, rule, filePath, result) {
+				changed = true
+			}
+		}
+	}
+
+	channels := getNodeValue(root, "channels")
+	if channels == nil || channels.Kind != yaml.MappingNode {
+		return changed
+	}
+	for i := 0; i < len(channels.Content); i += 2 {
+		channelName := channels.Content[i].Value
+		channel := channels.Content[i+1]
+		if !matchesPathPattern(channelName, rule.Condition.PathPatterns) {
+			continue
+		}
+
+		for _, operation := range asyncAPIChannelOperations {
+			message := getNodeValue(getNodeValue(channel, operation), "message")
+			if processPayloadDefaults(message, fmt.Sprintf("channel %s %s message", channelName, operation), ruleName, rule, filePath, result) {
+				changed = true
+			}
+		}
+
+		channelMessages := getNodeValue(channel, "messages")
+		if channelMessages == nil || channelMessages.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j < len(channelMessages.Content); j += 2 {
+			context := fmt.Sprintf("channel %s message %s", channelName, channelMessages.Content[j].Value)
+			if processPayloadDefaults(channelMessages.Content[j+1], context, ruleName, rule, filePath, result) {
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+// processPayloadDefaults processes the payload schema of a message
+func processPayloadDefaults(message *yaml.Node, context, ruleName string, rule config.DefaultRule, filePath string, result *DefaultsResult) bool {
+	payload := getNodeValue(message, "payload")
+	if payload == nil {
+		return false
+	}
+	return processSchemaDefaults(payload, nil, context+" payload", ruleName, rule, filePath, result)
+}
@@ -0,0 +1,217 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const asyncAPI2TestSpec = `asyncapi: 2.6.0
+info:
+  title: Events
+  version: 1.0.0
+channels:
+  user/{userId}/signedup:
+    parameters:
+      userId:
+        schema:
+          type: string
+    subscribe:
+      message:
+        $ref: '#/components/messages/UserSignedUp'
+    publish:
+      x-internal: true
+      message:
+        $ref: '#/components/messages/AuditEvent'
+  debug/trace:
+    x-internal: true
+    subscribe:
+      message:
+        payload:
+          type: object
+components:
+  messages:
+    UserSignedUp:
+      payload:
+        type: object
+        properties:
+          locale:
+            type: string
+          internalScore:
+            type: number
+            x-internal: true
+    AuditEvent:
+      payload:
+        type: object
+`
+
+const asyncAPI3TestSpec = `asyncapi: 3.0.0
+info:
+  title: Events
+  version: 1.0.0
+channels:
+  userSignedUp:
+    address: user/{userId}/signedup
+    parameters:
+      userId:
+        description: The user
+    messages:
+      signedUp:
+        payload:
+          type: object
+          properties:
+            locale:
+              type: string
+      debug:
+        x-internal: true
+        payload:
+          type: object
+  trace:
+    x-internal: true
+    address: debug/trace
+operations:
+  onSignedUp:
+    action: receive
+    channel:
+      $ref: '#/channels/userSignedUp'
+  onTrace:
+    action: receive
+    channel:
+      $ref: '#/channels/trace'
+`
+
+func writeAsyncAPITestFile(t *testing.T, spec string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.yaml")
+	if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	return dir, path
+}
+
+func readAsyncAPITestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	return string(data)
+}
+
+func TestIsAsyncAPIDocument(t *testing.T) {
+	if !isAsyncAPIDocument(parseYAMLToNode(t, asyncAPI2TestSpec)) {
+		t.Error("expected AsyncAPI document to be detected")
+	}
+	if isAsyncAPIDocument(parseYAMLToNode(t, "openapi: 3.0.0\npaths: {}\n")) {
+		t.Error("expected OpenAPI document not to be detected as AsyncAPI")
+	}
+}
+
+func TestStripInternalAsyncAPI(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		asyncAPI    bool
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "disabled skips AsyncAPI",
+			spec:     asyncAPI2TestSpec,
+			contains: []string{"debug/trace", "internalScore"},
+		},
+		{
+			name:        "AsyncAPI 2",
+			spec:        asyncAPI2TestSpec,
+			asyncAPI:    true,
+			contains:    []string{"subscribe:", "locale:"},
+			notContains: []string{"debug/trace", "publish:", "internalScore", "AuditEvent"},
+		},
+		{
+			name:        "AsyncAPI 3",
+			spec:        asyncAPI3TestSpec,
+			asyncAPI:    true,
+			contains:    []string{"signedUp:", "onSignedUp:"},
+			notContains: []string{"trace", "debug:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, path := writeAsyncAPITestFile(t, tt.spec)
+
+			opts := InternalOptions{StripInternal: config.StripInternal{Enabled: true}, AsyncAPI: tt.asyncAPI}
+			result, err := ProcessStripInternalInDir(dir, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Changed != tt.asyncAPI {
+				t.Errorf("expected Changed=%v, got %v", tt.asyncAPI, result.Changed)
+			}
+
+			output := readAsyncAPITestFile(t, path)
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output to contain %q\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("expected output not to contain %q\n%s", unwanted, output)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultsAsyncAPI(t *testing.T) {
+	defaults := config.DefaultValues{
+		Enabled: true,
+		Rules: map[string]config.DefaultRule{
+			"user_id": {
+				Target:    config.DefaultTarget{Location: "parameter"},
+				Condition: config.DefaultCondition{PropertyName: "userId"},
+				Value:     "me",
+			},
+			"locale": {
+				Target:    config.DefaultTarget{Location: "message"},
+				Condition: config.DefaultCondition{PropertyName: "locale"},
+				Value:     "en-US",
+			},
+		},
+	}
+
+	for name, spec := range map[string]string{"AsyncAPI 2": asyncAPI2TestSpec, "AsyncAPI 3": asyncAPI3TestSpec} {
+		t.Run(name, func(t *testing.T) {
+			dir, path := writeAsyncAPITestFile(t, spec)
+
+			result, err := ProcessDefaultsInDir(dir, DefaultsOptions{DefaultValues: defaults, AsyncAPI: true})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Changed || len(result.AppliedDefaults[path]) != 2 {
+				t.Fatalf("expected 2 applied defaults, got %v", result.AppliedDefaults)
+			}
+
+			output := readAsyncAPITestFile(t, path)
+			for _, want := range []string{"default: me", "default: en-US"} {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output to contain %q\n%s", want, output)
+				}
+			}
+		})
+	}
+
+	dir, path := writeAsyncAPITestFile(t, asyncAPI2TestSpec)
+	result, err := ProcessDefaultsInDir(dir, DefaultsOptions{DefaultValues: defaults})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Changed || readAsyncAPITestFile(t, path) != asyncAPI2TestSpec {
+		t.Error("expected AsyncAPI documents to be skipped unless enabled")
+	}
+}
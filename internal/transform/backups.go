@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/developerkunal/OpenMorph/internal/backup"
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Backup naming policies
+const (
+	BackupNamingBak         = "bak"         // file.bak next to the file, overwritten by every run (default)
+	BackupNamingTimestamped = "timestamped" // one folder per run under backups.dir, mirroring paths
+)
+
+// ValidateBackups checks the backups: section of the config
+func ValidateBackups(backups config.Backups) error {
+	switch backups.Naming {
+	case "", BackupNamingBak, BackupNamingTimestamped:
+	default:
+		return fmt.Errorf("backups: unknown naming %q (use %s or %s)", backups.Naming, BackupNamingBak, BackupNamingTimestamped)
+	}
+	if backups.MaxBackups < 0 {
+		return fmt.Errorf("backups: max_backups must not be negative, got %d", backups.MaxBackups)
+	}
+	return nil
+}
+
+// BackupDir returns the folder timestamped backups are kept in
+func BackupDir(backups config.Backups) string {
+	if backups.Dir != "" {
+		return backups.Dir
+	}
+	return backup.DefaultDir
+}
+
+// snapshotInputs reads every YAML/JSON file under inputPath, keyed by path
+func snapshotInputs(inputPath string) (map[string][]byte, error) {
+	originals := make(map[string][]byte)
+	err := filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(IsYAML(path) || IsJSON(path)) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		originals[path] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot inputs for backup: %v", err)
+	}
+	return originals, nil
+}
+
+// saveBackups keeps the originals of the files whose contents changed in a new run folder and
+// prunes the runs beyond max_backups. It returns nil when no file changed.
+func saveBackups(backups config.Backups, originals map[string][]byte) (*backup.Run, error) {
+	changed := make(map[string][]byte)
+	for path, data := range originals {
+		current, err := os.ReadFile(path)
+		if err != nil || !bytes.Equal(current, data) {
+			changed[path] = data
+		}
+	}
+
+	dir := BackupDir(backups)
+	run, err := backup.Save(dir, changed, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if run != nil && backups.MaxBackups > 0 {
+		if _, err := backup.Prune(dir, backups.MaxBackups); err != nil {
+			return run, err
+		}
+	}
+	return run, nil
+}
@@ -0,0 +1,135 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestTimestampedBackups(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "specs")
+	if err := os.MkdirAll(input, 0750); err != nil {
+		t.Fatal(err)
+	}
+	changed := filepath.Join(input, "api.yaml")
+	unchanged := filepath.Join(input, "other.yaml")
+	original := "openapi: 3.0.0\ninfo:\n  x-a: 1\n"
+	if err := os.WriteFile(changed, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(unchanged, []byte("openapi: 3.0.0\ninfo:\n  title: Other\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Mappings: map[string]string{"x-a": "x-b"},
+		Backups:  config.Backups{Naming: BackupNamingTimestamped, Dir: filepath.Join(dir, "backups"), MaxBackups: 1},
+	}
+	for i := 0; i < 2; i++ {
+		if i == 1 {
+			if err := os.WriteFile(changed, []byte(original), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		results, err := NewTransformationPipeline(cfg, nil, false, true, "").ExecuteFullPipeline(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if results.BackupRun == nil || len(results.BackupRun.Files) != 1 {
+			t.Fatalf("expected one backed-up file, got %+v", results.BackupRun)
+		}
+	}
+
+	if _, err := os.Stat(changed + ".bak"); err == nil {
+		t.Error("expected no .bak file next to the input")
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "backups"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected max_backups to keep one run, got %d", len(entries))
+	}
+}
+
+func TestOutputFileBackups(t *testing.T) {
+	tests := []struct {
+		name    string
+		backups config.Backups
+	}{
+		{"bak", config.Backups{}},
+		{"timestamped", config.Backups{Naming: BackupNamingTimestamped}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			input := filepath.Join(dir, "api.yaml")
+			output := filepath.Join(dir, "out.yaml")
+			if err := os.WriteFile(input, []byte("openapi: 3.0.0\ninfo:\n  x-a: 1\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(output, []byte("stale\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := &config.Config{Mappings: map[string]string{"x-a": "x-b"}, Backups: tt.backups}
+			cfg.Backups.Dir = filepath.Join(dir, "backups")
+			results, err := NewTransformationPipeline(cfg, nil, false, true, output).ExecuteFullPipeline(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := os.Stat(input + ".bak"); err == nil {
+				t.Error("expected no backup of the read-only input")
+			}
+			if tt.backups.Naming == BackupNamingTimestamped {
+				if results.BackupRun == nil || len(results.BackupRun.Files) != 1 {
+					t.Fatalf("expected the replaced output to be backed up, got %+v", results.BackupRun)
+				}
+				for _, original := range results.BackupRun.Files {
+					if original != output {
+						t.Errorf("expected the output to be backed up, got %s", original)
+					}
+				}
+			} else if data, err := os.ReadFile(output + ".bak"); err != nil || string(data) != "stale\n" {
+				t.Errorf("expected the previous output in %s.bak, got %q (%v)", output, data, err)
+			}
+		})
+	}
+}
+
+func TestOutputFileWrittenWithoutChanges(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "api.yaml")
+	output := filepath.Join(dir, "out.yaml")
+	original := "openapi: 3.0.0\ninfo:\n  title: Test\n"
+	if err := os.WriteFile(input, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := NewTransformationPipeline(&config.Config{}, nil, false, false, output).ExecuteFullPipeline(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Changed) != 0 {
+		t.Errorf("expected no changes, got %v", results.Changed)
+	}
+	if data, err := os.ReadFile(output); err != nil || string(data) != original {
+		t.Errorf("expected the output to be a copy of the input, got %q (%v)", data, err)
+	}
+}
+
+func TestValidateBackups(t *testing.T) {
+	if err := ValidateBackups(config.Backups{Naming: "daily"}); err == nil {
+		t.Error("expected an unknown naming error")
+	}
+	if err := ValidateBackups(config.Backups{MaxBackups: -1}); err == nil {
+		t.Error("expected a negative max_backups error")
+	}
+	if err := ValidateBackups(config.Backups{Naming: BackupNamingTimestamped, MaxBackups: 3}); err != nil {
+		t.Errorf("expected valid backups, got %v", err)
+	}
+}
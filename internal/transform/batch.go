@@ -0,0 +1,284 @@
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/lock"
+)
+
+// BatchOptions controls how a batch manifest is executed
+type BatchOptions struct {
+	DryRun  bool
+	Backup  bool   // keep a .bak copy of inputs transformed in place
+	Strict  bool   // fail jobs whose config or input has problems reported by strict mode
+	Lock    bool   // fail jobs whose input another run is transforming, see package lock
+	Version string // tool version recorded in provenance stamps
+}
+
+// BatchJobResult holds the outcome of one batch job
+type BatchJobResult struct {
+	Job        config.BatchJob
+	Output     string                 // where the transformed spec was written, the input when transformed in place
+	Results    *TransformationResults // nil when the job failed or its output was reused
+	CachedFrom string                 // earlier job with the same input, config and profile whose output was reused
+	Err        error
+}
+
+// Batch job statuses
+const (
+	BatchStatusTransformed = "transformed"
+	BatchStatusUnchanged   = "unchanged"
+	BatchStatusCached      = "cached"
+	BatchStatusFailed      = "failed"
+)
+
+// Status summarizes the outcome of the job
+func (r BatchJobResult) Status() string {
+	switch {
+	case r.Err != nil:
+		return BatchStatusFailed
+	case r.CachedFrom != "":
+		return BatchStatusCached
+	case r.Results != nil && r.Results.AnyTransformations:
+		return BatchStatusTransformed
+	default:
+		return BatchStatusUnchanged
+	}
+}
+
+// BatchResults aggregates every job of a batch run in manifest order
+type BatchResults struct {
+	Jobs []BatchJobResult
+}
+
+// Failed returns the number of jobs that failed
+func (r *BatchResults) Failed() int {
+	failed := 0
+	for _, job := range r.Jobs {
+		if job.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// batchRunner executes the jobs of one manifest, sharing loaded configs and the outputs of jobs
+// with identical inputs
+type batchRunner struct {
+	opts    BatchOptions
+	configs map[string]*config.Config
+	outputs map[string]BatchJobResult // job fingerprint -> first job that produced it
+}
+
+// RunBatch runs every job of the manifest in order. A failing job is reported in its result and
+// does not stop the remaining jobs.
+func RunBatch(manifest *config.BatchManifest, opts BatchOptions) *BatchResults {
+	runner := &batchRunner{
+		opts:    opts,
+		configs: make(map[string]*config.Config),
+		outputs: make(map[string]BatchJobResult),
+	}
+
+	results := &BatchResults{}
+	for _, job := range manifest.Jobs {
+		results.Jobs = append(results.Jobs, runner.run(job))
+	}
+	return results
+}
+
+// run executes a single job
+func (b *batchRunner) run(job config.BatchJob) BatchJobResult {
+	result := BatchJobResult{Job: job, Output: job.Output}
+	if result.Output == "" {
+		result.Output = job.Input
+	}
+
+	cfg, err := b.loadConfig(job.Config)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if len(cfg.Outputs) > 0 {
+		result.Err = errors.New("'outputs' cannot be used in batch jobs; add one job per profile instead")
+		return result
+	}
+	providers := []string(nil)
+	if job.Profile != "" {
+		if err := validateProfile(cfg, job.Profile); err != nil {
+			result.Err = err
+			return result
+		}
+		cfg, providers = profileConfig(cfg, job.Profile)
+	}
+
+	fingerprint, err := jobFingerprint(job)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	// Jobs transformed in place always run so their backups are written as usual
+	if previous, ok := b.outputs[fingerprint]; ok && job.Output != "" {
+		if !b.opts.DryRun {
+			if err := copyInput(previous.Output, result.Output); err != nil {
+				result.Err = err
+				return result
+			}
+		}
+		result.CachedFrom = previous.Job.Name
+		return result
+	}
+
+	var held *lock.Lock
+	if b.opts.Lock && !b.opts.DryRun {
+		if held, err = lock.Acquire(job.Input); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	result.Results, result.Err = b.transform(job, cfg, providers, result.Output)
+	if held != nil {
+		if err := held.Release(); err != nil && result.Err == nil {
+			result.Err = err
+		}
+	}
+	if result.Err == nil {
+		b.outputs[fingerprint] = result
+	}
+	return result
+}
+
+// transform stages the job's input at its output (or a temporary copy in dry-run mode) and runs
+// the full pipeline there
+func (b *batchRunner) transform(job config.BatchJob, cfg *config.Config, providers []string, output string) (*TransformationResults, error) {
+	target := output
+	if b.opts.DryRun {
+		tempDir, err := os.MkdirTemp("", "openmorph_batch_*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temp directory: %v", err)
+		}
+		defer os.RemoveAll(tempDir)
+		target = filepath.Join(tempDir, filepath.Base(job.Input))
+	}
+
+	backup := b.opts.Backup && target == job.Input
+	jobPipeline := NewTransformationPipeline(cfg, providers, false, backup, "")
+	jobPipeline.Version = b.opts.Version
+	if b.opts.Strict {
+		// Check the job's input rather than its staged copy so issues point at the input files
+		jobPipeline.Strict = true
+		if err := jobPipeline.CheckStrict(job.Input); err != nil {
+			return nil, err
+		}
+		jobPipeline.Strict = false
+	}
+
+	if target != job.Input {
+		if err := copyInput(job.Input, target); err != nil {
+			return nil, err
+		}
+	}
+
+	var results *TransformationResults
+	err := jobPipeline.withPipelineSpan(target, func(pipeline *TransformationPipeline) error {
+		var err error
+		results, err = pipeline.executeDirectoryPipeline(target)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if target != output {
+		rebaseResults(results, target, output)
+	}
+	return results, nil
+}
+
+// copyInput copies a spec file to the file dst, or a directory's contents into the directory dst
+func copyInput(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %v", err)
+	}
+	if !info.IsDir() {
+		if err := copyFile(src, dst); err != nil {
+			return err
+		}
+		return copySidecar(src, dst)
+	}
+	if err := os.MkdirAll(dst, 0750); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+	_, err = stageInputCopy(src, dst)
+	return err
+}
+
+// loadConfig loads a job's config file once per batch; jobs without a config use an empty one
+func (b *batchRunner) loadConfig(path string) (*config.Config, error) {
+	if path == "" {
+		return &config.Config{}, nil
+	}
+	if cfg, ok := b.configs[path]; ok {
+		return cfg, nil
+	}
+	cfg, err := config.LoadConfigFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %v", err)
+	}
+	b.configs[path] = cfg
+	return cfg, nil
+}
+
+// jobFingerprint hashes the job's input files, config path and profile. Jobs with the same
+// fingerprint produce the same output.
+func jobFingerprint(job config.BatchJob) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "config=%s\nprofile=%s\n", job.Config, job.Profile)
+
+	var files []string
+	err := filepath.WalkDir(job.Input, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %v", err)
+	}
+	sort.Strings(files)
+
+	for _, path := range files {
+		rel, err := filepath.Rel(job.Input, path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "file=%s\n", rel)
+		if err := hashFile(hash, path); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hashFile writes the contents of path to w
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
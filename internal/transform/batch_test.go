@@ -0,0 +1,151 @@
+package transform
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/lock"
+)
+
+const batchTestSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: bar
+      responses:
+        "200":
+          description: Success
+`
+
+func writeBatchFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "base.yaml")
+	writeBatchFile(t, configPath, "mappings:\n  x-foo: x-bar\n")
+	writeBatchFile(t, filepath.Join(dir, "specs/a.yaml"), batchTestSpec)
+	writeBatchFile(t, filepath.Join(dir, "specs/b.yaml"), batchTestSpec)
+	writeBatchFile(t, filepath.Join(dir, "specs/dir/c.yaml"), batchTestSpec)
+	writeBatchFile(t, filepath.Join(dir, "specs/inplace.yaml"), batchTestSpec)
+
+	manifest := &config.BatchManifest{Jobs: []config.BatchJob{
+		{Name: "a", Input: filepath.Join(dir, "specs/a.yaml"), Output: filepath.Join(dir, "out/a.yaml"), Config: configPath},
+		{Name: "b", Input: filepath.Join(dir, "specs/b.yaml"), Output: filepath.Join(dir, "out/b.yaml"), Config: configPath},
+		{Name: "dir", Input: filepath.Join(dir, "specs/dir"), Output: filepath.Join(dir, "out/dir"), Config: configPath},
+		{Name: "inplace", Input: filepath.Join(dir, "specs/inplace.yaml"), Config: configPath},
+		{Name: "noconfig", Input: filepath.Join(dir, "specs/a.yaml"), Output: filepath.Join(dir, "out/noconfig.yaml")},
+		{Name: "profile", Input: filepath.Join(dir, "specs/a.yaml"), Output: filepath.Join(dir, "out/p.yaml"), Config: configPath, Profile: "fern"},
+		{Name: "missing", Input: filepath.Join(dir, "specs/missing.yaml"), Config: configPath},
+	}}
+
+	results := RunBatch(manifest, BatchOptions{})
+
+	wantStatus := []string{
+		BatchStatusTransformed, BatchStatusCached, BatchStatusTransformed, BatchStatusTransformed,
+		BatchStatusUnchanged, BatchStatusFailed, BatchStatusFailed,
+	}
+	for i, want := range wantStatus {
+		if got := results.Jobs[i].Status(); got != want {
+			t.Errorf("job %s: expected status %s, got %s (err: %v)", results.Jobs[i].Job.Name, want, got, results.Jobs[i].Err)
+		}
+	}
+	if results.Failed() != 2 {
+		t.Errorf("expected 2 failed jobs, got %d", results.Failed())
+	}
+	if results.Jobs[1].CachedFrom != "a" {
+		t.Errorf("expected job b to reuse job a, got %q", results.Jobs[1].CachedFrom)
+	}
+
+	for _, output := range []string{"out/a.yaml", "out/b.yaml", "out/dir/c.yaml", "specs/inplace.yaml"} {
+		data, err := os.ReadFile(filepath.Join(dir, output))
+		if err != nil {
+			t.Fatalf("expected output %s: %v", output, err)
+		}
+		if !strings.Contains(string(data), "x-bar: bar") {
+			t.Errorf("expected %s to be transformed:\n%s", output, data)
+		}
+	}
+	// Untransformed outputs are still written
+	if data, err := os.ReadFile(filepath.Join(dir, "out/noconfig.yaml")); err != nil || string(data) != batchTestSpec {
+		t.Errorf("expected unchanged copy for job without config, got %q (%v)", data, err)
+	}
+	// Inputs of jobs with an output are left alone
+	if data, _ := os.ReadFile(filepath.Join(dir, "specs/a.yaml")); string(data) != batchTestSpec {
+		t.Errorf("expected input to be unchanged, got:\n%s", data)
+	}
+}
+
+func TestRunBatchDryRun(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "base.yaml")
+	writeBatchFile(t, configPath, "mappings:\n  x-foo: x-bar\n")
+	input := filepath.Join(dir, "specs/a.yaml")
+	writeBatchFile(t, input, batchTestSpec)
+	output := filepath.Join(dir, "out/a.yaml")
+
+	results := RunBatch(&config.BatchManifest{Jobs: []config.BatchJob{
+		{Name: "a", Input: input, Output: output, Config: configPath},
+		{Name: "inplace", Input: input, Config: configPath, Profile: OutputProfileNone},
+	}}, BatchOptions{DryRun: true})
+
+	for _, job := range results.Jobs {
+		if job.Status() != BatchStatusTransformed {
+			t.Errorf("job %s: expected transformed, got %s (err: %v)", job.Job.Name, job.Status(), job.Err)
+		}
+	}
+	if changed := results.Jobs[0].Results.Changed; len(changed) != 1 || changed[0] != output {
+		t.Errorf("expected dry-run results reported against the output, got %v", changed)
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("expected no output in dry-run mode, got err=%v", err)
+	}
+	if data, _ := os.ReadFile(input); string(data) != batchTestSpec {
+		t.Errorf("expected input to be unchanged in dry-run mode")
+	}
+}
+
+func TestRunBatchLockedInput(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "base.yaml")
+	input := filepath.Join(dir, "specs/a.yaml")
+	writeBatchFile(t, configPath, "mappings:\n  x-foo: x-bar\n")
+	writeBatchFile(t, input, batchTestSpec)
+
+	held, err := lock.Acquire(input)
+	if err != nil {
+		t.Fatalf("failed to lock the input: %v", err)
+	}
+	manifest := &config.BatchManifest{Jobs: []config.BatchJob{{Name: "a", Input: input, Config: configPath}}}
+
+	results := RunBatch(manifest, BatchOptions{Lock: true})
+	var heldErr *lock.HeldError
+	if !errors.As(results.Jobs[0].Err, &heldErr) {
+		t.Fatalf("expected the locked input to fail the job, got %v", results.Jobs[0].Err)
+	}
+
+	if err := held.Release(); err != nil {
+		t.Fatal(err)
+	}
+	results = RunBatch(manifest, BatchOptions{Lock: true})
+	if results.Jobs[0].Status() != BatchStatusTransformed {
+		t.Fatalf("expected the job to run once the lock is released, got %s (err: %v)", results.Jobs[0].Status(), results.Jobs[0].Err)
+	}
+	if _, err := os.Stat(lock.Path(input)); !os.IsNotExist(err) {
+		t.Errorf("expected the job to release its lock, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package transform
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChangedOperation is an operation at least one change location falls in
+type ChangedOperation struct {
+	File   string
+	Path   string
+	Method string
+}
+
+// String returns the operation as "file: GET /users"
+func (o ChangedOperation) String() string {
+	return fmt.Sprintf("%s: %s %s", o.File, strings.ToUpper(o.Method), o.Path)
+}
+
+// PreviewChangedOperations runs execute on a staged copy of the input and returns the operations
+// the run would change, with their files under inputPath. Every step sees what the steps before it
+// wrote, as in the real run, and nothing under inputPath or the output file is written. What the
+// steps print goes to Log, os.Stdout when it is nil.
+func (tp *TransformationPipeline) PreviewChangedOperations(inputPath string,
+	execute func(*TransformationPipeline, string) (*TransformationResults, error)) ([]ChangedOperation, error) {
+	preview := *tp
+	preview.Backup = false
+
+	// With an output file the steps already run on a copy and only the write is skipped
+	if preview.OutputFile != "" {
+		preview.DryRun = true
+		results, err := execute(&preview, inputPath)
+		if err != nil {
+			return nil, err
+		}
+		return ChangedOperations(results.AllLocations()), nil
+	}
+
+	stageDir, err := os.MkdirTemp("", "openmorph_budget_*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(stageDir)
+
+	stageInput, err := stageInputCopy(inputPath, stageDir)
+	if err != nil {
+		return nil, err
+	}
+	preview.DryRun = false
+	results, err := execute(&preview, stageInput)
+	if err != nil {
+		return nil, err
+	}
+	rebaseResults(results, stageInput, inputPath)
+	return ChangedOperations(results.AllLocations()), nil
+}
+
+// operationSpan is the range of lines an operation takes up in its document
+type operationSpan struct {
+	ChangedOperation
+	first, last int
+}
+
+// ChangedOperations returns the operations the locations of a dry run fall in, sorted by file and
+// position. The locations must refer to the documents as they are on disk, so changes to
+// components and other parts of a document outside its operations are not counted.
+func ChangedOperations(locations []ChangeLocation) []ChangedOperation {
+	spans := make(map[string][]operationSpan)
+	var changed []operationSpan
+	seen := make(map[ChangedOperation]bool)
+	for _, location := range locations {
+		if location.Line == 0 {
+			continue
+		}
+		fileSpans, ok := spans[location.File]
+		if !ok {
+			fileSpans = operationSpans(location.File)
+			spans[location.File] = fileSpans
+		}
+		for _, span := range fileSpans {
+			if location.Line >= span.first && location.Line <= span.last {
+				if !seen[span.ChangedOperation] {
+					seen[span.ChangedOperation] = true
+					changed = append(changed, span)
+				}
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(changed, func(i, j int) bool {
+		if changed[i].File != changed[j].File {
+			return changed[i].File < changed[j].File
+		}
+		return changed[i].first < changed[j].first
+	})
+	operations := make([]ChangedOperation, 0, len(changed))
+	for _, span := range changed {
+		operations = append(operations, span.ChangedOperation)
+	}
+	return operations
+}
+
+// operationSpans returns the lines of every operation of an OpenAPI document, from its method key
+// to its last line. Files that cannot be parsed have none.
+func operationSpans(file string) []operationSpan {
+	doc, err := loadAndParseDocument(file)
+	if err != nil {
+		return nil
+	}
+	paths := getNodeValue(getRootNode(doc), "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var spans []operationSpan
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathItem := paths.Content[i+1]
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			method := pathItem.Content[j].Value
+			if !isHTTPMethod(method) {
+				continue
+			}
+			spans = append(spans, operationSpan{
+				ChangedOperation: ChangedOperation{File: file, Path: paths.Content[i].Value, Method: method},
+				first:            pathItem.Content[j].Line,
+				last:             lastLine(pathItem.Content[j+1]),
+			})
+		}
+	}
+	return spans
+}
+
+// lastLine returns the last line a node or any of its children starts on
+func lastLine(node *yaml.Node) int {
+	last := node.Line
+	for _, child := range node.Content {
+		if line := lastLine(child); line > last {
+			last = line
+		}
+	}
+	return last
+}
@@ -0,0 +1,98 @@
+package transform
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestChangedOperations(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "api.yaml")
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: users
+      responses:
+        "200":
+          description: Success
+    post:
+      responses:
+        "201":
+          description: Created
+  /orders:
+    get:
+      x-foo: orders
+components:
+  schemas:
+    User:
+      type: object
+`
+	if err := os.WriteFile(file, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	locations := []ChangeLocation{
+		{File: file, Line: 18, Step: StepMappings}, // GET /orders
+		{File: file, Line: 8, Step: StepMappings},  // GET /users
+		{File: file, Line: 11, Step: StepDefaults}, // GET /users again
+		{File: file, Line: 22, Step: StepDefaults}, // component schema
+		{File: file, Step: StepExamples},           // no position
+	}
+	want := []ChangedOperation{
+		{File: file, Path: "/users", Method: "get"},
+		{File: file, Path: "/orders", Method: "get"},
+	}
+	if got := ChangedOperations(locations); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedOperations() = %v, want %v", got, want)
+	}
+}
+
+func TestPreviewChangedOperations(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-tier: gold
+      responses:
+        "200":
+          description: Success
+  /admin:
+    get:
+      x-internal: true
+      x-tier: gold
+      responses:
+        "200":
+          description: Success
+`
+	dir, file := writeTestSpec(t, spec)
+	cfg := &config.Config{
+		StripInternal:   config.StripInternal{Enabled: true, Extension: "x-internal"},
+		ExtensionValues: map[string]map[string]string{"x-tier": {"gold": "premium"}},
+	}
+	pipeline := NewTransformationPipeline(cfg, nil, false, false, "")
+	pipeline.Log = io.Discard
+
+	// The operation strip_internal removes is gone by the time extension values are replaced
+	got, err := pipeline.PreviewChangedOperations(dir, (*TransformationPipeline).ExecuteFullPipeline)
+	if err != nil {
+		t.Fatalf("PreviewChangedOperations() error = %v", err)
+	}
+	want := []ChangedOperation{{File: file, Path: "/users", Method: "get"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PreviewChangedOperations() = %v, want %v", got, want)
+	}
+	if data, _ := os.ReadFile(file); string(data) != spec {
+		t.Errorf("expected the input to be left alone, got:\n%s", data)
+	}
+}
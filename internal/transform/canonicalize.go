@@ -0,0 +1,240 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// DefaultOperationKeyOrder is the order operation keys are written in when canonicalize does not
+// configure one. Keys that are not listed follow in their original order, then extensions unless
+// extension_placement puts them elsewhere.
+var DefaultOperationKeyOrder = []string{
+	"summary",
+	"description",
+	"operationId",
+	"tags",
+	"parameters",
+	"requestBody",
+	"responses",
+	"callbacks",
+	"deprecated",
+	"security",
+	"servers",
+	"externalDocs",
+}
+
+// CanonicalizeOptions extends the regular Options with canonicalization settings
+type CanonicalizeOptions struct {
+	Options
+	Canonicalize config.Canonicalize
+	Placement    string // where extensions go among the operation keys, see ExtensionPlacementEnd
+}
+
+// CanonicalizeResult represents the result of document canonicalization
+type CanonicalizeResult struct {
+	Changed        bool
+	ProcessedFiles []string
+	SortedSections map[string][]string // file -> list of sections put into canonical order
+	Locations      []ChangeLocation    // source positions of the reordered sections
+}
+
+// createCanonicalizeResult creates a new CanonicalizeResult with initialized maps
+func createCanonicalizeResult() *CanonicalizeResult {
+	return &CanonicalizeResult{
+		ProcessedFiles: []string{},
+		SortedSections: make(map[string][]string),
+	}
+}
+
+// setCanonicalizeProcessedFiles sets the processed files for a CanonicalizeResult
+func setCanonicalizeProcessedFiles(result *CanonicalizeResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setCanonicalizeChanged sets the changed flag for a CanonicalizeResult
+func setCanonicalizeChanged(result *CanonicalizeResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessCanonicalizeInDir sorts paths and components and orders operation keys in all OpenAPI
+// files in a directory
+func ProcessCanonicalizeInDir(dir string, opts CanonicalizeOptions) (*CanonicalizeResult, error) {
+	if err := ValidateCanonicalize(opts.Canonicalize); err != nil {
+		return createCanonicalizeResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepCanonicalize,
+		opts.Options,
+		opts.Canonicalize.Enabled,
+		false,
+		createCanonicalizeResult,
+		func(path string, result *CanonicalizeResult) (bool, error) {
+			return processCanonicalizeInFile(path, opts, result)
+		},
+		setCanonicalizeProcessedFiles,
+		setCanonicalizeChanged,
+	)
+}
+
+// ValidateCanonicalize checks that the operation key order lists each key once and no extensions
+func ValidateCanonicalize(canonicalize config.Canonicalize) error {
+	seen := make(map[string]bool)
+	for _, key := range canonicalize.OperationKeyOrder {
+		if strings.HasPrefix(key, "x-") {
+			return fmt.Errorf("canonicalize.operation_key_order: %s: extensions are placed by extension_placement and cannot be ordered", key)
+		}
+		if seen[key] {
+			return fmt.Errorf("canonicalize.operation_key_order: %s is listed more than once", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// processCanonicalizeInFile canonicalizes a single file
+func processCanonicalizeInFile(path string, opts CanonicalizeOptions, result *CanonicalizeResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	order := opts.Canonicalize.OperationKeyOrder
+	if len(order) == 0 {
+		order = DefaultOperationKeyOrder
+	}
+
+	changed := false
+	record := func(node *yaml.Node, entry string) {
+		changed = true
+		result.SortedSections[path] = append(result.SortedSections[path], entry)
+		result.Locations = append(result.Locations, newChangeLocation(path, StepCanonicalize, node, entry))
+	}
+
+	paths := getNodeValue(root, "paths")
+	if paths != nil && paths.Kind == yaml.MappingNode {
+		if sortMappingKeys(paths) {
+			record(paths, "paths sorted")
+		}
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+			if pathItem.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j+1 < len(pathItem.Content); j += 2 {
+				method, operation := pathItem.Content[j].Value, pathItem.Content[j+1]
+				if !isHTTPMethod(method) || operation.Kind != yaml.MappingNode {
+					continue
+				}
+				if orderOperationKeys(operation, order, opts.Placement) {
+					record(operation, fmt.Sprintf("%s %s: operation keys reordered", strings.ToUpper(method), pathName))
+				}
+			}
+		}
+	}
+
+	if components := getNodeValue(root, "components"); components != nil && components.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(components.Content); i += 2 {
+			section := components.Content[i+1]
+			if section.Kind == yaml.MappingNode && sortMappingKeys(section) {
+				record(section, fmt.Sprintf("components.%s sorted", components.Content[i].Value))
+			}
+		}
+	}
+	if getStringValue(root, "swagger") != "" {
+		// Swagger 2.0 keeps its components at the top level
+		for _, section := range []string{"definitions", "parameters", "responses", "securityDefinitions"} {
+			if node := getNodeValue(root, section); node != nil && node.Kind == yaml.MappingNode && sortMappingKeys(node) {
+				record(node, section+" sorted")
+			}
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// sortMappingKeys sorts the pairs of a mapping node by key and reports whether the order changed
+func sortMappingKeys(node *yaml.Node) bool {
+	keys := make([]string, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	if sort.StringsAreSorted(keys) {
+		return false
+	}
+	reorderMapping(node, func(a, b *yaml.Node) bool { return a.Value < b.Value })
+	return true
+}
+
+// orderOperationKeys orders an operation's keys: listed keys in list order, then other keys, each
+// group keeping its original relative order. Extensions go at placement: last by default, first,
+// right after operationId, or last in alphabetical order.
+func orderOperationKeys(operation *yaml.Node, order []string, placement string) bool {
+	rank := func(key string) int {
+		for i, k := range order {
+			if k == key {
+				return 2 * i
+			}
+		}
+		return 2 * len(order)
+	}
+	extensionRank := 2*len(order) + 1
+	switch placement {
+	case ExtensionPlacementTop:
+		extensionRank = -1
+	case ExtensionPlacementAfterOperationID:
+		extensionRank = -1
+		if getNodeValue(operation, "operationId") != nil {
+			extensionRank = rank("operationId") + 1
+		}
+	}
+	keyRank := func(key string) int {
+		if isExtensionKey(key) {
+			return extensionRank
+		}
+		return rank(key)
+	}
+
+	before := make([]*yaml.Node, len(operation.Content))
+	copy(before, operation.Content)
+	reorderMapping(operation, func(a, b *yaml.Node) bool {
+		if placement == ExtensionPlacementAlphabetical && isExtensionKey(a.Value) && isExtensionKey(b.Value) {
+			return a.Value < b.Value
+		}
+		return keyRank(a.Value) < keyRank(b.Value)
+	})
+	for i := range before {
+		if before[i] != operation.Content[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// reorderMapping stably sorts the key/value pairs of a mapping node by key
+func reorderMapping(node *yaml.Node, less func(a, b *yaml.Node) bool) {
+	type pair struct{ key, value *yaml.Node }
+	pairs := make([]pair, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, pair{node.Content[i], node.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return less(pairs[i].key, pairs[j].key) })
+	node.Content = node.Content[:0]
+	for _, p := range pairs {
+		node.Content = append(node.Content, p.key, p.value)
+	}
+}
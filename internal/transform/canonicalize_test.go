@@ -0,0 +1,137 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const canonicalizeTestSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-internal-note: keep last
+      responses:
+        "200":
+          description: OK
+      operationId: listUsers
+      summary: List users
+  /accounts:
+    get:
+      summary: List accounts
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    User:
+      type: object
+    Account:
+      type: object
+  parameters:
+    Limit:
+      name: limit
+      in: query
+`
+
+func TestProcessCanonicalizeInDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(canonicalizeTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessCanonicalizeInDir(dir, CanonicalizeOptions{Canonicalize: config.Canonicalize{Enabled: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"paths sorted",
+		"GET /users: operation keys reordered",
+		"components.schemas sorted",
+	}
+	if got := result.SortedSections[path]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected sorted sections:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, pair := range [][2]string{
+		{"/accounts:", "/users:"},
+		{"summary: List users", "operationId: listUsers"},
+		{"operationId: listUsers", "x-internal-note: keep last"},
+		{"description: OK", "x-internal-note: keep last"},
+		{"Account:", "User:"},
+	} {
+		first, second := strings.Index(content, pair[0]), strings.Index(content, pair[1])
+		if first < 0 || second < 0 || first > second {
+			t.Errorf("expected %q before %q, got:\n%s", pair[0], pair[1], content)
+		}
+	}
+
+	// A second run finds nothing left to sort
+	result, err = ProcessCanonicalizeInDir(dir, CanonicalizeOptions{Canonicalize: config.Canonicalize{Enabled: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed {
+		t.Errorf("expected canonical output to be stable, got %v", result.SortedSections)
+	}
+}
+
+func TestValidateCanonicalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []string
+		want  string
+	}{
+		{"extension", []string{"summary", "x-codeSamples"}, "extensions are placed by extension_placement"},
+		{"duplicate", []string{"summary", "summary"}, "listed more than once"},
+	}
+	for _, tt := range tests {
+		err := ValidateCanonicalize(config.Canonicalize{OperationKeyOrder: tt.order})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestOrderOperationKeysPlacement(t *testing.T) {
+	tests := []struct {
+		placement string
+		keys      string
+		want      string
+	}{
+		{ExtensionPlacementEnd, "x-b responses operationId summary", "summary operationId responses x-b"},
+		{ExtensionPlacementTop, "responses x-b summary x-a", "x-b x-a summary responses"},
+		{ExtensionPlacementAfterOperationID, "x-b responses operationId summary", "summary operationId x-b responses"},
+		{ExtensionPlacementAfterOperationID, "responses x-b summary", "x-b summary responses"},
+		{ExtensionPlacementAlphabetical, "x-b responses x-a summary", "summary responses x-a x-b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.placement+" "+tt.keys, func(t *testing.T) {
+			operation := &yaml.Node{Kind: yaml.MappingNode}
+			for _, key := range strings.Fields(tt.keys) {
+				operation.Content = append(operation.Content, newScalarNode(key), newScalarNode("v"))
+			}
+			orderOperationKeys(operation, DefaultOperationKeyOrder, tt.placement)
+
+			var keys []string
+			for i := 0; i+1 < len(operation.Content); i += 2 {
+				keys = append(keys, operation.Content[i].Value)
+			}
+			if got := strings.Join(keys, " "); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
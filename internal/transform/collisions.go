@@ -0,0 +1,61 @@
+package transform
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Strategies for a mapping whose target key already exists on the same node
+const (
+	CollisionKeepExisting = "keep_existing" // leave both keys as they are (default)
+	CollisionOverwrite    = "overwrite"     // the mapped key's value replaces the existing one
+	CollisionMerge        = "merge"         // merge mapping values, existing keys win; others are kept
+	CollisionError        = "error"         // fail the run
+)
+
+// ValidateMappingCollisions checks the mapping_collisions strategy
+func ValidateMappingCollisions(strategy string) error {
+	switch strategy {
+	case "", CollisionKeepExisting, CollisionOverwrite, CollisionMerge, CollisionError:
+		return nil
+	}
+	return fmt.Errorf("mapping_collisions: unknown strategy %q (use %s, %s, %s or %s)",
+		strategy, CollisionKeepExisting, CollisionOverwrite, CollisionMerge, CollisionError)
+}
+
+// collisionResolution returns how a collision between the existing target value and the mapped
+// value is resolved. Values that are not both mappings cannot be merged and are kept as they are.
+func collisionResolution(strategy string, existing, incoming *yaml.Node) string {
+	switch strategy {
+	case CollisionOverwrite, CollisionError:
+		return strategy
+	case CollisionMerge:
+		if existing.Kind == yaml.MappingNode && incoming.Kind == yaml.MappingNode {
+			return CollisionMerge
+		}
+	}
+	return CollisionKeepExisting
+}
+
+// resolveCollision applies a resolution to the existing target value and returns the value the
+// target ends up with
+func resolveCollision(resolution string, existing, incoming *yaml.Node) *yaml.Node {
+	switch resolution {
+	case CollisionOverwrite:
+		return incoming
+	case CollisionMerge:
+		for i := 0; i+1 < len(incoming.Content); i += 2 {
+			if getNodeValue(existing, incoming.Content[i].Value) == nil {
+				existing.Content = append(existing.Content, incoming.Content[i], incoming.Content[i+1])
+			}
+		}
+	}
+	return existing
+}
+
+// collisionError reports a mapping whose target already exists under the error strategy
+func collisionError(file string, key *yaml.Node, from, to string) error {
+	location := ChangeLocation{File: file, Line: key.Line, Column: key.Column}
+	return fmt.Errorf("%s: mapping %s -> %s: %s already exists", location.Position(), from, to, to)
+}
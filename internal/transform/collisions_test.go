@@ -0,0 +1,133 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMappingCollisions(t *testing.T) {
+	const input = "x-group:\n  name: users\nx-fern-group:\n  order: 1\nsummary: List\n"
+	tests := []struct {
+		name      string
+		strategy  string
+		want      string
+		collision string
+	}{
+		{
+			name:      "keep existing by default",
+			want:      input,
+			collision: CollisionKeepExisting,
+		},
+		{
+			name:      "overwrite",
+			strategy:  CollisionOverwrite,
+			want:      "x-fern-group:\n    name: users\nsummary: List\n",
+			collision: CollisionOverwrite,
+		},
+		{
+			name:      "merge",
+			strategy:  CollisionMerge,
+			want:      "x-fern-group:\n    order: 1\n    name: users\nsummary: List\n",
+			collision: CollisionMerge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "spec.yaml")
+			if err := os.WriteFile(path, []byte(input), 0600); err != nil {
+				t.Fatal(err)
+			}
+			var changes []KeyChange
+			opts := Options{Mappings: map[string]string{"x-group": "x-fern-group"}, Collisions: tt.strategy}
+			if _, err := FileWithChanges(path, opts, &changes); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.want, got)
+			}
+			if len(changes) != 1 || changes[0].Collision != tt.collision || changes[0].Line != 1 {
+				t.Errorf("unexpected changes %+v", changes)
+			}
+		})
+	}
+}
+
+func TestMappingCollisionMergeFallsBackForScalars(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	input := "x-a: 1\nx-b: 2\n"
+	if err := os.WriteFile(path, []byte(input), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var changes []KeyChange
+	changed, err := FileWithChanges(path, Options{Mappings: map[string]string{"x-a": "x-b"}, Collisions: CollisionMerge}, &changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || len(changes) != 1 || changes[0].Collision != CollisionKeepExisting {
+		t.Errorf("expected the scalar collision to be kept, got changed=%v %+v", changed, changes)
+	}
+}
+
+func TestMappingCollisionError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(path, []byte(`{"x-a": 1, "x-b": 2}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := FileWithChanges(path, Options{Mappings: map[string]string{"x-a": "x-b"}, Collisions: CollisionError}, nil)
+	if err == nil || !strings.Contains(err.Error(), "x-b already exists") {
+		t.Errorf("expected a collision error, got %v", err)
+	}
+}
+
+func TestMappingCollisionJSONOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(path, []byte(`{"x-b": 2, "x-a": 1}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FileWithChanges(path, Options{Mappings: map[string]string{"x-a": "x-b"}, Collisions: CollisionOverwrite}, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(got), `"x-b"`) != 1 || !strings.Contains(string(got), `"x-b": 1`) || strings.Contains(string(got), `"x-a"`) {
+		t.Errorf("expected a single overwritten x-b, got:\n%s", got)
+	}
+}
+
+func TestPathMappingCollisions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	input := "x-meta:\n  limits:\n    burst: 10\nx-gateway:\n  limits:\n    rate: 100\n"
+	if err := os.WriteFile(path, []byte(input), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{Mappings: map[string]string{"x-meta.limits": "x-gateway.limits"}, Collisions: CollisionMerge}
+	if _, err := FileWithChanges(path, opts, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "x-gateway:\n    limits:\n        rate: 100\n        burst: 10\n"
+	if string(got) != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestValidateMappingCollisions(t *testing.T) {
+	if err := ValidateMappingCollisions("replace"); err == nil {
+		t.Error("expected an unknown strategy error")
+	}
+	if err := ValidateMappingCollisions(CollisionMerge); err != nil {
+		t.Errorf("expected merge to be valid, got %v", err)
+	}
+}
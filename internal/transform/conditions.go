@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// ValidateConditions checks the conditions of every step that selects operations: a path pattern
+// that does not compile, or an extension condition naming something other than an extension,
+// would otherwise match nothing without saying so
+func ValidateConditions(cfg *config.Config) error {
+	for _, name := range sortedKeysOf(cfg.VendorExtensions.Providers) {
+		provider := cfg.VendorExtensions.Providers[name]
+		if err := condition.ValidatePatterns(provider.PathPatterns, condition.Glob); err != nil {
+			return fmt.Errorf("vendor_extensions.providers.%s.path_patterns: %v", name, err)
+		}
+		if err := condition.ValidatePatterns(provider.ExcludePaths, condition.Glob); err != nil {
+			return fmt.Errorf("vendor_extensions.providers.%s.exclude_paths: %v", name, err)
+		}
+		if err := validateExtensionPresence(provider.HasExtension, provider.MissingExtension); err != nil {
+			return fmt.Errorf("vendor_extensions.providers.%s.%v", name, err)
+		}
+	}
+	for _, name := range sortedKeysOf(cfg.DefaultValues.Rules) {
+		ruleCondition := cfg.DefaultValues.Rules[name].Condition
+		if err := condition.ValidatePatterns(ruleCondition.PathPatterns, condition.Regex); err != nil {
+			return fmt.Errorf("default_values.rules.%s.condition.path_patterns: %v", name, err)
+		}
+		if err := validateExtensionPresence(ruleCondition.HasExtension, ruleCondition.MissingExtension); err != nil {
+			return fmt.Errorf("default_values.rules.%s.condition.%v", name, err)
+		}
+	}
+	for _, name := range sortedKeysOf(cfg.SchemaConstraints.Rules) {
+		ruleCondition := cfg.SchemaConstraints.Rules[name].Condition
+		if err := condition.ValidatePatterns(ruleCondition.PathPatterns, condition.Regex); err != nil {
+			return fmt.Errorf("schema_constraints.rules.%s.condition.path_patterns: %v", name, err)
+		}
+		if err := validateExtensionPresence(ruleCondition.HasExtension, ruleCondition.MissingExtension); err != nil {
+			return fmt.Errorf("schema_constraints.rules.%s.condition.%v", name, err)
+		}
+	}
+	for i, param := range cfg.ParameterInjection.Parameters {
+		if err := condition.ValidatePatterns(param.Condition.PathPatterns, condition.Regex); err != nil {
+			return fmt.Errorf("parameter_injection.parameters[%d].condition.path_patterns: %v", i, err)
+		}
+		if err := validateExtensionPresence(param.Condition.HasExtension, param.Condition.MissingExtension); err != nil {
+			return fmt.Errorf("parameter_injection.parameters[%d].condition.%v", i, err)
+		}
+	}
+	for i, rule := range cfg.EndpointPagination {
+		if err := condition.ValidatePatterns([]string{rule.Endpoint}, condition.Glob); err != nil {
+			return fmt.Errorf("endpoint_pagination[%d].endpoint: %v", i, err)
+		}
+	}
+	for _, pattern := range sortedKeysOf(cfg.ResultsFields) {
+		if err := condition.ValidatePatterns([]string{pattern}, condition.Glob); err != nil {
+			return fmt.Errorf("results_fields: %v", err)
+		}
+		if cfg.ResultsFields[pattern] == "" {
+			return fmt.Errorf("results_fields.%s: no property name", pattern)
+		}
+	}
+	return nil
+}
+
+// validateExtensionPresence checks that has_extension and missing_extension name extension keys
+func validateExtensionPresence(has, missing []string) error {
+	for _, key := range has {
+		if !strings.HasPrefix(key, "x-") {
+			return fmt.Errorf("has_extension: %q is not an extension key", key)
+		}
+	}
+	for _, key := range missing {
+		if !strings.HasPrefix(key, "x-") {
+			return fmt.Errorf("missing_extension: %q is not an extension key", key)
+		}
+	}
+	return nil
+}
+
+// sortedKeysOf returns the keys of a map in order
+func sortedKeysOf[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
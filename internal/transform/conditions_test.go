@@ -0,0 +1,115 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const conditionsTestSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      tags: [Users]
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+  /orders:
+    get:
+      tags: [Orders]
+      x-beta: true
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+`
+
+func TestDefaultsRuleMatchesTagsAndExtensions(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition config.DefaultCondition
+		want      string
+	}{
+		{name: "tags", condition: config.DefaultCondition{Tags: []string{"Users"}}, want: "/users"},
+		{name: "extensions", condition: config.DefaultCondition{Extensions: map[string]string{"x-beta": "true"}}, want: "/orders"},
+		{name: "has extension", condition: config.DefaultCondition{HasExtension: config.StringList{"x-beta"}}, want: "/orders"},
+		{name: "missing extension", condition: config.DefaultCondition{MissingExtension: config.StringList{"x-beta"}}, want: "/users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "api.yaml")
+			if err := os.WriteFile(path, []byte(conditionsTestSpec), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.condition.ParameterIn = "query"
+			result, err := ProcessDefaultsInDir(dir, DefaultsOptions{DefaultValues: config.DefaultValues{
+				Enabled: true,
+				Rules: map[string]config.DefaultRule{"limit": {
+					Target:    config.DefaultTarget{Location: "parameter"},
+					Condition: tt.condition,
+					Value:     20,
+				}},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			applied := result.AppliedDefaults[path]
+			if len(applied) != 1 || !strings.Contains(applied[0], tt.want) {
+				t.Errorf("expected a single default on %s, got %v", tt.want, applied)
+			}
+		})
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	valid := &config.Config{
+		VendorExtensions: config.VendorExtensions{Providers: map[string]config.ProviderConfig{
+			"fern": {PathPatterns: []string{"/api/*", "regex:^/v[0-9]+/"}},
+		}},
+		EndpointPagination: []config.EndpointPaginationRule{{Endpoint: "/users/*"}},
+	}
+	if err := ValidateConditions(valid); err != nil {
+		t.Errorf("expected valid conditions, got %v", err)
+	}
+
+	invalid := &config.Config{DefaultValues: config.DefaultValues{Rules: map[string]config.DefaultRule{
+		"limit": {Condition: config.DefaultCondition{PathPatterns: []string{"^/users/("}}},
+	}}}
+	err := ValidateConditions(invalid)
+	if err == nil || !strings.Contains(err.Error(), "default_values.rules.limit.condition.path_patterns") {
+		t.Errorf("expected an error naming the rule, got %v", err)
+	}
+
+	invalid = &config.Config{ParameterInjection: config.ParameterInjection{Parameters: []config.InjectedParameter{
+		{Condition: config.InjectionCondition{MissingExtension: config.StringList{"beta"}}},
+	}}}
+	err = ValidateConditions(invalid)
+	if err == nil || !strings.Contains(err.Error(), "parameter_injection.parameters[0].condition.missing_extension") {
+		t.Errorf("expected an error naming the condition, got %v", err)
+	}
+
+	invalid = &config.Config{ResultsFields: map[string]string{"/network-acls": ""}}
+	err = ValidateConditions(invalid)
+	if err == nil || !strings.Contains(err.Error(), "results_fields./network-acls") {
+		t.Errorf("expected an error naming the pattern, got %v", err)
+	}
+}
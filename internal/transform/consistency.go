@@ -0,0 +1,295 @@
+package transform
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Canonical definition preferences for component consistency
+const (
+	ConsistencyPreferMostCommon = "most_common"
+	ConsistencyPreferFirst      = "first"
+)
+
+// ConsistencyOptions extends the regular Options with cross-file component consistency settings
+type ConsistencyOptions struct {
+	Options
+	ComponentConsistency config.ComponentConsistency
+}
+
+// ConsistencyResult represents the result of the cross-file component consistency check
+type ConsistencyResult struct {
+	Changed              bool
+	ProcessedFiles       []string
+	Conflicts            map[string][]string // section/name -> one entry per distinct definition, listing the files that use it
+	HarmonizedComponents map[string][]string // file -> components replaced with the canonical definition
+	SkippedComponents    map[string][]Skip   // file -> conflicting components left as they were, with the reason
+	Locations            []ChangeLocation    // source positions of harmonized components
+}
+
+// componentDefinition is one file's definition of a component
+type componentDefinition struct {
+	file        string // path relative to the input directory, slash-separated
+	section     *yaml.Node
+	key         *yaml.Node
+	node        *yaml.Node
+	fingerprint string
+}
+
+// consistencyDocument is a parsed OpenAPI document of the input
+type consistencyDocument struct {
+	path string
+	doc  *yaml.Node
+	root *yaml.Node
+}
+
+// ValidateComponentConsistency checks the canonical definition preference and the component
+// names of the canonical overrides
+func ValidateComponentConsistency(consistency config.ComponentConsistency) error {
+	switch consistency.Prefer {
+	case "", ConsistencyPreferMostCommon, ConsistencyPreferFirst:
+	default:
+		return fmt.Errorf("component_consistency.prefer must be %s or %s, got %q",
+			ConsistencyPreferMostCommon, ConsistencyPreferFirst, consistency.Prefer)
+	}
+	for component, file := range consistency.Canonical {
+		section, name, ok := strings.Cut(component, "/")
+		if !ok || section == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("component_consistency.canonical: %q must be section/name, e.g. schemas/User", component)
+		}
+		if file == "" {
+			return fmt.Errorf("component_consistency.canonical.%s: file is required", component)
+		}
+	}
+	return nil
+}
+
+// ProcessComponentConsistencyInDir reports the components defined with different shapes under the
+// same name in several OpenAPI files of a directory and, when harmonize is enabled, replaces the
+// other definitions with the canonical one
+func ProcessComponentConsistencyInDir(dir string, opts ConsistencyOptions) (*ConsistencyResult, error) {
+	result := &ConsistencyResult{
+		ProcessedFiles:       []string{},
+		Conflicts:            make(map[string][]string),
+		HarmonizedComponents: make(map[string][]string),
+		SkippedComponents:    make(map[string][]Skip),
+	}
+	consistency := opts.ComponentConsistency
+	if !consistency.Enabled {
+		return result, nil
+	}
+	if err := ValidateComponentConsistency(consistency); err != nil {
+		return result, err
+	}
+
+	docs, err := loadConsistencyDocuments(dir, opts.Options)
+	if err != nil {
+		return result, err
+	}
+
+	ignoreKeys := make(map[string]bool, len(consistency.IgnoreKeys))
+	for _, key := range consistency.IgnoreKeys {
+		ignoreKeys[key] = true
+	}
+	definitions, components := collectComponentDefinitions(dir, docs, consistency.Sections, ignoreKeys)
+
+	changedDocs := make(map[string]bool)
+	for _, component := range components {
+		defs := definitions[component]
+		groups := groupDefinitions(defs)
+		if len(groups) < 2 {
+			continue
+		}
+
+		canonical := chooseCanonicalDefinition(component, groups, consistency)
+		for _, group := range groups {
+			entry := strings.Join(definitionFiles(group), ", ")
+			if group[0].fingerprint == canonical.fingerprint {
+				entry += " (canonical)"
+			}
+			result.Conflicts[component] = append(result.Conflicts[component], entry)
+		}
+		if !consistency.Harmonize {
+			continue
+		}
+
+		for _, def := range defs {
+			if def.fingerprint == canonical.fingerprint {
+				continue
+			}
+			path := filepath.Join(dir, filepath.FromSlash(def.file))
+			if missing := missingComponentRefs(canonical.node, docs[def.file].root); len(missing) > 0 {
+				result.SkippedComponents[path] = append(result.SkippedComponents[path], Skip{
+					Message: fmt.Sprintf("%s: the canonical definition references %s, which this file does not define", component, strings.Join(missing, ", ")),
+					Code:    SkipMissingComponentRef,
+				})
+				continue
+			}
+			setMappingValue(def.section, def.key.Value, cloneNode(canonical.node))
+			message := fmt.Sprintf("%s: replaced with the definition from %s", component, canonical.file)
+			result.HarmonizedComponents[path] = append(result.HarmonizedComponents[path], message)
+			result.Locations = append(result.Locations, newChangeLocation(path, StepComponentConsistency, def.key, message))
+			changedDocs[def.file] = true
+		}
+	}
+
+	files := make([]string, 0, len(changedDocs))
+	for file := range changedDocs {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		document := docs[file]
+		if !opts.DryRun {
+			if _, err := writeModifiedDocument(document.doc, document.path); err != nil {
+				return result, fmt.Errorf("error processing %s: %w", document.path, err)
+			}
+		}
+		result.ProcessedFiles = append(result.ProcessedFiles, document.path)
+		result.Changed = true
+	}
+	return result, nil
+}
+
+// loadConsistencyDocuments parses the OpenAPI documents the step processes, keyed by their
+// slash-separated path relative to dir
+func loadConsistencyDocuments(dir string, opts Options) (map[string]*consistencyDocument, error) {
+	docs := make(map[string]*consistencyDocument)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !stepProcessesFile(opts, StepComponentConsistency, dir, path) {
+			return nil
+		}
+		doc, err := loadAndParseDocument(path)
+		if err != nil {
+			return fmt.Errorf("error processing %s: %w", path, err)
+		}
+		root := getRootNode(doc)
+		if !isOpenAPIDocument(root) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		docs[filepath.ToSlash(rel)] = &consistencyDocument{path: path, doc: doc, root: root}
+		return nil
+	})
+	return docs, err
+}
+
+// collectComponentDefinitions returns the definitions of every component by section/name, in file
+// order, and the components defined in more than one file, sorted
+func collectComponentDefinitions(dir string, docs map[string]*consistencyDocument, sections []string, ignoreKeys map[string]bool) (map[string][]componentDefinition, []string) {
+	files := make([]string, 0, len(docs))
+	for file := range docs {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	definitions := make(map[string][]componentDefinition)
+	for _, file := range files {
+		components := getNodeValue(docs[file].root, "components")
+		if components == nil || components.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(components.Content); i += 2 {
+			sectionName, section := components.Content[i].Value, components.Content[i+1]
+			if section.Kind != yaml.MappingNode || (len(sections) > 0 && !contains(sections, sectionName)) {
+				continue
+			}
+			for j := 0; j+1 < len(section.Content); j += 2 {
+				component := sectionName + "/" + section.Content[j].Value
+				definitions[component] = append(definitions[component], componentDefinition{
+					file:        file,
+					section:     section,
+					key:         section.Content[j],
+					node:        section.Content[j+1],
+					fingerprint: componentFingerprint(section.Content[j+1], ignoreKeys),
+				})
+			}
+		}
+	}
+
+	var shared []string
+	for component, defs := range definitions {
+		if len(defs) > 1 {
+			shared = append(shared, component)
+		}
+	}
+	sort.Strings(shared)
+	return definitions, shared
+}
+
+// groupDefinitions groups the definitions of a component by shape, in order of first appearance
+func groupDefinitions(defs []componentDefinition) [][]componentDefinition {
+	var groups [][]componentDefinition
+	index := make(map[string]int)
+	for _, def := range defs {
+		i, ok := index[def.fingerprint]
+		if !ok {
+			i = len(groups)
+			index[def.fingerprint] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], def)
+	}
+	return groups
+}
+
+// chooseCanonicalDefinition picks the definition the others are harmonized to: the one from the
+// file named in canonical, or else by the configured preference
+func chooseCanonicalDefinition(component string, groups [][]componentDefinition, consistency config.ComponentConsistency) componentDefinition {
+	if file, ok := consistency.Canonical[component]; ok {
+		file = filepath.ToSlash(filepath.Clean(file))
+		for _, group := range groups {
+			for _, def := range group {
+				if def.file == file {
+					return def
+				}
+			}
+		}
+	}
+
+	canonical := groups[0]
+	if consistency.Prefer != ConsistencyPreferFirst {
+		for _, group := range groups[1:] {
+			if len(group) > len(canonical) {
+				canonical = group
+			}
+		}
+	}
+	return canonical[0]
+}
+
+// definitionFiles returns the files of a group of definitions
+func definitionFiles(group []componentDefinition) []string {
+	files := make([]string, len(group))
+	for i, def := range group {
+		files[i] = def.file
+	}
+	return files
+}
+
+// missingComponentRefs returns the local component references of node that root does not define
+func missingComponentRefs(node, root *yaml.Node) []string {
+	var missing []string
+	walkRefs(node, func(ref *yaml.Node) {
+		if !strings.HasPrefix(ref.Value, componentsRefPrefix) || contains(missing, ref.Value) {
+			return
+		}
+		if resolvePointer(root, strings.TrimPrefix(ref.Value, "#")) == nil {
+			missing = append(missing, ref.Value)
+		}
+	})
+	return missing
+}
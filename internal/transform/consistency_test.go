@@ -0,0 +1,151 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func writeConsistencySpecs(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	specs := map[string]string{
+		"billing.yaml": `openapi: 3.0.3
+info:
+  title: Billing
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id:
+          type: string
+    Money:
+      type: object
+      description: An amount
+`,
+		"orders.yaml": `openapi: 3.0.3
+info:
+  title: Orders
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    User:
+      type: object
+      description: A customer
+      properties:
+        id:
+          type: integer
+    Money:
+      type: object
+`,
+		"users.yaml": `openapi: 3.0.3
+info:
+  title: Users
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id:
+          type: integer
+        address:
+          $ref: '#/components/schemas/Address'
+    Address:
+      type: object
+`,
+	}
+	for name, content := range specs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestComponentConsistencyReport(t *testing.T) {
+	dir := writeConsistencySpecs(t)
+
+	result, err := ProcessComponentConsistencyInDir(dir, ConsistencyOptions{
+		ComponentConsistency: config.ComponentConsistency{Enabled: true, IgnoreKeys: []string{"description"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed {
+		t.Error("expected the report-only check to leave the files alone")
+	}
+	if len(result.Conflicts) != 1 {
+		t.Fatalf("expected only User to conflict once descriptions are ignored, got %v", result.Conflicts)
+	}
+	want := "billing.yaml (canonical)\norders.yaml\nusers.yaml"
+	if got := strings.Join(result.Conflicts["schemas/User"], "\n"); got != want {
+		t.Errorf("unexpected conflict entries:\n%s", got)
+	}
+}
+
+func TestComponentConsistencyHarmonize(t *testing.T) {
+	dir := writeConsistencySpecs(t)
+
+	result, err := ProcessComponentConsistencyInDir(dir, ConsistencyOptions{
+		ComponentConsistency: config.ComponentConsistency{
+			Enabled:   true,
+			Harmonize: true,
+			Sections:  []string{"schemas"},
+			Canonical: map[string]string{"schemas/User": "users.yaml"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	billing := filepath.Join(dir, "billing.yaml")
+	orders := filepath.Join(dir, "orders.yaml")
+	for file, want := range map[string]string{
+		billing: "schemas/User: the canonical definition references #/components/schemas/Address, which this file does not define",
+		orders:  "schemas/User: the canonical definition references #/components/schemas/Address, which this file does not define",
+	} {
+		if got := strings.Join(SkipMessages(result.SkippedComponents[file]), "\n"); got != want {
+			t.Errorf("%s: expected skip %q, got %q", file, want, got)
+		}
+	}
+
+	// Money differs only by description and is harmonized to the first file's definition on a tie
+	if got := strings.Join(result.HarmonizedComponents[orders], "\n"); got != "schemas/Money: replaced with the definition from billing.yaml" {
+		t.Errorf("unexpected harmonized components %q", got)
+	}
+	if len(result.ProcessedFiles) != 1 || result.ProcessedFiles[0] != orders || len(result.Locations) != 1 {
+		t.Errorf("expected only orders.yaml to change, got %v", result.ProcessedFiles)
+	}
+	data, err := os.ReadFile(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "description: An amount") {
+		t.Errorf("expected Money to be replaced, got:\n%s", data)
+	}
+}
+
+func TestValidateComponentConsistency(t *testing.T) {
+	valid := config.ComponentConsistency{Prefer: ConsistencyPreferFirst, Canonical: map[string]string{"schemas/User": "users.yaml"}}
+	if err := ValidateComponentConsistency(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, consistency := range []config.ComponentConsistency{
+		{Prefer: "largest"},
+		{Canonical: map[string]string{"User": "users.yaml"}},
+		{Canonical: map[string]string{"schemas/User": ""}},
+	} {
+		if err := ValidateComponentConsistency(consistency); err == nil {
+			t.Errorf("expected an error for %+v", consistency)
+		}
+	}
+}
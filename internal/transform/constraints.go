@@ -0,0 +1,356 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Schema forms accepted by settings that rewrite schemas for an OpenAPI version, such as
+// schema_constraints.exclusive_bounds
+const (
+	SchemaFormAuto = "auto" // match each document's OpenAPI version
+	SchemaForm30   = "3.0"  // e.g. minimum: 5, exclusiveMinimum: true
+	SchemaForm31   = "3.1"  // e.g. exclusiveMinimum: 5
+)
+
+// ConstraintsOptions extends the regular Options with schema constraint settings
+type ConstraintsOptions struct {
+	Options
+	SchemaConstraints config.SchemaConstraints
+	Providers         []string // vendor providers applied in this run, for rules limited to providers
+}
+
+// ConstraintsResult represents the result of schema constraint normalization
+type ConstraintsResult struct {
+	Changed               bool
+	ProcessedFiles        []string
+	NormalizedConstraints map[string][]string // file -> list of constraint changes
+	Locations             []ChangeLocation    // source positions of the changed schemas
+}
+
+// createConstraintsResult creates a new ConstraintsResult with initialized maps
+func createConstraintsResult() *ConstraintsResult {
+	return &ConstraintsResult{
+		ProcessedFiles:        []string{},
+		NormalizedConstraints: make(map[string][]string),
+	}
+}
+
+// setConstraintsProcessedFiles sets the processed files for a ConstraintsResult
+func setConstraintsProcessedFiles(result *ConstraintsResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setConstraintsChanged sets the changed flag for a ConstraintsResult
+func setConstraintsChanged(result *ConstraintsResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessConstraintsInDir normalizes schema constraints in all OpenAPI files in a directory
+func ProcessConstraintsInDir(dir string, opts ConstraintsOptions) (*ConstraintsResult, error) {
+	if err := ValidateSchemaConstraints(opts.SchemaConstraints); err != nil {
+		return createConstraintsResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepSchemaConstraints,
+		opts.Options,
+		opts.SchemaConstraints.Enabled,
+		len(opts.SchemaConstraints.Rules) == 0 && opts.SchemaConstraints.ExclusiveBounds == "",
+		createConstraintsResult,
+		func(path string, result *ConstraintsResult) (bool, error) {
+			return processConstraintsInFile(path, opts, result)
+		},
+		setConstraintsProcessedFiles,
+		setConstraintsChanged,
+	)
+}
+
+// ValidateSchemaConstraints checks the exclusive bounds form and that every rule does something
+func ValidateSchemaConstraints(constraints config.SchemaConstraints) error {
+	switch constraints.ExclusiveBounds {
+	case "", SchemaFormAuto, SchemaForm30, SchemaForm31:
+	default:
+		return fmt.Errorf("schema_constraints.exclusive_bounds must be %s, %s or %s, got %q",
+			SchemaFormAuto, SchemaForm30, SchemaForm31, constraints.ExclusiveBounds)
+	}
+	for _, name := range sortedConstraintRuleNames(constraints.Rules) {
+		rule := constraints.Rules[name]
+		if len(rule.Set) == 0 && len(rule.Strip) == 0 {
+			return fmt.Errorf("schema_constraints.rules.%s: set or strip is required", name)
+		}
+		for key, value := range rule.Set {
+			if createDefaultValueNode(value) == nil {
+				return fmt.Errorf("schema_constraints.rules.%s.set.%s: unsupported value %v", name, key, value)
+			}
+		}
+	}
+	return nil
+}
+
+// processConstraintsInFile normalizes schema constraints in a single file
+func processConstraintsInFile(path string, opts ConstraintsOptions, result *ConstraintsResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	n := &constraintNormalizer{
+		rules:  activeConstraintRules(opts.SchemaConstraints.Rules, opts.Providers),
+		bounds: schemaForm(root, opts.SchemaConstraints.ExclusiveBounds),
+		path:   path,
+		result: result,
+	}
+	if !n.normalizeDocument(root) {
+		return false, nil
+	}
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// constraintRuleEntry is a named constraint rule
+type constraintRuleEntry struct {
+	name string
+	rule config.ConstraintRule
+}
+
+// activeConstraintRules returns the rules that apply with the given vendor providers, highest
+// priority first and by name within a priority
+func activeConstraintRules(rules map[string]config.ConstraintRule, providers []string) []constraintRuleEntry {
+	var active []constraintRuleEntry
+	for _, name := range sortedConstraintRuleNames(rules) {
+		rule := rules[name]
+		if len(rule.Providers) > 0 && !anyProviderApplied(rule.Providers, providers) {
+			continue
+		}
+		active = append(active, constraintRuleEntry{name: name, rule: rule})
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].rule.Priority > active[j].rule.Priority
+	})
+	return active
+}
+
+func anyProviderApplied(ruleProviders, providers []string) bool {
+	for _, provider := range ruleProviders {
+		if contains(providers, provider) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedConstraintRuleNames(rules map[string]config.ConstraintRule) []string {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// schemaForm resolves a configured schema form for a document: "auto" picks the 3.1 form for
+// OpenAPI 3.1 and later documents and the 3.0 form for 3.0 and Swagger 2.0
+func schemaForm(root *yaml.Node, configured string) string {
+	if configured != SchemaFormAuto {
+		return configured
+	}
+	if version := getStringValue(root, "openapi"); version != "" && !strings.HasPrefix(version, "3.0") {
+		return SchemaForm31
+	}
+	return SchemaForm30
+}
+
+// constraintNormalizer applies constraint rules to the schemas of one document
+type constraintNormalizer struct {
+	rules  []constraintRuleEntry
+	bounds string
+	path   string
+	result *ConstraintsResult
+}
+
+// normalizeDocument normalizes the schemas of every operation and component
+func (n *constraintNormalizer) normalizeDocument(root *yaml.Node) bool {
+	return walkDocumentSchemas(root, n.normalizeSchema)
+}
+
+// normalizeSchema applies the matching rules and the exclusive bounds form to a schema
+func (n *constraintNormalizer) normalizeSchema(schema *yaml.Node, target schemaTarget) bool {
+	changed := false
+	for _, entry := range n.rules {
+		if matchesConstraintCondition(schema, target, entry.rule.Condition) && n.applyRule(schema, target, entry) {
+			changed = true
+		}
+	}
+	if n.convertExclusiveBounds(schema, target) {
+		changed = true
+	}
+	return changed
+}
+
+// matchesConstraintCondition reports whether a schema matches a rule condition. Path, method, tag
+// and extension conditions only match schemas inside operations; a missing_extension condition
+// too, since only operations can be checked for it.
+func matchesConstraintCondition(schema *yaml.Node, target schemaTarget, condition config.DefaultCondition) bool {
+	if len(condition.PathPatterns) > 0 && (target.pathName == "" || !matchesPathPattern(target.pathName, condition.PathPatterns)) {
+		return false
+	}
+	if len(condition.HTTPMethods) > 0 && (target.method == "" || !matchesHTTPMethod(target.method, condition.HTTPMethods)) {
+		return false
+	}
+	if hasOperationFlags(condition) &&
+		(target.operation == nil || !defaultRuleCondition(config.DefaultRule{Condition: condition}).Matches(target.pathName, target.method, target.operation)) {
+		return false
+	}
+	if condition.ParameterIn != "" && target.parameterIn != condition.ParameterIn {
+		return false
+	}
+	if condition.PropertyName != "" && (target.name == "" || !matchesPropertyName(target.name, condition.PropertyName)) {
+		return false
+	}
+	if condition.Type != "" && !hasSchemaType(schema, condition.Type) {
+		return false
+	}
+	if condition.Format != "" && getStringValue(schema, "format") != condition.Format {
+		return false
+	}
+	if condition.HasEnum && getNodeValue(schema, "enum") == nil {
+		return false
+	}
+	if condition.IsArray && !hasSchemaType(schema, "array") {
+		return false
+	}
+	if condition.Required != nil && *condition.Required != target.required {
+		return false
+	}
+	return true
+}
+
+// hasSchemaType reports whether a schema's type is want, including OpenAPI 3.1 type lists
+func hasSchemaType(schema *yaml.Node, want string) bool {
+	typeNode := getNodeValue(schema, "type")
+	if typeNode == nil {
+		return false
+	}
+	if typeNode.Kind == yaml.SequenceNode {
+		for _, item := range typeNode.Content {
+			if item.Value == want {
+				return true
+			}
+		}
+		return false
+	}
+	return typeNode.Value == want
+}
+
+// applyRule strips the rule's constraints from a schema and sets the ones it is missing
+func (n *constraintNormalizer) applyRule(schema *yaml.Node, target schemaTarget, entry constraintRuleEntry) bool {
+	changed := false
+	for _, key := range entry.rule.Strip {
+		if getNodeValue(schema, key) == nil {
+			continue
+		}
+		removeMappingKey(schema, key)
+		n.record(schema, fmt.Sprintf("%s: removed %s (rule: %s)", target.context, key, entry.name))
+		changed = true
+	}
+
+	keys := make([]string, 0, len(entry.rule.Set))
+	for key := range entry.rule.Set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if getNodeValue(schema, key) != nil {
+			continue
+		}
+		value := entry.rule.Set[key]
+		schema.Content = append(schema.Content, newScalarNode(key), createDefaultValueNode(value))
+		n.record(schema, fmt.Sprintf("%s: set %s = %v (rule: %s)", target.context, key, value, entry.name))
+		changed = true
+	}
+	return changed
+}
+
+// convertExclusiveBounds rewrites exclusiveMinimum and exclusiveMaximum into the configured form
+func (n *constraintNormalizer) convertExclusiveBounds(schema *yaml.Node, target schemaTarget) bool {
+	changed := false
+	for _, bound := range []struct {
+		exclusive, inclusive string
+		lower                bool
+	}{
+		{"exclusiveMinimum", "minimum", true},
+		{"exclusiveMaximum", "maximum", false},
+	} {
+		exclusive := getNodeValue(schema, bound.exclusive)
+		if exclusive == nil || exclusive.Kind != yaml.ScalarNode {
+			continue
+		}
+		isBool := exclusive.Value == "true" || exclusive.Value == "false"
+
+		switch {
+		case n.bounds == SchemaForm31 && isBool:
+			// minimum: 5, exclusiveMinimum: true -> exclusiveMinimum: 5
+			inclusive := getNodeValue(schema, bound.inclusive)
+			if exclusive.Value == "true" && inclusive != nil {
+				setMappingValue(schema, bound.exclusive, inclusive)
+				removeMappingKey(schema, bound.inclusive)
+			} else {
+				removeMappingKey(schema, bound.exclusive)
+			}
+		case n.bounds == SchemaForm30 && !isBool:
+			// exclusiveMinimum: 5 -> minimum: 5, exclusiveMinimum: true. An inclusive bound that is
+			// already stricter makes the exclusive one redundant.
+			limit, err := strconv.ParseFloat(exclusive.Value, 64)
+			if err != nil {
+				continue
+			}
+			inclusive := getNodeValue(schema, bound.inclusive)
+			if inclusive != nil {
+				current, err := strconv.ParseFloat(inclusive.Value, 64)
+				if err == nil && ((bound.lower && current > limit) || (!bound.lower && current < limit)) {
+					removeMappingKey(schema, bound.exclusive)
+					break
+				}
+			}
+			flag := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}
+			if inclusive != nil {
+				setMappingValue(schema, bound.inclusive, exclusive)
+				setMappingValue(schema, bound.exclusive, flag)
+				break
+			}
+			// Keep the bound where it was: the exclusive key becomes the inclusive one, followed by the flag
+			for i := 0; i+1 < len(schema.Content); i += 2 {
+				if schema.Content[i].Value == bound.exclusive {
+					schema.Content[i].Value = bound.inclusive
+					rest := append([]*yaml.Node{newScalarNode(bound.exclusive), flag}, schema.Content[i+2:]...)
+					schema.Content = append(schema.Content[:i+2], rest...)
+					break
+				}
+			}
+		default:
+			continue
+		}
+		n.record(schema, fmt.Sprintf("%s: %s converted to OpenAPI %s form", target.context, bound.exclusive, n.bounds))
+		changed = true
+	}
+	return changed
+}
+
+// record adds a constraint change to the result
+func (n *constraintNormalizer) record(schema *yaml.Node, entry string) {
+	n.result.NormalizedConstraints[n.path] = append(n.result.NormalizedConstraints[n.path], entry)
+	n.result.Locations = append(n.result.Locations, newChangeLocation(n.path, StepSchemaConstraints, schema, entry))
+}
@@ -0,0 +1,130 @@
+package transform
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const constraintsTestSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 0
+            exclusiveMinimum: true
+            maximum: 100
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          type: string
+          pattern: "^[a-z]+$"
+        email:
+          type: string
+          format: email
+          maxLength: 320
+        age:
+          type: integer
+          exclusiveMaximum: 150
+`
+
+func constraintsTestConfig() config.SchemaConstraints {
+	return config.SchemaConstraints{
+		Enabled:         true,
+		ExclusiveBounds: SchemaFormAuto,
+		Rules: map[string]config.ConstraintRule{
+			"bounded_strings": {
+				Condition: config.DefaultCondition{Type: "string"},
+				Set:       map[string]interface{}{"maxLength": 255},
+			},
+			"emails": {
+				Condition: config.DefaultCondition{Type: "string", Format: "email"},
+				Set:       map[string]interface{}{"maxLength": 254},
+				Priority:  10,
+			},
+			"no_patterns_for_fern": {
+				Providers: []string{"fern"},
+				Strip:     []string{"pattern"},
+			},
+		},
+	}
+}
+
+func TestProcessConstraintsInDir(t *testing.T) {
+	dir, path := writeTestSpec(t, constraintsTestSpec)
+
+	result, err := ProcessConstraintsInDir(dir, ConstraintsOptions{SchemaConstraints: constraintsTestConfig()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed {
+		t.Fatal("expected constraints to be normalized")
+	}
+
+	want := []string{
+		"component schemas.User property name: set maxLength = 255 (rule: bounded_strings)",
+		"component schemas.User property age: exclusiveMaximum converted to OpenAPI 3.0 form",
+	}
+	if got := result.NormalizedConstraints[path]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected changes:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, s := range []string{"maxLength: 320", "pattern: ", "maximum: 150", "exclusiveMaximum: true"} {
+		if !strings.Contains(content, s) {
+			t.Errorf("expected output to contain %q, got:\n%s", s, content)
+		}
+	}
+}
+
+func TestProcessConstraintsProviderRules(t *testing.T) {
+	dir, path := writeTestSpec(t, constraintsTestSpec)
+
+	constraints := constraintsTestConfig()
+	constraints.ExclusiveBounds = SchemaForm31
+	if _, err := ProcessConstraintsInDir(dir, ConstraintsOptions{SchemaConstraints: constraints, Providers: []string{"fern"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if strings.Contains(content, "pattern:") {
+		t.Errorf("expected the fern rule to strip pattern, got:\n%s", content)
+	}
+	if !strings.Contains(content, "exclusiveMinimum: 0") || strings.Contains(content, "minimum: 0") {
+		t.Errorf("expected the 3.1 exclusiveMinimum form, got:\n%s", content)
+	}
+}
+
+func TestValidateSchemaConstraints(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints config.SchemaConstraints
+		want        string
+	}{
+		{"bad bounds form", config.SchemaConstraints{ExclusiveBounds: "3.2"}, "exclusive_bounds must be"},
+		{"empty rule", config.SchemaConstraints{Rules: map[string]config.ConstraintRule{"noop": {}}}, "set or strip is required"},
+	}
+	for _, tt := range tests {
+		err := ValidateSchemaConstraints(tt.constraints)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package transform
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jsonNumberPattern matches the numbers JSON accepts as they are
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+// ConvertDocument converts a YAML or JSON document to format (yaml or json). Key order, numbers,
+// booleans and strings are kept exactly: the result is parsed again and compared with the input,
+// and the conversion fails rather than return a document that reads differently.
+func ConvertDocument(data []byte, format string) ([]byte, error) {
+	if format != OutputFormatYAML && format != OutputFormatJSON {
+		return nil, fmt.Errorf("format must be %s or %s, got %q", OutputFormatYAML, OutputFormatJSON, format)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML/JSON: %w", err)
+	}
+	if doc.Kind == 0 {
+		return nil, errors.New("document is empty")
+	}
+	if err := checkConvertible(&doc, ""); err != nil {
+		return nil, err
+	}
+
+	var output []byte
+	var err error
+	if format == OutputFormatJSON {
+		output, err = formatAsJSON(&doc)
+		output = append(output, '\n')
+	} else {
+		if isJSONDocument(data) {
+			clearStyles(&doc)
+		}
+		output, err = formatAsYAML(&doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRoundTrip(&doc, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// isJSONDocument reports whether data is JSON rather than YAML
+func isJSONDocument(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// checkConvertible reports the content a JSON document cannot hold or a converted document would
+// read differently: merge keys and non-finite numbers
+func checkConvertible(node *yaml.Node, path string) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.ShortTag() == "!!merge" {
+				return fmt.Errorf("%s: merge keys (<<) are not supported", displayPath(path))
+			}
+			if err := checkConvertible(node.Content[i+1], path+"/"+escapePointerToken(key.Value)); err != nil {
+				return err
+			}
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for i, child := range node.Content {
+			childPath := path
+			if node.Kind == yaml.SequenceNode {
+				childPath = fmt.Sprintf("%s/%d", path, i)
+			}
+			if err := checkConvertible(child, childPath); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!float" {
+			if _, err := jsonFloat(node.Value); err != nil {
+				return fmt.Errorf("%s: %v", displayPath(path), err)
+			}
+		}
+	}
+	return nil
+}
+
+// displayPath returns a JSON pointer for messages, with / for the root
+func displayPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// escapePointerToken escapes a key for use in a JSON pointer
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// checkRoundTrip parses the converted document and compares it with the original node by node
+func checkRoundTrip(original *yaml.Node, converted []byte) error {
+	var after yaml.Node
+	if err := yaml.Unmarshal(converted, &after); err != nil {
+		return fmt.Errorf("round-trip check failed: converted document does not parse: %v", err)
+	}
+	if problem := compareNodes(original, &after, ""); problem != "" {
+		return fmt.Errorf("round-trip check failed: %s", problem)
+	}
+	return nil
+}
+
+// compareNodes returns how b differs from a, or "" when they hold the same data in the same order
+func compareNodes(a, b *yaml.Node, path string) string {
+	if a.Kind == yaml.AliasNode {
+		return compareNodes(a.Alias, b, path)
+	}
+	if b.Kind == yaml.AliasNode {
+		return compareNodes(a, b.Alias, path)
+	}
+	if a.Kind != b.Kind {
+		return fmt.Sprintf("%s changed kind", displayPath(path))
+	}
+
+	switch a.Kind {
+	case yaml.ScalarNode:
+		aTag, aValue := canonicalScalar(a)
+		bTag, bValue := canonicalScalar(b)
+		if aTag != bTag || aValue != bValue {
+			return fmt.Sprintf("%s changed from %s %q to %s %q", displayPath(path), aTag, a.Value, bTag, b.Value)
+		}
+	case yaml.MappingNode:
+		if len(a.Content) != len(b.Content) {
+			return fmt.Sprintf("%s changed its keys", displayPath(path))
+		}
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			if a.Content[i].Value != b.Content[i].Value {
+				return fmt.Sprintf("%s: key %q became %q", displayPath(path), a.Content[i].Value, b.Content[i].Value)
+			}
+			if problem := compareNodes(a.Content[i+1], b.Content[i+1], path+"/"+escapePointerToken(a.Content[i].Value)); problem != "" {
+				return problem
+			}
+		}
+	default:
+		if len(a.Content) != len(b.Content) {
+			return fmt.Sprintf("%s changed its length", displayPath(path))
+		}
+		for i := range a.Content {
+			childPath := path
+			if a.Kind == yaml.SequenceNode {
+				childPath = fmt.Sprintf("%s/%d", path, i)
+			}
+			if problem := compareNodes(a.Content[i], b.Content[i], childPath); problem != "" {
+				return problem
+			}
+		}
+	}
+	return ""
+}
+
+// canonicalScalar returns the resolved type and value of a scalar, so that spellings of the same
+// value compare equal. Timestamps and custom tags are read as the strings they are in JSON.
+func canonicalScalar(node *yaml.Node) (string, string) {
+	switch tag := node.ShortTag(); tag {
+	case "!!null":
+		return tag, "null"
+	case "!!bool":
+		return tag, strings.ToLower(node.Value)
+	case "!!int":
+		if value, err := canonicalInt(node.Value); err == nil {
+			return tag, value
+		}
+		return tag, node.Value
+	case "!!float":
+		if f, err := strconv.ParseFloat(strings.ReplaceAll(node.Value, "_", ""), 64); err == nil {
+			return tag, strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		return tag, strings.ToLower(node.Value)
+	default:
+		return "!!str", node.Value
+	}
+}
+
+// canonicalInt returns a YAML integer in decimal, keeping integers of any size exact
+func canonicalInt(value string) (string, error) {
+	digits := strings.ReplaceAll(value, "_", "")
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimLeft(digits, "+-")
+
+	base := 10
+	if len(digits) > 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+		if base != 10 {
+			digits = digits[2:]
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return "", fmt.Errorf("invalid integer %q", value)
+	}
+	if negative {
+		n.Neg(n)
+	}
+	return n.String(), nil
+}
+
+// jsonFloat returns a YAML float as a JSON number, verbatim when JSON accepts it as written. A
+// float keeps a fraction or exponent, so it is not read back as an integer.
+func jsonFloat(value string) (string, error) {
+	if jsonNumberPattern.MatchString(value) {
+		return value, nil
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(value, "_", ""), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("%s cannot be represented in JSON", value)
+	}
+	number := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(number, ".e") {
+		number += ".0"
+	}
+	return number, nil
+}
@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		format  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "yaml to json keeps order and values",
+			input:  "b: 0x1F\na: True\nc: 1_000\nd: 1.\ne: \"007\"\nf: ~\n",
+			format: OutputFormatJSON,
+			want:   "{\n  \"b\": 31,\n  \"a\": true,\n  \"c\": 1000,\n  \"d\": 1.0,\n  \"e\": \"007\",\n  \"f\": null\n}\n",
+		},
+		{
+			name:   "json to yaml quotes ambiguous strings",
+			input:  `{"z": "yes", "a": "on", "n": 1.50, "s": "123", "k": {"200": "OK"}}`,
+			format: OutputFormatYAML,
+			want:   "z: \"yes\"\na: \"on\"\n\"n\": 1.50\ns: \"123\"\nk:\n    \"200\": OK\n",
+		},
+		{
+			name:   "long strings are not folded",
+			input:  `{"description": "` + strings.Repeat("word ", 40) + `end"}`,
+			format: OutputFormatYAML,
+			want:   "description: " + strings.Repeat("word ", 40) + "end\n",
+		},
+		{
+			name:   "aliases are expanded in json",
+			input:  "a: &x {k: v}\nb: *x\n",
+			format: OutputFormatJSON,
+			want:   "{\n  \"a\": {\n    \"k\": \"v\"\n  },\n  \"b\": {\n    \"k\": \"v\"\n  }\n}\n",
+		},
+		{
+			name:    "non-finite numbers",
+			input:   "a:\n  b: .inf\n",
+			format:  OutputFormatJSON,
+			wantErr: "/a/b: .inf cannot be represented in JSON",
+		},
+		{
+			name:    "merge keys",
+			input:   "base: &b {k: v}\nitem:\n  <<: *b\n",
+			format:  OutputFormatJSON,
+			wantErr: "/item: merge keys (<<) are not supported",
+		},
+		{
+			name:    "unknown format",
+			input:   "a: 1\n",
+			format:  "toml",
+			wantErr: `format must be yaml or json, got "toml"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := ConvertDocument([]byte(tt.input), tt.format)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(output) != tt.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestCanonicalInt(t *testing.T) {
+	tests := map[string]string{
+		"0x1F":                  "31",
+		"0o17":                  "15",
+		"0b101":                 "5",
+		"-1_000":                "-1000",
+		"+12":                   "12",
+		"123456789012345678901": "123456789012345678901",
+	}
+	for input, want := range tests {
+		got, err := canonicalInt(input)
+		if err != nil || got != want {
+			t.Errorf("canonicalInt(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+}
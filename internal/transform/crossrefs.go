@@ -0,0 +1,260 @@
+package transform
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// operationTargets are the operationIds and operation pointers (such as /paths/~1users/get) a
+// link can name
+type operationTargets struct {
+	ids      map[string]bool
+	pointers map[string]bool
+}
+
+// collectOperationTargets returns the operations of the paths and webhooks of a document
+func collectOperationTargets(root *yaml.Node) operationTargets {
+	targets := operationTargets{ids: make(map[string]bool), pointers: make(map[string]bool)}
+	for _, section := range []string{"paths", "webhooks"} {
+		items := getNodeValue(root, section)
+		if items == nil || items.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(items.Content); i += 2 {
+			pathItem := items.Content[i+1]
+			for j := 0; j+1 < len(pathItem.Content); j += 2 {
+				method := pathItem.Content[j].Value
+				if !isHTTPMethod(method) {
+					continue
+				}
+				targets.pointers["/"+section+"/"+escapeJSONPointer(items.Content[i].Value)+"/"+method] = true
+				if id := getStringValue(pathItem.Content[j+1], "operationId"); id != "" {
+					targets.ids[id] = true
+				}
+			}
+		}
+	}
+	return targets
+}
+
+// crossRefCleaner removes what refers to operations or security schemes that were removed
+type crossRefCleaner struct {
+	before         operationTargets
+	after          operationTargets
+	removedSchemes map[string]bool // names of removed security schemes
+	removed        []string        // human-readable secondary removals
+}
+
+// removeDanglingCrossReferences cleans up after operations, path items or security schemes were
+// removed from a document: security requirements naming a removed scheme, links to an operation
+// that is gone, callback expressions left without operations and servers lists left empty. before
+// holds the operations of the document as it was. It returns the secondary removals.
+func removeDanglingCrossReferences(root *yaml.Node, before operationTargets, removedSchemes map[string]bool) []string {
+	c := &crossRefCleaner{before: before, after: collectOperationTargets(root), removedSchemes: removedSchemes}
+
+	c.cleanSecurity(root, "document")
+	c.cleanServers(root, "document")
+	for _, section := range []string{"paths", "webhooks"} {
+		if items := getNodeValue(root, section); items != nil && items.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(items.Content); i += 2 {
+				c.cleanPathItem(items.Content[i+1], items.Content[i].Value)
+			}
+		}
+	}
+
+	components := getNodeValue(root, "components")
+	if responses := getNodeValue(components, "responses"); responses != nil && responses.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(responses.Content); i += 2 {
+			c.cleanLinks(responses.Content[i+1], "component "+componentKey("responses", responses.Content[i].Value))
+		}
+	}
+	if callbacks := getNodeValue(components, "callbacks"); callbacks != nil && callbacks.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(callbacks.Content); i += 2 {
+			c.cleanCallback(callbacks.Content[i+1], "component "+componentKey("callbacks", callbacks.Content[i].Value))
+		}
+	}
+
+	return c.removed
+}
+
+// cleanPathItem cleans a path item and its operations
+func (c *crossRefCleaner) cleanPathItem(pathItem *yaml.Node, path string) {
+	if pathItem == nil || pathItem.Kind != yaml.MappingNode {
+		return
+	}
+
+	c.cleanServers(pathItem, path)
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		method, operation := pathItem.Content[i].Value, pathItem.Content[i+1]
+		if !isHTTPMethod(method) || operation.Kind != yaml.MappingNode {
+			continue
+		}
+
+		context := strings.ToUpper(method) + " " + path
+		c.cleanSecurity(operation, context)
+		c.cleanServers(operation, context)
+		if responses := getNodeValue(operation, "responses"); responses != nil && responses.Kind == yaml.MappingNode {
+			for j := 0; j+1 < len(responses.Content); j += 2 {
+				c.cleanLinks(responses.Content[j+1], context+" response "+responses.Content[j].Value)
+			}
+		}
+		c.cleanCallbacks(operation, context)
+	}
+}
+
+// cleanSecurity removes security requirements naming a removed scheme, dropping the list when
+// none are left so the operation falls back to the document's requirements
+func (c *crossRefCleaner) cleanSecurity(node *yaml.Node, context string) {
+	security := getNodeValue(node, "security")
+	if security == nil || security.Kind != yaml.SequenceNode || len(c.removedSchemes) == 0 {
+		return
+	}
+
+	var kept []*yaml.Node
+	for _, requirement := range security.Content {
+		var schemes []string
+		for i := 0; requirement.Kind == yaml.MappingNode && i+1 < len(requirement.Content); i += 2 {
+			if c.removedSchemes[requirement.Content[i].Value] {
+				schemes = append(schemes, requirement.Content[i].Value)
+			}
+		}
+		if len(schemes) > 0 {
+			c.record("security requirement %s (%s)", strings.Join(schemes, ", "), context)
+			continue
+		}
+		kept = append(kept, requirement)
+	}
+	if len(kept) == len(security.Content) {
+		return
+	}
+	security.Content = kept
+
+	if len(kept) == 0 {
+		removeMappingKey(node, "security")
+	}
+}
+
+// cleanServers removes a servers list that was left empty
+func (c *crossRefCleaner) cleanServers(node *yaml.Node, context string) {
+	servers := getNodeValue(node, "servers")
+	if servers != nil && servers.Kind == yaml.SequenceNode && len(servers.Content) == 0 {
+		removeMappingKey(node, "servers")
+		c.record("empty servers (%s)", context)
+	}
+}
+
+// cleanLinks removes the links of a response that point to a removed operation
+func (c *crossRefCleaner) cleanLinks(response *yaml.Node, context string) {
+	links := getNodeValue(response, "links")
+	if links == nil || links.Kind != yaml.MappingNode {
+		return
+	}
+
+	var kept []*yaml.Node
+	for i := 0; i+1 < len(links.Content); i += 2 {
+		name, link := links.Content[i].Value, links.Content[i+1]
+		if c.linkTargetRemoved(link) {
+			c.record("link %s (%s)", name, context)
+			continue
+		}
+		kept = append(kept, links.Content[i], link)
+	}
+	if len(kept) == len(links.Content) {
+		return
+	}
+	links.Content = kept
+
+	if len(kept) == 0 {
+		removeMappingKey(response, "links")
+	}
+}
+
+// linkTargetRemoved reports whether a link names an operation that existed before and is gone
+func (c *crossRefCleaner) linkTargetRemoved(link *yaml.Node) bool {
+	if id := getStringValue(link, "operationId"); id != "" {
+		return c.before.ids[id] && !c.after.ids[id]
+	}
+
+	ref, ok := strings.CutPrefix(getStringValue(link, "operationRef"), "#")
+	if !ok {
+		return false
+	}
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+	return c.before.pointers[ref] && !c.after.pointers[ref]
+}
+
+// cleanCallbacks cleans the callbacks of an operation, dropping callbacks left without expressions
+func (c *crossRefCleaner) cleanCallbacks(operation *yaml.Node, context string) {
+	callbacks := getNodeValue(operation, "callbacks")
+	if callbacks == nil || callbacks.Kind != yaml.MappingNode {
+		return
+	}
+
+	var kept []*yaml.Node
+	for i := 0; i+1 < len(callbacks.Content); i += 2 {
+		name, callback := callbacks.Content[i].Value, callbacks.Content[i+1]
+		callbackContext := "callback " + name + " of " + context
+		if c.cleanCallback(callback, callbackContext) {
+			c.record("%s", callbackContext)
+			continue
+		}
+		kept = append(kept, callbacks.Content[i], callback)
+	}
+	if len(kept) == len(callbacks.Content) {
+		return
+	}
+	callbacks.Content = kept
+
+	if len(kept) == 0 {
+		removeMappingKey(operation, "callbacks")
+	}
+}
+
+// cleanCallback removes the expressions of a callback whose path items have no operations left
+// and cleans the remaining ones. It reports whether the callback had expressions and lost them all.
+func (c *crossRefCleaner) cleanCallback(callback *yaml.Node, context string) bool {
+	if callback == nil || callback.Kind != yaml.MappingNode || getNodeValue(callback, "$ref") != nil {
+		return false
+	}
+
+	var kept []*yaml.Node
+	for i := 0; i+1 < len(callback.Content); i += 2 {
+		expression, pathItem := callback.Content[i].Value, callback.Content[i+1]
+		if strings.HasPrefix(expression, "x-") {
+			kept = append(kept, callback.Content[i], pathItem)
+			continue
+		}
+		if !hasOperations(pathItem) {
+			c.record("callback expression %s (%s)", expression, context)
+			continue
+		}
+		c.cleanPathItem(pathItem, expression)
+		kept = append(kept, callback.Content[i], pathItem)
+	}
+	emptied := len(kept) < len(callback.Content) && len(kept) == 0
+	callback.Content = kept
+	return emptied
+}
+
+// hasOperations reports whether a path item has an operation or is a reference
+func hasOperations(pathItem *yaml.Node) bool {
+	if pathItem == nil || pathItem.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		if key := pathItem.Content[i].Value; key == "$ref" || isHTTPMethod(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// record records a secondary removal
+func (c *crossRefCleaner) record(format string, args ...interface{}) {
+	c.removed = append(c.removed, fmt.Sprintf(format, args...))
+}
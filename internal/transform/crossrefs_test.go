@@ -0,0 +1,155 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const crossRefsTestSpec = `openapi: 3.1.0
+info:
+  title: Test API
+  version: 1.0.0
+security:
+  - apiKey: []
+  - adminToken: []
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      security:
+        - adminToken: []
+      servers:
+        - url: https://admin.example.com
+          x-internal: true
+      responses:
+        "200":
+          description: OK
+          links:
+            purge:
+              operationId: purgeUsers
+            next:
+              operationId: listUsers
+    post:
+      operationId: createUser
+      callbacks:
+        onAudit:
+          '{$request.body#/auditUrl}':
+            post:
+              x-internal: true
+              responses:
+                "200":
+                  description: OK
+      responses:
+        "201":
+          description: Created
+          links:
+            purgeAll:
+              operationRef: '#/paths/~1admin~1purge/delete'
+  /admin/purge:
+    delete:
+      operationId: purgeUsers
+      x-internal: true
+      responses:
+        "204":
+          description: Purged
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    adminToken:
+      type: http
+      scheme: bearer
+      x-internal: true
+`
+
+func TestStripInternalRemovesDanglingCrossReferences(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(crossRefsTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessStripInternalInDir(dir, InternalOptions{StripInternal: config.StripInternal{Enabled: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondary := strings.Join(result.SecondaryRemovals[path], "\n")
+	for _, want := range []string{
+		"security requirement adminToken (document)",
+		"security requirement adminToken (GET /users)",
+		"empty servers (GET /users)",
+		"link purge (GET /users response 200)",
+		"link purgeAll (POST /users response 201)",
+		"callback expression {$request.body#/auditUrl} (callback onAudit of POST /users)",
+		"callback onAudit of POST /users",
+	} {
+		if !strings.Contains(secondary, want) {
+			t.Errorf("expected secondary removals to contain %q, got:\n%s", want, secondary)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Security []map[string]interface{}
+		Paths    map[string]map[string]map[string]interface{}
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Security) != 1 || doc.Security[0]["apiKey"] == nil {
+		t.Errorf("expected only the apiKey requirement to remain, got %v", doc.Security)
+	}
+	list := doc.Paths["/users"]["get"]
+	for _, key := range []string{"security", "servers"} {
+		if _, ok := list[key]; ok {
+			t.Errorf("expected %s to be removed from GET /users, got %v", key, list[key])
+		}
+	}
+	links := list["responses"].(map[string]interface{})["200"].(map[string]interface{})["links"].(map[string]interface{})
+	if _, ok := links["next"]; !ok || len(links) != 1 {
+		t.Errorf("expected only the link to the kept operation to remain, got %v", links)
+	}
+	create := doc.Paths["/users"]["post"]
+	if _, ok := create["callbacks"]; ok {
+		t.Errorf("expected the emptied callbacks to be removed, got %v", create["callbacks"])
+	}
+	if _, ok := create["responses"].(map[string]interface{})["201"].(map[string]interface{})["links"]; ok {
+		t.Error("expected the emptied links to be removed")
+	}
+}
+
+func TestExtractSubsetRemovesDanglingLinks(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(input, []byte(crossRefsTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, result, err := ExtractSubsetBytes(input, ExtractOptions{OperationIDs: []string{"listUsers"}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(result.SecondaryRemovals, "\n"); got != "link purge (GET /users response 200)" {
+		t.Errorf("expected the link to the removed operation to be reported, got %q", got)
+	}
+	if strings.Contains(string(output), "purgeUsers") {
+		t.Errorf("expected no reference to the removed operation, got:\n%s", output)
+	}
+	if !strings.Contains(string(output), "operationId: listUsers") {
+		t.Errorf("expected the link to the kept operation to remain, got:\n%s", output)
+	}
+}
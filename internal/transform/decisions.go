@@ -0,0 +1,60 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// PaginationDecision is the strategy the pagination priority list selected for an operation
+type PaginationDecision struct {
+	File     string
+	Path     string
+	Method   string // lowercase, as in the document
+	Strategy string
+}
+
+// Operation returns the operation as "GET /users"
+func (d PaginationDecision) Operation() string {
+	return strings.ToUpper(d.Method) + " " + d.Path
+}
+
+// DecisionRules turns the decisions into endpoint rules naming each operation exactly, in the order
+// the operations were processed. An operation decided differently in several files gets no rule:
+// it is reported as a conflict instead, as "GET /users: cursor in a.yaml, page in b.yaml".
+func DecisionRules(decisions []PaginationDecision) ([]config.EndpointPaginationRule, []string) {
+	var order []string
+	byOperation := make(map[string][]PaginationDecision)
+	for _, decision := range decisions {
+		key := decision.Operation()
+		if _, ok := byOperation[key]; !ok {
+			order = append(order, key)
+		}
+		byOperation[key] = append(byOperation[key], decision)
+	}
+
+	var rules []config.EndpointPaginationRule
+	var conflicts []string
+	for _, key := range order {
+		decided := byOperation[key]
+		strategies := make(map[string][]string)
+		for _, decision := range decided {
+			strategies[decision.Strategy] = append(strategies[decision.Strategy], decision.File)
+		}
+		if len(strategies) > 1 {
+			var found []string
+			for _, strategy := range sortedKeysOf(strategies) {
+				found = append(found, fmt.Sprintf("%s in %s", strategy, strings.Join(strategies[strategy], ", ")))
+			}
+			conflicts = append(conflicts, key+": "+strings.Join(found, ", "))
+			continue
+		}
+		rules = append(rules, config.EndpointPaginationRule{
+			Endpoint:   decided[0].Path,
+			Method:     strings.ToUpper(decided[0].Method),
+			Pagination: decided[0].Strategy,
+		})
+	}
+	return rules, conflicts
+}
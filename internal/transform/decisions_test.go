@@ -0,0 +1,78 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestPaginationDecisions(t *testing.T) {
+	dir := t.TempDir()
+	spec := `openapi: 3.0.0
+info:
+  title: decisions
+  version: "1"
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+      responses:
+        "200":
+          description: ok
+  /teams:
+    get:
+      parameters:
+        - name: page
+          in: query
+      responses:
+        "200":
+          description: ok
+  /health:
+    get:
+      responses:
+        "200":
+          description: ok
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessPaginationInDir(dir, PaginationOptions{
+		Options:            Options{DryRun: true},
+		PaginationPriority: []string{"cursor", "page"},
+		EndpointRules:      []config.EndpointPaginationRule{{Endpoint: "/teams", Method: "*", Pagination: "page"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PaginationDecision{{File: filepath.Join(dir, "api.yaml"), Path: "/users", Method: "get", Strategy: "cursor"}}
+	if !reflect.DeepEqual(result.Decisions, want) {
+		t.Errorf("expected only the priority decision for GET /users, got %+v", result.Decisions)
+	}
+}
+
+func TestDecisionRules(t *testing.T) {
+	rules, conflicts := DecisionRules([]PaginationDecision{
+		{File: "a.yaml", Path: "/users", Method: "get", Strategy: "cursor"},
+		{File: "a.yaml", Path: "/orders", Method: "get", Strategy: "offset"},
+		{File: "b.yaml", Path: "/users", Method: "get", Strategy: "page"},
+		{File: "b.yaml", Path: "/orders", Method: "get", Strategy: "offset"},
+		{File: "b.yaml", Path: "/orders", Method: "post", Strategy: "none"},
+	})
+
+	wantRules := []config.EndpointPaginationRule{
+		{Endpoint: "/orders", Method: "GET", Pagination: "offset"},
+		{Endpoint: "/orders", Method: "POST", Pagination: "none"},
+	}
+	if !reflect.DeepEqual(rules, wantRules) {
+		t.Errorf("expected rules %+v, got %+v", wantRules, rules)
+	}
+	if want := []string{"GET /users: cursor in a.yaml, page in b.yaml"}; !reflect.DeepEqual(conflicts, want) {
+		t.Errorf("expected conflicts %v, got %v", want, conflicts)
+	}
+}
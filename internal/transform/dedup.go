@@ -0,0 +1,298 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Canonical name preferences for component deduplication
+const (
+	DedupPreferShortest     = "shortest"
+	DedupPreferAlphabetical = "alphabetical"
+	DedupPreferFirst        = "first"
+)
+
+// DedupOptions extends the regular Options with component deduplication settings
+type DedupOptions struct {
+	Options
+	ComponentDedup config.ComponentDedup
+}
+
+// DedupResult represents the result of component deduplication processing
+type DedupResult struct {
+	Changed          bool
+	ProcessedFiles   []string
+	MergedComponents map[string][]string // file -> list of merged duplicates
+}
+
+// createDedupResult creates a new DedupResult with initialized maps
+func createDedupResult() *DedupResult {
+	return &DedupResult{
+		ProcessedFiles:   []string{},
+		MergedComponents: make(map[string][]string),
+	}
+}
+
+// setDedupProcessedFiles sets the processed files for a DedupResult
+func setDedupProcessedFiles(result *DedupResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setDedupChanged sets the changed flag for a DedupResult
+func setDedupChanged(result *DedupResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessComponentDedupInDir merges structurally identical components in all OpenAPI files in a directory
+func ProcessComponentDedupInDir(dir string, opts DedupOptions) (*DedupResult, error) {
+	if err := validateDedupPreference(opts.ComponentDedup.Prefer); err != nil {
+		return createDedupResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepComponentDedup,
+		opts.Options,
+		opts.ComponentDedup.Enabled,
+		false,
+		createDedupResult,
+		func(path string, result *DedupResult) (bool, error) {
+			return processComponentDedupInFile(path, opts, result)
+		},
+		setDedupProcessedFiles,
+		setDedupChanged,
+	)
+}
+
+// validateDedupPreference checks that the configured canonical name preference is known
+func validateDedupPreference(prefer string) error {
+	switch prefer {
+	case "", DedupPreferShortest, DedupPreferAlphabetical, DedupPreferFirst:
+		return nil
+	default:
+		return fmt.Errorf("unknown component dedup preference %q (expected %s, %s or %s)",
+			prefer, DedupPreferShortest, DedupPreferAlphabetical, DedupPreferFirst)
+	}
+}
+
+// processComponentDedupInFile merges duplicate components in a single file
+func processComponentDedupInFile(path string, opts DedupOptions, result *DedupResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	return processDocumentComponentDedup(doc, root, path, opts, result)
+}
+
+// processDocumentComponentDedup merges duplicate components in a document. Merging can make
+// components that referenced the duplicates identical too, so this repeats until nothing changes.
+func processDocumentComponentDedup(doc, root *yaml.Node, path string, opts DedupOptions, result *DedupResult) (bool, error) {
+	ignoreKeys := make(map[string]bool, len(opts.ComponentDedup.IgnoreKeys))
+	for _, key := range opts.ComponentDedup.IgnoreKeys {
+		ignoreKeys[key] = true
+	}
+	// Swagger 2.0 discriminators select definitions by name, so merging them would change payloads
+	discriminated := swagger2DiscriminatorSchemas(root)
+
+	changed := false
+	for {
+		merges := make(map[string]map[string]string)
+		for _, section := range getDedupSections(opts.ComponentDedup) {
+			sectionNode, _, _ := componentSection(root, section)
+			if sectionNode == nil || sectionNode.Kind != yaml.MappingNode {
+				continue
+			}
+			var exclude map[string]bool
+			if section == "schemas" {
+				exclude = discriminated
+			}
+			if plan := planSectionDedup(sectionNode, opts.ComponentDedup, ignoreKeys, exclude); len(plan) > 0 {
+				merges[section] = plan
+			}
+		}
+
+		if len(merges) == 0 {
+			break
+		}
+
+		refCounts := rewriteComponentRefs(root, merges)
+		for section, plan := range merges {
+			duplicates := make([]string, 0, len(plan))
+			for duplicate := range plan {
+				duplicates = append(duplicates, duplicate)
+			}
+			sectionNode, _, _ := componentSection(root, section)
+			filterUnusedSchemas(sectionNode, duplicates)
+		}
+		recordComponentMerges(result, path, root, merges, refCounts)
+		changed = true
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// getDedupSections returns the component sections to deduplicate, defaulting to schemas
+func getDedupSections(dedup config.ComponentDedup) []string {
+	if len(dedup.Sections) == 0 {
+		return []string{"schemas"}
+	}
+	return dedup.Sections
+}
+
+// planSectionDedup groups structurally identical components in one section and maps each duplicate
+// to its group's canonical name. Components in exclude are never merged.
+func planSectionDedup(sectionNode *yaml.Node, dedup config.ComponentDedup, ignoreKeys, exclude map[string]bool) map[string]string {
+	var fingerprints []string
+	groups := make(map[string][]string)
+
+	for i := 0; i < len(sectionNode.Content); i += 2 {
+		name := sectionNode.Content[i].Value
+		if exclude[name] {
+			continue
+		}
+		fingerprint := componentFingerprint(sectionNode.Content[i+1], ignoreKeys)
+		if _, seen := groups[fingerprint]; !seen {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+		groups[fingerprint] = append(groups[fingerprint], name)
+	}
+
+	plan := make(map[string]string)
+	for _, fingerprint := range fingerprints {
+		names := groups[fingerprint]
+		if len(names) < 2 {
+			continue
+		}
+
+		canonical := chooseCanonicalName(names, dedup)
+		for _, name := range names {
+			if name != canonical {
+				plan[name] = canonical
+			}
+		}
+	}
+	return plan
+}
+
+// chooseCanonicalName picks the name a duplicate group is merged into. Names listed in
+// canonical win; otherwise the configured preference decides. names is in document order.
+func chooseCanonicalName(names []string, dedup config.ComponentDedup) string {
+	for _, preferred := range dedup.Canonical {
+		if contains(names, preferred) {
+			return preferred
+		}
+	}
+
+	switch dedup.Prefer {
+	case DedupPreferFirst:
+		return names[0]
+	case DedupPreferAlphabetical:
+		sorted := append([]string(nil), names...)
+		sort.Strings(sorted)
+		return sorted[0]
+	default:
+		sorted := append([]string(nil), names...)
+		sort.Slice(sorted, func(i, j int) bool {
+			if len(sorted[i]) != len(sorted[j]) {
+				return len(sorted[i]) < len(sorted[j])
+			}
+			return sorted[i] < sorted[j]
+		})
+		return sorted[0]
+	}
+}
+
+// componentFingerprint builds an order-independent representation of a component used to detect
+// structurally identical components
+func componentFingerprint(node *yaml.Node, ignoreKeys map[string]bool) string {
+	var sb strings.Builder
+	writeFingerprint(&sb, node, ignoreKeys, false)
+	return sb.String()
+}
+
+// writeFingerprint writes the fingerprint of node to sb. Mapping keys are sorted; ignored keys are
+// skipped unless the mapping holds user-defined names (e.g. properties), where every key is significant.
+func writeFingerprint(sb *strings.Builder, node *yaml.Node, ignoreKeys map[string]bool, namedKeys bool) {
+	if node == nil {
+		sb.WriteString("~")
+		return
+	}
+
+	switch node.Kind {
+	case yaml.AliasNode:
+		writeFingerprint(sb, node.Alias, ignoreKeys, namedKeys)
+	case yaml.DocumentNode, yaml.SequenceNode:
+		sb.WriteString("[")
+		for _, item := range node.Content {
+			writeFingerprint(sb, item, ignoreKeys, false)
+			sb.WriteString(",")
+		}
+		sb.WriteString("]")
+	case yaml.MappingNode:
+		type entry struct {
+			key   string
+			value *yaml.Node
+		}
+		entries := make([]entry, 0, len(node.Content)/2)
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if !namedKeys && ignoreKeys[key] {
+				continue
+			}
+			entries = append(entries, entry{key: key, value: node.Content[i+1]})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+		sb.WriteString("{")
+		for _, e := range entries {
+			sb.WriteString(strconv.Quote(e.key))
+			sb.WriteString(":")
+			writeFingerprint(sb, e.value, ignoreKeys, !namedKeys && isNamedKeysContainer(e.key))
+			sb.WriteString(",")
+		}
+		sb.WriteString("}")
+	default:
+		sb.WriteString(node.ShortTag())
+		sb.WriteString(strconv.Quote(node.Value))
+	}
+}
+
+// isNamedKeysContainer reports whether the value of key is a mapping keyed by user-defined names
+func isNamedKeysContainer(key string) bool {
+	switch key {
+	case "properties", "patternProperties", "mapping", "headers", "links", "examples", "encoding":
+		return true
+	}
+	return false
+}
+
+// recordComponentMerges records the merged duplicates in sorted order
+func recordComponentMerges(result *DedupResult, path string, root *yaml.Node, merges map[string]map[string]string, refCounts map[string]int) {
+	var entries []string
+	for section, plan := range merges {
+		_, name, prefix := componentSection(root, section)
+		for duplicate, canonical := range plan {
+			entries = append(entries, fmt.Sprintf("%s.%s -> %s (%d references updated)",
+				name, duplicate, canonical, refCounts[prefix+duplicate]))
+		}
+	}
+	sort.Strings(entries)
+	result.MergedComponents[path] = append(result.MergedComponents[path], entries...)
+}
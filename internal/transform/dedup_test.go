@@ -0,0 +1,285 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const dedupTestSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/UserResponse'
+  /accounts:
+    get:
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/AccountResponse'
+components:
+  schemas:
+    UserResponse:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/UserDto'
+    AccountResponse:
+      properties:
+        owner:
+          $ref: '#/components/schemas/User'
+      type: object
+    UserDto:
+      type: object
+      description: Generated copy
+      properties:
+        id:
+          type: string
+    User:
+      type: object
+      properties:
+        id:
+          type: string
+    Order:
+      type: object
+      properties:
+        id:
+          type: integer
+`
+
+func TestProcessComponentDedupInDir(t *testing.T) {
+	tests := []struct {
+		name          string
+		dedup         config.ComponentDedup
+		expectChanged bool
+		expectError   bool
+		contains      []string
+		notContains   []string
+	}{
+		{
+			name:          "disabled dedup",
+			dedup:         config.ComponentDedup{Enabled: false},
+			expectChanged: false,
+			contains:      []string{"UserDto:"},
+		},
+		{
+			name:          "description differences keep components apart",
+			dedup:         config.ComponentDedup{Enabled: true},
+			expectChanged: false,
+			contains:      []string{"UserDto:", "UserResponse:", "AccountResponse:"},
+		},
+		{
+			name:          "ignored keys merge duplicates transitively",
+			dedup:         config.ComponentDedup{Enabled: true, IgnoreKeys: []string{"description"}},
+			expectChanged: true,
+			contains: []string{
+				"$ref: '#/components/schemas/User'",
+				"$ref: '#/components/schemas/UserResponse'",
+				"Order:",
+			},
+			notContains: []string{"UserDto", "AccountResponse"},
+		},
+		{
+			name: "canonical names win",
+			dedup: config.ComponentDedup{
+				Enabled:    true,
+				IgnoreKeys: []string{"description"},
+				Canonical:  []string{"UserDto", "AccountResponse"},
+			},
+			expectChanged: true,
+			contains:      []string{"$ref: '#/components/schemas/UserDto'", "$ref: '#/components/schemas/AccountResponse'"},
+			notContains:   []string{"schemas/User'", "UserResponse"},
+		},
+		{
+			name:        "unknown preference",
+			dedup:       config.ComponentDedup{Enabled: true, Prefer: "longest"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "api.yaml")
+			if err := os.WriteFile(path, []byte(dedupTestSpec), 0600); err != nil {
+				t.Fatalf("failed to write test file: %v", err)
+			}
+
+			result, err := ProcessComponentDedupInDir(dir, DedupOptions{ComponentDedup: tt.dedup})
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if result.Changed != tt.expectChanged {
+				t.Errorf("expected Changed=%v, got %v", tt.expectChanged, result.Changed)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			output := string(data)
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output to contain %q\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("expected output not to contain %q\n%s", unwanted, output)
+				}
+			}
+		})
+	}
+}
+
+func TestComponentDedupSwagger2(t *testing.T) {
+	dir, path := writeTestSpec(t, `swagger: "2.0"
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: '#/definitions/UserDto'
+definitions:
+  User:
+    type: object
+    properties:
+      id:
+        type: string
+  UserDto:
+    type: object
+    properties:
+      id:
+        type: string
+  Pet:
+    type: object
+    discriminator: kind
+    properties:
+      kind:
+        type: string
+  Cat:
+    allOf:
+      - $ref: '#/definitions/Pet'
+  Dog:
+    allOf:
+      - $ref: '#/definitions/Pet'
+`)
+
+	result, err := ProcessComponentDedupInDir(dir, DedupOptions{ComponentDedup: config.ComponentDedup{Enabled: true}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Cat and Dog are identical, but the discriminator tells them apart by name
+	want := "definitions.UserDto -> User (1 references updated)"
+	if got := strings.Join(result.MergedComponents[path], "\n"); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	output := string(data)
+	if strings.Contains(output, "UserDto") || !strings.Contains(output, "$ref: '#/definitions/User'") {
+		t.Errorf("expected UserDto to be merged into User, got:\n%s", output)
+	}
+	for _, kept := range []string{"  Cat:\n", "  Dog:\n"} {
+		if !strings.Contains(output, kept) {
+			t.Errorf("expected %q to be kept, got:\n%s", kept, output)
+		}
+	}
+}
+
+func TestChooseCanonicalName(t *testing.T) {
+	names := []string{"UserDto", "UserModel", "User"}
+
+	tests := []struct {
+		name     string
+		dedup    config.ComponentDedup
+		expected string
+	}{
+		{"default prefers shortest", config.ComponentDedup{}, "User"},
+		{"first in document order", config.ComponentDedup{Prefer: DedupPreferFirst}, "UserDto"},
+		{"alphabetical", config.ComponentDedup{Prefer: DedupPreferAlphabetical}, "User"},
+		{"canonical list", config.ComponentDedup{Canonical: []string{"Missing", "UserModel"}}, "UserModel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chooseCanonicalName(names, tt.dedup); got != tt.expected {
+				t.Errorf("chooseCanonicalName() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestComponentFingerprint(t *testing.T) {
+	tests := []struct {
+		name   string
+		a      string
+		b      string
+		ignore []string
+		equal  bool
+	}{
+		{"key order ignored", "type: object\nrequired: [id]", "required: [id]\ntype: object", nil, true},
+		{"scalar types differ", "enum: [1]", "enum: ['1']", nil, false},
+		{"ignored key", "type: string\ndescription: a", "type: string\ndescription: b", []string{"description"}, true},
+		{
+			"property names are never ignored",
+			"properties:\n  description:\n    type: string",
+			"properties:\n  title:\n    type: string",
+			[]string{"description", "title"},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ignore := make(map[string]bool)
+			for _, key := range tt.ignore {
+				ignore[key] = true
+			}
+
+			var a, b yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.a), &a); err != nil {
+				t.Fatalf("failed to parse YAML: %v", err)
+			}
+			if err := yaml.Unmarshal([]byte(tt.b), &b); err != nil {
+				t.Fatalf("failed to parse YAML: %v", err)
+			}
+
+			equal := componentFingerprint(a.Content[0], ignore) == componentFingerprint(b.Content[0], ignore)
+			if equal != tt.equal {
+				t.Errorf("expected fingerprints equal=%v, got %v", tt.equal, equal)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/developerkunal/OpenMorph/internal/condition"
 	"github.com/developerkunal/OpenMorph/internal/config"
 )
 
@@ -16,6 +17,7 @@ import (
 type DefaultsOptions struct {
 	Options
 	DefaultValues config.DefaultValues
+	AsyncAPI      bool // also apply defaults to AsyncAPI documents
 }
 
 // DefaultsResult represents the result of defaults processing
@@ -23,7 +25,9 @@ type DefaultsResult struct {
 	Changed         bool
 	ProcessedFiles  []string
 	AppliedDefaults map[string][]string // file -> list of applied defaults
-	SkippedTargets  map[string][]string // file -> list of skipped targets with reasons
+	SkippedTargets  map[string][]Skip   // file -> list of skipped targets with reasons
+	Locations       []ChangeLocation    // source positions of applied defaults
+	RuleOrder       []string            // "name (priority N)" of every rule, in the order they are applied
 }
 
 // createDefaultsResult creates a new DefaultsResult with initialized maps
@@ -31,7 +35,7 @@ func createDefaultsResult() *DefaultsResult {
 	return &DefaultsResult{
 		ProcessedFiles:  []string{},
 		AppliedDefaults: make(map[string][]string),
-		SkippedTargets:  make(map[string][]string),
+		SkippedTargets:  make(map[string][]Skip),
 	}
 }
 
@@ -47,8 +51,10 @@ func setDefaultsChanged(result *DefaultsResult, changed bool) {
 
 // ProcessDefaultsInDir processes default values in all OpenAPI files in a directory
 func ProcessDefaultsInDir(dir string, opts DefaultsOptions) (*DefaultsResult, error) {
-	return processTransformInDir(
+	result, err := processTransformInDir(
 		dir,
+		StepDefaults,
+		opts.Options,
 		opts.DefaultValues.Enabled,
 		len(opts.DefaultValues.Rules) == 0,
 		createDefaultsResult,
@@ -58,6 +64,12 @@ func ProcessDefaultsInDir(dir string, opts DefaultsOptions) (*DefaultsResult, er
 		setDefaultsProcessedFiles,
 		setDefaultsChanged,
 	)
+	if err == nil && opts.DefaultValues.Enabled {
+		for _, entry := range getSortedDefaultRules(opts.DefaultValues.Rules) {
+			result.RuleOrder = append(result.RuleOrder, fmt.Sprintf("%s (priority %d)", entry.Name, entry.Rule.Priority))
+		}
+	}
+	return result, err
 }
 
 // processDefaultsInFile processes default values in a single file
@@ -69,6 +81,9 @@ func processDefaultsInFile(path string, opts DefaultsOptions, result *DefaultsRe
 
 	root := getRootNode(doc)
 
+	if isAsyncAPIDocument(root) && opts.AsyncAPI {
+		return processAsyncAPIDefaults(doc, root, path, opts, result)
+	}
 	if !isOpenAPIDocument(root) {
 		return false, nil // Skip non-OpenAPI files
 	}
@@ -82,6 +97,7 @@ func processDocumentDefaults(doc, root *yaml.Node, path string, opts DefaultsOpt
 
 	// Sort rules by priority (higher priority first)
 	sortedRules := getSortedDefaultRules(opts.DefaultValues.Rules)
+	operations := scopeToPaths(root, opts.Paths)
 
 	for _, ruleEntry := range sortedRules {
 		ruleName := ruleEntry.Name
@@ -89,26 +105,36 @@ func processDocumentDefaults(doc, root *yaml.Node, path string, opts DefaultsOpt
 
 		switch rule.Target.Location {
 		case "parameter":
-			if processParameterDefaults(root, ruleName, rule, path, result) {
+			if processParameterDefaults(operations, ruleName, rule, path, result) {
 				changed = true
 			}
 		case "request_body":
-			if processRequestBodyDefaults(root, ruleName, rule, path, result) {
+			if processRequestBodyDefaults(operations, ruleName, rule, path, result) {
 				changed = true
 			}
 		case "response":
-			if processResponseDefaults(root, ruleName, rule, path, result) {
+			if processResponseDefaults(operations, ruleName, rule, path, result) {
 				changed = true
 			}
 		case "component":
-			if processComponentDefaults(root, ruleName, rule, path, result) {
+			// Components are shared by every operation, so they are out of scope when paths are limited
+			if len(opts.Paths) == 0 && processComponentDefaults(root, ruleName, rule, path, result) {
+				changed = true
+			}
+		case "server_variable":
+			if processServerVariableDefaults(root, operations, len(opts.Paths) == 0, ruleName, rule, path, result) {
+				changed = true
+			}
+		case "security_scheme":
+			// Security schemes are shared like components
+			if len(opts.Paths) == 0 && processSecuritySchemeDefaults(root, ruleName, rule, path, result) {
 				changed = true
 			}
 		}
 	}
 
 	if changed {
-		return writeDefaultsDocument(doc, path, opts.DryRun)
+		return writeStepDocument(opts.Options, doc, path)
 	}
 
 	return false, nil
@@ -120,7 +146,8 @@ type RuleEntry struct {
 	Rule config.DefaultRule
 }
 
-// getSortedDefaultRules returns rules sorted by priority (higher first)
+// getSortedDefaultRules returns rules sorted by priority (higher first), breaking ties by rule
+// name so that the order does not depend on map iteration
 func getSortedDefaultRules(rules map[string]config.DefaultRule) []RuleEntry {
 	var sortedRules []RuleEntry
 	for name, rule := range rules {
@@ -128,7 +155,10 @@ func getSortedDefaultRules(rules map[string]config.DefaultRule) []RuleEntry {
 	}
 
 	sort.Slice(sortedRules, func(i, j int) bool {
-		return sortedRules[i].Rule.Priority > sortedRules[j].Rule.Priority
+		if sortedRules[i].Rule.Priority != sortedRules[j].Rule.Priority {
+			return sortedRules[i].Rule.Priority > sortedRules[j].Rule.Priority
+		}
+		return sortedRules[i].Name < sortedRules[j].Name
 	})
 
 	return sortedRules
@@ -170,7 +200,7 @@ func processParametersInPath(pathNode *yaml.Node, pathName, ruleName string, rul
 			continue
 		}
 
-		if !matchesHTTPMethod(operation, rule.Condition.HTTPMethods) {
+		if !defaultRuleCondition(rule).Matches(pathName, operation, operationNode) {
 			continue
 		}
 
@@ -210,7 +240,7 @@ func applyParameterDefault(paramNode *yaml.Node, operationKey, ruleName string,
 	// Check parameter location (in: query, path, header, cookie)
 	paramIn := getStringValue(paramNode, "in")
 	if rule.Condition.ParameterIn != "" && paramIn != rule.Condition.ParameterIn {
-		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter", operationKey),
+		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter", operationKey), SkipLocationMismatch,
 			fmt.Sprintf("parameter in '%s' doesn't match rule condition '%s'", paramIn, rule.Condition.ParameterIn))
 		return false
 	}
@@ -218,36 +248,43 @@ func applyParameterDefault(paramNode *yaml.Node, operationKey, ruleName string,
 	// Check property name if specified
 	paramName := getStringValue(paramNode, "name")
 	if rule.Condition.PropertyName != "" && !matchesPropertyName(paramName, rule.Condition.PropertyName) {
-		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName),
+		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName), SkipNameMismatch,
 			fmt.Sprintf("parameter name doesn't match pattern '%s'", rule.Condition.PropertyName))
 		return false
 	}
 
-	schema := getNodeValue(paramNode, "schema")
+	schema := parameterSchema(paramNode)
 	if schema == nil {
-		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName), "no schema found")
+		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName), SkipNoSchema, "no schema found")
 		return false
 	}
 
 	// Check if default already exists
 	if getNodeValue(schema, "default") != nil {
-		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName), "default already exists")
+		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName), SkipDefaultExists, "default already exists")
 		return false
 	}
 
 	// Check type condition
 	schemaType := getStringValue(schema, "type")
 	if rule.Condition.Type != "" && schemaType != rule.Condition.Type {
-		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName),
+		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName), SkipTypeMismatch,
 			fmt.Sprintf("type '%s' doesn't match rule condition '%s'", schemaType, rule.Condition.Type))
 		return false
 	}
 
+	// Check format condition
+	if format := getStringValue(schema, "format"); rule.Condition.Format != "" && format != rule.Condition.Format {
+		addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName), SkipFormatMismatch,
+			fmt.Sprintf("format '%s' doesn't match rule condition '%s'", format, rule.Condition.Format))
+		return false
+	}
+
 	// Check enum condition
 	if rule.Condition.HasEnum {
 		enumNode := getNodeValue(schema, "enum")
 		if enumNode == nil {
-			addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName), "no enum found but required by rule")
+			addSkippedTarget(result, filePath, fmt.Sprintf("%s parameter %s", operationKey, paramName), SkipMissingEnum, "no enum found but required by rule")
 			return false
 		}
 	}
@@ -451,20 +488,26 @@ func processCompositions(schema *yaml.Node, root *yaml.Node, context, ruleName s
 func shouldApplyDefaultToProperty(propSchema *yaml.Node, propName string, rule config.DefaultRule, context, filePath string, result *DefaultsResult) bool {
 	// Check if default already exists
 	if getNodeValue(propSchema, "default") != nil {
-		addSkippedTarget(result, filePath, context, "default already exists")
+		addSkippedTarget(result, filePath, context, SkipDefaultExists, "default already exists")
 		return false
 	}
 
 	// Check property name pattern
 	if rule.Condition.PropertyName != "" && !matchesPropertyName(propName, rule.Condition.PropertyName) {
-		addSkippedTarget(result, filePath, context, fmt.Sprintf("property name doesn't match pattern '%s'", rule.Condition.PropertyName))
+		addSkippedTarget(result, filePath, context, SkipNameMismatch, fmt.Sprintf("property name doesn't match pattern '%s'", rule.Condition.PropertyName))
 		return false
 	}
 
 	// Check type condition
 	schemaType := getStringValue(propSchema, "type")
 	if rule.Condition.Type != "" && schemaType != rule.Condition.Type {
-		addSkippedTarget(result, filePath, context, fmt.Sprintf("type '%s' doesn't match rule condition '%s'", schemaType, rule.Condition.Type))
+		addSkippedTarget(result, filePath, context, SkipTypeMismatch, fmt.Sprintf("type '%s' doesn't match rule condition '%s'", schemaType, rule.Condition.Type))
+		return false
+	}
+
+	// Check format condition
+	if format := getStringValue(propSchema, "format"); rule.Condition.Format != "" && format != rule.Condition.Format {
+		addSkippedTarget(result, filePath, context, SkipFormatMismatch, fmt.Sprintf("format '%s' doesn't match rule condition '%s'", format, rule.Condition.Format))
 		return false
 	}
 
@@ -472,14 +515,14 @@ func shouldApplyDefaultToProperty(propSchema *yaml.Node, propName string, rule c
 	if rule.Condition.HasEnum {
 		enumNode := getNodeValue(propSchema, "enum")
 		if enumNode == nil {
-			addSkippedTarget(result, filePath, context, "no enum found but required by rule")
+			addSkippedTarget(result, filePath, context, SkipMissingEnum, "no enum found but required by rule")
 			return false
 		}
 	}
 
 	// Check array condition
 	if rule.Condition.IsArray && schemaType != "array" {
-		addSkippedTarget(result, filePath, context, "not an array but required by rule")
+		addSkippedTarget(result, filePath, context, SkipNotArray, "not an array but required by rule")
 		return false
 	}
 
@@ -505,28 +548,197 @@ func determineDefaultValue(rule config.DefaultRule, _ /* schema */, _ /* param *
 
 // addDefaultToSchema adds a default value to a schema node
 func addDefaultToSchema(schema *yaml.Node, defaultValue interface{}, context, _ /* propertyName */, ruleName, filePath string, result *DefaultsResult) bool {
-	// Create default key node
-	keyNode := &yaml.Node{
-		Kind:  yaml.ScalarNode,
-		Value: "default",
-	}
-
 	// Create default value node
 	valueNode := createDefaultValueNode(defaultValue)
 	if valueNode == nil {
-		addSkippedTarget(result, filePath, context, "could not create value node for default")
+		addSkippedTarget(result, filePath, context, SkipInvalidValue, "could not create value node for default")
 		return false
 	}
 
-	// Add to schema
-	schema.Content = append(schema.Content, keyNode, valueNode)
+	addDefaultField(schema, "default", valueNode, defaultValue, context, ruleName, filePath, result)
+	return true
+}
+
+// addDefaultField adds a field holding a default value to a node and records it
+func addDefaultField(node *yaml.Node, field string, valueNode *yaml.Node, defaultValue interface{}, context, ruleName, filePath string, result *DefaultsResult) {
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: field}, valueNode)
 
 	// Record the applied default
-	addAppliedDefault(result, filePath, fmt.Sprintf("%s: default = %v (rule: %s)", context, defaultValue, ruleName))
+	defaultInfo := fmt.Sprintf("%s: %s = %v (rule: %s)", context, field, defaultValue, ruleName)
+	addAppliedDefault(result, filePath, defaultInfo)
+	result.Locations = append(result.Locations, newChangeLocation(filePath, StepDefaults, node, defaultInfo))
+}
+
+// processServerVariableDefaults fills in missing defaults of server variables. Servers of the
+// document are only considered when includeDocument is set and the rule has no operation
+// conditions; path_patterns select path item servers, and methods, tags and extensions select
+// operation servers.
+func processServerVariableDefaults(root, operations *yaml.Node, includeDocument bool, ruleName string, rule config.DefaultRule, filePath string, result *DefaultsResult) bool {
+	changed := false
+	operationConditions := len(rule.Condition.PathPatterns) > 0 || len(rule.Condition.HTTPMethods) > 0 ||
+		hasOperationFlags(rule.Condition)
+	if includeDocument && !operationConditions {
+		changed = processServersDefaults(root, "", ruleName, rule, filePath, result)
+	}
+
+	paths := getNodeValue(operations, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return changed
+	}
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+		if !matchesPathPattern(pathName, rule.Condition.PathPatterns) {
+			continue
+		}
+		if len(rule.Condition.HTTPMethods) == 0 && !hasOperationFlags(rule.Condition) &&
+			processServersDefaults(pathItem, pathName, ruleName, rule, filePath, result) {
+			changed = true
+		}
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			method := pathItem.Content[j].Value
+			if !isHTTPMethod(method) || !defaultRuleCondition(rule).Matches(pathName, method, pathItem.Content[j+1]) {
+				continue
+			}
+			context := fmt.Sprintf("%s %s", strings.ToUpper(method), pathName)
+			if processServersDefaults(pathItem.Content[j+1], context, ruleName, rule, filePath, result) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// processServersDefaults fills in missing defaults of the variables of a node's servers
+func processServersDefaults(node *yaml.Node, context, ruleName string, rule config.DefaultRule, filePath string, result *DefaultsResult) bool {
+	servers := getNodeValue(node, "servers")
+	if servers == nil || servers.Kind != yaml.SequenceNode {
+		return false
+	}
+
+	changed := false
+	for _, server := range servers.Content {
+		variables := getNodeValue(server, "variables")
+		if variables == nil || variables.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(variables.Content); i += 2 {
+			name := variables.Content[i].Value
+			variableContext := strings.TrimSpace(fmt.Sprintf("%s server %s variable %s", context, getStringValue(server, "url"), name))
+			if applyServerVariableDefault(variables.Content[i+1], name, variableContext, ruleName, rule, filePath, result) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// applyServerVariableDefault sets the default of a server variable if conditions match. Server
+// variables are always strings, so the value is written as one and must be among the variable's
+// enum values when it has any.
+func applyServerVariableDefault(variable *yaml.Node, name, context, ruleName string, rule config.DefaultRule, filePath string, result *DefaultsResult) bool {
+	if variable.Kind != yaml.MappingNode {
+		return false
+	}
+	if rule.Condition.PropertyName != "" && !matchesPropertyName(name, rule.Condition.PropertyName) {
+		addSkippedTarget(result, filePath, context, SkipNameMismatch, fmt.Sprintf("variable name doesn't match pattern '%s'", rule.Condition.PropertyName))
+		return false
+	}
+	if rule.Condition.Type != "" && rule.Condition.Type != "string" {
+		addSkippedTarget(result, filePath, context, SkipTypeMismatch, fmt.Sprintf("type 'string' doesn't match rule condition '%s'", rule.Condition.Type))
+		return false
+	}
+	if getNodeValue(variable, "default") != nil {
+		addSkippedTarget(result, filePath, context, SkipDefaultExists, "default already exists")
+		return false
+	}
+	enum := getNodeValue(variable, "enum")
+	if rule.Condition.HasEnum && enum == nil {
+		addSkippedTarget(result, filePath, context, SkipMissingEnum, "no enum found but required by rule")
+		return false
+	}
 
+	defaultValue := determineDefaultValue(rule, nil, variable)
+	valueNode := createDefaultValueNode(defaultValue)
+	if valueNode == nil || valueNode.Kind != yaml.ScalarNode {
+		addSkippedTarget(result, filePath, context, SkipInvalidValue, "server variable defaults must be strings")
+		return false
+	}
+	valueNode.Tag = "!!str"
+
+	if enum != nil && enum.Kind == yaml.SequenceNode {
+		allowed := false
+		for _, value := range enum.Content {
+			allowed = allowed || value.Value == valueNode.Value
+		}
+		if !allowed {
+			addSkippedTarget(result, filePath, context, SkipInvalidValue, fmt.Sprintf("default '%s' is not one of the variable's enum values", valueNode.Value))
+			return false
+		}
+	}
+
+	addDefaultField(variable, "default", valueNode, defaultValue, context, ruleName, filePath, result)
 	return true
 }
 
+// processSecuritySchemeDefaults fills in a missing field of the security schemes in
+// components/securitySchemes. target.property names the field, with dots for nested fields such
+// as flows.clientCredentials.refreshUrl; the condition's type matches the scheme's type and
+// property_name its name.
+func processSecuritySchemeDefaults(root *yaml.Node, ruleName string, rule config.DefaultRule, filePath string, result *DefaultsResult) bool {
+	schemes := getNodeValue(getNodeValue(root, "components"), "securitySchemes")
+	if schemes == nil || schemes.Kind != yaml.MappingNode {
+		return false
+	}
+	if rule.Target.Property == "" {
+		addSkippedTarget(result, filePath, "rule "+ruleName, SkipInvalidRule, "security_scheme rules need target.property to name the field to fill")
+		return false
+	}
+
+	fields := strings.Split(rule.Target.Property, ".")
+	changed := false
+	for i := 0; i+1 < len(schemes.Content); i += 2 {
+		name, scheme := schemes.Content[i].Value, schemes.Content[i+1]
+		if scheme.Kind != yaml.MappingNode || getNodeValue(scheme, "$ref") != nil {
+			continue
+		}
+
+		context := "security scheme " + name
+		if rule.Condition.PropertyName != "" && !matchesPropertyName(name, rule.Condition.PropertyName) {
+			addSkippedTarget(result, filePath, context, SkipNameMismatch, fmt.Sprintf("scheme name doesn't match pattern '%s'", rule.Condition.PropertyName))
+			continue
+		}
+		if schemeType := getStringValue(scheme, "type"); rule.Condition.Type != "" && schemeType != rule.Condition.Type {
+			addSkippedTarget(result, filePath, context, SkipTypeMismatch,
+				fmt.Sprintf("type '%s' doesn't match rule condition '%s'", schemeType, rule.Condition.Type))
+			continue
+		}
+
+		parent := scheme
+		for _, field := range fields[:len(fields)-1] {
+			parent = getNodeValue(parent, field)
+		}
+		if parent == nil || parent.Kind != yaml.MappingNode {
+			addSkippedTarget(result, filePath, context, SkipNoSchema, fmt.Sprintf("no %s found", strings.Join(fields[:len(fields)-1], ".")))
+			continue
+		}
+		field := fields[len(fields)-1]
+		if getNodeValue(parent, field) != nil {
+			addSkippedTarget(result, filePath, context, SkipDefaultExists, fmt.Sprintf("%s already exists", rule.Target.Property))
+			continue
+		}
+
+		defaultValue := determineDefaultValue(rule, nil, scheme)
+		valueNode := createDefaultValueNode(defaultValue)
+		if valueNode == nil {
+			addSkippedTarget(result, filePath, context, SkipInvalidValue, "could not create value node for default")
+			continue
+		}
+		addDefaultField(parent, field, valueNode, defaultValue, context, ruleName, filePath, result)
+		changed = true
+	}
+	return changed
+}
+
 // createDefaultValueNode creates a YAML node from a default value
 func createDefaultValueNode(value interface{}) *yaml.Node {
 	switch v := value.(type) {
@@ -551,9 +763,9 @@ func createDefaultValueNode(value interface{}) *yaml.Node {
 		return node
 	case map[string]interface{}:
 		node := &yaml.Node{Kind: yaml.MappingNode}
-		for key, val := range v {
+		for _, key := range sortedKeysOf(v) {
 			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: key}
-			valNode := createDefaultValueNode(val)
+			valNode := createDefaultValueNode(v[key])
 			if valNode != nil {
 				node.Content = append(node.Content, keyNode, valNode)
 			}
@@ -590,7 +802,7 @@ func processOperationDefaults(root *yaml.Node, ruleName string, rule config.Defa
 				continue
 			}
 
-			if !matchesHTTPMethod(operation, rule.Condition.HTTPMethods) {
+			if !defaultRuleCondition(rule).Matches(pathName, operation, operationNode) {
 				continue
 			}
 
@@ -605,28 +817,35 @@ func processOperationDefaults(root *yaml.Node, ruleName string, rule config.Defa
 
 // Helper functions
 
-func matchesPathPattern(path string, patterns []string) bool {
-	if len(patterns) == 0 {
-		return true // No pattern means match all
-	}
-	for _, pattern := range patterns {
-		if matched, _ := regexp.MatchString(pattern, path); matched {
-			return true
-		}
+// defaultRuleCondition returns the operation condition of a rule; its path patterns are regular
+// expressions unless prefixed with glob:
+func defaultRuleCondition(rule config.DefaultRule) condition.Condition {
+	return condition.Condition{
+		Paths:      rule.Condition.PathPatterns,
+		Methods:    rule.Condition.HTTPMethods,
+		Tags:       rule.Condition.Tags,
+		Extensions: rule.Condition.Extensions,
+		Has:        rule.Condition.HasExtension,
+		Missing:    rule.Condition.MissingExtension,
+		Syntax:     condition.Regex,
 	}
-	return false
 }
 
+// hasOperationFlags reports whether a condition looks at the tags or extensions of operations
+func hasOperationFlags(condition config.DefaultCondition) bool {
+	return len(condition.Tags) > 0 || len(condition.Extensions) > 0 ||
+		len(condition.HasExtension) > 0 || len(condition.MissingExtension) > 0
+}
+
+// matchesPathPattern reports whether path matches any pattern, read as regular expressions
+// unless prefixed with glob:, or whether there are none
+func matchesPathPattern(path string, patterns []string) bool {
+	return condition.MatchPath(path, patterns, condition.Regex)
+}
+
+// matchesHTTPMethod reports whether method is one of methods, or whether there are none
 func matchesHTTPMethod(method string, methods []string) bool {
-	if len(methods) == 0 {
-		return true // No method filter means match all
-	}
-	for _, m := range methods {
-		if strings.EqualFold(method, m) {
-			return true
-		}
-	}
-	return false
+	return condition.MatchMethod(method, methods)
 }
 
 func matchesPropertyName(name, pattern string) bool {
@@ -644,19 +863,11 @@ func addAppliedDefault(result *DefaultsResult, filePath, defaultInfo string) {
 	result.AppliedDefaults[filePath] = append(result.AppliedDefaults[filePath], defaultInfo)
 }
 
-func addSkippedTarget(result *DefaultsResult, filePath, target, reason string) {
+func addSkippedTarget(result *DefaultsResult, filePath, target string, code SkipCode, reason string) {
 	if result.SkippedTargets[filePath] == nil {
-		result.SkippedTargets[filePath] = []string{}
+		result.SkippedTargets[filePath] = []Skip{}
 	}
-	result.SkippedTargets[filePath] = append(result.SkippedTargets[filePath], fmt.Sprintf("%s: %s", target, reason))
-}
-
-func writeDefaultsDocument(doc *yaml.Node, path string, dryRun bool) (bool, error) {
-	if dryRun {
-		return true, nil // Return true to indicate changes were detected, but don't write
-	}
-
-	return writeModifiedDocument(doc, path)
+	result.SkippedTargets[filePath] = append(result.SkippedTargets[filePath], Skip{Message: fmt.Sprintf("%s: %s", target, reason), Code: code})
 }
 
 // getStringValue is a helper to get string value from a YAML node
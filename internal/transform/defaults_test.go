@@ -422,7 +422,7 @@ type: string
 			}
 
 			result := &DefaultsResult{
-				SkippedTargets: make(map[string][]string),
+				SkippedTargets: make(map[string][]Skip),
 			}
 
 			shouldApply := shouldApplyDefaultToProperty(propSchema, tt.propName, tt.rule, "test context", "test.yaml", result)
@@ -465,3 +465,235 @@ func TestGetSortedDefaultRules(t *testing.T) {
 		t.Errorf("expected third rule to be 'low_priority', got %q", sorted[2].Name)
 	}
 }
+
+func TestGetSortedDefaultRulesBreaksTiesByName(t *testing.T) {
+	rules := map[string]config.DefaultRule{
+		"zeta":  {Priority: 5},
+		"alpha": {Priority: 5},
+		"mid":   {Priority: 5},
+		"top":   {Priority: 9},
+	}
+
+	for run := 0; run < 10; run++ {
+		var names []string
+		for _, entry := range getSortedDefaultRules(rules) {
+			names = append(names, entry.Name)
+		}
+		if got := strings.Join(names, ","); got != "top,alpha,mid,zeta" {
+			t.Fatalf("expected rules ordered by priority then name, got %s", got)
+		}
+	}
+}
+
+func TestProcessDefaultsReportsRuleOrder(t *testing.T) {
+	result, err := ProcessDefaultsInDir(t.TempDir(), DefaultsOptions{DefaultValues: config.DefaultValues{
+		Enabled: true,
+		Rules: map[string]config.DefaultRule{
+			"b": {Priority: 1},
+			"a": {Priority: 1},
+			"c": {Priority: 2},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(result.RuleOrder, ", "); got != "c (priority 2), a (priority 1), b (priority 1)" {
+		t.Errorf("unexpected rule order %q", got)
+	}
+}
+
+const serverDefaultsSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+servers:
+  - url: https://{region}.api.example.com:{port}
+    variables:
+      region:
+        enum: [eu, us]
+      port:
+        default: "443"
+paths:
+  /reports:
+    servers:
+      - url: https://{region}.reports.example.com
+        variables:
+          region:
+            enum: [apac]
+    get:
+      responses:
+        "200":
+          description: OK
+components:
+  securitySchemes:
+    bearer:
+      type: http
+      scheme: bearer
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://auth.example.com/token
+          scopes: {}
+`
+
+func TestServerVariableAndSecuritySchemeDefaults(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(serverDefaultsSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessDefaultsInDir(dir, DefaultsOptions{DefaultValues: config.DefaultValues{
+		Enabled: true,
+		Rules: map[string]config.DefaultRule{
+			"region": {
+				Target:    config.DefaultTarget{Location: "server_variable"},
+				Condition: config.DefaultCondition{PropertyName: "^region$"},
+				Value:     "eu",
+			},
+			"bearer_format": {
+				Target:    config.DefaultTarget{Location: "security_scheme", Property: "bearerFormat"},
+				Condition: config.DefaultCondition{Type: "http"},
+				Value:     "JWT",
+			},
+			"refresh_url": {
+				Target: config.DefaultTarget{Location: "security_scheme", Property: "flows.clientCredentials.refreshUrl"},
+				Value:  "https://auth.example.com/refresh",
+			},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	skipped := strings.Join(SkipMessages(result.SkippedTargets[path]), "\n")
+	for _, want := range []string{
+		"/reports server https://{region}.reports.example.com variable region: default 'eu' is not one of the variable's enum values",
+		"server https://{region}.api.example.com:{port} variable port: variable name doesn't match pattern '^region$'",
+		"security scheme apiKey: type 'apiKey' doesn't match rule condition 'http'",
+		"security scheme bearer: no flows.clientCredentials found",
+	} {
+		if !strings.Contains(skipped, want) {
+			t.Errorf("expected skipped targets to contain %q, got:\n%s", want, skipped)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Servers []struct {
+			Variables map[string]map[string]interface{}
+		}
+		Components struct {
+			SecuritySchemes map[string]map[string]interface{} `yaml:"securitySchemes"`
+		}
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := doc.Servers[0].Variables["region"]["default"]; got != "eu" {
+		t.Errorf("expected the region variable to default to eu, got %v", got)
+	}
+	if got := doc.Components.SecuritySchemes["bearer"]["bearerFormat"]; got != "JWT" {
+		t.Errorf("expected the bearer scheme to get a bearerFormat, got %v", got)
+	}
+	if _, ok := doc.Components.SecuritySchemes["apiKey"]["bearerFormat"]; ok {
+		t.Error("expected the apiKey scheme to be left alone")
+	}
+	flows := doc.Components.SecuritySchemes["oauth"]["flows"].(map[string]interface{})
+	if got := flows["clientCredentials"].(map[string]interface{})["refreshUrl"]; got != "https://auth.example.com/refresh" {
+		t.Errorf("expected the nested refreshUrl to be filled in, got %v", got)
+	}
+}
+
+func TestServerVariableDefaultIsString(t *testing.T) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte("enum: ['8080', '8443']\n"), &doc); err != nil {
+		t.Fatal(err)
+	}
+	variable := doc.Content[0]
+	result := createDefaultsResult()
+	rule := config.DefaultRule{Value: 8080}
+
+	if !applyServerVariableDefault(variable, "port", "port", "port", rule, "api.yaml", result) {
+		t.Fatalf("expected the default to be applied, skipped: %v", result.SkippedTargets)
+	}
+	if value := getNodeValue(variable, "default"); value.Tag != "!!str" || value.Value != "8080" {
+		t.Errorf("expected a string default, got %s %q", value.Tag, value.Value)
+	}
+}
+
+func TestParameterDefaultsWithContent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	spec := `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: filter
+          in: query
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  status:
+                    type: string
+      responses:
+        "200":
+          description: OK
+`
+	if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessDefaultsInDir(dir, DefaultsOptions{DefaultValues: config.DefaultValues{
+		Enabled: true,
+		Rules: map[string]config.DefaultRule{
+			"filter": {
+				Target:    config.DefaultTarget{Location: "parameter"},
+				Condition: config.DefaultCondition{ParameterIn: "query", Type: "object"},
+				Value:     map[string]interface{}{"status": "active"},
+			},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed {
+		t.Fatalf("expected the content parameter to get a default, skipped: %v", SkipMessages(result.SkippedTargets[path]))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Content map[string]struct {
+					Schema map[string]interface{}
+				}
+			}
+		}
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	schema := doc.Paths["/users"]["get"].Parameters[0].Content["application/json"].Schema
+	if got, ok := schema["default"].(map[string]interface{}); !ok || got["status"] != "active" {
+		t.Errorf("expected the default in the media type schema:\n%s", data)
+	}
+}
@@ -0,0 +1,229 @@
+package transform
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Discriminators name the property that selects a schema and map its values to schemas, by $ref or
+// by bare schema name. Every step that renames properties or schemas keeps them in step here.
+
+// walkDiscriminators calls fn for every schema in the node tree that declares a discriminator
+func walkDiscriminators(node *yaml.Node, fn func(schema, discriminator *yaml.Node)) {
+	if node == nil {
+		return
+	}
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, item := range node.Content {
+			walkDiscriminators(item, fn)
+		}
+	case yaml.MappingNode:
+		if discriminator := getNodeValue(node, "discriminator"); discriminator != nil && discriminator.Kind == yaml.MappingNode {
+			fn(node, discriminator)
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			walkDiscriminators(node.Content[i], fn)
+		}
+	}
+}
+
+// renameDiscriminatorProperties points the discriminators whose propertyName was renamed (old name
+// -> new name) at the new name and returns how many it updated. A name is only followed when it
+// now names a property somewhere in the document, so renaming an extension key never changes a
+// discriminator.
+func renameDiscriminatorProperties(root *yaml.Node, renames map[string]string) int {
+	if len(renames) == 0 {
+		return 0
+	}
+	properties := propertyNames(root, make(map[string]bool))
+
+	updated := 0
+	walkDiscriminators(root, func(_, discriminator *yaml.Node) {
+		propertyName := getNodeValue(discriminator, "propertyName")
+		if propertyName == nil || propertyName.Kind != yaml.ScalarNode {
+			return
+		}
+		if newName, ok := renames[propertyName.Value]; ok && properties[newName] {
+			propertyName.Value = newName
+			updated++
+		}
+	})
+	return updated
+}
+
+// propertyNames adds the keys of every properties map in the node tree to names
+func propertyNames(node *yaml.Node, names map[string]bool) map[string]bool {
+	if node == nil {
+		return names
+	}
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, item := range node.Content {
+			propertyNames(item, names)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if value := node.Content[i+1]; node.Content[i].Value == "properties" && value.Kind == yaml.MappingNode {
+				for j := 0; j < len(value.Content); j += 2 {
+					names[value.Content[j].Value] = true
+				}
+			}
+			propertyNames(node.Content[i+1], names)
+		}
+	}
+	return names
+}
+
+// pinDiscriminatorValues adds an explicit mapping entry for every schema about to be renamed (old
+// name -> new name) that a discriminator selects by its name alone: a oneOf or anyOf member
+// without a mapping entry, or a schema extending the discriminator's schema through allOf. The
+// entry maps the old name, which payloads keep sending, to the schema's $ref; rewriting the refs
+// afterwards points it at the new name. Call it before the schemas are renamed.
+func pinDiscriminatorValues(root *yaml.Node, schemaRenames map[string]string) int {
+	if len(schemaRenames) == 0 {
+		return 0
+	}
+	schemas := getNodeValue(getNodeValue(root, "components"), "schemas")
+
+	pinned := 0
+	walkDiscriminators(root, func(schema, discriminator *yaml.Node) {
+		for _, name := range implicitDiscriminatorSchemas(schema, schemas) {
+			if _, renamed := schemaRenames[name]; !renamed || discriminatorMapsSchema(discriminator, name) {
+				continue
+			}
+			mapping := getNodeValue(discriminator, "mapping")
+			if mapping == nil {
+				mapping = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				discriminator.Content = append(discriminator.Content, newScalarNode("mapping"), mapping)
+			}
+			if mapping.Kind != yaml.MappingNode || getNodeValue(mapping, name) != nil {
+				continue
+			}
+			mapping.Content = append(mapping.Content, newScalarNode(name), newScalarNode(componentRef("schemas", name)))
+			pinned++
+		}
+	})
+	return pinned
+}
+
+// implicitDiscriminatorSchemas returns the names of the schemas a discriminator on schema can
+// select, in document order: its oneOf and anyOf members, and the component schemas extending it
+// through allOf
+func implicitDiscriminatorSchemas(schema, schemas *yaml.Node) []string {
+	var names []string
+	for _, key := range []string{"oneOf", "anyOf"} {
+		members := getNodeValue(schema, key)
+		if members == nil || members.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, member := range members.Content {
+			if name, ok := schemaRefName(getStringValue(member, "$ref")); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	if schemas == nil || schemas.Kind != yaml.MappingNode {
+		return names
+	}
+	ref := ""
+	for i := 0; i+1 < len(schemas.Content); i += 2 {
+		if schemas.Content[i+1] == schema {
+			ref = componentRef("schemas", schemas.Content[i].Value)
+		}
+	}
+	if ref == "" {
+		return names
+	}
+	for i := 0; i+1 < len(schemas.Content); i += 2 {
+		allOf := getNodeValue(schemas.Content[i+1], "allOf")
+		if allOf == nil || allOf.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, part := range allOf.Content {
+			if getStringValue(part, "$ref") == ref {
+				names = append(names, schemas.Content[i].Value)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// schemaRefName returns the schema a $ref points at when it is a local component schema ref
+func schemaRefName(ref string) (string, bool) {
+	name, ok := strings.CutPrefix(ref, componentRef("schemas", ""))
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return name, true
+}
+
+// discriminatorMapsSchema reports whether the discriminator's mapping has an entry selecting the
+// schema, by $ref or by bare name
+func discriminatorMapsSchema(discriminator *yaml.Node, name string) bool {
+	mapping := getNodeValue(discriminator, "mapping")
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 1; i < len(mapping.Content); i += 2 {
+		if value := mapping.Content[i].Value; value == name || value == componentRef("schemas", name) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteDiscriminatorMapping rewrites discriminator mapping values, which may be
+// either full $refs or bare schema names
+func (rw *refRewriter) rewriteDiscriminatorMapping(discriminator *yaml.Node) {
+	mapping := getNodeValue(discriminator, "mapping")
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 1; i < len(mapping.Content); i += 2 {
+		value := mapping.Content[i]
+		if value.Kind != yaml.ScalarNode || rw.rewriteRefValue(value) {
+			continue
+		}
+		if newName, ok := rw.schemaRenames[value.Value]; ok {
+			rw.counts[componentRef("schemas", value.Value)]++
+			value.Value = newName
+		}
+	}
+}
+
+// swagger2DiscriminatorSchemas returns the definitions of a Swagger 2.0 document that a
+// discriminator selects by their name: the definitions declaring a discriminator and those
+// extending one through allOf. Swagger 2.0 has no mapping to pin the old name in, so renaming or
+// merging them would change the values payloads carry.
+func swagger2DiscriminatorSchemas(root *yaml.Node) map[string]bool {
+	names := make(map[string]bool)
+	definitions := getNodeValue(root, "definitions")
+	if getNodeValue(root, "swagger") == nil || definitions == nil || definitions.Kind != yaml.MappingNode {
+		return names
+	}
+
+	bases := make(map[string]bool)
+	for i := 0; i+1 < len(definitions.Content); i += 2 {
+		if getNodeValue(definitions.Content[i+1], "discriminator") != nil {
+			names[definitions.Content[i].Value] = true
+			bases["#/definitions/"+definitions.Content[i].Value] = true
+		}
+	}
+	for i := 0; i+1 < len(definitions.Content); i += 2 {
+		allOf := getNodeValue(definitions.Content[i+1], "allOf")
+		if allOf == nil || allOf.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, part := range allOf.Content {
+			if bases[getStringValue(part, "$ref")] {
+				names[definitions.Content[i].Value] = true
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,173 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const discriminatorSpec = `openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        petType:
+          type: string
+      discriminator:
+        propertyName: petType
+    CatDto:
+      allOf:
+        - $ref: '#/components/schemas/Pet'
+    Animal:
+      oneOf:
+        - $ref: '#/components/schemas/DogDto'
+        - $ref: '#/components/schemas/BirdDto'
+      discriminator:
+        propertyName: petType
+        mapping:
+          bird: '#/components/schemas/BirdDto'
+    DogDto:
+      type: object
+    BirdDto:
+      type: object
+`
+
+func TestRenameDiscriminatorProperties(t *testing.T) {
+	tests := []struct {
+		name           string
+		renames        map[string]string
+		renameProperty bool // whether the mappings renamed the petType property itself
+		want           string
+	}{
+		{"renamed property", map[string]string{"petType": "pet_type"}, true, "pet_type"},
+		{"new name is not a property", map[string]string{"petType": "pet_type"}, false, "petType"},
+		{"other key renamed", map[string]string{"type": "kind"}, false, "petType"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := parseYAMLToNode(t, discriminatorSpec)
+			if tt.renameProperty {
+				properties := getNodeValue(getNodeValue(getNodeValue(getNodeValue(root, "components"), "schemas"), "Pet"), "properties")
+				properties.Content[0].Value = tt.renames["petType"]
+			}
+
+			renameDiscriminatorProperties(root, tt.renames)
+			walkDiscriminators(root, func(_, discriminator *yaml.Node) {
+				if got := getStringValue(discriminator, "propertyName"); got != tt.want {
+					t.Errorf("expected propertyName %q, got %q", tt.want, got)
+				}
+			})
+		})
+	}
+}
+
+func TestProcessComponentRenamesInDir_PinsDiscriminatorValues(t *testing.T) {
+	dir, path := writeTestSpec(t, discriminatorSpec)
+	opts := RenameOptions{ComponentRenames: config.ComponentRenames{Enabled: true, StripSuffixes: []string{"Dto"}}}
+	if _, err := ProcessComponentRenamesInDir(dir, opts); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+
+	for _, want := range []string{
+		"CatDto: '#/components/schemas/Cat'",  // allOf subschema selected by its old name
+		"DogDto: '#/components/schemas/Dog'",  // oneOf member selected by its old name
+		"bird: '#/components/schemas/Bird'",   // explicit entry follows the rename
+		"- $ref: '#/components/schemas/Bird'", // and so do the members
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "BirdDto:") {
+		t.Errorf("expected no entry pinning an explicitly mapped schema:\n%s", content)
+	}
+}
+
+func TestProcessComponentDedupInDir_PinsDiscriminatorValues(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Kitten'
+      discriminator:
+        propertyName: petType
+    Cat:
+      type: object
+    Kitten:
+      type: object
+`
+	dir, path := writeTestSpec(t, spec)
+	if _, err := ProcessComponentDedupInDir(dir, DedupOptions{ComponentDedup: config.ComponentDedup{Enabled: true}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Kitten: '#/components/schemas/Cat'") {
+		t.Errorf("expected the merged schema's old name to keep selecting it:\n%s", data)
+	}
+}
+
+func TestFileWithChanges_RenamesDiscriminatorProperty(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		input string
+		want  string
+	}{
+		{
+			name:  "yaml",
+			file:  "api.yaml",
+			input: discriminatorSpec,
+			want:  "propertyName: pet_type",
+		},
+		{
+			name: "json",
+			file: "api.json",
+			input: `{"openapi": "3.0.0", "components": {"schemas": {"Pet": {
+  "properties": {"petType": {"type": "string"}},
+  "discriminator": {"propertyName": "petType"}}}}}`,
+			want: `"propertyName": "pet_type"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.input), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := FileWithChanges(path, Options{Mappings: map[string]string{"petType": "pet_type"}}, nil); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("expected output to contain %q:\n%s", tt.want, data)
+			}
+		})
+	}
+}
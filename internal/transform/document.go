@@ -0,0 +1,57 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TransformBytes runs the pipeline on an in-memory document and returns the transformed content.
+// name is the document's path as the caller knows it: its base name and extension decide how the
+// document is parsed, and every path in the returned results is reported against it. Documents
+// without a .yaml, .yml or .json extension are treated as JSON when they start with '{' and as
+// YAML otherwise. The pipeline's DryRun, Backup and OutputFile settings are ignored.
+func (tp *TransformationPipeline) TransformBytes(name string, content []byte) ([]byte, *TransformationResults, error) {
+	tempDir, err := os.MkdirTemp("", "openmorph_document_*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tempFilePath := filepath.Join(tempDir, documentFileName(name, content))
+	if err := os.WriteFile(tempFilePath, content, 0600); err != nil {
+		return nil, nil, fmt.Errorf("failed to write temp file: %v", err)
+	}
+
+	documentPipeline := NewTransformationPipeline(tp.Config, tp.VendorProviders, false, false, "")
+	documentPipeline.Context = tp.Context
+	documentPipeline.Log = tp.Log
+	results, err := documentPipeline.ExecuteFullPipeline(tempFilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transformed, err := os.ReadFile(tempFilePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read transformed document: %v", err)
+	}
+
+	rebaseResults(results, tempFilePath, name)
+	return transformed, results, nil
+}
+
+// documentFileName returns the file name an in-memory document is staged under
+func documentFileName(name string, content []byte) string {
+	base := filepath.Base(name)
+	if base == "." || base == string(filepath.Separator) {
+		base = "document"
+	}
+	if IsYAML(base) || IsJSON(base) {
+		return base
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		return base + ".json"
+	}
+	return base + ".yaml"
+}
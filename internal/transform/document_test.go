@@ -0,0 +1,57 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestTransformBytes(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: bar
+      responses:
+        "200":
+          description: Success
+`
+	cfg := &config.Config{Mappings: map[string]string{"x-foo": "x-bar"}}
+	pipeline := NewTransformationPipeline(cfg, nil, true, true, "ignored.yaml")
+
+	output, results, err := pipeline.TransformBytes("specs/api.yaml", []byte(spec))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+	if !strings.Contains(string(output), "x-bar: bar") {
+		t.Errorf("expected transformed content, got:\n%s", output)
+	}
+	if len(results.Changed) != 1 || results.Changed[0] != "specs/api.yaml" {
+		t.Errorf("expected results reported against the document name, got %v", results.Changed)
+	}
+	locations := results.AllLocations()
+	if len(locations) != 1 || locations[0].Position() != "specs/api.yaml:8:7" {
+		t.Errorf("unexpected locations: %+v", locations)
+	}
+}
+
+func TestDocumentFileName(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"specs/api.yml", "openapi: 3.0.0", "api.yml"},
+		{"api.JSON", "{}", "api.JSON"},
+		{"untitled-1", `  {"openapi": "3.0.0"}`, "untitled-1.json"},
+		{"untitled-2", "openapi: 3.0.0", "untitled-2.yaml"},
+		{"", "openapi: 3.0.0", "document.yaml"},
+	}
+	for _, tt := range tests {
+		if got := documentFileName(tt.name, []byte(tt.content)); got != tt.want {
+			t.Errorf("documentFileName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// objectShapeKeys are the keywords that give an object schema a shape; an object schema with none
+// of them accepts any object without saying so
+var objectShapeKeys = []string{
+	"properties", "additionalProperties", "patternProperties", "propertyNames", "unevaluatedProperties",
+	"minProperties", "maxProperties", "allOf", "oneOf", "anyOf", "not", "$ref", "enum", "const", "discriminator",
+}
+
+// EmptySchema is an object schema, a media type or a response content a step left empty
+type EmptySchema struct {
+	StrictIssue
+	Step  string // step after which it became empty
+	Fixed bool   // the empty media type or content was removed, or the object allowed any properties
+}
+
+// checkEmptyAfterStep runs a step that processes the files under dir and, when it is one that can
+// remove content and it changed something, records the object schemas and response contents it
+// left empty in results.EmptySchemas, fixing them when configured. Schemas that were already empty
+// before the step are not reported. Dry runs leave the files as they were, so there is nothing to
+// check.
+func (tp *TransformationPipeline) checkEmptyAfterStep(dir, step string, opts Options, results *TransformationResults, apply func() error) error {
+	settings := tp.Config.EmptySchemas
+	if settings.Disabled || opts.DryRun || !destructiveStepEnabled(tp.Config, step) {
+		return apply()
+	}
+
+	// Steps in between may rename paths, so the baseline is taken right before each step
+	before, err := findEmptySchemas(dir, opts.Files, nil, false)
+	if err != nil {
+		return fmt.Errorf("failed to check for empty schemas: %v", err)
+	}
+	baseline := emptyKeys(before)
+
+	if err := apply(); err != nil {
+		return err
+	}
+	if changes, _ := stepChanges(step, results); changes == 0 {
+		return nil
+	}
+
+	empty, err := findEmptySchemas(dir, opts.Files, baseline, settings.Fix)
+	if err != nil {
+		return fmt.Errorf("failed to check for empty schemas: %v", err)
+	}
+	for _, schema := range empty {
+		if !baseline[refKey(schema.StrictIssue)] {
+			schema.Step = step
+			results.EmptySchemas = append(results.EmptySchemas, schema)
+		}
+	}
+	return nil
+}
+
+// findEmptySchemas returns the empty object schemas and response contents of the OpenAPI documents
+// under dir. With fix, those not in baseline are fixed and their documents written.
+func findEmptySchemas(dir string, files config.FileFilter, baseline map[string]bool, fix bool) ([]EmptySchema, error) {
+	var empty []EmptySchema
+	err := walkOpenAPIDocuments(dir, func(path string, doc, root *yaml.Node) error {
+		if !IncludesFile(files, "", dir, path) {
+			return nil
+		}
+		found := documentEmptySchemas(path, root, baseline, fix)
+		empty = append(empty, found...)
+		for _, schema := range found {
+			if schema.Fixed {
+				_, err := writeModifiedDocument(doc, path)
+				return err
+			}
+		}
+		return nil
+	})
+	return empty, err
+}
+
+// documentEmptySchemas returns the empty object schemas and response contents of a document,
+// fixing those not in baseline when fix is set
+func documentEmptySchemas(path string, root *yaml.Node, baseline map[string]bool, fix bool) []EmptySchema {
+	var empty []EmptySchema
+	record := func(node *yaml.Node, message string, apply func()) {
+		schema := EmptySchema{StrictIssue: StrictIssue{File: path, Line: node.Line, Column: node.Column, Message: message}}
+		if fix && !baseline[refKey(schema.StrictIssue)] {
+			apply()
+			schema.Fixed = true
+		}
+		empty = append(empty, schema)
+	}
+
+	walkDocumentSchemas(root, func(schema *yaml.Node, target schemaTarget) bool {
+		if isEmptyObjectSchema(schema) {
+			record(schema, target.context+": object schema has no properties", func() {
+				setMappingValue(schema, "additionalProperties", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+			})
+		}
+		return false
+	})
+
+	visitResponse := func(context string, response *yaml.Node) {
+		content := getNodeValue(response, "content")
+		if content == nil || content.Kind != yaml.MappingNode {
+			return
+		}
+		for i := len(content.Content) - 2; i >= 0; i -= 2 {
+			mediaType, media := content.Content[i].Value, content.Content[i+1]
+			if media.Kind == yaml.MappingNode && len(media.Content) == 0 {
+				record(media, context+" "+mediaType+": media type has no schema", func() {
+					removeMappingKey(content, mediaType)
+				})
+			}
+		}
+		if len(content.Content) == 0 {
+			record(content, context+": response content is empty", func() {
+				removeMappingKey(response, "content")
+			})
+		}
+	}
+	if paths := getNodeValue(root, "paths"); paths != nil && paths.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+			if pathItem.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j+1 < len(pathItem.Content); j += 2 {
+				method, operation := pathItem.Content[j].Value, pathItem.Content[j+1]
+				responses := getNodeValue(operation, "responses")
+				if !isHTTPMethod(method) || responses == nil || responses.Kind != yaml.MappingNode {
+					continue
+				}
+				for k := 0; k+1 < len(responses.Content); k += 2 {
+					visitResponse(fmt.Sprintf("%s %s response %s", strings.ToUpper(method), pathName, responses.Content[k].Value), responses.Content[k+1])
+				}
+			}
+		}
+	}
+	if responses := getNodeValue(getNodeValue(root, "components"), "responses"); responses != nil && responses.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(responses.Content); i += 2 {
+			visitResponse("component "+componentKey("responses", responses.Content[i].Value), responses.Content[i+1])
+		}
+	}
+	return empty
+}
+
+// isEmptyObjectSchema reports whether schema is `type: object` without any keyword giving it a
+// shape; an empty properties mapping gives it none
+func isEmptyObjectSchema(schema *yaml.Node) bool {
+	if getStringValue(schema, "type") != "object" {
+		return false
+	}
+	for _, key := range objectShapeKeys {
+		value := getNodeValue(schema, key)
+		if value != nil && !(key == "properties" && value.Kind == yaml.MappingNode && len(value.Content) == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// emptyKeys returns the keys of the given empty schemas
+func emptyKeys(empty []EmptySchema) map[string]bool {
+	keys := make(map[string]bool, len(empty))
+	for _, schema := range empty {
+		keys[refKey(schema.StrictIssue)] = true
+	}
+	return keys
+}
+
+// normalizeEmptySchemas reports the empty schemas found on a temporary copy against inputPath
+func normalizeEmptySchemas(inputPath string, results *TransformationResults) {
+	for i := range results.EmptySchemas {
+		results.EmptySchemas[i].File = inputPath
+	}
+}
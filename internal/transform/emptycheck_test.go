@@ -0,0 +1,172 @@
+package transform
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const emptyCheckSpec = `openapi: 3.0.3
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /audit:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/AuditLog'
+components:
+  schemas:
+    AuditLog:
+      type: object
+      x-internal: true
+    Metadata:
+      type: object
+    Secret:
+      type: object
+      properties:
+        token:
+          type: string
+          x-internal: true
+`
+
+func TestEmptySchemasAfterStep(t *testing.T) {
+	dir, _ := writeTestSpec(t, emptyCheckSpec)
+
+	cfg := &config.Config{StripInternal: config.StripInternal{Enabled: true}}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []string
+	for _, schema := range results.EmptySchemas {
+		if schema.Step != StepStripInternal || schema.Fixed || schema.Line == 0 {
+			t.Errorf("unexpected empty schema %+v", schema)
+		}
+		messages = append(messages, schema.Message)
+	}
+	// Metadata was already a free-form object before the step, so it is not reported
+	want := "component schemas.Secret: object schema has no properties\n" +
+		"GET /audit response 200 application/json: media type has no schema"
+	if got := strings.Join(messages, "\n"); got != want {
+		t.Errorf("unexpected empty schemas:\n%s", got)
+	}
+}
+
+func TestEmptySchemasFix(t *testing.T) {
+	dir, path := writeTestSpec(t, emptyCheckSpec)
+
+	cfg := &config.Config{
+		StripInternal: config.StripInternal{Enabled: true},
+		EmptySchemas:  config.EmptySchemas{Fix: true},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Removing the media type leaves the content empty, which is removed in turn
+	if len(results.EmptySchemas) != 3 {
+		t.Fatalf("expected 3 empty schemas, got %+v", results.EmptySchemas)
+	}
+	for _, schema := range results.EmptySchemas {
+		if !schema.Fixed {
+			t.Errorf("expected %q to be fixed", schema.Message)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Paths      map[string]map[string]map[string]map[string]map[string]interface{} `yaml:"paths"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc.Paths["/audit"]["get"]["responses"]["200"]["content"]; ok {
+		t.Errorf("expected the empty content to be removed, got:\n%s", data)
+	}
+	if doc.Components.Schemas["Secret"]["additionalProperties"] != true {
+		t.Errorf("expected Secret to allow any properties, got %v", doc.Components.Schemas["Secret"])
+	}
+	if _, ok := doc.Components.Schemas["Metadata"]["additionalProperties"]; ok {
+		t.Errorf("expected the object that was empty before to be left alone, got %v", doc.Components.Schemas["Metadata"])
+	}
+}
+
+func TestEmptySchemasCheckDisabled(t *testing.T) {
+	dir, _ := writeTestSpec(t, emptyCheckSpec)
+
+	cfg := &config.Config{
+		StripInternal: config.StripInternal{Enabled: true},
+		EmptySchemas:  config.EmptySchemas{Disabled: true},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.EmptySchemas) != 0 {
+		t.Errorf("expected no check when disabled, got %+v", results.EmptySchemas)
+	}
+}
+
+func TestEmptySchemasAfterPathRenames(t *testing.T) {
+	dir, _ := writeTestSpec(t, `openapi: 3.0.3
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /a:
+    get:
+      responses:
+        "200":
+          description: OK
+          content: {}
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: offset
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+`)
+
+	// tenant_paths renames every path between strip_internal and pagination, so the content of /a,
+	// empty from the start, has another name by the time pagination runs
+	cfg := &config.Config{
+		StripInternal:      config.StripInternal{Enabled: true},
+		TenantPaths:        config.TenantPaths{Enabled: true},
+		PaginationPriority: []string{"cursor", "offset"},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.PaginationResult == nil || !results.PaginationResult.Changed {
+		t.Fatal("expected the pagination step to change the document")
+	}
+	if len(results.EmptySchemas) != 0 {
+		t.Errorf("expected the content that was empty before not to be reported, got %+v", results.EmptySchemas)
+	}
+}
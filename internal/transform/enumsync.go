@@ -0,0 +1,72 @@
+package transform
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// enumNameExtensions list a generated name for each enum value, in enum order
+var enumNameExtensions = []string{"x-enum-varnames", "x-enumNames"}
+
+// enumDescriptionExtensions list a description for each enum value, in enum order
+var enumDescriptionExtensions = []string{"x-enum-descriptions", "x-enumDescriptions"}
+
+// replaceEnumValues replaces the values of a schema's enum with values and keeps the extensions
+// that list one entry per value in step with it: each entry follows its value, and values that
+// were not in the enum get a name derived from the value and an empty description. Extensions
+// whose length did not match the enum are left alone, as their positions cannot be trusted.
+func replaceEnumValues(schema, enum *yaml.Node, values []*yaml.Node) {
+	previous := enum.Content
+	enum.Content = values
+
+	for _, key := range append(append([]string{}, enumNameExtensions...), enumDescriptionExtensions...) {
+		entries := getNodeValue(schema, key)
+		if entries == nil || entries.Kind != yaml.SequenceNode || len(entries.Content) != len(previous) {
+			continue
+		}
+
+		synced := make([]*yaml.Node, len(values))
+		for i, value := range values {
+			if j := enumValueIndex(previous, value); j >= 0 {
+				synced[i] = entries.Content[j]
+			} else if contains(enumNameExtensions, key) {
+				synced[i] = newScalarNode(enumValueName(value, entries.Content))
+			} else {
+				synced[i] = newScalarNode("")
+			}
+		}
+		entries.Content = synced
+	}
+}
+
+// enumValueIndex returns the index of value among the previous enum values, matching the node
+// itself first and then an equal scalar, or -1
+func enumValueIndex(previous []*yaml.Node, value *yaml.Node) int {
+	for i, node := range previous {
+		if node == value {
+			return i
+		}
+	}
+	for i, node := range previous {
+		if node.Kind == yaml.ScalarNode && value.Kind == yaml.ScalarNode && node.Tag == value.Tag && node.Value == value.Value {
+			return i
+		}
+	}
+	return -1
+}
+
+// enumValueName derives the name of a new enum value in the style of the existing names:
+// UPPER_SNAKE when they are all upper case, PascalCase otherwise
+func enumValueName(value *yaml.Node, names []*yaml.Node) string {
+	upper := len(names) > 0
+	for _, name := range names {
+		if name.Value != strings.ToUpper(name.Value) {
+			upper = false
+		}
+	}
+	if upper {
+		return strings.ToUpper(applyCasing(value.Value, CasingSnake))
+	}
+	return applyCasing(value.Value, CasingPascal)
+}
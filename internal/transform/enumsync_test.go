@@ -0,0 +1,43 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestReplaceEnumValues(t *testing.T) {
+	var doc yaml.Node
+	spec := `type: string
+enum: [active, disabled, archived]
+x-enum-varnames: [ACTIVE, DISABLED, ARCHIVED]
+x-enumNames: [Active, Disabled, Archived]
+x-enum-descriptions: [In use, Turned off, Kept for history]
+x-enumDescriptions: [stale]
+`
+	if err := yaml.Unmarshal([]byte(spec), &doc); err != nil {
+		t.Fatal(err)
+	}
+	schema := doc.Content[0]
+	enum := getNodeValue(schema, "enum")
+
+	values := []*yaml.Node{enum.Content[2], enum.Content[0], newScalarNode("pending_review")}
+	replaceEnumValues(schema, enum, values)
+
+	for key, want := range map[string]string{
+		"enum":                "archived,active,pending_review",
+		"x-enum-varnames":     "ARCHIVED,ACTIVE,PENDING_REVIEW",
+		"x-enumNames":         "Archived,Active,PendingReview",
+		"x-enum-descriptions": "Kept for history,In use,",
+		"x-enumDescriptions":  "stale", // did not match the enum before, so it is left alone
+	} {
+		var got []string
+		for _, item := range getNodeValue(schema, key).Content {
+			got = append(got, item.Value)
+		}
+		if strings.Join(got, ",") != want {
+			t.Errorf("%s = %v, want %s", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// defaultEnvelope is the envelope unwrapped when none are configured
+var defaultEnvelope = config.EnvelopeShape{DataField: "data", Fields: []string{"code", "message"}}
+
+// EnvelopeOptions extends the regular Options with envelope unwrapping settings
+type EnvelopeOptions struct {
+	Options
+	UnwrapEnvelopes config.UnwrapEnvelopes
+}
+
+// EnvelopeResult represents the result of response envelope unwrapping
+type EnvelopeResult struct {
+	Changed            bool
+	ProcessedFiles     []string
+	UnwrappedResponses map[string][]string // file -> list of unwrapped responses
+	RemovedComponents  map[string][]string // file -> list of envelope components no longer referenced
+	Locations          []ChangeLocation    // source positions of the unwrapped schemas
+}
+
+// createEnvelopeResult creates a new EnvelopeResult with initialized maps
+func createEnvelopeResult() *EnvelopeResult {
+	return &EnvelopeResult{
+		ProcessedFiles:     []string{},
+		UnwrappedResponses: make(map[string][]string),
+		RemovedComponents:  make(map[string][]string),
+	}
+}
+
+// setEnvelopeProcessedFiles sets the processed files for an EnvelopeResult
+func setEnvelopeProcessedFiles(result *EnvelopeResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setEnvelopeChanged sets the changed flag for an EnvelopeResult
+func setEnvelopeChanged(result *EnvelopeResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessEnvelopesInDir unwraps enveloped success responses in all OpenAPI files in a directory
+func ProcessEnvelopesInDir(dir string, opts EnvelopeOptions) (*EnvelopeResult, error) {
+	if err := ValidateUnwrapEnvelopes(opts.UnwrapEnvelopes); err != nil {
+		return createEnvelopeResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepUnwrapEnvelopes,
+		opts.Options,
+		opts.UnwrapEnvelopes.Enabled,
+		false,
+		createEnvelopeResult,
+		func(path string, result *EnvelopeResult) (bool, error) {
+			return processEnvelopesInFile(path, opts, result)
+		},
+		setEnvelopeProcessedFiles,
+		setEnvelopeChanged,
+	)
+}
+
+// ValidateUnwrapEnvelopes checks that every configured envelope names its data field
+func ValidateUnwrapEnvelopes(unwrap config.UnwrapEnvelopes) error {
+	for i, envelope := range unwrap.Envelopes {
+		if envelope.DataField == "" {
+			return fmt.Errorf("unwrap_envelopes.envelopes[%d]: data_field is required", i)
+		}
+	}
+	if unwrap.Extension != "" && !strings.HasPrefix(unwrap.Extension, "x-") {
+		return fmt.Errorf("unwrap_envelopes.extension: %q must start with x-", unwrap.Extension)
+	}
+	return nil
+}
+
+// processEnvelopesInFile unwraps enveloped success responses in a single file
+func processEnvelopesInFile(path string, opts EnvelopeOptions, result *EnvelopeResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	components := getNodeValue(root, "components")
+	var reachableBefore map[string]bool
+	if components != nil && components.Kind == yaml.MappingNode {
+		reachableBefore = componentClosure(root)
+	}
+
+	u := &envelopeUnwrapper{
+		root:      root,
+		envelopes: opts.UnwrapEnvelopes.Envelopes,
+		extension: opts.UnwrapEnvelopes.Extension,
+		path:      path,
+		result:    result,
+		visited:   make(map[*yaml.Node]bool),
+	}
+	if len(u.envelopes) == 0 {
+		u.envelopes = []config.EnvelopeShape{defaultEnvelope}
+	}
+	if !u.unwrapPaths() {
+		return false, nil
+	}
+
+	if pruned := pruneUnreachableComponents(root, reachableBefore); len(pruned) > 0 {
+		result.RemovedComponents[path] = append(result.RemovedComponents[path], pruned...)
+	}
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// envelopeUnwrapper replaces enveloped response schemas in one document
+type envelopeUnwrapper struct {
+	root      *yaml.Node
+	envelopes []config.EnvelopeShape
+	extension string
+	path      string
+	result    *EnvelopeResult
+	visited   map[*yaml.Node]bool // shared response objects already unwrapped
+}
+
+// unwrapPaths unwraps the 2xx responses of every operation
+func (u *envelopeUnwrapper) unwrapPaths() bool {
+	paths := getNodeValue(u.root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return false
+	}
+
+	changed := false
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			method, operation := pathItem.Content[j].Value, pathItem.Content[j+1]
+			if !isHTTPMethod(method) {
+				continue
+			}
+			responses := getNodeValue(operation, "responses")
+			if responses == nil || responses.Kind != yaml.MappingNode {
+				continue
+			}
+			for k := 0; k+1 < len(responses.Content); k += 2 {
+				code := responses.Content[k].Value
+				if !isTwoHundredCode(code) {
+					continue
+				}
+				context := fmt.Sprintf("%s %s %s", strings.ToUpper(method), pathName, code)
+				if u.unwrapResponse(responses.Content[k+1], context) {
+					changed = true
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// unwrapResponse unwraps the schema of every media type of a response, following a local $ref
+// to a shared response once
+func (u *envelopeUnwrapper) unwrapResponse(response *yaml.Node, context string) bool {
+	if ref := getStringValue(response, "$ref"); ref != "" {
+		if !strings.HasPrefix(ref, "#/") {
+			return false
+		}
+		response = resolvePointer(u.root, strings.TrimPrefix(ref, "#"))
+	}
+	if response == nil || u.visited[response] {
+		return false
+	}
+	u.visited[response] = true
+
+	content := getNodeValue(response, "content")
+	if content == nil || content.Kind != yaml.MappingNode {
+		return false
+	}
+
+	changed := false
+	for i := 0; i+1 < len(content.Content); i += 2 {
+		mediaType, mediaTypeNode := content.Content[i].Value, content.Content[i+1]
+		if u.unwrapMediaType(mediaTypeNode, context+" "+mediaType) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// unwrapMediaType replaces an enveloped media type schema with the schema of its data field
+func (u *envelopeUnwrapper) unwrapMediaType(mediaType *yaml.Node, context string) bool {
+	schema := getNodeValue(mediaType, "schema")
+	if schema == nil {
+		return false
+	}
+
+	envelopeSchema := schema
+	envelopeName := "inline envelope"
+	if ref := getStringValue(schema, "$ref"); ref != "" {
+		if !strings.HasPrefix(ref, "#/") {
+			return false
+		}
+		envelopeSchema = resolvePointer(u.root, strings.TrimPrefix(ref, "#"))
+		envelopeName = ref[strings.LastIndex(ref, "/")+1:]
+	}
+
+	envelope, ok := u.matchEnvelope(envelopeSchema)
+	if !ok {
+		return false
+	}
+	data := getNodeValue(getNodeValue(envelopeSchema, "properties"), envelope.DataField)
+
+	if u.extension != "" {
+		setMappingValue(mediaType, u.extension, envelopeRecord(envelope.DataField, schema))
+	}
+	setMappingValue(mediaType, "schema", cloneNode(data))
+
+	entry := fmt.Sprintf("%s: %s.%s", context, envelopeName, envelope.DataField)
+	u.result.UnwrappedResponses[u.path] = append(u.result.UnwrappedResponses[u.path], entry)
+	u.result.Locations = append(u.result.Locations, newChangeLocation(u.path, StepUnwrapEnvelopes, schema, entry))
+	return true
+}
+
+// matchEnvelope returns the configured envelope a schema has the shape of: an object with the
+// data property and no properties besides the envelope's fields
+func (u *envelopeUnwrapper) matchEnvelope(schema *yaml.Node) (config.EnvelopeShape, bool) {
+	properties := getNodeValue(schema, "properties")
+	if properties == nil || properties.Kind != yaml.MappingNode {
+		return config.EnvelopeShape{}, false
+	}
+	for _, composition := range []string{"allOf", "oneOf", "anyOf"} {
+		if getNodeValue(schema, composition) != nil {
+			return config.EnvelopeShape{}, false
+		}
+	}
+
+	for _, envelope := range u.envelopes {
+		if getNodeValue(properties, envelope.DataField) == nil {
+			continue
+		}
+		allowed := map[string]bool{envelope.DataField: true}
+		for _, field := range envelope.Fields {
+			allowed[field] = true
+		}
+		matches := true
+		for i := 0; i < len(properties.Content); i += 2 {
+			if !allowed[properties.Content[i].Value] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return envelope, true
+		}
+	}
+	return config.EnvelopeShape{}, false
+}
+
+// envelopeRecord builds the extension value recording a removed envelope: its data field and
+// the envelope schema, so SDK generators can still surface envelope fields such as error codes
+func envelopeRecord(dataField string, schema *yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+		newScalarNode("data_field"), newScalarNode(dataField),
+		newScalarNode("schema"), cloneNode(schema),
+	}}
+}
+
+// setMappingValue sets key in a mapping node, appending it when missing
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, newScalarNode(key), value)
+}
+
+// cloneNode returns a deep copy of node
+func cloneNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	copied := *node
+	copied.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied.Content[i] = cloneNode(child)
+	}
+	return &copied
+}
+
+// isTwoHundredCode reports whether a response code is a 2xx status or the 2XX range
+func isTwoHundredCode(code string) bool {
+	return len(code) == 3 && code[0] == '2' && (strings.EqualFold(code[1:], "XX") || strings.Trim(code[1:], "0123456789") == "")
+}
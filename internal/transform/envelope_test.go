@@ -0,0 +1,174 @@
+package transform
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const envelopeTestSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/UserEnvelope'
+        "404":
+          description: Not found
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/ErrorEnvelope'
+  /status:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  code:
+                    type: integer
+                  data:
+                    type: string
+  /items:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: array
+                  next_cursor:
+                    type: string
+components:
+  schemas:
+    User:
+      type: object
+    UserEnvelope:
+      type: object
+      properties:
+        code:
+          type: integer
+        message:
+          type: string
+        data:
+          $ref: '#/components/schemas/User'
+    ErrorEnvelope:
+      type: object
+      properties:
+        code:
+          type: integer
+        data:
+          type: object
+`
+
+func TestProcessEnvelopesInDir(t *testing.T) {
+	dir, path := writeTestSpec(t, envelopeTestSpec)
+
+	result, err := ProcessEnvelopesInDir(dir, EnvelopeOptions{UnwrapEnvelopes: config.UnwrapEnvelopes{Enabled: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed {
+		t.Fatal("expected envelopes to be unwrapped")
+	}
+
+	want := []string{
+		"GET /users/{id} 200 application/json: UserEnvelope.data",
+		"GET /status 200 application/json: inline envelope.data",
+	}
+	if got := result.UnwrappedResponses[path]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected unwrapped responses %v, got %v", want, got)
+	}
+	if got := result.RemovedComponents[path]; len(got) != 1 || !strings.Contains(got[0], "UserEnvelope") {
+		t.Errorf("expected the UserEnvelope component to be removed, got %v", got)
+	}
+	if len(result.Locations) != 2 || result.Locations[0].Line != 14 {
+		t.Errorf("expected locations at the envelope schemas, got %+v", result.Locations)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if !strings.Contains(content, "$ref: '#/components/schemas/User'\n") {
+		t.Errorf("expected the response to reference User directly, got:\n%s", content)
+	}
+	// Error responses and objects with properties outside the envelope are left alone
+	for _, s := range []string{"ErrorEnvelope:", "next_cursor:"} {
+		if !strings.Contains(content, s) {
+			t.Errorf("expected output to keep %q, got:\n%s", s, content)
+		}
+	}
+}
+
+func TestProcessEnvelopesRecordsExtension(t *testing.T) {
+	dir, path := writeTestSpec(t, envelopeTestSpec)
+
+	unwrap := config.UnwrapEnvelopes{
+		Enabled:   true,
+		Envelopes: []config.EnvelopeShape{{DataField: "data", Fields: []string{"code", "message"}}},
+		Extension: "x-response-envelope",
+	}
+	result, err := ProcessEnvelopesInDir(dir, EnvelopeOptions{UnwrapEnvelopes: unwrap})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.RemovedComponents) != 0 {
+		t.Errorf("expected the recorded envelope component to be kept, got %v", result.RemovedComponents)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, s := range []string{"x-response-envelope:", "data_field: data", "$ref: '#/components/schemas/UserEnvelope'", "UserEnvelope:"} {
+		if !strings.Contains(content, s) {
+			t.Errorf("expected output to contain %q, got:\n%s", s, content)
+		}
+	}
+}
+
+func TestProcessEnvelopesDryRun(t *testing.T) {
+	dir, path := writeTestSpec(t, envelopeTestSpec)
+
+	result, err := ProcessEnvelopesInDir(dir, EnvelopeOptions{Options: Options{DryRun: true}, UnwrapEnvelopes: config.UnwrapEnvelopes{Enabled: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed {
+		t.Error("expected dry run to report changes")
+	}
+	if data, _ := os.ReadFile(path); string(data) != envelopeTestSpec {
+		t.Error("expected dry run to leave the file untouched")
+	}
+}
+
+func TestValidateUnwrapEnvelopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		unwrap config.UnwrapEnvelopes
+		want   string
+	}{
+		{"missing data field", config.UnwrapEnvelopes{Envelopes: []config.EnvelopeShape{{Fields: []string{"code"}}}}, "data_field is required"},
+		{"bad extension", config.UnwrapEnvelopes{Extension: "envelope"}, "must start with x-"},
+	}
+	for _, tt := range tests {
+		err := ValidateUnwrapEnvelopes(tt.unwrap)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,641 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Example repair modes
+const (
+	ExamplesModePrune      = "prune"      // drop the properties and items that no longer match, regenerating only examples that cannot be pruned
+	ExamplesModeRegenerate = "regenerate" // replace every invalid example with a minimal one generated from the schema
+)
+
+// maxExampleDepth bounds how deep examples are checked and generated, so recursive schemas terminate
+const maxExampleDepth = 16
+
+// ExamplesOptions extends the regular Options with example repair settings
+type ExamplesOptions struct {
+	Options
+	Examples config.Examples
+}
+
+// ExamplesResult represents the result of example repair
+type ExamplesResult struct {
+	Changed          bool
+	ProcessedFiles   []string
+	RepairedExamples map[string][]string // file -> list of repaired examples
+	Locations        []ChangeLocation    // source positions of the repaired examples
+}
+
+// createExamplesResult creates a new ExamplesResult with initialized maps
+func createExamplesResult() *ExamplesResult {
+	return &ExamplesResult{
+		ProcessedFiles:   []string{},
+		RepairedExamples: make(map[string][]string),
+	}
+}
+
+// setExamplesProcessedFiles sets the processed files for an ExamplesResult
+func setExamplesProcessedFiles(result *ExamplesResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setExamplesChanged sets the changed flag for an ExamplesResult
+func setExamplesChanged(result *ExamplesResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessExamplesInDir repairs the examples that no longer match their schema in all OpenAPI
+// files in a directory
+func ProcessExamplesInDir(dir string, opts ExamplesOptions) (*ExamplesResult, error) {
+	if err := ValidateExamples(opts.Examples); err != nil {
+		return createExamplesResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepExamples,
+		opts.Options,
+		opts.Examples.Enabled,
+		false,
+		createExamplesResult,
+		func(path string, result *ExamplesResult) (bool, error) {
+			return processExamplesInFile(path, opts, result)
+		},
+		setExamplesProcessedFiles,
+		setExamplesChanged,
+	)
+}
+
+// ValidateExamples checks the example repair mode
+func ValidateExamples(examples config.Examples) error {
+	switch examples.Mode {
+	case "", ExamplesModePrune, ExamplesModeRegenerate:
+		return nil
+	default:
+		return fmt.Errorf("examples.mode must be %s or %s, got %q", ExamplesModePrune, ExamplesModeRegenerate, examples.Mode)
+	}
+}
+
+// processExamplesInFile repairs the examples of a single file
+func processExamplesInFile(path string, opts ExamplesOptions, result *ExamplesResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	r := &exampleRepairer{
+		root:       root,
+		regenerate: opts.Examples.Mode == ExamplesModeRegenerate,
+		path:       path,
+		result:     result,
+	}
+	if !r.repairDocument() {
+		return false, nil
+	}
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// exampleRepairer checks the examples of one document against their schemas and repairs them
+type exampleRepairer struct {
+	root       *yaml.Node
+	regenerate bool
+	path       string
+	result     *ExamplesResult
+}
+
+// repairDocument repairs the examples inside schemas first, so examples generated for operations
+// can reuse them, then those of every operation and component
+func (r *exampleRepairer) repairDocument() bool {
+	changed := walkDocumentSchemas(r.root, r.repairSchemaExamples)
+
+	if paths := getNodeValue(r.root, "paths"); paths != nil && paths.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+			if r.repairParameters(getNodeValue(pathItem, "parameters"), pathName) {
+				changed = true
+			}
+			for j := 0; j+1 < len(pathItem.Content); j += 2 {
+				method, operation := pathItem.Content[j].Value, pathItem.Content[j+1]
+				if !isHTTPMethod(method) {
+					continue
+				}
+				context := fmt.Sprintf("%s %s", strings.ToUpper(method), pathName)
+				if r.repairOperation(operation, context) {
+					changed = true
+				}
+			}
+		}
+	}
+
+	components := getNodeValue(r.root, "components")
+	for _, section := range []string{"parameters", "requestBodies", "responses"} {
+		entries := getNodeValue(components, section)
+		if entries == nil || entries.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(entries.Content); i += 2 {
+			entry := entries.Content[i+1]
+			context := "component " + componentKey(section, entries.Content[i].Value)
+			var repaired bool
+			switch section {
+			case "parameters":
+				repaired = r.repairParameter(entry, context)
+			case "requestBodies":
+				repaired = r.repairContent(getNodeValue(entry, "content"), context)
+			case "responses":
+				repaired = r.repairResponse(entry, context)
+			}
+			if repaired {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// repairOperation repairs the examples of an operation's parameters, request body and responses
+func (r *exampleRepairer) repairOperation(operation *yaml.Node, context string) bool {
+	changed := r.repairParameters(getNodeValue(operation, "parameters"), context)
+	if r.repairContent(getNodeValue(getNodeValue(operation, "requestBody"), "content"), context+" requestBody") {
+		changed = true
+	}
+	if responses := getNodeValue(operation, "responses"); responses != nil && responses.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(responses.Content); i += 2 {
+			if r.repairResponse(responses.Content[i+1], context+" response "+responses.Content[i].Value) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// repairParameters repairs the examples of every inline parameter in a parameters list
+func (r *exampleRepairer) repairParameters(params *yaml.Node, context string) bool {
+	if params == nil || params.Kind != yaml.SequenceNode {
+		return false
+	}
+	changed := false
+	for _, param := range params.Content {
+		if r.repairParameter(param, context) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// repairParameter repairs the examples of a parameter, given next to its schema or in its content
+func (r *exampleRepairer) repairParameter(param *yaml.Node, context string) bool {
+	if param == nil || param.Kind != yaml.MappingNode || getNodeValue(param, "$ref") != nil {
+		return false
+	}
+	context += " parameter " + getStringValue(param, "name")
+	changed := r.repairExamples(param, getNodeValue(param, "schema"), context)
+	if r.repairContent(getNodeValue(param, "content"), context) {
+		changed = true
+	}
+	return changed
+}
+
+// repairResponse repairs the examples of a response's media types, or its Swagger 2.0 examples
+func (r *exampleRepairer) repairResponse(response *yaml.Node, context string) bool {
+	if response == nil || response.Kind != yaml.MappingNode || getNodeValue(response, "$ref") != nil {
+		return false
+	}
+	changed := r.repairContent(getNodeValue(response, "content"), context)
+
+	schema, examples := getNodeValue(response, "schema"), getNodeValue(response, "examples")
+	if schema != nil && examples != nil && examples.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(examples.Content); i += 2 {
+			if r.repairValue(examples, i+1, schema, context+" example "+examples.Content[i].Value) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// repairContent repairs the examples of every media type in a content map
+func (r *exampleRepairer) repairContent(content *yaml.Node, context string) bool {
+	if content == nil || content.Kind != yaml.MappingNode {
+		return false
+	}
+	changed := false
+	for i := 0; i+1 < len(content.Content); i += 2 {
+		media := content.Content[i+1]
+		if r.repairExamples(media, getNodeValue(media, "schema"), context+" "+content.Content[i].Value) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// repairExamples repairs the example and the named examples stored next to a schema. Examples
+// given as $refs or external values are left alone.
+func (r *exampleRepairer) repairExamples(holder, schema *yaml.Node, context string) bool {
+	if schema == nil || holder.Kind != yaml.MappingNode {
+		return false
+	}
+	changed := false
+	if index := mappingKeyIndex(holder, "example"); index < len(holder.Content) {
+		changed = r.repairValue(holder, index+1, schema, context+" example")
+	}
+
+	examples := getNodeValue(holder, "examples")
+	if examples == nil || examples.Kind != yaml.MappingNode {
+		return changed
+	}
+	for i := 0; i+1 < len(examples.Content); i += 2 {
+		entry := examples.Content[i+1]
+		if entry.Kind != yaml.MappingNode || getNodeValue(entry, "$ref") != nil {
+			continue
+		}
+		if index := mappingKeyIndex(entry, "value"); index < len(entry.Content) &&
+			r.repairValue(entry, index+1, schema, context+" example "+examples.Content[i].Value) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// repairSchemaExamples repairs the example of a schema, and its OpenAPI 3.1 examples list
+func (r *exampleRepairer) repairSchemaExamples(schema *yaml.Node, target schemaTarget) bool {
+	changed := false
+	if index := mappingKeyIndex(schema, "example"); index < len(schema.Content) {
+		changed = r.repairValue(schema, index+1, schema, target.context+" example")
+	}
+	if examples := getNodeValue(schema, "examples"); examples != nil && examples.Kind == yaml.SequenceNode {
+		for i := range examples.Content {
+			if r.repairValue(examples, i, schema, fmt.Sprintf("%s examples[%d]", target.context, i)) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// repairValue checks the example at parent.Content[index] against schema and prunes or
+// regenerates it
+func (r *exampleRepairer) repairValue(parent *yaml.Node, index int, schema *yaml.Node, context string) bool {
+	value := parent.Content[index]
+	checked := value
+	if r.regenerate {
+		checked = cloneNode(value) // Only look for problems; the example is replaced as a whole
+	}
+
+	issues, valid := r.check(checked, schema, "", 0)
+	if valid && len(issues) == 0 {
+		return false
+	}
+
+	var entry string
+	if r.regenerate || !valid {
+		parent.Content[index] = r.generate(schema, value, 0)
+		entry = fmt.Sprintf("%s: regenerated (%s)", context, strings.Join(issues, ", "))
+	} else {
+		entry = fmt.Sprintf("%s: pruned %s", context, strings.Join(issues, ", "))
+	}
+	r.result.RepairedExamples[r.path] = append(r.result.RepairedExamples[r.path], entry)
+	r.result.Locations = append(r.result.Locations, newChangeLocation(r.path, StepExamples, value, entry))
+	return true
+}
+
+// check compares value, found at the example path at, with schema. Properties the schema does not
+// declare and properties and items that do not match are removed from value and reported; valid
+// is false when value itself does not match.
+func (r *exampleRepairer) check(value, schema *yaml.Node, at string, depth int) (issues []string, valid bool) {
+	schema = r.resolve(schema)
+	if schema == nil || depth > maxExampleDepth || value.Kind == yaml.AliasNode {
+		return nil, true
+	}
+	label := at
+	if label == "" {
+		label = "value"
+	}
+
+	types := r.types(schema, 0)
+	if value.Tag == "!!null" {
+		if len(types) == 0 || isNullableSchema(schema) {
+			return nil, true
+		}
+		return []string{label + " (not nullable)"}, false
+	}
+	if len(types) > 0 && !matchesExampleType(value, types) {
+		return []string{fmt.Sprintf("%s (expected %s)", label, strings.Join(types, " or "))}, false
+	}
+	if enum := getNodeValue(schema, "enum"); enum != nil && enum.Kind == yaml.SequenceNode && !inEnum(enum, value) {
+		return []string{label + " (not in enum)"}, false
+	}
+
+	switch value.Kind {
+	case yaml.MappingNode:
+		shape := r.shape(schema, true, 0)
+		var kept []*yaml.Node
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key := value.Content[i].Value
+			child := key
+			if at != "" {
+				child = at + "." + key
+			}
+			property, declared := shape.properties[key]
+			if !declared {
+				property = shape.additional
+			}
+			if !declared && !shape.open {
+				issues = append(issues, child+" (not in schema)")
+				continue
+			}
+			childIssues, childValid := r.check(value.Content[i+1], property, child, depth+1)
+			issues = append(issues, childIssues...)
+			if childValid {
+				kept = append(kept, value.Content[i], value.Content[i+1])
+			}
+		}
+		value.Content = kept
+	case yaml.SequenceNode:
+		items := getNodeValue(schema, "items")
+		var kept []*yaml.Node
+		for i, item := range value.Content {
+			childIssues, childValid := r.check(item, items, fmt.Sprintf("%s[%d]", at, i), depth+1)
+			issues = append(issues, childIssues...)
+			if childValid {
+				kept = append(kept, item)
+			}
+		}
+		value.Content = kept
+	}
+	return issues, true
+}
+
+// resolve follows local $refs to the schema they point at, or returns nil when the schema cannot
+// be resolved and the example is not checked against it
+func (r *exampleRepairer) resolve(schema *yaml.Node) *yaml.Node {
+	for hops := 0; schema != nil && hops <= maxExampleDepth; hops++ {
+		if schema.Kind != yaml.MappingNode {
+			return nil
+		}
+		ref := getStringValue(schema, "$ref")
+		if ref == "" {
+			return schema
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil // External schemas are not loaded
+		}
+		schema = resolvePointer(r.root, strings.TrimPrefix(ref, "#"))
+	}
+	return nil
+}
+
+// types returns the types a schema allows, from its type or its composition, or nil when any
+// value is allowed
+func (r *exampleRepairer) types(schema *yaml.Node, depth int) []string {
+	if depth > maxExampleDepth {
+		return nil
+	}
+	if typeNode := getNodeValue(schema, "type"); typeNode != nil {
+		if typeNode.Kind == yaml.SequenceNode {
+			var types []string
+			for _, item := range typeNode.Content {
+				types = append(types, item.Value)
+			}
+			return types
+		}
+		return []string{typeNode.Value}
+	}
+	if allOf := getNodeValue(schema, "allOf"); allOf != nil && allOf.Kind == yaml.SequenceNode {
+		for _, member := range allOf.Content {
+			if types := r.types(r.resolve(member), depth+1); len(types) > 0 {
+				return types
+			}
+		}
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		members := getNodeValue(schema, key)
+		if members == nil || members.Kind != yaml.SequenceNode || len(members.Content) == 0 {
+			continue
+		}
+		var union []string
+		for _, member := range members.Content {
+			types := r.types(r.resolve(member), depth+1)
+			if len(types) == 0 {
+				return nil
+			}
+			for _, t := range types {
+				if !contains(union, t) {
+					union = append(union, t)
+				}
+			}
+		}
+		return union
+	}
+	if getNodeValue(schema, "properties") != nil {
+		return []string{"object"}
+	}
+	if getNodeValue(schema, "items") != nil {
+		return []string{"array"}
+	}
+	return nil
+}
+
+// objectShape is the properties an object schema declares, merged over its composition
+type objectShape struct {
+	names      []string // property names in declaration order
+	properties map[string]*yaml.Node
+	required   map[string]bool
+	open       bool       // undeclared properties are allowed
+	additional *yaml.Node // schema of undeclared properties, if given
+}
+
+// shape merges the properties of schema and its allOf members, and of its oneOf/anyOf members
+// when alternatives is set. An object without declared properties is open; one with declared
+// properties is closed unless additionalProperties allows more.
+func (r *exampleRepairer) shape(schema *yaml.Node, alternatives bool, depth int) objectShape {
+	shape := objectShape{properties: make(map[string]*yaml.Node), required: make(map[string]bool)}
+	r.collectShape(schema, alternatives, depth, &shape)
+	if len(shape.properties) == 0 {
+		shape.open = true
+	}
+	return shape
+}
+
+// collectShape adds the properties of schema and its composition to shape
+func (r *exampleRepairer) collectShape(schema *yaml.Node, alternatives bool, depth int, shape *objectShape) {
+	schema = r.resolve(schema)
+	if schema == nil || depth > maxExampleDepth {
+		return
+	}
+	if properties := getNodeValue(schema, "properties"); properties != nil && properties.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(properties.Content); i += 2 {
+			name := properties.Content[i].Value
+			if _, ok := shape.properties[name]; !ok {
+				shape.names = append(shape.names, name)
+			}
+			shape.properties[name] = properties.Content[i+1]
+		}
+	}
+	if required := getNodeValue(schema, "required"); required != nil && required.Kind == yaml.SequenceNode {
+		for _, item := range required.Content {
+			shape.required[item.Value] = true
+		}
+	}
+	if additional := getNodeValue(schema, "additionalProperties"); additional != nil {
+		switch {
+		case additional.Kind == yaml.MappingNode:
+			shape.open, shape.additional = true, additional
+		case additional.Value == "true":
+			shape.open = true
+		}
+	}
+
+	keys := []string{"allOf"}
+	if alternatives {
+		keys = append(keys, "oneOf", "anyOf")
+	}
+	for _, key := range keys {
+		if members := getNodeValue(schema, key); members != nil && members.Kind == yaml.SequenceNode {
+			for _, member := range members.Content {
+				r.collectShape(member, alternatives, depth+1, shape)
+			}
+		}
+	}
+}
+
+// generate builds a minimal example for schema from its example, default, const or enum, or from
+// its type. The example being replaced is never reused.
+func (r *exampleRepairer) generate(schema, replaced *yaml.Node, depth int) *yaml.Node {
+	schema = r.resolve(schema)
+	if schema == nil || depth > maxExampleDepth {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+	for _, key := range []string{"example", "default", "const"} {
+		if value := getNodeValue(schema, key); value != nil && value != replaced {
+			return cloneNode(value)
+		}
+	}
+	if enum := getNodeValue(schema, "enum"); enum != nil && enum.Kind == yaml.SequenceNode && len(enum.Content) > 0 {
+		return cloneNode(enum.Content[0])
+	}
+
+	var kind string
+	for _, t := range r.types(schema, 0) {
+		if t != "null" {
+			kind = t
+			break
+		}
+	}
+	if kind == "" {
+		for _, key := range []string{"oneOf", "anyOf"} {
+			if members := getNodeValue(schema, key); members != nil && members.Kind == yaml.SequenceNode && len(members.Content) > 0 {
+				return r.generate(members.Content[0], replaced, depth+1)
+			}
+		}
+	}
+
+	switch kind {
+	case "object":
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		shape := r.shape(schema, false, 0)
+		for _, name := range shape.names {
+			if len(shape.required) > 0 && !shape.required[name] {
+				continue // Only required properties, when there are any
+			}
+			node.Content = append(node.Content, newScalarNode(name), r.generate(shape.properties[name], replaced, depth+1))
+		}
+		return node
+	case "array":
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if items := getNodeValue(schema, "items"); items != nil {
+			node.Content = append(node.Content, r.generate(items, replaced, depth+1))
+		}
+		return node
+	case "string":
+		return newScalarNode(exampleString(getStringValue(schema, "format")))
+	case "integer", "number":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "0"}
+	case "boolean":
+		return newBoolNode(false)
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+// exampleString returns a placeholder string for a string format
+func exampleString(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	default:
+		return "string"
+	}
+}
+
+// matchesExampleType reports whether an example value has one of the schema types
+func matchesExampleType(value *yaml.Node, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "object":
+			if value.Kind == yaml.MappingNode {
+				return true
+			}
+		case "array":
+			if value.Kind == yaml.SequenceNode {
+				return true
+			}
+		case "string":
+			if value.Kind == yaml.ScalarNode && (value.Tag == "!!str" || value.Tag == "!!timestamp" || value.Tag == "!!binary") {
+				return true
+			}
+		case "integer":
+			if value.Kind == yaml.ScalarNode && value.Tag == "!!int" {
+				return true
+			}
+		case "number":
+			if value.Kind == yaml.ScalarNode && (value.Tag == "!!int" || value.Tag == "!!float") {
+				return true
+			}
+		case "boolean":
+			if value.Kind == yaml.ScalarNode && value.Tag == "!!bool" {
+				return true
+			}
+		case "null":
+			if value.Tag == "!!null" {
+				return true
+			}
+		default:
+			return true // Unknown types are not checked
+		}
+	}
+	return false
+}
+
+// inEnum reports whether a scalar value is one of the enum values. Non-scalar values are not checked.
+func inEnum(enum, value *yaml.Node) bool {
+	if value.Kind != yaml.ScalarNode {
+		return true
+	}
+	for _, item := range enum.Content {
+		if item.Kind == yaml.ScalarNode && item.Value == value.Value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package transform
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const examplesTestSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+          example: ten
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+              example:
+                - id: 1
+                  name: Ada
+                  next_cursor: abc
+                  tags: [admin, 7]
+              examples:
+                valid:
+                  value:
+                    - id: 2
+                      name: Grace
+components:
+  schemas:
+    User:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+          example: Alan
+        tags:
+          type: array
+          items:
+            type: string
+      example:
+        id: 3
+        total: 10
+`
+
+func runExamples(t *testing.T, spec string, examples config.Examples) (*ExamplesResult, string, string) {
+	t.Helper()
+	dir, path := writeTestSpec(t, spec)
+	examples.Enabled = true
+	result, err := ProcessExamplesInDir(dir, ExamplesOptions{Examples: examples})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	return result, path, string(data)
+}
+
+func TestExamplesPrune(t *testing.T) {
+	result, path, content := runExamples(t, examplesTestSpec, config.Examples{})
+
+	if strings.Contains(content, "next_cursor") || strings.Contains(content, "total: 10") {
+		t.Errorf("expected undeclared properties to be pruned, got:\n%s", content)
+	}
+	if !strings.Contains(content, "name: Ada") || !strings.Contains(content, "id: 3") {
+		t.Errorf("expected declared properties to be kept, got:\n%s", content)
+	}
+	if !strings.Contains(content, "tags: [admin]") {
+		t.Errorf("expected the mismatched array item to be pruned, got:\n%s", content)
+	}
+	if !strings.Contains(content, "example: 0") {
+		t.Errorf("expected the mismatched parameter example to be regenerated, got:\n%s", content)
+	}
+	if !strings.Contains(content, "name: Grace") {
+		t.Errorf("expected the valid named example to be left alone, got:\n%s", content)
+	}
+
+	repairs := strings.Join(result.RepairedExamples[path], "\n")
+	for _, want := range []string{
+		"component schemas.User example: pruned total (not in schema)",
+		"GET /users parameter limit example: regenerated (value (expected integer))",
+		"GET /users response 200 application/json example: pruned [0].next_cursor (not in schema), [0].tags[1] (expected string)",
+	} {
+		if !strings.Contains(repairs, want) {
+			t.Errorf("expected repair %q, got:\n%s", want, repairs)
+		}
+	}
+	if len(result.Locations) != 3 {
+		t.Errorf("expected 3 locations, got %d", len(result.Locations))
+	}
+}
+
+func TestExamplesRegenerate(t *testing.T) {
+	_, _, content := runExamples(t, examplesTestSpec, config.Examples{Mode: ExamplesModeRegenerate})
+
+	if strings.Contains(content, "name: Ada") || strings.Contains(content, "total: 10") {
+		t.Errorf("expected the invalid examples to be replaced, got:\n%s", content)
+	}
+	// The User example keeps only its required property, and the response example is built from it
+	if strings.Count(content, "- id: 0") != 1 || strings.Count(content, "id: 0") != 2 {
+		t.Errorf("expected minimal User and response examples, got:\n%s", content)
+	}
+	if !strings.Contains(content, "name: Grace") {
+		t.Errorf("expected the valid named example to be left alone, got:\n%s", content)
+	}
+}
+
+func TestExamplesValidSpecUnchanged(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Test API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Page:
+      allOf:
+        - type: object
+          properties:
+            items:
+              type: array
+        - type: object
+          properties:
+            next:
+              type: [string, "null"]
+              format: uri
+      examples:
+        - items: []
+          next: null
+    Meta:
+      type: object
+      additionalProperties:
+        type: string
+      example:
+        region: eu
+`
+	result, _, _ := runExamples(t, spec, config.Examples{})
+	if result.Changed {
+		t.Errorf("expected no repairs, got %v", result.RepairedExamples)
+	}
+}
+
+func TestValidateExamples(t *testing.T) {
+	if err := ValidateExamples(config.Examples{Mode: ExamplesModeRegenerate}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateExamples(config.Examples{Mode: "rewrite"}); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
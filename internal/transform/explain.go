@@ -0,0 +1,72 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Kinds of rule an explanation covers
+const (
+	ExplainDefaultsRule   = "defaults rule"
+	ExplainVendorProvider = "vendor provider"
+)
+
+// RuleExplanation lists what a single defaults rule or vendor provider would change in the input
+// and which targets it leaves alone, with the reason
+type RuleExplanation struct {
+	Kind    string              // ExplainDefaultsRule or ExplainVendorProvider
+	Name    string              // name of the rule or provider
+	Matches map[string][]string // file -> changes the rule would make
+	Skipped map[string][]Skip   // file -> targets left alone, with the reason
+}
+
+// ExplainRule evaluates the defaults rule or vendor provider called name against cfg.Input as a
+// dry run, on its own and whether or not its section is enabled. It fails when the name is unknown
+// or names both a defaults rule and a vendor provider.
+func ExplainRule(cfg *config.Config, name string) (*RuleExplanation, error) {
+	rule, isRule := cfg.DefaultValues.Rules[name]
+	provider, isProvider := cfg.VendorExtensions.Providers[name]
+	if isRule && isProvider {
+		return nil, fmt.Errorf("%q names both a defaults rule and a vendor provider", name)
+	}
+
+	opts := Options{DryRun: true, Files: cfg.Files}
+	switch {
+	case isRule:
+		result, err := ProcessDefaultsInDir(cfg.Input, DefaultsOptions{
+			Options:       opts,
+			DefaultValues: config.DefaultValues{Enabled: true, Rules: map[string]config.DefaultRule{name: rule}},
+			AsyncAPI:      cfg.AsyncAPI.Enabled,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &RuleExplanation{Kind: ExplainDefaultsRule, Name: name, Matches: result.AppliedDefaults, Skipped: result.SkippedTargets}, nil
+	case isProvider:
+		result, err := ProcessVendorExtensionsInDir(cfg.Input, VendorExtensionOptions{
+			Options:          opts,
+			VendorExtensions: config.VendorExtensions{Enabled: true, Providers: map[string]config.ProviderConfig{name: provider}},
+			ResultsFields:    cfg.ResultsFields,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &RuleExplanation{Kind: ExplainVendorProvider, Name: name, Matches: result.AddedExtensions, Skipped: result.SkippedOperations}, nil
+	}
+
+	var names []string
+	for ruleName := range cfg.DefaultValues.Rules {
+		names = append(names, ruleName)
+	}
+	for providerName := range cfg.VendorExtensions.Providers {
+		names = append(names, providerName)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no defaults rule or vendor provider named %q: the config has none", name)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("no defaults rule or vendor provider named %q (available: %s)", name, strings.Join(names, ", "))
+}
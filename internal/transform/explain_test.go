@@ -0,0 +1,89 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const explainTestSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: sort
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+`
+
+func TestExplainRule(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(explainTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Input: dir,
+		DefaultValues: config.DefaultValues{
+			Enabled: false,
+			Rules: map[string]config.DefaultRule{
+				"limits": {
+					Target:    config.DefaultTarget{Location: "parameter"},
+					Condition: config.DefaultCondition{PropertyName: "^limit$"},
+					Value:     20,
+				},
+				"sorting": {
+					Target:    config.DefaultTarget{Location: "parameter"},
+					Condition: config.DefaultCondition{PropertyName: "^sort$"},
+					Value:     "asc",
+				},
+			},
+		},
+	}
+
+	explanation, err := ExplainRule(cfg, "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if explanation.Kind != ExplainDefaultsRule {
+		t.Errorf("expected a defaults rule, got %q", explanation.Kind)
+	}
+	if got := strings.Join(explanation.Matches[path], "\n"); got != "GET /users: default = 20 (rule: limits)" {
+		t.Errorf("expected only the limit parameter to match, got %q", got)
+	}
+	if got := strings.Join(SkipMessages(explanation.Skipped[path]), "\n"); got != "GET /users parameter sort: parameter name doesn't match pattern '^limit$'" {
+		t.Errorf("expected the sort parameter to be skipped, got %q", got)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != explainTestSpec {
+		t.Error("expected explain to leave the file untouched")
+	}
+
+	if _, err := ExplainRule(cfg, "missing"); err == nil || !strings.Contains(err.Error(), "available: limits, sorting") {
+		t.Errorf("expected an error listing the available rules, got %v", err)
+	}
+
+	cfg.VendorExtensions.Providers = map[string]config.ProviderConfig{"limits": {ExtensionName: "x-limits"}}
+	if _, err := ExplainRule(cfg, "limits"); err == nil {
+		t.Error("expected an error for a name used by both a rule and a provider")
+	}
+}
@@ -0,0 +1,281 @@
+package transform
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/pagination"
+)
+
+// OperationExplanation is everything the pipeline decides for one operation
+type OperationExplanation struct {
+	File       string
+	Line       int
+	Column     int
+	Path       string
+	Method     string                 // lowercase, as in the document
+	Pagination *PaginationExplanation // nil when the pagination step is off
+	Providers  []RuleVerdict          // enabled vendor providers, by name
+	Defaults   []RuleVerdict          // enabled defaults rules, by name
+	Diff       []string               // the operation before and after the pipeline, as "+ ", "- " and "  " lines
+	Moved      bool                   // the operation is no longer at its path and method after the pipeline
+}
+
+// Operation returns the operation as "GET /users"
+func (e OperationExplanation) Operation() string {
+	return strings.ToUpper(e.Method) + " " + e.Path
+}
+
+// Position returns the operation's location as file:line:column
+func (e OperationExplanation) Position() string {
+	return ChangeLocation{File: e.File, Line: e.Line, Column: e.Column}.Position()
+}
+
+// PaginationExplanation is the pagination detected on an operation and the strategy selected for it
+type PaginationExplanation struct {
+	Evidence []string // what was detected of each strategy
+	Strategy string   // selected strategy, empty when the operation is left alone
+	Reason   string   // why the strategy was selected, or none was
+}
+
+// RuleVerdict is what a single defaults rule or vendor provider would do to an operation
+type RuleVerdict struct {
+	Name    string
+	Changes []string // changes it would make
+	Skipped []string // why it leaves the operation alone
+}
+
+// ExplainOperation explains what the pipeline configured by cfg decides for the operation at
+// pointer in file: the pagination detected and the strategy selected, the vendor providers and
+// defaults rules that match it or skip it, and the operation before and after the whole pipeline.
+// The pointer is a JSON pointer such as /paths/~1users/get, or an operation such as "GET /users".
+// Providers and rules are evaluated on the file as it is, as when they are explained on their own.
+func ExplainOperation(cfg *config.Config, vendorProviders []string, file, pointer string) (*OperationExplanation, error) {
+	pathName, method, err := parseOperationPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := loadAndParseDocument(file)
+	if err != nil {
+		return nil, err
+	}
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return nil, fmt.Errorf("%s is not an OpenAPI document", file)
+	}
+	pathItem := getNodeValue(getNodeValue(root, "paths"), pathName)
+	methodNode, operation := operationEntry(pathItem, method)
+	if operation == nil {
+		return nil, fmt.Errorf("%s has no operation %s %s", file, strings.ToUpper(method), pathName)
+	}
+
+	explanation := &OperationExplanation{
+		File:   file,
+		Line:   methodNode.Line,
+		Column: methodNode.Column,
+		Path:   pathName,
+		Method: methodNode.Value,
+	}
+	if len(cfg.PaginationPriority) > 0 {
+		explanation.Pagination = explainPagination(cfg, pathItem, operation, root, pathName, methodNode.Value)
+	}
+	prefixes := []string{explanation.Operation() + ":", explanation.Operation() + " "}
+
+	pipeline := NewTransformationPipeline(cfg, vendorProviders, false, false, "")
+	opts := Options{DryRun: true, Paths: cfg.OnlyPaths}
+	for _, name := range pipeline.appliedVendorProviders() {
+		if provider, ok := cfg.VendorExtensions.Providers[name]; ok {
+			result, err := ProcessVendorExtensionsInDir(file, VendorExtensionOptions{
+				Options: opts,
+				VendorExtensions: config.VendorExtensions{
+					Enabled:   true,
+					Providers: map[string]config.ProviderConfig{name: provider},
+				},
+				ResultsFields: cfg.ResultsFields,
+			})
+			if err != nil {
+				return nil, err
+			}
+			explanation.Providers = append(explanation.Providers, RuleVerdict{
+				Name:    name,
+				Changes: entriesFor(result.AddedExtensions, prefixes),
+				Skipped: entriesFor(SkipMessagesByFile(result.SkippedOperations), prefixes),
+			})
+		}
+	}
+	if cfg.DefaultValues.Enabled {
+		for _, name := range sortedKeysOf(cfg.DefaultValues.Rules) {
+			result, err := ProcessDefaultsInDir(file, DefaultsOptions{
+				Options:       opts,
+				DefaultValues: config.DefaultValues{Enabled: true, Rules: map[string]config.DefaultRule{name: cfg.DefaultValues.Rules[name]}},
+				AsyncAPI:      cfg.AsyncAPI.Enabled,
+			})
+			if err != nil {
+				return nil, err
+			}
+			explanation.Defaults = append(explanation.Defaults, RuleVerdict{
+				Name:    name,
+				Changes: entriesFor(result.AppliedDefaults, prefixes),
+				Skipped: entriesFor(SkipMessagesByFile(result.SkippedTargets), prefixes),
+			})
+		}
+	}
+
+	transformed, _, err := pipeline.TransformBytes(file, content)
+	if err != nil {
+		return nil, err
+	}
+	var after yaml.Node
+	if err := yaml.Unmarshal(transformed, &after); err != nil {
+		return nil, fmt.Errorf("failed to parse the transformed document: %v", err)
+	}
+	_, transformedOperation := operationEntry(getNodeValue(getNodeValue(getRootNode(&after), "paths"), pathName), method)
+	if transformedOperation == nil {
+		explanation.Moved = true
+		return explanation, nil
+	}
+	explanation.Diff = lineDiff(nodeLines(operation), nodeLines(transformedOperation))
+	return explanation, nil
+}
+
+// explainPagination explains the pagination strategy selected for an operation
+func explainPagination(cfg *config.Config, pathItem, operation, root *yaml.Node, pathName, method string) *PaginationExplanation {
+	selection := pagination.ExplainSelection(pathItem, operation, root, pathName, method, pagination.Options{
+		Priority:      cfg.PaginationPriority,
+		EndpointRules: convertEndpointRules(cfg.EndpointPagination),
+	})
+	explanation := &PaginationExplanation{Evidence: describeEvidence(selection.Detected), Strategy: selection.Strategy}
+	switch {
+	case !condition.MatchPath(pathName, cfg.OnlyPaths, condition.Glob):
+		explanation.Strategy = ""
+		explanation.Reason = "the path is outside only_paths"
+	case len(selection.Detected) == 0:
+		explanation.Reason = "no pagination detected"
+	case selection.Tie != nil:
+		explanation.Reason = PaginationTie{Tie: *selection.Tie}.Reason()
+	case selection.Strategy == "":
+		explanation.Reason = "no pagination parameters, or none of the detected strategies is in " + prioritySource(selection)
+	case selection.Rule != nil:
+		explanation.Reason = fmt.Sprintf("endpoint_pagination rule %s %s -> %s", selection.Rule.Method, selection.Rule.Endpoint, selection.Rule.Pagination)
+	case selection.Strategy == "none":
+		explanation.Reason = "none comes before every detected strategy in pagination_priority"
+	default:
+		explanation.Reason = fmt.Sprintf("first detected strategy in pagination_priority [%s]", strings.Join(selection.Priority, ", "))
+	}
+	return explanation
+}
+
+// prioritySource names what the strategies of a selection were tried from
+func prioritySource(selection pagination.Selection) string {
+	if selection.Rule != nil {
+		return fmt.Sprintf("the endpoint_pagination rule %s %s -> %s", selection.Rule.Method, selection.Rule.Endpoint, selection.Rule.Pagination)
+	}
+	return "pagination_priority"
+}
+
+// parseOperationPointer returns the path and lowercase method of a JSON pointer to an operation,
+// such as /paths/~1users/get or #/paths/~1users/get, or of an operation such as "GET /users"
+func parseOperationPointer(pointer string) (string, string, error) {
+	if method, pathName, ok := strings.Cut(strings.TrimSpace(pointer), " "); ok && isHTTPMethod(strings.ToLower(method)) {
+		return strings.TrimSpace(pathName), strings.ToLower(method), nil
+	}
+
+	trimmed := strings.TrimPrefix(pointer, "#")
+	if unescaped, err := url.PathUnescape(trimmed); err == nil {
+		trimmed = unescaped
+	}
+	tokens := strings.Split(strings.TrimPrefix(trimmed, "/"), "/")
+	if !strings.HasPrefix(trimmed, "/") || len(tokens) != 3 || tokens[0] != "paths" || !isHTTPMethod(strings.ToLower(tokens[2])) {
+		return "", "", fmt.Errorf("%q is not a pointer to an operation, such as /paths/~1users/get", pointer)
+	}
+	pathName := strings.ReplaceAll(strings.ReplaceAll(tokens[1], "~1", "/"), "~0", "~")
+	return pathName, strings.ToLower(tokens[2]), nil
+}
+
+// operationEntry returns the method key and operation of a path item, matching the method
+// case-insensitively
+func operationEntry(pathItem *yaml.Node, method string) (*yaml.Node, *yaml.Node) {
+	if pathItem == nil || pathItem.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		if strings.EqualFold(pathItem.Content[i].Value, method) && pathItem.Content[i+1].Kind == yaml.MappingNode {
+			return pathItem.Content[i], pathItem.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// entriesFor returns the entries of every file that start with one of the prefixes
+func entriesFor(entries map[string][]string, prefixes []string) []string {
+	var matching []string
+	for _, file := range sortedKeysOf(entries) {
+		for _, entry := range entries[file] {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(entry, prefix) {
+					matching = append(matching, entry)
+					break
+				}
+			}
+		}
+	}
+	return matching
+}
+
+// nodeLines returns a node as YAML lines
+func nodeLines(node *yaml.Node) []string {
+	return strings.Split(strings.TrimSuffix(encodeNode(node), "\n"), "\n")
+}
+
+// lineDiff compares two lists of lines, returning every line prefixed with "- " when only before
+// has it, "+ " when only after has it and "  " when both do. It is empty when they are equal.
+func lineDiff(before, after []string) []string {
+	// common[i][j] is the length of the longest common subsequence of before[i:] and after[j:]
+	common := make([][]int, len(before)+1)
+	for i := range common {
+		common[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	changed := false
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			diff = append(diff, "  "+before[i])
+			i++
+			j++
+		case j < len(after) && (i == len(before) || common[i][j+1] >= common[i+1][j]):
+			diff = append(diff, "+ "+after[j])
+			changed = true
+			j++
+		default:
+			diff = append(diff, "- "+before[i])
+			changed = true
+			i++
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return diff
+}
@@ -0,0 +1,162 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestExplainOperation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(explainTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Input: path,
+		DefaultValues: config.DefaultValues{
+			Enabled: true,
+			Rules: map[string]config.DefaultRule{
+				"limits": {
+					Target:    config.DefaultTarget{Location: "parameter"},
+					Condition: config.DefaultCondition{PropertyName: "^limit$"},
+					Value:     20,
+				},
+				"orders": {
+					Target:    config.DefaultTarget{Location: "parameter"},
+					Condition: config.DefaultCondition{PropertyName: "^order$"},
+					Value:     "asc",
+				},
+			},
+		},
+	}
+
+	explanation, err := ExplainOperation(cfg, nil, path, "#/paths/~1users/get")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if explanation.Operation() != "GET /users" || explanation.Line != 7 {
+		t.Errorf("expected GET /users on line 7, got %s on line %d", explanation.Operation(), explanation.Line)
+	}
+	if explanation.Pagination != nil {
+		t.Error("expected no pagination explanation without a pagination_priority")
+	}
+	if len(explanation.Defaults) != 2 {
+		t.Fatalf("expected a verdict for both rules, got %+v", explanation.Defaults)
+	}
+	if limits := explanation.Defaults[0]; limits.Name != "limits" || !reflect.DeepEqual(limits.Changes, []string{"GET /users: default = 20 (rule: limits)"}) {
+		t.Errorf("expected the limits rule to set the limit default, got %+v", limits)
+	}
+	if orders := explanation.Defaults[1]; orders.Name != "orders" || len(orders.Changes) != 0 || len(orders.Skipped) != 2 {
+		t.Errorf("expected the orders rule to skip both parameters, got %+v", orders)
+	}
+	if !containsDiffLine(explanation.Diff, "+ ", "default: 20") {
+		t.Errorf("expected the diff to add the limit default, got:\n%s", strings.Join(explanation.Diff, "\n"))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != explainTestSpec {
+		t.Error("expected explain to leave the file untouched")
+	}
+
+	if _, err := ExplainOperation(cfg, nil, path, "DELETE /users"); err == nil {
+		t.Error("expected an error for a missing operation")
+	}
+}
+
+func TestExplainOperationPagination(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(explainTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Input: path, PaginationPriority: []string{"cursor", "offset"}}
+	explanation, err := ExplainOperation(cfg, nil, path, "GET /users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &PaginationExplanation{
+		Evidence: []string{"offset: params limit"},
+		Strategy: "offset",
+		Reason:   "first detected strategy in pagination_priority [cursor, offset]",
+	}
+	if !reflect.DeepEqual(explanation.Pagination, want) {
+		t.Errorf("expected %+v, got %+v", want, explanation.Pagination)
+	}
+
+	cfg.EndpointPagination = []config.EndpointPaginationRule{{Endpoint: "/users", Method: "GET", Pagination: "none"}}
+	explanation, err = ExplainOperation(cfg, nil, path, "GET /users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := explanation.Pagination.Reason; got != "endpoint_pagination rule GET /users -> none" {
+		t.Errorf("expected the endpoint rule to decide, got %q", got)
+	}
+	if !containsDiffLine(explanation.Diff, "- ", "- name: limit") {
+		t.Errorf("expected the diff to remove the limit parameter, got:\n%s", strings.Join(explanation.Diff, "\n"))
+	}
+}
+
+// containsDiffLine reports whether a diff has a line with the prefix and, after its indentation, the text
+func containsDiffLine(diff []string, prefix, text string) bool {
+	for _, line := range diff {
+		if strings.HasPrefix(line, prefix) && strings.TrimSpace(strings.TrimPrefix(line, prefix)) == text {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseOperationPointer(t *testing.T) {
+	tests := []struct {
+		pointer string
+		path    string
+		method  string
+		wantErr bool
+	}{
+		{pointer: "/paths/~1users~1{id}/get", path: "/users/{id}", method: "get"},
+		{pointer: "#/paths/~1users/POST", path: "/users", method: "post"},
+		{pointer: "#/paths/~1users~1%7Bid%7D/get", path: "/users/{id}", method: "get"},
+		{pointer: "GET /users/{id}", path: "/users/{id}", method: "get"},
+		{pointer: "/paths/~1users", wantErr: true},
+		{pointer: "/components/schemas/User", wantErr: true},
+		{pointer: "/paths/~1users/parameters", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			path, method, err := parseOperationPointer(tt.pointer)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %s %s", method, path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != tt.path || method != tt.method {
+				t.Errorf("expected %s %s, got %s %s", tt.method, tt.path, method, path)
+			}
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	got := lineDiff([]string{"a", "b", "c"}, []string{"a", "c", "d"})
+	want := []string{"  a", "- b", "  c", "+ d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got := lineDiff([]string{"a"}, []string{"a"}); got != nil {
+		t.Errorf("expected no diff for equal lines, got %q", got)
+	}
+}
@@ -0,0 +1,429 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+)
+
+// ExtractOptions selects the operations kept by ExtractSubset. Values within one filter are
+// alternatives; every non-empty filter must match for an operation to be kept.
+type ExtractOptions struct {
+	Tags         []string // keep operations tagged with any of these tags
+	Paths        []string // keep operations whose path matches any of these patterns (e.g. /v1/invoices*)
+	OperationIDs []string // keep operations with any of these operationIds
+}
+
+// ExtractResult represents the result of extracting a subset of a spec
+type ExtractResult struct {
+	KeptOperations    []string // "METHOD /path" of every kept operation
+	RemovedOperations int
+	KeptComponents    []string // "section.Name" of every kept component
+	RemovedComponents []string // "section.Name" of every removed component
+	RemovedTags       []string
+	SecondaryRemovals []string // links left pointing at removed operations
+}
+
+// ExtractSubset reads the spec at inputPath, keeps only the selected operations and the transitive
+// closure of components they reference, and writes the subset to outputPath
+func ExtractSubset(inputPath, outputPath string, opts ExtractOptions) (*ExtractResult, error) {
+	output, result, err := ExtractSubsetBytes(inputPath, opts, IsJSON(outputPath))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, output, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+	return result, nil
+}
+
+// ExtractSubsetBytes is like ExtractSubset but returns the formatted subset instead of writing it
+func ExtractSubsetBytes(inputPath string, opts ExtractOptions, asJSON bool) ([]byte, *ExtractResult, error) {
+	if len(opts.Tags) == 0 && len(opts.Paths) == 0 && len(opts.OperationIDs) == 0 {
+		return nil, nil, errors.New("at least one of tags, paths or operation IDs must be specified")
+	}
+
+	doc, err := loadAndParseDocument(inputPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return nil, nil, fmt.Errorf("%s is not an OpenAPI document", inputPath)
+	}
+
+	result := &ExtractResult{}
+	extractDocumentSubset(root, opts, result)
+
+	var output []byte
+	if asJSON {
+		output, err = formatAsJSON(doc)
+	} else {
+		output, err = formatAsYAML(doc)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return output, result, nil
+}
+
+// extractDocumentSubset filters operations, removes links left pointing at removed
+// operations, then prunes components, security schemes and tags that the kept operations no longer need
+func extractDocumentSubset(root *yaml.Node, opts ExtractOptions, result *ExtractResult) {
+	operationsBefore := collectOperationTargets(root)
+	for _, section := range []string{"paths", "webhooks"} {
+		if items := getNodeValue(root, section); items != nil && items.Kind == yaml.MappingNode {
+			filterPathItems(items, opts, result)
+		}
+	}
+
+	result.SecondaryRemovals = removeDanglingCrossReferences(root, operationsBefore, nil)
+
+	usedTags, usedSchemes := collectOperationUsage(root)
+	pruneUnreferencedComponents(root, usedSchemes, result)
+	pruneUnusedTags(root, usedTags, result)
+}
+
+// filterPathItems removes unselected operations and path items left without operations
+func filterPathItems(items *yaml.Node, opts ExtractOptions, result *ExtractResult) {
+	var kept []*yaml.Node
+	for i := 0; i < len(items.Content); i += 2 {
+		path := items.Content[i].Value
+		pathItem := items.Content[i+1]
+		if pathItem.Kind != yaml.MappingNode {
+			continue
+		}
+
+		var content []*yaml.Node
+		operations := 0
+		for j := 0; j < len(pathItem.Content); j += 2 {
+			key := pathItem.Content[j].Value
+			if isHTTPMethod(key) {
+				if !operationSelected(path, pathItem.Content[j+1], opts) {
+					result.RemovedOperations++
+					continue
+				}
+				operations++
+				result.KeptOperations = append(result.KeptOperations, strings.ToUpper(key)+" "+path)
+			}
+			content = append(content, pathItem.Content[j], pathItem.Content[j+1])
+		}
+
+		if operations > 0 {
+			pathItem.Content = content
+			kept = append(kept, items.Content[i], pathItem)
+		}
+	}
+	items.Content = kept
+}
+
+// operationSelected reports whether an operation matches every configured filter
+func operationSelected(path string, operation *yaml.Node, opts ExtractOptions) bool {
+	if !condition.MatchPath(path, opts.Paths, condition.Glob) {
+		return false
+	}
+
+	if len(opts.OperationIDs) > 0 && !contains(opts.OperationIDs, getStringValue(operation, "operationId")) {
+		return false
+	}
+
+	if len(opts.Tags) > 0 {
+		tags := getNodeValue(operation, "tags")
+		if tags == nil || tags.Kind != yaml.SequenceNode {
+			return false
+		}
+		for _, tag := range tags.Content {
+			if contains(opts.Tags, tag.Value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// collectOperationUsage returns the tags and security scheme names used by the remaining operations
+// and the top-level security requirements
+func collectOperationUsage(root *yaml.Node) (map[string]bool, map[string]bool) {
+	usedTags := make(map[string]bool)
+	usedSchemes := make(map[string]bool)
+
+	collectSecuritySchemes(getNodeValue(root, "security"), usedSchemes)
+	for _, section := range []string{"paths", "webhooks"} {
+		items := getNodeValue(root, section)
+		if items == nil || items.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 1; i < len(items.Content); i += 2 {
+			pathItem := items.Content[i]
+			for j := 0; j+1 < len(pathItem.Content); j += 2 {
+				if !isHTTPMethod(pathItem.Content[j].Value) {
+					continue
+				}
+				operation := pathItem.Content[j+1]
+				if tags := getNodeValue(operation, "tags"); tags != nil && tags.Kind == yaml.SequenceNode {
+					for _, tag := range tags.Content {
+						usedTags[tag.Value] = true
+					}
+				}
+				collectSecuritySchemes(getNodeValue(operation, "security"), usedSchemes)
+			}
+		}
+	}
+
+	return usedTags, usedSchemes
+}
+
+// collectSecuritySchemes records the scheme names used by a list of security requirements
+func collectSecuritySchemes(security *yaml.Node, usedSchemes map[string]bool) {
+	if security == nil || security.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, requirement := range security.Content {
+		if requirement.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i < len(requirement.Content); i += 2 {
+			usedSchemes[requirement.Content[i].Value] = true
+		}
+	}
+}
+
+// pruneUnreferencedComponents removes every component outside the transitive closure of refs made
+// from outside the component sections. Security schemes are kept when named by a security
+// requirement. Swagger 2.0 documents are pruned in their top-level sections.
+func pruneUnreferencedComponents(root *yaml.Node, usedSchemes map[string]bool, result *ExtractResult) {
+	sections := documentComponentSections(root)
+	if len(sections) == 0 {
+		return
+	}
+
+	reachable := componentClosure(root)
+	_, schemesName, _ := componentSection(root, "securitySchemes")
+	for name := range usedSchemes {
+		reachable[componentKey(schemesName, name)] = true
+	}
+
+	removed, kept := filterComponents(root, func(key string) bool { return reachable[key] })
+	result.KeptComponents = append(result.KeptComponents, kept...)
+	result.RemovedComponents = append(result.RemovedComponents, removed...)
+}
+
+// filterComponents removes the components for which keep returns false, drops the sections left
+// empty and the components mapping when nothing is left, and returns the componentKeys of the
+// removed and kept components in document order. Swagger 2.0 sections are filtered at the top level.
+func filterComponents(root *yaml.Node, keep func(key string) bool) ([]string, []string) {
+	var removed, kept []string
+	components := getNodeValue(root, "components")
+	for _, section := range documentComponentSections(root) {
+		sectionNode, name, _ := componentSection(root, section)
+		if sectionNode == nil || sectionNode.Kind != yaml.MappingNode {
+			continue
+		}
+
+		var content []*yaml.Node
+		for j := 0; j < len(sectionNode.Content); j += 2 {
+			key := componentKey(name, sectionNode.Content[j].Value)
+			if keep(key) {
+				content = append(content, sectionNode.Content[j], sectionNode.Content[j+1])
+				kept = append(kept, key)
+			} else {
+				removed = append(removed, key)
+			}
+		}
+		sectionNode.Content = content
+
+		if len(content) == 0 {
+			if components != nil {
+				removeMappingKey(components, section)
+			} else {
+				removeMappingKey(root, name)
+			}
+		}
+	}
+
+	if components != nil && components.Kind == yaml.MappingNode && len(components.Content) == 0 {
+		removeMappingKey(root, "components")
+	}
+	return removed, kept
+}
+
+// componentKey identifies a component as "section.Name"
+func componentKey(section, name string) string {
+	return section + "." + name
+}
+
+// componentClosure returns the components reachable from refs outside the component sections,
+// keyed by componentKey with the sections named as in the document (definitions in Swagger 2.0)
+func componentClosure(root *yaml.Node) map[string]bool {
+	swagger := getNodeValue(root, "swagger") != nil
+	sections := make(map[string]*yaml.Node)
+	for _, section := range documentComponentSections(root) {
+		sectionNode, name, _ := componentSection(root, section)
+		sections[name] = sectionNode
+	}
+
+	reachable := make(map[string]bool)
+	var queue []string
+
+	enqueue := func(refs map[string]bool) {
+		for ref := range refs {
+			section, name, ok := parseDocumentComponentRef(root, ref)
+			if !ok {
+				continue
+			}
+			key := componentKey(section, name)
+			if !reachable[key] {
+				reachable[key] = true
+				queue = append(queue, key)
+			}
+		}
+	}
+
+	for i := 0; i < len(root.Content); i += 2 {
+		key := root.Content[i].Value
+		if key != "components" && !(swagger && sections[key] != nil) {
+			enqueue(collectComponentRefs(root.Content[i+1]))
+		}
+	}
+
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+
+		section, name, _ := strings.Cut(key, ".")
+		if node := getNodeValue(sections[section], name); node != nil {
+			enqueue(collectComponentRefs(node))
+		}
+	}
+
+	return reachable
+}
+
+// collectComponentRefs collects local component refs in a node, including bare schema names
+// used in discriminator mappings
+func collectComponentRefs(node *yaml.Node) map[string]bool {
+	refs := extractComponentRefs(node)
+	collectDiscriminatorRefs(node, refs)
+	return refs
+}
+
+// collectDiscriminatorRefs adds the schemas named by discriminator mappings to refs
+func collectDiscriminatorRefs(node *yaml.Node, refs map[string]bool) {
+	if node == nil {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			collectDiscriminatorRefs(item, refs)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			if node.Content[i].Value == "discriminator" {
+				if mapping := getNodeValue(value, "mapping"); mapping != nil && mapping.Kind == yaml.MappingNode {
+					for j := 1; j < len(mapping.Content); j += 2 {
+						target := mapping.Content[j].Value
+						if !strings.Contains(target, "/") {
+							target = componentRef("schemas", target)
+						}
+						refs[target] = true
+					}
+				}
+			}
+			collectDiscriminatorRefs(value, refs)
+		}
+	}
+}
+
+// parseComponentRef splits a local component ref (or a ref into a component) into section and name
+func parseComponentRef(ref string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(ref, componentsRefPrefix)
+	if !ok {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// parseDocumentComponentRef splits a local ref to (or into) a component of the document into the
+// section, named as in the document, and the component name
+func parseDocumentComponentRef(root *yaml.Node, ref string) (string, string, bool) {
+	if getNodeValue(root, "swagger") != nil {
+		return parseSwagger2Ref(ref)
+	}
+	return parseComponentRef(ref)
+}
+
+// parseSwagger2Ref splits a local ref to (or into) a Swagger 2.0 definition, parameter, response
+// or security definition into its top-level section and name
+func parseSwagger2Ref(ref string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 || parts[1] == "" {
+		return "", "", false
+	}
+	for _, section := range swagger2Sections {
+		if parts[0] == section {
+			return parts[0], parts[1], true
+		}
+	}
+	return "", "", false
+}
+
+// pruneUnusedTags removes top-level tag definitions that no remaining operation uses
+func pruneUnusedTags(root *yaml.Node, usedTags map[string]bool, result *ExtractResult) {
+	tags := getNodeValue(root, "tags")
+	if tags == nil || tags.Kind != yaml.SequenceNode {
+		return
+	}
+
+	var kept []*yaml.Node
+	for _, tag := range tags.Content {
+		name := getStringValue(tag, "name")
+		if usedTags[name] {
+			kept = append(kept, tag)
+		} else {
+			result.RemovedTags = append(result.RemovedTags, name)
+		}
+	}
+	sort.Strings(result.RemovedTags)
+	tags.Content = kept
+
+	if len(kept) == 0 {
+		removeMappingKey(root, "tags")
+	}
+}
+
+// removeMappingKey removes a key and its value from a mapping node
+func removeMappingKey(node *yaml.Node, key string) {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
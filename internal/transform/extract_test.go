@@ -0,0 +1,278 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const extractTestSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+tags:
+  - name: billing
+  - name: users
+security:
+  - apiKey: []
+paths:
+  /v1/invoices:
+    parameters:
+      - $ref: '#/components/parameters/Tenant'
+    get:
+      tags: [billing]
+      operationId: listInvoices
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/InvoiceList'
+    post:
+      tags: [billing]
+      operationId: createInvoice
+      responses:
+        "201":
+          description: Created
+  /v1/users:
+    get:
+      tags: [users]
+      operationId: listUsers
+      security:
+        - oauth: []
+      responses:
+        "200":
+          $ref: '#/components/responses/UserList'
+components:
+  parameters:
+    Tenant:
+      name: tenant
+      in: header
+      schema:
+        type: string
+  responses:
+    UserList:
+      description: Users
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/User'
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    oauth:
+      type: http
+      scheme: bearer
+  schemas:
+    InvoiceList:
+      type: array
+      items:
+        $ref: '#/components/schemas/Invoice'
+    Invoice:
+      oneOf:
+        - $ref: '#/components/schemas/CardPayment'
+      discriminator:
+        propertyName: kind
+        mapping:
+          bank: BankPayment
+    CardPayment:
+      type: object
+    BankPayment:
+      type: object
+    User:
+      type: object
+`
+
+func TestExtractSubset(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            ExtractOptions
+		expectErr       bool
+		expectOps       int
+		expectRemovedOp int
+		contains        []string
+		notContains     []string
+	}{
+		{
+			name:      "no filters",
+			opts:      ExtractOptions{},
+			expectErr: true,
+		},
+		{
+			name:            "by tag keeps transitive closure",
+			opts:            ExtractOptions{Tags: []string{"billing"}},
+			expectOps:       2,
+			expectRemovedOp: 1,
+			contains:        []string{"InvoiceList:", "Invoice:", "CardPayment:", "BankPayment:", "Tenant:", "apiKey:", "- name: billing"},
+			notContains:     []string{"/v1/users", "User", "oauth", "- name: users"},
+		},
+		{
+			name:            "filters combine",
+			opts:            ExtractOptions{Paths: []string{"/v1/*"}, OperationIDs: []string{"createInvoice", "listUsers"}},
+			expectOps:       2,
+			expectRemovedOp: 1,
+			contains:        []string{"createInvoice", "UserList:", "User:", "oauth:"},
+			notContains:     []string{"listInvoices", "InvoiceList", "CardPayment"},
+		},
+		{
+			name:            "path pattern",
+			opts:            ExtractOptions{Paths: []string{"/v1/users*"}},
+			expectOps:       1,
+			expectRemovedOp: 2,
+			contains:        []string{"listUsers"},
+			notContains:     []string{"/v1/invoices", "parameters:", "- name: billing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			input := filepath.Join(dir, "master.yaml")
+			output := filepath.Join(dir, "out", "subset.yaml")
+			if err := os.WriteFile(input, []byte(extractTestSpec), 0600); err != nil {
+				t.Fatalf("failed to write test file: %v", err)
+			}
+
+			result, err := ExtractSubset(input, output, tt.opts)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result.KeptOperations) != tt.expectOps {
+				t.Errorf("expected %d kept operations, got %v", tt.expectOps, result.KeptOperations)
+			}
+			if result.RemovedOperations != tt.expectRemovedOp {
+				t.Errorf("expected %d removed operations, got %d", tt.expectRemovedOp, result.RemovedOperations)
+			}
+
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			out := string(data)
+			for _, want := range tt.contains {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected output to contain %q\n%s", want, out)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(out, unwanted) {
+					t.Errorf("expected output not to contain %q\n%s", unwanted, out)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractSubsetSwagger2(t *testing.T) {
+	_, input := writeTestSpec(t, `swagger: "2.0"
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /invoices:
+    get:
+      tags: [billing]
+      security:
+        - apiKey: []
+      parameters:
+        - $ref: '#/parameters/Limit'
+      responses:
+        "200":
+          $ref: '#/responses/InvoiceList'
+  /users:
+    get:
+      tags: [users]
+      security:
+        - oauth: []
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: '#/definitions/User'
+parameters:
+  Limit:
+    name: limit
+    in: query
+    type: integer
+responses:
+  InvoiceList:
+    description: OK
+    schema:
+      type: array
+      items:
+        $ref: '#/definitions/Invoice'
+definitions:
+  Invoice:
+    type: object
+  User:
+    type: object
+  Orphan:
+    type: object
+securityDefinitions:
+  apiKey:
+    type: apiKey
+    in: header
+    name: X-Key
+  oauth:
+    type: oauth2
+    flow: implicit
+    authorizationUrl: https://example.com/auth
+`)
+
+	output, result, err := ExtractSubsetBytes(input, ExtractOptions{Tags: []string{"billing"}}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sort.Strings(result.KeptComponents)
+	sort.Strings(result.RemovedComponents)
+	wantKept := []string{"definitions.Invoice", "parameters.Limit", "responses.InvoiceList", "securityDefinitions.apiKey"}
+	wantRemoved := []string{"definitions.Orphan", "definitions.User", "securityDefinitions.oauth"}
+	if !reflect.DeepEqual(result.KeptComponents, wantKept) {
+		t.Errorf("expected kept components %v, got %v", wantKept, result.KeptComponents)
+	}
+	if !reflect.DeepEqual(result.RemovedComponents, wantRemoved) {
+		t.Errorf("expected removed components %v, got %v", wantRemoved, result.RemovedComponents)
+	}
+	for _, unwanted := range []string{"User", "Orphan", "oauth"} {
+		if strings.Contains(string(output), unwanted) {
+			t.Errorf("expected output not to contain %q\n%s", unwanted, output)
+		}
+	}
+}
+
+func TestParseComponentRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		section string
+		name    string
+		ok      bool
+	}{
+		{"#/components/schemas/User", "schemas", "User", true},
+		{"#/components/schemas/User/properties/id", "schemas", "User", true},
+		{"#/components/schemas/", "", "", false},
+		{"other.yaml#/components/schemas/User", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			section, name, ok := parseComponentRef(tt.ref)
+			if section != tt.section || name != tt.name || ok != tt.ok {
+				t.Errorf("parseComponentRef(%q) = %q, %q, %v", tt.ref, section, name, ok)
+			}
+		})
+	}
+}
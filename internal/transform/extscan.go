@@ -0,0 +1,227 @@
+package transform
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// ExtensionFamilies maps the generator families whose extensions the scanner recognizes to the
+// prefix their extension keys share
+var ExtensionFamilies = map[string]string{
+	"autorest":  "x-ms-",
+	"codegen":   "x-codegen-",
+	"fern":      "x-fern-",
+	"speakeasy": "x-speakeasy-",
+	"stainless": "x-stainless-",
+}
+
+// extensionEquivalents lists extensions of different families that express the same thing, as
+// family -> key rows
+var extensionEquivalents = []map[string]string{
+	{"fern": "x-fern-pagination", "speakeasy": "x-speakeasy-pagination", "autorest": "x-ms-pageable"},
+	{"fern": "x-fern-sdk-group-name", "speakeasy": "x-speakeasy-group"},
+	{"fern": "x-fern-sdk-method-name", "speakeasy": "x-speakeasy-name-override"},
+	{"fern": "x-fern-ignore", "speakeasy": "x-speakeasy-ignore"},
+	{"fern": "x-fern-retries", "speakeasy": "x-speakeasy-retries"},
+	{"fern": "x-fern-enum", "speakeasy": "x-speakeasy-enums", "codegen": "x-enum-varnames", "autorest": "x-ms-enum"},
+}
+
+// ExtensionOccurrence is one place an extension key is used
+type ExtensionOccurrence struct {
+	File   string
+	Line   int
+	Column int
+	Value  string // the value on one line, shortened
+}
+
+// Position returns the occurrence as file:line:column
+func (o ExtensionOccurrence) Position() string {
+	return ChangeLocation{File: o.File, Line: o.Line, Column: o.Column}.Position()
+}
+
+// ExtensionUsage lists the occurrences of one extension key and what it could be renamed to
+type ExtensionUsage struct {
+	Name        string
+	Occurrences []ExtensionOccurrence
+	MappedTo    string // target of the configured mapping for this key, if any
+	Suggestion  string // suggested mapping target, if any
+	Guessed     bool   // the suggestion only swaps the family prefix and should be verified
+}
+
+// ScanExtensions lists every extension key used in the OpenAPI documents under inputPath with its
+// occurrences, ordered by name. Keys with a configured mapping carry its target.
+func ScanExtensions(inputPath string, files config.FileFilter, mappings map[string]string) ([]ExtensionUsage, error) {
+	byName := make(map[string]*ExtensionUsage)
+	err := filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(IsYAML(path) || IsJSON(path)) || !IncludesFile(files, "", inputPath, path) {
+			return nil
+		}
+		if reason, err := sniffFile(path); err != nil || reason != skipReasonNone {
+			return nil
+		}
+
+		doc, err := loadAndParseDocument(path)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", path, err)
+		}
+		root := getRootNode(doc)
+		if !isOpenAPIDocument(root) {
+			return nil
+		}
+		collectExtensionOccurrences(path, root, byName)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]ExtensionUsage, 0, len(byName))
+	for name, usage := range byName {
+		usage.MappedTo = mappings[name]
+		usages = append(usages, *usage)
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Name < usages[j].Name })
+	return usages, nil
+}
+
+// collectExtensionOccurrences records every extension key in the tree
+func collectExtensionOccurrences(path string, node *yaml.Node, byName map[string]*ExtensionUsage) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			collectExtensionOccurrences(path, child, byName)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if strings.HasPrefix(key.Value, "x-") {
+				usage, ok := byName[key.Value]
+				if !ok {
+					usage = &ExtensionUsage{Name: key.Value}
+					byName[key.Value] = usage
+				}
+				usage.Occurrences = append(usage.Occurrences, ExtensionOccurrence{
+					File:   path,
+					Line:   key.Line,
+					Column: key.Column,
+					Value:  summarizeValue(value),
+				})
+			}
+			collectExtensionOccurrences(path, value, byName)
+		}
+	}
+}
+
+// summarizeValue renders a value on one line, shortened to a readable length
+func summarizeValue(node *yaml.Node) string {
+	var value string
+	if node.Kind == yaml.ScalarNode {
+		value = node.Value
+	} else {
+		flow := *node
+		setFlowStyle(&flow)
+		out, err := yaml.Marshal(&flow)
+		if err != nil {
+			return ""
+		}
+		value = strings.TrimSpace(string(out))
+	}
+	value = strings.Join(strings.Fields(value), " ")
+	if len(value) > 60 {
+		value = value[:57] + "..."
+	}
+	return value
+}
+
+// setFlowStyle renders node and its children in flow style, without changing the originals
+func setFlowStyle(node *yaml.Node) {
+	node.Style = yaml.FlowStyle
+	children := make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied := *child
+		setFlowStyle(&copied)
+		children[i] = &copied
+	}
+	node.Content = children
+}
+
+// SuggestMappings fills in the suggested target family key of every unmapped usage. Known
+// equivalents are suggested as they are; other keys of a known family get the target prefix
+// instead of their own and are marked as guessed.
+func SuggestMappings(usages []ExtensionUsage, target string) error {
+	targetPrefix, ok := ExtensionFamilies[target]
+	if !ok {
+		return fmt.Errorf("unknown target %q (expected one of %s)", target, strings.Join(extensionFamilyNames(), ", "))
+	}
+
+	for i := range usages {
+		usage := &usages[i]
+		if usage.MappedTo != "" || strings.HasPrefix(usage.Name, targetPrefix) {
+			continue
+		}
+		if equivalent := equivalentExtension(usage.Name, target); equivalent != "" {
+			usage.Suggestion = equivalent
+			continue
+		}
+		for family, prefix := range ExtensionFamilies {
+			if family != target && strings.HasPrefix(usage.Name, prefix) {
+				usage.Suggestion = targetPrefix + strings.TrimPrefix(usage.Name, prefix)
+				usage.Guessed = true
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// equivalentExtension returns the extension of the target family that expresses the same as name
+func equivalentExtension(name, target string) string {
+	for _, row := range extensionEquivalents {
+		for family, key := range row {
+			if key == name && family != target {
+				return row[target]
+			}
+		}
+	}
+	return ""
+}
+
+// extensionFamilyNames returns the names of the known families in order
+func extensionFamilyNames() []string {
+	names := make([]string, 0, len(ExtensionFamilies))
+	for name := range ExtensionFamilies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StarterMappings renders the suggestions as a mappings: block for the config file. Guessed
+// suggestions are commented out so they are only applied once verified.
+func StarterMappings(usages []ExtensionUsage) string {
+	var b strings.Builder
+	for _, usage := range usages {
+		if usage.Suggestion == "" {
+			continue
+		}
+		if usage.Guessed {
+			fmt.Fprintf(&b, "  # %s: %s   # guessed from the prefix, verify before enabling\n", usage.Name, usage.Suggestion)
+		} else {
+			fmt.Fprintf(&b, "  %s: %s\n", usage.Name, usage.Suggestion)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "mappings:\n" + b.String()
+}
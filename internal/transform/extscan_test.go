@@ -0,0 +1,74 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestScanExtensionsAndSuggestMappings(t *testing.T) {
+	dir := t.TempDir()
+	spec := `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-fern-pagination:
+        cursor: $request.cursor
+      x-fern-availability: beta
+      x-codegen-request-body-name: body
+      x-rate-limit: 100
+      responses:
+        "200":
+          description: OK
+    post:
+      x-codegen-request-body-name: user
+      x-speakeasy-group: users
+      responses:
+        "201":
+          description: Created
+`
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	usages, err := ScanExtensions(dir, config.FileFilter{}, map[string]string{"x-rate-limit": "x-speakeasy-rate-limit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SuggestMappings(usages, "speakeasy"); err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]ExtensionUsage)
+	for _, usage := range usages {
+		byName[usage.Name] = usage
+	}
+	if got := len(byName["x-codegen-request-body-name"].Occurrences); got != 2 {
+		t.Errorf("expected 2 occurrences, got %d", got)
+	}
+	if got := byName["x-fern-pagination"].Occurrences[0]; got.Position() != path+":8:7" || got.Value != "{cursor: $request.cursor}" {
+		t.Errorf("unexpected occurrence %+v", got)
+	}
+	if byName["x-rate-limit"].MappedTo != "x-speakeasy-rate-limit" || byName["x-rate-limit"].Suggestion != "" {
+		t.Errorf("expected the configured mapping to be reported, got %+v", byName["x-rate-limit"])
+	}
+
+	want := `mappings:
+  # x-codegen-request-body-name: x-speakeasy-request-body-name   # guessed from the prefix, verify before enabling
+  # x-fern-availability: x-speakeasy-availability   # guessed from the prefix, verify before enabling
+  x-fern-pagination: x-speakeasy-pagination
+`
+	if got := StarterMappings(usages); got != want {
+		t.Errorf("expected starter mappings:\n%s\ngot:\n%s", want, got)
+	}
+
+	if err := SuggestMappings(usages, "openapi-generator"); err == nil {
+		t.Error("expected an unknown target to be rejected")
+	}
+}
@@ -0,0 +1,492 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// LoadExtensionSchemas returns the schema of every extension in extension_schemas, reading schema
+// files relative to the config file. Numbers in the schemas are float64, as in decoded JSON.
+func LoadExtensionSchemas(cfg *config.Config) (map[string]map[string]interface{}, error) {
+	schemas := make(map[string]map[string]interface{}, len(cfg.ExtensionSchemas))
+	for _, name := range sortedExtensionSchemaNames(cfg.ExtensionSchemas) {
+		entry := cfg.ExtensionSchemas[name]
+		if !strings.HasPrefix(name, "x-") {
+			return nil, fmt.Errorf("extension_schemas.%s: extension names must start with x-", name)
+		}
+		if (entry.Schema == nil) == (entry.File == "") {
+			return nil, fmt.Errorf("extension_schemas.%s: exactly one of schema or file is required", name)
+		}
+
+		var raw interface{} = entry.Schema
+		if entry.File != "" {
+			path := entry.File
+			if !filepath.IsAbs(path) && cfg.Source != "" {
+				path = filepath.Join(filepath.Dir(cfg.Source), path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("extension_schemas.%s: %w", name, err)
+			}
+			if err := yaml.Unmarshal(data, &raw); err != nil {
+				return nil, fmt.Errorf("extension_schemas.%s: error parsing %s: %w", name, path, err)
+			}
+		}
+
+		schema, ok := normalizeJSONValue(raw).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("extension_schemas.%s: schema must be an object", name)
+		}
+		if err := compileSchemaPatterns(schema); err != nil {
+			return nil, fmt.Errorf("extension_schemas.%s: %w", name, err)
+		}
+		schemas[name] = schema
+	}
+	return schemas, nil
+}
+
+// ValidateExtensionSchemas checks that every extension schema can be loaded
+func ValidateExtensionSchemas(cfg *config.Config) error {
+	_, err := LoadExtensionSchemas(cfg)
+	return err
+}
+
+// CheckExtensions validates every occurrence of a registered extension in the OpenAPI and AsyncAPI
+// documents under inputPath against its schema. Issues point at the offending value.
+func CheckExtensions(inputPath string, files config.FileFilter, schemas map[string]map[string]interface{}) ([]StrictIssue, error) {
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+
+	var issues []StrictIssue
+	err := filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(IsYAML(path) || IsJSON(path)) || !IncludesFile(files, "", inputPath, path) {
+			return nil
+		}
+		if reason, err := sniffFile(path); err != nil || reason != skipReasonNone {
+			return nil
+		}
+
+		doc, err := loadAndParseDocument(path)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", path, err)
+		}
+		issues = append(issues, extensionIssues(path, getRootNode(doc), schemas)...)
+		return nil
+	})
+	return issues, err
+}
+
+// extensionIssues validates every occurrence of a registered extension in one document
+func extensionIssues(path string, root *yaml.Node, schemas map[string]map[string]interface{}) []StrictIssue {
+	var issues []StrictIssue
+	walkExtensions(root, schemas, func(name string, value *yaml.Node) {
+		v := &schemaValidator{root: schemas[name]}
+		v.validate(schemas[name], value, name)
+		for _, violation := range v.violations {
+			issues = append(issues, StrictIssue{
+				File:    path,
+				Line:    violation.node.Line,
+				Column:  violation.node.Column,
+				Message: violation.message,
+			})
+		}
+	})
+	return issues
+}
+
+// walkExtensions calls visit with the value of every registered extension in the tree
+func walkExtensions(node *yaml.Node, schemas map[string]map[string]interface{}, visit func(name string, value *yaml.Node)) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if _, ok := schemas[node.Content[i].Value]; ok {
+				visit(node.Content[i].Value, node.Content[i+1])
+			}
+			walkExtensions(node.Content[i+1], schemas, visit)
+		}
+		return
+	}
+	for _, item := range node.Content {
+		walkExtensions(item, schemas, visit)
+	}
+}
+
+// schemaViolation is a value that does not match its schema
+type schemaViolation struct {
+	node    *yaml.Node
+	message string
+}
+
+// schemaValidator validates YAML values against a JSON Schema. It supports the commonly used
+// keywords: type, enum, const, properties, required, additionalProperties, min/maxProperties,
+// items, min/maxItems, uniqueItems, min/maxLength, pattern, minimum, maximum, exclusiveMinimum,
+// exclusiveMaximum, multipleOf, allOf, anyOf, oneOf, not and local $refs. Other keywords are ignored.
+type schemaValidator struct {
+	root       map[string]interface{} // document local $refs resolve against
+	violations []schemaViolation
+}
+
+// validate records every way node, found at the given path, fails to match schema
+func (v *schemaValidator) validate(schema interface{}, node *yaml.Node, at string) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			v.fail(node, "%s: no value is allowed", at)
+		}
+		return
+	case map[string]interface{}:
+		schema = s
+	default:
+		return
+	}
+	s := schema.(map[string]interface{})
+
+	if ref, ok := s["$ref"].(string); ok {
+		target, found := resolveSchemaRef(v.root, ref)
+		if !found {
+			v.fail(node, "%s: unresolved $ref %s in extension schema", at, ref)
+			return
+		}
+		v.validate(target, node, at)
+	}
+
+	if types, ok := s["type"]; ok && !matchesJSONType(node, types) {
+		v.fail(node, "%s: expected %s, got %s", at, describeJSONTypes(types), jsonType(node))
+		return
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && !containsJSONValue(enum, nodeJSONValue(node)) {
+		v.fail(node, "%s: %s is not one of %s", at, formatJSONValue(nodeJSONValue(node)), formatJSONValue(enum))
+	}
+	if constant, ok := s["const"]; ok && !reflect.DeepEqual(constant, nodeJSONValue(node)) {
+		v.fail(node, "%s: must be %s", at, formatJSONValue(constant))
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		v.validateObject(s, node, at)
+	case yaml.SequenceNode:
+		v.validateArray(s, node, at)
+	case yaml.ScalarNode:
+		v.validateScalar(s, node, at)
+	}
+
+	v.validateCompositions(s, node, at)
+}
+
+func (v *schemaValidator) validateObject(s map[string]interface{}, node *yaml.Node, at string) {
+	properties, _ := s["properties"].(map[string]interface{})
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok && getNodeValue(node, key) == nil {
+				v.fail(node, "%s: missing required property %q", at, key)
+			}
+		}
+	}
+	count := len(node.Content) / 2
+	if limit, ok := numberKeyword(s, "minProperties"); ok && float64(count) < limit {
+		v.fail(node, "%s: expected at least %v properties, got %d", at, limit, count)
+	}
+	if limit, ok := numberKeyword(s, "maxProperties"); ok && float64(count) > limit {
+		v.fail(node, "%s: expected at most %v properties, got %d", at, limit, count)
+	}
+
+	additional, hasAdditional := s["additionalProperties"]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if propertySchema, ok := properties[key]; ok {
+			v.validate(propertySchema, value, at+"."+key)
+			continue
+		}
+		if !hasAdditional {
+			continue
+		}
+		if allowed, ok := additional.(bool); ok && !allowed {
+			v.fail(node.Content[i], "%s: unexpected property %q", at, key)
+			continue
+		}
+		v.validate(additional, value, at+"."+key)
+	}
+}
+
+func (v *schemaValidator) validateArray(s map[string]interface{}, node *yaml.Node, at string) {
+	count := len(node.Content)
+	if limit, ok := numberKeyword(s, "minItems"); ok && float64(count) < limit {
+		v.fail(node, "%s: expected at least %v items, got %d", at, limit, count)
+	}
+	if limit, ok := numberKeyword(s, "maxItems"); ok && float64(count) > limit {
+		v.fail(node, "%s: expected at most %v items, got %d", at, limit, count)
+	}
+	if unique, _ := s["uniqueItems"].(bool); unique {
+		for i := 1; i < count; i++ {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(nodeJSONValue(node.Content[i]), nodeJSONValue(node.Content[j])) {
+					v.fail(node.Content[i], "%s[%d]: duplicates item %d", at, i, j)
+					break
+				}
+			}
+		}
+	}
+	if items, ok := s["items"]; ok {
+		for i, item := range node.Content {
+			v.validate(items, item, fmt.Sprintf("%s[%d]", at, i))
+		}
+	}
+}
+
+func (v *schemaValidator) validateScalar(s map[string]interface{}, node *yaml.Node, at string) {
+	switch jsonType(node) {
+	case "string":
+		length := float64(utf8.RuneCountInString(node.Value))
+		if limit, ok := numberKeyword(s, "minLength"); ok && length < limit {
+			v.fail(node, "%s: expected at least %v characters, got %v", at, limit, length)
+		}
+		if limit, ok := numberKeyword(s, "maxLength"); ok && length > limit {
+			v.fail(node, "%s: expected at most %v characters, got %v", at, limit, length)
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(node.Value) {
+				v.fail(node, "%s: %q does not match pattern %s", at, node.Value, pattern)
+			}
+		}
+	case "integer", "number":
+		value, err := strconv.ParseFloat(node.Value, 64)
+		if err != nil {
+			return
+		}
+		if limit, ok := numberKeyword(s, "minimum"); ok && value < limit {
+			v.fail(node, "%s: %v is less than the minimum %v", at, value, limit)
+		}
+		if limit, ok := numberKeyword(s, "maximum"); ok && value > limit {
+			v.fail(node, "%s: %v is greater than the maximum %v", at, value, limit)
+		}
+		if limit, ok := numberKeyword(s, "exclusiveMinimum"); ok && value <= limit {
+			v.fail(node, "%s: %v must be greater than %v", at, value, limit)
+		}
+		if limit, ok := numberKeyword(s, "exclusiveMaximum"); ok && value >= limit {
+			v.fail(node, "%s: %v must be less than %v", at, value, limit)
+		}
+		if divisor, ok := numberKeyword(s, "multipleOf"); ok && divisor > 0 {
+			if quotient := value / divisor; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+				v.fail(node, "%s: %v is not a multiple of %v", at, value, divisor)
+			}
+		}
+	}
+}
+
+func (v *schemaValidator) validateCompositions(s map[string]interface{}, node *yaml.Node, at string) {
+	if all, ok := s["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			v.validate(sub, node, at)
+		}
+	}
+	if any, ok := s["anyOf"].([]interface{}); ok && v.countMatches(any, node, at) == 0 {
+		v.fail(node, "%s: does not match any of the anyOf schemas", at)
+	}
+	if one, ok := s["oneOf"].([]interface{}); ok {
+		if matches := v.countMatches(one, node, at); matches != 1 {
+			v.fail(node, "%s: matches %d of the oneOf schemas, expected exactly one", at, matches)
+		}
+	}
+	if not, ok := s["not"]; ok && v.countMatches([]interface{}{not}, node, at) == 1 {
+		v.fail(node, "%s: must not match the not schema", at)
+	}
+}
+
+// countMatches returns how many of the schemas node matches, without recording violations
+func (v *schemaValidator) countMatches(schemas []interface{}, node *yaml.Node, at string) int {
+	matches := 0
+	for _, schema := range schemas {
+		sub := &schemaValidator{root: v.root}
+		sub.validate(schema, node, at)
+		if len(sub.violations) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func (v *schemaValidator) fail(node *yaml.Node, format string, args ...interface{}) {
+	v.violations = append(v.violations, schemaViolation{node: node, message: fmt.Sprintf(format, args...)})
+}
+
+// resolveSchemaRef resolves a local $ref such as #/$defs/Page within an extension schema
+func resolveSchemaRef(root map[string]interface{}, ref string) (interface{}, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	var current interface{} = root
+	for _, token := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[token]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// jsonType returns the JSON type of a YAML node
+func jsonType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.Tag {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+// matchesJSONType reports whether node has one of the types of a type keyword
+func matchesJSONType(node *yaml.Node, types interface{}) bool {
+	list, ok := types.([]interface{})
+	if !ok {
+		list = []interface{}{types}
+	}
+	actual := jsonType(node)
+	for _, t := range list {
+		switch t {
+		case actual:
+			return true
+		case "number":
+			if actual == "integer" {
+				return true
+			}
+		case "integer":
+			if value, err := strconv.ParseFloat(node.Value, 64); actual == "number" && err == nil && value == math.Trunc(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func describeJSONTypes(types interface{}) string {
+	list, ok := types.([]interface{})
+	if !ok {
+		return fmt.Sprint(types)
+	}
+	names := make([]string, 0, len(list))
+	for _, t := range list {
+		names = append(names, fmt.Sprint(t))
+	}
+	return strings.Join(names, " or ")
+}
+
+// numberKeyword returns a numeric schema keyword
+func numberKeyword(s map[string]interface{}, key string) (float64, bool) {
+	value, ok := s[key].(float64)
+	return value, ok
+}
+
+// nodeJSONValue decodes a node into the value encoding/json would produce for it
+func nodeJSONValue(node *yaml.Node) interface{} {
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil
+	}
+	return normalizeJSONValue(value)
+}
+
+// normalizeJSONValue converts a decoded YAML value into its JSON form: float64 numbers and
+// map[string]interface{} objects
+func normalizeJSONValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+func containsJSONValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatJSONValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// compileSchemaPatterns checks that every pattern keyword in a schema is a valid regular expression
+func compileSchemaPatterns(schema interface{}) error {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		for key, value := range s {
+			if pattern, ok := value.(string); ok && key == "pattern" {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+				}
+				continue
+			}
+			if err := compileSchemaPatterns(value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range s {
+			if err := compileSchemaPatterns(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sortedExtensionSchemaNames(schemas map[string]config.ExtensionSchema) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
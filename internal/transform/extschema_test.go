@@ -0,0 +1,129 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const extensionSchemaTestSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-pagination:
+        cursor: 42
+        results: data
+        style: offset
+      responses:
+        "200":
+          description: OK
+  /teams:
+    get:
+      x-pagination:
+        cursor: next
+        results: items
+        unknown: true
+      x-rate-limit: 100
+      responses:
+        "200":
+          description: OK
+`
+
+func extensionSchemaTestConfig() *config.Config {
+	return &config.Config{
+		ExtensionSchemas: map[string]config.ExtensionSchema{
+			"x-pagination": {Schema: map[string]interface{}{
+				"type":                 "object",
+				"required":             []interface{}{"cursor", "results", "page_size"},
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"cursor":    map[string]interface{}{"type": "string"},
+					"results":   map[string]interface{}{"type": "string", "minLength": 1},
+					"page_size": map[string]interface{}{"type": "integer", "maximum": 500},
+					"style":     map[string]interface{}{"$ref": "#/$defs/Style"},
+				},
+				"$defs": map[string]interface{}{
+					"Style": map[string]interface{}{"enum": []interface{}{"cursor", "page"}},
+				},
+			}},
+			"x-rate-limit": {Schema: map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 50}},
+		},
+	}
+}
+
+func TestCheckExtensions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(extensionSchemaTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas, err := LoadExtensionSchemas(extensionSchemaTestConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := CheckExtensions(dir, config.FileFilter{}, schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`9:9: x-pagination: missing required property "page_size"`,
+		"9:17: x-pagination.cursor: expected string, got integer",
+		`11:16: x-pagination.style: "offset" is not one of ["cursor","page"]`,
+		`18:9: x-pagination: missing required property "page_size"`,
+		`20:9: x-pagination: unexpected property "unknown"`,
+		"21:21: x-rate-limit: 100 is greater than the maximum 50",
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, strings.TrimPrefix(issue.Position(), path+":")+": "+issue.Message)
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected issues:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestLoadExtensionSchemasFromFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sdk-group.json"), []byte(`{"type": "string", "pattern": "^[a-z]+$"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		Source:           filepath.Join(dir, ".openmorph.yaml"),
+		ExtensionSchemas: map[string]config.ExtensionSchema{"x-sdk-group": {File: "sdk-group.json"}},
+	}
+
+	schemas, err := LoadExtensionSchemas(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schemas["x-sdk-group"]["pattern"] != "^[a-z]+$" {
+		t.Errorf("expected the schema file to be loaded, got %v", schemas)
+	}
+}
+
+func TestValidateExtensionSchemas(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas map[string]config.ExtensionSchema
+		want    string
+	}{
+		{"not an extension", map[string]config.ExtensionSchema{"pagination": {Schema: map[string]interface{}{}}}, "must start with x-"},
+		{"no schema", map[string]config.ExtensionSchema{"x-a": {}}, "exactly one of schema or file"},
+		{"bad pattern", map[string]config.ExtensionSchema{"x-a": {Schema: map[string]interface{}{"pattern": "("}}}, "invalid pattern"},
+		{"missing file", map[string]config.ExtensionSchema{"x-a": {File: "missing.json"}}, "missing.json"},
+	}
+	for _, tt := range tests {
+		err := ValidateExtensionSchemas(&config.Config{ExtensionSchemas: tt.schemas})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
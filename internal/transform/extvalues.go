@@ -0,0 +1,157 @@
+package transform
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExtensionValuesOptions extends the regular Options with the extension value lookup tables
+type ExtensionValuesOptions struct {
+	Options
+	Tables map[string]map[string]string // extension -> value -> replacement
+}
+
+// ExtensionValuesResult represents the result of replacing extension values
+type ExtensionValuesResult struct {
+	Changed        bool
+	ProcessedFiles []string
+	ReplacedValues map[string][]string // file -> list of replaced values
+	Locations      []ChangeLocation    // source positions of the replaced values
+}
+
+// createExtensionValuesResult creates a new ExtensionValuesResult with initialized maps
+func createExtensionValuesResult() *ExtensionValuesResult {
+	return &ExtensionValuesResult{
+		ProcessedFiles: []string{},
+		ReplacedValues: make(map[string][]string),
+	}
+}
+
+// setExtensionValuesProcessedFiles sets the processed files for an ExtensionValuesResult
+func setExtensionValuesProcessedFiles(result *ExtensionValuesResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setExtensionValuesChanged sets the changed flag for an ExtensionValuesResult
+func setExtensionValuesChanged(result *ExtensionValuesResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessExtensionValuesInDir replaces extension values through the lookup tables in all OpenAPI
+// files in a directory
+func ProcessExtensionValuesInDir(dir string, opts ExtensionValuesOptions) (*ExtensionValuesResult, error) {
+	if err := ValidateExtensionValues(opts.Tables); err != nil {
+		return createExtensionValuesResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepExtensionValues,
+		opts.Options,
+		len(opts.Tables) > 0,
+		false,
+		createExtensionValuesResult,
+		func(path string, result *ExtensionValuesResult) (bool, error) {
+			return processExtensionValuesInFile(path, opts, result)
+		},
+		setExtensionValuesProcessedFiles,
+		setExtensionValuesChanged,
+	)
+}
+
+// ValidateExtensionValues checks that every lookup table is keyed by an extension
+func ValidateExtensionValues(tables map[string]map[string]string) error {
+	for _, extension := range sortedKeysOf(tables) {
+		if !isExtensionKey(extension) {
+			return fmt.Errorf("extension_values: %q must start with x-", extension)
+		}
+	}
+	return nil
+}
+
+// processExtensionValuesInFile replaces extension values in a single file
+func processExtensionValuesInFile(path string, opts ExtensionValuesOptions, result *ExtensionValuesResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	r := &extensionValueReplacer{tables: opts.Tables, path: path, result: result}
+	if !r.walk(root, "") {
+		return false, nil
+	}
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// extensionValueReplacer replaces the values of the extensions of one document
+type extensionValueReplacer struct {
+	tables map[string]map[string]string
+	path   string
+	result *ExtensionValuesResult
+}
+
+// walk replaces the values of the extensions found anywhere below node, at is the dotted path of
+// node. Only scalar values and the scalar items of sequences are replaced.
+func (r *extensionValueReplacer) walk(node *yaml.Node, at string) bool {
+	changed := false
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if table, ok := r.tables[key]; ok {
+				switch value.Kind {
+				case yaml.ScalarNode:
+					if r.replace(value, table, at, key) {
+						changed = true
+					}
+				case yaml.SequenceNode:
+					for j, item := range value.Content {
+						if item.Kind == yaml.ScalarNode && r.replace(item, table, at, fmt.Sprintf("%s[%d]", key, j)) {
+							changed = true
+						}
+					}
+				}
+			}
+			if r.walk(value, buildPath(at, key)) {
+				changed = true
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			if r.walk(item, fmt.Sprintf("%s[%d]", at, i)) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// replace sets a scalar to its replacement in table, if it has one. Replacements are written as
+// strings.
+func (r *extensionValueReplacer) replace(value *yaml.Node, table map[string]string, at, label string) bool {
+	replacement, ok := table[value.Value]
+	if !ok || replacement == value.Value || value.Tag == "!!null" {
+		return false
+	}
+
+	message := fmt.Sprintf("%s: %s -> %s", label, value.Value, replacement)
+	if at != "" {
+		message = at + " " + message
+	}
+	r.result.ReplacedValues[r.path] = append(r.result.ReplacedValues[r.path], message)
+	r.result.Locations = append(r.result.Locations, newChangeLocation(r.path, StepExtensionValues, value, message))
+
+	value.Value = replacement
+	value.Tag = "!!str"
+	if value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		value.Style = 0
+	}
+	return true
+}
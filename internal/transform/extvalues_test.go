@@ -0,0 +1,89 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const extensionValuesSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+  x-owner: TEAM-042
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      x-owner: TEAM-042
+      x-sdk-group: [legacy_users, admin]
+      x-other: TEAM-042
+      responses:
+        "200":
+          description: OK
+    post:
+      x-owner:
+        team: TEAM-042
+      responses:
+        "201":
+          description: Created
+`
+
+func TestProcessExtensionValues(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(extensionValuesSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := ExtensionValuesOptions{Tables: map[string]map[string]string{
+		"x-owner":     {"TEAM-042": "Payments"},
+		"x-sdk-group": {"legacy_users": "users"},
+	}}
+	result, err := ProcessExtensionValuesInDir(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"info x-owner: TEAM-042 -> Payments",
+		"paths./users.get x-owner: TEAM-042 -> Payments",
+		"paths./users.get x-sdk-group[0]: legacy_users -> users",
+	}
+	if !reflect.DeepEqual(result.ReplacedValues[path], want) {
+		t.Errorf("expected replacements %v, got %v", want, result.ReplacedValues[path])
+	}
+	if len(result.Locations) != 3 || result.Locations[0].Line != 5 {
+		t.Errorf("unexpected locations %+v", result.Locations)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"x-sdk-group: [users, admin]", "x-other: TEAM-042", "team: TEAM-042"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected %q in the output:\n%s", expected, data)
+		}
+	}
+
+	// A second run finds nothing left to replace
+	result, err = ProcessExtensionValuesInDir(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed {
+		t.Errorf("expected no changes on a second run, got %v", result.ReplacedValues)
+	}
+}
+
+func TestValidateExtensionValues(t *testing.T) {
+	if err := ValidateExtensionValues(map[string]map[string]string{"owner": {"a": "b"}}); err == nil {
+		t.Error("expected a table for a key without the x- prefix to be rejected")
+	}
+	if err := ValidateExtensionValues(map[string]map[string]string{"x-owner": {"a": "b"}}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
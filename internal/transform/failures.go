@@ -0,0 +1,94 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// FileError is a file a step failed to parse or process. With KeepGoing the pipeline records it,
+// leaves the file alone in the remaining steps and carries on with the other files.
+type FileError struct {
+	File    string
+	Step    string
+	Line    int // 1-based line the parser reported, 0 if unknown
+	Message string
+}
+
+// Position returns file:line, or the file alone when the line is unknown
+func (e FileError) Position() string {
+	if e.Line == 0 {
+		return e.File
+	}
+	return fmt.Sprintf("%s:%d", e.File, e.Line)
+}
+
+// errorLinePattern finds the line in YAML and JSON parser errors, such as "yaml: line 3: ..."
+var errorLinePattern = regexp.MustCompile(`\bline (\d+)\b`)
+
+// fileErrors collects the files that failed during a keep-going run. It is safe for concurrent
+// use; a nil collector records nothing, so the first error aborts the run.
+type fileErrors struct {
+	mu     sync.Mutex
+	errors []FileError
+	failed map[string]bool
+}
+
+// newFileErrors returns the collector of a run, nil unless the pipeline keeps going
+func (tp *TransformationPipeline) newFileErrors() *fileErrors {
+	if !tp.KeepGoing {
+		return nil
+	}
+	return &fileErrors{failed: make(map[string]bool)}
+}
+
+// record notes that step failed to process path
+func (f *fileErrors) record(step, path string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fileError := FileError{File: path, Step: step, Message: err.Error()}
+	if match := errorLinePattern.FindStringSubmatch(fileError.Message); match != nil {
+		fileError.Line, _ = strconv.Atoi(match[1])
+	}
+	f.errors = append(f.errors, fileError)
+	f.failed[path] = true
+}
+
+// leaveAlone makes the remaining steps skip path without recording an error for it, for a file that
+// failed in an earlier part of the run
+func (f *fileErrors) leaveAlone(path string) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed[path] = true
+}
+
+// has reports whether a step already failed to process path
+func (f *fileErrors) has(path string) bool {
+	if f == nil {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.failed[path]
+}
+
+// list returns the recorded errors in the order the files failed
+func (f *fileErrors) list() []FileError {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FileError(nil), f.errors...)
+}
+
+// normalizeFileErrors reports the errors found on a temporary copy against inputPath
+func normalizeFileErrors(inputPath string, results *TransformationResults) {
+	for i := range results.FileErrors {
+		results.FileErrors[i].File = inputPath
+	}
+}
@@ -0,0 +1,97 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const keepGoingSpec = `openapi: 3.0.3
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: true
+      responses:
+        "200":
+          description: OK
+`
+
+func writeKeepGoingInput(t *testing.T) (dir, good, broken string) {
+	t.Helper()
+	dir = t.TempDir()
+	good = filepath.Join(dir, "good.yaml")
+	broken = filepath.Join(dir, "broken.yaml")
+	if err := os.WriteFile(good, []byte(keepGoingSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(broken, []byte("openapi: 3.0.3\ninfo: {title: API\npaths: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir, good, broken
+}
+
+func TestKeepGoingRecordsBrokenFiles(t *testing.T) {
+	dir, good, broken := writeKeepGoingInput(t)
+	cfg := &config.Config{Mappings: map[string]string{"x-foo": "x-bar"}}
+
+	tp := NewTransformationPipeline(cfg, nil, false, false, "")
+	tp.Strict = true
+	tp.KeepGoing = true
+	results, err := tp.ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatalf("expected the run to keep going, got %v", err)
+	}
+
+	if len(results.FileErrors) != 1 {
+		t.Fatalf("expected 1 file error, got %+v", results.FileErrors)
+	}
+	fileError := results.FileErrors[0]
+	if fileError.File != broken || fileError.Step != StepMappings || fileError.Line != 1 {
+		t.Errorf("unexpected file error %+v", fileError)
+	}
+	if fileError.Position() != broken+":1" {
+		t.Errorf("unexpected position %q", fileError.Position())
+	}
+
+	data, err := os.ReadFile(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "x-bar") {
+		t.Errorf("expected the good file to be transformed, got:\n%s", data)
+	}
+}
+
+func TestKeepGoingDisabledAbortsOnBrokenFile(t *testing.T) {
+	dir, _, _ := writeKeepGoingInput(t)
+	cfg := &config.Config{Mappings: map[string]string{"x-foo": "x-bar"}}
+
+	if _, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir); err == nil {
+		t.Fatal("expected the broken file to abort the run")
+	}
+}
+
+func TestKeepGoingSingleFile(t *testing.T) {
+	_, _, broken := writeKeepGoingInput(t)
+	output := filepath.Join(t.TempDir(), "out.yaml")
+	cfg := &config.Config{Mappings: map[string]string{"x-foo": "x-bar"}}
+
+	tp := NewTransformationPipeline(cfg, nil, false, false, output)
+	tp.KeepGoing = true
+	results, err := tp.ExecuteFullPipeline(broken)
+	if err != nil {
+		t.Fatalf("expected the run to keep going, got %v", err)
+	}
+	if len(results.FileErrors) != 1 || results.FileErrors[0].File != broken {
+		t.Fatalf("expected the input to be reported once, got %+v", results.FileErrors)
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("expected no output for a broken file, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package transform
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// pipelineSteps lists the steps that walk the input, in pipeline order. Per-step file patterns
+// are keyed by these names.
+var pipelineSteps = []string{
+	StepMappings,
+	StepStripInternal,
+	StepPathVariants,
+	StepTenantPaths,
+	StepUnwrapEnvelopes,
+	StepPagination,
+	StepFlatten,
+	StepParameterInjection,
+	StepVendorExtensions,
+	StepExtensionValues,
+	StepDefaults,
+	StepSchemaConstraints,
+	StepNullability,
+	StepComponentDedup,
+	StepComponentRenames,
+	StepSchemaTitles,
+	StepComponentConsistency,
+	StepExamples,
+	StepCanonicalize,
+	StepLinkSync,
+}
+
+// StepOrder returns the position of a step in the pipeline, for listing steps in the order they
+// run. Steps that do not walk the input come after the others.
+func StepOrder(step string) int {
+	for i, name := range pipelineSteps {
+		if name == step {
+			return i
+		}
+	}
+	return len(pipelineSteps)
+}
+
+// ValidateFileFilter checks that every pattern is well-formed and every per-step override names a
+// known step
+func ValidateFileFilter(filter config.FileFilter) error {
+	if err := validatePatterns("files", filter.Include, filter.Exclude); err != nil {
+		return err
+	}
+	for step, patterns := range filter.Steps {
+		if !isPipelineStep(step) {
+			return fmt.Errorf("files.steps: unknown step %q (supported: %s)", step, strings.Join(pipelineSteps, ", "))
+		}
+		if err := validatePatterns("files.steps."+step, patterns.Include, patterns.Exclude); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePatterns(field string, include, exclude []string) error {
+	for _, pattern := range include {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s.include: invalid pattern %q", field, pattern)
+		}
+	}
+	for _, pattern := range exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s.exclude: invalid pattern %q", field, pattern)
+		}
+	}
+	return nil
+}
+
+func isPipelineStep(step string) bool {
+	for _, name := range pipelineSteps {
+		if name == step {
+			return true
+		}
+	}
+	return false
+}
+
+// IncludesFile reports whether the step processes the file at path, found while walking root.
+// A file passed directly as root is always processed.
+func IncludesFile(filter config.FileFilter, step, root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return true
+	}
+	rel = filepath.ToSlash(rel)
+
+	patterns := filter.ForStep(step)
+	if len(patterns.Include) > 0 && !matchesAnyPattern(patterns.Include, rel) {
+		return false
+	}
+	return !matchesAnyPattern(patterns.Exclude, rel)
+}
+
+// stepProcessesFile reports whether a step walking root processes the file at path
+func stepProcessesFile(opts Options, step, root, path string) bool {
+	return (IsYAML(path) || IsJSON(path)) && IncludesFile(opts.Files, step, root, path) && sniffAllows(step, path)
+}
+
+// countProgressFiles counts the OpenAPI documents under inputPath that the steps process, the
+// total a progress indicator counts towards. A single file counts as one.
+func countProgressFiles(inputPath string, files config.FileFilter) int {
+	info, err := os.Stat(inputPath)
+	if err != nil || !info.IsDir() {
+		return 1
+	}
+
+	count := 0
+	opts := Options{Files: files}
+	_ = filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && stepProcessesFile(opts, "", inputPath, path) {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+func matchesAnyPattern(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if matchFilePattern(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFilePattern matches a slash-separated relative path against a glob pattern. `**` matches
+// zero or more path segments, and a pattern without a `/` matches the file name at any depth.
+func matchFilePattern(pattern, rel string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
@@ -0,0 +1,177 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestMatchFilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{"*.yaml", "api.yaml", true},
+		{"*.yaml", "nested/dir/api.yaml", true},
+		{"*.yaml", "api.json", false},
+		{"specs/*.yaml", "specs/api.yaml", true},
+		{"specs/*.yaml", "specs/v1/api.yaml", false},
+		{"specs/**", "specs/v1/api.yaml", true},
+		{"**/fixtures/**", "fixtures/a.yaml", true},
+		{"**/fixtures/**", "specs/fixtures/deep/a.yaml", true},
+		{"**/fixtures/**", "specs/a.yaml", false},
+		{".github/**", ".github/workflows/ci.yml", true},
+		{"./specs/**/api.yaml", "specs/api.yaml", true},
+	}
+	for _, tt := range tests {
+		if got := matchFilePattern(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("matchFilePattern(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestIncludesFile(t *testing.T) {
+	filter := config.FileFilter{
+		Include: []string{"specs/**"},
+		Exclude: []string{"**/fixtures/**"},
+		Steps: map[string]config.FilePatterns{
+			StepDefaults: {Exclude: []string{}},
+		},
+	}
+	root := filepath.Join("repo")
+
+	if !IncludesFile(filter, StepPagination, root, filepath.Join(root, "specs", "api.yaml")) {
+		t.Error("expected specs/api.yaml to be included")
+	}
+	if IncludesFile(filter, StepPagination, root, filepath.Join(root, "config.yaml")) {
+		t.Error("expected files outside include patterns to be skipped")
+	}
+	if IncludesFile(filter, StepPagination, root, filepath.Join(root, "specs", "fixtures", "a.yaml")) {
+		t.Error("expected fixtures to be excluded")
+	}
+	if !IncludesFile(filter, StepDefaults, root, filepath.Join(root, "specs", "fixtures", "a.yaml")) {
+		t.Error("expected the defaults override to clear the exclude patterns")
+	}
+	if !IncludesFile(filter, StepPagination, filepath.Join(root, "config.yaml"), filepath.Join(root, "config.yaml")) {
+		t.Error("expected a file passed as the input to always be included")
+	}
+}
+
+func TestValidateFileFilter(t *testing.T) {
+	if err := ValidateFileFilter(config.FileFilter{Exclude: []string{"[a-"}}); err == nil || !strings.Contains(err.Error(), "files.exclude") {
+		t.Errorf("expected invalid pattern error, got %v", err)
+	}
+	err := ValidateFileFilter(config.FileFilter{Steps: map[string]config.FilePatterns{"vendor": {}}})
+	if err == nil || !strings.Contains(err.Error(), `unknown step "vendor"`) {
+		t.Errorf("expected unknown step error, got %v", err)
+	}
+	if err := ValidateFileFilter(config.FileFilter{Steps: map[string]config.FilePatterns{StepFlatten: {Include: []string{"*.yaml"}}}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPipeline_FilePatternsPerStep(t *testing.T) {
+	dir := t.TempDir()
+	spec := `openapi: 3.0.0
+info:
+  title: Test
+  version: 1.0.0
+x-old: value
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+`
+	for _, rel := range []string{"specs/api.yaml", "specs/fixtures/fixture.yaml"} {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Mappings: map[string]string{"x-old": "x-new"},
+		DefaultValues: config.DefaultValues{
+			Enabled: true,
+			Rules: map[string]config.DefaultRule{
+				"limit": {
+					Target:    config.DefaultTarget{Location: "parameter"},
+					Condition: config.DefaultCondition{Type: "integer"},
+					Value:     20,
+				},
+			},
+		},
+		Files: config.FileFilter{
+			Exclude: []string{"**/fixtures/**"},
+			Steps:   map[string]config.FilePatterns{StepDefaults: {Exclude: []string{}}},
+		},
+	}
+	if _, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir); err != nil {
+		t.Fatalf("ExecuteFullPipeline failed: %v", err)
+	}
+
+	fixture, err := os.ReadFile(filepath.Join(dir, "specs", "fixtures", "fixture.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(fixture), "x-old") {
+		t.Error("expected the excluded fixture to keep its unmapped key")
+	}
+	if !strings.Contains(string(fixture), "default: 20") {
+		t.Error("expected the defaults step override to process the fixture")
+	}
+
+	api, err := os.ReadFile(filepath.Join(dir, "specs", "api.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(api), "x-new") {
+		t.Error("expected specs/api.yaml to be mapped")
+	}
+}
+
+func TestCountProgressFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"specs/users.yaml":         "openapi: 3.0.0\n",
+		"specs/orders.json":        `{"openapi": "3.0.0"}`,
+		"specs/fixtures/a.yaml":    "openapi: 3.0.0\n",
+		".github/workflows/ci.yml": "on: push\n",
+		"specs/ignored.yaml":       "openapi: 3.0.0\nx-openmorph: ignore\n",
+		"specs/README.md":          "# Specs\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := countProgressFiles(dir, config.FileFilter{}); got != 3 {
+		t.Errorf("expected 3 files, got %d", got)
+	}
+	if got := countProgressFiles(dir, config.FileFilter{Exclude: []string{"**/fixtures/**"}}); got != 2 {
+		t.Errorf("expected 2 files without fixtures, got %d", got)
+	}
+	if got := countProgressFiles(filepath.Join(dir, "specs", "users.yaml"), config.FileFilter{}); got != 1 {
+		t.Errorf("expected a single file to count as 1, got %d", got)
+	}
+}
@@ -7,12 +7,20 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
 )
 
+// NoFlattenExtension marks a schema whose compositions and references are never flattened
+const NoFlattenExtension = "x-openmorph-no-flatten"
+
 // FlattenOptions extends the regular Options with flattening-specific settings
 type FlattenOptions struct {
 	Options
 	FlattenResponses bool
+	Exclude          []string // component schemas that are never flattened or chain-collapsed
+	AllOf            config.FlattenAllOf
+	Metadata         string // whose metadata wins when a single-member composition is collapsed: wrapper (default) or target
 }
 
 // FlattenResult represents the result of flattening processing
@@ -21,6 +29,8 @@ type FlattenResult struct {
 	ProcessedFiles    []string
 	FlattenedRefs     map[string][]string // file -> flattened reference paths
 	RemovedComponents map[string][]string // file -> removed component names
+	SkippedSchemas    map[string][]Skip   // file -> schemas left alone, with the reason
+	Locations         []ChangeLocation    // source positions of flattened references
 }
 
 // ProcessFlatteningInDir processes response flattening in all OpenAPI files in a directory
@@ -29,6 +39,7 @@ func ProcessFlatteningInDir(dir string, opts FlattenOptions) (*FlattenResult, er
 		ProcessedFiles:    []string{},
 		FlattenedRefs:     make(map[string][]string),
 		RemovedComponents: make(map[string][]string),
+		SkippedSchemas:    make(map[string][]Skip),
 	}
 
 	if !opts.FlattenResponses {
@@ -43,8 +54,10 @@ func ProcessFlatteningInDir(dir string, opts FlattenOptions) (*FlattenResult, er
 			return nil
 		}
 
-		if IsYAML(path) || IsJSON(path) {
-			changed, err := processFlatteningInFile(path, opts, result)
+		if stepProcessesFile(opts.Options, StepFlatten, dir, path) {
+			changed, err := traceFile(opts.Options, StepFlatten, path, func() (bool, error) {
+				return processFlatteningInFile(path, opts, result)
+			})
 			if err != nil {
 				return fmt.Errorf("error processing %s: %w", path, err)
 			}
@@ -80,20 +93,29 @@ func processDocumentFlattening(doc, root *yaml.Node, path string, opts FlattenOp
 	// Validate composition structures before processing
 	if validationErrors := ValidateAndReportCompositions(root, path); validationErrors != "" {
 		// Log validation warnings but continue processing
-		fmt.Printf("⚠️  Validation warnings for %s:\n%s", path, validationErrors)
+		fmt.Fprintf(opts.logWriter(), "⚠️  Validation warnings for %s:\n%s", path, validationErrors)
 	}
 
 	// Track component references before flattening to identify unused ones later
 	componentsBefore := extractComponentRefs(root)
 
-	// First pass: flatten oneOf/anyOf/allOf with single refs
+	// Merge allOf: [$ref] with sibling properties first, before the single ref is flattened away
 	changed := false
-	processComponentsFlattening(root, path, result, &changed)
-	processPathsFlattening(root, path, result, &changed)
+	if opts.AllOf.Enabled && mergeAllOfSiblings(root, opts, path, result) {
+		changed = true
+	}
+
+	// First pass: flatten oneOf/anyOf/allOf with single refs
+	// Components are shared by every operation, so they are left alone when paths are limited
+	meta := flattenMetadata{root: root, rule: opts.Metadata}
+	if len(opts.Paths) == 0 {
+		processComponentsFlattening(root, opts.Exclude, meta, path, result, &changed)
+	}
+	processPathsFlattening(scopeToPaths(root, opts.Paths), meta, path, result, &changed)
 
 	// Second pass: flatten reference chains (optional, more aggressive)
 	if opts.FlattenResponses {
-		if flattenReferenceChains(root, path, result, &changed) {
+		if flattenReferenceChains(root, opts.Paths, opts.Exclude, path, result, &changed) {
 			changed = true
 		}
 	}
@@ -111,32 +133,29 @@ func processDocumentFlattening(doc, root *yaml.Node, path string, opts FlattenOp
 			result.RemovedComponents[path] = unused
 		}
 
-		// Only write to file if not in dry-run mode
-		if opts.DryRun {
-			return true, nil // Return true to indicate changes were detected, but don't write
-		}
-
-		return writeModifiedDocument(doc, path)
+		return writeStepDocument(opts.Options, doc, path)
 	}
 
 	return false, nil
 }
 
-// flattenReferenceChains flattens chains of references to point directly to final targets
-func flattenReferenceChains(root *yaml.Node, filePath string, result *FlattenResult, changed *bool) bool {
+// flattenReferenceChains flattens chains of references to point directly to final targets. With
+// patterns, only the references in matching path items are updated. Schemas that opt out of
+// flattening are neither collapsed nor updated.
+func flattenReferenceChains(root *yaml.Node, patterns, exclude []string, filePath string, result *FlattenResult, changed *bool) bool {
 	// Build a map of schema name to its direct reference (if it's just a $ref)
-	refMap := buildDirectRefMap(root)
+	refMap := buildDirectRefMap(root, exclude)
 
 	if len(refMap) == 0 {
 		return false
 	}
 	// Flatten reference chains in components/schemas
 	// Capture the result of the first flattening operation
-	schemaChanged := flattenSchemaReferences(root, refMap, filePath, result)
+	schemaChanged := len(patterns) == 0 && flattenSchemaReferences(root, refMap, exclude, filePath, result)
 
 	// Flatten reference chains in paths
 	// Capture the result of the second flattening operation
-	pathChanged := flattenPathReferences(root, refMap, filePath, result)
+	pathChanged := flattenPathReferences(scopeToPaths(root, patterns), refMap, filePath, result)
 
 	// Combine the results: localChanged is true if either operation made a change
 	localChanged := schemaChanged || pathChanged
@@ -147,8 +166,9 @@ func flattenReferenceChains(root *yaml.Node, filePath string, result *FlattenRes
 	return localChanged
 }
 
-// buildDirectRefMap builds a map of schema names that are direct references
-func buildDirectRefMap(root *yaml.Node) map[string]string {
+// buildDirectRefMap builds a map of schema names that are direct references, leaving out the
+// schemas that opt out of flattening
+func buildDirectRefMap(root *yaml.Node, exclude []string) map[string]string {
 	refMap := make(map[string]string)
 
 	components := getNodeValue(root, "components")
@@ -165,7 +185,7 @@ func buildDirectRefMap(root *yaml.Node) map[string]string {
 		schemaName := schemas.Content[i].Value
 		schemaNode := schemas.Content[i+1]
 
-		if schemaNode.Kind == yaml.MappingNode {
+		if schemaNode.Kind == yaml.MappingNode && !optsOutOfFlattening(schemaName, schemaNode, exclude) {
 			// Check if this schema is just a direct $ref
 			if refValue := getDirectRef(schemaNode); refValue != "" {
 				refMap[schemaName] = refValue
@@ -190,7 +210,7 @@ func getDirectRef(node *yaml.Node) string {
 }
 
 // flattenSchemaReferences flattens reference chains in schemas
-func flattenSchemaReferences(root *yaml.Node, refMap map[string]string, filePath string, result *FlattenResult) bool {
+func flattenSchemaReferences(root *yaml.Node, refMap map[string]string, exclude []string, filePath string, result *FlattenResult) bool {
 	localChanged := false
 
 	components := getNodeValue(root, "components")
@@ -206,6 +226,9 @@ func flattenSchemaReferences(root *yaml.Node, refMap map[string]string, filePath
 	for i := 0; i < len(schemas.Content); i += 2 {
 		schemaName := schemas.Content[i].Value
 		schemaNode := schemas.Content[i+1]
+		if optsOutOfFlattening(schemaName, schemaNode, exclude) {
+			continue
+		}
 
 		if updateReferencesInNode(schemaNode, refMap, filePath, result, schemaName) {
 			localChanged = true
@@ -257,11 +280,7 @@ func updateReferencesInNode(node *yaml.Node, refMap map[string]string, filePath
 					localChanged = true
 
 					// Record the flattening
-					if result.FlattenedRefs[filePath] == nil {
-						result.FlattenedRefs[filePath] = []string{}
-					}
-					result.FlattenedRefs[filePath] = append(result.FlattenedRefs[filePath],
-						fmt.Sprintf("%s: %s -> %s", context, value.Value, newRef))
+					recordFlattening(result, filePath, value, fmt.Sprintf("%s: %s -> %s", context, value.Value, newRef))
 				}
 			} else {
 				if updateReferencesInNode(value, refMap, filePath, result, context) {
@@ -317,8 +336,9 @@ func resolveReferenceChain(ref string, refMap map[string]string) string {
 	}
 }
 
-// processComponentsFlattening processes flattening in the components section
-func processComponentsFlattening(root *yaml.Node, path string, result *FlattenResult, changed *bool) bool {
+// processComponentsFlattening processes flattening in the components section, skipping the
+// excluded schemas
+func processComponentsFlattening(root *yaml.Node, exclude []string, meta flattenMetadata, path string, result *FlattenResult, changed *bool) bool {
 	components := getNodeValue(root, "components")
 	if components == nil || components.Kind != yaml.MappingNode {
 		return false
@@ -333,8 +353,12 @@ func processComponentsFlattening(root *yaml.Node, path string, result *FlattenRe
 	for i := 0; i < len(schemas.Content); i += 2 {
 		schemaName := schemas.Content[i].Value
 		schemaNode := schemas.Content[i+1]
+		if contains(exclude, schemaName) {
+			recordFlattenSkip(result, path, schemaName, SkipExcluded, "flatten_exclude")
+			continue
+		}
 
-		if flattenSchemaNode(schemaNode, schemaName, path, result) {
+		if flattenSchemaNode(schemaNode, meta, schemaName, path, result) {
 			localChanged = true
 		}
 	}
@@ -346,7 +370,7 @@ func processComponentsFlattening(root *yaml.Node, path string, result *FlattenRe
 }
 
 // processPathsFlattening processes flattening in the paths section
-func processPathsFlattening(root *yaml.Node, path string, result *FlattenResult, changed *bool) bool {
+func processPathsFlattening(root *yaml.Node, meta flattenMetadata, path string, result *FlattenResult, changed *bool) bool {
 	paths := getNodeValue(root, "paths")
 	if paths == nil || paths.Kind != yaml.MappingNode {
 		return false
@@ -361,7 +385,7 @@ func processPathsFlattening(root *yaml.Node, path string, result *FlattenResult,
 			continue
 		}
 
-		if flattenPathNode(pathNode, pathName, path, result) {
+		if flattenPathNode(pathNode, meta, pathName, path, result) {
 			localChanged = true
 		}
 	}
@@ -373,10 +397,14 @@ func processPathsFlattening(root *yaml.Node, path string, result *FlattenResult,
 }
 
 // flattenSchemaNode flattens oneOf/anyOf/allOf in a schema node
-func flattenSchemaNode(node *yaml.Node, schemaName, path string, result *FlattenResult) bool {
+func flattenSchemaNode(node *yaml.Node, meta flattenMetadata, schemaName, path string, result *FlattenResult) bool {
 	if node == nil || node.Kind != yaml.MappingNode {
 		return false
 	}
+	if hasNoFlattenMarker(node) {
+		recordFlattenSkip(result, path, schemaName, SkipExcluded, NoFlattenExtension)
+		return false
+	}
 
 	changed := false
 
@@ -387,15 +415,17 @@ func flattenSchemaNode(node *yaml.Node, schemaName, path string, result *Flatten
 
 		switch {
 		case isCompositionKey(key):
-			if processCompositionKey(node, i, key, value, schemaName, path, result) {
+			// Collapsing to a $ref may drop metadata before the key, so carry on after the $ref
+			if next, ok := processCompositionKey(node, i, key, value, meta, schemaName, path, result); ok {
+				i = next
 				changed = true
 			}
 		case key == "properties":
-			if processPropertiesNode(value, schemaName, path, result) {
+			if processPropertiesNode(value, meta, schemaName, path, result) {
 				changed = true
 			}
 		default:
-			if processOtherNodes(value, schemaName, path, result) {
+			if processOtherNodes(value, meta, schemaName, path, result) {
 				changed = true
 			}
 		}
@@ -404,35 +434,42 @@ func flattenSchemaNode(node *yaml.Node, schemaName, path string, result *Flatten
 	return changed
 }
 
-// processCompositionKey handles oneOf/anyOf/allOf keys
-func processCompositionKey(parentNode *yaml.Node, keyIndex int, key string, value *yaml.Node, schemaName, path string, result *FlattenResult) bool {
+// processCompositionKey handles oneOf/anyOf/allOf keys. When it changes the parent it returns the
+// index of the last key it handled.
+func processCompositionKey(parentNode *yaml.Node, keyIndex int, key string, value *yaml.Node, meta flattenMetadata, schemaName, path string, result *FlattenResult) (int, bool) {
 	if isEmptyComposition(value) {
 		// Handle empty composition by removing it entirely
 		handleEmptyComposition(parentNode, keyIndex, schemaName, key, path, result)
-		return true
+		return keyIndex, true
 	}
 
 	if refValue := getSingleRefFromArray(value); refValue != "" {
-		// Replace the oneOf/anyOf/allOf with direct $ref
-		parentNode.Content[keyIndex] = &yaml.Node{Kind: yaml.ScalarNode, Value: "$ref"}
-		parentNode.Content[keyIndex+1] = &yaml.Node{Kind: yaml.ScalarNode, Value: refValue}
+		keyNode := parentNode.Content[keyIndex]
+
+		// Replace the oneOf/anyOf/allOf with direct $ref, keeping the metadata of both sides
+		refIndex := meta.collapseToRef(parentNode, keyIndex, value.Content[0], refValue)
 
 		// Record the flattening
-		recordFlattening(result, path, fmt.Sprintf("%s.%s -> $ref: %s", schemaName, key, refValue))
-		return true
+		recordFlattening(result, path, keyNode, fmt.Sprintf("%s.%s -> $ref: %s", schemaName, key, refValue))
+		return refIndex, true
 	}
 
 	if singleSchema := getSingleSchemaFromArray(value); singleSchema != nil {
+		if conflict := inlineConflict(parentNode, keyIndex, singleSchema); conflict != "" {
+			recordFlattenSkip(result, path, schemaName, SkipConflictingKeywords, fmt.Sprintf("%s member redefines %s", key, conflict))
+			return keyIndex, false
+		}
+
 		// Replace the oneOf/anyOf/allOf with the single inline schema
-		flattenCompositionWithInlineSchema(parentNode, keyIndex, singleSchema, schemaName, key, path, result)
-		return true
+		flattenCompositionWithInlineSchema(parentNode, keyIndex, singleSchema, meta, schemaName, key, path, result)
+		return keyIndex, true
 	}
 
-	return false
+	return keyIndex, false
 }
 
 // processPropertiesNode handles the properties section
-func processPropertiesNode(value *yaml.Node, schemaName, path string, result *FlattenResult) bool {
+func processPropertiesNode(value *yaml.Node, meta flattenMetadata, schemaName, path string, result *FlattenResult) bool {
 	if value.Kind != yaml.MappingNode {
 		return false
 	}
@@ -446,7 +483,7 @@ func processPropertiesNode(value *yaml.Node, schemaName, path string, result *Fl
 
 		if propNode.Kind == yaml.MappingNode {
 			propSchemaName := fmt.Sprintf("%s.properties.%s", schemaName, propName)
-			if flattenSchemaNode(propNode, propSchemaName, path, result) {
+			if flattenSchemaNode(propNode, meta, propSchemaName, path, result) {
 				changed = true
 			}
 
@@ -466,19 +503,19 @@ func processPropertiesNode(value *yaml.Node, schemaName, path string, result *Fl
 }
 
 // processOtherNodes handles other node types (mappings, sequences)
-func processOtherNodes(value *yaml.Node, schemaName, path string, result *FlattenResult) bool {
+func processOtherNodes(value *yaml.Node, meta flattenMetadata, schemaName, path string, result *FlattenResult) bool {
 	changed := false
 
 	switch value.Kind {
 	case yaml.MappingNode:
 		// Recursively process nested objects
-		if flattenSchemaNode(value, schemaName, path, result) {
+		if flattenSchemaNode(value, meta, schemaName, path, result) {
 			changed = true
 		}
 	case yaml.SequenceNode:
 		// Process arrays
 		for _, item := range value.Content {
-			if flattenSchemaNode(item, schemaName, path, result) {
+			if flattenSchemaNode(item, meta, schemaName, path, result) {
 				changed = true
 			}
 		}
@@ -517,16 +554,37 @@ func removeEmptyProperties(propertiesNode *yaml.Node, propertiesToRemove []int)
 	return true
 }
 
-// recordFlattening records a flattening operation in the result
-func recordFlattening(result *FlattenResult, path, flattenedPath string) {
+// recordFlattening records a flattening operation in the result, positioned at node
+func recordFlattening(result *FlattenResult, path string, node *yaml.Node, flattenedPath string) {
 	if result.FlattenedRefs[path] == nil {
 		result.FlattenedRefs[path] = []string{}
 	}
 	result.FlattenedRefs[path] = append(result.FlattenedRefs[path], flattenedPath)
+	result.Locations = append(result.Locations, newChangeLocation(path, StepFlatten, node, flattenedPath))
+}
+
+// recordFlattenSkip records a schema left alone because it opts out of flattening
+func recordFlattenSkip(result *FlattenResult, path, schemaName string, code SkipCode, reason string) {
+	if result.SkippedSchemas == nil {
+		result.SkippedSchemas = make(map[string][]Skip)
+	}
+	result.SkippedSchemas[path] = append(result.SkippedSchemas[path], Skip{Message: fmt.Sprintf("%s (%s)", schemaName, reason), Code: code})
+}
+
+// hasNoFlattenMarker reports whether a schema is marked x-openmorph-no-flatten: true
+func hasNoFlattenMarker(node *yaml.Node) bool {
+	marker := getNodeValue(node, NoFlattenExtension)
+	return marker != nil && marker.Kind == yaml.ScalarNode && marker.Value == "true"
+}
+
+// optsOutOfFlattening reports whether a component schema is excluded from flattening by name or
+// by its marker
+func optsOutOfFlattening(name string, node *yaml.Node, exclude []string) bool {
+	return contains(exclude, name) || hasNoFlattenMarker(node)
 }
 
 // flattenPathNode flattens oneOf/anyOf/allOf in path responses
-func flattenPathNode(node *yaml.Node, pathName, path string, result *FlattenResult) bool {
+func flattenPathNode(node *yaml.Node, meta flattenMetadata, pathName, path string, result *FlattenResult) bool {
 	if node == nil || node.Kind != yaml.MappingNode {
 		return false
 	}
@@ -541,7 +599,7 @@ func flattenPathNode(node *yaml.Node, pathName, path string, result *FlattenResu
 		if methodNode.Kind == yaml.MappingNode {
 			responses := getNodeValue(methodNode, "responses")
 			if responses != nil && responses.Kind == yaml.MappingNode {
-				if flattenResponsesNode(responses, fmt.Sprintf("%s %s", method, pathName), path, result) {
+				if flattenResponsesNode(responses, meta, fmt.Sprintf("%s %s", method, pathName), path, result) {
 					changed = true
 				}
 			}
@@ -552,7 +610,7 @@ func flattenPathNode(node *yaml.Node, pathName, path string, result *FlattenResu
 }
 
 // flattenResponsesNode flattens oneOf/anyOf/allOf in responses
-func flattenResponsesNode(node *yaml.Node, operation, path string, result *FlattenResult) bool {
+func flattenResponsesNode(node *yaml.Node, meta flattenMetadata, operation, path string, result *FlattenResult) bool {
 	if node == nil || node.Kind != yaml.MappingNode {
 		return false
 	}
@@ -576,7 +634,7 @@ func flattenResponsesNode(node *yaml.Node, operation, path string, result *Flatt
 						schema := getNodeValue(mediaNode, "schema")
 						if schema != nil {
 							schemaPath := fmt.Sprintf("%s -> %s -> %s", operation, responseCode, mediaType)
-							if flattenSchemaNode(schema, schemaPath, path, result) {
+							if flattenSchemaNode(schema, meta, schemaPath, path, result) {
 								changed = true
 							}
 						}
@@ -589,7 +647,8 @@ func flattenResponsesNode(node *yaml.Node, operation, path string, result *Flatt
 	return changed
 }
 
-// getSingleRefFromArray checks if an array contains only one $ref and returns it
+// getSingleRefFromArray checks if an array contains only one $ref, possibly with metadata next to
+// it, and returns it
 func getSingleRefFromArray(arrayNode *yaml.Node) string {
 	if arrayNode == nil || arrayNode.Kind != yaml.SequenceNode {
 		return ""
@@ -605,7 +664,7 @@ func getSingleRefFromArray(arrayNode *yaml.Node) string {
 		return ""
 	}
 
-	// Check if the element contains only a $ref
+	// Check if the element contains only a $ref and metadata
 	var refValue string
 	hasOnlyRef := true
 	for i := 0; i < len(element.Content); i += 2 {
@@ -614,8 +673,8 @@ func getSingleRefFromArray(arrayNode *yaml.Node) string {
 
 		if key == "$ref" {
 			refValue = value
-		} else {
-			// If there are other properties besides $ref, don't flatten
+		} else if !isFlattenMetadataKey(key) {
+			// If there are other keywords besides $ref, don't flatten
 			hasOnlyRef = false
 			break
 		}
@@ -657,21 +716,35 @@ func getSingleSchemaFromArray(arrayNode *yaml.Node) *yaml.Node {
 	return element
 }
 
-// flattenCompositionWithInlineSchema replaces oneOf/anyOf/allOf with the single inline schema
-func flattenCompositionWithInlineSchema(parentNode *yaml.Node, keyIndex int, singleSchema *yaml.Node, schemaName, compositionType, path string, result *FlattenResult) {
+// flattenCompositionWithInlineSchema replaces oneOf/anyOf/allOf with the single inline schema.
+// Keywords the parent already defines are not repeated; for metadata the rule picks the value.
+func flattenCompositionWithInlineSchema(parentNode *yaml.Node, keyIndex int, singleSchema *yaml.Node, meta flattenMetadata, schemaName, compositionType, path string, result *FlattenResult) {
 	// Remove the composition key and replace with the inline schema's properties
 	// We need to merge the single schema's content into the parent node
 
+	keyNode := parentNode.Content[keyIndex]
+
+	// Keep the keys the parent doesn't define yet; metadata it does define may be replaced
+	var added []*yaml.Node
+	for i := 0; i+1 < len(singleSchema.Content); i += 2 {
+		key, value := singleSchema.Content[i].Value, singleSchema.Content[i+1]
+		if existing := getNodeValue(parentNode, key); existing == nil || existing == parentNode.Content[keyIndex+1] {
+			added = append(added, singleSchema.Content[i], value)
+		} else if isFlattenMetadataKey(key) && meta.targetWins() {
+			setMappingValue(parentNode, key, value)
+		}
+	}
+
 	// First, remove the composition key-value pair
-	newContent := make([]*yaml.Node, 0, len(parentNode.Content)-2+len(singleSchema.Content))
+	newContent := make([]*yaml.Node, 0, len(parentNode.Content)-2+len(added))
 
 	// Copy content before the composition
 	for i := 0; i < keyIndex; i++ {
 		newContent = append(newContent, parentNode.Content[i])
 	}
 
-	// Add the single schema's content (all its key-value pairs)
-	newContent = append(newContent, singleSchema.Content...)
+	// Add the single schema's content
+	newContent = append(newContent, added...)
 
 	// Copy content after the composition
 	for i := keyIndex + 2; i < len(parentNode.Content); i++ {
@@ -682,7 +755,7 @@ func flattenCompositionWithInlineSchema(parentNode *yaml.Node, keyIndex int, sin
 	parentNode.Content = newContent
 
 	// Record the flattening
-	recordFlattening(result, path, fmt.Sprintf("%s.%s -> inline schema", schemaName, compositionType))
+	recordFlattening(result, path, keyNode, fmt.Sprintf("%s.%s -> inline schema", schemaName, compositionType))
 }
 
 // isEmptyComposition checks if a composition array is empty
@@ -695,6 +768,8 @@ func isEmptyComposition(arrayNode *yaml.Node) bool {
 
 // handleEmptyComposition removes empty composition from schema
 func handleEmptyComposition(parentNode *yaml.Node, keyIndex int, schemaName, compositionType, path string, result *FlattenResult) {
+	keyNode := parentNode.Content[keyIndex]
+
 	// Remove the empty composition key-value pair
 	newContent := make([]*yaml.Node, 0, len(parentNode.Content)-2)
 
@@ -712,9 +787,5 @@ func handleEmptyComposition(parentNode *yaml.Node, keyIndex int, schemaName, com
 	parentNode.Content = newContent
 
 	// Record the removal
-	flattenedPath := fmt.Sprintf("%s.%s -> removed (empty)", schemaName, compositionType)
-	if result.FlattenedRefs[path] == nil {
-		result.FlattenedRefs[path] = []string{}
-	}
-	result.FlattenedRefs[path] = append(result.FlattenedRefs[path], flattenedPath)
+	recordFlattening(result, path, keyNode, fmt.Sprintf("%s.%s -> removed (empty)", schemaName, compositionType))
 }
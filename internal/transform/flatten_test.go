@@ -2,6 +2,9 @@ package transform
 
 import (
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -841,3 +844,103 @@ components:
 		t.Error("properties should be preserved after flattening")
 	}
 }
+
+func TestFlatteningOptOut(t *testing.T) {
+	input := `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/PetAlias'
+components:
+  schemas:
+    Pet:
+      type: object
+    PetAlias:
+      $ref: '#/components/schemas/Pet'
+    Payment:
+      x-openmorph-no-flatten: true
+      oneOf:
+        - $ref: '#/components/schemas/Card'
+    Order:
+      type: object
+      properties:
+        payment:
+          x-openmorph-no-flatten: true
+          anyOf:
+            - $ref: '#/components/schemas/Card'
+        pet:
+          allOf:
+            - $ref: '#/components/schemas/Pet'
+    Wrapper:
+      allOf:
+        - $ref: '#/components/schemas/Pet'
+    Card:
+      type: object
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(input), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessFlatteningInDir(dir, FlattenOptions{FlattenResponses: true, Exclude: []string{"PetAlias", "Wrapper"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Components struct {
+			Schemas map[string]map[string]interface{} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas := doc.Components.Schemas
+	if schemas["PetAlias"]["$ref"] != "#/components/schemas/Pet" {
+		t.Errorf("expected the excluded alias to be kept, got %v", schemas["PetAlias"])
+	}
+	if !strings.Contains(string(data), "$ref: '#/components/schemas/PetAlias'") {
+		t.Errorf("expected the response to keep referencing the excluded alias, got:\n%s", data)
+	}
+	if _, ok := schemas["Payment"]["oneOf"]; !ok {
+		t.Errorf("expected the marked composition to be kept, got %v", schemas["Payment"])
+	}
+	if _, ok := schemas["Wrapper"]["allOf"]; !ok {
+		t.Errorf("expected the excluded composition to be kept, got %v", schemas["Wrapper"])
+	}
+	properties, _ := schemas["Order"]["properties"].(map[string]interface{})
+	if payment, _ := properties["payment"].(map[string]interface{}); payment["anyOf"] == nil {
+		t.Errorf("expected the marked inline schema to be kept, got %v", properties["payment"])
+	}
+	if pet, _ := properties["pet"].(map[string]interface{}); pet["$ref"] != "#/components/schemas/Pet" {
+		t.Errorf("expected the unmarked property to be flattened, got %v", properties["pet"])
+	}
+
+	want := []string{
+		"Payment (x-openmorph-no-flatten)",
+		"Order.properties.payment (x-openmorph-no-flatten)",
+		"PetAlias (flatten_exclude)",
+		"Wrapper (flatten_exclude)",
+	}
+	got := SkipMessages(result.SkippedSchemas[path])
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected skipped schemas %v", got)
+	}
+}
@@ -0,0 +1,281 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// allOf merge modes
+const (
+	AllOfMergeInline   = "inline"
+	AllOfMergePreserve = "preserve"
+)
+
+// allOfMergeKeys are the object keywords combined when an allOf is merged into one schema
+var allOfMergeKeys = []string{"type", "properties", "required", "additionalProperties", "minProperties", "maxProperties", "nullable"}
+
+// allOfMovedKeys are the sibling keywords moved next to the $ref in preserve mode
+var allOfMovedKeys = []string{"properties", "required", "additionalProperties", "minProperties", "maxProperties"}
+
+// allOfAnnotationKeys describe a schema rather than its shape; they stay on the merged schema and
+// are not copied from the referenced one
+var allOfAnnotationKeys = []string{"title", "description", "example", "examples", "externalDocs", "deprecated"}
+
+// ValidateFlattenAllOf checks the allOf merge mode
+func ValidateFlattenAllOf(allOf config.FlattenAllOf) error {
+	switch allOf.Mode {
+	case "", AllOfMergeInline, AllOfMergePreserve:
+		return nil
+	}
+	return fmt.Errorf("flatten_allof.mode must be %s or %s, got %q", AllOfMergeInline, AllOfMergePreserve, allOf.Mode)
+}
+
+// mergeAllOfSiblings merges every `allOf: [$ref]` that has sibling properties, or inline members
+// next to the reference, into one schema. Like the other flattening passes, component schemas are
+// left alone when paths are limited, and schemas that opt out of flattening are skipped.
+func mergeAllOfSiblings(root *yaml.Node, opts FlattenOptions, path string, result *FlattenResult) bool {
+	changed := false
+	if len(opts.Paths) == 0 {
+		if schemas := getNodeValue(getNodeValue(root, "components"), "schemas"); schemas != nil && schemas.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(schemas.Content); i += 2 {
+				schemaName, schemaNode := schemas.Content[i].Value, schemas.Content[i+1]
+				if optsOutOfFlattening(schemaName, schemaNode, opts.Exclude) {
+					continue
+				}
+				if mergeAllOfInNode(root, schemaNode, schemaName, opts.AllOf.Mode, path, result) {
+					changed = true
+				}
+			}
+		}
+	}
+
+	if paths := getNodeValue(scopeToPaths(root, opts.Paths), "paths"); paths != nil && paths.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			if mergeAllOfInNode(root, paths.Content[i+1], paths.Content[i].Value, opts.AllOf.Mode, path, result) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// mergeAllOfInNode merges the qualifying allOf compositions in node and below it, innermost first
+func mergeAllOfInNode(root, node *yaml.Node, name, mode, path string, result *FlattenResult) bool {
+	changed := false
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			if mergeAllOfInNode(root, item, fmt.Sprintf("%s[%d]", name, i), mode, path, result) {
+				changed = true
+			}
+		}
+	case yaml.MappingNode:
+		if hasNoFlattenMarker(node) {
+			return false
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if key == "example" || key == "examples" || strings.HasPrefix(key, "x-") {
+				continue
+			}
+			if mergeAllOfInNode(root, node.Content[i+1], name+"."+key, mode, path, result) {
+				changed = true
+			}
+		}
+		if mergeAllOf(root, node, name, mode, path, result) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// mergeAllOf merges the allOf of node when it holds exactly one $ref and the schema has sibling
+// object keywords or inline members next to the reference
+func mergeAllOf(root, node *yaml.Node, name, mode, path string, result *FlattenResult) bool {
+	allOf := getNodeValue(node, "allOf")
+	if allOf == nil || allOf.Kind != yaml.SequenceNode {
+		return false
+	}
+
+	ref := ""
+	var inline []*yaml.Node
+	for _, member := range allOf.Content {
+		switch {
+		case member.Kind != yaml.MappingNode:
+			return false
+		case getDirectRef(member) != "":
+			if ref != "" {
+				return false // several references are real multiple inheritance, not this pattern
+			}
+			ref = getDirectRef(member)
+		default:
+			inline = append(inline, member)
+		}
+	}
+	if ref == "" {
+		return false
+	}
+
+	siblings := &yaml.Node{Kind: yaml.MappingNode}
+	keys := allOfMergeKeys
+	if mode == AllOfMergePreserve {
+		keys = allOfMovedKeys
+	}
+	for _, key := range keys {
+		if value := getNodeValue(node, key); value != nil {
+			siblings.Content = append(siblings.Content, newScalarNode(key), value)
+		}
+	}
+	if len(siblings.Content) == 0 && (mode == AllOfMergePreserve || len(inline) == 0) {
+		return false // a lone $ref is flattened by the regular pass
+	}
+
+	allOfKey := mappingKeyNode(node, "allOf")
+	if mode == AllOfMergePreserve {
+		preserveAllOfRef(node, allOf, inline, siblings)
+		recordFlattening(result, path, allOfKey, fmt.Sprintf("%s.allOf -> sibling properties moved next to $ref: %s", name, ref))
+		return true
+	}
+
+	merged, reason := flatAllOfSchema(root, node, name, map[string]bool{})
+	if reason != "" {
+		recordFlattenSkip(result, path, name+".allOf", SkipUnmergeableAllOf, reason)
+		return false
+	}
+
+	content := make([]*yaml.Node, 0, len(node.Content)+len(merged.Content))
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch key := node.Content[i].Value; {
+		case key == "allOf":
+			content = append(content, merged.Content...)
+		case !contains(allOfMergeKeys, key):
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+	}
+	node.Content = content
+	recordFlattening(result, path, allOfKey, fmt.Sprintf("%s.allOf -> merged %s with the sibling properties", name, ref))
+	return true
+}
+
+// preserveAllOfRef moves the sibling object keywords of node into the inline member of its allOf,
+// adding one next to the $ref when there is none, so the reference itself is kept
+func preserveAllOfRef(node, allOf *yaml.Node, inline []*yaml.Node, siblings *yaml.Node) {
+	var member *yaml.Node
+	if len(inline) == 1 && mergeObjectKeywords(cloneNode(inline[0]), siblings, "") == "" {
+		member = inline[0]
+	} else {
+		member = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{newScalarNode("type"), newScalarNode("object")}}
+		allOf.Content = append(allOf.Content, member)
+	}
+	mergeObjectKeywords(member, siblings, "")
+	for i := 0; i+1 < len(siblings.Content); i += 2 {
+		removeMappingKey(node, siblings.Content[i].Value)
+	}
+}
+
+// flatAllOfSchema returns the object keywords of schema with its allOf members and, through
+// local references, the schemas they point at merged in, or the reason it cannot be merged
+func flatAllOfSchema(root, schema *yaml.Node, name string, seen map[string]bool) (*yaml.Node, string) {
+	if ref := getDirectRef(schema); ref != "" {
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, fmt.Sprintf("%s is not a local reference", ref)
+		}
+		if seen[ref] {
+			return nil, fmt.Sprintf("%s refers to itself", ref)
+		}
+		target := resolvePointer(root, strings.TrimPrefix(ref, "#"))
+		if target == nil || target.Kind != yaml.MappingNode {
+			return nil, fmt.Sprintf("%s does not resolve", ref)
+		}
+		seen[ref] = true
+		defer delete(seen, ref)
+		return flatAllOfSchema(root, target, ref, seen)
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode}
+	own := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		key, value := schema.Content[i].Value, schema.Content[i+1]
+		switch {
+		case key == "allOf" && value.Kind == yaml.SequenceNode:
+			for _, member := range value.Content {
+				if member.Kind != yaml.MappingNode {
+					return nil, fmt.Sprintf("%s has an allOf member that is not a schema", name)
+				}
+				part, reason := flatAllOfSchema(root, member, name, seen)
+				if reason != "" {
+					return nil, reason
+				}
+				if reason := mergeObjectKeywords(merged, part, name); reason != "" {
+					return nil, reason
+				}
+			}
+		case contains(allOfMergeKeys, key):
+			own.Content = append(own.Content, schema.Content[i], value)
+		case contains(allOfAnnotationKeys, key) || strings.HasPrefix(key, "x-"):
+		default:
+			return nil, fmt.Sprintf("%s uses %s", name, key)
+		}
+	}
+	if reason := mergeObjectKeywords(merged, own, name); reason != "" {
+		return nil, reason
+	}
+	return merged, ""
+}
+
+// mergeObjectKeywords adds the object keywords of src to dst: properties and required names are
+// combined, other keywords must agree. It returns the reason when src, named from, redefines one.
+func mergeObjectKeywords(dst, src *yaml.Node, from string) string {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i].Value, src.Content[i+1]
+		existing := getNodeValue(dst, key)
+		switch {
+		case existing == nil:
+			setMappingValue(dst, key, cloneNode(value))
+		case key == "properties" && existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				property := value.Content[j].Value
+				if current := getNodeValue(existing, property); current != nil {
+					if componentFingerprint(current, nil) != componentFingerprint(value.Content[j+1], nil) {
+						return fmt.Sprintf("%s redefines property %s", from, property)
+					}
+					continue
+				}
+				existing.Content = append(existing.Content, cloneNode(value.Content[j]), cloneNode(value.Content[j+1]))
+			}
+		case key == "required" && existing.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode:
+			for _, item := range value.Content {
+				if !sequenceContainsScalar(existing, item.Value) {
+					existing.Content = append(existing.Content, cloneNode(item))
+				}
+			}
+		case componentFingerprint(existing, nil) != componentFingerprint(value, nil):
+			return fmt.Sprintf("%s redefines %s", from, key)
+		}
+	}
+	return ""
+}
+
+// sequenceContainsScalar reports whether a sequence node holds a scalar with the given value
+func sequenceContainsScalar(sequence *yaml.Node, value string) bool {
+	for _, item := range sequence.Content {
+		if item.Kind == yaml.ScalarNode && item.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// mappingKeyNode returns the key node of key in a mapping node
+func mappingKeyNode(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package transform
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const allOfSiblingsSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Dog'
+components:
+  schemas:
+    Pet:
+      type: object
+      description: A pet
+      required: [id]
+      properties:
+        id:
+          type: string
+    Dog:
+      description: A dog
+      allOf:
+        - $ref: '#/components/schemas/Pet'
+      required: [breed]
+      properties:
+        breed:
+          type: string
+    Cat:
+      allOf:
+        - $ref: '#/components/schemas/Pet'
+        - type: object
+          properties:
+            id:
+              type: integer
+`
+
+func flattenAllOfSpec(t *testing.T, mode string) (*FlattenResult, string, map[string]map[string]interface{}) {
+	t.Helper()
+	dir, path := writeTestSpec(t, allOfSiblingsSpec)
+
+	result, err := ProcessFlatteningInDir(dir, FlattenOptions{
+		FlattenResponses: true,
+		AllOf:            config.FlattenAllOf{Enabled: true, Mode: mode},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Components struct {
+			Schemas map[string]map[string]interface{} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return result, path, doc.Components.Schemas
+}
+
+func TestFlattenAllOfInline(t *testing.T) {
+	result, path, schemas := flattenAllOfSpec(t, AllOfMergeInline)
+
+	dog := schemas["Dog"]
+	if _, ok := dog["allOf"]; ok {
+		t.Fatalf("expected the allOf to be merged, got %v", dog)
+	}
+	if dog["type"] != "object" || dog["description"] != "A dog" {
+		t.Errorf("expected the base type and the own description, got %v", dog)
+	}
+	properties, _ := dog["properties"].(map[string]interface{})
+	if _, ok := properties["id"]; !ok || properties["breed"] == nil {
+		t.Errorf("expected the inherited and the sibling properties, got %v", properties)
+	}
+	if required, _ := dog["required"].([]interface{}); len(required) != 2 || required[0] != "id" || required[1] != "breed" {
+		t.Errorf("expected the required names to be combined, got %v", dog["required"])
+	}
+	if _, ok := schemas["Pet"]; !ok {
+		t.Error("expected Pet to be kept while Cat still references it")
+	}
+
+	// Cat redefines id with another type, so merging would lose a constraint
+	if _, ok := schemas["Cat"]["allOf"]; !ok {
+		t.Errorf("expected the conflicting allOf to be kept, got %v", schemas["Cat"])
+	}
+	if got := strings.Join(SkipMessages(result.SkippedSchemas[path]), "\n"); got != "Cat.allOf (Cat redefines property id)" {
+		t.Errorf("unexpected skipped schemas %q", got)
+	}
+	if got := strings.Join(result.FlattenedRefs[path], "\n"); !strings.Contains(got, "Dog.allOf -> merged #/components/schemas/Pet with the sibling properties") {
+		t.Errorf("unexpected flattened refs %q", got)
+	}
+}
+
+func TestFlattenAllOfPreserve(t *testing.T) {
+	_, _, schemas := flattenAllOfSpec(t, AllOfMergePreserve)
+
+	dog := schemas["Dog"]
+	if _, ok := dog["properties"]; ok {
+		t.Errorf("expected the sibling properties to move into the allOf, got %v", dog)
+	}
+	allOf, _ := dog["allOf"].([]interface{})
+	if len(allOf) != 2 {
+		t.Fatalf("expected the $ref and one inline member, got %v", dog["allOf"])
+	}
+	if ref, _ := allOf[0].(map[string]interface{}); ref["$ref"] != "#/components/schemas/Pet" {
+		t.Errorf("expected the reference to be kept, got %v", allOf[0])
+	}
+	member, _ := allOf[1].(map[string]interface{})
+	if properties, _ := member["properties"].(map[string]interface{}); member["type"] != "object" || properties["breed"] == nil {
+		t.Errorf("unexpected inline member %v", member)
+	}
+	if required, _ := member["required"].([]interface{}); len(required) != 1 || required[0] != "breed" {
+		t.Errorf("expected the required names to move with the properties, got %v", member["required"])
+	}
+}
+
+func TestValidateFlattenAllOf(t *testing.T) {
+	for _, mode := range []string{"", AllOfMergeInline, AllOfMergePreserve} {
+		if err := ValidateFlattenAllOf(config.FlattenAllOf{Mode: mode}); err != nil {
+			t.Errorf("unexpected error for %q: %v", mode, err)
+		}
+	}
+	if err := ValidateFlattenAllOf(config.FlattenAllOf{Mode: "resolve"}); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
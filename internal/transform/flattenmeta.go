@@ -0,0 +1,114 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Metadata precedence when a single-member composition is collapsed
+const (
+	FlattenMetadataWrapper = "wrapper"
+	FlattenMetadataTarget  = "target"
+)
+
+// flattenMetadataKeys describe a schema rather than its shape, so the wrapper and the collapsed
+// member may both carry them; extensions (x-*) are treated the same way
+var flattenMetadataKeys = []string{"title", "description", "deprecated", "example", "examples", "externalDocs", "readOnly", "writeOnly"}
+
+// flattenMetadata decides whose metadata wins when a composition is collapsed into its wrapper
+type flattenMetadata struct {
+	root *yaml.Node // document the collapsed $refs are resolved against
+	rule string     // FlattenMetadataWrapper or FlattenMetadataTarget
+}
+
+// ValidateFlattenMetadata checks the metadata precedence rule
+func ValidateFlattenMetadata(rule string) error {
+	switch rule {
+	case "", FlattenMetadataWrapper, FlattenMetadataTarget:
+		return nil
+	}
+	return fmt.Errorf("flatten_metadata must be %s or %s, got %q", FlattenMetadataWrapper, FlattenMetadataTarget, rule)
+}
+
+// isFlattenMetadataKey reports whether a schema keyword is metadata
+func isFlattenMetadataKey(key string) bool {
+	return contains(flattenMetadataKeys, key) || strings.HasPrefix(key, "x-")
+}
+
+// targetWins reports whether the collapsed member's metadata replaces the wrapper's
+func (m flattenMetadata) targetWins() bool {
+	return m.rule == FlattenMetadataTarget
+}
+
+// collapseToRef replaces the composition at keyIndex with the member's $ref and merges the
+// member's metadata into the wrapper. When the target wins, the wrapper also drops the metadata
+// the referenced schema defines itself, so the schema's own values show through. It returns the
+// index of the last key it placed after the $ref.
+func (m flattenMetadata) collapseToRef(parentNode *yaml.Node, keyIndex int, member *yaml.Node, ref string) int {
+	parentNode.Content[keyIndex] = newScalarNode("$ref")
+	parentNode.Content[keyIndex+1] = newScalarNode(ref)
+
+	var added []*yaml.Node
+	for i := 0; i+1 < len(member.Content); i += 2 {
+		key, value := member.Content[i].Value, member.Content[i+1]
+		if key == "$ref" {
+			continue
+		}
+		if existing := getNodeValue(parentNode, key); existing != nil {
+			if m.targetWins() {
+				setMappingValue(parentNode, key, value)
+			}
+			continue
+		}
+		added = append(added, member.Content[i], value)
+	}
+
+	if m.targetWins() {
+		if pointer, ok := strings.CutPrefix(ref, "#"); ok {
+			if target := resolvePointer(m.root, pointer); target != nil && target.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(target.Content); i += 2 {
+					key := target.Content[i].Value
+					if isFlattenMetadataKey(key) && getNodeValue(member, key) == nil {
+						removeMappingKey(parentNode, key)
+					}
+				}
+			}
+		}
+	}
+
+	refIndex := 0
+	for i := 0; i < len(parentNode.Content); i += 2 {
+		if parentNode.Content[i].Value == "$ref" {
+			refIndex = i
+			break
+		}
+	}
+	content := make([]*yaml.Node, 0, len(parentNode.Content)+len(added))
+	content = append(content, parentNode.Content[:refIndex+2]...)
+	content = append(content, added...)
+	content = append(content, parentNode.Content[refIndex+2:]...)
+	parentNode.Content = content
+	return refIndex + len(added)
+}
+
+// inlineConflict returns the first non-metadata keyword the wrapper and the inline member both
+// define with different values, which would make collapsing the member change the schema
+func inlineConflict(parentNode *yaml.Node, keyIndex int, member *yaml.Node) string {
+	for i := 0; i+1 < len(member.Content); i += 2 {
+		key := member.Content[i].Value
+		if isFlattenMetadataKey(key) {
+			continue
+		}
+		for j := 0; j+1 < len(parentNode.Content); j += 2 {
+			if j == keyIndex || parentNode.Content[j].Value != key {
+				continue
+			}
+			if componentFingerprint(parentNode.Content[j+1], nil) != componentFingerprint(member.Content[i+1], nil) {
+				return key
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package transform
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const flattenMetadataSpec = `openapi: 3.1.0
+info:
+  title: Test API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      title: Pet
+      description: Any pet
+    Wrapped:
+      description: The wrapper
+      deprecated: true
+      oneOf:
+        - $ref: '#/components/schemas/Pet'
+    Annotated:
+      description: The wrapper
+      anyOf:
+        - $ref: '#/components/schemas/Pet'
+          description: The member
+          x-internal: true
+    Inline:
+      title: Wrapper title
+      type: object
+      oneOf:
+        - type: object
+          title: Member title
+          description: Member description
+    Conflicting:
+      type: object
+      oneOf:
+        - type: string
+`
+
+func flattenMetadataSchemas(t *testing.T, rule string) (*FlattenResult, string, map[string]map[string]interface{}) {
+	t.Helper()
+	dir, path := writeTestSpec(t, flattenMetadataSpec)
+
+	result, err := ProcessFlatteningInDir(dir, FlattenOptions{FlattenResponses: true, Metadata: rule})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Components struct {
+			Schemas map[string]map[string]interface{} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return result, path, doc.Components.Schemas
+}
+
+func TestFlattenMetadata(t *testing.T) {
+	petRef := "#/components/schemas/Pet"
+	tests := []struct {
+		rule string
+		want map[string]map[string]interface{}
+	}{
+		{
+			rule: FlattenMetadataWrapper,
+			want: map[string]map[string]interface{}{
+				"Wrapped":   {"$ref": petRef, "description": "The wrapper", "deprecated": true},
+				"Annotated": {"$ref": petRef, "description": "The wrapper", "x-internal": true},
+				"Inline":    {"title": "Wrapper title", "type": "object", "description": "Member description"},
+			},
+		},
+		{
+			rule: FlattenMetadataTarget,
+			want: map[string]map[string]interface{}{
+				"Wrapped":   {"$ref": petRef, "deprecated": true},
+				"Annotated": {"$ref": petRef, "description": "The member", "x-internal": true},
+				"Inline":    {"title": "Member title", "type": "object", "description": "Member description"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			_, _, schemas := flattenMetadataSchemas(t, tt.rule)
+			for name, want := range tt.want {
+				if !reflect.DeepEqual(schemas[name], want) {
+					t.Errorf("%s: expected %v, got %v", name, want, schemas[name])
+				}
+			}
+		})
+	}
+}
+
+func TestFlattenMetadata_KeepsConflictingInlineMember(t *testing.T) {
+	result, path, schemas := flattenMetadataSchemas(t, "")
+	if _, ok := schemas["Conflicting"]["oneOf"]; !ok {
+		t.Errorf("expected the conflicting member to stay in the oneOf, got %v", schemas["Conflicting"])
+	}
+	if got := strings.Join(SkipMessages(result.SkippedSchemas[path]), "\n"); got != "Conflicting (oneOf member redefines type)" {
+		t.Errorf("unexpected skipped schemas %q", got)
+	}
+}
+
+func TestValidateFlattenMetadata(t *testing.T) {
+	for _, rule := range []string{"", FlattenMetadataWrapper, FlattenMetadataTarget} {
+		if err := ValidateFlattenMetadata(rule); err != nil {
+			t.Errorf("%q: unexpected error %v", rule, err)
+		}
+	}
+	if err := ValidateFlattenMetadata("member"); err == nil {
+		t.Error("expected an unknown rule to be rejected")
+	}
+}
@@ -0,0 +1,12 @@
+package transform_test
+
+import (
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/goldentest"
+)
+
+// TestGolden runs the pipeline on every case under testdata; see the goldentest package
+func TestGolden(t *testing.T) {
+	goldentest.Run(t, "testdata")
+}
@@ -0,0 +1,266 @@
+package transform
+
+import (
+	"math"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Health factors, in the order the breakdown lists them
+const (
+	HealthMissingDescriptions    = "missing_descriptions"
+	HealthUnusedComponents       = "unused_components"
+	HealthDuplicateSchemas       = "duplicate_schemas"
+	HealthInconsistentPagination = "inconsistent_pagination"
+	HealthDeepComposition        = "deep_composition"
+)
+
+// healthWeights are the points each factor deducts at most, together 100
+var healthWeights = []struct {
+	name   string
+	weight int
+}{
+	{HealthMissingDescriptions, 25},
+	{HealthUnusedComponents, 20},
+	{HealthDuplicateSchemas, 20},
+	{HealthInconsistentPagination, 20},
+	{HealthDeepComposition, 15},
+}
+
+// maxHealthyCompositionDepth is the deepest oneOf/anyOf/allOf nesting a schema has without counting
+// against the health score
+const maxHealthyCompositionDepth = 2
+
+// healthFingerprintIgnoreKeys are the keys left out when looking for duplicate schemas, so that
+// schemas only differing in their documentation count as duplicates
+var healthFingerprintIgnoreKeys = map[string]bool{"description": true, "title": true, "example": true, "examples": true}
+
+// unusedComponentSections are the component sections referenced through $ref. Security schemes are
+// referenced by name and never count as unused.
+var unusedComponentSections = []string{"schemas", "responses", "parameters", "examples", "requestBodies", "headers", "links", "callbacks"}
+
+// SpecHealth is a score from 0 to 100 summarizing the debt of a spec, higher is healthier. Each
+// factor deducts its weight times the share of the items it checks that have an issue, so scores
+// stay comparable as a spec grows.
+type SpecHealth struct {
+	Score     int            `json:"score"`
+	Breakdown []HealthFactor `json:"breakdown"`
+}
+
+// HealthFactor is one factor of a SpecHealth
+type HealthFactor struct {
+	Name    string `json:"name"`
+	Issues  int    `json:"issues"`  // items counted against the score
+	Checked int    `json:"checked"` // items the factor inspects
+	Weight  int    `json:"weight"`  // most points the factor deducts
+	Penalty int    `json:"penalty"` // points deducted
+}
+
+// newSpecHealth creates a SpecHealth without issues
+func newSpecHealth() SpecHealth {
+	health := SpecHealth{Score: 100}
+	for _, factor := range healthWeights {
+		health.Breakdown = append(health.Breakdown, HealthFactor{Name: factor.name, Weight: factor.weight})
+	}
+	return health
+}
+
+// record sets the counts of a factor
+func (h *SpecHealth) record(name string, issues, checked int) {
+	for i := range h.Breakdown {
+		if h.Breakdown[i].Name == name {
+			h.Breakdown[i].Issues = issues
+			h.Breakdown[i].Checked = checked
+		}
+	}
+}
+
+// add adds the counts of other to h
+func (h *SpecHealth) add(other SpecHealth) {
+	for i := range h.Breakdown {
+		h.Breakdown[i].Issues += other.Breakdown[i].Issues
+		h.Breakdown[i].Checked += other.Breakdown[i].Checked
+	}
+}
+
+// score computes the penalties and the score from the counts
+func (h *SpecHealth) score() {
+	h.Score = 100
+	for i := range h.Breakdown {
+		factor := &h.Breakdown[i]
+		factor.Penalty = 0
+		if factor.Checked > 0 {
+			factor.Penalty = int(math.Round(float64(factor.Weight*factor.Issues) / float64(factor.Checked)))
+		}
+		h.Score -= factor.Penalty
+	}
+}
+
+// documentHealth scores one document. operationStrategies holds the pagination strategies of each
+// operation, in document order.
+func documentHealth(root *yaml.Node, operationStrategies []map[string]bool) SpecHealth {
+	health := newSpecHealth()
+
+	schemas := getNodeValue(getNodeValue(root, "components"), "schemas")
+	if getStringValue(root, "swagger") != "" {
+		schemas = getNodeValue(root, "definitions")
+	}
+
+	issues, checked := countMissingDescriptions(root, schemas)
+	health.record(HealthMissingDescriptions, issues, checked)
+	issues, checked = countUnusedComponents(root)
+	health.record(HealthUnusedComponents, issues, checked)
+	issues, checked = countDuplicateSchemas(schemas)
+	health.record(HealthDuplicateSchemas, issues, checked)
+	issues, checked = countInconsistentPagination(operationStrategies)
+	health.record(HealthInconsistentPagination, issues, checked)
+	issues, checked = countDeepCompositions(schemas)
+	health.record(HealthDeepComposition, issues, checked)
+
+	health.score()
+	return health
+}
+
+// countMissingDescriptions counts the operations without a summary or description, and the
+// parameters and component schemas without a description. References are not counted.
+func countMissingDescriptions(root, schemas *yaml.Node) (int, int) {
+	issues, checked := 0, 0
+	check := func(node *yaml.Node, keys ...string) {
+		if node == nil || node.Kind != yaml.MappingNode || getNodeValue(node, "$ref") != nil {
+			return
+		}
+		checked++
+		for _, key := range keys {
+			if getStringValue(node, key) != "" {
+				return
+			}
+		}
+		issues++
+	}
+	checkParameters := func(params *yaml.Node) {
+		if params != nil && params.Kind == yaml.SequenceNode {
+			for _, param := range params.Content {
+				check(param, "description")
+			}
+		}
+	}
+
+	if paths := getNodeValue(root, "paths"); paths != nil && paths.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			pathItem := paths.Content[i+1]
+			if pathItem.Kind != yaml.MappingNode {
+				continue
+			}
+			checkParameters(getNodeValue(pathItem, "parameters"))
+			for j := 0; j+1 < len(pathItem.Content); j += 2 {
+				if isHTTPMethod(pathItem.Content[j].Value) {
+					check(pathItem.Content[j+1], "summary", "description")
+					checkParameters(getNodeValue(pathItem.Content[j+1], "parameters"))
+				}
+			}
+		}
+	}
+
+	parameters := getNodeValue(getNodeValue(root, "components"), "parameters")
+	if getStringValue(root, "swagger") != "" {
+		parameters = getNodeValue(root, "parameters")
+	}
+	for _, section := range []*yaml.Node{parameters, schemas} {
+		if section != nil && section.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(section.Content); i += 2 {
+				check(section.Content[i+1], "description")
+			}
+		}
+	}
+	return issues, checked
+}
+
+// countUnusedComponents counts the components no $ref outside the components section reaches,
+// directly or through other components. Swagger 2.0 documents are not checked.
+func countUnusedComponents(root *yaml.Node) (int, int) {
+	components := getNodeValue(root, "components")
+	if components == nil || components.Kind != yaml.MappingNode {
+		return 0, 0
+	}
+
+	reachable := componentClosure(root)
+	issues, checked := 0, 0
+	for _, section := range unusedComponentSections {
+		sectionNode := getNodeValue(components, section)
+		if sectionNode == nil || sectionNode.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(sectionNode.Content); i += 2 {
+			checked++
+			if !reachable[componentKey(section, sectionNode.Content[i].Value)] {
+				issues++
+			}
+		}
+	}
+	return issues, checked
+}
+
+// countDuplicateSchemas counts the component schemas structurally identical to an earlier one,
+// ignoring their documentation
+func countDuplicateSchemas(schemas *yaml.Node) (int, int) {
+	if schemas == nil || schemas.Kind != yaml.MappingNode {
+		return 0, 0
+	}
+
+	seen := make(map[string]bool)
+	issues := 0
+	for i := 0; i+1 < len(schemas.Content); i += 2 {
+		fingerprint := componentFingerprint(schemas.Content[i+1], healthFingerprintIgnoreKeys)
+		if seen[fingerprint] {
+			issues++
+		}
+		seen[fingerprint] = true
+	}
+	return issues, len(schemas.Content) / 2
+}
+
+// countInconsistentPagination counts the paginated operations that match several strategies, or a
+// single one other than the strategy most operations use
+func countInconsistentPagination(operationStrategies []map[string]bool) (int, int) {
+	usage := make(map[string]int)
+	checked := 0
+	for _, strategies := range operationStrategies {
+		if len(strategies) == 0 {
+			continue
+		}
+		checked++
+		if len(strategies) == 1 {
+			for strategy := range strategies {
+				usage[strategy]++
+			}
+		}
+	}
+
+	dominant := ""
+	if sorted := SortedCounts(usage); len(sorted) > 0 {
+		dominant = sorted[0]
+	}
+	issues := 0
+	for _, strategies := range operationStrategies {
+		if len(strategies) > 1 || (len(strategies) == 1 && !strategies[dominant]) {
+			issues++
+		}
+	}
+	return issues, checked
+}
+
+// countDeepCompositions counts the component schemas nesting oneOf/anyOf/allOf deeper than
+// maxHealthyCompositionDepth
+func countDeepCompositions(schemas *yaml.Node) (int, int) {
+	if schemas == nil || schemas.Kind != yaml.MappingNode {
+		return 0, 0
+	}
+
+	issues := 0
+	for i := 0; i+1 < len(schemas.Content); i += 2 {
+		if compositionDepth(schemas.Content[i+1]) > maxHealthyCompositionDepth {
+			issues++
+		}
+	}
+	return issues, len(schemas.Content) / 2
+}
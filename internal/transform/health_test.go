@@ -0,0 +1,102 @@
+package transform
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDocumentHealth(t *testing.T) {
+	spec := `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      summary: List users
+      parameters:
+        - name: page
+          in: query
+          description: Page number
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+  /teams:
+    get:
+      parameters:
+        - name: page
+          in: query
+          description: Page number
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+  /events:
+    get:
+      description: List events
+      parameters:
+        - $ref: "#/components/parameters/Cursor"
+      responses:
+        "200":
+          description: OK
+components:
+  parameters:
+    Cursor:
+      name: cursor
+      in: query
+      description: Opaque cursor
+      schema:
+        type: string
+  schemas:
+    User:
+      description: A user
+      type: object
+    Member:
+      type: object
+    Nested:
+      description: Deeply composed
+      oneOf:
+        - anyOf:
+            - allOf:
+                - type: string
+`
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(spec), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := documentStats("api.yaml", getRootNode(&doc))
+	want := map[string][2]int{
+		HealthMissingDescriptions:    {2, 9}, // GET /teams and Member
+		HealthUnusedComponents:       {2, 4}, // Member and Nested
+		HealthDuplicateSchemas:       {1, 3}, // Member repeats User without its description
+		HealthInconsistentPagination: {1, 3}, // cursor on /events, page elsewhere
+		HealthDeepComposition:        {1, 3}, // Nested
+	}
+	penalties := 0
+	for _, factor := range stats.Health.Breakdown {
+		if got := [2]int{factor.Issues, factor.Checked}; got != want[factor.Name] {
+			t.Errorf("%s: expected %v issues of checked, got %v", factor.Name, want[factor.Name], got)
+		}
+		penalties += factor.Penalty
+	}
+	if stats.Health.Score != 100-penalties || stats.Health.Score != 65 {
+		t.Errorf("expected a score of 65, got %+v", stats.Health)
+	}
+
+	// Totals are scored from the summed counts
+	total := newSpecStats("")
+	total.add(stats)
+	total.add(newSpecStats("empty.yaml"))
+	if total.Health.Score != stats.Health.Score {
+		t.Errorf("expected the total to keep the score %d, got %d", stats.Health.Score, total.Health.Score)
+	}
+}
@@ -0,0 +1,301 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// InjectionOptions extends the regular Options with parameter injection settings
+type InjectionOptions struct {
+	Options
+	ParameterInjection config.ParameterInjection
+}
+
+// InjectionResult represents the result of parameter injection
+type InjectionResult struct {
+	Changed           bool
+	ProcessedFiles    []string
+	InjectedParams    map[string][]string // file -> list of operations and the parameters added to them
+	CreatedComponents map[string][]string // file -> list of parameter components created
+	Locations         []ChangeLocation    // source positions of the operations that gained parameters
+}
+
+// createInjectionResult creates a new InjectionResult with initialized maps
+func createInjectionResult() *InjectionResult {
+	return &InjectionResult{
+		ProcessedFiles:    []string{},
+		InjectedParams:    make(map[string][]string),
+		CreatedComponents: make(map[string][]string),
+	}
+}
+
+// setInjectionProcessedFiles sets the processed files for an InjectionResult
+func setInjectionProcessedFiles(result *InjectionResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setInjectionChanged sets the changed flag for an InjectionResult
+func setInjectionChanged(result *InjectionResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessParameterInjectionInDir adds the configured parameters to matching operations in all
+// OpenAPI files in a directory
+func ProcessParameterInjectionInDir(dir string, opts InjectionOptions) (*InjectionResult, error) {
+	if err := ValidateParameterInjection(opts.ParameterInjection); err != nil {
+		return createInjectionResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepParameterInjection,
+		opts.Options,
+		opts.ParameterInjection.Enabled,
+		len(opts.ParameterInjection.Parameters) == 0,
+		createInjectionResult,
+		func(path string, result *InjectionResult) (bool, error) {
+			return processParameterInjectionInFile(path, opts, result)
+		},
+		setInjectionProcessedFiles,
+		setInjectionChanged,
+	)
+}
+
+// ValidateParameterInjection checks that every injected parameter names its component and
+// defines a name and location
+func ValidateParameterInjection(injection config.ParameterInjection) error {
+	seen := make(map[string]bool)
+	for i, param := range injection.Parameters {
+		if param.Component == "" {
+			return fmt.Errorf("parameter_injection.parameters[%d]: component is required", i)
+		}
+		if seen[param.Component] {
+			return fmt.Errorf("parameter_injection.parameters[%d]: component %q is injected twice", i, param.Component)
+		}
+		seen[param.Component] = true
+		if name, _ := param.Definition["name"].(string); name == "" {
+			return fmt.Errorf("parameter_injection.parameters[%d]: definition.name is required", i)
+		}
+		in, _ := param.Definition["in"].(string)
+		switch in {
+		case "query", "header", "path", "cookie":
+		default:
+			return fmt.Errorf("parameter_injection.parameters[%d]: definition.in must be query, header, path or cookie, got %q", i, in)
+		}
+		if value, ok := param.Definition["required"]; ok {
+			required, isBool := value.(bool)
+			if !isBool {
+				return fmt.Errorf("parameter_injection.parameters[%d]: definition.required must be true or false, got %v", i, value)
+			}
+			if in == "path" && !required {
+				return fmt.Errorf("parameter_injection.parameters[%d]: definition.required must be true for a path parameter", i)
+			}
+		}
+	}
+	return nil
+}
+
+// processParameterInjectionInFile adds the configured parameters to a single file
+func processParameterInjectionInFile(path string, opts InjectionOptions, result *InjectionResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	changed := false
+	for _, param := range opts.ParameterInjection.Parameters {
+		if injectParameter(root, param, path, result) {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// injectParameter adds a $ref to the parameter's component to every matching operation that does
+// not already declare a parameter with the same name and location. The component is created on
+// first use; an existing component of the same name is reused as is, and so is one of another
+// name declaring the same parameter. Path parameters are only added to paths with a {name}
+// segment for them.
+func injectParameter(root *yaml.Node, param config.InjectedParameter, path string, result *InjectionResult) bool {
+	paths := getNodeValue(root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return false
+	}
+
+	section, refPrefix := parameterSection(root)
+	component := param.Component
+	definition := getNodeValue(section, component)
+	if definition == nil {
+		definition = &yaml.Node{}
+		if err := definition.Encode(injectedDefinition(param.Definition)); err != nil {
+			return false
+		}
+		if existing := findParameterComponent(section, getStringValue(definition, "name"), getStringValue(definition, "in")); existing != "" {
+			component = existing
+			definition = getNodeValue(section, existing)
+		}
+	}
+	name, in := getStringValue(definition, "name"), getStringValue(definition, "in")
+	ref := refPrefix + component
+	via := ""
+	if component != param.Component {
+		via = ", via parameters." + component
+	}
+
+	when := condition.Condition{
+		Paths:      param.Condition.PathPatterns,
+		Methods:    param.Condition.HTTPMethods,
+		Tags:       param.Condition.Tags,
+		Extensions: param.Condition.Extensions,
+		Has:        param.Condition.HasExtension,
+		Missing:    param.Condition.MissingExtension,
+		Syntax:     condition.Regex,
+	}
+	changed := false
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+		if pathItem.Kind != yaml.MappingNode || !condition.MatchPath(pathName, when.Paths, when.Syntax) {
+			continue
+		}
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			method, operation := pathItem.Content[j], pathItem.Content[j+1]
+			if !isHTTPMethod(method.Value) || operation.Kind != yaml.MappingNode || !when.Matches(pathName, method.Value, operation) {
+				continue
+			}
+			if declaresParameter(root, getNodeValue(pathItem, "parameters"), name, in) ||
+				declaresParameter(root, getNodeValue(operation, "parameters"), name, in) {
+				continue
+			}
+			if in == "path" && !strings.Contains(pathName, "{"+name+"}") {
+				continue
+			}
+
+			if getNodeValue(section, component) == nil {
+				section = ensureParameterSection(root)
+				section.Content = append(section.Content, newScalarNode(component), definition)
+				result.CreatedComponents[path] = append(result.CreatedComponents[path], "parameters."+component)
+			}
+			appendParameterRef(operation, ref)
+			changed = true
+
+			entry := fmt.Sprintf("%s %s: %s (%s%s)", strings.ToUpper(method.Value), pathName, name, in, via)
+			result.InjectedParams[path] = append(result.InjectedParams[path], entry)
+			result.Locations = append(result.Locations, newChangeLocation(path, StepParameterInjection, method, entry))
+		}
+	}
+	return changed
+}
+
+// injectedDefinition returns the definition of an injected parameter with required set for a path
+// parameter, which OpenAPI requires
+func injectedDefinition(definition map[string]interface{}) map[string]interface{} {
+	if definition["in"] != "path" || definition["required"] != nil {
+		return definition
+	}
+	withRequired := make(map[string]interface{}, len(definition)+1)
+	for key, value := range definition {
+		withRequired[key] = value
+	}
+	withRequired["required"] = true
+	return withRequired
+}
+
+// findParameterComponent returns the name of the reusable parameter declaring the parameter with
+// the given name and location, or an empty string when there is none
+func findParameterComponent(section *yaml.Node, name, in string) string {
+	if section == nil || section.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(section.Content); i += 2 {
+		if getStringValue(section.Content[i+1], "name") == name && getStringValue(section.Content[i+1], "in") == in {
+			return section.Content[i].Value
+		}
+	}
+	return ""
+}
+
+// parameterSection returns the document's reusable parameters section, which may not exist yet,
+// and the $ref prefix of its entries: components/parameters for OpenAPI 3, parameters for Swagger 2
+func parameterSection(root *yaml.Node) (*yaml.Node, string) {
+	if getNodeValue(root, "swagger") != nil {
+		return getNodeValue(root, "parameters"), "#/parameters/"
+	}
+	return getNodeValue(getNodeValue(root, "components"), "parameters"), componentRef("parameters", "")
+}
+
+// ensureParameterSection returns the document's reusable parameters section, creating it if needed
+func ensureParameterSection(root *yaml.Node) *yaml.Node {
+	parent := root
+	if getNodeValue(root, "swagger") == nil {
+		parent = ensureMapping(root, "components")
+	}
+	return ensureMapping(parent, "parameters")
+}
+
+// ensureMapping returns the mapping under key, creating it if needed
+func ensureMapping(node *yaml.Node, key string) *yaml.Node {
+	if value := getNodeValue(node, key); value != nil && value.Kind == yaml.MappingNode {
+		return value
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return value
+		}
+	}
+	node.Content = append(node.Content, newScalarNode(key), value)
+	return value
+}
+
+// declaresParameter reports whether a parameters list declares a parameter with the given name
+// and location, directly or through a local $ref
+func declaresParameter(root, params *yaml.Node, name, in string) bool {
+	if params == nil || params.Kind != yaml.SequenceNode {
+		return false
+	}
+	for _, param := range params.Content {
+		if ref := getStringValue(param, "$ref"); ref != "" {
+			if !strings.HasPrefix(ref, "#/") {
+				continue
+			}
+			param = resolvePointer(root, strings.TrimPrefix(ref, "#"))
+		}
+		if param != nil && getStringValue(param, "name") == name && getStringValue(param, "in") == in {
+			return true
+		}
+	}
+	return false
+}
+
+// appendParameterRef adds a $ref parameter to the operation, creating its parameters list if needed
+func appendParameterRef(operation *yaml.Node, ref string) {
+	params := getNodeValue(operation, "parameters")
+	if params == nil || params.Kind != yaml.SequenceNode {
+		params = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		operation.Content = append(operation.Content, newScalarNode("parameters"), params)
+	}
+	params.Content = append(params.Content, &yaml.Node{
+		Kind:    yaml.MappingNode,
+		Tag:     "!!map",
+		Content: []*yaml.Node{newScalarNode("$ref"), newScalarNode(ref)},
+	})
+}
+
+func newScalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
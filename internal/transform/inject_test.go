@@ -0,0 +1,204 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const injectionTestSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /tenants/{id}/users:
+    get:
+      tags: [users]
+      parameters:
+        - name: X-Request-Id
+          in: header
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+    post:
+      tags: [users]
+      responses:
+        "201":
+          description: Created
+  /health:
+    get:
+      responses:
+        "200":
+          description: OK
+`
+
+func injectionTestConfig() config.ParameterInjection {
+	return config.ParameterInjection{
+		Enabled: true,
+		Parameters: []config.InjectedParameter{
+			{
+				Component:  "RequestId",
+				Definition: map[string]interface{}{"name": "X-Request-Id", "in": "header", "schema": map[string]interface{}{"type": "string"}},
+			},
+			{
+				Component:  "TenantId",
+				Definition: map[string]interface{}{"name": "tenant_id", "in": "query", "required": true},
+				Condition:  config.InjectionCondition{PathPatterns: []string{"^/tenants/"}, HTTPMethods: []string{"get"}},
+			},
+		},
+	}
+}
+
+func TestProcessParameterInjectionInDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(injectionTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessParameterInjectionInDir(dir, InjectionOptions{ParameterInjection: injectionTestConfig()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed {
+		t.Fatal("expected parameters to be injected")
+	}
+
+	want := []string{
+		"POST /tenants/{id}/users: X-Request-Id (header)",
+		"GET /health: X-Request-Id (header)",
+		"GET /tenants/{id}/users: tenant_id (query)",
+	}
+	if got := result.InjectedParams[path]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected injected params %v, got %v", want, got)
+	}
+	if got := result.CreatedComponents[path]; len(got) != 2 {
+		t.Errorf("expected both components to be created, got %v", got)
+	}
+	if len(result.Locations) != 3 || result.Locations[0].Line != 17 {
+		t.Errorf("expected locations at the operation keys, got %+v", result.Locations)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, s := range []string{"$ref: '#/components/parameters/RequestId'", "$ref: '#/components/parameters/TenantId'", "TenantId:\n"} {
+		if !strings.Contains(content, s) {
+			t.Errorf("expected output to contain %q, got:\n%s", s, content)
+		}
+	}
+
+	// A second run finds every parameter already declared
+	result, err = ProcessParameterInjectionInDir(dir, InjectionOptions{ParameterInjection: injectionTestConfig()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed {
+		t.Errorf("expected injection to be idempotent, got %v", result.InjectedParams)
+	}
+}
+
+func TestProcessParameterInjectionSwagger(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	spec := "swagger: \"2.0\"\npaths:\n  /users:\n    get:\n      responses:\n        \"200\":\n          description: OK\n"
+	if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	injection := injectionTestConfig()
+	injection.Parameters = injection.Parameters[:1]
+	if _, err := ProcessParameterInjectionInDir(dir, InjectionOptions{ParameterInjection: injection}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "$ref: '#/parameters/RequestId'") || strings.Contains(string(data), "components:") {
+		t.Errorf("expected a Swagger 2 parameters section, got:\n%s", data)
+	}
+}
+
+func TestProcessParameterInjectionReusesComponent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /tenants/{tenant_id}/users:
+    get:
+      responses:
+        "200":
+          description: OK
+  /health:
+    get:
+      responses:
+        "200":
+          description: OK
+components:
+  parameters:
+    RequestID:
+      name: X-Request-Id
+      in: header
+      schema:
+        type: string
+`
+	if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	injection := injectionTestConfig()
+	injection.Parameters = append(injection.Parameters[:1], config.InjectedParameter{
+		Component:  "TenantPath",
+		Definition: map[string]interface{}{"name": "tenant_id", "in": "path", "schema": map[string]interface{}{"type": "string"}},
+	})
+	result, err := ProcessParameterInjectionInDir(dir, InjectionOptions{ParameterInjection: injection})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"GET /tenants/{tenant_id}/users: X-Request-Id (header, via parameters.RequestID)",
+		"GET /health: X-Request-Id (header, via parameters.RequestID)",
+		"GET /tenants/{tenant_id}/users: tenant_id (path)",
+	}
+	if got := result.InjectedParams[path]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected injected params %v, got %v", want, got)
+	}
+	if got := result.CreatedComponents[path]; strings.Join(got, ",") != "parameters.TenantPath" {
+		t.Errorf("expected only the path parameter component to be created, got %v", got)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if strings.Contains(content, "RequestId:") || !strings.Contains(content, "$ref: '#/components/parameters/RequestID'") {
+		t.Errorf("expected the existing component to be referenced, got:\n%s", content)
+	}
+	if !strings.Contains(content, "TenantPath:\n            in: path\n            name: tenant_id\n            required: true") {
+		t.Errorf("expected the path parameter to be required, got:\n%s", content)
+	}
+}
+
+func TestValidateParameterInjection(t *testing.T) {
+	tests := []struct {
+		name  string
+		param config.InjectedParameter
+		want  string
+	}{
+		{"missing component", config.InjectedParameter{Definition: map[string]interface{}{"name": "a", "in": "query"}}, "component is required"},
+		{"missing name", config.InjectedParameter{Component: "A", Definition: map[string]interface{}{"in": "query"}}, "definition.name is required"},
+		{"bad location", config.InjectedParameter{Component: "A", Definition: map[string]interface{}{"name": "a", "in": "body"}}, "definition.in must be"},
+		{"required not a boolean", config.InjectedParameter{Component: "A", Definition: map[string]interface{}{"name": "a", "in": "query", "required": "yes"}}, "definition.required must be true or false"},
+		{"optional path parameter", config.InjectedParameter{Component: "A", Definition: map[string]interface{}{"name": "a", "in": "path", "required": false}}, "must be true for a path parameter"},
+	}
+	for _, tt := range tests {
+		err := ValidateParameterInjection(config.ParameterInjection{Parameters: []config.InjectedParameter{tt.param}})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
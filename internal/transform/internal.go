@@ -0,0 +1,420 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const defaultInternalExtension = "x-internal"
+
+// InternalOptions extends the regular Options with internal-content stripping settings
+type InternalOptions struct {
+	Options
+	StripInternal config.StripInternal
+	AsyncAPI      bool // also strip AsyncAPI documents
+}
+
+// InternalResult represents the result of internal-content stripping
+type InternalResult struct {
+	Changed           bool
+	ProcessedFiles    []string
+	RemovedItems      map[string][]string // file -> list of removed internal items
+	PrunedComponents  map[string][]string // file -> list of components no longer referenced after stripping
+	SecondaryRemovals map[string][]string // file -> security requirements, links, callbacks and servers left dangling by stripping
+}
+
+// createInternalResult creates a new InternalResult with initialized maps
+func createInternalResult() *InternalResult {
+	return &InternalResult{
+		ProcessedFiles:    []string{},
+		RemovedItems:      make(map[string][]string),
+		PrunedComponents:  make(map[string][]string),
+		SecondaryRemovals: make(map[string][]string),
+	}
+}
+
+// setInternalProcessedFiles sets the processed files for an InternalResult
+func setInternalProcessedFiles(result *InternalResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setInternalChanged sets the changed flag for an InternalResult
+func setInternalChanged(result *InternalResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessStripInternalInDir removes internal-only content from all OpenAPI files in a directory
+func ProcessStripInternalInDir(dir string, opts InternalOptions) (*InternalResult, error) {
+	return processTransformInDir(
+		dir,
+		StepStripInternal,
+		opts.Options,
+		opts.StripInternal.Enabled,
+		false,
+		createInternalResult,
+		func(path string, result *InternalResult) (bool, error) {
+			return processStripInternalInFile(path, opts, result)
+		},
+		setInternalProcessedFiles,
+		setInternalChanged,
+	)
+}
+
+// processStripInternalInFile removes internal-only content from a single file
+func processStripInternalInFile(path string, opts InternalOptions, result *InternalResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+
+	if !isOpenAPIDocument(root) && !(opts.AsyncAPI && isAsyncAPIDocument(root)) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	return processDocumentStripInternal(doc, root, path, opts, result)
+}
+
+// getInternalExtension returns the configured marker extension, defaulting to x-internal
+func getInternalExtension(stripInternal config.StripInternal) string {
+	if stripInternal.Extension == "" {
+		return defaultInternalExtension
+	}
+	return stripInternal.Extension
+}
+
+// internalStripper removes nodes marked with the internal extension and tracks what was removed
+type internalStripper struct {
+	root              *yaml.Node
+	marker            string
+	removedComponents map[string]bool // componentKeys of removed components, named as in the document
+	internalTags      map[string]bool // names of removed tag definitions
+	removedChannels   map[string]bool // names of removed AsyncAPI channels
+	removed           []string        // human-readable removed items
+}
+
+// processDocumentStripInternal strips internal content, then prunes components that were only
+// reachable through it and path items left without operations
+func processDocumentStripInternal(doc, root *yaml.Node, path string, opts InternalOptions, result *InternalResult) (bool, error) {
+	operationsBefore := collectOperationTargets(root)
+	reachableBefore := componentClosure(root)
+
+	s := &internalStripper{
+		root:              root,
+		marker:            getInternalExtension(opts.StripInternal),
+		removedComponents: make(map[string]bool),
+		internalTags:      make(map[string]bool),
+		removedChannels:   make(map[string]bool),
+	}
+
+	s.stripComponents()
+	s.stripTags(root)
+	for _, section := range []string{"paths", "webhooks"} {
+		if items := getNodeValue(root, section); items != nil && items.Kind == yaml.MappingNode {
+			s.stripPathItems(items, section == "webhooks")
+		}
+	}
+	s.stripChannels(root)
+	s.stripAsyncOperations(root)
+	s.stripComponentContents()
+
+	if len(s.removed) == 0 {
+		return false, nil
+	}
+
+	result.RemovedItems[path] = append(result.RemovedItems[path], s.removed...)
+	if secondary := removeDanglingCrossReferences(root, operationsBefore, s.removedSecuritySchemes()); len(secondary) > 0 {
+		result.SecondaryRemovals[path] = append(result.SecondaryRemovals[path], secondary...)
+	}
+	if pruned := pruneUnreachableComponents(root, reachableBefore); len(pruned) > 0 {
+		result.PrunedComponents[path] = append(result.PrunedComponents[path], pruned...)
+	}
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// isInternal reports whether a node is marked with the internal extension
+func (s *internalStripper) isInternal(node *yaml.Node) bool {
+	marker := getNodeValue(node, s.marker)
+	return marker != nil && marker.Kind == yaml.ScalarNode && strings.EqualFold(marker.Value, "true")
+}
+
+// isRemovedRef reports whether a node is a bare $ref to a removed component
+func (s *internalStripper) isRemovedRef(node *yaml.Node) bool {
+	if node == nil || node.Kind != yaml.MappingNode || len(node.Content) != 2 || node.Content[0].Value != "$ref" {
+		return false
+	}
+	section, name, ok := parseDocumentComponentRef(s.root, node.Content[1].Value)
+	return ok && s.removedComponents[componentKey(section, name)]
+}
+
+// removedSecuritySchemes returns the names of the removed security schemes
+func (s *internalStripper) removedSecuritySchemes() map[string]bool {
+	schemes := make(map[string]bool)
+	_, section, _ := componentSection(s.root, "securitySchemes")
+	for key := range s.removedComponents {
+		if keySection, name, _ := strings.Cut(key, "."); keySection == section {
+			schemes[name] = true
+		}
+	}
+	return schemes
+}
+
+// shouldRemove reports whether a node is internal or a reference to removed internal content
+func (s *internalStripper) shouldRemove(node *yaml.Node) bool {
+	return s.isInternal(node) || s.isRemovedRef(node)
+}
+
+// record records a removed item
+func (s *internalStripper) record(format string, args ...interface{}) {
+	s.removed = append(s.removed, fmt.Sprintf(format, args...))
+}
+
+// stripComponents removes internal components from every components section, or from the
+// Swagger 2.0 sections holding them
+func (s *internalStripper) stripComponents() {
+	for _, documentSection := range documentComponentSections(s.root) {
+		sectionNode, section, _ := componentSection(s.root, documentSection)
+		if sectionNode == nil || sectionNode.Kind != yaml.MappingNode {
+			continue
+		}
+
+		var kept []*yaml.Node
+		for j := 0; j < len(sectionNode.Content); j += 2 {
+			name := sectionNode.Content[j].Value
+			if s.isInternal(sectionNode.Content[j+1]) {
+				s.removedComponents[componentKey(section, name)] = true
+				s.record("component %s", componentKey(section, name))
+				continue
+			}
+			kept = append(kept, sectionNode.Content[j], sectionNode.Content[j+1])
+		}
+		sectionNode.Content = kept
+	}
+}
+
+// stripTags removes internal tag definitions
+func (s *internalStripper) stripTags(root *yaml.Node) {
+	tags := getNodeValue(root, "tags")
+	if tags == nil || tags.Kind != yaml.SequenceNode {
+		return
+	}
+
+	var kept []*yaml.Node
+	for _, tag := range tags.Content {
+		if s.isInternal(tag) {
+			name := getStringValue(tag, "name")
+			s.internalTags[name] = true
+			s.record("tag %s", name)
+			continue
+		}
+		kept = append(kept, tag)
+	}
+	if len(kept) == len(tags.Content) {
+		return
+	}
+	tags.Content = kept
+
+	if len(kept) == 0 {
+		removeMappingKey(root, "tags")
+	}
+}
+
+// stripPathItems removes internal path items and operations, dropping path items whose
+// operations were all removed, and strips nested internal content from what remains
+func (s *internalStripper) stripPathItems(items *yaml.Node, webhooks bool) {
+	kind := "path"
+	if webhooks {
+		kind = "webhook"
+	}
+
+	var kept []*yaml.Node
+	for i := 0; i < len(items.Content); i += 2 {
+		path := items.Content[i].Value
+		pathItem := items.Content[i+1]
+		if s.isInternal(pathItem) {
+			s.record("%s %s", kind, path)
+			continue
+		}
+		if pathItem.Kind != yaml.MappingNode {
+			kept = append(kept, items.Content[i], pathItem)
+			continue
+		}
+
+		var content []*yaml.Node
+		operations, removedOperations := 0, 0
+		for j := 0; j < len(pathItem.Content); j += 2 {
+			key := pathItem.Content[j].Value
+			value := pathItem.Content[j+1]
+			if !isHTTPMethod(key) {
+				s.stripNested(value, path)
+				content = append(content, pathItem.Content[j], value)
+				continue
+			}
+
+			context := strings.ToUpper(key) + " " + path
+			if s.isInternal(value) {
+				s.record("operation %s", context)
+				removedOperations++
+				continue
+			}
+			s.stripOperationTags(value)
+			s.stripNested(value, context)
+			operations++
+			content = append(content, pathItem.Content[j], value)
+		}
+		pathItem.Content = content
+
+		if operations == 0 && removedOperations > 0 {
+			s.record("empty %s %s", kind, path)
+			continue
+		}
+		kept = append(kept, items.Content[i], pathItem)
+	}
+	items.Content = kept
+}
+
+// stripOperationTags removes references to internal tags from an operation
+func (s *internalStripper) stripOperationTags(operation *yaml.Node) {
+	tags := getNodeValue(operation, "tags")
+	if tags == nil || tags.Kind != yaml.SequenceNode || len(s.internalTags) == 0 {
+		return
+	}
+
+	var kept []*yaml.Node
+	for _, tag := range tags.Content {
+		if !s.internalTags[tag.Value] {
+			kept = append(kept, tag)
+		}
+	}
+	tags.Content = kept
+}
+
+// stripComponentContents strips nested internal content from the remaining components
+func (s *internalStripper) stripComponentContents() {
+	for _, documentSection := range documentComponentSections(s.root) {
+		sectionNode, section, _ := componentSection(s.root, documentSection)
+		if sectionNode == nil || sectionNode.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j < len(sectionNode.Content); j += 2 {
+			s.stripNested(sectionNode.Content[j+1], componentKey(section, sectionNode.Content[j].Value))
+		}
+	}
+}
+
+// stripNested removes internal properties, parameters and list items, plus bare references to
+// removed components, anywhere below node. Removed properties are dropped from required lists.
+func (s *internalStripper) stripNested(node *yaml.Node, context string) {
+	if node == nil {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var kept []*yaml.Node
+		for _, item := range node.Content {
+			if s.shouldRemove(item) {
+				s.recordItem(item, context)
+				continue
+			}
+			s.stripNested(item, context)
+			kept = append(kept, item)
+		}
+		node.Content = kept
+	case yaml.MappingNode:
+		var removedProperties []string
+		var kept []*yaml.Node
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			value := node.Content[i+1]
+
+			if key == "properties" && value.Kind == yaml.MappingNode {
+				removedProperties = append(removedProperties, s.stripProperties(value, context)...)
+			} else if s.isRemovedRef(value) {
+				s.record("reference %s (%s)", key, context)
+				continue
+			} else if isHTTPMethod(key) && s.isInternal(value) {
+				s.record("callback operation %s (%s)", strings.ToUpper(key), context)
+				continue
+			} else {
+				s.stripNested(value, context)
+			}
+			kept = append(kept, node.Content[i], value)
+		}
+		node.Content = kept
+
+		if len(removedProperties) > 0 {
+			removeRequiredNames(node, removedProperties)
+		}
+	}
+}
+
+// stripProperties removes internal properties from a properties mapping and returns their names
+func (s *internalStripper) stripProperties(properties *yaml.Node, context string) []string {
+	var removed []string
+	var kept []*yaml.Node
+	for i := 0; i < len(properties.Content); i += 2 {
+		name := properties.Content[i].Value
+		value := properties.Content[i+1]
+		if s.shouldRemove(value) {
+			s.record("property %s (%s)", name, context)
+			removed = append(removed, name)
+			continue
+		}
+		s.stripNested(value, context)
+		kept = append(kept, properties.Content[i], value)
+	}
+	properties.Content = kept
+	return removed
+}
+
+// recordItem records a removed list item, naming parameters by name and location
+func (s *internalStripper) recordItem(item *yaml.Node, context string) {
+	if name := getStringValue(item, "name"); name != "" {
+		s.record("parameter %s in %s (%s)", name, getStringValue(item, "in"), context)
+		return
+	}
+	if ref := getStringValue(item, "$ref"); ref != "" {
+		s.record("reference %s (%s)", ref, context)
+		return
+	}
+	s.record("item (%s)", context)
+}
+
+// removeRequiredNames removes names from a schema's required list, dropping the list if it becomes empty
+func removeRequiredNames(schema *yaml.Node, names []string) {
+	required := getNodeValue(schema, "required")
+	if required == nil || required.Kind != yaml.SequenceNode {
+		return
+	}
+
+	var kept []*yaml.Node
+	for _, item := range required.Content {
+		if !contains(names, item.Value) {
+			kept = append(kept, item)
+		}
+	}
+	required.Content = kept
+
+	if len(kept) == 0 {
+		removeMappingKey(schema, "required")
+	}
+}
+
+// pruneUnreachableComponents removes components that were reachable before stripping but are not
+// anymore, then drops empty component sections. It returns the pruned "section.Name" keys in sorted order.
+func pruneUnreachableComponents(root *yaml.Node, reachableBefore map[string]bool) []string {
+	reachableAfter := componentClosure(root)
+	pruned, _ := filterComponents(root, func(key string) bool {
+		return !reachableBefore[key] || reachableAfter[key]
+	})
+	sort.Strings(pruned)
+	return pruned
+}
@@ -0,0 +1,306 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const internalTestSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+tags:
+  - name: users
+  - name: admin
+    x-internal: true
+paths:
+  /users:
+    get:
+      tags: [users, admin]
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: debug
+          in: query
+          x-internal: true
+          schema:
+            type: boolean
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+    delete:
+      x-internal: true
+      responses:
+        "204":
+          description: Deleted
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/DeleteAudit'
+  /admin/reindex:
+    post:
+      x-internal: true
+      responses:
+        "202":
+          description: Accepted
+  /health:
+    x-internal: true
+    get:
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, passwordHash]
+      properties:
+        id:
+          type: string
+        passwordHash:
+          type: string
+          x-internal: true
+        audit:
+          $ref: '#/components/schemas/AuditInfo'
+    AuditInfo:
+      x-internal: true
+      type: object
+      properties:
+        actor:
+          $ref: '#/components/schemas/Actor'
+    Actor:
+      type: object
+    DeleteAudit:
+      type: object
+    Unreferenced:
+      type: object
+`
+
+func TestProcessStripInternalInDir(t *testing.T) {
+	tests := []struct {
+		name          string
+		strip         config.StripInternal
+		expectChanged bool
+		contains      []string
+		notContains   []string
+	}{
+		{
+			name:          "disabled",
+			strip:         config.StripInternal{Enabled: false},
+			expectChanged: false,
+			contains:      []string{"passwordHash", "/admin/reindex"},
+		},
+		{
+			name:          "default marker",
+			strip:         config.StripInternal{Enabled: true},
+			expectChanged: true,
+			contains: []string{
+				"/users:",
+				"name: limit",
+				"required: [id]",
+				"tags: [users]",
+				"Unreferenced:",
+			},
+			notContains: []string{
+				"x-internal",
+				"debug",
+				"delete:",
+				"/admin/reindex",
+				"/health",
+				"passwordHash",
+				"AuditInfo",
+				"audit:",
+				"Actor",
+				"DeleteAudit",
+				"name: admin",
+			},
+		},
+		{
+			name:          "custom marker",
+			strip:         config.StripInternal{Enabled: true, Extension: "x-private"},
+			expectChanged: false,
+			contains:      []string{"passwordHash"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "api.yaml")
+			if err := os.WriteFile(path, []byte(internalTestSpec), 0600); err != nil {
+				t.Fatalf("failed to write test file: %v", err)
+			}
+
+			result, err := ProcessStripInternalInDir(dir, InternalOptions{StripInternal: tt.strip})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Changed != tt.expectChanged {
+				t.Errorf("expected Changed=%v, got %v", tt.expectChanged, result.Changed)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			output := string(data)
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output to contain %q\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("expected output not to contain %q\n%s", unwanted, output)
+				}
+			}
+		})
+	}
+}
+
+func TestStripInternalReportsPrunedComponents(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(internalTestSpec), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	opts := InternalOptions{
+		Options:       Options{DryRun: true},
+		StripInternal: config.StripInternal{Enabled: true},
+	}
+	result, err := ProcessStripInternalInDir(dir, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pruned := result.PrunedComponents[path]
+	if strings.Join(pruned, ",") != "schemas.Actor,schemas.DeleteAudit" {
+		t.Errorf("expected Actor and DeleteAudit to be pruned, got %v", pruned)
+	}
+
+	removed := strings.Join(result.RemovedItems[path], "\n")
+	for _, want := range []string{
+		"component schemas.AuditInfo",
+		"tag admin",
+		"operation DELETE /users",
+		"parameter debug in query (GET /users)",
+		"property passwordHash (schemas.User)",
+		"property audit (schemas.User)",
+		"empty path /admin/reindex",
+		"path /health",
+	} {
+		if !strings.Contains(removed, want) {
+			t.Errorf("expected removed items to contain %q, got:\n%s", want, removed)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if string(data) != internalTestSpec {
+		t.Error("expected dry run to leave the file untouched")
+	}
+}
+
+func TestStripInternalSwagger2(t *testing.T) {
+	dir, path := writeTestSpec(t, `swagger: "2.0"
+info:
+  title: Test API
+  version: 1.0.0
+security:
+  - adminKey: []
+paths:
+  /users:
+    get:
+      parameters:
+        - $ref: '#/parameters/Debug'
+        - $ref: '#/parameters/Limit'
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: '#/definitions/User'
+        "403":
+          $ref: '#/responses/AdminOnly'
+parameters:
+  Debug:
+    name: debug
+    in: query
+    type: boolean
+    x-internal: true
+  Limit:
+    name: limit
+    in: query
+    type: integer
+responses:
+  AdminOnly:
+    description: Forbidden
+    x-internal: true
+    schema:
+      $ref: '#/definitions/AdminError'
+definitions:
+  User:
+    type: object
+    properties:
+      audit:
+        $ref: '#/definitions/Audit'
+  Audit:
+    type: object
+    x-internal: true
+  AdminError:
+    type: object
+securityDefinitions:
+  adminKey:
+    type: apiKey
+    in: header
+    name: X-Admin
+    x-internal: true
+`)
+
+	opts := InternalOptions{StripInternal: config.StripInternal{Enabled: true}}
+	result, err := ProcessStripInternalInDir(dir, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pruned := strings.Join(result.PrunedComponents[path], ","); pruned != "definitions.AdminError" {
+		t.Errorf("expected AdminError to be pruned, got %v", pruned)
+	}
+	removed := strings.Join(result.RemovedItems[path], "\n")
+	for _, want := range []string{
+		"component parameters.Debug",
+		"component responses.AdminOnly",
+		"component definitions.Audit",
+		"component securityDefinitions.adminKey",
+		"property audit (definitions.User)",
+	} {
+		if !strings.Contains(removed, want) {
+			t.Errorf("expected removed items to contain %q, got:\n%s", want, removed)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	for _, unwanted := range []string{"Debug", "AdminOnly", "Audit", "AdminError", "adminKey", "x-internal"} {
+		if strings.Contains(string(data), unwanted) {
+			t.Errorf("expected output not to contain %q, got:\n%s", unwanted, data)
+		}
+	}
+	if !strings.Contains(string(data), "$ref: '#/parameters/Limit'") {
+		t.Errorf("expected the public parameter to be kept, got:\n%s", data)
+	}
+}
@@ -0,0 +1,232 @@
+package transform
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+	"github.com/developerkunal/OpenMorph/internal/pagination"
+)
+
+// Pagination invariants checked after the pipeline
+const (
+	InvariantSingleStrategy  = "single_strategy"  // the parameters of an operation belong to one strategy at most
+	InvariantExtensionParams = "extension_params" // a pagination extension only names existing parameters
+	InvariantNoStrayFields   = "no_stray_fields"  // responses keep no fields of a strategy that was not selected
+)
+
+// InvariantViolation is an operation whose pagination the pipeline left inconsistent
+type InvariantViolation struct {
+	StrictIssue
+	Invariant string // InvariantSingleStrategy, InvariantExtensionParams or InvariantNoStrayFields
+}
+
+// InvariantError is returned by the pipeline when pagination_invariants.fail is set and the
+// transformed specs violate an invariant
+type InvariantError struct {
+	Violations []InvariantViolation
+}
+
+func (e *InvariantError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pagination invariants violated %d time(s):", len(e.Violations))
+	for _, violation := range e.Violations {
+		fmt.Fprintf(&b, "\n  %s: %s", violation.Position(), violation.Message)
+	}
+	return b.String()
+}
+
+// invariantChecks selects the invariants that apply to a run
+type invariantChecks struct {
+	strategies   bool                                  // the pagination step ran
+	extensions   []string                              // pagination extensions the vendor step adds
+	sharedFields map[string]pagination.SharedFieldRule // fields kept per selected strategy
+	results      map[string]string                     // results_fields
+	paths        []string                              // only_paths
+}
+
+// checkPaginationInvariants checks the transformed files under dir once every step ran and records
+// the violations in results. With pagination_invariants.fail, violations fail the run. Dry runs
+// leave the files as they were, so there is nothing to check.
+func (tp *TransformationPipeline) checkPaginationInvariants(dir string, opts Options, results *TransformationResults) error {
+	if tp.Config.PaginationInvariants.Disabled || opts.DryRun {
+		return nil
+	}
+
+	checks := invariantChecks{
+		strategies:   len(tp.Config.PaginationPriority) > 0,
+		sharedFields: convertSharedFields(tp.Config.SharedFields),
+		results:      tp.Config.ResultsFields,
+		paths:        opts.Paths,
+	}
+	for _, name := range tp.appliedVendorProviders() {
+		provider, ok := tp.Config.VendorExtensions.Providers[name]
+		if ok && (provider.Mode == "" || provider.Mode == ProviderModePagination) && provider.ExtensionName != "" {
+			checks.extensions = append(checks.extensions, provider.ExtensionName)
+		}
+	}
+	if !checks.strategies && len(checks.extensions) == 0 {
+		return nil
+	}
+
+	err := walkOpenAPIDocuments(dir, func(path string, _, root *yaml.Node) error {
+		if IncludesFile(opts.Files, "", dir, path) {
+			results.InvariantViolations = append(results.InvariantViolations, documentInvariantViolations(path, root, checks)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check pagination invariants: %v", err)
+	}
+	if tp.Config.PaginationInvariants.Fail && len(results.InvariantViolations) > 0 {
+		return &InvariantError{Violations: results.InvariantViolations}
+	}
+	return nil
+}
+
+// documentInvariantViolations checks the operations of a document
+func documentInvariantViolations(path string, root *yaml.Node, checks invariantChecks) []InvariantViolation {
+	var violations []InvariantViolation
+	record := func(node *yaml.Node, invariant, message string) {
+		violations = append(violations, InvariantViolation{
+			StrictIssue: StrictIssue{File: path, Line: node.Line, Column: node.Column, Message: message},
+			Invariant:   invariant,
+		})
+	}
+
+	paths := getNodeValue(root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+		if pathItem.Kind != yaml.MappingNode || !condition.MatchPath(pathName, checks.paths, condition.Glob) {
+			continue
+		}
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			method, operation := pathItem.Content[j].Value, pathItem.Content[j+1]
+			if !isHTTPMethod(method) || operation.Kind != yaml.MappingNode {
+				continue
+			}
+			context := strings.ToUpper(method) + " " + pathName
+			params := operationParameters(pathItem, operation)
+
+			if checks.strategies {
+				strategies := pagination.ParamStrategies(params, root)
+				if len(strategies) > 1 {
+					record(operation, InvariantSingleStrategy, fmt.Sprintf("%s: parameters of several pagination strategies: %s", context, strings.Join(strategies, ", ")))
+				}
+				if len(strategies) == 1 {
+					keep := checks.sharedFields[strategies[0]].Keep
+					if results := pagination.ResultsField(checks.results, pathName); results != "" {
+						keep = append(slices.Clip(keep), results)
+					}
+					if stray := pagination.StrayFields(getNodeValue(operation, "responses"), strategies[0], keep, root); len(stray) > 0 {
+						record(operation, InvariantNoStrayFields, fmt.Sprintf("%s: paginated with %s, but responses keep fields of other strategies: %s", context, strategies[0], strings.Join(stray, ", ")))
+					}
+				}
+			}
+
+			for _, extension := range checks.extensions {
+				value := getNodeValue(operation, extension)
+				if value == nil {
+					continue
+				}
+				for _, name := range requestReferences(value) {
+					if !hasRequestField(operation, params, name, root) {
+						record(value, InvariantExtensionParams, fmt.Sprintf("%s: %s names $request.%s, which is not a parameter or request body property", context, extension, name))
+					}
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// operationParameters returns the parameters of an operation along with those it inherits from
+// its path item
+func operationParameters(pathItem, operation *yaml.Node) *yaml.Node {
+	combined := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, node := range []*yaml.Node{pathItem, operation} {
+		if params := getNodeValue(node, "parameters"); params != nil && params.Kind == yaml.SequenceNode {
+			combined.Content = append(combined.Content, params.Content...)
+		}
+	}
+	return combined
+}
+
+// requestReferences returns the names of the request fields an extension value refers to with
+// $request expressions, such as cursor for $request.cursor or $request.body.cursor
+func requestReferences(node *yaml.Node) []string {
+	var names []string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if rest, ok := strings.CutPrefix(node.Value, "$request."); ok {
+			segments := strings.Split(rest, ".")
+			if name := segments[len(segments)-1]; name != "" {
+				names = append(names, name)
+			}
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			names = append(names, requestReferences(node.Content[i])...)
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			names = append(names, requestReferences(child)...)
+		}
+	}
+	return names
+}
+
+// hasRequestField reports whether an operation has a parameter or a top-level request body
+// property called name
+func hasRequestField(operation, params *yaml.Node, name string, root *yaml.Node) bool {
+	for _, param := range params.Content {
+		if getStringValue(resolveLocalRef(param, root), "name") == name {
+			return true
+		}
+	}
+
+	content := getNodeValue(resolveLocalRef(getNodeValue(operation, "requestBody"), root), "content")
+	if content == nil || content.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 1; i < len(content.Content); i += 2 {
+		schema := resolveLocalRef(getNodeValue(content.Content[i], "schema"), root)
+		members := []*yaml.Node{schema}
+		if allOf := getNodeValue(schema, "allOf"); allOf != nil && allOf.Kind == yaml.SequenceNode {
+			for _, member := range allOf.Content {
+				members = append(members, resolveLocalRef(member, root))
+			}
+		}
+		for _, member := range members {
+			if getNodeValue(getNodeValue(member, "properties"), name) != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveLocalRef returns the node a local $ref points to, or the node itself when it is not a
+// reference or the reference does not resolve
+func resolveLocalRef(node, root *yaml.Node) *yaml.Node {
+	ref := getStringValue(node, "$ref")
+	if pointer, ok := strings.CutPrefix(ref, "#"); ok {
+		if resolved := resolvePointer(root, pointer); resolved != nil {
+			return resolved
+		}
+	}
+	return node
+}
+
+// normalizeInvariantViolations reports the violations found on a temporary copy against inputPath
+func normalizeInvariantViolations(inputPath string, results *TransformationResults) {
+	for i := range results.InvariantViolations {
+		results.InvariantViolations[i].File = inputPath
+	}
+}
@@ -0,0 +1,169 @@
+package transform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const invariantSpec = `openapi: 3.0.3
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: offset
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+  /orders:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Cursor'
+      x-fern-pagination:
+        cursor: $request.cursor
+        next_cursor: $response.next_cursor
+        results: $response.orders
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  next_cursor:
+                    type: string
+                  total_count:
+                    type: integer
+  /search:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              allOf:
+                - $ref: '#/components/schemas/Query'
+      x-fern-pagination:
+        cursor: $request.body.after
+      responses:
+        "200":
+          description: OK
+  /events:
+    get:
+      x-fern-pagination:
+        cursor: $request.cursor
+      responses:
+        "200":
+          description: OK
+components:
+  parameters:
+    Cursor:
+      name: cursor
+      in: query
+      schema:
+        type: string
+  schemas:
+    Query:
+      type: object
+      properties:
+        after:
+          type: string
+`
+
+func invariantConfig() *config.Config {
+	return &config.Config{
+		PaginationPriority: []string{"cursor", "offset"},
+		VendorExtensions: config.VendorExtensions{
+			Enabled:   true,
+			Providers: map[string]config.ProviderConfig{"fern": {ExtensionName: "x-fern-pagination"}},
+		},
+	}
+}
+
+func TestCheckPaginationInvariants(t *testing.T) {
+	dir, _ := writeTestSpec(t, invariantSpec)
+
+	results := &TransformationResults{}
+	tp := NewTransformationPipeline(invariantConfig(), nil, false, false, "")
+	if err := tp.checkPaginationInvariants(dir, Options{}, results); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, violation := range results.InvariantViolations {
+		if violation.Line == 0 {
+			t.Errorf("expected a position for %+v", violation)
+		}
+		got = append(got, violation.Invariant+" "+violation.Message)
+	}
+	want := "single_strategy GET /users: parameters of several pagination strategies: cursor, offset\n" +
+		"no_stray_fields GET /orders: paginated with cursor, but responses keep fields of other strategies: total_count\n" +
+		"extension_params GET /events: x-fern-pagination names $request.cursor, which is not a parameter or request body property"
+	if strings.Join(got, "\n") != want {
+		t.Errorf("unexpected violations:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestCheckPaginationInvariantsKeepsSharedFields(t *testing.T) {
+	dir, _ := writeTestSpec(t, invariantSpec)
+
+	cfg := invariantConfig()
+	cfg.SharedFields = map[string]config.SharedFieldRule{"cursor": {Keep: []string{"total_count"}}}
+	cfg.OnlyPaths = []string{"/orders"}
+	results := &TransformationResults{}
+	tp := NewTransformationPipeline(cfg, nil, false, false, "")
+	if err := tp.checkPaginationInvariants(dir, Options{Paths: cfg.OnlyPaths}, results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results.InvariantViolations) != 0 {
+		t.Errorf("expected no violations, got %+v", results.InvariantViolations)
+	}
+}
+
+func TestCheckPaginationInvariantsFail(t *testing.T) {
+	dir, _ := writeTestSpec(t, invariantSpec)
+
+	cfg := invariantConfig()
+	cfg.PaginationInvariants.Fail = true
+	tp := NewTransformationPipeline(cfg, nil, false, false, "")
+	err := tp.checkPaginationInvariants(dir, Options{}, &TransformationResults{})
+	var invariantErr *InvariantError
+	if !errors.As(err, &invariantErr) || len(invariantErr.Violations) != 3 {
+		t.Fatalf("expected an invariant error with 3 violations, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "api.yaml:8:7: GET /users") {
+		t.Errorf("expected positions in the error, got %q", err.Error())
+	}
+
+	cfg.PaginationInvariants.Disabled = true
+	results := &TransformationResults{}
+	if err := tp.checkPaginationInvariants(dir, Options{}, results); err != nil || len(results.InvariantViolations) != 0 {
+		t.Errorf("expected disabled invariants to be skipped, got %v %+v", err, results.InvariantViolations)
+	}
+}
+
+func TestPipelineChecksPaginationInvariants(t *testing.T) {
+	dir, _ := writeTestSpec(t, invariantSpec)
+
+	cfg := &config.Config{PaginationPriority: []string{"cursor", "offset"}}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The pagination step picks cursor for /users and drops total_count from /orders
+	if len(results.InvariantViolations) != 0 {
+		t.Errorf("expected no violations after the pipeline, got %+v", results.InvariantViolations)
+	}
+}
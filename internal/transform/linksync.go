@@ -0,0 +1,312 @@
+package transform
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LinkSyncResult represents the result of keeping response links in step with the operations they
+// name, after steps renamed or removed operations
+type LinkSyncResult struct {
+	Changed        bool
+	ProcessedFiles []string
+	UpdatedLinks   map[string][]string // file -> links pointed at a renamed operation
+	RemovedLinks   map[string][]string // file -> links dropped because their operation is gone
+	Locations      []ChangeLocation    // source positions of the updated and removed links
+}
+
+// operationChanges holds the operations of every OpenAPI document before and after the pipeline,
+// keyed by absolute path, and the renames detected between them
+type operationChanges struct {
+	before  map[string][]operationRef
+	after   map[string][]operationRef
+	renames map[string]*operationRenames
+}
+
+// detectOperationChanges snapshots the operations below dir again and compares them with before
+func detectOperationChanges(dir string, before map[string][]operationRef) (*operationChanges, error) {
+	after, err := snapshotOperations(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &operationChanges{before: before, after: after, renames: diffOperationSnapshots(before, after)}, nil
+}
+
+// changed reports whether the operations of a document changed
+func (c *operationChanges) changed(absPath string) bool {
+	before, after := c.before[absPath], c.after[absPath]
+	if len(before) != len(after) {
+		return true
+	}
+	for i := range before {
+		if before[i] != after[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasChanges reports whether the operations of any document changed
+func (c *operationChanges) hasChanges() bool {
+	for absPath := range c.before {
+		if c.changed(absPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncOperationLinksInDir updates the links whose operationId or operationRef names an operation
+// the pipeline renamed, and removes those naming an operation it removed, in every OpenAPI
+// document below dir whose operations changed
+func SyncOperationLinksInDir(dir string, opts Options, changes *operationChanges) (*LinkSyncResult, error) {
+	return processTransformInDir(
+		dir,
+		StepLinkSync,
+		opts,
+		changes != nil && changes.hasChanges(),
+		false,
+		func() *LinkSyncResult {
+			return &LinkSyncResult{
+				ProcessedFiles: []string{},
+				UpdatedLinks:   make(map[string][]string),
+				RemovedLinks:   make(map[string][]string),
+			}
+		},
+		func(path string, result *LinkSyncResult) (bool, error) {
+			return syncOperationLinksInFile(path, opts, changes, result)
+		},
+		func(result *LinkSyncResult, files []string) { result.ProcessedFiles = files },
+		func(result *LinkSyncResult, changed bool) { result.Changed = changed },
+	)
+}
+
+// syncOperationLinksInFile updates the links of one document whose operations changed
+func syncOperationLinksInFile(path string, opts Options, changes *operationChanges, result *LinkSyncResult) (bool, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := changes.before[absPath]; !ok || !changes.changed(absPath) {
+		return false, nil
+	}
+
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil
+	}
+
+	s := &linkSyncer{
+		file:    path,
+		renames: changes.renames[absPath],
+		before:  operationTargetsOf(changes.before[absPath]),
+		after:   collectOperationTargets(root),
+		result:  result,
+	}
+	if s.renames == nil {
+		s.renames = &operationRenames{}
+	}
+	if !s.syncDocument(root) {
+		return false, nil
+	}
+	return writeStepDocument(opts, doc, path)
+}
+
+// operationTargetsOf returns the operationIds and operation pointers of a snapshot
+func operationTargetsOf(operations []operationRef) operationTargets {
+	targets := operationTargets{ids: make(map[string]bool), pointers: make(map[string]bool)}
+	for _, op := range operations {
+		if op.OperationID != "" {
+			targets.ids[op.OperationID] = true
+		}
+		targets.pointers["/paths/"+escapeJSONPointer(op.Path)+"/"+op.Method] = true
+	}
+	return targets
+}
+
+// linkSyncer updates the links of one document
+type linkSyncer struct {
+	file    string
+	renames *operationRenames
+	before  operationTargets
+	after   operationTargets
+	removed map[string]bool // removed link components, by $ref
+	changed bool
+	result  *LinkSyncResult
+}
+
+// syncDocument updates the link components first, so links referencing a removed one go with it,
+// then the links of every response. It reports whether anything changed.
+func (s *linkSyncer) syncDocument(root *yaml.Node) bool {
+	s.removed = make(map[string]bool)
+	components := getNodeValue(root, "components")
+	if links := getNodeValue(components, "links"); links != nil && links.Kind == yaml.MappingNode {
+		var kept []*yaml.Node
+		for i := 0; i+1 < len(links.Content); i += 2 {
+			name := links.Content[i].Value
+			if !s.syncLink(links.Content[i], links.Content[i+1], "component "+componentKey("links", name)) {
+				s.removed[componentRef("links", name)] = true
+				continue
+			}
+			kept = append(kept, links.Content[i], links.Content[i+1])
+		}
+		links.Content = kept
+	}
+
+	for _, section := range []string{"paths", "webhooks"} {
+		if items := getNodeValue(root, section); items != nil && items.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(items.Content); i += 2 {
+				s.syncPathItem(items.Content[i+1], items.Content[i].Value)
+			}
+		}
+	}
+	if responses := getNodeValue(components, "responses"); responses != nil && responses.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(responses.Content); i += 2 {
+			s.syncResponse(responses.Content[i+1], "component "+componentKey("responses", responses.Content[i].Value))
+		}
+	}
+	if callbacks := getNodeValue(components, "callbacks"); callbacks != nil && callbacks.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(callbacks.Content); i += 2 {
+			s.syncCallback(callbacks.Content[i+1])
+		}
+	}
+	return s.changed
+}
+
+// syncPathItem updates the links of the responses of every operation of a path item, including
+// those of its callbacks
+func (s *linkSyncer) syncPathItem(pathItem *yaml.Node, path string) {
+	if pathItem == nil || pathItem.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		method, operation := pathItem.Content[i].Value, pathItem.Content[i+1]
+		if !isHTTPMethod(method) || operation.Kind != yaml.MappingNode {
+			continue
+		}
+		context := strings.ToUpper(method) + " " + path
+		if responses := getNodeValue(operation, "responses"); responses != nil && responses.Kind == yaml.MappingNode {
+			for j := 0; j+1 < len(responses.Content); j += 2 {
+				s.syncResponse(responses.Content[j+1], context+" response "+responses.Content[j].Value)
+			}
+		}
+		if callbacks := getNodeValue(operation, "callbacks"); callbacks != nil && callbacks.Kind == yaml.MappingNode {
+			for j := 0; j+1 < len(callbacks.Content); j += 2 {
+				s.syncCallback(callbacks.Content[j+1])
+			}
+		}
+	}
+}
+
+// syncCallback updates the links of the path items of a callback
+func (s *linkSyncer) syncCallback(callback *yaml.Node) {
+	if callback == nil || callback.Kind != yaml.MappingNode || getNodeValue(callback, "$ref") != nil {
+		return
+	}
+	for i := 0; i+1 < len(callback.Content); i += 2 {
+		if expression := callback.Content[i].Value; !strings.HasPrefix(expression, "x-") {
+			s.syncPathItem(callback.Content[i+1], expression)
+		}
+	}
+}
+
+// syncResponse updates the links of a response, dropping the links map when none are left
+func (s *linkSyncer) syncResponse(response *yaml.Node, context string) {
+	links := getNodeValue(response, "links")
+	if links == nil || links.Kind != yaml.MappingNode {
+		return
+	}
+
+	var kept []*yaml.Node
+	for i := 0; i+1 < len(links.Content); i += 2 {
+		name, link := links.Content[i], links.Content[i+1]
+		if ref := getStringValue(link, "$ref"); s.removed[ref] {
+			s.recordRemoval(name, fmt.Sprintf("link %s (%s): %s was removed", name.Value, context, ref))
+			continue
+		}
+		if !s.syncLink(name, link, "link "+name.Value+" ("+context+")") {
+			continue
+		}
+		kept = append(kept, name, link)
+	}
+	if len(kept) == len(links.Content) {
+		return
+	}
+	links.Content = kept
+
+	if len(kept) == 0 {
+		removeMappingKey(response, "links")
+	}
+}
+
+// syncLink points a link at its renamed operation. It returns false when the link names an
+// operation that existed before and is gone, so the caller drops it.
+func (s *linkSyncer) syncLink(key, link *yaml.Node, label string) bool {
+	if link == nil || link.Kind != yaml.MappingNode {
+		return true
+	}
+
+	if node := getNodeValue(link, "operationId"); node != nil && node.Kind == yaml.ScalarNode {
+		if newID, ok := s.renames.operationIDs[node.Value]; ok {
+			s.recordUpdate(node, fmt.Sprintf("%s operationId: %s -> %s", label, node.Value, newID))
+			node.Value = newID
+			return true
+		}
+		if s.before.ids[node.Value] && !s.after.ids[node.Value] {
+			s.recordRemoval(key, fmt.Sprintf("%s: operation %s was removed", label, node.Value))
+			return false
+		}
+		return true
+	}
+
+	node := getNodeValue(link, "operationRef")
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return true
+	}
+	pointer, ok := strings.CutPrefix(node.Value, "#")
+	if !ok {
+		return true
+	}
+	if unescaped, err := url.PathUnescape(pointer); err == nil {
+		pointer = unescaped
+	}
+	if rest, ok := strings.CutPrefix(pointer, "/paths/"); ok {
+		escapedPath, method, _ := strings.Cut(rest, "/")
+		if newPath, ok := s.renames.paths[unescapeJSONPointer(escapedPath)]; ok {
+			newRef := "#/paths/" + escapeJSONPointer(newPath)
+			if method != "" {
+				newRef += "/" + method
+			}
+			s.recordUpdate(node, fmt.Sprintf("%s operationRef: %s -> %s", label, node.Value, newRef))
+			node.Value = newRef
+			return true
+		}
+	}
+	if s.before.pointers[pointer] && !s.after.pointers[pointer] {
+		s.recordRemoval(key, fmt.Sprintf("%s: operation %s was removed", label, node.Value))
+		return false
+	}
+	return true
+}
+
+// recordUpdate records a link pointed at a renamed operation
+func (s *linkSyncer) recordUpdate(node *yaml.Node, message string) {
+	s.changed = true
+	s.result.UpdatedLinks[s.file] = append(s.result.UpdatedLinks[s.file], message)
+	s.result.Locations = append(s.result.Locations, newChangeLocation(s.file, StepLinkSync, node, message))
+}
+
+// recordRemoval records a link dropped because its operation is gone
+func (s *linkSyncer) recordRemoval(node *yaml.Node, message string) {
+	s.changed = true
+	s.result.RemovedLinks[s.file] = append(s.result.RemovedLinks[s.file], message)
+	s.result.Locations = append(s.result.Locations, newChangeLocation(s.file, StepLinkSync, node, message))
+}
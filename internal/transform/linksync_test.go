@@ -0,0 +1,187 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const linkSyncBeforeSpec = `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: OK
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          description: OK
+  /pets/{petId}/photos:
+    get:
+      operationId: listPhotos
+      responses:
+        "200":
+          description: OK
+`
+
+const linkSyncAfterSpec = `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: OK
+          links:
+            pet:
+              operationId: getPet
+            photos:
+              operationId: listPhotos
+            shared:
+              $ref: '#/components/links/Photos'
+            self:
+              operationRef: '#/paths/~1pets/get'
+  /pets/{petId}:
+    get:
+      operationId: fetchPet
+      responses:
+        "200":
+          description: OK
+          links:
+            photos:
+              operationRef: '#/paths/~1pets~1{petId}~1photos/get'
+components:
+  links:
+    Photos:
+      operationId: listPhotos
+`
+
+func TestSyncOperationLinksInDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pets.yaml")
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := map[string][]operationRef{absPath: collectOperationRefs(parseYAMLToNode(t, linkSyncBeforeSpec))}
+	if err := os.WriteFile(path, []byte(linkSyncAfterSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := detectOperationChanges(dir, before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := SyncOperationLinksInDir(dir, Options{}, changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed {
+		t.Fatal("expected links to be updated")
+	}
+
+	wantUpdated := []string{"link pet (GET /pets response 200) operationId: getPet -> fetchPet"}
+	if got := result.UpdatedLinks[path]; strings.Join(got, "\n") != strings.Join(wantUpdated, "\n") {
+		t.Errorf("unexpected updated links %q", got)
+	}
+	wantRemoved := []string{
+		"component links.Photos: operation listPhotos was removed",
+		"link photos (GET /pets response 200): operation listPhotos was removed",
+		"link shared (GET /pets response 200): #/components/links/Photos was removed",
+		"link photos (GET /pets/{petId} response 200): operation #/paths/~1pets~1{petId}~1photos/get was removed",
+	}
+	if got := result.RemovedLinks[path]; strings.Join(got, "\n") != strings.Join(wantRemoved, "\n") {
+		t.Errorf("unexpected removed links:\n%s", strings.Join(got, "\n"))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{"operationId: fetchPet\n", "operationRef: '#/paths/~1pets/get'"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, content)
+		}
+	}
+	for _, unwanted := range []string{"listPhotos", "Photos:", "photos:"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("expected %q to be removed:\n%s", unwanted, content)
+		}
+	}
+}
+
+func TestPipelineSyncsLinksOnPathMapping(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: Pets API
+  version: 1.0.0
+paths:
+  /v1/pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: OK
+          links:
+            pet:
+              operationRef: '#/paths/~1v1~1pets~1{petId}/get'
+  /v1/pets/{petId}:
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          description: OK
+`
+	tests := []struct {
+		name   string
+		output bool
+	}{
+		{"directory", false},
+		{"single file output", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "pets.yaml")
+			if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+				t.Fatal(err)
+			}
+			input, output, outputFile := dir, path, ""
+			if tt.output {
+				outputFile = filepath.Join(t.TempDir(), "out.yaml")
+				input, output = path, outputFile
+			}
+
+			cfg := &config.Config{Mappings: map[string]string{"/v1/pets/{petId}": "/pets/{petId}"}}
+			results, err := NewTransformationPipeline(cfg, nil, false, false, outputFile).ExecuteFullPipeline(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if results.LinkResult == nil || !results.LinkResult.Changed {
+				t.Fatalf("expected the link to be updated, got %+v", results.LinkResult)
+			}
+
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), "operationRef: '#/paths/~1pets~1{petId}/get'") {
+				t.Errorf("expected the operationRef to follow the renamed path:\n%s", data)
+			}
+		})
+	}
+}
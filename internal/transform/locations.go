@@ -0,0 +1,146 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Step names used in ChangeLocation.Step, telemetry spans and step metrics
+const (
+	StepMappings             = "mappings"
+	StepStripInternal        = "strip_internal"
+	StepPathVariants         = "path_variants"
+	StepTenantPaths          = "tenant_paths"
+	StepUnwrapEnvelopes      = "unwrap_envelopes"
+	StepPagination           = "pagination"
+	StepFlatten              = "flatten"
+	StepParameterInjection   = "parameter_injection"
+	StepVendorExtensions     = "vendor_extensions"
+	StepExtensionValues      = "extension_values"
+	StepDefaults             = "defaults"
+	StepSchemaConstraints    = "schema_constraints"
+	StepNullability          = "nullability"
+	StepComponentDedup       = "component_dedup"
+	StepComponentRenames     = "component_renames"
+	StepSchemaTitles         = "schema_titles"
+	StepComponentConsistency = "component_consistency"
+	StepExamples             = "examples"
+	StepCanonicalize         = "canonicalize"
+	StepLinkSync             = "link_sync"
+	StepArazzoSync           = "arazzo_sync"
+)
+
+// ChangeLocation is the source position of a change reported by a transformation step.
+// Line and Column are 1-based and refer to the document as the step read it.
+type ChangeLocation struct {
+	File    string
+	Line    int
+	Column  int
+	Step    string // step that made the change
+	Message string // same text as the step's result entry
+}
+
+// Position returns the location as file:line:column, the format editors and terminals link to
+func (l ChangeLocation) Position() string {
+	if l.Line == 0 {
+		return l.File
+	}
+	return fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+}
+
+// newChangeLocation records the position of node for a change
+func newChangeLocation(file, step string, node *yaml.Node, message string) ChangeLocation {
+	location := ChangeLocation{File: file, Step: step, Message: message}
+	if node != nil {
+		location.Line = node.Line
+		location.Column = node.Column
+	}
+	return location
+}
+
+// AllLocations returns the locations of every change recorded by the pipeline, sorted by file and position
+func (r *TransformationResults) AllLocations() []ChangeLocation {
+	var locations []ChangeLocation
+	for _, change := range r.KeyChanges {
+		message := fmt.Sprintf("%s -> %s", change.OldKey, change.NewKey)
+		if change.Collision != "" {
+			message += fmt.Sprintf(" (collision: %s)", change.Collision)
+		}
+		locations = append(locations, ChangeLocation{
+			File:    change.File,
+			Line:    change.Line,
+			Column:  change.Column,
+			Step:    StepMappings,
+			Message: message,
+		})
+	}
+	if r.PathVariantsResult != nil {
+		locations = append(locations, r.PathVariantsResult.Locations...)
+	}
+	if r.TenantPathsResult != nil {
+		locations = append(locations, r.TenantPathsResult.Locations...)
+	}
+	if r.PaginationResult != nil {
+		locations = append(locations, r.PaginationResult.Locations...)
+	}
+	if r.EnvelopeResult != nil {
+		locations = append(locations, r.EnvelopeResult.Locations...)
+	}
+	if r.FlattenResult != nil {
+		locations = append(locations, r.FlattenResult.Locations...)
+	}
+	if r.InjectionResult != nil {
+		locations = append(locations, r.InjectionResult.Locations...)
+	}
+	if r.VendorResult != nil {
+		locations = append(locations, r.VendorResult.Locations...)
+	}
+	if r.ExtensionValuesResult != nil {
+		locations = append(locations, r.ExtensionValuesResult.Locations...)
+	}
+	if r.DefaultsResult != nil {
+		locations = append(locations, r.DefaultsResult.Locations...)
+	}
+	if r.ConstraintsResult != nil {
+		locations = append(locations, r.ConstraintsResult.Locations...)
+	}
+	if r.NullabilityResult != nil {
+		locations = append(locations, r.NullabilityResult.Locations...)
+	}
+	if r.SchemaTitlesResult != nil {
+		locations = append(locations, r.SchemaTitlesResult.Locations...)
+	}
+	if r.ConsistencyResult != nil {
+		locations = append(locations, r.ConsistencyResult.Locations...)
+	}
+	if r.ExamplesResult != nil {
+		locations = append(locations, r.ExamplesResult.Locations...)
+	}
+	if r.CanonicalizeResult != nil {
+		locations = append(locations, r.CanonicalizeResult.Locations...)
+	}
+	if r.LinkResult != nil {
+		locations = append(locations, r.LinkResult.Locations...)
+	}
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		if locations[i].File != locations[j].File {
+			return locations[i].File < locations[j].File
+		}
+		if locations[i].Line != locations[j].Line {
+			return locations[i].Line < locations[j].Line
+		}
+		return locations[i].Column < locations[j].Column
+	})
+	return locations
+}
+
+// rebaseLocations rewrites the file of every location
+func rebaseLocations(locations []ChangeLocation, rebase func(string) string) []ChangeLocation {
+	for i := range locations {
+		locations[i].File = rebase(locations[i].File)
+	}
+	return locations
+}
@@ -0,0 +1,86 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const locationsTestSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-owner: platform
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: Success
+`
+
+func TestPipelineRecordsChangeLocations(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(locationsTestSpec), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	cfg := &config.Config{
+		Mappings: map[string]string{"x-owner": "x-team"},
+		DefaultValues: config.DefaultValues{
+			Enabled: true,
+			Rules: map[string]config.DefaultRule{
+				"limit": {
+					Target:    config.DefaultTarget{Location: "parameter"},
+					Condition: config.DefaultCondition{PropertyName: "limit"},
+					Value:     20,
+				},
+			},
+		},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Positions refer to the document as each step read it: defaults run after the mapping step
+	// rewrote the file with four-space indentation
+	locations := results.AllLocations()
+	if len(locations) != 2 {
+		t.Fatalf("expected 2 locations, got %+v", locations)
+	}
+
+	expected := []ChangeLocation{
+		{File: path, Line: 8, Column: 7, Step: StepMappings, Message: "x-owner -> x-team"},
+		{File: path, Line: 13, Column: 21, Step: StepDefaults, Message: "GET /users: default = 20 (rule: limit)"},
+	}
+	for i, want := range expected {
+		if locations[i] != want {
+			t.Errorf("location %d: expected %+v, got %+v", i, want, locations[i])
+		}
+	}
+
+	if got := locations[1].Position(); got != path+":13:21" {
+		t.Errorf("unexpected position %q", got)
+	}
+}
+
+func TestPatchJSONKeysRecordsPosition(t *testing.T) {
+	orig := []byte("{\n  \"info\": {\n    \"x-owner\": \"platform\"\n  }\n}\n")
+	var changes []KeyChange
+	_, changed := patchJSONKeysWithChanges(orig, Options{Mappings: map[string]string{"x-owner": "x-team"}}, "api.json", &changes)
+	if !changed || len(changes) != 1 {
+		t.Fatalf("expected one change, got %+v", changes)
+	}
+	if changes[0].Line != 3 || changes[0].Column != 5 {
+		t.Errorf("expected position 3:5, got %d:%d", changes[0].Line, changes[0].Column)
+	}
+}
@@ -0,0 +1,82 @@
+package transform
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// nearMissRatio is the share of an unused mapping key a document key must start with to be
+// reported as a likely intended match
+const nearMissRatio = 0.75
+
+// MappingStat is how often one configured mapping matched during a run
+type MappingStat struct {
+	From       string
+	To         string
+	Matches    int              // keys renamed or moved, including collisions
+	NearMisses []ExtensionUsage // document keys similar to From, reported for mappings without matches
+}
+
+// MappingStats counts the key changes of a run per configured mapping, ordered by mapping key
+func MappingStats(mappings map[string]string, changes []KeyChange) []MappingStat {
+	counts := make(map[string]int, len(mappings))
+	for _, change := range changes {
+		counts[change.OldKey]++
+	}
+
+	stats := make([]MappingStat, 0, len(mappings))
+	for from, to := range mappings {
+		stats = append(stats, MappingStat{From: from, To: to, Matches: counts[from]})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].From < stats[j].From })
+	return stats
+}
+
+// FindNearMisses fills in the near misses of the mappings that matched nothing: extension keys in
+// the documents under inputPath that share most of their prefix with the mapping key, which is
+// likely a typo in the mapping or a variant spelling in the documents
+func FindNearMisses(inputPath string, files config.FileFilter, mappings map[string]string, stats []MappingStat) error {
+	unused := false
+	for _, stat := range stats {
+		unused = unused || stat.Matches == 0
+	}
+	if !unused {
+		return nil
+	}
+
+	usages, err := ScanExtensions(inputPath, files, mappings)
+	if err != nil {
+		return err
+	}
+	targets := make(map[string]bool, len(mappings))
+	for _, to := range mappings {
+		targets[to] = true
+	}
+
+	for i := range stats {
+		if stats[i].Matches > 0 {
+			continue
+		}
+		for _, usage := range usages {
+			if usage.MappedTo == "" && !targets[usage.Name] && isNearMiss(stats[i].From, usage.Name) {
+				stats[i].NearMisses = append(stats[i].NearMisses, usage)
+			}
+		}
+	}
+	return nil
+}
+
+// isNearMiss reports whether key starts with most of the mapping key from, ignoring case
+func isNearMiss(from, key string) bool {
+	from, key = strings.ToLower(from), strings.ToLower(key)
+	if from == key {
+		return true
+	}
+	common := 0
+	for common < len(from) && common < len(key) && from[common] == key[common] {
+		common++
+	}
+	return common > len("x-") && float64(common) >= nearMissRatio*float64(len(from))
+}
@@ -0,0 +1,49 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestMappingStats(t *testing.T) {
+	mappings := map[string]string{"x-a": "x-b", "x-c": "x-d"}
+	changes := []KeyChange{{OldKey: "x-a"}, {OldKey: "x-a"}, {OldKey: "x-e"}}
+
+	stats := MappingStats(mappings, changes)
+	if len(stats) != 2 || stats[0].From != "x-a" || stats[0].Matches != 2 || stats[1].From != "x-c" || stats[1].Matches != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestFindNearMisses(t *testing.T) {
+	dir := t.TempDir()
+	spec := "openapi: 3.0.0\ninfo:\n  title: Test\n  version: 1.0.0\n  x-speakeasy-groups: users\n  x-Internal: true\n  x-other: 1\npaths: {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	mappings := map[string]string{"x-speakeasy-group": "x-fern-sdk-group-name", "x-internal": "x-private", "x-other": "x-another"}
+	stats := MappingStats(mappings, []KeyChange{{OldKey: "x-other"}})
+	if err := FindNearMisses(dir, config.FileFilter{}, mappings, stats); err != nil {
+		t.Fatal(err)
+	}
+
+	misses := make(map[string][]string)
+	for _, stat := range stats {
+		for _, miss := range stat.NearMisses {
+			misses[stat.From] = append(misses[stat.From], miss.Name)
+		}
+	}
+	if len(misses["x-speakeasy-group"]) != 1 || misses["x-speakeasy-group"][0] != "x-speakeasy-groups" {
+		t.Errorf("expected x-speakeasy-groups as a near miss, got %v", misses)
+	}
+	if len(misses["x-internal"]) != 1 || misses["x-internal"][0] != "x-Internal" {
+		t.Errorf("expected x-Internal as a near miss, got %v", misses)
+	}
+	if len(misses["x-other"]) != 0 {
+		t.Errorf("expected no near misses for a used mapping, got %v", misses["x-other"])
+	}
+}
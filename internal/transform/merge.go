@@ -0,0 +1,426 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Conflict strategies for merging specs
+const (
+	MergeConflictError        = "error"
+	MergeConflictRenamePrefix = "rename-prefix"
+	MergeConflictPreferFirst  = "prefer-first"
+)
+
+// MergeOptions configures how multiple specs are merged
+type MergeOptions struct {
+	ConflictStrategy string   // error (default), rename-prefix or prefer-first
+	Prefixes         []string // component prefixes per input for rename-prefix, defaults to the PascalCased file name
+}
+
+// MergeResult represents the result of merging specs
+type MergeResult struct {
+	Inputs             []string
+	Paths              int
+	Components         int
+	Tags               int
+	Servers            int
+	RenamedComponents  []string // "file: section.Old -> New" for components renamed to resolve conflicts
+	SkippedComponents  []string // "file: section.Name" for conflicting components dropped in favor of the first
+	SkippedOperations  []string // "file: METHOD /path" for conflicting operations dropped in favor of the first
+	DuplicateIdentical int      // identical components/operations present in several inputs
+}
+
+// MergeSpecs merges the specs at inputPaths into one document written to outputPath. The first
+// input provides info, openapi version and any other top-level fields.
+func MergeSpecs(inputPaths []string, outputPath string, opts MergeOptions) (*MergeResult, error) {
+	output, result, err := MergeSpecsBytes(inputPaths, opts, IsJSON(outputPath))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, output, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+	return result, nil
+}
+
+// MergeSpecsBytes is like MergeSpecs but returns the formatted merged document instead of writing it
+func MergeSpecsBytes(inputPaths []string, opts MergeOptions, asJSON bool) ([]byte, *MergeResult, error) {
+	if len(inputPaths) < 2 {
+		return nil, nil, errors.New("at least two input specs are required")
+	}
+	if err := validateMergeOptions(inputPaths, opts); err != nil {
+		return nil, nil, err
+	}
+
+	var base *yaml.Node
+	var baseRoot *yaml.Node
+	result := &MergeResult{Inputs: inputPaths}
+
+	for i, path := range inputPaths {
+		doc, err := loadAndParseDocument(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading %s: %w", path, err)
+		}
+		root := getRootNode(doc)
+		if !isOpenAPIDocument(root) {
+			return nil, nil, fmt.Errorf("%s is not an OpenAPI document", path)
+		}
+
+		if i == 0 {
+			base, baseRoot = doc, root
+			continue
+		}
+		if want, got := specMajorVersion(baseRoot), specMajorVersion(root); got != want {
+			return nil, nil, fmt.Errorf("%s is %s but %s is %s; specs of different major versions cannot be merged",
+				path, got, inputPaths[0], want)
+		}
+
+		m := &specMerger{
+			target:   baseRoot,
+			source:   root,
+			file:     path,
+			strategy: getMergeConflictStrategy(opts),
+			prefix:   getMergePrefix(inputPaths, opts, i),
+			result:   result,
+		}
+		if err := m.merge(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	countMergedContent(baseRoot, result)
+
+	var output []byte
+	var err error
+	if asJSON {
+		output, err = formatAsJSON(base)
+	} else {
+		output, err = formatAsYAML(base)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return output, result, nil
+}
+
+// specMajorVersion describes the major version of a document, such as "Swagger 2" or "OpenAPI 3"
+func specMajorVersion(root *yaml.Node) string {
+	if version := getNodeValue(root, "swagger"); version != nil {
+		major, _, _ := strings.Cut(version.Value, ".")
+		return "Swagger " + major
+	}
+	major, _, _ := strings.Cut(getStringValue(root, "openapi"), ".")
+	return "OpenAPI " + major
+}
+
+// validateMergeOptions checks the conflict strategy and prefixes
+func validateMergeOptions(inputPaths []string, opts MergeOptions) error {
+	switch opts.ConflictStrategy {
+	case "", MergeConflictError, MergeConflictRenamePrefix, MergeConflictPreferFirst:
+	default:
+		return fmt.Errorf("unknown conflict strategy %q (expected %s, %s or %s)",
+			opts.ConflictStrategy, MergeConflictError, MergeConflictRenamePrefix, MergeConflictPreferFirst)
+	}
+
+	if len(opts.Prefixes) > 0 && len(opts.Prefixes) != len(inputPaths) {
+		return fmt.Errorf("expected %d prefixes (one per input), got %d", len(inputPaths), len(opts.Prefixes))
+	}
+	return nil
+}
+
+// getMergeConflictStrategy returns the configured conflict strategy, defaulting to error
+func getMergeConflictStrategy(opts MergeOptions) string {
+	if opts.ConflictStrategy == "" {
+		return MergeConflictError
+	}
+	return opts.ConflictStrategy
+}
+
+// getMergePrefix returns the component prefix for the input at index i
+func getMergePrefix(inputPaths []string, opts MergeOptions, i int) string {
+	if len(opts.Prefixes) > 0 {
+		return opts.Prefixes[i]
+	}
+	base := filepath.Base(inputPaths[i])
+	return pascalCaseName(strings.TrimSuffix(base, filepath.Ext(base)))
+}
+
+// pascalCaseName converts a file name such as "billing-api" into "BillingApi"
+func pascalCaseName(name string) string {
+	var sb strings.Builder
+	upperNext := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			sb.WriteRune(unicode.ToUpper(r))
+			upperNext = false
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// specMerger merges one source document into the target document
+type specMerger struct {
+	target   *yaml.Node
+	source   *yaml.Node
+	file     string
+	strategy string
+	prefix   string
+	result   *MergeResult
+}
+
+// merge merges components first (renaming conflicts in the source when needed), then paths,
+// webhooks, tags, servers and any remaining top-level fields
+func (m *specMerger) merge() error {
+	if err := m.mergeComponents(); err != nil {
+		return err
+	}
+
+	for _, section := range []string{"paths", "webhooks"} {
+		if err := m.mergePathItems(section); err != nil {
+			return err
+		}
+	}
+
+	m.mergeNamedList("tags", func(item *yaml.Node) string { return getStringValue(item, "name") })
+	m.mergeNamedList("servers", func(item *yaml.Node) string { return getStringValue(item, "url") })
+	m.mergeNamedList("security", func(item *yaml.Node) string { return componentFingerprint(item, nil) })
+
+	// Remaining top-level fields (e.g. x- extensions) are taken from the first input that has them
+	for i := 0; i < len(m.source.Content); i += 2 {
+		key := m.source.Content[i].Value
+		if getNodeValue(m.target, key) == nil {
+			m.target.Content = append(m.target.Content, m.source.Content[i], m.source.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// mergeComponents merges every component section, or the Swagger 2.0 sections holding them.
+// Identical components are merged silently; conflicting ones are handled by the conflict strategy.
+func (m *specMerger) mergeComponents() error {
+	sections := documentComponentSections(m.source)
+	if len(sections) == 0 {
+		return nil
+	}
+
+	// Renaming a component changes the refs of components that use it, which can turn them into
+	// conflicts as well, so renames are planned until nothing else conflicts
+	for {
+		renames, err := m.planComponentRenames(sections)
+		if err != nil {
+			return err
+		}
+		if len(renames) == 0 {
+			break
+		}
+		applyComponentRenames(m.source, renames)
+		for _, entry := range sortedRenameEntries(m.source, renames) {
+			m.result.RenamedComponents = append(m.result.RenamedComponents, m.file+": "+entry)
+		}
+	}
+
+	for _, section := range sections {
+		sourceSection, sectionName, _ := componentSection(m.source, section)
+		if sourceSection == nil || sourceSection.Kind != yaml.MappingNode {
+			continue
+		}
+		targetSection := ensureComponentSection(m.target, section)
+
+		for j := 0; j < len(sourceSection.Content); j += 2 {
+			name := sourceSection.Content[j].Value
+			existing := getNodeValue(targetSection, name)
+			switch {
+			case existing == nil:
+				targetSection.Content = append(targetSection.Content, sourceSection.Content[j], sourceSection.Content[j+1])
+			case componentFingerprint(existing, nil) == componentFingerprint(sourceSection.Content[j+1], nil):
+				m.result.DuplicateIdentical++
+			default:
+				// Only prefer-first reaches here: error and rename-prefix conflicts are resolved by planComponentRenames
+				m.result.SkippedComponents = append(m.result.SkippedComponents, m.file+": "+componentKey(sectionName, name))
+			}
+		}
+	}
+	return nil
+}
+
+// planComponentRenames finds conflicting components and, for rename-prefix, plans their new names.
+// It fails for the error strategy and when a prefixed name is taken as well.
+func (m *specMerger) planComponentRenames(sections []string) (map[string]map[string]string, error) {
+	renames := make(map[string]map[string]string)
+
+	for _, section := range sections {
+		sourceSection, sectionName, _ := componentSection(m.source, section)
+		targetSection, _, _ := componentSection(m.target, section)
+		if sourceSection == nil || sourceSection.Kind != yaml.MappingNode || targetSection == nil {
+			continue
+		}
+
+		for j := 0; j < len(sourceSection.Content); j += 2 {
+			name := sourceSection.Content[j].Value
+			existing := getNodeValue(targetSection, name)
+			if existing == nil || componentFingerprint(existing, nil) == componentFingerprint(sourceSection.Content[j+1], nil) {
+				continue
+			}
+
+			switch m.strategy {
+			case MergeConflictPreferFirst:
+				continue
+			case MergeConflictRenamePrefix:
+				newName := m.prefix + name
+				if getNodeValue(targetSection, newName) != nil || getNodeValue(sourceSection, newName) != nil {
+					return nil, fmt.Errorf("cannot rename conflicting component %s from %s: %s already exists",
+						componentKey(sectionName, name), m.file, componentKey(sectionName, newName))
+				}
+				if renames[section] == nil {
+					renames[section] = make(map[string]string)
+				}
+				renames[section][name] = newName
+			default:
+				return nil, fmt.Errorf("component %s in %s conflicts with an existing definition (use --conflict rename-prefix or prefer-first)",
+					componentKey(sectionName, name), m.file)
+			}
+		}
+	}
+	return renames, nil
+}
+
+// mergePathItems merges path items (or webhooks) operation by operation
+func (m *specMerger) mergePathItems(section string) error {
+	sourceItems := getNodeValue(m.source, section)
+	if sourceItems == nil || sourceItems.Kind != yaml.MappingNode {
+		return nil
+	}
+	targetItems := ensureMappingValue(m.target, section)
+
+	for i := 0; i < len(sourceItems.Content); i += 2 {
+		path := sourceItems.Content[i].Value
+		sourceItem := sourceItems.Content[i+1]
+		targetItem := getNodeValue(targetItems, path)
+		if targetItem == nil {
+			targetItems.Content = append(targetItems.Content, sourceItems.Content[i], sourceItem)
+			continue
+		}
+		if sourceItem.Kind != yaml.MappingNode || targetItem.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for j := 0; j < len(sourceItem.Content); j += 2 {
+			key := sourceItem.Content[j].Value
+			existing := getNodeValue(targetItem, key)
+			switch {
+			case existing == nil:
+				targetItem.Content = append(targetItem.Content, sourceItem.Content[j], sourceItem.Content[j+1])
+			case !isHTTPMethod(key):
+				// Path-level fields such as parameters and summary are taken from the first input
+			case componentFingerprint(existing, nil) == componentFingerprint(sourceItem.Content[j+1], nil):
+				m.result.DuplicateIdentical++
+			case m.strategy == MergeConflictPreferFirst:
+				m.result.SkippedOperations = append(m.result.SkippedOperations,
+					m.file+": "+strings.ToUpper(key)+" "+path)
+			default:
+				return fmt.Errorf("operation %s %s in %s conflicts with an existing definition (operations cannot be renamed; use --conflict prefer-first)",
+					strings.ToUpper(key), path, m.file)
+			}
+		}
+	}
+	return nil
+}
+
+// mergeNamedList appends source list items whose identity (as computed by id) is not yet present
+func (m *specMerger) mergeNamedList(key string, id func(*yaml.Node) string) {
+	sourceList := getNodeValue(m.source, key)
+	if sourceList == nil || sourceList.Kind != yaml.SequenceNode {
+		return
+	}
+
+	targetList := getNodeValue(m.target, key)
+	if targetList == nil {
+		m.target.Content = append(m.target.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"})
+		targetList = m.target.Content[len(m.target.Content)-1]
+	}
+	if targetList.Kind != yaml.SequenceNode {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range targetList.Content {
+		seen[id(item)] = true
+	}
+	for _, item := range sourceList.Content {
+		if itemID := id(item); !seen[itemID] {
+			seen[itemID] = true
+			targetList.Content = append(targetList.Content, item)
+		}
+	}
+}
+
+// ensureMappingValue returns the mapping stored under key, creating it if missing
+func ensureMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if value := getNodeValue(node, key); value != nil {
+		return value
+	}
+
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// ensureComponentSection returns the mapping of a component section, creating it if missing
+func ensureComponentSection(root *yaml.Node, section string) *yaml.Node {
+	if getNodeValue(root, "swagger") == nil {
+		return ensureMappingValue(ensureMappingValue(root, "components"), section)
+	}
+	return ensureMappingValue(root, swagger2Sections[section])
+}
+
+// sortedRenameEntries formats planned renames as sorted "section.Old -> New" entries, naming the
+// sections as the document does
+func sortedRenameEntries(root *yaml.Node, renames map[string]map[string]string) []string {
+	var entries []string
+	for section, plan := range renames {
+		_, name, _ := componentSection(root, section)
+		for oldName, newName := range plan {
+			entries = append(entries, fmt.Sprintf("%s.%s -> %s", name, oldName, newName))
+		}
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// countMergedContent records the size of the merged document
+func countMergedContent(root *yaml.Node, result *MergeResult) {
+	if paths := getNodeValue(root, "paths"); paths != nil {
+		result.Paths = len(paths.Content) / 2
+	}
+	for _, section := range documentComponentSections(root) {
+		if sectionNode, _, _ := componentSection(root, section); sectionNode != nil && sectionNode.Kind == yaml.MappingNode {
+			result.Components += len(sectionNode.Content) / 2
+		}
+	}
+	if tags := getNodeValue(root, "tags"); tags != nil {
+		result.Tags = len(tags.Content)
+	}
+	if servers := getNodeValue(root, "servers"); servers != nil {
+		result.Servers = len(servers.Content)
+	}
+}
@@ -0,0 +1,325 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mergeUsersSpec = `openapi: 3.0.0
+info:
+  title: Users API
+  version: 1.0.0
+servers:
+  - url: https://api.example.com
+tags:
+  - name: users
+paths:
+  /users:
+    get:
+      tags: [users]
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+    User:
+      type: object
+      properties:
+        id:
+          type: string
+`
+
+const mergeBillingSpec = `openapi: 3.0.0
+info:
+  title: Billing API
+  version: 2.0.0
+servers:
+  - url: https://api.example.com
+  - url: https://billing.example.com
+tags:
+  - name: billing
+paths:
+  /invoices:
+    get:
+      tags: [billing]
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Invoice'
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+    User:
+      type: object
+      properties:
+        email:
+          type: string
+    Invoice:
+      type: object
+      properties:
+        customer:
+          $ref: '#/components/schemas/User'
+`
+
+func writeMergeInputs(t *testing.T, specs ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	names := []string{"users.yaml", "billing-api.yaml", "third.yaml"}
+	paths := make([]string, 0, len(specs))
+	for i, spec := range specs {
+		path := filepath.Join(dir, names[i])
+		if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestMergeSpecs(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        MergeOptions
+		expectErr   string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:      "conflict errors by default",
+			opts:      MergeOptions{},
+			expectErr: "component schemas.User",
+		},
+		{
+			name:      "unknown strategy",
+			opts:      MergeOptions{ConflictStrategy: "merge-deep"},
+			expectErr: "unknown conflict strategy",
+		},
+		{
+			name: "rename with default prefix",
+			opts: MergeOptions{ConflictStrategy: MergeConflictRenamePrefix},
+			contains: []string{
+				"title: Users API",
+				"/users:",
+				"/invoices:",
+				"BillingApiUser:",
+				"$ref: '#/components/schemas/BillingApiUser'",
+				"- name: users",
+				"- name: billing",
+				"url: https://billing.example.com",
+			},
+			notContains: []string{"title: Billing API", "BillingApiError"},
+		},
+		{
+			name:     "rename with explicit prefixes",
+			opts:     MergeOptions{ConflictStrategy: MergeConflictRenamePrefix, Prefixes: []string{"Users", "Billing"}},
+			contains: []string{"BillingUser:"},
+		},
+		{
+			name:        "prefer first",
+			opts:        MergeOptions{ConflictStrategy: MergeConflictPreferFirst},
+			contains:    []string{"customer:\n                    $ref: '#/components/schemas/User'", "id:"},
+			notContains: []string{"email:"},
+		},
+		{
+			name:      "prefix count mismatch",
+			opts:      MergeOptions{ConflictStrategy: MergeConflictRenamePrefix, Prefixes: []string{"Users"}},
+			expectErr: "expected 2 prefixes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputs := writeMergeInputs(t, mergeUsersSpec, mergeBillingSpec)
+
+			output, result, err := MergeSpecsBytes(inputs, tt.opts, false)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Paths != 2 {
+				t.Errorf("expected 2 paths, got %d", result.Paths)
+			}
+
+			out := string(output)
+			for _, want := range tt.contains {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected output to contain %q\n%s", want, out)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(out, unwanted) {
+					t.Errorf("expected output not to contain %q\n%s", unwanted, out)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeSpecsOperationConflicts(t *testing.T) {
+	conflicting := strings.Replace(mergeUsersSpec, "description: Success", "description: Other", 1)
+	inputs := writeMergeInputs(t, mergeUsersSpec, conflicting)
+
+	if _, _, err := MergeSpecsBytes(inputs, MergeOptions{ConflictStrategy: MergeConflictRenamePrefix}, false); err == nil ||
+		!strings.Contains(err.Error(), "operation GET /users") {
+		t.Errorf("expected operation conflict error, got %v", err)
+	}
+
+	_, result, err := MergeSpecsBytes(inputs, MergeOptions{ConflictStrategy: MergeConflictPreferFirst}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.SkippedOperations) != 1 || !strings.HasSuffix(result.SkippedOperations[0], "GET /users") {
+		t.Errorf("expected GET /users to be skipped, got %v", result.SkippedOperations)
+	}
+}
+
+func TestMergeSpecsIdentical(t *testing.T) {
+	inputs := writeMergeInputs(t, mergeUsersSpec, mergeUsersSpec, mergeUsersSpec)
+
+	_, result, err := MergeSpecsBytes(inputs, MergeOptions{}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Paths != 1 || result.Components != 2 {
+		t.Errorf("expected 1 path and 2 components, got %d and %d", result.Paths, result.Components)
+	}
+	if result.DuplicateIdentical != 6 {
+		t.Errorf("expected 6 identical duplicates, got %d", result.DuplicateIdentical)
+	}
+}
+
+func TestMergeSpecsSwagger2(t *testing.T) {
+	first := `swagger: "2.0"
+info:
+  title: Users API
+  version: 1.0.0
+security:
+  - ApiKey: []
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: '#/definitions/User'
+definitions:
+  User:
+    type: object
+securityDefinitions:
+  ApiKey:
+    type: apiKey
+    in: header
+    name: X-Key
+`
+	second := `swagger: "2.0"
+info:
+  title: Billing API
+  version: 1.0.0
+paths:
+  /invoices:
+    get:
+      security:
+        - ApiKey: []
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: '#/definitions/Invoice'
+  /customers:
+    get:
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: '#/definitions/User'
+definitions:
+  Invoice:
+    type: object
+  User:
+    type: string
+securityDefinitions:
+  ApiKey:
+    type: apiKey
+    in: query
+    name: key
+`
+	inputs := writeMergeInputs(t, first, second)
+
+	output, result, err := MergeSpecsBytes(inputs, MergeOptions{ConflictStrategy: MergeConflictRenamePrefix}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		inputs[1] + ": definitions.User -> BillingApiUser",
+		inputs[1] + ": securityDefinitions.ApiKey -> BillingApiApiKey",
+	}
+	if strings.Join(result.RenamedComponents, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected renames %v, got %v", want, result.RenamedComponents)
+	}
+	if result.Components != 5 {
+		t.Errorf("expected 5 components, got %d", result.Components)
+	}
+	out := string(output)
+	for _, want := range []string{
+		"    Invoice:\n",
+		"    BillingApiUser:\n",
+		"$ref: '#/definitions/BillingApiUser'",
+		"- BillingApiApiKey: []",
+		"    BillingApiApiKey:\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q\n%s", want, out)
+		}
+	}
+}
+
+func TestMergeSpecsMajorVersions(t *testing.T) {
+	swagger := `swagger: "2.0"
+info:
+  title: Legacy API
+  version: 1.0.0
+paths: {}
+`
+	inputs := writeMergeInputs(t, mergeUsersSpec, swagger)
+	if _, _, err := MergeSpecsBytes(inputs, MergeOptions{}, false); err == nil ||
+		!strings.Contains(err.Error(), "is Swagger 2 but") {
+		t.Errorf("expected a major version error, got %v", err)
+	}
+}
+
+func TestPascalCaseName(t *testing.T) {
+	tests := map[string]string{
+		"billing-api": "BillingApi",
+		"users":       "Users",
+		"v2_orders":   "V2Orders",
+	}
+	for input, expected := range tests {
+		if got := pascalCaseName(input); got != expected {
+			t.Errorf("pascalCaseName(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package transform
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/developerkunal/OpenMorph/internal/telemetry"
+)
+
+// StepMetric records how long a pipeline step took and how many changes it made
+type StepMetric struct {
+	Step     string
+	Duration time.Duration
+	Changes  int
+}
+
+// FileMetric records how long a step took on one file and the file's size before and after it. In
+// a dry run BytesAfter is the size the step would have written.
+type FileMetric struct {
+	Step        string
+	File        string
+	Duration    time.Duration
+	BytesBefore int64
+	BytesAfter  int64
+}
+
+// SizeDelta returns how many bytes the step added to the file, negative when it shrank the file
+func (m FileMetric) SizeDelta() int64 {
+	return m.BytesAfter - m.BytesBefore
+}
+
+// fileMetrics collects the FileMetrics of a run. It is safe for concurrent use; a nil collector
+// records nothing.
+type fileMetrics struct {
+	mu      sync.Mutex
+	metrics []FileMetric
+	planned map[string]int64 // sizes dry-run steps would have written, by file
+}
+
+// newFileMetrics returns the collector of a pipeline run
+func newFileMetrics() *fileMetrics {
+	return &fileMetrics{planned: make(map[string]int64)}
+}
+
+// plan notes the size a dry-run step would have written to path
+func (m *fileMetrics) plan(path string, size int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.planned[path] = int64(size)
+}
+
+// record notes how long step took on path, given the size path had before it
+func (m *fileMetrics) record(step, path string, duration time.Duration, before int64, changed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	after := before
+	if size, ok := m.planned[path]; ok {
+		after = size
+		delete(m.planned, path)
+	} else if changed {
+		after = fileSize(path)
+	}
+	m.metrics = append(m.metrics, FileMetric{Step: step, File: path, Duration: duration, BytesBefore: before, BytesAfter: after})
+}
+
+// list returns the recorded metrics in the order the files were processed
+func (m *fileMetrics) list() []FileMetric {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]FileMetric(nil), m.metrics...)
+}
+
+// normalizeFileMetrics reports the metrics recorded on a temporary copy against inputPath
+func normalizeFileMetrics(inputPath string, results *TransformationResults) {
+	for i := range results.FileMetrics {
+		results.FileMetrics[i].File = inputPath
+	}
+}
+
+// fileSize returns the size of path, 0 when it cannot be read
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// context returns the context the step's per-file spans are started in
+func (o Options) context() context.Context {
+	if o.Context == nil {
+		return context.Background()
+	}
+	return o.Context
+}
+
+// traceFile processes one file of a step inside a span, reporting it to opts.Progress and recording
+// a FileMetric for it. In a keep-going run an error is recorded instead of returned, and a file that
+// failed before is left alone.
+func traceFile(opts Options, step, path string, process func() (bool, error)) (bool, error) {
+	if opts.failures.has(path) {
+		return false, nil
+	}
+	_, span := telemetry.StartFile(opts.context(), step, path)
+	opts.Progress.StartFile(path)
+	var before int64
+	if opts.metrics != nil {
+		before = fileSize(path)
+	}
+	start := time.Now()
+	changed, err := process()
+	if opts.metrics != nil {
+		opts.metrics.record(step, path, time.Since(start), before, changed)
+	}
+	opts.Progress.FinishFile()
+	telemetry.EndFile(span, changed, err)
+	if err != nil && opts.failures != nil {
+		opts.failures.record(step, path, err)
+		return false, nil
+	}
+	return changed, err
+}
+
+// runStep runs one pipeline step inside a span and records a StepMetric for it when the step ran
+func runStep(step string, opts Options, results *TransformationResults, apply func(Options) error) error {
+	ctx, span := telemetry.StartStep(opts.context(), step)
+	opts.Context = ctx
+	opts.Progress.StartStep(step)
+
+	start := time.Now()
+	err := apply(opts)
+	duration := time.Since(start)
+	opts.Progress.FinishStep()
+
+	changes, ran := stepChanges(step, results)
+	if ran {
+		results.StepMetrics = append(results.StepMetrics, StepMetric{Step: step, Duration: duration, Changes: changes})
+	}
+	telemetry.EndStep(span, changes, err)
+	return err
+}
+
+// stepChanges counts the changes a step recorded in results; ran is false when the step was disabled
+func stepChanges(step string, results *TransformationResults) (changes int, ran bool) {
+	switch step {
+	case StepMappings:
+		return len(results.KeyChanges), true
+	case StepStripInternal:
+		if r := results.InternalResult; r != nil {
+			return countEntries(r.RemovedItems) + countEntries(r.PrunedComponents) + countEntries(r.SecondaryRemovals), true
+		}
+	case StepPathVariants:
+		if r := results.PathVariantsResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepTenantPaths:
+		if r := results.TenantPathsResult; r != nil {
+			return len(r.Locations) + countEntries(r.ChangedComponents), true
+		}
+	case StepPagination:
+		if r := results.PaginationResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepFlatten:
+		if r := results.FlattenResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepUnwrapEnvelopes:
+		if r := results.EnvelopeResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepParameterInjection:
+		if r := results.InjectionResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepVendorExtensions:
+		if r := results.VendorResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepExtensionValues:
+		if r := results.ExtensionValuesResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepDefaults:
+		if r := results.DefaultsResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepSchemaConstraints:
+		if r := results.ConstraintsResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepNullability:
+		if r := results.NullabilityResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepComponentDedup:
+		if r := results.DedupResult; r != nil {
+			return countEntries(r.MergedComponents), true
+		}
+	case StepComponentRenames:
+		if r := results.RenameResult; r != nil {
+			return countEntries(r.RenamedComponents), true
+		}
+	case StepSchemaTitles:
+		if r := results.SchemaTitlesResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepComponentConsistency:
+		if r := results.ConsistencyResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepExamples:
+		if r := results.ExamplesResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepCanonicalize:
+		if r := results.CanonicalizeResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepLinkSync:
+		if r := results.LinkResult; r != nil {
+			return len(r.Locations), true
+		}
+	case StepArazzoSync:
+		if r := results.ArazzoResult; r != nil {
+			return countEntries(r.UpdatedReferences), true
+		}
+	}
+	return 0, false
+}
+
+// countEntries counts the values of a file -> entries result map
+func countEntries(entries map[string][]string) int {
+	count := 0
+	for _, values := range entries {
+		count += len(values)
+	}
+	return count
+}
@@ -0,0 +1,115 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestExecuteFullPipeline_StepMetrics(t *testing.T) {
+	dir := t.TempDir()
+	spec := `openapi: 3.0.0
+info:
+  title: Test
+  version: 1.0.0
+x-old: value
+paths: {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Mappings:      map[string]string{"x-old": "x-new"},
+		StripInternal: config.StripInternal{Enabled: true},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatalf("ExecuteFullPipeline failed: %v", err)
+	}
+
+	var steps []string
+	for _, metric := range results.StepMetrics {
+		steps = append(steps, metric.Step)
+	}
+	want := []string{StepMappings, StepStripInternal}
+	if len(steps) != len(want) || steps[0] != want[0] || steps[1] != want[1] {
+		t.Fatalf("expected metrics for %v, got %v", want, steps)
+	}
+	if results.StepMetrics[0].Changes != 1 {
+		t.Errorf("expected 1 mapping change, got %d", results.StepMetrics[0].Changes)
+	}
+}
+
+func TestStepChanges(t *testing.T) {
+	results := &TransformationResults{
+		DedupResult: &DedupResult{MergedComponents: map[string][]string{"a.yaml": {"A", "B"}, "b.yaml": {"C"}}},
+	}
+	if changes, ran := stepChanges(StepComponentDedup, results); !ran || changes != 3 {
+		t.Errorf("expected dedup to report 3 changes, got %d (ran=%v)", changes, ran)
+	}
+	if _, ran := stepChanges(StepFlatten, results); ran {
+		t.Error("expected flatten to be reported as not run")
+	}
+}
+
+func TestExecuteFullPipeline_FileMetrics(t *testing.T) {
+	// Indented the way the YAML encoder writes it, so only the renamed key changes the size
+	spec := `openapi: 3.0.0
+info:
+    title: Test
+    version: 1.0.0
+x-old: value
+paths:
+    /users:
+        get:
+            x-internal: true
+            responses:
+                "200":
+                    description: OK
+`
+	cfg := &config.Config{
+		Mappings:      map[string]string{"x-old": "x-renamed"},
+		StripInternal: config.StripInternal{Enabled: true},
+	}
+
+	for _, dryRun := range []bool{false, true} {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "api.yaml")
+		if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+			t.Fatal(err)
+		}
+		results, err := NewTransformationPipeline(cfg, nil, dryRun, false, "").ExecuteFullPipeline(dir)
+		if err != nil {
+			t.Fatalf("ExecuteFullPipeline(dryRun=%v) failed: %v", dryRun, err)
+		}
+
+		metrics := make(map[string]FileMetric)
+		for _, metric := range results.FileMetrics {
+			if metric.File != path {
+				t.Errorf("unexpected file %s", metric.File)
+			}
+			metrics[metric.Step] = metric
+		}
+		mappings, ok := metrics[StepMappings]
+		if !ok || mappings.BytesBefore != int64(len(spec)) || mappings.SizeDelta() != 4 {
+			t.Errorf("dryRun=%v: expected the mappings step to grow the file by 4 bytes, got %+v", dryRun, mappings)
+		}
+		internal, ok := metrics[StepStripInternal]
+		if !ok || internal.SizeDelta() >= 0 {
+			t.Errorf("dryRun=%v: expected stripping internal content to shrink the file, got %+v", dryRun, internal)
+		}
+		if dryRun {
+			if data, _ := os.ReadFile(path); string(data) != spec {
+				t.Error("expected the dry run to leave the file alone")
+			}
+			if internal.BytesBefore != int64(len(spec)) {
+				t.Errorf("expected dry-run steps to start from the original file, got %+v", internal)
+			}
+		} else if internal.BytesBefore != mappings.BytesAfter {
+			t.Errorf("expected steps to build on each other, got %+v after %+v", internal, mappings)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Name casings, shared by generated component names and tag groups
+const (
+	CasingSnake  = "snake"
+	CasingKebab  = "kebab"
+	CasingCamel  = "camel"
+	CasingPascal = "pascal"
+)
+
+// DefaultReservedSuffix is appended to generated component names that are reserved words
+const DefaultReservedSuffix = "Model"
+
+// reservedComponentNames are type names SDK generators commonly reserve, compared case-insensitively
+var reservedComponentNames = []string{
+	"any", "array", "boolean", "class", "date", "error", "exception", "integer",
+	"list", "map", "null", "number", "object", "string", "type", "void",
+}
+
+var componentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+var templatePlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// ValidateComponentNaming checks the casing, the reserved suffix and the collision suffix of the
+// component naming settings
+func ValidateComponentNaming(naming config.ComponentNaming) error {
+	switch naming.Casing {
+	case "", CasingSnake, CasingKebab, CasingCamel, CasingPascal:
+	default:
+		return fmt.Errorf("component_naming.casing must be %s, %s, %s or %s, got %q",
+			CasingPascal, CasingCamel, CasingSnake, CasingKebab, naming.Casing)
+	}
+	if naming.ReservedSuffix != "" && !componentNamePattern.MatchString(naming.ReservedSuffix) {
+		return fmt.Errorf("component_naming.reserved_suffix %q is not valid in a component name", naming.ReservedSuffix)
+	}
+	if naming.CollisionSuffix != "" {
+		if !strings.Contains(naming.CollisionSuffix, "{n}") {
+			return fmt.Errorf("component_naming.collision_suffix must contain {n}, got %q", naming.CollisionSuffix)
+		}
+		if suffix := strings.ReplaceAll(naming.CollisionSuffix, "{n}", "2"); !componentNamePattern.MatchString(suffix) {
+			return fmt.Errorf("component_naming.collision_suffix %q is not valid in a component name", naming.CollisionSuffix)
+		}
+	}
+	return nil
+}
+
+// validateNameTemplate checks that a name template of the given config field only uses the
+// variables in vars, includes every required one, and yields valid component names
+func validateNameTemplate(field, template string, vars []string, required ...string) error {
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(template, -1) {
+		if !contains(vars, strings.ToLower(match[1])) {
+			return fmt.Errorf("%s: unknown placeholder {%s}", field, match[1])
+		}
+	}
+	for _, variable := range required {
+		if !strings.Contains(strings.ToLower(template), "{"+variable+"}") {
+			return fmt.Errorf("%s must contain {%s}, got %q", field, capitalize(variable), template)
+		}
+	}
+
+	sample := make(map[string]string, len(vars))
+	for _, variable := range vars {
+		sample[variable] = "sample"
+	}
+	if name := renderNameTemplate(template, sample); !componentNamePattern.MatchString(name) {
+		return fmt.Errorf("%s yields invalid component names such as %q", field, name)
+	}
+	return nil
+}
+
+// renderNameTemplate replaces the {variable} placeholders of a name template with their values; a
+// capitalized placeholder such as {Strategy} capitalizes the first letter of the value
+func renderNameTemplate(template string, vars map[string]string) string {
+	return templatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		value, ok := vars[strings.ToLower(name)]
+		if !ok {
+			return placeholder
+		}
+		if name != strings.ToLower(name) {
+			return capitalize(value)
+		}
+		return value
+	})
+}
+
+// componentNamer names the components generated by the steps, so every step renders templates,
+// applies casing, avoids reserved words and resolves collisions the same way
+type componentNamer struct {
+	naming config.ComponentNaming
+}
+
+// newComponentNamer returns a namer for the given naming settings
+func newComponentNamer(naming config.ComponentNaming) componentNamer {
+	return componentNamer{naming: naming}
+}
+
+// name renders a name template with vars in the configured casing, suffixing reserved words
+func (n componentNamer) name(template string, vars map[string]string) string {
+	name := renderNameTemplate(template, vars)
+	if n.naming.Casing != "" {
+		name = applyCasing(name, n.naming.Casing)
+	}
+	if n.reserved(name) {
+		suffix := n.naming.ReservedSuffix
+		if suffix == "" {
+			suffix = DefaultReservedSuffix
+		}
+		name += suffix
+	}
+	return name
+}
+
+// reserved reports whether name is a built-in or configured reserved word
+func (n componentNamer) reserved(name string) bool {
+	for _, words := range [][]string{reservedComponentNames, n.naming.ReservedWords} {
+		for _, word := range words {
+			if strings.EqualFold(word, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// claim returns the name under which a generated component belongs in section: name itself when it
+// is free or already holds a component for which same is true, or else the first suffixed name
+// that is. Without a collision suffix, ok is false when name holds a different component.
+func (n componentNamer) claim(section *yaml.Node, name string, same func(existing *yaml.Node) bool) (string, bool) {
+	candidate := name
+	for i := 2; ; i++ {
+		existing := getNodeValue(section, candidate)
+		if existing == nil || same(existing) {
+			return candidate, true
+		}
+		if n.naming.CollisionSuffix == "" {
+			return name, false
+		}
+		candidate = name + strings.ReplaceAll(n.naming.CollisionSuffix, "{n}", fmt.Sprint(i))
+	}
+}
+
+// applyCasing rewrites s in a casing, splitting it into words at separators and case changes
+func applyCasing(s, casing string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return s
+	}
+	switch casing {
+	case CasingSnake:
+		return strings.ToLower(strings.Join(words, "_"))
+	case CasingKebab:
+		return strings.ToLower(strings.Join(words, "-"))
+	case CasingCamel, CasingPascal:
+		var b strings.Builder
+		for i, word := range words {
+			runes := []rune(strings.ToLower(word))
+			if i > 0 || casing == CasingPascal {
+				runes[0] = unicode.ToUpper(runes[0])
+			}
+			b.WriteString(string(runes))
+		}
+		return b.String()
+	default:
+		return s
+	}
+}
+
+// splitWords splits a name into words at separators and lower-to-upper case changes, so "Billing &
+// Invoices", "billing_invoices" and "BillingInvoices" all become Billing, Invoices
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,105 @@
+package transform
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestRenderNameTemplate(t *testing.T) {
+	vars := map[string]string{"schema": "user", "strategy": "cursor"}
+	tests := []struct {
+		template, want string
+	}{
+		{"{schema}{Strategy}Page", "userCursorPage"},
+		{"{Schema}_{strategy}", "User_cursor"},
+		{"{Schema}{Other}", "User{Other}"},
+	}
+	for _, tt := range tests {
+		if got := renderNameTemplate(tt.template, vars); got != tt.want {
+			t.Errorf("renderNameTemplate(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestComponentNamerName(t *testing.T) {
+	vars := map[string]string{"strategy": "cursor", "item": "user_account"}
+	tests := []struct {
+		naming   config.ComponentNaming
+		template string
+		want     string
+	}{
+		{config.ComponentNaming{}, "{Strategy}Page_{Item}", "CursorPage_User_account"},
+		{config.ComponentNaming{Casing: CasingPascal}, "{Strategy}Page_{Item}", "CursorPageUserAccount"},
+		{config.ComponentNaming{Casing: CasingSnake}, "{Strategy}Page_{Item}", "cursor_page_user_account"},
+		{config.ComponentNaming{Casing: CasingCamel}, "{item}", "userAccount"},
+		{config.ComponentNaming{}, "Object", "ObjectModel"},
+		{config.ComponentNaming{ReservedWords: []string{"CursorPage"}, ReservedSuffix: "Dto"}, "{Strategy}Page", "CursorPageDto"},
+	}
+	for _, tt := range tests {
+		if got := newComponentNamer(tt.naming).name(tt.template, vars); got != tt.want {
+			t.Errorf("name(%+v, %q) = %q, want %q", tt.naming, tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestComponentNamerClaim(t *testing.T) {
+	var section yaml.Node
+	if err := yaml.Unmarshal([]byte("Page: {type: string}\nPage_2: {type: integer}\n"), &section); err != nil {
+		t.Fatal(err)
+	}
+	schemas := section.Content[0]
+	isType := func(typ string) func(*yaml.Node) bool {
+		return func(existing *yaml.Node) bool { return getStringValue(existing, "type") == typ }
+	}
+
+	suffixing := newComponentNamer(config.ComponentNaming{CollisionSuffix: "_{n}"})
+	tests := []struct {
+		namer  componentNamer
+		typ    string
+		want   string
+		wantOK bool
+	}{
+		{suffixing, "string", "Page", true},
+		{suffixing, "integer", "Page_2", true},
+		{suffixing, "object", "Page_3", true},
+		{componentNamer{}, "object", "Page", false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.namer.claim(schemas, "Page", isType(tt.typ))
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("claim for %s = %q, %v, want %q, %v", tt.typ, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestValidateComponentNaming(t *testing.T) {
+	valid := config.ComponentNaming{Casing: CasingKebab, ReservedSuffix: "Model", CollisionSuffix: "_{n}"}
+	if err := ValidateComponentNaming(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, naming := range []config.ComponentNaming{
+		{Casing: "upper"},
+		{ReservedSuffix: "My Model"},
+		{CollisionSuffix: "_copy"},
+		{CollisionSuffix: " {n}"},
+	} {
+		if err := ValidateComponentNaming(naming); err == nil {
+			t.Errorf("expected an error for %+v", naming)
+		}
+	}
+}
+
+func TestValidateNameTemplate(t *testing.T) {
+	vars := []string{"strategy", "item"}
+	if err := validateNameTemplate("name_template", "{Item}{Strategy}List", vars, "item"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, template := range []string{"{Strategy}Page", "{Item}{Schema}", "{Item} Page"} {
+		if err := validateNameTemplate("name_template", template, vars, "item"); err == nil {
+			t.Errorf("expected an error for %q", template)
+		}
+	}
+}
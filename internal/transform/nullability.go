@@ -0,0 +1,286 @@
+package transform
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// NullableOptionalProperties is the nullability.optional_properties policy that marks every
+// optional object property nullable
+const NullableOptionalProperties = "nullable"
+
+// NullabilityOptions extends the regular Options with nullability policy settings
+type NullabilityOptions struct {
+	Options
+	Nullability config.Nullability
+}
+
+// NullabilityResult represents the result of nullability policy enforcement
+type NullabilityResult struct {
+	Changed            bool
+	ProcessedFiles     []string
+	NullabilityChanges map[string][]string // file -> list of nullability changes
+	Locations          []ChangeLocation    // source positions of the changed schemas
+}
+
+// createNullabilityResult creates a new NullabilityResult with initialized maps
+func createNullabilityResult() *NullabilityResult {
+	return &NullabilityResult{
+		ProcessedFiles:     []string{},
+		NullabilityChanges: make(map[string][]string),
+	}
+}
+
+// setNullabilityProcessedFiles sets the processed files for a NullabilityResult
+func setNullabilityProcessedFiles(result *NullabilityResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setNullabilityChanged sets the changed flag for a NullabilityResult
+func setNullabilityChanged(result *NullabilityResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessNullabilityInDir enforces the nullability policy in all OpenAPI files in a directory
+func ProcessNullabilityInDir(dir string, opts NullabilityOptions) (*NullabilityResult, error) {
+	if err := ValidateNullability(opts.Nullability); err != nil {
+		return createNullabilityResult(), err
+	}
+
+	policy := opts.Nullability
+	return processTransformInDir(
+		dir,
+		StepNullability,
+		opts.Options,
+		policy.Enabled,
+		policy.OptionalProperties == "" && !policy.Strip && policy.Form == "",
+		createNullabilityResult,
+		func(path string, result *NullabilityResult) (bool, error) {
+			return processNullabilityInFile(path, opts, result)
+		},
+		setNullabilityProcessedFiles,
+		setNullabilityChanged,
+	)
+}
+
+// ValidateNullability checks the policy values and that stripping is not combined with adding
+// nullability
+func ValidateNullability(policy config.Nullability) error {
+	switch policy.OptionalProperties {
+	case "", NullableOptionalProperties:
+	default:
+		return fmt.Errorf("nullability.optional_properties must be %q, got %q", NullableOptionalProperties, policy.OptionalProperties)
+	}
+	switch policy.Form {
+	case "", SchemaFormAuto, SchemaForm30, SchemaForm31:
+	default:
+		return fmt.Errorf("nullability.form must be %s, %s or %s, got %q", SchemaFormAuto, SchemaForm30, SchemaForm31, policy.Form)
+	}
+	if policy.Strip && policy.OptionalProperties != "" {
+		return fmt.Errorf("nullability.strip cannot be combined with optional_properties: %s", policy.OptionalProperties)
+	}
+	return nil
+}
+
+// processNullabilityInFile enforces the nullability policy in a single file
+func processNullabilityInFile(path string, opts NullabilityOptions, result *NullabilityResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	e := &nullabilityEnforcer{
+		policy:       opts.Nullability,
+		form:         schemaForm(root, opts.Nullability.Form),
+		documentForm: schemaForm(root, SchemaFormAuto),
+		path:         path,
+		result:       result,
+	}
+	if !walkDocumentSchemas(root, e.enforce) {
+		return false, nil
+	}
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// nullabilityEnforcer applies the nullability policy to the schemas of one document
+type nullabilityEnforcer struct {
+	policy       config.Nullability
+	form         string // form to convert to, empty to keep
+	documentForm string // form new nullability is written in when form is empty
+	path         string
+	result       *NullabilityResult
+}
+
+// enforce applies the policy to a single schema: strip or add nullability, then convert its form
+func (e *nullabilityEnforcer) enforce(schema *yaml.Node, target schemaTarget) bool {
+	changed := false
+	if e.policy.Strip && e.strip(schema, target) {
+		changed = true
+	}
+	if e.policy.OptionalProperties == NullableOptionalProperties && target.property && !target.required &&
+		e.makeNullable(schema, target) {
+		changed = true
+	}
+	if e.form != "" && e.convert(schema, target) {
+		changed = true
+	}
+	return changed
+}
+
+// strip removes nullable and the "null" type from a schema
+func (e *nullabilityEnforcer) strip(schema *yaml.Node, target schemaTarget) bool {
+	changed := false
+	if getNodeValue(schema, "nullable") != nil {
+		removeMappingKey(schema, "nullable")
+		changed = true
+	}
+	if types := getNodeValue(schema, "type"); types != nil && types.Kind == yaml.SequenceNode && removeNullType(schema, types) {
+		changed = true
+	}
+	if !changed {
+		return false
+	}
+	removeNullEnumValue(schema)
+	e.record(schema, fmt.Sprintf("%s: nullability removed", target.context))
+	return true
+}
+
+// makeNullable marks a typed schema nullable in the configured form, or the document's form
+func (e *nullabilityEnforcer) makeNullable(schema *yaml.Node, target schemaTarget) bool {
+	types := getNodeValue(schema, "type")
+	if types == nil || isNullableSchema(schema) {
+		return false
+	}
+
+	form := e.form
+	if form == "" {
+		form = e.documentForm
+	}
+	if form == SchemaForm31 {
+		addNullType(schema, types)
+	} else {
+		if types.Kind == yaml.SequenceNode {
+			return false // type lists cannot be expressed in the 3.0 form
+		}
+		schema.Content = append(schema.Content, newScalarNode("nullable"), newBoolNode(true))
+	}
+	e.record(schema, fmt.Sprintf("%s: optional property made nullable", target.context))
+	return true
+}
+
+// convert rewrites nullability into the configured form: nullable: true for 3.0, a "null" type for 3.1
+func (e *nullabilityEnforcer) convert(schema *yaml.Node, target schemaTarget) bool {
+	types := getNodeValue(schema, "type")
+	switch e.form {
+	case SchemaForm31:
+		nullable := getNodeValue(schema, "nullable")
+		if nullable == nil {
+			return false
+		}
+		removeMappingKey(schema, "nullable")
+		if nullable.Value == "true" && types != nil {
+			addNullType(schema, types)
+		}
+	case SchemaForm30:
+		if types == nil || types.Kind != yaml.SequenceNode || len(types.Content) != 2 || !removeNullType(schema, types) {
+			return false // only [T, "null"] has a 3.0 equivalent
+		}
+		insertAfterKey(schema, "type", newScalarNode("nullable"), newBoolNode(true))
+	default:
+		return false
+	}
+	e.record(schema, fmt.Sprintf("%s: nullability converted to OpenAPI %s form", target.context, e.form))
+	return true
+}
+
+// record adds a nullability change to the result
+func (e *nullabilityEnforcer) record(schema *yaml.Node, entry string) {
+	e.result.NullabilityChanges[e.path] = append(e.result.NullabilityChanges[e.path], entry)
+	e.result.Locations = append(e.result.Locations, newChangeLocation(e.path, StepNullability, schema, entry))
+}
+
+// isNullableSchema reports whether a schema allows null in either form
+func isNullableSchema(schema *yaml.Node) bool {
+	return getStringValue(schema, "nullable") == "true" || hasSchemaType(schema, "null")
+}
+
+// addNullType adds "null" to a schema's type, turning a single type into a list, and to its enum
+// along with the extensions listing one entry per enum value
+func addNullType(schema, types *yaml.Node) {
+	if types.Kind == yaml.ScalarNode {
+		types = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle, Content: []*yaml.Node{types}}
+		setMappingValue(schema, "type", types)
+	}
+	types.Content = append(types.Content, newScalarNode("null"))
+
+	if enum := getNodeValue(schema, "enum"); enum != nil && enum.Kind == yaml.SequenceNode {
+		for _, value := range enum.Content {
+			if value.Tag == "!!null" {
+				return
+			}
+		}
+		values := append(append([]*yaml.Node{}, enum.Content...), &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"})
+		replaceEnumValues(schema, enum, values)
+	}
+}
+
+// removeNullType removes "null" from a type list, collapsing a single remaining type to a scalar
+func removeNullType(schema, types *yaml.Node) bool {
+	var kept []*yaml.Node
+	for _, item := range types.Content {
+		if item.Value != "null" {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) == len(types.Content) || len(kept) == 0 {
+		return false
+	}
+	if len(kept) == 1 {
+		setMappingValue(schema, "type", kept[0])
+	} else {
+		types.Content = kept
+	}
+	return true
+}
+
+// removeNullEnumValue removes null from a schema's enum and the extensions listing one entry per
+// enum value
+func removeNullEnumValue(schema *yaml.Node) {
+	enum := getNodeValue(schema, "enum")
+	if enum == nil || enum.Kind != yaml.SequenceNode {
+		return
+	}
+	var kept []*yaml.Node
+	for _, value := range enum.Content {
+		if value.Tag != "!!null" {
+			kept = append(kept, value)
+		}
+	}
+	replaceEnumValues(schema, enum, kept)
+}
+
+// insertAfterKey inserts a key/value pair right after key in a mapping node, or appends it when
+// key is missing
+func insertAfterKey(node *yaml.Node, key string, newKey, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			rest := append([]*yaml.Node{newKey, value}, node.Content[i+2:]...)
+			node.Content = append(node.Content[:i+2], rest...)
+			return
+		}
+	}
+	node.Content = append(node.Content, newKey, value)
+}
+
+func newBoolNode(value bool) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(value)}
+}
@@ -0,0 +1,143 @@
+package transform
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const nullabilityTestSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                nickname:
+                  type: string
+      responses:
+        "201":
+          description: Created
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        status:
+          type: string
+          enum: [active, disabled]
+        deleted_at:
+          type: string
+          nullable: true
+`
+
+func runNullability(t *testing.T, spec string, policy config.Nullability) (*NullabilityResult, string, string) {
+	t.Helper()
+	dir, path := writeTestSpec(t, spec)
+	policy.Enabled = true
+	result, err := ProcessNullabilityInDir(dir, NullabilityOptions{Nullability: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	return result, path, string(data)
+}
+
+func TestNullabilityOptionalProperties(t *testing.T) {
+	result, path, content := runNullability(t, nullabilityTestSpec, config.Nullability{OptionalProperties: NullableOptionalProperties})
+
+	want := []string{
+		"POST /users requestBody application/json property nickname: optional property made nullable",
+		"component schemas.User property status: optional property made nullable",
+	}
+	if got := result.NullabilityChanges[path]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected changes:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	if strings.Count(content, "nullable: true") != 3 {
+		t.Errorf("expected three nullable properties, got:\n%s", content)
+	}
+}
+
+func TestNullabilityConvertTo31(t *testing.T) {
+	result, path, content := runNullability(t, nullabilityTestSpec, config.Nullability{OptionalProperties: NullableOptionalProperties, Form: SchemaForm31})
+
+	if got := result.NullabilityChanges[path]; len(got) != 3 {
+		t.Errorf("expected three changes, got %v", got)
+	}
+	if strings.Contains(content, "nullable:") {
+		t.Errorf("expected no nullable keywords in the 3.1 form, got:\n%s", content)
+	}
+	for _, s := range []string{`type: [string, "null"]`, "enum: [active, disabled, null]"} {
+		if !strings.Contains(content, s) {
+			t.Errorf("expected output to contain %q, got:\n%s", s, content)
+		}
+	}
+}
+
+func TestNullabilityEnumExtensions(t *testing.T) {
+	spec := "openapi: 3.1.0\ncomponents:\n  schemas:\n    Status:\n      type: [string, \"null\"]\n      enum: [null, active]\n" +
+		"      x-enum-varnames: [NONE, ACTIVE]\n      x-enum-descriptions: [Not set, In use]\n"
+	_, _, content := runNullability(t, spec, config.Nullability{Strip: true})
+	for _, s := range []string{"enum: [active]", "x-enum-varnames: [ACTIVE]", "x-enum-descriptions: [In use]"} {
+		if !strings.Contains(content, s) {
+			t.Errorf("expected output to contain %q, got:\n%s", s, content)
+		}
+	}
+
+	spec = "openapi: 3.0.3\ncomponents:\n  schemas:\n    Status:\n      type: string\n      nullable: true\n      enum: [active]\n" +
+		"      x-enum-varnames: [ACTIVE]\n"
+	_, _, content = runNullability(t, spec, config.Nullability{Form: SchemaForm31})
+	if !strings.Contains(content, "x-enum-varnames: [ACTIVE, \"NULL\"]") {
+		t.Errorf("expected a name for the null enum value, got:\n%s", content)
+	}
+}
+
+func TestNullabilityConvertTo30(t *testing.T) {
+	spec := "openapi: 3.1.0\ncomponents:\n  schemas:\n    Tag:\n      type: [string, \"null\"]\n      description: A tag\n"
+	_, _, content := runNullability(t, spec, config.Nullability{Form: SchemaForm30})
+
+	if !strings.Contains(content, "type: string\n            nullable: true\n            description: A tag") {
+		t.Errorf("expected nullable: true after the type, got:\n%s", content)
+	}
+}
+
+func TestNullabilityStrip(t *testing.T) {
+	spec := "openapi: 3.1.0\ncomponents:\n  schemas:\n    Tag:\n      type: [string, \"null\"]\n      enum: [a, null]\n    Old:\n      type: integer\n      nullable: true\n"
+	result, path, content := runNullability(t, spec, config.Nullability{Strip: true})
+
+	if got := result.NullabilityChanges[path]; len(got) != 2 {
+		t.Errorf("expected two changes, got %v", got)
+	}
+	if strings.Contains(content, "null") {
+		t.Errorf("expected nullability to be removed, got:\n%s", content)
+	}
+}
+
+func TestValidateNullability(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy config.Nullability
+		want   string
+	}{
+		{"bad optional policy", config.Nullability{OptionalProperties: "required"}, "optional_properties must be"},
+		{"bad form", config.Nullability{Form: "3.2"}, "form must be"},
+		{"strip and add", config.Nullability{Strip: true, OptionalProperties: NullableOptionalProperties}, "cannot be combined"},
+	}
+	for _, tt := range tests {
+		err := ValidateNullability(tt.policy)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package transform
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// yaml11ScalarPattern matches the plain strings YAML 1.1 readers take for booleans or sexagesimal
+// numbers, which YAML 1.2 reads as strings
+var yaml11ScalarPattern = regexp.MustCompile(`^(?:y|Y|yes|Yes|YES|n|N|no|No|NO|on|On|ON|off|Off|OFF|[-+]?[0-9][0-9_]*(?::[0-5]?[0-9])+(?:\.[0-9_]*)?)$`)
+
+// Serializations a transformed document can be written in
+const (
+	OutputFormatYAML = "yaml"
+	OutputFormatJSON = "json"
+)
+
+// ValidateOutputFormats checks that output_formats only lists yaml and json, each once
+func ValidateOutputFormats(formats []string) error {
+	seen := make(map[string]bool)
+	for i, format := range formats {
+		if format != OutputFormatYAML && format != OutputFormatJSON {
+			return fmt.Errorf("output_formats[%d] must be %s or %s, got %q", i, OutputFormatYAML, OutputFormatJSON, format)
+		}
+		if seen[format] {
+			return fmt.Errorf("output_formats[%d]: duplicate format %s", i, format)
+		}
+		seen[format] = true
+	}
+	return nil
+}
+
+// documentFormat returns the serialization of the document at path
+func documentFormat(path string) string {
+	if IsJSON(path) {
+		return OutputFormatJSON
+	}
+	return OutputFormatYAML
+}
+
+// formatSibling returns the path next to path that holds its document in format
+func formatSibling(path, format string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + "." + format
+}
+
+// hasYAMLSource reports whether a JSON document has a YAML document of the same name next to it
+func hasYAMLSource(path string) bool {
+	if !IsJSON(path) {
+		return false
+	}
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, ext := range []string{".yaml", ".yml"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFormatSiblings writes the OpenAPI document at path in every format other than its own,
+// next to it, and returns the files written. The siblings are serialized from the final node tree
+// of the document, so they never drift from it.
+func writeFormatSiblings(path string, formats []string) ([]string, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return writeDocumentFormats(doc, path, formats)
+}
+
+// writeDocumentFormats writes doc, read from path, in every format other than its own
+func writeDocumentFormats(doc *yaml.Node, path string, formats []string) ([]string, error) {
+	if !isOpenAPIDocument(getRootNode(doc)) {
+		return nil, nil
+	}
+
+	var written []string
+	for _, format := range formats {
+		if format == documentFormat(path) {
+			continue
+		}
+		if format == OutputFormatYAML {
+			// JSON parses into flow style with quoted strings, which YAML readers should not get
+			clearStyles(doc)
+		}
+		target := formatSibling(path, format)
+		output, err := formatDocument(doc, target)
+		if err != nil {
+			return written, fmt.Errorf("failed to format %s: %v", target, err)
+		}
+		if err := os.WriteFile(target, output, 0600); err != nil {
+			return written, fmt.Errorf("failed to write %s: %v", target, err)
+		}
+		written = append(written, target)
+	}
+	return written, nil
+}
+
+// writeOutputFormats writes every OpenAPI document under inputPath in the other formats listed.
+// When a document exists both as YAML and JSON, the YAML one is the source and the JSON one is
+// rewritten from it, so siblings written by an earlier run are never used as a source. Documents
+// that do not parse were left alone by the steps and are left out here too.
+func writeOutputFormats(inputPath string, formats []string, files config.FileFilter) ([]string, error) {
+	if len(formats) == 0 {
+		return nil, nil
+	}
+
+	var sources []string
+	err := filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(IsYAML(path) || IsJSON(path)) || !IncludesFile(files, "", inputPath, path) {
+			return nil
+		}
+		if reason, err := sniffFile(path); err != nil || reason != skipReasonNone || hasYAMLSource(path) {
+			return nil
+		}
+		sources = append(sources, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for output formats: %v", err)
+	}
+
+	var written []string
+	for _, path := range sources {
+		doc, err := loadAndParseDocument(path)
+		if err != nil {
+			continue
+		}
+		siblings, err := writeDocumentFormats(doc, path, formats)
+		written = append(written, siblings...)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// clearStyles resets node and its children to the default block style. Strings that YAML 1.1
+// readers take for booleans or numbers, such as yes or on, stay quoted.
+func clearStyles(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!str" && yaml11ScalarPattern.MatchString(node.Value) {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		clearStyles(child)
+	}
+}
@@ -0,0 +1,133 @@
+package transform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const outputFormatsSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-operation-group-name: users
+      responses:
+        "200":
+          description: OK
+`
+
+func TestOutputFormatsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(spec, []byte(outputFormatsSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Not an OpenAPI document, so no sibling is written for it
+	if err := os.WriteFile(filepath.Join(dir, "notes.yaml"), []byte("title: notes\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Mappings:      map[string]string{"x-operation-group-name": "x-group"},
+		OutputFormats: []string{OutputFormatYAML, OutputFormatJSON},
+	}
+	for run := 1; run <= 2; run++ {
+		results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+		if err != nil {
+			t.Fatalf("run %d: pipeline failed: %v", run, err)
+		}
+		want := []string{filepath.Join(dir, "api.json")}
+		if !reflect.DeepEqual(results.FormatOutputs, want) {
+			t.Fatalf("run %d: expected format outputs %v, got %v", run, want, results.FormatOutputs)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "api.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("expected valid JSON, got %v:\n%s", err, data)
+	}
+	if !strings.Contains(string(data), `"x-group": "users"`) {
+		t.Errorf("expected the transformed document in the JSON output:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no sibling for a non-OpenAPI document, got %v", err)
+	}
+}
+
+func TestOutputFormatsOutputFile(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "api.json")
+	spec := `{"openapi": "3.0.3", "info": {"title": "Test API", "version": "1.0.0"}, "paths": {}}`
+	if err := os.WriteFile(input, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(t.TempDir(), "out.json")
+
+	cfg := &config.Config{OutputFormats: []string{OutputFormatYAML}}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, output).ExecuteFullPipeline(input)
+	if err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+	sibling := filepath.Join(filepath.Dir(output), "out.yaml")
+	if !reflect.DeepEqual(results.FormatOutputs, []string{sibling}) {
+		t.Fatalf("expected format outputs [%s], got %v", sibling, results.FormatOutputs)
+	}
+	data, err := os.ReadFile(sibling)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "title: Test API") {
+		t.Errorf("expected the document in the YAML output:\n%s", data)
+	}
+}
+
+func TestOutputFormatsDryRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(outputFormatsSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{OutputFormats: []string{OutputFormatJSON}}
+	results, err := NewTransformationPipeline(cfg, nil, true, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+	if len(results.FormatOutputs) != 0 {
+		t.Errorf("expected no format outputs in a dry run, got %v", results.FormatOutputs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "api.json")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing written in a dry run, got %v", err)
+	}
+}
+
+func TestValidateOutputFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"both", []string{"yaml", "json"}, false},
+		{"unknown", []string{"toml"}, true},
+		{"duplicate", []string{"json", "json"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOutputFormats(tt.formats)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
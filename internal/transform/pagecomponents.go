@@ -0,0 +1,212 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/pagination"
+)
+
+// DefaultPageComponentTemplate names the generated page components, e.g. CursorPage_User
+const DefaultPageComponentTemplate = "{Strategy}Page_{Item}"
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// pageFingerprintIgnore lists the keys that do not make two envelopes different
+var pageFingerprintIgnore = map[string]bool{"description": true, "title": true}
+
+// ValidatePaginationComponents checks that the name template parameterizes the item schema and
+// yields valid component names
+func ValidatePaginationComponents(components config.PaginationComponents) error {
+	if components.NameTemplate == "" {
+		return nil
+	}
+	return validateNameTemplate("pagination_components.name_template", components.NameTemplate, []string{"strategy", "item"}, "item")
+}
+
+// pageComponentName returns the name of the page component of a strategy and item schema
+func pageComponentName(namer componentNamer, components config.PaginationComponents, strategy, item string) string {
+	template := components.NameTemplate
+	if template == "" {
+		template = DefaultPageComponentTemplate
+	}
+	return namer.name(template, map[string]string{"strategy": strategy, "item": item})
+}
+
+// generatePageComponents replaces the inline paginated response schemas of the operations under
+// scoped with references to page components generated under root, and reports whether the
+// document changed. An envelope is only replaced when its operation uses a single strategy and
+// it holds one array of a component schema next to that strategy's fields.
+func generatePageComponents(root, scoped *yaml.Node, components config.PaginationComponents, namer componentNamer, filePath string, result *PaginationResult) bool {
+	paths := getNodeValue(scoped, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return false
+	}
+
+	changed := false
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+		if pathItem.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			methodKey, operation := pathItem.Content[j], pathItem.Content[j+1]
+			if !isHTTPMethod(methodKey.Value) || operation.Kind != yaml.MappingNode {
+				continue
+			}
+			strategy := operationStrategy(pathItem, operation, root)
+			if strategy == "" {
+				continue
+			}
+			key := fmt.Sprintf("%s %s", strings.ToUpper(methodKey.Value), pathName)
+			entries := referencePageComponents(root, operation, strategy, components, namer)
+			if len(entries) == 0 {
+				continue
+			}
+			result.PageComponents[key] = append(result.PageComponents[key], entries...)
+			if pageEntriesChanged(entries) {
+				changed = true
+				result.Locations = append(result.Locations, newChangeLocation(filePath, StepPagination, methodKey, key))
+			}
+		}
+	}
+	return changed
+}
+
+// operationStrategy returns the single pagination strategy left on an operation by its
+// parameters, or an empty string when it has none or several
+func operationStrategy(pathItem, operation, root *yaml.Node) string {
+	params := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, owner := range []*yaml.Node{pathItem, operation} {
+		if p := getNodeValue(owner, "parameters"); p != nil && p.Kind == yaml.SequenceNode {
+			params.Content = append(params.Content, p.Content...)
+		}
+	}
+	detected := pagination.DetectPaginationInParamsWithDoc(params, root)
+	if len(detected) != 1 || detected[0].Strategy == "none" {
+		return ""
+	}
+	return detected[0].Strategy
+}
+
+// referencePageComponents rewrites the paginated success responses of an operation to reference
+// their page component and returns one entry per envelope, as "<status> <media type> -> <name>"
+// or, when the envelope was kept inline, "<status> <media type>: kept inline, <reason>"
+func referencePageComponents(root, operation *yaml.Node, strategy string, components config.PaginationComponents, namer componentNamer) []string {
+	responses := getNodeValue(operation, "responses")
+	if responses == nil || responses.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var entries []string
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		status, response := responses.Content[i].Value, responses.Content[i+1]
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		content := getNodeValue(response, "content")
+		if content == nil || content.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(content.Content); j += 2 {
+			mediaType, media := content.Content[j].Value, content.Content[j+1]
+			if !strings.Contains(mediaType, "json") {
+				continue
+			}
+			schema := getNodeValue(media, "schema")
+			item := pageItemSchema(schema, strategy)
+			if item == "" {
+				continue
+			}
+
+			target := fmt.Sprintf("%s %s", status, mediaType)
+			name, reason := addPageComponent(root, namer, pageComponentName(namer, components, strategy, item), schema)
+			if reason != "" {
+				entries = append(entries, fmt.Sprintf("%s: kept inline, %s", target, reason))
+				continue
+			}
+			setMappingValue(media, "schema", &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+				newScalarNode("$ref"), newScalarNode(schemaRefPrefix + name),
+			}})
+			entries = append(entries, fmt.Sprintf("%s -> %s", target, name))
+		}
+	}
+	return entries
+}
+
+// pageItemSchema returns the name of the component schema listed by an inline paginated envelope,
+// or an empty string when schema is not one
+func pageItemSchema(schema *yaml.Node, strategy string) string {
+	if schema == nil || schema.Kind != yaml.MappingNode || getNodeValue(schema, "$ref") != nil {
+		return ""
+	}
+	for _, composition := range []string{"allOf", "oneOf", "anyOf"} {
+		if getNodeValue(schema, composition) != nil {
+			return ""
+		}
+	}
+	properties := getNodeValue(schema, "properties")
+	if properties == nil || properties.Kind != yaml.MappingNode {
+		return ""
+	}
+
+	item := ""
+	hasPageField := false
+	for i := 0; i+1 < len(properties.Content); i += 2 {
+		name, property := properties.Content[i].Value, properties.Content[i+1]
+		if contains(pagination.PaginationStrategies[strategy].Fields, name) {
+			hasPageField = true
+			continue
+		}
+		if getStringValue(property, "type") != "array" {
+			continue
+		}
+		ref := getStringValue(getNodeValue(property, "items"), "$ref")
+		if !strings.HasPrefix(ref, schemaRefPrefix) || item != "" {
+			return "" // an inline item schema, or several lists
+		}
+		item = strings.TrimPrefix(ref, schemaRefPrefix)
+	}
+	if !hasPageField || strings.Contains(item, "/") {
+		return ""
+	}
+	return item
+}
+
+// addPageComponent adds the page component built from the envelope schema under name, or under
+// the name the namer picks when name holds a different component, unless an identical one exists.
+// It returns the name the envelope references, or why it cannot reference one.
+func addPageComponent(root *yaml.Node, namer componentNamer, name string, schema *yaml.Node) (string, string) {
+	schemas := ensureMappingPath(root, []string{"components", "schemas"})
+	if schemas == nil {
+		return "", "components.schemas is not a mapping"
+	}
+
+	page := cloneNode(schema)
+	removeMappingKey(page, "description")
+	removeMappingKey(page, "title")
+	fingerprint := componentFingerprint(page, pageFingerprintIgnore)
+	claimed, ok := namer.claim(schemas, name, func(existing *yaml.Node) bool {
+		return componentFingerprint(existing, pageFingerprintIgnore) == fingerprint
+	})
+	if !ok {
+		return "", fmt.Sprintf("%s exists with a different shape", name)
+	}
+	if getNodeValue(schemas, claimed) == nil {
+		schemas.Content = append(schemas.Content, newScalarNode(claimed), page)
+	}
+	return claimed, ""
+}
+
+// pageEntriesChanged reports whether any envelope was replaced with a reference
+func pageEntriesChanged(entries []string) bool {
+	for _, entry := range entries {
+		if strings.Contains(entry, " -> ") {
+			return true
+		}
+	}
+	return false
+}
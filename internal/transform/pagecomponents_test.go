@@ -0,0 +1,235 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const pageComponentsSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                description: A page of users
+                properties:
+                  data:
+                    type: array
+                    items:
+                      $ref: '#/components/schemas/User'
+                  next_cursor:
+                    type: string
+  /teams/{id}/users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                description: Team members
+                properties:
+                  data:
+                    type: array
+                    items:
+                      $ref: '#/components/schemas/User'
+                  next_cursor:
+                    type: string
+  /orders:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: array
+                    items:
+                      $ref: '#/components/schemas/Order'
+                  next_cursor:
+                    type: string
+                  has_more:
+                    type: boolean
+  /users/{id}:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+    Order:
+      type: object
+    CursorPage_Order:
+      type: object
+      properties:
+        items:
+          type: array
+          items:
+            $ref: '#/components/schemas/Order'
+`
+
+func TestPageComponents(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(pageComponentsSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessPaginationInDir(dir, PaginationOptions{
+		PaginationPriority: []string{"cursor", "none"},
+		Components:         config.PaginationComponents{Enabled: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed {
+		t.Fatal("expected the envelopes to be replaced")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Paths      map[string]map[string]map[string]interface{}
+		Components struct {
+			Schemas map[string]map[string]interface{}
+		}
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	page, ok := doc.Components.Schemas["CursorPage_User"]
+	if !ok {
+		t.Fatalf("expected CursorPage_User to be generated, got:\n%s", data)
+	}
+	if _, ok := page["description"]; ok {
+		t.Errorf("expected the envelope description to be dropped from the component, got %v", page)
+	}
+	for _, p := range []string{"/users", "/teams/{id}/users"} {
+		if got := responseSchemaRef(doc.Paths[p]["get"]); got != "#/components/schemas/CursorPage_User" {
+			t.Errorf("expected %s to reference CursorPage_User, got %q", p, got)
+		}
+	}
+	if got := responseSchemaRef(doc.Paths["/orders"]["get"]); got != "" {
+		t.Errorf("expected /orders to stay inline next to the different CursorPage_Order, got %q", got)
+	}
+	if got := responseSchemaRef(doc.Paths["/users/{id}"]["get"]); got != "#/components/schemas/User" {
+		t.Errorf("expected the unpaginated response to be left alone, got %q", got)
+	}
+
+	for operation, want := range map[string]string{
+		"GET /users":            "200 application/json -> CursorPage_User",
+		"GET /teams/{id}/users": "200 application/json -> CursorPage_User",
+		"GET /orders":           "200 application/json: kept inline, CursorPage_Order exists with a different shape",
+	} {
+		if got := strings.Join(result.PageComponents[operation], "\n"); got != want {
+			t.Errorf("%s: expected %q, got %q", operation, want, got)
+		}
+	}
+	if len(result.Locations) != 2 {
+		t.Errorf("expected 2 locations, got %d", len(result.Locations))
+	}
+}
+
+func TestPageComponentsCollisionSuffix(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(pageComponentsSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessPaginationInDir(dir, PaginationOptions{
+		PaginationPriority: []string{"cursor", "none"},
+		Components:         config.PaginationComponents{Enabled: true},
+		Naming:             config.ComponentNaming{CollisionSuffix: "{n}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(result.PageComponents["GET /orders"], "\n"); got != "200 application/json -> CursorPage_Order2" {
+		t.Errorf("expected the envelope to get a suffixed component, got %q", got)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "CursorPage_Order2:") {
+		t.Errorf("expected CursorPage_Order2 to be generated, got:\n%s", data)
+	}
+}
+
+// responseSchemaRef returns the $ref of an operation's 200 application/json schema
+func responseSchemaRef(operation map[string]interface{}) string {
+	response := operation["responses"].(map[string]interface{})["200"].(map[string]interface{})
+	media := response["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	ref, _ := media["schema"].(map[string]interface{})["$ref"].(string)
+	return ref
+}
+
+func TestPageComponentName(t *testing.T) {
+	tests := []struct {
+		template, strategy, item, want string
+	}{
+		{"", "cursor", "User", "CursorPage_User"},
+		{"{Item}{Strategy}List", "offset", "Order", "OrderOffsetList"},
+		{"{strategy}.{Item}", "page", "Team", "page.Team"},
+	}
+	for _, tt := range tests {
+		got := pageComponentName(componentNamer{}, config.PaginationComponents{NameTemplate: tt.template}, tt.strategy, tt.item)
+		if got != tt.want {
+			t.Errorf("pageComponentName(%q, %q, %q) = %q, want %q", tt.template, tt.strategy, tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePaginationComponents(t *testing.T) {
+	if err := ValidatePaginationComponents(config.PaginationComponents{NameTemplate: "{Item}Page"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, template := range []string{"{Strategy}Page", "{Strategy} Page {Item}"} {
+		if err := ValidatePaginationComponents(config.PaginationComponents{NameTemplate: template}); err == nil {
+			t.Errorf("expected an error for %q", template)
+		}
+	}
+}
@@ -3,9 +3,11 @@ package transform
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -19,6 +21,98 @@ type PaginationOptions struct {
 	Options
 	PaginationPriority []string
 	EndpointRules      []config.EndpointPaginationRule
+	Cleanup            config.PaginationCleanup
+	SharedFields       map[string]config.SharedFieldRule
+	ResultsFields      map[string]string // path pattern -> response property holding the results
+	Components         config.PaginationComponents
+	Metadata           config.PaginationMetadata
+	Headers            config.PaginationHeaders
+	Naming             config.ComponentNaming
+	Placement          string // where the metadata extension goes among the operation keys, see ExtensionPlacementEnd
+}
+
+// DefaultPagingSentencePatterns select the description sentences scrubbed by pagination_cleanup
+// when it does not configure its own patterns
+var DefaultPagingSentencePatterns = []string{
+	`(?i)paginat`,
+	`(?i)\b(next|previous|prev|first|last) page\b`,
+	`(?i)\b(cursor|per_page|page_size|page size|page number|page token)\b`,
+	`(?i)\b(offset|limit)\b.*\b(results|items|records)\b`,
+}
+
+// ValidateSharedFields checks that the shared field rules name known strategies and pagination
+// response fields, and do not both keep and remove a field
+func ValidateSharedFields(rules map[string]config.SharedFieldRule) error {
+	for strategy, rule := range rules {
+		if _, ok := pagination.PaginationStrategies[strategy]; !ok {
+			return fmt.Errorf("pagination_shared_fields: unknown strategy %q", strategy)
+		}
+		keep := make(map[string]bool)
+		for _, field := range rule.Keep {
+			if !isPaginationField(field) {
+				return fmt.Errorf("pagination_shared_fields.%s.keep: %s is not a pagination response field", strategy, field)
+			}
+			keep[field] = true
+		}
+		for _, field := range rule.Remove {
+			if !isPaginationField(field) {
+				return fmt.Errorf("pagination_shared_fields.%s.remove: %s is not a pagination response field", strategy, field)
+			}
+			if keep[field] {
+				return fmt.Errorf("pagination_shared_fields.%s: %s is both kept and removed", strategy, field)
+			}
+		}
+	}
+	return nil
+}
+
+// isPaginationField reports whether field is a response field of any pagination strategy
+func isPaginationField(field string) bool {
+	for _, strategy := range pagination.PaginationStrategies {
+		for _, f := range strategy.Fields {
+			if f == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// convertSharedFields converts config.SharedFieldRule to pagination.SharedFieldRule
+func convertSharedFields(rules map[string]config.SharedFieldRule) map[string]pagination.SharedFieldRule {
+	converted := make(map[string]pagination.SharedFieldRule, len(rules))
+	for strategy, rule := range rules {
+		converted[strategy] = pagination.SharedFieldRule{Keep: rule.Keep, Remove: rule.Remove}
+	}
+	return converted
+}
+
+// ValidatePaginationCleanup checks that the description patterns compile
+func ValidatePaginationCleanup(cleanup config.PaginationCleanup) error {
+	_, err := compileDescriptionPatterns(cleanup)
+	return err
+}
+
+// compileDescriptionPatterns compiles the patterns that select the description sentences to scrub,
+// returning none when scrubbing is disabled
+func compileDescriptionPatterns(cleanup config.PaginationCleanup) ([]*regexp.Regexp, error) {
+	if !cleanup.ScrubDescriptions {
+		return nil, nil
+	}
+	patterns := cleanup.DescriptionPatterns
+	if len(patterns) == 0 {
+		patterns = DefaultPagingSentencePatterns
+	}
+
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pagination_cleanup.description_patterns: invalid pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
 }
 
 // convertEndpointRules converts config.EndpointPaginationRule to pagination.EndpointPaginationRule
@@ -36,27 +130,60 @@ func convertEndpointRules(configRules []config.EndpointPaginationRule) []paginat
 
 // PaginationResult represents the result of pagination processing
 type PaginationResult struct {
-	Changed          bool
-	ProcessedFiles   []string
-	RemovedParams    map[string][]string // file -> removed param names
-	RemovedResponses map[string][]string // file -> removed response codes
-	ModifiedSchemas  map[string][]string // file -> modified schema paths
-	UnusedComponents []string            // components that became unused
+	Changed           bool
+	ProcessedFiles    []string
+	RemovedParams     map[string][]string  // file -> removed param names
+	RemovedResponses  map[string][]string  // file -> removed response codes
+	ModifiedSchemas   map[string][]string  // file -> modified schema paths
+	MergedParams      map[string][]string  // operation -> merged duplicate parameter definitions
+	MovedParams       map[string][]string  // operation -> path-level parameters moved into sibling operations
+	ScrubbedSentences map[string][]string  // operation -> paging sentences removed from its description
+	RemovedLinks      map[string][]string  // operation -> response links of removed strategies
+	PageComponents    map[string][]string  // operation -> envelopes replaced with generated page components
+	Metadata          map[string][]string  // operation -> pagination metadata extensions set or removed
+	Headers           map[string][]string  // operation -> response headers added for its strategy
+	UnusedComponents  []string             // components that became unused
+	Locations         []ChangeLocation     // source positions of changed operations
+	Decisions         []PaginationDecision // strategies the priority list selected, changed or not
+	UnresolvedRefs    []UnresolvedRef      // $refs followed while processing operations that do not resolve
+	DynamicRefs       []UnresolvedRef      // $dynamicRefs followed while processing operations, resolved best-effort
 }
 
 // ProcessPaginationInDir processes pagination in all OpenAPI files in a directory
 func ProcessPaginationInDir(dir string, opts PaginationOptions) (*PaginationResult, error) {
 	result := &PaginationResult{
-		ProcessedFiles:   []string{},
-		RemovedParams:    make(map[string][]string),
-		RemovedResponses: make(map[string][]string),
-		ModifiedSchemas:  make(map[string][]string),
-		UnusedComponents: []string{},
+		ProcessedFiles:    []string{},
+		RemovedParams:     make(map[string][]string),
+		RemovedResponses:  make(map[string][]string),
+		ModifiedSchemas:   make(map[string][]string),
+		MergedParams:      make(map[string][]string),
+		MovedParams:       make(map[string][]string),
+		ScrubbedSentences: make(map[string][]string),
+		RemovedLinks:      make(map[string][]string),
+		PageComponents:    make(map[string][]string),
+		Metadata:          make(map[string][]string),
+		Headers:           make(map[string][]string),
+		UnusedComponents:  []string{},
 	}
 
 	if len(opts.PaginationPriority) == 0 {
 		return result, nil // No pagination priority configured
 	}
+	if err := ValidatePaginationCleanup(opts.Cleanup); err != nil {
+		return result, err
+	}
+	if err := ValidateSharedFields(opts.SharedFields); err != nil {
+		return result, err
+	}
+	if err := ValidatePaginationComponents(opts.Components); err != nil {
+		return result, err
+	}
+	if err := ValidatePaginationMetadata(opts.Metadata); err != nil {
+		return result, err
+	}
+	if err := ValidatePaginationHeaders(opts.Headers); err != nil {
+		return result, err
+	}
 
 	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -66,8 +193,10 @@ func ProcessPaginationInDir(dir string, opts PaginationOptions) (*PaginationResu
 			return nil
 		}
 
-		if IsYAML(path) || IsJSON(path) {
-			changed, err := processPaginationInFile(path, opts, result)
+		if stepProcessesFile(opts.Options, StepPagination, dir, path) {
+			changed, err := traceFile(opts.Options, StepPagination, path, func() (bool, error) {
+				return processPaginationInFile(path, opts, result)
+			})
 			if err != nil {
 				return fmt.Errorf("error processing %s: %w", path, err)
 			}
@@ -125,7 +254,17 @@ func getRootNode(doc *yaml.Node) *yaml.Node {
 func processDocumentPagination(doc, root *yaml.Node, path string, opts PaginationOptions, result *PaginationResult) (bool, error) {
 	componentsBefore := extractComponentRefs(root)
 
-	changed := processPaginationInPaths(root, opts, path, result)
+	scoped := scopeToPaths(root, opts.Paths)
+	changed := processPaginationInPaths(scoped, opts, path, result)
+	if opts.Components.Enabled && generatePageComponents(root, scoped, opts.Components, newComponentNamer(opts.Naming), path, result) {
+		changed = true
+	}
+	if opts.Metadata.Enabled && annotatePagination(root, scoped, opts, path, result) {
+		changed = true
+	}
+	if opts.Headers.Enabled && injectPaginationHeaders(root, scoped, opts, path, result) {
+		changed = true
+	}
 
 	if changed {
 		return handleDocumentChanges(doc, root, path, componentsBefore, result, opts)
@@ -143,25 +282,12 @@ func handleDocumentChanges(doc, root *yaml.Node, path string, componentsBefore m
 		result.UnusedComponents = append(result.UnusedComponents, unused...)
 	}
 
-	// Only write to file if not in dry-run mode
-	if opts.DryRun {
-		return true, nil // Return true to indicate changes were detected, but don't write
-	}
-
-	return writeModifiedDocument(doc, path)
+	return writeStepDocument(opts.Options, doc, path)
 }
 
 // writeModifiedDocument writes the modified document back to file
 func writeModifiedDocument(doc *yaml.Node, path string) (bool, error) {
-	var output []byte
-	var err error
-
-	if IsJSON(path) {
-		output, err = formatAsJSON(doc)
-	} else {
-		output, err = formatAsYAML(doc)
-	}
-
+	output, err := formatDocument(doc, path)
 	if err != nil {
 		return false, err
 	}
@@ -173,6 +299,28 @@ func writeModifiedDocument(doc *yaml.Node, path string) (bool, error) {
 	return true, nil
 }
 
+// writeStepDocument writes a document a step modified. In a dry run nothing is written; the size
+// the step would have written is noted for its FileMetric instead.
+func writeStepDocument(opts Options, doc *yaml.Node, path string) (bool, error) {
+	if !opts.DryRun {
+		return writeModifiedDocument(doc, path)
+	}
+	if opts.metrics != nil {
+		if output, err := formatDocument(doc, path); err == nil {
+			opts.metrics.plan(path, len(output))
+		}
+	}
+	return true, nil
+}
+
+// formatDocument formats document as JSON or YAML, matching the extension of path
+func formatDocument(doc *yaml.Node, path string) ([]byte, error) {
+	if IsJSON(path) {
+		return formatAsJSON(doc)
+	}
+	return formatAsYAML(doc)
+}
+
 // formatAsJSON formats document as JSON
 func formatAsJSON(doc *yaml.Node) ([]byte, error) {
 	yamlOutput, err := yaml.Marshal(doc)
@@ -204,23 +352,28 @@ func isOpenAPIDocument(root *yaml.Node) bool {
 }
 
 // processPaginationInPaths processes pagination in the paths section
-func processPaginationInPaths(root *yaml.Node, opts PaginationOptions, _ string, result *PaginationResult) bool {
+func processPaginationInPaths(root *yaml.Node, opts PaginationOptions, filePath string, result *PaginationResult) bool {
 	paths := getNodeValue(root, "paths")
 	if paths == nil || paths.Kind != yaml.MappingNode {
 		return false
 	}
 
 	changed := false
+	// The patterns were validated when processing started
+	descriptionPatterns, _ := compileDescriptionPatterns(opts.Cleanup)
 	paginationOpts := pagination.Options{
-		Priority:      opts.PaginationPriority,
-		EndpointRules: convertEndpointRules(opts.EndpointRules),
+		Priority:            opts.PaginationPriority,
+		EndpointRules:       convertEndpointRules(opts.EndpointRules),
+		DescriptionPatterns: descriptionPatterns,
+		SharedFields:        convertSharedFields(opts.SharedFields),
+		ResultsFields:       opts.ResultsFields,
 	}
 
-	return processPathsAndOperations(paths, paginationOpts, root, result, &changed)
+	return processPathsAndOperations(paths, paginationOpts, root, filePath, opts.logWriter(), result, &changed)
 }
 
 // processPathsAndOperations processes all paths and their operations
-func processPathsAndOperations(paths *yaml.Node, paginationOpts pagination.Options, root *yaml.Node, result *PaginationResult, changed *bool) bool {
+func processPathsAndOperations(paths *yaml.Node, paginationOpts pagination.Options, root *yaml.Node, filePath string, log io.Writer, result *PaginationResult, changed *bool) bool {
 	for i := 0; i < len(paths.Content); i += 2 {
 		pathName := paths.Content[i].Value
 		pathNode := paths.Content[i+1]
@@ -229,43 +382,60 @@ func processPathsAndOperations(paths *yaml.Node, paginationOpts pagination.Optio
 			continue
 		}
 
-		processOperationsInPath(pathNode, pathName, paginationOpts, root, result, changed)
+		processOperationsInPath(pathNode, pathName, paginationOpts, root, filePath, log, result, changed)
 	}
 
 	return *changed
 }
 
 // processOperationsInPath processes all operations in a single path
-func processOperationsInPath(pathNode *yaml.Node, pathName string, paginationOpts pagination.Options, root *yaml.Node, result *PaginationResult, changed *bool) {
+func processOperationsInPath(pathNode *yaml.Node, pathName string, paginationOpts pagination.Options, root *yaml.Node, filePath string, log io.Writer, result *PaginationResult, changed *bool) {
 	for j := 0; j < len(pathNode.Content); j += 2 {
-		operation := pathNode.Content[j].Value
+		operationKey := pathNode.Content[j]
 		operationNode := pathNode.Content[j+1]
 
-		if !isHTTPMethod(operation) {
+		if !isHTTPMethod(operationKey.Value) {
 			continue
 		}
 
-		processOperation(operation, operationNode, pathName, paginationOpts, root, result, changed)
+		processOperation(pathNode, operationKey, operationNode, pathName, paginationOpts, root, filePath, log, result, changed)
 	}
 }
 
-// processOperation processes a single operation
-func processOperation(operation string, operationNode *yaml.Node, pathName string, paginationOpts pagination.Options, root *yaml.Node, result *PaginationResult, changed *bool) {
-	operationResult, err := pagination.ProcessEndpointWithPathAndMethod(operationNode, root, pathName, operation, paginationOpts)
+// processOperation processes a single operation of the path item pathNode, writing a warning to
+// log when it fails
+func processOperation(pathNode, operationKey, operationNode *yaml.Node, pathName string, paginationOpts pagination.Options, root *yaml.Node, filePath string, log io.Writer, result *PaginationResult, changed *bool) {
+	operation := operationKey.Value
+	operationResult, err := pagination.ProcessEndpointInPathItem(pathNode, operationNode, root, pathName, operation, paginationOpts)
 	if err != nil {
-		fmt.Printf("Warning: failed to process %s %s: %v\n", operation, pathName, err)
+		fmt.Fprintf(log, "Warning: failed to process %s %s: %v\n", operation, pathName, err)
 		return
 	}
 
+	for _, ref := range operationResult.UnresolvedRefs {
+		addUnresolvedRef(result, filePath, pathName, operation, ref)
+	}
+	for _, ref := range operationResult.DynamicRefs {
+		addDynamicRef(result, filePath, pathName, operation, ref)
+	}
+	if operationResult.Decision != "" && !operationResult.DecidedByRule {
+		result.Decisions = append(result.Decisions, PaginationDecision{
+			File:     filePath,
+			Path:     pathName,
+			Method:   operation,
+			Strategy: operationResult.Decision,
+		})
+	}
 	if operationResult.Changed {
 		*changed = true
-		recordOperationChanges(operation, pathName, operationResult, result)
+		recordOperationChanges(operationKey, pathName, filePath, operationResult, result)
 	}
 }
 
 // recordOperationChanges records changes made to an operation
-func recordOperationChanges(operation, pathName string, operationResult *pagination.ProcessResult, result *PaginationResult) {
-	key := fmt.Sprintf("%s %s", strings.ToUpper(operation), pathName)
+func recordOperationChanges(operationKey *yaml.Node, pathName, filePath string, operationResult *pagination.ProcessResult, result *PaginationResult) {
+	key := fmt.Sprintf("%s %s", strings.ToUpper(operationKey.Value), pathName)
+	result.Locations = append(result.Locations, newChangeLocation(filePath, StepPagination, operationKey, key))
 
 	if len(operationResult.RemovedParams) > 0 {
 		result.RemovedParams[key] = operationResult.RemovedParams
@@ -278,6 +448,22 @@ func recordOperationChanges(operation, pathName string, operationResult *paginat
 	if len(operationResult.ModifiedSchemas) > 0 {
 		result.ModifiedSchemas[key] = operationResult.ModifiedSchemas
 	}
+
+	if len(operationResult.MergedParams) > 0 {
+		result.MergedParams[key] = operationResult.MergedParams
+	}
+
+	if len(operationResult.MovedParams) > 0 {
+		result.MovedParams[key] = operationResult.MovedParams
+	}
+
+	if len(operationResult.ScrubbedSentences) > 0 {
+		result.ScrubbedSentences[key] = operationResult.ScrubbedSentences
+	}
+
+	if len(operationResult.RemovedLinks) > 0 {
+		result.RemovedLinks[key] = operationResult.RemovedLinks
+	}
 }
 
 // isHTTPMethod checks if a string is an HTTP method
@@ -441,6 +627,8 @@ func yamlNodeToJSON(node *yaml.Node, indent int) ([]byte, error) {
 		return handleSequenceNode(node, indent)
 	case yaml.ScalarNode:
 		return handleScalarNode(node)
+	case yaml.AliasNode:
+		return yamlNodeToJSON(node.Alias, indent)
 	default:
 		return []byte("null"), nil
 	}
@@ -503,17 +691,26 @@ func handleSequenceNode(node *yaml.Node, indent int) ([]byte, error) {
 	return []byte("[\n" + strings.Join(parts, ",\n") + "\n" + indentStr + "]"), nil
 }
 
-// handleScalarNode handles scalar nodes (primitives)
+// handleScalarNode handles scalar nodes (primitives). Numbers and booleans are written in their
+// JSON form, so YAML spellings like 0x1F, 1_000 or True are not copied verbatim.
 func handleScalarNode(node *yaml.Node) ([]byte, error) {
-	switch node.Tag {
-	case "!!null", "":
+	switch node.ShortTag() {
+	case "!!null":
 		return []byte("null"), nil
 	case "!!bool":
-		return []byte(node.Value), nil
-	case "!!int", "!!float":
-		return []byte(node.Value), nil
-	case "!!str":
-		return []byte(escapeJSONString(node.Value)), nil
+		return []byte(strings.ToLower(node.Value)), nil
+	case "!!int":
+		value, err := canonicalInt(node.Value)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(value), nil
+	case "!!float":
+		value, err := jsonFloat(node.Value)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(value), nil
 	default:
 		return []byte(escapeJSONString(node.Value)), nil
 	}
@@ -521,7 +718,7 @@ func handleScalarNode(node *yaml.Node) ([]byte, error) {
 
 // formatKeyValuePair formats a single key-value pair
 func formatKeyValuePair(key, value *yaml.Node, nextIndentStr string, nextIndent int) (string, error) {
-	keyJSON := fmt.Sprintf("\"%s\"", key.Value)
+	keyJSON := escapeJSONString(key.Value)
 
 	valueJSON, err := yamlNodeToJSON(value, nextIndent)
 	if err != nil {
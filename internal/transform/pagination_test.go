@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestPaginationCleanupScrubsDescriptions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	spec := `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      description: Lists users. Use the cursor from the previous response to fetch the next page.
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+`
+	if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessPaginationInDir(dir, PaginationOptions{
+		PaginationPriority: []string{"none"},
+		Cleanup:            config.PaginationCleanup{ScrubDescriptions: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.ScrubbedSentences["GET /users"]; len(got) != 1 {
+		t.Errorf("expected one scrubbed sentence, got %v", result.ScrubbedSentences)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	if !strings.Contains(out, "description: Lists users.\n") {
+		t.Errorf("expected the paging sentence to be removed, got:\n%s", out)
+	}
+	if strings.Contains(out, "parameters:") {
+		t.Errorf("expected the empty parameters list to be removed, got:\n%s", out)
+	}
+}
+
+func TestValidatePaginationCleanup(t *testing.T) {
+	err := ValidatePaginationCleanup(config.PaginationCleanup{ScrubDescriptions: true, DescriptionPatterns: []string{"("}})
+	if err == nil || !strings.Contains(err.Error(), "pagination_cleanup.description_patterns") {
+		t.Errorf("expected an invalid pattern error, got %v", err)
+	}
+	if err := ValidatePaginationCleanup(config.PaginationCleanup{DescriptionPatterns: []string{"("}}); err != nil {
+		t.Errorf("expected patterns to be ignored while scrubbing is disabled, got %v", err)
+	}
+}
+
+func TestValidateSharedFields(t *testing.T) {
+	tests := []struct {
+		rules map[string]config.SharedFieldRule
+		want  string
+	}{
+		{map[string]config.SharedFieldRule{"pages": {Keep: []string{"total"}}}, `unknown strategy "pages"`},
+		{map[string]config.SharedFieldRule{"page": {Keep: []string{"items"}}}, "items is not a pagination response field"},
+		{map[string]config.SharedFieldRule{"page": {Keep: []string{"total"}, Remove: []string{"total"}}}, "total is both kept and removed"},
+	}
+	for _, tt := range tests {
+		if err := ValidateSharedFields(tt.rules); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected error containing %q, got %v", tt.want, err)
+		}
+	}
+	if err := ValidateSharedFields(map[string]config.SharedFieldRule{"offset": {Remove: []string{"limit"}}}); err != nil {
+		t.Errorf("expected a valid rule, got %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/pagination"
+)
+
+// DefaultPaginationHeaders are the headers pagination_headers adds for a strategy it configures no
+// headers for
+var DefaultPaginationHeaders = map[string]map[string]map[string]interface{}{
+	"cursor": {
+		"X-Next-Cursor": nextPageHeader(),
+		"Link":          linkHeader(),
+	},
+	"page": {
+		"X-Next-Page":   nextPageHeader(),
+		"X-Total-Count": totalCountHeader(),
+		"Link":          linkHeader(),
+	},
+	"offset": {
+		"X-Total-Count": totalCountHeader(),
+		"Link":          linkHeader(),
+	},
+	"checkpoint": {
+		"Link": linkHeader(),
+	},
+}
+
+// nextPageHeader returns the template of a header holding the value that selects the next page
+func nextPageHeader() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Value of the {param} parameter for the next page, absent on the last page",
+		"schema":      map[string]interface{}{"type": "{param_type}"},
+	}
+}
+
+// totalCountHeader returns the template of a header holding the total number of items
+func totalCountHeader() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Total number of items across all pages",
+		"schema":      map[string]interface{}{"type": "integer"},
+	}
+}
+
+// linkHeader returns the template of an RFC 8288 Link header
+func linkHeader() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "RFC 8288 links to the next and previous pages ({strategy} pagination)",
+		"schema":      map[string]interface{}{"type": "string"},
+	}
+}
+
+// ValidatePaginationHeaders checks that pagination_headers names known strategies and defines
+// every header with a schema or content
+func ValidatePaginationHeaders(headers config.PaginationHeaders) error {
+	for _, strategy := range sortedKeysOf(headers.Headers) {
+		if _, ok := pagination.PaginationStrategies[strategy]; !ok || strategy == "none" {
+			return fmt.Errorf("pagination_headers: unknown strategy %q", strategy)
+		}
+		for _, name := range sortedKeysOf(headers.Headers[strategy]) {
+			definition := headers.Headers[strategy][name]
+			if strings.TrimSpace(name) == "" {
+				return fmt.Errorf("pagination_headers.headers.%s: header name is empty", strategy)
+			}
+			if definition["schema"] == nil && definition["content"] == nil {
+				return fmt.Errorf("pagination_headers.headers.%s.%s: schema or content is required", strategy, name)
+			}
+		}
+	}
+	return nil
+}
+
+// strategyHeaders returns the header templates added for strategy
+func strategyHeaders(headers config.PaginationHeaders, strategy string) map[string]map[string]interface{} {
+	if configured, ok := headers.Headers[strategy]; ok {
+		return configured
+	}
+	return DefaultPaginationHeaders[strategy]
+}
+
+// injectPaginationHeaders adds the headers of the strategy each operation under scoped is left
+// with to its success responses. Headers a response already declares, under any letter case, and
+// referenced responses are left alone. It reports whether the document changed.
+func injectPaginationHeaders(root, scoped *yaml.Node, opts PaginationOptions, filePath string, result *PaginationResult) bool {
+	paths := getNodeValue(scoped, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return false
+	}
+	swagger := getNodeValue(root, "swagger") != nil
+
+	changed := false
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+		if pathItem.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			methodKey, operation := pathItem.Content[j], pathItem.Content[j+1]
+			if !isHTTPMethod(methodKey.Value) || operation.Kind != yaml.MappingNode {
+				continue
+			}
+			strategy := operationStrategy(pathItem, operation, root)
+			templates := strategyHeaders(opts.Headers, strategy)
+			if strategy == "" || len(templates) == 0 {
+				continue
+			}
+			responses := getNodeValue(operation, "responses")
+			if responses == nil || responses.Kind != yaml.MappingNode {
+				continue
+			}
+
+			param, paramType := pageParameter(pathItem, operation, root, strategy)
+			expand := strings.NewReplacer("{strategy}", strategy, "{param}", param, "{param_type}", paramType)
+			key := fmt.Sprintf("%s %s", strings.ToUpper(methodKey.Value), pathName)
+			for k := 0; k+1 < len(responses.Content); k += 2 {
+				status, response := responses.Content[k].Value, responses.Content[k+1]
+				if !strings.HasPrefix(status, "2") || response.Kind != yaml.MappingNode || getNodeValue(response, "$ref") != nil {
+					continue
+				}
+				for _, name := range sortedKeysOf(templates) {
+					if declaresHeader(response, name) {
+						continue
+					}
+					definition := &yaml.Node{}
+					if err := definition.Encode(expandTemplate(templates[name], expand)); err != nil {
+						continue
+					}
+					if swagger {
+						inlineHeaderSchema(definition)
+					}
+					setMappingValue(ensureResponseHeaders(response), name, definition)
+
+					entry := fmt.Sprintf("%s %s", status, name)
+					result.Headers[key] = append(result.Headers[key], entry)
+					result.Locations = append(result.Locations, newChangeLocation(filePath, StepPagination, responses.Content[k], key+": "+entry))
+					changed = true
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// pageParameter returns the name of the parameter selecting the page of strategy and the type of
+// its schema, string when it declares none
+func pageParameter(pathItem, operation, root *yaml.Node, strategy string) (string, string) {
+	params := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, owner := range []*yaml.Node{operation, pathItem} {
+		if p := getNodeValue(owner, "parameters"); p != nil && p.Kind == yaml.SequenceNode {
+			params.Content = append(params.Content, p.Content...)
+		}
+	}
+
+	var names []string
+	for _, detected := range pagination.DetectPaginationInParamsWithDoc(params, root) {
+		if detected.Strategy == strategy {
+			names = detected.Parameters
+		}
+	}
+	if len(names) == 0 {
+		return strategy, "string"
+	}
+	// Prefer the parameter the strategy is named after, such as page over per_page
+	name := names[0]
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, pagination.PaginationStrategies[strategy].Params[0]) {
+			name = candidate
+			break
+		}
+	}
+
+	for _, param := range params.Content {
+		param = resolveLocalRef(param, root)
+		if getStringValue(param, "name") != name {
+			continue
+		}
+		schema := resolveLocalRef(parameterSchema(param), root)
+		if paramType := getStringValue(schema, "type"); paramType != "" {
+			return name, paramType
+		}
+		if paramType := getStringValue(param, "type"); paramType != "" {
+			return name, paramType // Swagger 2.0
+		}
+	}
+	return name, "string"
+}
+
+// expandTemplate returns a copy of a header template with its placeholders filled in
+func expandTemplate(value interface{}, expand *strings.Replacer) interface{} {
+	switch v := value.(type) {
+	case string:
+		return expand.Replace(v)
+	case map[string]interface{}:
+		expanded := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			expanded[key] = expandTemplate(item, expand)
+		}
+		return expanded
+	case []interface{}:
+		expanded := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded[i] = expandTemplate(item, expand)
+		}
+		return expanded
+	default:
+		return value
+	}
+}
+
+// declaresHeader reports whether a response declares the header, ignoring case as HTTP does
+func declaresHeader(response *yaml.Node, name string) bool {
+	headers := getNodeValue(response, "headers")
+	if headers == nil || headers.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(headers.Content); i += 2 {
+		if strings.EqualFold(headers.Content[i].Value, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureResponseHeaders returns the headers of a response, creating them after its description
+func ensureResponseHeaders(response *yaml.Node) *yaml.Node {
+	if headers := getNodeValue(response, "headers"); headers != nil && headers.Kind == yaml.MappingNode {
+		return headers
+	}
+	removeMappingKey(response, "headers")
+
+	headers := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	at := 0
+	for i := 0; i+1 < len(response.Content); i += 2 {
+		if response.Content[i].Value == "description" {
+			at = i + 2
+		}
+	}
+	content := append([]*yaml.Node{}, response.Content[:at]...)
+	content = append(content, newScalarNode("headers"), headers)
+	response.Content = append(content, response.Content[at:]...)
+	return headers
+}
+
+// inlineHeaderSchema moves the schema of a header into the header itself, as Swagger 2.0 declares
+// header types
+func inlineHeaderSchema(header *yaml.Node) {
+	schema := getNodeValue(header, "schema")
+	if schema == nil || schema.Kind != yaml.MappingNode {
+		return
+	}
+	removeMappingKey(header, "schema")
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		if getNodeValue(header, schema.Content[i].Value) == nil {
+			header.Content = append(header.Content, schema.Content[i], schema.Content[i+1])
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const paginationHeadersSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: per_page
+          in: query
+          schema:
+            type: integer
+        - name: page
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+        "404":
+          description: Not found
+  /teams:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+          headers:
+            x-next-cursor:
+              schema:
+                type: string
+        "206":
+          $ref: '#/components/responses/Partial'
+components:
+  responses:
+    Partial:
+      description: Partial
+`
+
+func TestPaginationHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]map[string]map[string]interface{}
+		want    map[string][]string
+		output  []string
+	}{
+		{
+			name: "default headers",
+			want: map[string][]string{
+				"GET /users": {"200 Link", "200 X-Next-Page", "200 X-Total-Count"},
+				"GET /teams": {"200 Link"},
+			},
+			output: []string{
+				"description: OK headers: Link:",
+				"description: Value of the page parameter for the next page, absent on the last page schema: type: integer",
+			},
+		},
+		{
+			name: "configured templates",
+			headers: map[string]map[string]map[string]interface{}{
+				"page": {"X-Page-Count": {"description": "Pages of {strategy} results", "schema": map[string]interface{}{"type": "{param_type}"}}},
+			},
+			want: map[string][]string{
+				"GET /users": {"200 X-Page-Count"},
+				"GET /teams": {"200 Link"},
+			},
+			output: []string{"description: Pages of page results schema: type: integer"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "api.yaml")
+			if err := os.WriteFile(path, []byte(paginationHeadersSpec), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			opts := PaginationOptions{
+				PaginationPriority: []string{"cursor", "page"},
+				Headers:            config.PaginationHeaders{Enabled: true, Headers: tt.headers},
+			}
+			result, err := ProcessPaginationInDir(dir, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.Headers, tt.want) {
+				t.Errorf("expected headers %v, got %v", tt.want, result.Headers)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Compare without indentation
+			flat := strings.Join(strings.Fields(string(data)), " ")
+			for _, want := range tt.output {
+				if !strings.Contains(flat, want) {
+					t.Errorf("expected %q in the output:\n%s", want, data)
+				}
+			}
+
+			// A second run finds every header declared
+			result, err = ProcessPaginationInDir(dir, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Headers) != 0 {
+				t.Errorf("expected no headers on a second run, got %v", result.Headers)
+			}
+		})
+	}
+}
+
+func TestValidatePaginationHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]map[string]map[string]interface{}
+		wantErr bool
+	}{
+		{"unknown strategy", map[string]map[string]map[string]interface{}{"token": {"X-Next": {"schema": map[string]interface{}{}}}}, true},
+		{"missing schema", map[string]map[string]map[string]interface{}{"page": {"X-Next-Page": {"description": "Next"}}}, true},
+		{"valid", map[string]map[string]map[string]interface{}{"page": {"X-Next-Page": {"schema": map[string]interface{}{"type": "integer"}}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePaginationHeaders(config.PaginationHeaders{Headers: tt.headers})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/pagination"
+)
+
+// DefaultPaginationMetadataExtension is the extension pagination_metadata sets when it names none
+const DefaultPaginationMetadataExtension = "x-pagination"
+
+// paginationMetadataExtension returns the extension configured by pagination_metadata
+func paginationMetadataExtension(metadata config.PaginationMetadata) string {
+	if metadata.Extension == "" {
+		return DefaultPaginationMetadataExtension
+	}
+	return metadata.Extension
+}
+
+// ValidatePaginationMetadata checks that pagination_metadata names an extension
+func ValidatePaginationMetadata(metadata config.PaginationMetadata) error {
+	if metadata.Extension != "" && !isExtensionKey(metadata.Extension) {
+		return fmt.Errorf("pagination_metadata.extension must start with x-, got %q", metadata.Extension)
+	}
+	return nil
+}
+
+// annotatePagination records the strategy each operation under scoped is left with in the
+// pagination metadata extension, and removes the extension from operations left without one. It
+// reports whether the document changed.
+func annotatePagination(root, scoped *yaml.Node, opts PaginationOptions, filePath string, result *PaginationResult) bool {
+	paths := getNodeValue(scoped, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return false
+	}
+	extension := paginationMetadataExtension(opts.Metadata)
+
+	changed := false
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+		if pathItem.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			methodKey, operation := pathItem.Content[j], pathItem.Content[j+1]
+			if !isHTTPMethod(methodKey.Value) || operation.Kind != yaml.MappingNode {
+				continue
+			}
+
+			var entry string
+			metadata := paginationMetadata(pathItem, operation, root)
+			existing := getNodeValue(operation, extension)
+			switch {
+			case metadata == nil && existing == nil:
+				continue
+			case metadata == nil:
+				removeMappingKey(operation, extension)
+				entry = extension + " removed"
+			case existing != nil && componentFingerprint(existing, nil) == componentFingerprint(metadata, nil):
+				continue
+			default:
+				if existing != nil {
+					setMappingValue(operation, extension, metadata)
+				} else {
+					insertExtension(operation, extension, metadata, opts.Placement)
+				}
+				entry = extension + ": " + describePaginationMetadata(metadata)
+			}
+
+			key := fmt.Sprintf("%s %s", strings.ToUpper(methodKey.Value), pathName)
+			result.Metadata[key] = append(result.Metadata[key], entry)
+			result.Locations = append(result.Locations, newChangeLocation(filePath, StepPagination, methodKey, key))
+			changed = true
+		}
+	}
+	return changed
+}
+
+// paginationMetadata returns the metadata of the single strategy an operation is left with: its
+// name, the parameters naming it, inherited ones included, and the response fields it uses. It
+// returns nil when the operation has no strategy or several.
+func paginationMetadata(pathItem, operation, root *yaml.Node) *yaml.Node {
+	strategy := operationStrategy(pathItem, operation, root)
+	if strategy == "" {
+		return nil
+	}
+
+	params := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, owner := range []*yaml.Node{operation, pathItem} {
+		if p := getNodeValue(owner, "parameters"); p != nil && p.Kind == yaml.SequenceNode {
+			params.Content = append(params.Content, p.Content...)
+		}
+	}
+	var names, fields []string
+	for _, detected := range pagination.DetectPaginationInParamsWithDoc(params, root) {
+		if detected.Strategy == strategy {
+			names = uniqueStrings(detected.Parameters)
+		}
+	}
+	for _, detected := range pagination.DetectPaginationInResponsesWithDoc(getNodeValue(operation, "responses"), root) {
+		if detected.Strategy == strategy {
+			fields = uniqueStrings(detected.Fields)
+		}
+	}
+
+	metadata := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setMappingValue(metadata, "strategy", newScalarNode(strategy))
+	setMappingValue(metadata, "params", stringSequenceNode(names))
+	if len(fields) > 0 {
+		setMappingValue(metadata, "fields", stringSequenceNode(fields))
+	}
+	return metadata
+}
+
+// describePaginationMetadata summarizes a metadata extension for the results
+func describePaginationMetadata(metadata *yaml.Node) string {
+	description := getStringValue(metadata, "strategy")
+	for _, key := range []string{"params", "fields"} {
+		var values []string
+		if list := getNodeValue(metadata, key); list != nil {
+			for _, item := range list.Content {
+				values = append(values, item.Value)
+			}
+		}
+		if len(values) > 0 {
+			description += fmt.Sprintf(" %s [%s]", key, strings.Join(values, ", "))
+		}
+	}
+	return description
+}
+
+// stringSequenceNode returns a flow sequence of string scalars
+func stringSequenceNode(values []string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+	for _, value := range values {
+		node.Content = append(node.Content, newScalarNode(value))
+	}
+	return node
+}
+
+// uniqueStrings returns values without repeats, in their first order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,116 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const paginationMetadataSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    parameters:
+      - name: size
+        in: query
+        schema:
+          type: integer
+    get:
+      operationId: listUsers
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: offset
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: array
+                    items:
+                      type: string
+                  next_cursor:
+                    type: string
+      x-owner: accounts
+  /health:
+    get:
+      x-pagination:
+        strategy: page
+      responses:
+        "200":
+          description: OK
+`
+
+func TestPaginationMetadata(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(paginationMetadataSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := PaginationOptions{
+		PaginationPriority: []string{"cursor", "offset"},
+		Metadata:           config.PaginationMetadata{Enabled: true},
+		Placement:          ExtensionPlacementAfterOperationID,
+	}
+	result, err := ProcessPaginationInDir(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(result.Metadata["GET /users"], "\n"); got != "x-pagination: cursor params [cursor, size] fields [next_cursor]" {
+		t.Errorf("unexpected metadata for GET /users: %q", got)
+	}
+	if got := strings.Join(result.Metadata["GET /health"], "\n"); got != "x-pagination removed" {
+		t.Errorf("unexpected metadata for GET /health: %q", got)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `            operationId: listUsers
+            x-pagination:
+                strategy: cursor
+                params: [cursor, size]
+                fields: [next_cursor]
+            parameters:`
+	if !strings.Contains(string(data), want) {
+		t.Errorf("expected the metadata after the operationId:\n%s", data)
+	}
+	if strings.Count(string(data), "x-pagination") != 1 {
+		t.Errorf("expected the stale metadata of GET /health to be removed:\n%s", data)
+	}
+
+	// A second run finds the metadata up to date
+	result, err = ProcessPaginationInDir(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed || len(result.Metadata) != 0 {
+		t.Errorf("expected no changes on a second run, got %v", result.Metadata)
+	}
+}
+
+func TestValidatePaginationMetadata(t *testing.T) {
+	if err := ValidatePaginationMetadata(config.PaginationMetadata{Extension: "pagination"}); err == nil {
+		t.Error("expected an extension without the x- prefix to be rejected")
+	}
+	if err := ValidatePaginationMetadata(config.PaginationMetadata{Extension: "x-acme-paging"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
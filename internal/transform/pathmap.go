@@ -0,0 +1,229 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A mapping whose key or target contains dots moves a value between nesting levels instead of
+// renaming a key in place: "x-ratelimit" -> "x-gateway.rateLimit" nests the value under
+// x-gateway, creating it when missing, and "x-meta.owner" -> "x-owner" lifts it out again,
+// dropping x-meta once it is empty. Each dot separates one level of mapping keys.
+
+// ValidateMappings checks that the keys and targets of path mappings have no empty segments
+func ValidateMappings(mappings map[string]string) error {
+	for from, to := range mappings {
+		for _, key := range []string{from, to} {
+			for _, segment := range strings.Split(key, ".") {
+				if segment == "" {
+					return fmt.Errorf("mappings: %s -> %s: %q has an empty path segment", from, to, key)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isPathMapping reports whether a mapping moves a value between nesting levels
+func isPathMapping(from, to string) bool {
+	return strings.Contains(from, ".") || strings.Contains(to, ".")
+}
+
+// hasPathMappings reports whether any of the mappings moves values between nesting levels
+func hasPathMappings(mappings map[string]string) bool {
+	for from, to := range mappings {
+		if isPathMapping(from, to) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPathMappings moves the values the path mappings select within the mapping node n. A
+// mapping is left alone when a level of the target is not a mapping; when the target already
+// exists, opts.Collisions decides what happens.
+func applyPathMappings(n *yaml.Node, opts Options, file string, changes *[]KeyChange) (bool, error) {
+	var froms []string
+	for from, to := range opts.Mappings {
+		if isPathMapping(from, to) {
+			froms = append(froms, from)
+		}
+	}
+	sort.Strings(froms)
+
+	changed := false
+	for _, from := range froms {
+		to := opts.Mappings[from]
+		fromPath, toPath := strings.Split(from, "."), strings.Split(to, ".")
+		if isExcludedKey(fromPath[0], opts.Exclude) {
+			continue
+		}
+		if lookupPathKey(n, toPath) != nil {
+			resolved, err := applyPathCollision(n, fromPath, toPath, opts, file, changes)
+			if err != nil {
+				return changed, err
+			}
+			changed = changed || resolved
+			continue
+		}
+		if movePathValue(n, fromPath, toPath) {
+			changed = true
+			if changes != nil {
+				key := lookupPathKey(n, toPath)
+				*changes = append(*changes, KeyChange{File: file, OldKey: from, NewKey: to, Line: key.Line, Column: key.Column})
+			}
+		}
+	}
+	return changed, nil
+}
+
+// applyPathCollision resolves a path mapping whose target already exists under n and reports
+// whether the value at fromPath was moved onto it. Paths nested in one another are left alone.
+func applyPathCollision(n *yaml.Node, fromPath, toPath []string, opts Options, file string, changes *[]KeyChange) (bool, error) {
+	parents, index := findPath(n, fromPath)
+	if parents == nil || isPathPrefix(fromPath, toPath) || isPathPrefix(toPath, fromPath) {
+		return false, nil
+	}
+	targetParents, targetIndex := findPath(n, toPath)
+	targetParent := targetParents[len(targetParents)-1]
+	key, value := parents[len(parents)-1].Content[index], parents[len(parents)-1].Content[index+1]
+	from, to := strings.Join(fromPath, "."), strings.Join(toPath, ".")
+
+	resolution := collisionResolution(opts.Collisions, targetParent.Content[targetIndex+1], value)
+	if resolution == CollisionError {
+		return false, collisionError(file, key, from, to)
+	}
+	if changes != nil {
+		*changes = append(*changes, KeyChange{File: file, OldKey: from, NewKey: to, Line: key.Line, Column: key.Column, Collision: resolution})
+	}
+	if resolution == CollisionKeepExisting {
+		return false, nil
+	}
+
+	targetParent.Content[targetIndex+1] = resolveCollision(resolution, targetParent.Content[targetIndex+1], value)
+	removePathValue(parents, index, fromPath)
+	return true, nil
+}
+
+// isPathPrefix reports whether prefix is a leading part of path
+func isPathPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// removePathValue removes the pair at index of the innermost of parents, as returned by findPath
+// for path, and drops the levels that removal emptied, innermost first
+func removePathValue(parents []*yaml.Node, index int, path []string) {
+	parent := parents[len(parents)-1]
+	parent.Content = append(parent.Content[:index], parent.Content[index+2:]...)
+	for level := len(parents) - 1; level > 0 && len(parents[level].Content) == 0; level-- {
+		removeMappingKey(parents[level-1], path[level-1])
+	}
+}
+
+// movePathValue moves the value at fromPath under n to toPath and reports whether it did
+func movePathValue(n *yaml.Node, fromPath, toPath []string) bool {
+	parents, index := findPath(n, fromPath)
+	if parents == nil || lookupPathKey(n, toPath) != nil || !canCreatePath(n, toPath[:len(toPath)-1]) {
+		return false
+	}
+
+	parent := parents[len(parents)-1]
+	key, value := parent.Content[index], parent.Content[index+1]
+	position := mappingKeyIndex(n, fromPath[0])
+	removePathValue(parents, index, fromPath)
+	if len(fromPath) > 1 && getNodeValue(n, fromPath[0]) != nil {
+		position += 2 // The level the value came from stays, so the new key follows it
+	}
+
+	target := n
+	for i, segment := range toPath[:len(toPath)-1] {
+		next := getNodeValue(target, segment)
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			pair := []*yaml.Node{newScalarNode(segment), next}
+			if i == 0 && position <= len(target.Content) {
+				// A new top-level key takes the place of the key that was moved
+				target.Content = append(target.Content[:position], append(pair, target.Content[position:]...)...)
+			} else {
+				target.Content = append(target.Content, pair...)
+			}
+		}
+		target = next
+	}
+
+	key.Value = toPath[len(toPath)-1]
+	if len(toPath) == 1 && position <= len(target.Content) {
+		target.Content = append(target.Content[:position], append([]*yaml.Node{key, value}, target.Content[position:]...)...)
+	} else {
+		target.Content = append(target.Content, key, value)
+	}
+	return true
+}
+
+// findPath returns the mapping nodes along path, starting with n, and the index of the last
+// segment's key in the innermost one, or nil when the path does not exist
+func findPath(n *yaml.Node, path []string) ([]*yaml.Node, int) {
+	parents := []*yaml.Node{n}
+	current := n
+	for i, segment := range path {
+		if current.Kind != yaml.MappingNode {
+			return nil, 0
+		}
+		index := mappingKeyIndex(current, segment)
+		if index >= len(current.Content) {
+			return nil, 0
+		}
+		if i == len(path)-1 {
+			return parents, index
+		}
+		current = current.Content[index+1]
+		parents = append(parents, current)
+	}
+	return nil, 0
+}
+
+// lookupPathKey returns the key node at path under n, or nil
+func lookupPathKey(n *yaml.Node, path []string) *yaml.Node {
+	parents, index := findPath(n, path)
+	if parents == nil {
+		return nil
+	}
+	return parents[len(parents)-1].Content[index]
+}
+
+// canCreatePath reports whether every existing level of path under n is a mapping
+func canCreatePath(n *yaml.Node, path []string) bool {
+	current := n
+	for _, segment := range path {
+		next := getNodeValue(current, segment)
+		if next == nil {
+			return true
+		}
+		if next.Kind != yaml.MappingNode {
+			return false
+		}
+		current = next
+	}
+	return true
+}
+
+// mappingKeyIndex returns the index of key in the mapping node n, or len(n.Content) when missing
+func mappingKeyIndex(n *yaml.Node, key string) int {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return i
+		}
+	}
+	return len(n.Content)
+}
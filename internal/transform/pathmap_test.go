@@ -0,0 +1,106 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPathMappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings map[string]string
+		input    string
+		want     string
+	}{
+		{
+			name:     "flat to nested creates the parent",
+			mappings: map[string]string{"x-ratelimit": "x-gateway.rateLimit"},
+			input:    "get:\n  summary: List\n  x-ratelimit: 100\n  responses: {}\n",
+			want:     "get:\n    summary: List\n    x-gateway:\n        rateLimit: 100\n    responses: {}\n",
+		},
+		{
+			name:     "flat to nested joins an existing parent",
+			mappings: map[string]string{"x-ratelimit": "x-gateway.rateLimit"},
+			input:    "x-ratelimit: 100\nx-gateway:\n  timeout: 30\n",
+			want:     "x-gateway:\n    timeout: 30\n    rateLimit: 100\n",
+		},
+		{
+			name:     "nested to flat drops the emptied parent",
+			mappings: map[string]string{"x-meta.owner": "x-owner"},
+			input:    "summary: List\nx-meta:\n  owner: billing\ntags: [a]\n",
+			want:     "summary: List\nx-owner: billing\ntags: [a]\n",
+		},
+		{
+			name:     "nested to flat keeps the remaining parent",
+			mappings: map[string]string{"x-meta.owner": "x-owner"},
+			input:    "x-meta:\n  owner: billing\n  tier: gold\nsummary: List\n",
+			want:     "x-meta:\n    tier: gold\nx-owner: billing\nsummary: List\n",
+		},
+		{
+			name:     "existing target is left alone",
+			mappings: map[string]string{"x-ratelimit": "x-gateway.rateLimit"},
+			input:    "x-ratelimit: 100\nx-gateway:\n  rateLimit: 50\n",
+			want:     "x-ratelimit: 100\nx-gateway:\n  rateLimit: 50\n",
+		},
+		{
+			name:     "scalar parent is left alone",
+			mappings: map[string]string{"x-ratelimit": "x-gateway.rateLimit"},
+			input:    "x-ratelimit: 100\nx-gateway: enabled\n",
+			want:     "x-ratelimit: 100\nx-gateway: enabled\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "spec.yaml")
+			if err := os.WriteFile(path, []byte(tt.input), 0600); err != nil {
+				t.Fatal(err)
+			}
+			var changes []KeyChange
+			if _, err := FileWithChanges(path, Options{Mappings: tt.mappings}, &changes); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPathMappingsJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(path, []byte(`{"x-ratelimit": 100, "x-a": true}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var changes []KeyChange
+	opts := Options{Mappings: map[string]string{"x-ratelimit": "x-gateway.rateLimit", "x-a": "x-b"}}
+	if _, err := FileWithChanges(path, opts, &changes); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), `"x-gateway": {`) || !strings.Contains(string(got), `"rateLimit": 100`) || !strings.Contains(string(got), `"x-b": true`) {
+		t.Errorf("expected the value to be nested and the key renamed, got:\n%s", got)
+	}
+	if len(changes) != 2 || changes[0].OldKey != "x-ratelimit" || changes[0].Column != 2 {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+}
+
+func TestValidateMappings(t *testing.T) {
+	if err := ValidateMappings(map[string]string{"x-a": "x-b..c"}); err == nil || !strings.Contains(err.Error(), "empty path segment") {
+		t.Errorf("expected an empty segment error, got %v", err)
+	}
+	if err := ValidateMappings(map[string]string{"x-a": "x-b.c", "x-d": "x-e"}); err != nil {
+		t.Errorf("expected valid mappings, got %v", err)
+	}
+}
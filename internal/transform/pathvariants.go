@@ -0,0 +1,299 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Canonical forms of a trailing slash
+const (
+	TrailingSlashStrip = "strip"
+	TrailingSlashAdd   = "add"
+)
+
+// PathVariantsOptions extends the regular Options with path variant settings
+type PathVariantsOptions struct {
+	Options
+	PathVariants config.PathVariants
+}
+
+// PathVariantsResult represents the result of merging path variants
+type PathVariantsResult struct {
+	Changed        bool
+	ProcessedFiles []string
+	MergedPaths    map[string][]string // file -> list of operations moved or removed, with the path they merged into
+	Locations      []ChangeLocation    // source positions of the merged path variants
+}
+
+// createPathVariantsResult creates a new PathVariantsResult with initialized maps
+func createPathVariantsResult() *PathVariantsResult {
+	return &PathVariantsResult{
+		ProcessedFiles: []string{},
+		MergedPaths:    make(map[string][]string),
+	}
+}
+
+// setPathVariantsProcessedFiles sets the processed files for a PathVariantsResult
+func setPathVariantsProcessedFiles(result *PathVariantsResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setPathVariantsChanged sets the changed flag for a PathVariantsResult
+func setPathVariantsChanged(result *PathVariantsResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessPathVariantsInDir merges the operations duplicated under variants of the same path in
+// all OpenAPI files in a directory
+func ProcessPathVariantsInDir(dir string, opts PathVariantsOptions) (*PathVariantsResult, error) {
+	if err := ValidatePathVariants(opts.PathVariants); err != nil {
+		return createPathVariantsResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepPathVariants,
+		opts.Options,
+		opts.PathVariants.Enabled,
+		false,
+		createPathVariantsResult,
+		func(path string, result *PathVariantsResult) (bool, error) {
+			return processPathVariantsInFile(path, opts, result)
+		},
+		setPathVariantsProcessedFiles,
+		setPathVariantsChanged,
+	)
+}
+
+// ValidatePathVariants checks the canonical trailing slash form and that version prefixes are
+// paths of their own, such as /v1
+func ValidatePathVariants(variants config.PathVariants) error {
+	switch variants.TrailingSlash {
+	case "", TrailingSlashStrip, TrailingSlashAdd:
+	default:
+		return fmt.Errorf("path_variants.trailing_slash must be %s or %s, got %q", TrailingSlashStrip, TrailingSlashAdd, variants.TrailingSlash)
+	}
+	for _, prefix := range variants.VersionPrefixes {
+		if !strings.HasPrefix(prefix, "/") || strings.HasSuffix(prefix, "/") || strings.ContainsAny(prefix, "{}") {
+			return fmt.Errorf("path_variants.version_prefixes: %q must start with / and not end with one, like /v1", prefix)
+		}
+	}
+	return nil
+}
+
+// processPathVariantsInFile merges the path variants of a single file
+func processPathVariantsInFile(path string, opts PathVariantsOptions, result *PathVariantsResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+	paths := getNodeValue(root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return false, nil
+	}
+
+	changed := false
+	for _, group := range pathVariantGroups(paths, opts.PathVariants) {
+		if mergePathVariants(paths, group, opts.PathVariants, path, result) {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// pathVariantGroups returns the paths that are variants of each other, in document order, for
+// every path written more than one way
+func pathVariantGroups(paths *yaml.Node, variants config.PathVariants) [][]string {
+	var order []string
+	groups := make(map[string][]string)
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathName := paths.Content[i].Value
+		base, _ := basePath(pathName, variants.VersionPrefixes)
+		if _, ok := groups[base]; !ok {
+			order = append(order, base)
+		}
+		groups[base] = append(groups[base], pathName)
+	}
+
+	var duplicated [][]string
+	for _, base := range order {
+		if len(groups[base]) > 1 {
+			duplicated = append(duplicated, groups[base])
+		}
+	}
+	return duplicated
+}
+
+// basePath returns a path without its trailing slash and version prefix, and the prefix it had
+func basePath(pathName string, prefixes []string) (string, string) {
+	base := pathName
+	if len(base) > 1 {
+		base = strings.TrimSuffix(base, "/")
+	}
+	for _, prefix := range prefixes {
+		if base == prefix {
+			return "/", prefix
+		}
+		if strings.HasPrefix(base, prefix+"/") {
+			return strings.TrimPrefix(base, prefix), prefix
+		}
+	}
+	return base, ""
+}
+
+// canonicalPath returns the form the variants of a path are merged into. The version prefix is
+// kept when configured to, using the first configured prefix any of the variants has.
+func canonicalPath(group []string, variants config.PathVariants) string {
+	base, _ := basePath(group[0], variants.VersionPrefixes)
+	prefix := ""
+	if variants.KeepVersionPrefix {
+		used := make(map[string]bool)
+		for _, pathName := range group {
+			_, p := basePath(pathName, variants.VersionPrefixes)
+			used[p] = true
+		}
+		for _, p := range variants.VersionPrefixes {
+			if used[p] {
+				prefix = p
+				break
+			}
+		}
+	}
+
+	canonical := prefix + base
+	if prefix != "" && base == "/" {
+		canonical = prefix
+	}
+	if variants.TrailingSlash == TrailingSlashAdd && !strings.HasSuffix(canonical, "/") {
+		canonical += "/"
+	}
+	return canonical
+}
+
+// mergePathVariants merges the path items of a group of variants into the canonical one, renaming
+// the first variant when none is canonical. Operations the canonical path item lacks are moved
+// into it; the others are duplicates and removed, keeping the canonical one.
+func mergePathVariants(paths *yaml.Node, group []string, variants config.PathVariants, file string, result *PathVariantsResult) bool {
+	canonical := canonicalPath(group, variants)
+	targetIndex, renamed := -1, false
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		if paths.Content[i].Value == canonical {
+			targetIndex = i
+			break
+		}
+	}
+	if targetIndex < 0 {
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			if paths.Content[i].Value == group[0] {
+				targetIndex = i
+				break
+			}
+		}
+		key := paths.Content[targetIndex]
+		entry := fmt.Sprintf("%s renamed to %s", key.Value, canonical)
+		result.MergedPaths[file] = append(result.MergedPaths[file], entry)
+		result.Locations = append(result.Locations, newChangeLocation(file, StepPathVariants, key, entry))
+		key.Value = canonical
+		renamed = true
+	}
+	target := paths.Content[targetIndex+1]
+	if target.Kind != yaml.MappingNode {
+		return renamed
+	}
+
+	changed := renamed
+	var kept []*yaml.Node
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		key, pathItem := paths.Content[i], paths.Content[i+1]
+		if i == targetIndex || !containsString(group, key.Value) || pathItem.Kind != yaml.MappingNode {
+			kept = append(kept, key, pathItem)
+			continue
+		}
+		mergePathItem(target, pathItem, key, canonical, file, result)
+		changed = true
+	}
+	paths.Content = kept
+	return changed
+}
+
+// mergePathItem moves the operations and path-level settings of a variant into the canonical
+// path item, dropping what it already has
+func mergePathItem(target, variant, variantKey *yaml.Node, canonical, file string, result *PathVariantsResult) {
+	record := func(node *yaml.Node, entry string) {
+		result.MergedPaths[file] = append(result.MergedPaths[file], entry)
+		result.Locations = append(result.Locations, newChangeLocation(file, StepPathVariants, node, entry))
+	}
+
+	for i := 0; i+1 < len(variant.Content); i += 2 {
+		key, value := variant.Content[i], variant.Content[i+1]
+		existing := getNodeValue(target, key.Value)
+		switch {
+		case key.Value == "parameters":
+			if existing == nil {
+				target.Content = append(target.Content, key, value)
+			} else {
+				mergePathParameters(existing, value)
+			}
+		case !isHTTPMethod(key.Value):
+			if existing == nil {
+				target.Content = append(target.Content, key, value)
+			}
+		case existing == nil:
+			target.Content = append(target.Content, key, value)
+			record(key, fmt.Sprintf("%s %s moved to %s", strings.ToUpper(key.Value), variantKey.Value, canonical))
+		case encodeNode(existing) == encodeNode(value):
+			record(key, fmt.Sprintf("%s %s removed, duplicate of %s", strings.ToUpper(key.Value), variantKey.Value, canonical))
+		default:
+			record(key, fmt.Sprintf("%s %s removed, differed from the %s operation that was kept", strings.ToUpper(key.Value), variantKey.Value, canonical))
+		}
+	}
+}
+
+// mergePathParameters appends the path-level parameters of a variant the canonical path item
+// does not already declare
+func mergePathParameters(target, params *yaml.Node) {
+	if target.Kind != yaml.SequenceNode || params.Kind != yaml.SequenceNode {
+		return
+	}
+	declared := make(map[string]bool)
+	for _, param := range target.Content {
+		declared[parameterIdentity(param)] = true
+	}
+	for _, param := range params.Content {
+		if id := parameterIdentity(param); !declared[id] {
+			declared[id] = true
+			target.Content = append(target.Content, param)
+		}
+	}
+}
+
+// parameterIdentity identifies a parameter by its $ref, or by its name and location
+func parameterIdentity(param *yaml.Node) string {
+	if ref := getStringValue(param, "$ref"); ref != "" {
+		return ref
+	}
+	return getStringValue(param, "in") + ":" + getStringValue(param, "name")
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const pathVariantsTestSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: OK
+  /users/:
+    parameters:
+      - name: X-Tenant
+        in: header
+    get:
+      operationId: listUsersSlash
+      responses:
+        "200":
+          description: OK
+    post:
+      operationId: createUser
+      responses:
+        "201":
+          description: Created
+  /v1/orders/:
+    get:
+      operationId: listOrders
+      responses:
+        "200":
+          description: OK
+  /orders:
+    delete:
+      operationId: deleteOrders
+      responses:
+        "204":
+          description: Deleted
+  /health:
+    get:
+      responses:
+        "200":
+          description: OK
+`
+
+func TestProcessPathVariantsInDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(pathVariantsTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	variants := config.PathVariants{Enabled: true, VersionPrefixes: []string{"/v1"}, KeepVersionPrefix: true}
+	result, err := ProcessPathVariantsInDir(dir, PathVariantsOptions{PathVariants: variants})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"GET /users/ removed, differed from the /users operation that was kept",
+		"POST /users/ moved to /users",
+		"/v1/orders/ renamed to /v1/orders",
+		"DELETE /orders moved to /v1/orders",
+	}
+	if got := result.MergedPaths[path]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected merges %v, got %v", want, got)
+	}
+
+	data, _ := os.ReadFile(path)
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	paths := getNodeValue(getRootNode(&doc), "paths")
+	var names []string
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		names = append(names, paths.Content[i].Value)
+	}
+	if strings.Join(names, ",") != "/users,/v1/orders,/health" {
+		t.Errorf("expected one path per variant group, got %v", names)
+	}
+	users := getNodeValue(paths, "/users")
+	if getStringValue(getNodeValue(users, "get"), "operationId") != "listUsers" || getNodeValue(users, "post") == nil || getNodeValue(users, "parameters") == nil {
+		t.Errorf("expected the canonical operation kept and the others moved, got:\n%s", data)
+	}
+
+	// Merged documents have nothing left to merge
+	result, err = ProcessPathVariantsInDir(dir, PathVariantsOptions{PathVariants: variants})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed {
+		t.Errorf("expected merging to be idempotent, got %v", result.MergedPaths)
+	}
+}
+
+func TestCanonicalPath(t *testing.T) {
+	tests := []struct {
+		group    []string
+		variants config.PathVariants
+		want     string
+	}{
+		{[]string{"/users/", "/users"}, config.PathVariants{}, "/users"},
+		{[]string{"/users", "/users/"}, config.PathVariants{TrailingSlash: TrailingSlashAdd}, "/users/"},
+		{[]string{"/v1/users", "/users"}, config.PathVariants{VersionPrefixes: []string{"/v1"}}, "/users"},
+		{[]string{"/users", "/v2/users/"}, config.PathVariants{VersionPrefixes: []string{"/v1", "/v2"}, KeepVersionPrefix: true}, "/v2/users"},
+		{[]string{"/v1", "/v1/"}, config.PathVariants{VersionPrefixes: []string{"/v1"}, KeepVersionPrefix: true}, "/v1"},
+	}
+	for _, tt := range tests {
+		if got := canonicalPath(tt.group, tt.variants); got != tt.want {
+			t.Errorf("canonicalPath(%v) = %q, want %q", tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePathVariants(t *testing.T) {
+	if err := ValidatePathVariants(config.PathVariants{TrailingSlash: "keep"}); err == nil || !strings.Contains(err.Error(), "trailing_slash") {
+		t.Errorf("expected an invalid trailing_slash error, got %v", err)
+	}
+	if err := ValidatePathVariants(config.PathVariants{VersionPrefixes: []string{"v1/"}}); err == nil || !strings.Contains(err.Error(), "version_prefixes") {
+		t.Errorf("expected an invalid prefix error, got %v", err)
+	}
+}
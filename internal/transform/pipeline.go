@@ -1,10 +1,17 @@
 package transform
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/developerkunal/OpenMorph/internal/backup"
 	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/progress"
+	"github.com/developerkunal/OpenMorph/internal/telemetry"
 )
 
 // TransformationPipeline represents the complete transformation pipeline
@@ -14,16 +21,52 @@ type TransformationPipeline struct {
 	DryRun          bool
 	Backup          bool
 	OutputFile      string
+	Context         context.Context    // parent of the pipeline's telemetry spans, defaults to context.Background()
+	Strict          bool               // fail before transforming when CheckStrict finds problems
+	Progress        *progress.Reporter // shows the files each step processed, nil for none
+	KeepGoing       bool               // record files that fail to parse or process and carry on with the rest
+	Version         string             // tool version recorded in provenance stamps
+	Log             io.Writer          // receives the warnings steps print while processing, defaults to os.Stdout
 }
 
 // TransformationResults aggregates results from all transformation steps
 type TransformationResults struct {
-	Changed            []string
-	PaginationResult   *PaginationResult
-	FlattenResult      *FlattenResult
-	VendorResult       *VendorExtensionResult
-	DefaultsResult     *DefaultsResult
-	AnyTransformations bool
+	Changed               []string
+	KeyChanges            []KeyChange
+	InternalResult        *InternalResult
+	PathVariantsResult    *PathVariantsResult
+	TenantPathsResult     *TenantPathsResult
+	EnvelopeResult        *EnvelopeResult
+	PaginationResult      *PaginationResult
+	FlattenResult         *FlattenResult
+	InjectionResult       *InjectionResult
+	VendorResult          *VendorExtensionResult
+	ExtensionValuesResult *ExtensionValuesResult
+	DefaultsResult        *DefaultsResult
+	ConstraintsResult     *ConstraintsResult
+	NullabilityResult     *NullabilityResult
+	DedupResult           *DedupResult
+	RenameResult          *RenameResult
+	SchemaTitlesResult    *SchemaTitlesResult
+	ConsistencyResult     *ConsistencyResult
+	ExamplesResult        *ExamplesResult
+	CanonicalizeResult    *CanonicalizeResult
+	LinkResult            *LinkSyncResult
+	ArazzoResult          *ArazzoResult
+	StepMetrics           []StepMetric      // duration and change count of every step that ran, in order
+	FileMetrics           []FileMetric      // duration and size change of every file each step processed, in order
+	SkippedFiles          []SkippedFile     // YAML/JSON files the OpenAPI steps skipped without parsing
+	BackupRun             *backup.Run       // where the originals were kept, with timestamped backups
+	ProtectedSkips        []ProtectedSkip   // changes to protected items that were undone
+	DanglingRefs          []DanglingRef     // $refs that stopped resolving after a step
+	EmptySchemas          []EmptySchema     // object schemas and response contents a step left empty
+	PrunedContainers      []PrunedContainer // path items, request bodies and parameters lists a step left empty
+	// InvariantViolations are the operations whose pagination is inconsistent after the pipeline
+	InvariantViolations []InvariantViolation
+	FileErrors          []FileError // files that failed and were left alone, with KeepGoing
+	StampedFiles        []string    // documents stamped with their provenance
+	FormatOutputs       []string    // documents written in another format, see output_formats
+	AnyTransformations  bool
 }
 
 // normalizeResultPaths normalizes file paths in result structures to show the original input path
@@ -36,12 +79,12 @@ func normalizeResultPaths(inputPath string, files []string) []string {
 }
 
 // normalizeMapKeys normalizes map keys to use the original input path
-func normalizeMapKeys(inputPath string, originalMap map[string][]string) map[string][]string {
+func normalizeMapKeys[V any](inputPath string, originalMap map[string][]V) map[string][]V {
 	if len(originalMap) == 0 {
 		return originalMap
 	}
 
-	normalized := make(map[string][]string)
+	normalized := make(map[string][]V)
 	for _, values := range originalMap {
 		normalized[inputPath] = values
 		break // Use only the first entry's values since all should be the same for single file
@@ -49,18 +92,129 @@ func normalizeMapKeys(inputPath string, originalMap map[string][]string) map[str
 	return normalized
 }
 
+// normalizeLocations points every location at the original input path
+func normalizeLocations(inputPath string, locations []ChangeLocation) []ChangeLocation {
+	return rebaseLocations(locations, func(string) string { return inputPath })
+}
+
 // ExecuteFullPipeline runs the complete transformation pipeline in the correct order
 func (tp *TransformationPipeline) ExecuteFullPipeline(inputPath string) (*TransformationResults, error) {
-	// Determine if we're processing a single file or directory
-	isOutputMode := tp.OutputFile != ""
+	var results *TransformationResults
+	err := tp.withPipelineSpan(inputPath, func(pipeline *TransformationPipeline) error {
+		var err error
+		// For single file with output, use the enhanced single file processor
+		if pipeline.OutputFile != "" {
+			results, err = pipeline.executeSingleFileWithOutput(inputPath)
+			return err
+		}
+
+		// For directory processing, execute each step in sequence
+		results, err = pipeline.executeDirectoryPipeline(inputPath)
+		return err
+	})
+	return results, err
+}
+
+// ExecuteVendorExtensions runs only the vendor extensions step, so the extensions of the selected
+// providers can be previewed or applied independently of the rest of the pipeline
+func (tp *TransformationPipeline) ExecuteVendorExtensions(inputPath string) (*TransformationResults, error) {
+	if !tp.Config.VendorExtensions.Enabled || len(tp.Config.VendorExtensions.Providers) == 0 {
+		return nil, errors.New("no vendor extension providers are enabled in the config")
+	}
+
+	var results *TransformationResults
+	err := tp.withPipelineSpan(inputPath, func(pipeline *TransformationPipeline) error {
+		var err error
+		results, err = pipeline.executeVendorExtensions(inputPath)
+		return err
+	})
+	return results, err
+}
+
+// executeVendorExtensions applies the vendor extensions step to a directory, or to a single file
+// with output
+func (tp *TransformationPipeline) executeVendorExtensions(inputPath string) (*TransformationResults, error) {
+	results := &TransformationResults{
+		Changed: []string{},
+	}
+	if err := tp.CheckStrict(inputPath); err != nil {
+		return nil, err
+	}
+
+	if tp.OutputFile == "" {
+		opts := Options{
+			DryRun:   tp.DryRun,
+			Backup:   tp.Backup,
+			Context:  tp.Context,
+			Files:    tp.Config.Files,
+			Paths:    tp.Config.OnlyPaths,
+			Progress: tp.Progress,
+			Log:      tp.Log,
+			metrics:  newFileMetrics(),
+		}
+		err := runStep(StepVendorExtensions, opts, results, func(opts Options) error {
+			return protectStep(tp.Config.Protect, inputPath, StepVendorExtensions, results, func() error {
+				return tp.applyVendorExtensionsStep(inputPath, opts, results)
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		results.FileMetrics = opts.metrics.list()
+		if results.VendorResult != nil && results.VendorResult.Changed {
+			results.Changed = append(results.Changed, results.VendorResult.ProcessedFiles...)
+		}
+		return results, nil
+	}
+
+	tempDir, tempFilePath, cleanup, err := tp.setupTempProcessing(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var changed bool
+	err = runStep(StepVendorExtensions, Options{Context: tp.Context, Log: tp.Log}, results, func(opts Options) error {
+		return protectStep(tp.Config.Protect, tempDir, StepVendorExtensions, results, func() error {
+			var err error
+			changed, err = tp.applySingleFileVendorExtensions(inputPath, tempDir, opts, results)
+			return err
+		})
+	})
+	normalizeProtectedSkips(inputPath, results)
+	if err != nil {
+		return results, err
+	}
 
-	// For single file with output, use the enhanced single file processor
-	if isOutputMode {
-		return tp.executeSingleFileWithOutput(inputPath)
+	if changed {
+		results.Changed = append(results.Changed, inputPath)
+		results.AnyTransformations = true
+	}
+	if !tp.DryRun {
+		if err := tp.writeOutputFile(tempFilePath, results); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// withPipelineSpan runs fn with a copy of the pipeline whose Context carries a span for the run over inputPath
+func (tp *TransformationPipeline) withPipelineSpan(inputPath string, fn func(*TransformationPipeline) error) error {
+	parent := tp.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, span := telemetry.StartPipeline(parent, inputPath)
+	if tp.Progress != nil {
+		tp.Progress.Start(countProgressFiles(inputPath, tp.Config.Files))
+		defer tp.Progress.Finish()
 	}
 
-	// For directory processing, execute each step in sequence
-	return tp.executeDirectoryPipeline(inputPath)
+	pipeline := *tp
+	pipeline.Context = ctx
+	err := fn(&pipeline)
+	telemetry.End(span, err)
+	return err
 }
 
 // executeSingleFileWithOutput handles single file transformation with output file
@@ -77,38 +231,98 @@ func (tp *TransformationPipeline) executeSingleFileWithOutput(inputPath string)
 	}
 	defer cleanup()
 
+	if err := tp.CheckStrict(inputPath); err != nil {
+		return nil, err
+	}
+
+	skippedFiles, err := collectSkippedFiles(tempFilePath, config.FileFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan input: %v", err)
+	}
+	for _, skipped := range skippedFiles {
+		results.SkippedFiles = append(results.SkippedFiles, SkippedFile{File: inputPath, Reason: skipped.Reason, Code: skipped.Code})
+	}
+
 	// Apply all transformations using the same pipeline as directory processing
 	opts := Options{
-		Mappings: tp.Config.Mappings,
-		Exclude:  tp.Config.Exclude,
-		DryRun:   false, // Process the temp file, not dry run
-		Backup:   false, // No backup for temp files
+		Mappings:   tp.Config.Mappings,
+		Exclude:    ProtectedExclude(tp.Config),
+		DryRun:     false, // Process the temp file, not dry run
+		Backup:     false, // No backup for temp files
+		Context:    tp.Context,
+		Collisions: tp.Config.MappingCollisions,
+		Paths:      tp.Config.OnlyPaths,
+		Progress:   tp.Progress,
+		Log:        tp.Log,
+		failures:   tp.newFileErrors(),
+		metrics:    newFileMetrics(),
 	}
 
 	anyChanges, err := tp.applySingleFileTransformations(inputPath, tempDir, tempFilePath, opts, results)
 	if err != nil {
 		return nil, err
 	}
+	results.FileErrors = opts.failures.list()
+	normalizeFileErrors(inputPath, results)
+	results.FileMetrics = opts.metrics.list()
+	normalizeFileMetrics(inputPath, results)
 
-	// Copy result to output file if changes were made
 	if anyChanges {
 		results.Changed = append(results.Changed, inputPath)
 		results.AnyTransformations = true
+	}
 
-		if !tp.DryRun && tp.OutputFile != "" {
-			transformedData, err := os.ReadFile(tempFilePath)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read transformed file: %v", err)
-			}
-			if err := os.WriteFile(tp.OutputFile, transformedData, 0600); err != nil {
-				return nil, fmt.Errorf("failed to write output file: %v", err)
-			}
+	// The output is written even when nothing changed, so validation and later tools always find
+	// it, but not for an input that failed with KeepGoing
+	if !tp.DryRun && tp.OutputFile != "" && len(results.FileErrors) == 0 {
+		if err := tp.writeOutputFile(tempFilePath, results); err != nil {
+			return nil, err
 		}
 	}
 
 	return results, nil
 }
 
+// writeOutputFile copies the transformed temporary file to the output file. The input is only
+// read, so backups never cover it: with Backup, the previous contents of an output file the run
+// replaces are kept instead.
+func (tp *TransformationPipeline) writeOutputFile(tempFilePath string, results *TransformationResults) error {
+	transformedData, err := os.ReadFile(tempFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read transformed file: %v", err)
+	}
+
+	previous, err := os.ReadFile(tp.OutputFile)
+	replaced := err == nil && !bytes.Equal(previous, transformedData)
+	timestamped := tp.Config.Backups.Naming == BackupNamingTimestamped
+	if tp.Backup && replaced && !timestamped {
+		if err := os.WriteFile(tp.OutputFile+".bak", previous, 0600); err != nil {
+			return fmt.Errorf("failed to back up output file: %v", err)
+		}
+	}
+
+	if err := os.WriteFile(tp.OutputFile, transformedData, 0600); err != nil {
+		return fmt.Errorf("failed to write output file: %v", err)
+	}
+	if err := copySidecar(tempFilePath, tp.OutputFile); err != nil {
+		return err
+	}
+	formatOutputs, err := writeFormatSiblings(tp.OutputFile, tp.Config.OutputFormats)
+	results.FormatOutputs = append(results.FormatOutputs, formatOutputs...)
+	if err != nil {
+		return err
+	}
+
+	if tp.Backup && replaced && timestamped {
+		run, err := saveBackups(tp.Config.Backups, map[string][]byte{tp.OutputFile: previous})
+		if err != nil {
+			return err
+		}
+		results.BackupRun = run
+	}
+	return nil
+}
+
 // setupTempProcessing creates temporary directory and file for processing
 func (*TransformationPipeline) setupTempProcessing(inputPath string) (string, string, func(), error) {
 	// Read the original file
@@ -137,39 +351,187 @@ func (*TransformationPipeline) setupTempProcessing(inputPath string) (string, st
 // applySingleFileTransformations applies all transformation steps to a single file
 func (tp *TransformationPipeline) applySingleFileTransformations(inputPath, tempDir, tempFilePath string, opts Options, results *TransformationResults) (bool, error) {
 	var anyChanges bool
+	originals, err := tp.snapshotForProvenance(tempDir, opts)
+	if err != nil {
+		return false, err
+	}
+	before, err := tp.snapshotOperationsBefore(tempDir)
+	if err != nil {
+		return false, err
+	}
 
 	// Step 1: Apply basic key mappings
 	if len(tp.Config.Mappings) > 0 {
-		fileChanged, err := FileWithChanges(tempFilePath, opts, nil)
+		err := runStep(StepMappings, opts, results, func(opts Options) error {
+			var fileChanged bool
+			err := protectStep(tp.Config.Protect, tempDir, StepMappings, results, func() error {
+				var err error
+				fileChanged, err = traceFile(opts, StepMappings, inputPath, func() (bool, error) {
+					return FileWithChanges(tempFilePath, opts, &results.KeyChanges)
+				})
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply mappings: %v", err)
+			}
+			for i := range results.KeyChanges {
+				results.KeyChanges[i].File = inputPath
+			}
+			if fileChanged {
+				anyChanges = true
+			}
+			return nil
+		})
 		if err != nil {
-			return false, fmt.Errorf("failed to apply mappings: %v", err)
+			return false, err
 		}
-		if fileChanged {
-			anyChanges = true
+		if opts.failures.has(inputPath) {
+			// The remaining steps would fail on the file the same way
+			return false, nil
 		}
 	}
 
 	// Apply remaining transformations using helper functions
-	steps := []func(string, string, Options, *TransformationResults) (bool, error){
-		tp.applySingleFilePagination,
-		tp.applySingleFileFlattening,
-		tp.applySingleFileVendorExtensions,
-		tp.applySingleFileDefaults,
+	steps := []struct {
+		name  string
+		apply func(string, string, Options, *TransformationResults) (bool, error)
+	}{
+		{StepStripInternal, tp.applySingleFileStripInternal},
+		{StepPathVariants, tp.applySingleFilePathVariants},
+		{StepTenantPaths, tp.applySingleFileTenantPaths},
+		{StepUnwrapEnvelopes, tp.applySingleFileUnwrapEnvelopes},
+		{StepPagination, tp.applySingleFilePagination},
+		{StepFlatten, tp.applySingleFileFlattening},
+		{StepParameterInjection, tp.applySingleFileParameterInjection},
+		{StepVendorExtensions, tp.applySingleFileVendorExtensions},
+		{StepExtensionValues, tp.applySingleFileExtensionValues},
+		{StepDefaults, tp.applySingleFileDefaults},
+		{StepSchemaConstraints, tp.applySingleFileSchemaConstraints},
+		{StepNullability, tp.applySingleFileNullability},
+		{StepComponentDedup, tp.applySingleFileComponentDedup},
+		{StepComponentRenames, tp.applySingleFileComponentRenames},
+		{StepSchemaTitles, tp.applySingleFileSchemaTitles},
+		{StepExamples, tp.applySingleFileExamples},
+		{StepCanonicalize, tp.applySingleFileCanonicalize},
+		{StepLinkSync, func(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+			return tp.applySingleFileLinkSync(inputPath, tempDir, opts, before, results)
+		}},
 	}
 
 	for _, step := range steps {
-		changed, err := step(inputPath, tempDir, opts, results)
+		err := runStep(step.name, opts, results, func(opts Options) error {
+			return tp.checkRefsAfterStep(tempDir, step.name, opts, results, func() error {
+				return tp.checkEmptyAfterStep(tempDir, step.name, opts, results, func() error {
+					return tp.pruneEmptyAfterStep(tempDir, step.name, opts, results, func() error {
+						return protectStep(tp.Config.Protect, tempDir, step.name, results, func() error {
+							changed, err := step.apply(inputPath, tempDir, opts, results)
+							if changed {
+								anyChanges = true
+							}
+							return err
+						})
+					})
+				})
+			})
+		})
 		if err != nil {
 			return false, err
 		}
-		if changed {
-			anyChanges = true
-		}
 	}
 
+	if err := tp.stampProvenance(tempDir, opts, originals, results); err != nil {
+		return false, err
+	}
+	normalizeStampedFiles(inputPath, results)
+
+	invariantErr := tp.checkPaginationInvariants(tempDir, opts, results)
+
+	normalizeProtectedSkips(inputPath, results)
+	normalizeDanglingRefs(inputPath, results)
+	normalizeEmptySchemas(inputPath, results)
+	normalizePrunedContainers(inputPath, results)
+	normalizeInvariantViolations(inputPath, results)
+	if invariantErr != nil {
+		return false, invariantErr
+	}
 	return anyChanges, nil
 }
 
+// applySingleFileStripInternal removes internal-only content from a single file
+func (tp *TransformationPipeline) applySingleFileStripInternal(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.StripInternal.Enabled {
+		return false, nil
+	}
+
+	internalOpts := InternalOptions{
+		Options:       opts,
+		StripInternal: tp.Config.StripInternal,
+		AsyncAPI:      tp.Config.AsyncAPI.Enabled,
+	}
+	internalResult, err := ProcessStripInternalInDir(tempDir, internalOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to strip internal content: %v", err)
+	}
+
+	if internalResult != nil {
+		internalResult.ProcessedFiles = normalizeResultPaths(inputPath, internalResult.ProcessedFiles)
+		internalResult.RemovedItems = normalizeMapKeys(inputPath, internalResult.RemovedItems)
+		internalResult.PrunedComponents = normalizeMapKeys(inputPath, internalResult.PrunedComponents)
+		internalResult.SecondaryRemovals = normalizeMapKeys(inputPath, internalResult.SecondaryRemovals)
+	}
+	results.InternalResult = internalResult
+	return internalResult != nil && internalResult.Changed, nil
+}
+
+// applySingleFilePathVariants merges the path variants of a single file
+func (tp *TransformationPipeline) applySingleFilePathVariants(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.PathVariants.Enabled {
+		return false, nil
+	}
+
+	variantsOpts := PathVariantsOptions{
+		Options:      opts,
+		PathVariants: tp.Config.PathVariants,
+	}
+	variantsResult, err := ProcessPathVariantsInDir(tempDir, variantsOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to merge path variants: %v", err)
+	}
+
+	if variantsResult != nil {
+		variantsResult.ProcessedFiles = normalizeResultPaths(inputPath, variantsResult.ProcessedFiles)
+		variantsResult.MergedPaths = normalizeMapKeys(inputPath, variantsResult.MergedPaths)
+		variantsResult.Locations = normalizeLocations(inputPath, variantsResult.Locations)
+	}
+	results.PathVariantsResult = variantsResult
+	return variantsResult != nil && variantsResult.Changed, nil
+}
+
+// applySingleFileTenantPaths adds or removes the tenant path segment in a single file
+func (tp *TransformationPipeline) applySingleFileTenantPaths(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.TenantPaths.Enabled {
+		return false, nil
+	}
+
+	tenantOpts := TenantPathsOptions{
+		Options:     opts,
+		TenantPaths: tp.Config.TenantPaths,
+	}
+	tenantResult, err := ProcessTenantPathsInDir(tempDir, tenantOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to rewrite tenant paths: %v", err)
+	}
+
+	if tenantResult != nil {
+		tenantResult.ProcessedFiles = normalizeResultPaths(inputPath, tenantResult.ProcessedFiles)
+		tenantResult.RewrittenPaths = normalizeMapKeys(inputPath, tenantResult.RewrittenPaths)
+		tenantResult.ChangedComponents = normalizeMapKeys(inputPath, tenantResult.ChangedComponents)
+		tenantResult.Locations = normalizeLocations(inputPath, tenantResult.Locations)
+	}
+	results.TenantPathsResult = tenantResult
+	return tenantResult != nil && tenantResult.Changed, nil
+}
+
 // applySingleFilePagination applies pagination transformations to a single file
 func (tp *TransformationPipeline) applySingleFilePagination(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
 	if len(tp.Config.PaginationPriority) == 0 {
@@ -180,6 +542,14 @@ func (tp *TransformationPipeline) applySingleFilePagination(inputPath, tempDir s
 		Options:            opts,
 		PaginationPriority: tp.Config.PaginationPriority,
 		EndpointRules:      tp.Config.EndpointPagination,
+		Cleanup:            tp.Config.PaginationCleanup,
+		SharedFields:       tp.Config.SharedFields,
+		ResultsFields:      tp.Config.ResultsFields,
+		Components:         tp.Config.PageComponents,
+		Metadata:           tp.Config.PaginationMetadata,
+		Headers:            tp.Config.PaginationHeaders,
+		Naming:             tp.Config.ComponentNaming,
+		Placement:          tp.Config.ExtensionPlacement,
 	}
 	paginationResult, err := ProcessPaginationInDir(tempDir, paginationOpts)
 	if err != nil {
@@ -188,6 +558,13 @@ func (tp *TransformationPipeline) applySingleFilePagination(inputPath, tempDir s
 
 	if paginationResult != nil {
 		paginationResult.ProcessedFiles = normalizeResultPaths(inputPath, paginationResult.ProcessedFiles)
+		paginationResult.Locations = normalizeLocations(inputPath, paginationResult.Locations)
+		for i := range paginationResult.UnresolvedRefs {
+			paginationResult.UnresolvedRefs[i].File = inputPath
+		}
+		for i := range paginationResult.DynamicRefs {
+			paginationResult.DynamicRefs[i].File = inputPath
+		}
 	}
 	results.PaginationResult = paginationResult
 	return paginationResult != nil && paginationResult.Changed, nil
@@ -202,6 +579,9 @@ func (tp *TransformationPipeline) applySingleFileFlattening(inputPath, tempDir s
 	flattenOpts := FlattenOptions{
 		Options:          opts,
 		FlattenResponses: tp.Config.FlattenResponses,
+		Exclude:          tp.Config.FlattenExclude,
+		AllOf:            tp.Config.FlattenAllOf,
+		Metadata:         tp.Config.FlattenMetadata,
 	}
 	flattenResult, err := ProcessFlatteningInDir(tempDir, flattenOpts)
 	if err != nil {
@@ -212,11 +592,63 @@ func (tp *TransformationPipeline) applySingleFileFlattening(inputPath, tempDir s
 		flattenResult.ProcessedFiles = normalizeResultPaths(inputPath, flattenResult.ProcessedFiles)
 		flattenResult.FlattenedRefs = normalizeMapKeys(inputPath, flattenResult.FlattenedRefs)
 		flattenResult.RemovedComponents = normalizeMapKeys(inputPath, flattenResult.RemovedComponents)
+		flattenResult.SkippedSchemas = normalizeMapKeys(inputPath, flattenResult.SkippedSchemas)
+		flattenResult.Locations = normalizeLocations(inputPath, flattenResult.Locations)
 	}
 	results.FlattenResult = flattenResult
 	return flattenResult != nil && flattenResult.Changed, nil
 }
 
+// applySingleFileUnwrapEnvelopes unwraps enveloped success responses in a single file
+func (tp *TransformationPipeline) applySingleFileUnwrapEnvelopes(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.UnwrapEnvelopes.Enabled {
+		return false, nil
+	}
+
+	envelopeOpts := EnvelopeOptions{
+		Options:         opts,
+		UnwrapEnvelopes: tp.Config.UnwrapEnvelopes,
+	}
+	envelopeResult, err := ProcessEnvelopesInDir(tempDir, envelopeOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to unwrap response envelopes: %v", err)
+	}
+
+	if envelopeResult != nil {
+		envelopeResult.ProcessedFiles = normalizeResultPaths(inputPath, envelopeResult.ProcessedFiles)
+		envelopeResult.UnwrappedResponses = normalizeMapKeys(inputPath, envelopeResult.UnwrappedResponses)
+		envelopeResult.RemovedComponents = normalizeMapKeys(inputPath, envelopeResult.RemovedComponents)
+		envelopeResult.Locations = normalizeLocations(inputPath, envelopeResult.Locations)
+	}
+	results.EnvelopeResult = envelopeResult
+	return envelopeResult != nil && envelopeResult.Changed, nil
+}
+
+// applySingleFileParameterInjection adds the configured parameters to a single file
+func (tp *TransformationPipeline) applySingleFileParameterInjection(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.ParameterInjection.Enabled {
+		return false, nil
+	}
+
+	injectionOpts := InjectionOptions{
+		Options:            opts,
+		ParameterInjection: tp.Config.ParameterInjection,
+	}
+	injectionResult, err := ProcessParameterInjectionInDir(tempDir, injectionOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to inject parameters: %v", err)
+	}
+
+	if injectionResult != nil {
+		injectionResult.ProcessedFiles = normalizeResultPaths(inputPath, injectionResult.ProcessedFiles)
+		injectionResult.InjectedParams = normalizeMapKeys(inputPath, injectionResult.InjectedParams)
+		injectionResult.CreatedComponents = normalizeMapKeys(inputPath, injectionResult.CreatedComponents)
+		injectionResult.Locations = normalizeLocations(inputPath, injectionResult.Locations)
+	}
+	results.InjectionResult = injectionResult
+	return injectionResult != nil && injectionResult.Changed, nil
+}
+
 // applySingleFileVendorExtensions applies vendor extension transformations to a single file
 func (tp *TransformationPipeline) applySingleFileVendorExtensions(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
 	if !tp.Config.VendorExtensions.Enabled || len(tp.Config.VendorExtensions.Providers) == 0 {
@@ -227,6 +659,8 @@ func (tp *TransformationPipeline) applySingleFileVendorExtensions(inputPath, tem
 		Options:          opts,
 		VendorExtensions: tp.Config.VendorExtensions,
 		EnabledProviders: tp.VendorProviders,
+		Placement:        tp.Config.ExtensionPlacement,
+		ResultsFields:    tp.Config.ResultsFields,
 	}
 	vendorResult, err := ProcessVendorExtensionsInDir(tempDir, vendorOpts)
 	if err != nil {
@@ -237,6 +671,7 @@ func (tp *TransformationPipeline) applySingleFileVendorExtensions(inputPath, tem
 		vendorResult.ProcessedFiles = normalizeResultPaths(inputPath, vendorResult.ProcessedFiles)
 		vendorResult.AddedExtensions = normalizeMapKeys(inputPath, vendorResult.AddedExtensions)
 		vendorResult.SkippedOperations = normalizeMapKeys(inputPath, vendorResult.SkippedOperations)
+		vendorResult.Locations = normalizeLocations(inputPath, vendorResult.Locations)
 	}
 	results.VendorResult = vendorResult
 	return vendorResult != nil && vendorResult.Changed, nil
@@ -251,6 +686,7 @@ func (tp *TransformationPipeline) applySingleFileDefaults(inputPath, tempDir str
 	defaultsOpts := DefaultsOptions{
 		Options:       opts,
 		DefaultValues: tp.Config.DefaultValues,
+		AsyncAPI:      tp.Config.AsyncAPI.Enabled,
 	}
 	defaultsResult, err := ProcessDefaultsInDir(tempDir, defaultsOpts)
 	if err != nil {
@@ -261,58 +697,466 @@ func (tp *TransformationPipeline) applySingleFileDefaults(inputPath, tempDir str
 		defaultsResult.ProcessedFiles = normalizeResultPaths(inputPath, defaultsResult.ProcessedFiles)
 		defaultsResult.AppliedDefaults = normalizeMapKeys(inputPath, defaultsResult.AppliedDefaults)
 		defaultsResult.SkippedTargets = normalizeMapKeys(inputPath, defaultsResult.SkippedTargets)
+		defaultsResult.Locations = normalizeLocations(inputPath, defaultsResult.Locations)
 	}
 	results.DefaultsResult = defaultsResult
 	return defaultsResult != nil && defaultsResult.Changed, nil
 }
 
+// applySingleFileSchemaConstraints normalizes schema constraints in a single file
+func (tp *TransformationPipeline) applySingleFileSchemaConstraints(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.SchemaConstraints.Enabled {
+		return false, nil
+	}
+
+	constraintsOpts := ConstraintsOptions{
+		Options:           opts,
+		SchemaConstraints: tp.Config.SchemaConstraints,
+		Providers:         tp.appliedVendorProviders(),
+	}
+	constraintsResult, err := ProcessConstraintsInDir(tempDir, constraintsOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to normalize schema constraints: %v", err)
+	}
+
+	if constraintsResult != nil {
+		constraintsResult.ProcessedFiles = normalizeResultPaths(inputPath, constraintsResult.ProcessedFiles)
+		constraintsResult.NormalizedConstraints = normalizeMapKeys(inputPath, constraintsResult.NormalizedConstraints)
+		constraintsResult.Locations = normalizeLocations(inputPath, constraintsResult.Locations)
+	}
+	results.ConstraintsResult = constraintsResult
+	return constraintsResult != nil && constraintsResult.Changed, nil
+}
+
+// applySingleFileExtensionValues replaces extension values through the lookup tables in a single file
+func (tp *TransformationPipeline) applySingleFileExtensionValues(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if len(tp.Config.ExtensionValues) == 0 {
+		return false, nil
+	}
+
+	valuesOpts := ExtensionValuesOptions{
+		Options: opts,
+		Tables:  tp.Config.ExtensionValues,
+	}
+	valuesResult, err := ProcessExtensionValuesInDir(tempDir, valuesOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to replace extension values: %v", err)
+	}
+
+	if valuesResult != nil {
+		valuesResult.ProcessedFiles = normalizeResultPaths(inputPath, valuesResult.ProcessedFiles)
+		valuesResult.ReplacedValues = normalizeMapKeys(inputPath, valuesResult.ReplacedValues)
+		valuesResult.Locations = normalizeLocations(inputPath, valuesResult.Locations)
+	}
+	results.ExtensionValuesResult = valuesResult
+	return valuesResult != nil && valuesResult.Changed, nil
+}
+
+// applySingleFileNullability enforces the nullability policy in a single file
+func (tp *TransformationPipeline) applySingleFileNullability(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.Nullability.Enabled {
+		return false, nil
+	}
+
+	nullabilityOpts := NullabilityOptions{
+		Options:     opts,
+		Nullability: tp.Config.Nullability,
+	}
+	nullabilityResult, err := ProcessNullabilityInDir(tempDir, nullabilityOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to enforce nullability policy: %v", err)
+	}
+
+	if nullabilityResult != nil {
+		nullabilityResult.ProcessedFiles = normalizeResultPaths(inputPath, nullabilityResult.ProcessedFiles)
+		nullabilityResult.NullabilityChanges = normalizeMapKeys(inputPath, nullabilityResult.NullabilityChanges)
+		nullabilityResult.Locations = normalizeLocations(inputPath, nullabilityResult.Locations)
+	}
+	results.NullabilityResult = nullabilityResult
+	return nullabilityResult != nil && nullabilityResult.Changed, nil
+}
+
+// applySingleFileComponentDedup merges duplicate components in a single file
+func (tp *TransformationPipeline) applySingleFileComponentDedup(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.ComponentDedup.Enabled {
+		return false, nil
+	}
+
+	dedupOpts := DedupOptions{
+		Options:        opts,
+		ComponentDedup: tp.Config.ComponentDedup,
+	}
+	dedupResult, err := ProcessComponentDedupInDir(tempDir, dedupOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to deduplicate components: %v", err)
+	}
+
+	if dedupResult != nil {
+		dedupResult.ProcessedFiles = normalizeResultPaths(inputPath, dedupResult.ProcessedFiles)
+		dedupResult.MergedComponents = normalizeMapKeys(inputPath, dedupResult.MergedComponents)
+	}
+	results.DedupResult = dedupResult
+	return dedupResult != nil && dedupResult.Changed, nil
+}
+
+// applySingleFileComponentRenames applies component renames to a single file
+func (tp *TransformationPipeline) applySingleFileComponentRenames(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.ComponentRenames.Enabled {
+		return false, nil
+	}
+
+	renameOpts := RenameOptions{
+		Options:          opts,
+		ComponentRenames: tp.Config.ComponentRenames,
+	}
+	renameResult, err := ProcessComponentRenamesInDir(tempDir, renameOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply component renames: %v", err)
+	}
+
+	if renameResult != nil {
+		renameResult.ProcessedFiles = normalizeResultPaths(inputPath, renameResult.ProcessedFiles)
+		renameResult.RenamedComponents = normalizeMapKeys(inputPath, renameResult.RenamedComponents)
+		renameResult.SkippedRenames = normalizeMapKeys(inputPath, renameResult.SkippedRenames)
+	}
+	results.RenameResult = renameResult
+	return renameResult != nil && renameResult.Changed, nil
+}
+
+// applySingleFileSchemaTitles fills in and normalizes the schema titles of a single file
+func (tp *TransformationPipeline) applySingleFileSchemaTitles(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.SchemaTitles.Enabled {
+		return false, nil
+	}
+
+	titlesOpts := SchemaTitlesOptions{
+		Options:      opts,
+		SchemaTitles: tp.Config.SchemaTitles,
+	}
+	titlesResult, err := ProcessSchemaTitlesInDir(tempDir, titlesOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply schema titles: %v", err)
+	}
+
+	if titlesResult != nil {
+		titlesResult.ProcessedFiles = normalizeResultPaths(inputPath, titlesResult.ProcessedFiles)
+		titlesResult.AddedTitles = normalizeMapKeys(inputPath, titlesResult.AddedTitles)
+		titlesResult.NormalizedTitles = normalizeMapKeys(inputPath, titlesResult.NormalizedTitles)
+		titlesResult.Locations = normalizeLocations(inputPath, titlesResult.Locations)
+	}
+	results.SchemaTitlesResult = titlesResult
+	return titlesResult != nil && titlesResult.Changed, nil
+}
+
+// applySingleFileExamples repairs the examples of a single file
+func (tp *TransformationPipeline) applySingleFileExamples(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.Examples.Enabled {
+		return false, nil
+	}
+
+	examplesOpts := ExamplesOptions{
+		Options:  opts,
+		Examples: tp.Config.Examples,
+	}
+	examplesResult, err := ProcessExamplesInDir(tempDir, examplesOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to repair examples: %v", err)
+	}
+
+	if examplesResult != nil {
+		examplesResult.ProcessedFiles = normalizeResultPaths(inputPath, examplesResult.ProcessedFiles)
+		examplesResult.RepairedExamples = normalizeMapKeys(inputPath, examplesResult.RepairedExamples)
+		examplesResult.Locations = normalizeLocations(inputPath, examplesResult.Locations)
+	}
+	results.ExamplesResult = examplesResult
+	return examplesResult != nil && examplesResult.Changed, nil
+}
+
+// applySingleFileCanonicalize sorts the sections of a single file
+func (tp *TransformationPipeline) applySingleFileCanonicalize(inputPath, tempDir string, opts Options, results *TransformationResults) (bool, error) {
+	if !tp.Config.Canonicalize.Enabled {
+		return false, nil
+	}
+
+	canonicalizeOpts := CanonicalizeOptions{
+		Options:      opts,
+		Canonicalize: tp.Config.Canonicalize,
+		Placement:    tp.Config.ExtensionPlacement,
+	}
+	canonicalizeResult, err := ProcessCanonicalizeInDir(tempDir, canonicalizeOpts)
+	if err != nil {
+		return false, fmt.Errorf("failed to canonicalize documents: %v", err)
+	}
+
+	if canonicalizeResult != nil {
+		canonicalizeResult.ProcessedFiles = normalizeResultPaths(inputPath, canonicalizeResult.ProcessedFiles)
+		canonicalizeResult.SortedSections = normalizeMapKeys(inputPath, canonicalizeResult.SortedSections)
+		canonicalizeResult.Locations = normalizeLocations(inputPath, canonicalizeResult.Locations)
+	}
+	results.CanonicalizeResult = canonicalizeResult
+	return canonicalizeResult != nil && canonicalizeResult.Changed, nil
+}
+
+// applySingleFileLinkSync updates the links of a single file that name operations the earlier
+// steps renamed or removed
+func (*TransformationPipeline) applySingleFileLinkSync(inputPath, tempDir string, opts Options, before map[string][]operationRef, results *TransformationResults) (bool, error) {
+	if before == nil {
+		return false, nil
+	}
+	changes, err := detectOperationChanges(tempDir, before)
+	if err != nil {
+		return false, fmt.Errorf("failed to snapshot operations: %v", err)
+	}
+	if !changes.hasChanges() {
+		return false, nil
+	}
+	linkResult, err := SyncOperationLinksInDir(tempDir, opts, changes)
+	if err != nil {
+		return false, fmt.Errorf("failed to sync links: %v", err)
+	}
+
+	linkResult.ProcessedFiles = normalizeResultPaths(inputPath, linkResult.ProcessedFiles)
+	linkResult.UpdatedLinks = normalizeMapKeys(inputPath, linkResult.UpdatedLinks)
+	linkResult.RemovedLinks = normalizeMapKeys(inputPath, linkResult.RemovedLinks)
+	linkResult.Locations = normalizeLocations(inputPath, linkResult.Locations)
+	results.LinkResult = linkResult
+	return linkResult.Changed, nil
+}
+
 // executeDirectoryPipeline handles directory-based transformations
 func (tp *TransformationPipeline) executeDirectoryPipeline(inputPath string) (*TransformationResults, error) {
+	if !tp.Backup || tp.DryRun || tp.Config.Backups.Naming != BackupNamingTimestamped {
+		return tp.runDirectorySteps(inputPath)
+	}
+
+	// Timestamped backups replace the .bak files: snapshot the inputs and keep the ones the run changed
+	originals, err := snapshotInputs(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	steps := *tp
+	steps.Backup = false
+	results, err := steps.runDirectorySteps(inputPath)
+	run, backupErr := saveBackups(tp.Config.Backups, originals)
+	if err != nil {
+		return nil, err
+	}
+	if backupErr != nil {
+		return nil, backupErr
+	}
+	results.BackupRun = run
+	return results, nil
+}
+
+// runDirectorySteps applies every step of the pipeline to the files under inputPath
+func (tp *TransformationPipeline) runDirectorySteps(inputPath string) (*TransformationResults, error) {
+	run, results, err := tp.startDirectoryRun(inputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 1: Apply basic key mappings
+	err = protectStep(tp.Config.Protect, inputPath, StepMappings, results, func() error {
+		return applyMappingsStep(inputPath, run.opts, results)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tp.applySharedSteps(inputPath, run.opts, results); err != nil {
+		return nil, err
+	}
+	if err := tp.applyProfileSteps(inputPath, run.opts, results); err != nil {
+		return nil, err
+	}
+	if finished, err := tp.finishDirectoryRun(inputPath, run, results); err != nil {
+		return finished, err
+	}
+
+	if !tp.DryRun {
+		results.FormatOutputs, err = writeOutputFormats(inputPath, tp.Config.OutputFormats, tp.Config.Files)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// directoryRun is what the steps of a run over the files under one input share: their options and
+// what the input looked like before the first step, which link sync, Arazzo sync and provenance
+// stamping compare against
+type directoryRun struct {
+	opts            Options
+	arazzoDocuments []string
+	before          map[string][]operationRef
+	originals       map[string][]byte
+}
+
+// startDirectoryRun checks the input and takes what a run over the files under inputPath needs
+// before its first step
+func (tp *TransformationPipeline) startDirectoryRun(inputPath string) (*directoryRun, *TransformationResults, error) {
 	results := &TransformationResults{
 		Changed: []string{},
 	}
+	run := &directoryRun{
+		opts: Options{
+			Mappings:   tp.Config.Mappings,
+			Exclude:    ProtectedExclude(tp.Config),
+			DryRun:     tp.DryRun,
+			Backup:     tp.Backup,
+			OutputFile: tp.OutputFile,
+			Context:    tp.Context,
+			Files:      tp.Config.Files,
+			Collisions: tp.Config.MappingCollisions,
+			Paths:      tp.Config.OnlyPaths,
+			Progress:   tp.Progress,
+			Log:        tp.Log,
+			failures:   tp.newFileErrors(),
+			metrics:    newFileMetrics(),
+		},
+	}
 
-	// Step 1: Apply basic key mappings
-	opts := Options{
-		Mappings:   tp.Config.Mappings,
-		Exclude:    tp.Config.Exclude,
-		DryRun:     tp.DryRun,
-		Backup:     tp.Backup,
-		OutputFile: tp.OutputFile,
+	if err := ValidateFileFilter(tp.Config.Files); err != nil {
+		return nil, nil, err
+	}
+	if err := tp.CheckStrict(inputPath); err != nil {
+		return nil, nil, err
 	}
 
-	changed, err := Dir(inputPath, opts)
+	skippedFiles, err := collectSkippedFiles(inputPath, tp.Config.Files)
 	if err != nil {
-		return nil, fmt.Errorf("failed to apply basic mappings: %v", err)
+		return nil, nil, fmt.Errorf("failed to scan input: %v", err)
 	}
-	results.Changed = changed
-	if len(changed) > 0 {
-		results.AnyTransformations = true
+	results.SkippedFiles = skippedFiles
+
+	run.arazzoDocuments, err = findArazzoDocuments(inputPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to detect Arazzo documents: %v", err)
+	}
+	run.before, err = tp.snapshotOperationsBefore(inputPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	run.originals, err = tp.snapshotForProvenance(inputPath, run.opts)
+	if err != nil {
+		return nil, nil, err
 	}
+	return run, results, nil
+}
 
-	// Step 2: Apply pagination transformations
-	if err := tp.applyPaginationStep(inputPath, opts, results); err != nil {
-		return nil, err
+// finishDirectoryRun runs what follows the steps of a run over the files under inputPath: link and
+// Arazzo sync, provenance stamping and the pagination invariants. With failed invariants the
+// results are returned along with the error.
+func (tp *TransformationPipeline) finishDirectoryRun(inputPath string, run *directoryRun, results *TransformationResults) (*TransformationResults, error) {
+	var changes *operationChanges
+	if run.before != nil {
+		var err error
+		if changes, err = detectOperationChanges(inputPath, run.before); err != nil {
+			return nil, fmt.Errorf("failed to snapshot operations: %v", err)
+		}
 	}
 
-	// Step 3: Apply response flattening
-	if err := tp.applyFlatteningStep(inputPath, opts, results); err != nil {
+	// Step 16: Keep response links in sync with renamed and removed operations
+	err := runStep(StepLinkSync, run.opts, results, func(opts Options) error {
+		return tp.applyLinkSyncStep(inputPath, opts, changes, results)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Step 4: Apply vendor extensions
-	if err := tp.applyVendorExtensionsStep(inputPath, opts, results); err != nil {
+	// Step 17: Keep Arazzo workflow references in sync with renamed operations
+	err = runStep(StepArazzoSync, run.opts, results, func(Options) error {
+		return tp.applyArazzoSyncStep(run.arazzoDocuments, changes, results)
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	// Step 5: Apply default values
-	if err := tp.applyDefaultsStep(inputPath, opts, results); err != nil {
+	results.FileErrors = run.opts.failures.list()
+	results.FileMetrics = run.opts.metrics.list()
+	if err := tp.stampProvenance(inputPath, run.opts, run.originals, results); err != nil {
 		return nil, err
 	}
 
+	if err := tp.checkPaginationInvariants(inputPath, run.opts, results); err != nil {
+		return results, err
+	}
 	return results, nil
 }
 
+// applyMappingsStep applies the basic key mappings to every file under inputPath
+func applyMappingsStep(inputPath string, opts Options, results *TransformationResults) error {
+	return runStep(StepMappings, opts, results, func(opts Options) error {
+		changed, keyChanges, err := DirWithChanges(inputPath, opts)
+		if err != nil {
+			return fmt.Errorf("failed to apply basic mappings: %v", err)
+		}
+		results.Changed = changed
+		results.KeyChanges = keyChanges
+		if len(changed) > 0 {
+			results.AnyTransformations = true
+		}
+		return nil
+	})
+}
+
+// pipelineStep is a directory step of the pipeline
+type pipelineStep struct {
+	name  string
+	apply func(inputPath string, opts Options, results *TransformationResults) error
+}
+
+// applySteps runs the steps in order, each inside its own span, undoing their changes to protected items
+func (tp *TransformationPipeline) applySteps(inputPath string, opts Options, results *TransformationResults, steps []pipelineStep) error {
+	for _, step := range steps {
+		err := runStep(step.name, opts, results, func(opts Options) error {
+			return tp.checkRefsAfterStep(inputPath, step.name, opts, results, func() error {
+				return tp.checkEmptyAfterStep(inputPath, step.name, opts, results, func() error {
+					return tp.pruneEmptyAfterStep(inputPath, step.name, opts, results, func() error {
+						return protectStep(tp.Config.Protect, inputPath, step.name, results, func() error {
+							return step.apply(inputPath, opts, results)
+						})
+					})
+				})
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applySharedSteps applies the steps that do not depend on the vendor provider profile
+func (tp *TransformationPipeline) applySharedSteps(inputPath string, opts Options, results *TransformationResults) error {
+	return tp.applySteps(inputPath, opts, results, []pipelineStep{
+		{StepStripInternal, tp.applyStripInternalStep},           // Step 2: Strip internal-only content
+		{StepPathVariants, tp.applyPathVariantsStep},             // Step 2b: Merge operations duplicated under path variants
+		{StepTenantPaths, tp.applyTenantPathsStep},               // Step 2c: Add or remove the tenant path segment
+		{StepUnwrapEnvelopes, tp.applyUnwrapEnvelopesStep},       // Step 3: Unwrap response envelopes
+		{StepPagination, tp.applyPaginationStep},                 // Step 4: Apply pagination transformations
+		{StepFlatten, tp.applyFlatteningStep},                    // Step 5: Apply response flattening
+		{StepParameterInjection, tp.applyParameterInjectionStep}, // Step 6: Inject standard parameters
+	})
+}
+
+// applyProfileSteps applies vendor extensions and every step that runs after them
+func (tp *TransformationPipeline) applyProfileSteps(inputPath string, opts Options, results *TransformationResults) error {
+	return tp.applySteps(inputPath, opts, results, []pipelineStep{
+		{StepVendorExtensions, tp.applyVendorExtensionsStep},         // Step 7: Apply vendor extensions
+		{StepExtensionValues, tp.applyExtensionValuesStep},           // Step 7b: Replace extension values through lookup tables
+		{StepDefaults, tp.applyDefaultsStep},                         // Step 8: Apply default values
+		{StepSchemaConstraints, tp.applySchemaConstraintsStep},       // Step 9: Normalize schema constraints
+		{StepNullability, tp.applyNullabilityStep},                   // Step 10: Enforce the nullability policy
+		{StepComponentDedup, tp.applyComponentDedupStep},             // Step 11: Merge duplicate components
+		{StepComponentRenames, tp.applyComponentRenamesStep},         // Step 12: Apply component renames
+		{StepSchemaTitles, tp.applySchemaTitlesStep},                 // Step 12b: Fill in and normalize schema titles
+		{StepComponentConsistency, tp.applyComponentConsistencyStep}, // Step 13: Check components shared between files
+		{StepExamples, tp.applyExamplesStep},                         // Step 14: Repair examples that no longer match their schema
+		{StepCanonicalize, tp.applyCanonicalizeStep},                 // Step 15: Sort document sections
+	})
+}
+
 // NewTransformationPipeline creates a new transformation pipeline
 func NewTransformationPipeline(cfg *config.Config, vendorProviders []string, dryRun bool, backup bool, outputFile string) *TransformationPipeline {
 	return &TransformationPipeline{
@@ -324,6 +1168,70 @@ func NewTransformationPipeline(cfg *config.Config, vendorProviders []string, dry
 	}
 }
 
+// applyStripInternalStep removes internal-only content and prunes what it leaves unused
+func (tp *TransformationPipeline) applyStripInternalStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.StripInternal.Enabled {
+		return nil
+	}
+
+	internalOpts := InternalOptions{
+		Options:       opts,
+		StripInternal: tp.Config.StripInternal,
+		AsyncAPI:      tp.Config.AsyncAPI.Enabled,
+	}
+	internalResult, err := ProcessStripInternalInDir(inputPath, internalOpts)
+	if err != nil {
+		return fmt.Errorf("failed to strip internal content: %v", err)
+	}
+	results.InternalResult = internalResult
+	if internalResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applyTenantPathsStep adds or removes the tenant path segment
+func (tp *TransformationPipeline) applyTenantPathsStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.TenantPaths.Enabled {
+		return nil
+	}
+
+	tenantOpts := TenantPathsOptions{
+		Options:     opts,
+		TenantPaths: tp.Config.TenantPaths,
+	}
+	tenantResult, err := ProcessTenantPathsInDir(inputPath, tenantOpts)
+	if err != nil {
+		return fmt.Errorf("failed to rewrite tenant paths: %v", err)
+	}
+	results.TenantPathsResult = tenantResult
+	if tenantResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applyPathVariantsStep merges the operations duplicated under variants of the same path
+func (tp *TransformationPipeline) applyPathVariantsStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.PathVariants.Enabled {
+		return nil
+	}
+
+	variantsOpts := PathVariantsOptions{
+		Options:      opts,
+		PathVariants: tp.Config.PathVariants,
+	}
+	variantsResult, err := ProcessPathVariantsInDir(inputPath, variantsOpts)
+	if err != nil {
+		return fmt.Errorf("failed to merge path variants: %v", err)
+	}
+	results.PathVariantsResult = variantsResult
+	if variantsResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
 // applyPaginationStep applies pagination transformations
 func (tp *TransformationPipeline) applyPaginationStep(inputPath string, opts Options, results *TransformationResults) error {
 	if len(tp.Config.PaginationPriority) == 0 {
@@ -334,6 +1242,14 @@ func (tp *TransformationPipeline) applyPaginationStep(inputPath string, opts Opt
 		Options:            opts,
 		PaginationPriority: tp.Config.PaginationPriority,
 		EndpointRules:      tp.Config.EndpointPagination,
+		Cleanup:            tp.Config.PaginationCleanup,
+		SharedFields:       tp.Config.SharedFields,
+		ResultsFields:      tp.Config.ResultsFields,
+		Components:         tp.Config.PageComponents,
+		Metadata:           tp.Config.PaginationMetadata,
+		Headers:            tp.Config.PaginationHeaders,
+		Naming:             tp.Config.ComponentNaming,
+		Placement:          tp.Config.ExtensionPlacement,
 	}
 	paginationResult, err := ProcessPaginationInDir(inputPath, paginationOpts)
 	if err != nil {
@@ -355,6 +1271,9 @@ func (tp *TransformationPipeline) applyFlatteningStep(inputPath string, opts Opt
 	flattenOpts := FlattenOptions{
 		Options:          opts,
 		FlattenResponses: tp.Config.FlattenResponses,
+		Exclude:          tp.Config.FlattenExclude,
+		AllOf:            tp.Config.FlattenAllOf,
+		Metadata:         tp.Config.FlattenMetadata,
 	}
 	flattenResult, err := ProcessFlatteningInDir(inputPath, flattenOpts)
 	if err != nil {
@@ -367,6 +1286,48 @@ func (tp *TransformationPipeline) applyFlatteningStep(inputPath string, opts Opt
 	return nil
 }
 
+// applyUnwrapEnvelopesStep unwraps enveloped success responses
+func (tp *TransformationPipeline) applyUnwrapEnvelopesStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.UnwrapEnvelopes.Enabled {
+		return nil
+	}
+
+	envelopeOpts := EnvelopeOptions{
+		Options:         opts,
+		UnwrapEnvelopes: tp.Config.UnwrapEnvelopes,
+	}
+	envelopeResult, err := ProcessEnvelopesInDir(inputPath, envelopeOpts)
+	if err != nil {
+		return fmt.Errorf("failed to unwrap response envelopes: %v", err)
+	}
+	results.EnvelopeResult = envelopeResult
+	if envelopeResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applyParameterInjectionStep adds the configured parameters to matching operations
+func (tp *TransformationPipeline) applyParameterInjectionStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.ParameterInjection.Enabled {
+		return nil
+	}
+
+	injectionOpts := InjectionOptions{
+		Options:            opts,
+		ParameterInjection: tp.Config.ParameterInjection,
+	}
+	injectionResult, err := ProcessParameterInjectionInDir(inputPath, injectionOpts)
+	if err != nil {
+		return fmt.Errorf("failed to inject parameters: %v", err)
+	}
+	results.InjectionResult = injectionResult
+	if injectionResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
 // applyVendorExtensionsStep applies vendor extension transformations
 func (tp *TransformationPipeline) applyVendorExtensionsStep(inputPath string, opts Options, results *TransformationResults) error {
 	if !tp.Config.VendorExtensions.Enabled {
@@ -377,6 +1338,8 @@ func (tp *TransformationPipeline) applyVendorExtensionsStep(inputPath string, op
 		Options:          opts,
 		VendorExtensions: tp.Config.VendorExtensions,
 		EnabledProviders: tp.VendorProviders,
+		Placement:        tp.Config.ExtensionPlacement,
+		ResultsFields:    tp.Config.ResultsFields,
 	}
 	vendorResult, err := ProcessVendorExtensionsInDir(inputPath, vendorOpts)
 	if err != nil {
@@ -398,6 +1361,7 @@ func (tp *TransformationPipeline) applyDefaultsStep(inputPath string, opts Optio
 	defaultsOpts := DefaultsOptions{
 		Options:       opts,
 		DefaultValues: tp.Config.DefaultValues,
+		AsyncAPI:      tp.Config.AsyncAPI.Enabled,
 	}
 	defaultsResult, err := ProcessDefaultsInDir(inputPath, defaultsOpts)
 	if err != nil {
@@ -409,3 +1373,261 @@ func (tp *TransformationPipeline) applyDefaultsStep(inputPath string, opts Optio
 	}
 	return nil
 }
+
+// applySchemaConstraintsStep normalizes numeric and string constraints of schemas
+func (tp *TransformationPipeline) applySchemaConstraintsStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.SchemaConstraints.Enabled {
+		return nil
+	}
+
+	constraintsOpts := ConstraintsOptions{
+		Options:           opts,
+		SchemaConstraints: tp.Config.SchemaConstraints,
+		Providers:         tp.appliedVendorProviders(),
+	}
+	constraintsResult, err := ProcessConstraintsInDir(inputPath, constraintsOpts)
+	if err != nil {
+		return fmt.Errorf("failed to normalize schema constraints: %v", err)
+	}
+	results.ConstraintsResult = constraintsResult
+	if constraintsResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applyExtensionValuesStep replaces extension values through the lookup tables
+func (tp *TransformationPipeline) applyExtensionValuesStep(inputPath string, opts Options, results *TransformationResults) error {
+	if len(tp.Config.ExtensionValues) == 0 {
+		return nil
+	}
+
+	valuesOpts := ExtensionValuesOptions{
+		Options: opts,
+		Tables:  tp.Config.ExtensionValues,
+	}
+	valuesResult, err := ProcessExtensionValuesInDir(inputPath, valuesOpts)
+	if err != nil {
+		return fmt.Errorf("failed to replace extension values: %v", err)
+	}
+	results.ExtensionValuesResult = valuesResult
+	if valuesResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applyNullabilityStep enforces the nullability policy
+func (tp *TransformationPipeline) applyNullabilityStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.Nullability.Enabled {
+		return nil
+	}
+
+	nullabilityOpts := NullabilityOptions{
+		Options:     opts,
+		Nullability: tp.Config.Nullability,
+	}
+	nullabilityResult, err := ProcessNullabilityInDir(inputPath, nullabilityOpts)
+	if err != nil {
+		return fmt.Errorf("failed to enforce nullability policy: %v", err)
+	}
+	results.NullabilityResult = nullabilityResult
+	if nullabilityResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// appliedVendorProviders returns the vendor providers whose extensions this pipeline applies
+func (tp *TransformationPipeline) appliedVendorProviders() []string {
+	if !tp.Config.VendorExtensions.Enabled {
+		return nil
+	}
+	if len(tp.VendorProviders) > 0 {
+		return tp.VendorProviders
+	}
+	return configuredProviderNames(tp.Config)
+}
+
+// applyComponentDedupStep merges structurally identical components
+func (tp *TransformationPipeline) applyComponentDedupStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.ComponentDedup.Enabled {
+		return nil
+	}
+
+	dedupOpts := DedupOptions{
+		Options:        opts,
+		ComponentDedup: tp.Config.ComponentDedup,
+	}
+	dedupResult, err := ProcessComponentDedupInDir(inputPath, dedupOpts)
+	if err != nil {
+		return fmt.Errorf("failed to deduplicate components: %v", err)
+	}
+	results.DedupResult = dedupResult
+	if dedupResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applyComponentRenamesStep applies component renames and rewrites references
+func (tp *TransformationPipeline) applyComponentRenamesStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.ComponentRenames.Enabled {
+		return nil
+	}
+
+	renameOpts := RenameOptions{
+		Options:          opts,
+		ComponentRenames: tp.Config.ComponentRenames,
+	}
+	renameResult, err := ProcessComponentRenamesInDir(inputPath, renameOpts)
+	if err != nil {
+		return fmt.Errorf("failed to apply component renames: %v", err)
+	}
+	results.RenameResult = renameResult
+	if renameResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applySchemaTitlesStep fills in and normalizes the titles of component schemas
+func (tp *TransformationPipeline) applySchemaTitlesStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.SchemaTitles.Enabled {
+		return nil
+	}
+
+	titlesOpts := SchemaTitlesOptions{
+		Options:      opts,
+		SchemaTitles: tp.Config.SchemaTitles,
+	}
+	titlesResult, err := ProcessSchemaTitlesInDir(inputPath, titlesOpts)
+	if err != nil {
+		return fmt.Errorf("failed to apply schema titles: %v", err)
+	}
+	results.SchemaTitlesResult = titlesResult
+	if titlesResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applyCanonicalizeStep sorts paths and components and orders operation keys
+func (tp *TransformationPipeline) applyCanonicalizeStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.Canonicalize.Enabled {
+		return nil
+	}
+
+	canonicalizeOpts := CanonicalizeOptions{
+		Options:      opts,
+		Canonicalize: tp.Config.Canonicalize,
+		Placement:    tp.Config.ExtensionPlacement,
+	}
+	canonicalizeResult, err := ProcessCanonicalizeInDir(inputPath, canonicalizeOpts)
+	if err != nil {
+		return fmt.Errorf("failed to canonicalize documents: %v", err)
+	}
+	results.CanonicalizeResult = canonicalizeResult
+	if canonicalizeResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applyComponentConsistencyStep reports components defined differently under the same name in
+// several files and harmonizes them when configured to. A single input file has nothing to compare
+// against, so the single-file flow skips this step.
+func (tp *TransformationPipeline) applyComponentConsistencyStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.Consistency.Enabled {
+		return nil
+	}
+
+	consistencyOpts := ConsistencyOptions{
+		Options:              opts,
+		ComponentConsistency: tp.Config.Consistency,
+	}
+	consistencyResult, err := ProcessComponentConsistencyInDir(inputPath, consistencyOpts)
+	if err != nil {
+		return fmt.Errorf("failed to check component consistency: %v", err)
+	}
+	results.ConsistencyResult = consistencyResult
+	if consistencyResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applyExamplesStep repairs the examples that no longer match their schema
+func (tp *TransformationPipeline) applyExamplesStep(inputPath string, opts Options, results *TransformationResults) error {
+	if !tp.Config.Examples.Enabled {
+		return nil
+	}
+
+	examplesOpts := ExamplesOptions{
+		Options:  opts,
+		Examples: tp.Config.Examples,
+	}
+	examplesResult, err := ProcessExamplesInDir(inputPath, examplesOpts)
+	if err != nil {
+		return fmt.Errorf("failed to repair examples: %v", err)
+	}
+	results.ExamplesResult = examplesResult
+	if examplesResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// snapshotOperationsBefore snapshots the operations of every OpenAPI document before the pipeline
+// runs, so links and Arazzo workflows can follow the operations it renames or removes. Dry runs
+// write nothing to compare against, so they take no snapshot.
+func (tp *TransformationPipeline) snapshotOperationsBefore(inputPath string) (map[string][]operationRef, error) {
+	if tp.DryRun {
+		return nil, nil
+	}
+	before, err := snapshotOperations(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot operations: %v", err)
+	}
+	return before, nil
+}
+
+// applyLinkSyncStep updates the response links that name operations renamed or removed by the
+// pipeline. It only runs when the pipeline changed the operations of some document.
+func (*TransformationPipeline) applyLinkSyncStep(inputPath string, opts Options, changes *operationChanges, results *TransformationResults) error {
+	if changes == nil || !changes.hasChanges() {
+		return nil
+	}
+	linkResult, err := SyncOperationLinksInDir(inputPath, opts, changes)
+	if err != nil {
+		return fmt.Errorf("failed to sync links: %v", err)
+	}
+	results.LinkResult = linkResult
+	if linkResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
+
+// applyArazzoSyncStep updates Arazzo workflow steps that reference operations renamed by the pipeline.
+// In dry-run mode nothing is written, so only the detected documents are reported.
+func (tp *TransformationPipeline) applyArazzoSyncStep(documents []string, changes *operationChanges, results *TransformationResults) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	var renames map[string]*operationRenames
+	if changes != nil {
+		renames = changes.renames
+	}
+
+	arazzoResult, err := SyncArazzoDocuments(documents, renames, tp.DryRun)
+	if err != nil {
+		return fmt.Errorf("failed to sync Arazzo documents: %v", err)
+	}
+	results.ArazzoResult = arazzoResult
+	if arazzoResult.Changed {
+		results.AnyTransformations = true
+	}
+	return nil
+}
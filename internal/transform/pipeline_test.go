@@ -654,3 +654,48 @@ paths:
 		}
 	})
 }
+
+func TestExecuteVendorExtensions(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(input, []byte(variantsTestSpec), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	// Preview with one provider: nothing is written and the other steps do not run
+	pipeline := NewTransformationPipeline(variantsTestConfig(), []string{"fern"}, true, false, "")
+	results, err := pipeline.ExecuteVendorExtensions(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := results.VendorResult.OperationsTouched; len(got) != 1 || got["fern"] != 1 {
+		t.Errorf("expected fern to touch one operation, got %v", got)
+	}
+	if len(results.KeyChanges) != 0 {
+		t.Errorf("expected mappings not to run, got %v", results.KeyChanges)
+	}
+	data, _ := os.ReadFile(input)
+	if string(data) != variantsTestSpec {
+		t.Errorf("expected the preview not to write the input, got:\n%s", data)
+	}
+
+	// Apply every provider to an output file
+	output := filepath.Join(dir, "out.yaml")
+	pipeline = NewTransformationPipeline(variantsTestConfig(), nil, false, false, output)
+	results, err = pipeline.ExecuteVendorExtensions(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := results.VendorResult.OperationsTouched; got["fern"] != 1 || got["speakeasy"] != 1 {
+		t.Errorf("expected both providers to touch one operation, got %v", got)
+	}
+	data, err = os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	for _, want := range []string{"x-fern-pagination:", "x-speakeasy-pagination:", "x-internal-owner: platform"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Positions the steps insert extensions at, within operations and other mappings
+const (
+	ExtensionPlacementEnd              = "end"                // after every other key
+	ExtensionPlacementTop              = "top"                // after the extensions the mapping starts with
+	ExtensionPlacementAfterOperationID = "after_operation_id" // after operationId and the extensions following it
+	ExtensionPlacementAlphabetical     = "alphabetical"       // among the other extensions, in alphabetical order
+)
+
+// ValidateExtensionPlacement checks that extension_placement names a known position
+func ValidateExtensionPlacement(placement string) error {
+	switch placement {
+	case "", ExtensionPlacementEnd, ExtensionPlacementTop, ExtensionPlacementAfterOperationID, ExtensionPlacementAlphabetical:
+		return nil
+	}
+	return fmt.Errorf("extension_placement must be %s, %s, %s or %s, got %q",
+		ExtensionPlacementEnd, ExtensionPlacementTop, ExtensionPlacementAfterOperationID, ExtensionPlacementAlphabetical, placement)
+}
+
+// insertExtension adds the extension key with value to a mapping node at placement. Mappings
+// without an operationId take after_operation_id extensions at the top, and alphabetical ones go
+// last when the mapping has no extensions yet.
+func insertExtension(node *yaml.Node, key string, value *yaml.Node, placement string) {
+	position := extensionPosition(node, key, placement)
+	pair := []*yaml.Node{newScalarNode(key), value}
+	node.Content = append(node.Content[:position], append(pair, node.Content[position:]...)...)
+}
+
+// extensionPosition returns the index in the content of a mapping node the extension key goes at
+func extensionPosition(node *yaml.Node, key, placement string) int {
+	// afterExtensions skips the run of extensions starting at index i
+	afterExtensions := func(i int) int {
+		for i+1 < len(node.Content) && isExtensionKey(node.Content[i].Value) {
+			i += 2
+		}
+		return i
+	}
+
+	switch placement {
+	case ExtensionPlacementTop:
+		return afterExtensions(0)
+	case ExtensionPlacementAfterOperationID:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "operationId" {
+				return afterExtensions(i + 2)
+			}
+		}
+		return afterExtensions(0)
+	case ExtensionPlacementAlphabetical:
+		last := -1
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if existing := node.Content[i].Value; isExtensionKey(existing) {
+				if existing > key {
+					return i
+				}
+				last = i
+			}
+		}
+		if last >= 0 {
+			return last + 2
+		}
+	}
+	return len(node.Content)
+}
+
+// isExtensionKey reports whether a mapping key is a specification extension
+func isExtensionKey(key string) bool {
+	return strings.HasPrefix(key, "x-")
+}
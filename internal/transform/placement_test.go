@@ -0,0 +1,54 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestInsertExtension(t *testing.T) {
+	tests := []struct {
+		placement string
+		keys      string
+		want      string
+	}{
+		{ExtensionPlacementEnd, "summary x-a operationId responses", "summary x-a operationId responses x-m"},
+		{"", "summary operationId", "summary operationId x-m"},
+		{ExtensionPlacementTop, "summary operationId", "x-m summary operationId"},
+		{ExtensionPlacementTop, "x-a summary", "x-a x-m summary"},
+		{ExtensionPlacementAfterOperationID, "summary operationId x-a responses", "summary operationId x-a x-m responses"},
+		{ExtensionPlacementAfterOperationID, "summary responses", "x-m summary responses"},
+		{ExtensionPlacementAlphabetical, "x-a summary x-z responses", "x-a summary x-m x-z responses"},
+		{ExtensionPlacementAlphabetical, "x-a summary x-b responses", "x-a summary x-b x-m responses"},
+		{ExtensionPlacementAlphabetical, "summary responses", "summary responses x-m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.placement+" "+tt.keys, func(t *testing.T) {
+			node := &yaml.Node{Kind: yaml.MappingNode}
+			for _, key := range strings.Fields(tt.keys) {
+				node.Content = append(node.Content, newScalarNode(key), newScalarNode("v"))
+			}
+			insertExtension(node, "x-m", newScalarNode("v"), tt.placement)
+
+			var keys []string
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				keys = append(keys, node.Content[i].Value)
+			}
+			if got := strings.Join(keys, " "); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateExtensionPlacement(t *testing.T) {
+	for _, placement := range []string{"", ExtensionPlacementEnd, ExtensionPlacementTop, ExtensionPlacementAfterOperationID, ExtensionPlacementAlphabetical} {
+		if err := ValidateExtensionPlacement(placement); err != nil {
+			t.Errorf("%q: unexpected error %v", placement, err)
+		}
+	}
+	if err := ValidateExtensionPlacement("bottom"); err == nil || !strings.Contains(err.Error(), `got "bottom"`) {
+		t.Errorf("expected an error naming the placement, got %v", err)
+	}
+}
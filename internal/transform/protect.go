@@ -0,0 +1,298 @@
+package transform
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Items listed under protect: are guarded around every step: their contents are recorded before
+// the step runs, and whatever the step changed or removed is put back afterwards and reported as
+// a ProtectedSkip. Protected extension keys are also excluded from the key mappings.
+
+// ProtectedSkip is a change a step attempted on a protected item, which was undone
+type ProtectedSkip struct {
+	File   string
+	Step   string
+	Item   string // e.g. "paths./users", "components.schemas.User" or "x-internal-id at paths./users.get"
+	Reason string
+}
+
+// protectedItem is the recorded state of one protected item
+type protectedItem struct {
+	name    string   // how the item is reported
+	parent  []string // pointer segments of the mapping holding the item
+	key     string
+	value   *yaml.Node
+	encoded string // the value as YAML, to detect changes
+	prevKey string // the key before the item in its parent, to restore it in place
+}
+
+// ValidateProtect checks the protect: section of the config
+func ValidateProtect(protect config.Protect) error {
+	if err := ValidatePaths(protect.Paths); err != nil {
+		return fmt.Errorf("protect.paths: %v", strings.TrimPrefix(err.Error(), "only_paths: "))
+	}
+	for _, name := range protect.Components {
+		if name == "" || strings.Count(name, "/") > 1 {
+			return fmt.Errorf("protect.components: %q is not a name or section/name", name)
+		}
+	}
+	for _, key := range protect.Extensions {
+		if !strings.HasPrefix(key, "x-") {
+			return fmt.Errorf("protect.extensions: %q is not an extension key", key)
+		}
+	}
+	return nil
+}
+
+// isProtectEmpty reports whether nothing is protected
+func isProtectEmpty(protect config.Protect) bool {
+	return len(protect.Paths) == 0 && len(protect.Components) == 0 && len(protect.Extensions) == 0
+}
+
+// ProtectedExclude returns the keys the mappings must leave alone: the configured exclusions and
+// the protected extension keys
+func ProtectedExclude(cfg *config.Config) []string {
+	exclude := make([]string, 0, len(cfg.Exclude)+len(cfg.Protect.Extensions))
+	exclude = append(exclude, cfg.Exclude...)
+	for _, key := range cfg.Protect.Extensions {
+		if !contains(exclude, key) {
+			exclude = append(exclude, key)
+		}
+	}
+	return exclude
+}
+
+// normalizeProtectedSkips reports the skips recorded on a temporary copy against inputPath
+func normalizeProtectedSkips(inputPath string, results *TransformationResults) {
+	for i := range results.ProtectedSkips {
+		results.ProtectedSkips[i].File = inputPath
+	}
+}
+
+// protectStep runs a step that processes the files under dir and undoes its changes to the
+// protected items, recording each in results.ProtectedSkips
+func protectStep(protect config.Protect, dir, step string, results *TransformationResults, apply func() error) error {
+	if isProtectEmpty(protect) {
+		return apply()
+	}
+
+	before := make(map[string][]protectedItem)
+	err := walkOpenAPIDocuments(dir, func(path string, _, root *yaml.Node) error {
+		before[path] = snapshotProtected(root, protect)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := apply(); err != nil {
+		return err
+	}
+
+	return walkOpenAPIDocuments(dir, func(path string, doc, root *yaml.Node) error {
+		skips := restoreProtected(root, before[path])
+		if len(skips) == 0 {
+			return nil
+		}
+		for i := range skips {
+			skips[i].File = path
+			skips[i].Step = step
+		}
+		results.ProtectedSkips = append(results.ProtectedSkips, skips...)
+		_, err := writeModifiedDocument(doc, path)
+		return err
+	})
+}
+
+// walkOpenAPIDocuments calls fn with every OpenAPI document under dir
+func walkOpenAPIDocuments(dir string, fn func(path string, doc, root *yaml.Node) error) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(IsYAML(path) || IsJSON(path)) {
+			return nil
+		}
+		if reason, err := sniffFile(path); err != nil || reason != skipReasonNone {
+			return nil
+		}
+		doc, err := loadAndParseDocument(path)
+		if err != nil {
+			return nil // Left to the steps to report
+		}
+		root := getRootNode(doc)
+		if !isOpenAPIDocument(root) {
+			return nil
+		}
+		return fn(path, doc, root)
+	})
+}
+
+// snapshotProtected records the protected items of a document
+func snapshotProtected(root *yaml.Node, protect config.Protect) []protectedItem {
+	var items []protectedItem
+	if paths := getNodeValue(root, "paths"); paths != nil && paths.Kind == yaml.MappingNode && len(protect.Paths) > 0 {
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			if condition.MatchPath(paths.Content[i].Value, protect.Paths, condition.Glob) {
+				items = append(items, newProtectedItem("paths."+paths.Content[i].Value, []string{"paths"}, paths, i))
+			}
+		}
+	}
+
+	if components := getNodeValue(root, "components"); components != nil && components.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(components.Content); i += 2 {
+			section, entries := components.Content[i].Value, components.Content[i+1]
+			if entries.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j+1 < len(entries.Content); j += 2 {
+				name := entries.Content[j].Value
+				if contains(protect.Components, name) || contains(protect.Components, section+"/"+name) {
+					items = append(items, newProtectedItem("components."+section+"."+name, []string{"components", section}, entries, j))
+				}
+			}
+		}
+	}
+
+	if len(protect.Extensions) > 0 {
+		collectProtectedExtensions(root, nil, protect.Extensions, &items)
+	}
+	return items
+}
+
+// collectProtectedExtensions records the protected extension keys under node, whose pointer is parent
+func collectProtectedExtensions(node *yaml.Node, parent []string, keys []string, items *[]protectedItem) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if contains(keys, key) {
+				name := key + " at " + strings.Join(append([]string{"root"}, parent...), ".")
+				*items = append(*items, newProtectedItem(name, parent, node, i))
+				continue // The protected value is guarded as a whole
+			}
+			collectProtectedExtensions(node.Content[i+1], append(parent[:len(parent):len(parent)], key), keys, items)
+		}
+	case yaml.SequenceNode:
+		for i, element := range node.Content {
+			collectProtectedExtensions(element, append(parent[:len(parent):len(parent)], elementSegment(element, i)), keys, items)
+		}
+	}
+}
+
+// newProtectedItem records the pair at index i of the mapping node holding it
+func newProtectedItem(name string, parent []string, holder *yaml.Node, i int) protectedItem {
+	item := protectedItem{name: name, parent: parent, key: holder.Content[i].Value, value: holder.Content[i+1]}
+	item.encoded = encodeNode(item.value)
+	if i > 0 {
+		item.prevKey = holder.Content[i-2].Value
+	}
+	return item
+}
+
+// elementSegment identifies a sequence element by its name (and location, for parameters), so
+// the pointer survives steps that remove other elements, or by its index otherwise
+func elementSegment(element *yaml.Node, index int) string {
+	if name := getStringValue(element, "name"); element.Kind == yaml.MappingNode && name != "" {
+		if in := getStringValue(element, "in"); in != "" {
+			return "[" + name + "@" + in + "]"
+		}
+		return "[" + name + "]"
+	}
+	return "[" + strconv.Itoa(index) + "]"
+}
+
+// resolveSegments returns the node at the pointer segments under root, or nil
+func resolveSegments(root *yaml.Node, pointer []string) *yaml.Node {
+	current := root
+	for _, segment := range pointer {
+		switch current.Kind {
+		case yaml.MappingNode:
+			current = getNodeValue(current, segment)
+		case yaml.SequenceNode:
+			var next *yaml.Node
+			for i, element := range current.Content {
+				if elementSegment(element, i) == segment {
+					next = element
+					break
+				}
+			}
+			current = next
+		default:
+			return nil
+		}
+		if current == nil {
+			return nil
+		}
+	}
+	return current
+}
+
+// restoreProtected puts back the protected items the step changed or removed and returns a skip
+// for each
+func restoreProtected(root *yaml.Node, items []protectedItem) []ProtectedSkip {
+	var skips []ProtectedSkip
+	for _, item := range items {
+		parent := resolveSegments(root, item.parent)
+		if parent == nil && len(item.parent) <= 2 && (item.parent[0] == "paths" || item.parent[0] == "components") {
+			parent = ensureMappingPath(root, item.parent)
+		}
+		if parent == nil || parent.Kind != yaml.MappingNode {
+			skips = append(skips, ProtectedSkip{Item: item.name, Reason: "its parent was removed or renamed, so it could not be restored"})
+			continue
+		}
+
+		index := mappingKeyIndex(parent, item.key)
+		switch {
+		case index < len(parent.Content) && encodeNode(parent.Content[index+1]) == item.encoded:
+			continue
+		case index < len(parent.Content):
+			parent.Content[index+1] = item.value
+			skips = append(skips, ProtectedSkip{Item: item.name, Reason: "modification undone"})
+		default:
+			position := 0
+			if item.prevKey != "" {
+				position = min(mappingKeyIndex(parent, item.prevKey)+2, len(parent.Content))
+			}
+			pair := []*yaml.Node{newScalarNode(item.key), item.value}
+			parent.Content = append(parent.Content[:position], append(pair, parent.Content[position:]...)...)
+			skips = append(skips, ProtectedSkip{Item: item.name, Reason: "removal undone"})
+		}
+	}
+	return skips
+}
+
+// ensureMappingPath returns the mapping at path under root, creating the missing levels
+func ensureMappingPath(root *yaml.Node, path []string) *yaml.Node {
+	current := root
+	for _, segment := range path {
+		next := getNodeValue(current, segment)
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			current.Content = append(current.Content, newScalarNode(segment), next)
+		}
+		if next.Kind != yaml.MappingNode {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// encodeNode returns a node as YAML, or an empty string when it cannot be encoded
+func encodeNode(node *yaml.Node) string {
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
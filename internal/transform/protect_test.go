@@ -0,0 +1,161 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const protectSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-internal: true
+      x-operation-group-name: users
+      responses:
+        "200":
+          description: OK
+  /billing:
+    get:
+      x-internal: true
+      x-operation-group-name: billing
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    Secret:
+      type: object
+      x-internal: true
+`
+
+func TestValidateProtect(t *testing.T) {
+	valid := config.Protect{Paths: []string{"/billing*"}, Components: []string{"Money", "schemas/Page"}, Extensions: []string{"x-internal-id"}}
+	if err := ValidateProtect(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, protect := range []config.Protect{
+		{Paths: []string{""}},
+		{Components: []string{"a/b/c"}},
+		{Extensions: []string{"internal-id"}},
+	} {
+		if err := ValidateProtect(protect); err == nil {
+			t.Errorf("expected an error for %+v", protect)
+		}
+	}
+}
+
+func TestProtectedItemsSurviveSteps(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(protectSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Mappings:      map[string]string{"x-operation-group-name": "x-group"},
+		StripInternal: config.StripInternal{Enabled: true},
+		Protect: config.Protect{
+			Paths:      []string{"/billing"},
+			Components: []string{"schemas/Secret"},
+		},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if strings.Contains(content, "/users") {
+		t.Errorf("expected the unprotected internal path to be stripped, got:\n%s", content)
+	}
+	if !strings.Contains(content, "/billing") || !strings.Contains(content, "x-operation-group-name: billing") {
+		t.Errorf("expected /billing to be left as it was, got:\n%s", content)
+	}
+	if !strings.Contains(content, "Secret:") {
+		t.Errorf("expected the protected component to be kept, got:\n%s", content)
+	}
+
+	var skips []string
+	for _, skip := range results.ProtectedSkips {
+		if skip.File != path {
+			t.Errorf("expected skips to be reported against %s, got %s", path, skip.File)
+		}
+		skips = append(skips, skip.Item+" "+skip.Step+": "+skip.Reason)
+	}
+	want := []string{
+		"paths./billing " + StepMappings + ": modification undone",
+		"paths./billing " + StepStripInternal + ": removal undone",
+		"components.schemas.Secret " + StepStripInternal + ": removal undone",
+	}
+	if strings.Join(skips, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected skips:\n%s", strings.Join(skips, "\n"))
+	}
+}
+
+func TestProtectedExtensionsAreExcludedFromMappings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(protectSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Mappings: map[string]string{"x-operation-group-name": "x-group"},
+		Exclude:  []string{"x-other"},
+		Protect:  config.Protect{Extensions: []string{"x-operation-group-name"}},
+	}
+	if got := ProtectedExclude(cfg); strings.Join(got, ",") != "x-other,x-operation-group-name" {
+		t.Errorf("unexpected exclusions %v", got)
+	}
+
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.KeyChanges) != 0 {
+		t.Errorf("expected no key changes, got %+v", results.KeyChanges)
+	}
+	if len(cfg.Exclude) != 1 {
+		t.Errorf("expected the config exclusions to be left alone, got %v", cfg.Exclude)
+	}
+}
+
+func TestProtectedSkipsWithOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "api.yaml")
+	output := filepath.Join(dir, "out.yaml")
+	if err := os.WriteFile(input, []byte(protectSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		StripInternal: config.StripInternal{Enabled: true},
+		Protect:       config.Protect{Paths: []string{"/billing"}},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, output).ExecuteFullPipeline(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.ProtectedSkips) != 1 || results.ProtectedSkips[0].File != input {
+		t.Fatalf("expected one skip reported against the input, got %+v", results.ProtectedSkips)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "/billing") || strings.Contains(string(data), "/users") {
+		t.Errorf("expected only /billing to be kept, got:\n%s", data)
+	}
+}
@@ -0,0 +1,283 @@
+package transform
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// ProvenanceExtension is the root key a stamp is written under in extension mode
+const ProvenanceExtension = "x-openmorph"
+
+// ProvenanceSuffix is appended to the path of a document for its stamp in sidecar mode. It has no
+// YAML/JSON extension, so the steps never process sidecars.
+const ProvenanceSuffix = ".provenance"
+
+// Provenance modes
+const (
+	ProvenanceModeExtension = "extension"
+	ProvenanceModeSidecar   = "sidecar"
+)
+
+// SourceDateEpochEnv names the environment variable that fixes the time recorded in stamps, in
+// seconds since the Unix epoch, so that identical runs produce identical output
+const SourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// ProvenanceStamp records which pipeline produced a document
+type ProvenanceStamp struct {
+	Tool      string `yaml:"tool" json:"tool"`
+	Version   string `yaml:"version" json:"version"`
+	Config    string `yaml:"config" json:"config"`       // sha256 of the settings of the run
+	Generated string `yaml:"generated" json:"generated"` // RFC 3339 time of the run
+	Checksum  string `yaml:"checksum" json:"checksum"`   // sha256 of the document without its stamp, see DocumentChecksum
+}
+
+// ProvenanceCheck is the stamp of a document compared with its current contents
+type ProvenanceCheck struct {
+	File    string
+	Stamp   *ProvenanceStamp // nil when the document carries none
+	Sidecar bool             // the stamp was read from a sidecar file
+	Edited  bool             // the document changed since it was stamped
+}
+
+// ValidateProvenance checks the provenance mode, and SOURCE_DATE_EPOCH when stamps are written
+func ValidateProvenance(provenance config.Provenance) error {
+	if provenance.Enabled {
+		if _, err := stampTime(); err != nil {
+			return err
+		}
+	}
+	switch provenance.Mode {
+	case "", ProvenanceModeExtension, ProvenanceModeSidecar:
+		return nil
+	default:
+		return fmt.Errorf("provenance.mode: unknown mode %q, expected %s or %s", provenance.Mode, ProvenanceModeExtension, ProvenanceModeSidecar)
+	}
+}
+
+// snapshotForProvenance keeps the contents of the inputs when provenance is enabled, so the
+// documents the run changed can be stamped afterwards
+func (tp *TransformationPipeline) snapshotForProvenance(inputPath string, opts Options) (map[string][]byte, error) {
+	if !tp.Config.Provenance.Enabled || opts.DryRun {
+		return nil, nil
+	}
+	return snapshotInputs(inputPath)
+}
+
+// stampProvenance stamps every document under dir whose contents differ from originals. Files that
+// failed in a keep-going run are left alone.
+func (tp *TransformationPipeline) stampProvenance(dir string, opts Options, originals map[string][]byte, results *TransformationResults) error {
+	if originals == nil {
+		return nil
+	}
+
+	generated, err := stampTime()
+	if err != nil {
+		return err
+	}
+	stamp := ProvenanceStamp{
+		Tool:      "openmorph",
+		Version:   tp.Version,
+		Config:    configHash(tp.Config),
+		Generated: generated.Format(time.RFC3339),
+	}
+	err = walkOpenAPIDocuments(dir, func(path string, doc, _ *yaml.Node) error {
+		if !IncludesFile(opts.Files, "", dir, path) || opts.failures.has(path) {
+			return nil
+		}
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if original, ok := originals[path]; ok && bytes.Equal(original, current) {
+			return nil
+		}
+		if err := writeProvenance(doc, path, stamp, tp.Config.Provenance.Mode); err != nil {
+			return fmt.Errorf("failed to stamp %s: %w", path, err)
+		}
+		results.StampedFiles = append(results.StampedFiles, path)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record provenance: %v", err)
+	}
+	return nil
+}
+
+// stampTime returns the time recorded in stamps: SOURCE_DATE_EPOCH when set, otherwise now
+func stampTime() (time.Time, error) {
+	epoch := os.Getenv(SourceDateEpochEnv)
+	if epoch == "" {
+		return time.Now().UTC(), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a number of seconds, got %q", SourceDateEpochEnv, epoch)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// writeProvenance stamps the document at path. The checksum is taken from the document as written,
+// so re-reading it yields the same checksum.
+func writeProvenance(doc *yaml.Node, path string, stamp ProvenanceStamp, mode string) error {
+	removeMappingKey(getRootNode(doc), ProvenanceExtension)
+	if _, err := writeModifiedDocument(doc, path); err != nil {
+		return err
+	}
+	written, err := loadAndParseDocument(path)
+	if err != nil {
+		return err
+	}
+	stamp.Checksum = DocumentChecksum(written)
+
+	if mode == ProvenanceModeSidecar {
+		data, err := json.MarshalIndent(stamp, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path+ProvenanceSuffix, append(data, '\n'), 0600)
+	}
+
+	var value yaml.Node
+	if err := value.Encode(stamp); err != nil {
+		return err
+	}
+	root := getRootNode(written)
+	root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: ProvenanceExtension}, &value)
+	_, err = writeModifiedDocument(written, path)
+	return err
+}
+
+// CheckProvenance compares the stamp of every OpenAPI document under inputPath, at its root or in
+// its sidecar, with the document's current contents
+func CheckProvenance(inputPath string, files config.FileFilter) ([]ProvenanceCheck, error) {
+	var checks []ProvenanceCheck
+	err := walkOpenAPIDocuments(inputPath, func(path string, doc, root *yaml.Node) error {
+		if !IncludesFile(files, "", inputPath, path) {
+			return nil
+		}
+		check, err := checkDocumentProvenance(path, doc, root)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		checks = append(checks, check)
+		return nil
+	})
+	return checks, err
+}
+
+// checkDocumentProvenance reads the stamp of one document
+func checkDocumentProvenance(path string, doc, root *yaml.Node) (ProvenanceCheck, error) {
+	check := ProvenanceCheck{File: path}
+	var stamp ProvenanceStamp
+	if value := getNodeValue(root, ProvenanceExtension); value != nil && value.Kind == yaml.MappingNode {
+		if err := value.Decode(&stamp); err != nil {
+			return check, fmt.Errorf("invalid %s: %w", ProvenanceExtension, err)
+		}
+	} else {
+		data, err := os.ReadFile(path + ProvenanceSuffix)
+		if errors.Is(err, os.ErrNotExist) {
+			return check, nil
+		}
+		if err != nil {
+			return check, err
+		}
+		if err := json.Unmarshal(data, &stamp); err != nil {
+			return check, fmt.Errorf("invalid %s: %w", path+ProvenanceSuffix, err)
+		}
+		check.Sidecar = true
+	}
+
+	check.Stamp = &stamp
+	check.Edited = DocumentChecksum(doc) != stamp.Checksum
+	return check, nil
+}
+
+// DocumentChecksum returns the sha256 of a document's contents, leaving out its x-openmorph stamp.
+// Formatting, comments and the order of mapping keys don't count, so a document re-serialized as
+// YAML or JSON keeps its checksum while any edit to its values changes it.
+func DocumentChecksum(doc *yaml.Node) string {
+	sum := hashNode(getRootNode(doc), true)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// hashNode hashes a node tree; at the root, the provenance stamp is skipped
+func hashNode(node *yaml.Node, root bool) [sha256.Size]byte {
+	h := sha256.New()
+	switch node.Kind {
+	case yaml.AliasNode:
+		return hashNode(node.Alias, false)
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return hashNode(node.Content[0], root)
+		}
+	case yaml.ScalarNode:
+		fmt.Fprintf(h, "scalar:%s:%s", node.ShortTag(), node.Value)
+	case yaml.SequenceNode:
+		h.Write([]byte("sequence:"))
+		for _, child := range node.Content {
+			sum := hashNode(child, false)
+			h.Write(sum[:])
+		}
+	case yaml.MappingNode:
+		var pairs []string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if root && node.Content[i].Value == ProvenanceExtension {
+				continue
+			}
+			key, value := hashNode(node.Content[i], false), hashNode(node.Content[i+1], false)
+			pairs = append(pairs, hex.EncodeToString(key[:])+hex.EncodeToString(value[:]))
+		}
+		sort.Strings(pairs)
+		h.Write([]byte("mapping:"))
+		for _, pair := range pairs {
+			h.Write([]byte(pair))
+		}
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// configHash identifies the settings of a run, leaving out where its input and output are
+func configHash(cfg *config.Config) string {
+	settings := *cfg
+	settings.Input, settings.Output = "", ""
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// copySidecar copies the sidecar stamp of a document next to its copy, if it has one
+func copySidecar(src, dst string) error {
+	data, err := os.ReadFile(src + ProvenanceSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst+ProvenanceSuffix, data, 0600); err != nil {
+		return fmt.Errorf("failed to write provenance sidecar: %v", err)
+	}
+	return nil
+}
+
+// normalizeStampedFiles reports the files stamped on a temporary copy against inputPath
+func normalizeStampedFiles(inputPath string, results *TransformationResults) {
+	results.StampedFiles = normalizeResultPaths(inputPath, results.StampedFiles)
+}
@@ -0,0 +1,146 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const provenanceSpec = `openapi: 3.0.3
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-foo: true
+      responses:
+        "200":
+          description: OK
+`
+
+func provenanceConfig(mode string) *config.Config {
+	return &config.Config{
+		Mappings:   map[string]string{"x-foo": "x-bar"},
+		Provenance: config.Provenance{Enabled: true, Mode: mode},
+	}
+}
+
+func TestStampProvenanceExtension(t *testing.T) {
+	dir := t.TempDir()
+	changed := filepath.Join(dir, "users.yaml")
+	unchanged := filepath.Join(dir, "plain.json")
+	if err := os.WriteFile(changed, []byte(provenanceSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	plain := `{"openapi": "3.0.3", "info": {"title": "API", "version": "1.0.0"}, "paths": {}}`
+	if err := os.WriteFile(unchanged, []byte(plain), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tp := NewTransformationPipeline(provenanceConfig(""), nil, false, false, "")
+	tp.Version = "1.2.3"
+	results, err := tp.ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+	if len(results.StampedFiles) != 1 || results.StampedFiles[0] != changed {
+		t.Fatalf("expected only the changed file to be stamped, got %v", results.StampedFiles)
+	}
+
+	data, err := os.ReadFile(changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"x-openmorph:", "tool: openmorph", "version: 1.2.3", "config: sha256:", "checksum: sha256:"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected the stamp to contain %q:\n%s", want, data)
+		}
+	}
+
+	checks, err := CheckProvenance(dir, config.FileFilter{})
+	if err != nil {
+		t.Fatalf("CheckProvenance failed: %v", err)
+	}
+	stamped := map[string]ProvenanceCheck{}
+	for _, check := range checks {
+		stamped[check.File] = check
+	}
+	if check := stamped[changed]; check.Stamp == nil || check.Edited || check.Sidecar {
+		t.Errorf("expected an unedited stamp for %s, got %+v", changed, check)
+	}
+	if check := stamped[unchanged]; check.Stamp != nil {
+		t.Errorf("expected no stamp for the unchanged file, got %+v", check.Stamp)
+	}
+
+	edited := strings.Replace(string(data), "description: OK", "description: Edited", 1)
+	if err := os.WriteFile(changed, []byte(edited), 0600); err != nil {
+		t.Fatal(err)
+	}
+	checks, err = CheckProvenance(changed, config.FileFilter{})
+	if err != nil {
+		t.Fatalf("CheckProvenance failed: %v", err)
+	}
+	if len(checks) != 1 || !checks[0].Edited {
+		t.Errorf("expected the edit to be detected, got %+v", checks)
+	}
+}
+
+func TestStampProvenanceSidecarSingleFile(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "users.json")
+	json := `{"openapi": "3.0.3", "info": {"title": "API", "version": "1.0.0"}, "paths": {"/users": {"get": {"x-foo": true, "responses": {"200": {"description": "OK"}}}}}}`
+	if err := os.WriteFile(input, []byte(json), 0600); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(t.TempDir(), "out.json")
+
+	results, err := NewTransformationPipeline(provenanceConfig(ProvenanceModeSidecar), nil, false, false, output).ExecuteFullPipeline(input)
+	if err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+	if len(results.StampedFiles) != 1 || results.StampedFiles[0] != input {
+		t.Fatalf("expected the input to be reported as stamped, got %v", results.StampedFiles)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), ProvenanceExtension) {
+		t.Errorf("expected no stamp in the document in sidecar mode:\n%s", data)
+	}
+	checks, err := CheckProvenance(output, config.FileFilter{})
+	if err != nil {
+		t.Fatalf("CheckProvenance failed: %v", err)
+	}
+	if len(checks) != 1 || checks[0].Stamp == nil || !checks[0].Sidecar || checks[0].Edited {
+		t.Errorf("expected an unedited sidecar stamp next to the output, got %+v", checks)
+	}
+}
+
+func TestDocumentChecksumIgnoresFormatting(t *testing.T) {
+	yamlDoc := parseYAMLToNode(t, "openapi: 3.0.3\ninfo:\n  title: API # comment\n  version: 1.0.0\n")
+	jsonDoc := parseYAMLToNode(t, `{"info": {"version": "1.0.0", "title": "API"}, "openapi": "3.0.3"}`)
+	if DocumentChecksum(yamlDoc) != DocumentChecksum(jsonDoc) {
+		t.Error("expected formatting, comments and key order not to change the checksum")
+	}
+
+	edited := parseYAMLToNode(t, "openapi: 3.0.3\ninfo:\n  title: API\n  version: 1.0.1\n")
+	if DocumentChecksum(yamlDoc) == DocumentChecksum(edited) {
+		t.Error("expected an edited value to change the checksum")
+	}
+}
+
+func TestValidateProvenance(t *testing.T) {
+	for _, mode := range []string{"", ProvenanceModeExtension, ProvenanceModeSidecar} {
+		if err := ValidateProvenance(config.Provenance{Mode: mode}); err != nil {
+			t.Errorf("mode %q: unexpected error %v", mode, err)
+		}
+	}
+	if err := ValidateProvenance(config.Provenance{Mode: "trailer"}); err == nil {
+		t.Error("expected an unknown mode to be rejected")
+	}
+}
@@ -0,0 +1,172 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// PrunedContainer is a path item, request body or parameters list a step left empty, and that was
+// removed after it
+type PrunedContainer struct {
+	StrictIssue
+	Step string // step after which it became empty
+}
+
+// pruneEmptyAfterStep runs a step that processes the files under dir and, when it is one that can
+// remove content and it changed something, removes the path items left without operations, the
+// request bodies left without content and the parameters lists left empty, recording them in
+// results.PrunedContainers. Containers that were already empty before the step are left alone.
+// Dry runs leave the files as they were, so there is nothing to prune.
+func (tp *TransformationPipeline) pruneEmptyAfterStep(dir, step string, opts Options, results *TransformationResults, apply func() error) error {
+	if tp.Config.PruneEmpty.Disabled || opts.DryRun || !destructiveStepEnabled(tp.Config, step) {
+		return apply()
+	}
+
+	// Steps in between may rename paths, so the baseline is taken right before each step
+	before, err := pruneEmptyContainers(dir, opts.Files, nil, false)
+	if err != nil {
+		return fmt.Errorf("failed to check for empty containers: %v", err)
+	}
+	baseline := prunedKeys(before)
+
+	if err := apply(); err != nil {
+		return err
+	}
+	if changes, _ := stepChanges(step, results); changes == 0 {
+		return nil
+	}
+
+	empty, err := pruneEmptyContainers(dir, opts.Files, baseline, true)
+	if err != nil {
+		return fmt.Errorf("failed to prune empty containers: %v", err)
+	}
+	for _, container := range empty {
+		if !baseline[refKey(container.StrictIssue)] {
+			container.Step = step
+			results.PrunedContainers = append(results.PrunedContainers, container)
+		}
+	}
+	return nil
+}
+
+// pruneEmptyContainers returns the empty containers of the OpenAPI documents under dir. With
+// prune, those not in baseline are removed and their documents written.
+func pruneEmptyContainers(dir string, files config.FileFilter, baseline map[string]bool, prune bool) ([]PrunedContainer, error) {
+	var empty []PrunedContainer
+	err := walkOpenAPIDocuments(dir, func(path string, doc, root *yaml.Node) error {
+		if !IncludesFile(files, "", dir, path) {
+			return nil
+		}
+		found, pruned := documentEmptyContainers(path, root, baseline, prune)
+		empty = append(empty, found...)
+		if pruned {
+			_, err := writeModifiedDocument(doc, path)
+			return err
+		}
+		return nil
+	})
+	return empty, err
+}
+
+// documentEmptyContainers returns the empty containers of a document and whether any was removed.
+// A container is removed only when prune is set and it is not in baseline, so a path item that
+// was already empty stays even when it is reported again.
+func documentEmptyContainers(path string, root *yaml.Node, baseline map[string]bool, prune bool) ([]PrunedContainer, bool) {
+	var empty []PrunedContainer
+	pruned := false
+	check := func(node *yaml.Node, message string) bool {
+		container := PrunedContainer{StrictIssue: StrictIssue{File: path, Line: node.Line, Column: node.Column, Message: message}}
+		empty = append(empty, container)
+		if prune && !baseline[refKey(container.StrictIssue)] {
+			pruned = true
+			return true
+		}
+		return false
+	}
+	pruneParameters := func(node *yaml.Node, context string) {
+		parameters := getNodeValue(node, "parameters")
+		if parameters != nil && parameters.Kind == yaml.SequenceNode && len(parameters.Content) == 0 &&
+			check(parameters, context+": parameters list is empty") {
+			removeMappingKey(node, "parameters")
+		}
+	}
+
+	for _, section := range []string{"paths", "webhooks"} {
+		items := getNodeValue(root, section)
+		if items == nil || items.Kind != yaml.MappingNode {
+			continue
+		}
+
+		var kept []*yaml.Node
+		for i := 0; i+1 < len(items.Content); i += 2 {
+			name, item := items.Content[i].Value, items.Content[i+1]
+			if !isEmptyPathItem(item) {
+				pruneParameters(item, section+"."+name)
+				for j := 0; j+1 < len(item.Content); j += 2 {
+					method, operation := item.Content[j].Value, item.Content[j+1]
+					if !isHTTPMethod(method) || operation.Kind != yaml.MappingNode {
+						continue
+					}
+					context := strings.ToUpper(method) + " " + name
+					pruneParameters(operation, context)
+					if body := getNodeValue(operation, "requestBody"); isEmptyRequestBody(body) &&
+						check(body, context+": request body has no content") {
+						removeMappingKey(operation, "requestBody")
+					}
+				}
+			} else if check(item, section+"."+name+": path item has no operations") {
+				continue
+			}
+			kept = append(kept, items.Content[i], item)
+		}
+		items.Content = kept
+	}
+	return empty, pruned
+}
+
+// isEmptyPathItem reports whether a path item is null or holds no operation and no $ref. Servers,
+// parameters and extensions describe operations, so they are nothing without one.
+func isEmptyPathItem(item *yaml.Node) bool {
+	if item.Kind == yaml.ScalarNode {
+		return item.ShortTag() == "!!null"
+	}
+	if item.Kind != yaml.MappingNode || getNodeValue(item, "$ref") != nil {
+		return false
+	}
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if isHTTPMethod(item.Content[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmptyRequestBody reports whether an inline request body has an empty content mapping, or
+// none, which OpenAPI requires
+func isEmptyRequestBody(body *yaml.Node) bool {
+	if body == nil || body.Kind != yaml.MappingNode || getNodeValue(body, "$ref") != nil {
+		return false
+	}
+	content := getNodeValue(body, "content")
+	return content == nil || (content.Kind == yaml.MappingNode && len(content.Content) == 0)
+}
+
+// prunedKeys returns the keys of the given empty containers
+func prunedKeys(empty []PrunedContainer) map[string]bool {
+	keys := make(map[string]bool, len(empty))
+	for _, container := range empty {
+		keys[refKey(container.StrictIssue)] = true
+	}
+	return keys
+}
+
+// normalizePrunedContainers reports the containers pruned on a temporary copy against inputPath
+func normalizePrunedContainers(inputPath string, results *TransformationResults) {
+	for i := range results.PrunedContainers {
+		results.PrunedContainers[i].File = inputPath
+	}
+}
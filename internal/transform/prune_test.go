@@ -0,0 +1,259 @@
+package transform
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const pruneSpec = `openapi: 3.0.3
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: debug
+          in: query
+          x-internal: true
+          schema:
+            type: boolean
+      responses:
+        "200":
+          description: OK
+  /shared:
+    parameters:
+      - name: tenant
+        in: header
+        schema:
+          type: string
+`
+
+func TestPruneEmptyAfterStep(t *testing.T) {
+	dir, path := writeTestSpec(t, pruneSpec)
+
+	cfg := &config.Config{StripInternal: config.StripInternal{Enabled: true}}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []string
+	for _, container := range results.PrunedContainers {
+		if container.Step != StepStripInternal || container.Line == 0 {
+			t.Errorf("unexpected pruned container %+v", container)
+		}
+		messages = append(messages, container.Message)
+	}
+	// /shared had no operations before the step, so it is left alone
+	if got, want := strings.Join(messages, "\n"), "GET /users: parameters list is empty"; got != want {
+		t.Errorf("unexpected pruned containers:\n%s", got)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "parameters: []") {
+		t.Errorf("expected the empty parameters list to be removed, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "/shared:") {
+		t.Errorf("expected the path item that was empty before to be kept, got:\n%s", data)
+	}
+}
+
+func TestPruneEmptyDisabled(t *testing.T) {
+	dir, path := writeTestSpec(t, pruneSpec)
+
+	cfg := &config.Config{
+		StripInternal: config.StripInternal{Enabled: true},
+		PruneEmpty:    config.PruneEmpty{Disabled: true},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.PrunedContainers) != 0 {
+		t.Errorf("expected no pruning when disabled, got %+v", results.PrunedContainers)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "parameters: []") {
+		t.Errorf("expected the empty parameters list to be kept, got:\n%s", data)
+	}
+}
+
+func TestDocumentEmptyContainers(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		baseline []string
+		want     []string
+		left     string
+	}{
+		{
+			name: "path item without operations",
+			spec: `paths:
+  /users:
+    summary: Users
+    parameters:
+      - name: id
+        in: path
+  /health:
+    get:
+      responses: {}
+`,
+			want: []string{"paths./users: path item has no operations"},
+			left: "paths:\n    /health:\n        get:\n            responses: {}\n",
+		},
+		{
+			name: "null path item and webhook",
+			spec: `paths:
+  /users:
+webhooks:
+  created: {}
+`,
+			want: []string{"paths./users: path item has no operations", "webhooks.created: path item has no operations"},
+			left: "paths: {}\nwebhooks: {}\n",
+		},
+		{
+			name: "path item reference is kept",
+			spec: `paths:
+  /users:
+    $ref: '#/components/pathItems/Users'
+`,
+			left: "paths:\n    /users:\n        $ref: '#/components/pathItems/Users'\n",
+		},
+		{
+			name: "request body without content",
+			spec: `paths:
+  /users:
+    post:
+      requestBody:
+        required: true
+        content: {}
+      responses: {}
+`,
+			want: []string{"POST /users: request body has no content"},
+			left: "paths:\n    /users:\n        post:\n            responses: {}\n",
+		},
+		{
+			name: "empty parameters lists",
+			spec: `paths:
+  /users:
+    parameters: []
+    get:
+      parameters: []
+      responses: {}
+`,
+			want: []string{"paths./users: parameters list is empty", "GET /users: parameters list is empty"},
+			left: "paths:\n    /users:\n        get:\n            responses: {}\n",
+		},
+		{
+			name: "already empty before the step",
+			spec: `paths:
+  /users:
+    get:
+      parameters: []
+      responses: {}
+`,
+			baseline: []string{"GET /users: parameters list is empty"},
+			want:     []string{"GET /users: parameters list is empty"},
+			left:     "paths:\n    /users:\n        get:\n            parameters: []\n            responses: {}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.spec), &doc); err != nil {
+				t.Fatal(err)
+			}
+			baseline := make(map[string]bool)
+			for _, message := range tt.baseline {
+				baseline[refKey(StrictIssue{File: "api.yaml", Message: message})] = true
+			}
+
+			empty, _ := documentEmptyContainers("api.yaml", getRootNode(&doc), baseline, true)
+			var messages []string
+			for _, container := range empty {
+				messages = append(messages, container.Message)
+			}
+			if strings.Join(messages, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("expected %q, got %q", tt.want, messages)
+			}
+
+			output, err := formatAsYAML(&doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(output) != tt.left {
+				t.Errorf("unexpected document left:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestPruneEmptyAfterPathRenames(t *testing.T) {
+	dir, path := writeTestSpec(t, `openapi: 3.0.3
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /a:
+    get:
+      parameters: []
+      responses:
+        "200":
+          description: OK
+  /b: {}
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: offset
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+`)
+
+	// tenant_paths renames every path between strip_internal and pagination
+	cfg := &config.Config{
+		StripInternal:      config.StripInternal{Enabled: true},
+		TenantPaths:        config.TenantPaths{Enabled: true},
+		PaginationPriority: []string{"cursor", "offset"},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.PaginationResult == nil || !results.PaginationResult.Changed {
+		t.Fatal("expected the pagination step to change the document")
+	}
+	if len(results.PrunedContainers) != 0 {
+		t.Errorf("expected the containers that were empty before to be left alone, got %+v", results.PrunedContainers)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"/{tenantId}/b:", "parameters: []"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q to be kept, got:\n%s", want, data)
+		}
+	}
+}
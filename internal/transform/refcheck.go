@@ -0,0 +1,92 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// destructiveStepEnabled reports whether step is enabled and is one that removes, renames or
+// replaces content, and can therefore leave $refs dangling or schemas empty
+func destructiveStepEnabled(cfg *config.Config, step string) bool {
+	switch step {
+	case StepStripInternal:
+		return cfg.StripInternal.Enabled
+	case StepUnwrapEnvelopes:
+		return cfg.UnwrapEnvelopes.Enabled
+	case StepPagination:
+		return len(cfg.PaginationPriority) > 0
+	case StepFlatten:
+		return cfg.FlattenResponses
+	case StepComponentDedup:
+		return cfg.ComponentDedup.Enabled
+	case StepComponentRenames:
+		return cfg.ComponentRenames.Enabled
+	case StepComponentConsistency:
+		return cfg.Consistency.Enabled && cfg.Consistency.Harmonize
+	}
+	return false
+}
+
+// DanglingRef is a $ref a step left pointing at a node that no longer exists
+type DanglingRef struct {
+	StrictIssue
+	Step string // step after which the reference stopped resolving
+}
+
+// refKey identifies a dangling reference independently of its position, which moves as steps
+// edit the file
+func refKey(issue StrictIssue) string {
+	return issue.File + "\x00" + issue.Message
+}
+
+// checkRefsAfterStep runs a step that processes the files under dir and, when it is one that can
+// leave references dangling and it changed something, records the $refs that stopped resolving in
+// results.DanglingRefs. References that were already dangling before the step are not reported.
+// Dry runs leave the files as they were, so there is nothing to check.
+func (tp *TransformationPipeline) checkRefsAfterStep(dir, step string, opts Options, results *TransformationResults, apply func() error) error {
+	if tp.Config.RefCheck.Disabled || opts.DryRun || !destructiveStepEnabled(tp.Config, step) {
+		return apply()
+	}
+
+	// Steps in between may move the references, so the baseline is taken right before each step
+	before, err := checkRefs(dir, opts.Files, opts.failures != nil)
+	if err != nil {
+		return fmt.Errorf("failed to check references: %v", err)
+	}
+	baseline := refKeys(before)
+
+	if err := apply(); err != nil {
+		return err
+	}
+	if changes, _ := stepChanges(step, results); changes == 0 {
+		return nil
+	}
+
+	issues, err := checkRefs(dir, opts.Files, opts.failures != nil)
+	if err != nil {
+		return fmt.Errorf("failed to check references: %v", err)
+	}
+	for _, issue := range issues {
+		if !baseline[refKey(issue)] {
+			results.DanglingRefs = append(results.DanglingRefs, DanglingRef{StrictIssue: issue, Step: step})
+		}
+	}
+	return nil
+}
+
+// refKeys returns the keys of the given dangling references
+func refKeys(issues []StrictIssue) map[string]bool {
+	keys := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		keys[refKey(issue)] = true
+	}
+	return keys
+}
+
+// normalizeDanglingRefs reports the references found on a temporary copy against inputPath
+func normalizeDanglingRefs(inputPath string, results *TransformationResults) {
+	for i := range results.DanglingRefs {
+		results.DanglingRefs[i].File = inputPath
+	}
+}
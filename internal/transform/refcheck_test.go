@@ -0,0 +1,145 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func writeRefCheckSpecs(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	specs := map[string]string{
+		"shared.yaml": `openapi: 3.0.3
+info:
+  title: Shared
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Secret:
+      type: object
+      x-internal: true
+    Public:
+      type: object
+`,
+		"api.yaml": `openapi: 3.0.3
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /secrets:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: 'shared.yaml#/components/schemas/Secret'
+  /public:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Missing'
+`,
+	}
+	for name, content := range specs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDanglingRefsAfterStep(t *testing.T) {
+	dir := writeRefCheckSpecs(t)
+
+	cfg := &config.Config{StripInternal: config.StripInternal{Enabled: true}}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results.DanglingRefs) != 1 {
+		t.Fatalf("expected only the newly dangling reference to be reported, got %+v", results.DanglingRefs)
+	}
+	ref := results.DanglingRefs[0]
+	if ref.File != filepath.Join(dir, "api.yaml") || ref.Step != StepStripInternal || ref.Line == 0 {
+		t.Errorf("unexpected dangling ref %+v", ref)
+	}
+	if !strings.Contains(ref.Message, "shared.yaml#/components/schemas/Secret") {
+		t.Errorf("expected the message to name the reference, got %q", ref.Message)
+	}
+}
+
+func TestDanglingRefsCheckDisabled(t *testing.T) {
+	dir := writeRefCheckSpecs(t)
+
+	cfg := &config.Config{
+		StripInternal: config.StripInternal{Enabled: true},
+		RefCheck:      config.RefCheck{Disabled: true},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.DanglingRefs) != 0 {
+		t.Errorf("expected no check when disabled, got %+v", results.DanglingRefs)
+	}
+}
+
+func TestDanglingRefsAfterPathRenames(t *testing.T) {
+	dir, _ := writeTestSpec(t, `openapi: 3.0.3
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /a:
+    get:
+      responses:
+        "200":
+          description: OK
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: offset
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          $ref: '#/paths/~1a/get/responses/200'
+`)
+
+	// tenant_paths renames every path between strip_internal and pagination, which is not what
+	// leaves the pointer into /a dangling
+	cfg := &config.Config{
+		StripInternal:      config.StripInternal{Enabled: true},
+		TenantPaths:        config.TenantPaths{Enabled: true},
+		PaginationPriority: []string{"cursor", "offset"},
+	}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.PaginationResult == nil || !results.PaginationResult.Changed {
+		t.Fatal("expected the pagination step to change the document")
+	}
+	for _, ref := range results.DanglingRefs {
+		if ref.Step == StepPagination {
+			t.Errorf("expected no reference blamed on pagination, got %+v", ref)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package transform
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const componentsRefPrefix = "#/components/"
+
+// componentRef builds a local $ref pointing at a component
+func componentRef(section, name string) string {
+	return componentsRefPrefix + section + "/" + name
+}
+
+// swagger2Sections maps the component sections to the top-level Swagger 2.0 sections holding the
+// same components
+var swagger2Sections = map[string]string{
+	"schemas":         "definitions",
+	"parameters":      "parameters",
+	"responses":       "responses",
+	"securitySchemes": "securityDefinitions",
+}
+
+// componentSection returns the mapping of a component section in a document, the name the section
+// has there and the prefix of the local $refs to its components. Swagger 2.0 documents keep
+// schemas, parameters, responses and security schemes at the top level, schemas under definitions
+// and security schemes under securityDefinitions; the other sections have no Swagger 2.0
+// counterpart, so the mapping is nil.
+func componentSection(root *yaml.Node, section string) (*yaml.Node, string, string) {
+	if getNodeValue(root, "swagger") == nil {
+		return getNodeValue(getNodeValue(root, "components"), section), section, componentsRefPrefix + section + "/"
+	}
+	name, ok := swagger2Sections[section]
+	if !ok {
+		return nil, section, ""
+	}
+	return getNodeValue(root, name), name, "#/" + name + "/"
+}
+
+// documentComponentSections returns the component sections of a document in document order: the
+// keys of components, or the sections named by the top-level Swagger 2.0 sections it has
+func documentComponentSections(root *yaml.Node) []string {
+	var sections []string
+	if getNodeValue(root, "swagger") == nil {
+		components := getNodeValue(root, "components")
+		if components == nil || components.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i < len(components.Content); i += 2 {
+			sections = append(sections, components.Content[i].Value)
+		}
+		return sections
+	}
+
+	for i := 0; i < len(root.Content); i += 2 {
+		for section, name := range swagger2Sections {
+			if root.Content[i].Value == name {
+				sections = append(sections, section)
+			}
+		}
+	}
+	return sections
+}
+
+// documentComponentRef builds a local $ref pointing at a component of the document
+func documentComponentRef(root *yaml.Node, section, name string) string {
+	_, _, prefix := componentSection(root, section)
+	return prefix + name
+}
+
+// buildRefRenames converts per-section component renames into a map of old $ref -> new $ref
+func buildRefRenames(root *yaml.Node, renames map[string]map[string]string) map[string]string {
+	refRenames := make(map[string]string)
+	for section, names := range renames {
+		for oldName, newName := range names {
+			refRenames[documentComponentRef(root, section, oldName)] = documentComponentRef(root, section, newName)
+		}
+	}
+	return refRenames
+}
+
+// rewriteComponentRefs points every reference to the renamed components (section -> old name ->
+// new name) at the new names, pinning the discriminator values of renamed schemas first, and
+// returns the number of rewritten references per old $ref. Security requirements name their
+// schemes instead of referencing them, so they are renamed too and counted under the scheme's $ref.
+// Call it before the components move.
+func rewriteComponentRefs(root *yaml.Node, renames map[string]map[string]string) map[string]int {
+	pinDiscriminatorValues(root, renames["schemas"])
+	rw := newRefRewriter(buildRefRenames(root, renames), renames["schemas"])
+	rw.rewrite(root)
+	for name, count := range renameSecurityRequirements(root, renames["securitySchemes"]) {
+		rw.counts[documentComponentRef(root, "securitySchemes", name)] += count
+	}
+	return rw.counts
+}
+
+// renameSecurityRequirements renames the schemes named by the top-level and operation security
+// requirements, returning the number of renamed entries per old scheme name. A requirement that
+// already names the new scheme keeps only its first entry, as when duplicates are merged.
+func renameSecurityRequirements(root *yaml.Node, renames map[string]string) map[string]int {
+	counts := make(map[string]int)
+	if len(renames) == 0 {
+		return counts
+	}
+
+	for _, security := range securityRequirementLists(root) {
+		for _, requirement := range security.Content {
+			if requirement.Kind != yaml.MappingNode {
+				continue
+			}
+			var kept []*yaml.Node
+			seen := make(map[string]bool)
+			for i := 0; i+1 < len(requirement.Content); i += 2 {
+				key := requirement.Content[i]
+				if newName, ok := renames[key.Value]; ok {
+					counts[key.Value]++
+					key.Value = newName
+				}
+				if !seen[key.Value] {
+					seen[key.Value] = true
+					kept = append(kept, key, requirement.Content[i+1])
+				}
+			}
+			requirement.Content = kept
+		}
+	}
+	return counts
+}
+
+// securityRequirementLists returns the top-level security requirements and those of every
+// operation under paths and webhooks
+func securityRequirementLists(root *yaml.Node) []*yaml.Node {
+	var lists []*yaml.Node
+	add := func(security *yaml.Node) {
+		if security != nil && security.Kind == yaml.SequenceNode {
+			lists = append(lists, security)
+		}
+	}
+
+	add(getNodeValue(root, "security"))
+	for _, section := range []string{"paths", "webhooks"} {
+		items := getNodeValue(root, section)
+		if items == nil || items.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 1; i < len(items.Content); i += 2 {
+			pathItem := items.Content[i]
+			if pathItem.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j+1 < len(pathItem.Content); j += 2 {
+				if isHTTPMethod(pathItem.Content[j].Value) {
+					add(getNodeValue(pathItem.Content[j+1], "security"))
+				}
+			}
+		}
+	}
+	return lists
+}
+
+// refRewriter rewrites references to renamed components in a single pass, so chained
+// renames (A -> B, B -> C) never rewrite the same reference twice
+type refRewriter struct {
+	refRenames    map[string]string // old $ref -> new $ref
+	schemaRenames map[string]string // old schema name -> new schema name (for bare discriminator mappings)
+	counts        map[string]int    // old $ref -> number of rewritten references
+}
+
+// newRefRewriter creates a refRewriter for the given renames
+func newRefRewriter(refRenames, schemaRenames map[string]string) *refRewriter {
+	return &refRewriter{
+		refRenames:    refRenames,
+		schemaRenames: schemaRenames,
+		counts:        make(map[string]int),
+	}
+}
+
+// rewriteRefs rewrites every $ref in the node tree that points at a renamed component,
+// including refs into a component (e.g. #/components/schemas/Old/properties/id),
+// and returns the number of rewritten references
+func rewriteRefs(node *yaml.Node, refRenames map[string]string, schemaRenames map[string]string) int {
+	rw := newRefRewriter(refRenames, schemaRenames)
+	rw.rewrite(node)
+
+	total := 0
+	for _, count := range rw.counts {
+		total += count
+	}
+	return total
+}
+
+// rewrite walks the node tree and rewrites references in place
+func (rw *refRewriter) rewrite(node *yaml.Node) {
+	if node == nil || (len(rw.refRenames) == 0 && len(rw.schemaRenames) == 0) {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, item := range node.Content {
+			rw.rewrite(item)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			value := node.Content[i+1]
+
+			switch {
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				rw.rewriteRefValue(value)
+			case key == "discriminator":
+				rw.rewriteDiscriminatorMapping(value)
+				rw.rewrite(value)
+			default:
+				rw.rewrite(value)
+			}
+		}
+	}
+}
+
+// rewriteRefValue rewrites a single $ref scalar if it points at (or into) a renamed component
+func (rw *refRewriter) rewriteRefValue(value *yaml.Node) bool {
+	if newRef, oldRef, ok := renameRef(value.Value, rw.refRenames); ok {
+		value.Value = newRef
+		rw.counts[oldRef]++
+		return true
+	}
+	return false
+}
+
+// renameRef returns the renamed $ref and the matched old $ref if ref points at (or into) a renamed component
+func renameRef(ref string, refRenames map[string]string) (newRef, oldRef string, ok bool) {
+	if renamed, exists := refRenames[ref]; exists {
+		return renamed, ref, true
+	}
+
+	// Refs into a component keep their suffix. Component names never contain "/",
+	// so at most one renamed component can match.
+	for candidate, renamed := range refRenames {
+		if strings.HasPrefix(ref, candidate+"/") {
+			return renamed + strings.TrimPrefix(ref, candidate), candidate, true
+		}
+	}
+
+	return ref, "", false
+}
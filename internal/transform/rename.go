@@ -0,0 +1,285 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// RenameOptions extends the regular Options with component rename settings
+type RenameOptions struct {
+	Options
+	ComponentRenames config.ComponentRenames
+}
+
+// RenameResult represents the result of component rename processing
+type RenameResult struct {
+	Changed           bool
+	ProcessedFiles    []string
+	RenamedComponents map[string][]string // file -> list of renamed components
+	SkippedRenames    map[string][]Skip   // file -> list of skipped renames with reasons
+}
+
+// compiledRenamePattern is a RenamePattern with its regex compiled
+type compiledRenamePattern struct {
+	re      *regexp.Regexp
+	replace string
+}
+
+// createRenameResult creates a new RenameResult with initialized maps
+func createRenameResult() *RenameResult {
+	return &RenameResult{
+		ProcessedFiles:    []string{},
+		RenamedComponents: make(map[string][]string),
+		SkippedRenames:    make(map[string][]Skip),
+	}
+}
+
+// setRenameProcessedFiles sets the processed files for a RenameResult
+func setRenameProcessedFiles(result *RenameResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setRenameChanged sets the changed flag for a RenameResult
+func setRenameChanged(result *RenameResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessComponentRenamesInDir renames components in all OpenAPI files in a directory
+func ProcessComponentRenamesInDir(dir string, opts RenameOptions) (*RenameResult, error) {
+	patterns, err := compileRenamePatterns(opts.ComponentRenames.Patterns)
+	if err != nil {
+		return createRenameResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepComponentRenames,
+		opts.Options,
+		opts.ComponentRenames.Enabled,
+		isComponentRenamesEmpty(opts.ComponentRenames),
+		createRenameResult,
+		func(path string, result *RenameResult) (bool, error) {
+			return processComponentRenamesInFile(path, opts, patterns, result)
+		},
+		setRenameProcessedFiles,
+		setRenameChanged,
+	)
+}
+
+// isComponentRenamesEmpty reports whether no rename rules are configured
+func isComponentRenamesEmpty(renames config.ComponentRenames) bool {
+	return len(renames.Map) == 0 && len(renames.StripPrefixes) == 0 &&
+		len(renames.StripSuffixes) == 0 && len(renames.Patterns) == 0
+}
+
+// compileRenamePatterns compiles the configured regex rename patterns
+func compileRenamePatterns(patterns []config.RenamePattern) ([]compiledRenamePattern, error) {
+	compiled := make([]compiledRenamePattern, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid component rename pattern %q: %w", p.Match, err)
+		}
+		compiled = append(compiled, compiledRenamePattern{re: re, replace: p.Replace})
+	}
+	return compiled, nil
+}
+
+// processComponentRenamesInFile renames components in a single file
+func processComponentRenamesInFile(path string, opts RenameOptions, patterns []compiledRenamePattern, result *RenameResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	return processDocumentComponentRenames(doc, root, path, opts, patterns, result)
+}
+
+// processDocumentComponentRenames renames components in a document and rewrites all references to them
+func processDocumentComponentRenames(doc, root *yaml.Node, path string, opts RenameOptions, patterns []compiledRenamePattern, result *RenameResult) (bool, error) {
+	renames := make(map[string]map[string]string)
+	for _, section := range getRenameSections(opts.ComponentRenames) {
+		sectionNode, name, _ := componentSection(root, section)
+		if sectionNode == nil || sectionNode.Kind != yaml.MappingNode {
+			continue
+		}
+
+		plan := planSectionRenames(sectionNode, name, opts.ComponentRenames, patterns, path, result)
+		if section == "schemas" {
+			for schema := range swagger2DiscriminatorSchemas(root) {
+				if _, ok := plan[schema]; ok {
+					delete(plan, schema)
+					addSkippedRename(result, path, name, schema, SkipDiscriminatorValue,
+						"a Swagger 2.0 discriminator selects it by name, so payloads would change")
+				}
+			}
+		}
+		if len(plan) > 0 {
+			renames[section] = plan
+		}
+	}
+
+	if len(renames) == 0 {
+		return false, nil
+	}
+
+	refCounts := applyComponentRenames(root, renames)
+	recordComponentRenames(result, path, root, renames, refCounts)
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// getRenameSections returns the component sections to rename, defaulting to schemas
+func getRenameSections(renames config.ComponentRenames) []string {
+	if len(renames.Sections) == 0 {
+		return []string{"schemas"}
+	}
+	return renames.Sections
+}
+
+// computeComponentName computes the new name for a component, returning the original name if no rule applies
+func computeComponentName(name string, renames config.ComponentRenames, patterns []compiledRenamePattern) string {
+	if newName, ok := renames.Map[name]; ok {
+		return newName
+	}
+
+	newName := name
+	for _, prefix := range renames.StripPrefixes {
+		if prefix != "" && strings.HasPrefix(newName, prefix) {
+			newName = strings.TrimPrefix(newName, prefix)
+			break
+		}
+	}
+	for _, suffix := range renames.StripSuffixes {
+		if suffix != "" && strings.HasSuffix(newName, suffix) {
+			newName = strings.TrimSuffix(newName, suffix)
+			break
+		}
+	}
+	for _, p := range patterns {
+		if p.re.MatchString(newName) {
+			newName = p.re.ReplaceAllString(newName, p.replace)
+		}
+	}
+
+	return newName
+}
+
+// planSectionRenames computes old -> new names for one component section, dropping renames
+// that would produce empty names or collide with other components
+func planSectionRenames(sectionNode *yaml.Node, section string, renames config.ComponentRenames, patterns []compiledRenamePattern, path string, result *RenameResult) map[string]string {
+	var names []string
+	plan := make(map[string]string)
+
+	for i := 0; i < len(sectionNode.Content); i += 2 {
+		name := sectionNode.Content[i].Value
+		names = append(names, name)
+
+		newName := computeComponentName(name, renames, patterns)
+		if newName == name {
+			continue
+		}
+		if newName == "" {
+			addSkippedRename(result, path, section, name, SkipEmptyName, "rename would produce an empty name")
+			continue
+		}
+		plan[name] = newName
+	}
+
+	dropCollidingRenames(names, plan, section, path, result)
+	return plan
+}
+
+// dropCollidingRenames removes renames whose final name is shared with another component.
+// Dropping a rename can create a new collision, so this repeats until the plan is stable.
+func dropCollidingRenames(names []string, plan map[string]string, section, path string, result *RenameResult) {
+	for {
+		finalNames := make(map[string][]string)
+		for _, name := range names {
+			finalName := name
+			if newName, ok := plan[name]; ok {
+				finalName = newName
+			}
+			finalNames[finalName] = append(finalNames[finalName], name)
+		}
+
+		collisions := make([]string, 0, len(finalNames))
+		for finalName, owners := range finalNames {
+			if len(owners) > 1 {
+				collisions = append(collisions, finalName)
+			}
+		}
+		sort.Strings(collisions)
+
+		dropped := false
+		for _, finalName := range collisions {
+			owners := finalNames[finalName]
+			for _, owner := range owners {
+				if _, ok := plan[owner]; !ok {
+					continue
+				}
+				delete(plan, owner)
+				dropped = true
+				others := make([]string, 0, len(owners)-1)
+				for _, other := range owners {
+					if other != owner {
+						others = append(others, other)
+					}
+				}
+				sort.Strings(others)
+				addSkippedRename(result, path, section, owner, SkipNameCollision,
+					fmt.Sprintf("'%s' collides with %s", finalName, strings.Join(others, ", ")))
+			}
+		}
+
+		if !dropped {
+			return
+		}
+	}
+}
+
+// applyComponentRenames renames component keys and rewrites refs, returning the number of
+// rewritten references per old $ref
+func applyComponentRenames(root *yaml.Node, renames map[string]map[string]string) map[string]int {
+	refCounts := rewriteComponentRefs(root, renames)
+	for section, plan := range renames {
+		sectionNode, _, _ := componentSection(root, section)
+		for i := 0; i < len(sectionNode.Content); i += 2 {
+			keyNode := sectionNode.Content[i]
+			if newName, ok := plan[keyNode.Value]; ok {
+				keyNode.Value = newName
+			}
+		}
+	}
+	return refCounts
+}
+
+// recordComponentRenames records the applied renames in sorted order
+func recordComponentRenames(result *RenameResult, path string, root *yaml.Node, renames map[string]map[string]string, refCounts map[string]int) {
+	var entries []string
+	for section, plan := range renames {
+		_, name, prefix := componentSection(root, section)
+		for oldName, newName := range plan {
+			entries = append(entries, fmt.Sprintf("%s.%s -> %s (%d references updated)",
+				name, oldName, newName, refCounts[prefix+oldName]))
+		}
+	}
+	sort.Strings(entries)
+	result.RenamedComponents[path] = append(result.RenamedComponents[path], entries...)
+}
+
+func addSkippedRename(result *RenameResult, filePath, section, name string, code SkipCode, reason string) {
+	result.SkippedRenames[filePath] = append(result.SkippedRenames[filePath], Skip{Message: fmt.Sprintf("%s.%s: %s", section, name, reason), Code: code})
+}
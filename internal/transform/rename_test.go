@@ -0,0 +1,446 @@
+package transform
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const renameTestSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/UserDto'
+  /users/{id}:
+    get:
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    $ref: '#/components/schemas/UserDto/properties/id'
+                  pet:
+                    $ref: '#/components/schemas/PetDto'
+components:
+  schemas:
+    UserDto:
+      type: object
+      properties:
+        id:
+          type: string
+    PetDto:
+      oneOf:
+        - $ref: '#/components/schemas/CatDto'
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: '#/components/schemas/CatDto'
+          dog: DogDto
+    CatDto:
+      type: object
+    DogDto:
+      type: object
+`
+
+func TestProcessComponentRenamesInDir(t *testing.T) {
+	tests := []struct {
+		name          string
+		renames       config.ComponentRenames
+		expectChanged bool
+		expectError   bool
+		contains      []string
+		notContains   []string
+	}{
+		{
+			name:          "disabled renames",
+			renames:       config.ComponentRenames{Enabled: false, StripSuffixes: []string{"Dto"}},
+			expectChanged: false,
+			contains:      []string{"UserDto:"},
+		},
+		{
+			name:          "no rules configured",
+			renames:       config.ComponentRenames{Enabled: true},
+			expectChanged: false,
+			contains:      []string{"UserDto:"},
+		},
+		{
+			name:          "strip suffix rewrites refs and discriminator mappings",
+			renames:       config.ComponentRenames{Enabled: true, StripSuffixes: []string{"Dto"}},
+			expectChanged: true,
+			contains: []string{
+				"    User:",
+				"$ref: '#/components/schemas/User'",
+				"$ref: '#/components/schemas/User/properties/id'",
+				"cat: '#/components/schemas/Cat'",
+				"dog: Dog",
+			},
+			notContains: []string{"Dto"},
+		},
+		{
+			name: "explicit map wins over patterns",
+			renames: config.ComponentRenames{
+				Enabled:       true,
+				Map:           map[string]string{"UserDto": "Account"},
+				StripSuffixes: []string{"Dto"},
+			},
+			expectChanged: true,
+			contains:      []string{"    Account:", "$ref: '#/components/schemas/Account'", "    Pet:"},
+		},
+		{
+			name: "regex pattern",
+			renames: config.ComponentRenames{
+				Enabled:  true,
+				Patterns: []config.RenamePattern{{Match: "^(.*)Dto$", Replace: "${1}Model"}},
+			},
+			expectChanged: true,
+			contains:      []string{"    UserModel:", "$ref: '#/components/schemas/UserModel/properties/id'", "dog: DogModel"},
+		},
+		{
+			name: "invalid regex pattern",
+			renames: config.ComponentRenames{
+				Enabled:  true,
+				Patterns: []config.RenamePattern{{Match: "([", Replace: ""}},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, path := writeTestSpec(t, renameTestSpec)
+
+			result, err := ProcessComponentRenamesInDir(dir, RenameOptions{ComponentRenames: tt.renames})
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			if result.Changed != tt.expectChanged {
+				t.Errorf("expected Changed=%v, got %v", tt.expectChanged, result.Changed)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			output := string(data)
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output to contain %q\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("expected output not to contain %q\n%s", unwanted, output)
+				}
+			}
+		})
+	}
+}
+
+func TestComponentRenamesChained(t *testing.T) {
+	dir, path := writeTestSpec(t, `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    A:
+      $ref: '#/components/schemas/B'
+    B:
+      type: object
+`)
+
+	opts := RenameOptions{ComponentRenames: config.ComponentRenames{
+		Enabled: true,
+		Map:     map[string]string{"A": "B", "B": "C"},
+	}}
+	if _, err := ProcessComponentRenamesInDir(dir, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	output := string(data)
+	if !strings.Contains(output, "B:\n            $ref: '#/components/schemas/C'") {
+		t.Errorf("expected A -> B pointing at C, got:\n%s", output)
+	}
+	if !strings.Contains(output, "C:\n            type: object") {
+		t.Errorf("expected B -> C, got:\n%s", output)
+	}
+}
+
+func TestComponentRenamesCollisions(t *testing.T) {
+	dir, path := writeTestSpec(t, `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    User:
+      type: object
+    UserDto:
+      type: object
+    OrderDto:
+      type: object
+`)
+
+	opts := RenameOptions{ComponentRenames: config.ComponentRenames{
+		Enabled:       true,
+		StripSuffixes: []string{"Dto"},
+	}}
+	result, err := ProcessComponentRenamesInDir(dir, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	skipped := result.SkippedRenames[path]
+	if len(skipped) != 1 || !strings.Contains(skipped[0].Message, "schemas.UserDto: 'User' collides with User") || skipped[0].Code != SkipNameCollision {
+		t.Errorf("expected UserDto rename to be skipped, got %v", skipped)
+	}
+	renamed := result.RenamedComponents[path]
+	if len(renamed) != 1 || !strings.HasPrefix(renamed[0], "schemas.OrderDto -> Order") {
+		t.Errorf("expected OrderDto rename, got %v", renamed)
+	}
+}
+
+func TestComponentRenamesSwagger2(t *testing.T) {
+	dir, path := writeTestSpec(t, `swagger: "2.0"
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /things:
+    get:
+      parameters:
+        - $ref: '#/parameters/LimitV2'
+      responses:
+        "200":
+          description: OK
+          schema:
+            $ref: '#/definitions/ThingV2'
+parameters:
+  LimitV2:
+    name: limit
+    in: query
+    type: integer
+definitions:
+  ThingV2:
+    type: object
+    properties:
+      pet:
+        $ref: '#/definitions/PetV2'
+  PetV2:
+    type: object
+    discriminator: kind
+    properties:
+      kind:
+        type: string
+  CatV2:
+    allOf:
+      - $ref: '#/definitions/PetV2'
+`)
+
+	opts := RenameOptions{ComponentRenames: config.ComponentRenames{
+		Enabled:       true,
+		Sections:      []string{"schemas", "parameters"},
+		StripSuffixes: []string{"V2"},
+	}}
+	result, err := ProcessComponentRenamesInDir(dir, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"definitions.ThingV2 -> Thing (1 references updated)", "parameters.LimitV2 -> Limit (1 references updated)"}
+	if !reflect.DeepEqual(result.RenamedComponents[path], want) {
+		t.Errorf("expected %v, got %v", want, result.RenamedComponents[path])
+	}
+	// The discriminator selects PetV2 and CatV2 by name, which Swagger 2.0 cannot pin
+	skipped := result.SkippedRenames[path]
+	if len(skipped) != 2 || skipped[0].Code != SkipDiscriminatorValue || !strings.HasPrefix(skipped[0].Message, "definitions.") {
+		t.Errorf("expected the discriminated definitions to be skipped, got %v", skipped)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	output := string(data)
+	for _, want := range []string{"$ref: '#/definitions/Thing'", "$ref: '#/parameters/Limit'", "    Thing:\n", "    Limit:\n", "$ref: '#/definitions/PetV2'"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestComponentRenamesSecuritySchemes(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		section string
+	}{
+		{
+			name: "components",
+			spec: `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+security:
+  - ApiKeyV2: []
+paths:
+  /things:
+    get:
+      security:
+        - ApiKeyV2: []
+          OAuthV2: [read]
+      responses:
+        "200":
+          description: OK
+components:
+  securitySchemes:
+    ApiKeyV2:
+      type: apiKey
+      in: header
+      name: X-Key
+    OAuthV2:
+      type: oauth2
+      flows: {}
+`,
+			section: "securitySchemes",
+		},
+		{
+			name: "Swagger 2.0",
+			spec: `swagger: "2.0"
+info:
+  title: Test API
+  version: 1.0.0
+security:
+  - ApiKeyV2: []
+paths:
+  /things:
+    get:
+      security:
+        - ApiKeyV2: []
+          OAuthV2: [read]
+      responses:
+        "200":
+          description: OK
+securityDefinitions:
+  ApiKeyV2:
+    type: apiKey
+    in: header
+    name: X-Key
+  OAuthV2:
+    type: oauth2
+    flow: implicit
+    authorizationUrl: https://example.com/auth
+`,
+			section: "securityDefinitions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, path := writeTestSpec(t, tt.spec)
+			opts := RenameOptions{ComponentRenames: config.ComponentRenames{
+				Enabled:       true,
+				Sections:      []string{"securitySchemes"},
+				StripSuffixes: []string{"V2"},
+			}}
+			result, err := ProcessComponentRenamesInDir(dir, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := []string{
+				tt.section + ".ApiKeyV2 -> ApiKey (2 references updated)",
+				tt.section + ".OAuthV2 -> OAuth (1 references updated)",
+			}
+			if !reflect.DeepEqual(result.RenamedComponents[path], want) {
+				t.Errorf("expected %v, got %v", want, result.RenamedComponents[path])
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			if strings.Contains(string(data), "V2") {
+				t.Errorf("expected every security requirement to name the new schemes, got:\n%s", data)
+			}
+		})
+	}
+}
+
+func TestComponentRenamesDryRun(t *testing.T) {
+	dir, path := writeTestSpec(t, renameTestSpec)
+
+	opts := RenameOptions{
+		Options:          Options{DryRun: true},
+		ComponentRenames: config.ComponentRenames{Enabled: true, StripSuffixes: []string{"Dto"}},
+	}
+	result, err := ProcessComponentRenamesInDir(dir, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Changed {
+		t.Error("expected dry run to report changes")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if string(data) != renameTestSpec {
+		t.Error("expected dry run to leave the file untouched")
+	}
+}
+
+func TestRenameRef(t *testing.T) {
+	refRenames := map[string]string{
+		"#/components/schemas/User": "#/components/schemas/Account",
+	}
+
+	tests := []struct {
+		ref      string
+		expected string
+		ok       bool
+	}{
+		{"#/components/schemas/User", "#/components/schemas/Account", true},
+		{"#/components/schemas/User/properties/id", "#/components/schemas/Account/properties/id", true},
+		{"#/components/schemas/UserList", "#/components/schemas/UserList", false},
+		{"#/components/responses/User", "#/components/responses/User", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			newRef, _, ok := renameRef(tt.ref, refRenames)
+			if ok != tt.ok || newRef != tt.expected {
+				t.Errorf("renameRef(%q) = %q, %v; expected %q, %v", tt.ref, newRef, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
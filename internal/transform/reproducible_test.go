@@ -0,0 +1,225 @@
+package transform
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// reproducibleConfig touches the steps whose settings are maps, so their iteration order would
+// show in the output
+const reproducibleConfig = `
+mappings:
+  x-a: x-b
+  x-b: x-c
+  x-c: x-d
+  x-speakeasy-ignore: x-fern-ignore
+pagination_priority: [cursor, offset, page, none]
+provenance:
+  enabled: true
+vendor_extensions:
+  enabled: true
+  providers:
+    fern:
+      extension_name: x-fern-pagination
+      target_level: operation
+      field_mapping:
+        request_params:
+          cursor: [cursor]
+          limit: [limit]
+        response_fields:
+          results: [data]
+      strategies:
+        cursor:
+          template:
+            type: cursor
+            cursor: $request.{cursor_param}
+            page_size: $request.{limit_param}
+            results: $response.{results_field}
+            meta:
+              zeta: 1
+              alpha: 2
+    speakeasy:
+      extension_name: x-speakeasy-pagination
+      target_level: operation
+      field_mapping:
+        request_params:
+          cursor: [cursor]
+      strategies:
+        cursor:
+          template:
+            type: cursor
+            inputs: $request.{cursor_param}
+    stainless:
+      extension_name: x-stainless-pagination
+      target_level: operation
+      strategies:
+        cursor:
+          template:
+            scheme: cursor
+default_values:
+  enabled: true
+  rules:
+    limit:
+      target:
+        location: parameter
+      condition:
+        parameter_in: query
+        property_name: limit
+      value: 20
+    settings:
+      target:
+        location: component
+      condition:
+        type: object
+        property_name: preferences
+      value:
+        theme: dark
+        locale: en
+        beta: false
+        retries: 3
+`
+
+const reproducibleYAML = `openapi: 3.0.3
+info:
+  title: API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-a: true
+      x-speakeasy-ignore: false
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: array
+                    items:
+                      $ref: "#/components/schemas/User"
+                  next_cursor:
+                    type: string
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id:
+          type: string
+    Settings:
+      type: object
+      properties:
+        preferences:
+          type: object
+`
+
+const reproducibleJSON = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Orders", "version": "1.0.0"},
+  "paths": {
+    "/orders": {
+      "get": {
+        "x-a": 1,
+        "x-b": 2,
+        "x-speakeasy-ignore": true,
+        "responses": {"200": {"description": "OK"}}
+      }
+    }
+  }
+}
+`
+
+// runReproducible runs the pipeline on a fresh copy of the fixtures and returns the resulting files
+func runReproducible(t *testing.T, cfg *config.Config) map[string][]byte {
+	t.Helper()
+	dir := t.TempDir()
+	fixtures := map[string]string{"users.yaml": reproducibleYAML, "orders.json": reproducibleJSON}
+	for name, content := range fixtures {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tp := NewTransformationPipeline(cfg, nil, false, false, "")
+	tp.Version = "1.0.0"
+	if _, err := tp.ExecuteFullPipeline(dir); err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+
+	outputs := make(map[string][]byte)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs[entry.Name()] = data
+	}
+	return outputs
+}
+
+func TestPipelineOutputIsReproducible(t *testing.T) {
+	t.Setenv(SourceDateEpochEnv, "1700000000")
+	var cfg config.Config
+	if err := yaml.Unmarshal([]byte(reproducibleConfig), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	first := runReproducible(t, &cfg)
+	for _, want := range []string{"x-fern-pagination", "x-speakeasy-pagination", "locale: en", "generated: \"2023-11-14T22:13:20Z\""} {
+		if !strings.Contains(string(first["users.yaml"]), want) {
+			t.Errorf("expected the fixture to exercise %q:\n%s", want, first["users.yaml"])
+		}
+	}
+
+	// Map iteration order is randomized per range, so a few runs catch most leaks
+	for run := 0; run < 5; run++ {
+		next := runReproducible(t, &cfg)
+		if len(next) != len(first) {
+			t.Fatalf("run %d produced %d files, want %d", run, len(next), len(first))
+		}
+		for name, data := range first {
+			if !bytes.Equal(next[name], data) {
+				t.Fatalf("run %d produced a different %s:\n%s\nwant:\n%s", run, name, next[name], data)
+			}
+		}
+	}
+}
+
+func TestStampTimeHonorsSourceDateEpoch(t *testing.T) {
+	t.Setenv(SourceDateEpochEnv, "0")
+	if got, err := stampTime(); err != nil || !got.Equal(time.Unix(0, 0)) {
+		t.Errorf("stampTime() = %v, %v; want the Unix epoch", got, err)
+	}
+
+	t.Setenv(SourceDateEpochEnv, "yesterday")
+	if err := ValidateProvenance(config.Provenance{Enabled: true}); err == nil {
+		t.Error("expected an invalid SOURCE_DATE_EPOCH to be rejected")
+	}
+	if err := ValidateProvenance(config.Provenance{}); err != nil {
+		t.Errorf("expected SOURCE_DATE_EPOCH to be ignored without provenance, got %v", err)
+	}
+}
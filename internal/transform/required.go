@@ -0,0 +1,84 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Skip codes that fail a run of a required step when its fail_on is not configured
+var (
+	DefaultVendorFailOn   = []SkipCode{SkipMissingFields}
+	DefaultDefaultsFailOn = []SkipCode{SkipInvalidValue, SkipInvalidRule}
+)
+
+// RequiredSkip is an item a required step skipped for a reason that fails the run
+type RequiredSkip struct {
+	Step string
+	File string
+	Skip
+}
+
+// ValidateRequiredSkips checks that the fail_on lists name skip codes
+func ValidateRequiredSkips(cfg *config.Config) error {
+	for section, codes := range map[string][]string{
+		"vendor_extensions.fail_on": cfg.VendorExtensions.FailOn,
+		"default_values.fail_on":    cfg.DefaultValues.FailOn,
+	} {
+		for _, code := range codes {
+			if _, ok := skipCodeDescriptions[SkipCode(code)]; !ok {
+				return fmt.Errorf("%s: unknown skip reason %q", section, code)
+			}
+		}
+	}
+	return nil
+}
+
+// RequiredSkips returns the items the steps marked required in cfg skipped for a reason in their
+// fail_on, ordered by step, file and message
+func RequiredSkips(cfg *config.Config, results ...*TransformationResults) []RequiredSkip {
+	var required []RequiredSkip
+	collect := func(step string, failOn []string, defaults []SkipCode, skips map[string][]Skip) {
+		codes := make(map[SkipCode]bool)
+		if failOn == nil {
+			for _, code := range defaults {
+				codes[code] = true
+			}
+		}
+		for _, code := range failOn {
+			codes[SkipCode(code)] = true
+		}
+		for file, fileSkips := range skips {
+			for _, skip := range fileSkips {
+				if codes[skip.Code] {
+					required = append(required, RequiredSkip{Step: step, File: file, Skip: skip})
+				}
+			}
+		}
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		if cfg.VendorExtensions.Required && result.VendorResult != nil {
+			collect(StepVendorExtensions, cfg.VendorExtensions.FailOn, DefaultVendorFailOn, result.VendorResult.SkippedOperations)
+		}
+		if cfg.DefaultValues.Required && result.DefaultsResult != nil {
+			collect(StepDefaults, cfg.DefaultValues.FailOn, DefaultDefaultsFailOn, result.DefaultsResult.SkippedTargets)
+		}
+	}
+
+	sort.SliceStable(required, func(i, j int) bool {
+		a, b := required[i], required[j]
+		if a.Step != b.Step {
+			return a.Step < b.Step
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Message < b.Message
+	})
+	return required
+}
@@ -0,0 +1,98 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestRequiredSkips(t *testing.T) {
+	results := &TransformationResults{
+		VendorResult: &VendorExtensionResult{SkippedOperations: map[string][]Skip{
+			"api.yaml": {
+				{Message: "GET /users: missing required fields results_field for fern (cursor strategy)", Code: SkipMissingFields},
+				{Message: "GET /health: no pagination detected for fern", Code: SkipNoPagination},
+			},
+		}},
+		DefaultsResult: &DefaultsResult{SkippedTargets: map[string][]Skip{
+			"api.yaml": {{Message: "GET /users parameter limit: value doesn't fit", Code: SkipInvalidValue}},
+		}},
+	}
+
+	cfg := &config.Config{}
+	if skips := RequiredSkips(cfg, results); len(skips) != 0 {
+		t.Errorf("expected nothing without required steps, got %+v", skips)
+	}
+
+	cfg.VendorExtensions.Required = true
+	skips := RequiredSkips(cfg, results)
+	if len(skips) != 1 || skips[0].Step != StepVendorExtensions || skips[0].Code != SkipMissingFields {
+		t.Errorf("expected the missing fields skip, got %+v", skips)
+	}
+
+	cfg.VendorExtensions.FailOn = []string{"no_pagination"}
+	cfg.DefaultValues.Required = true
+	skips = RequiredSkips(cfg, results)
+	if len(skips) != 2 || skips[0].Code != SkipInvalidValue || skips[1].Code != SkipNoPagination {
+		t.Errorf("expected the configured and default codes, got %+v", skips)
+	}
+
+	cfg.DefaultValues.FailOn = []string{}
+	if skips := RequiredSkips(cfg, results); len(skips) != 1 {
+		t.Errorf("expected an empty fail_on to fail on nothing, got %+v", skips)
+	}
+}
+
+func TestValidateRequiredSkips(t *testing.T) {
+	cfg := &config.Config{VendorExtensions: config.VendorExtensions{FailOn: []string{"missing_required_fields", "no_tags"}}}
+	if err := ValidateRequiredSkips(cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cfg.DefaultValues.FailOn = []string{"missing_fields"}
+	if err := ValidateRequiredSkips(cfg); err == nil || !strings.Contains(err.Error(), `default_values.fail_on: unknown skip reason "missing_fields"`) {
+		t.Errorf("expected an error naming the code, got %v", err)
+	}
+}
+
+func TestVendorExtensionsRecordMissingFields(t *testing.T) {
+	dir := t.TempDir()
+	spec := `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+      responses:
+        "200":
+          description: OK
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessVendorExtensionsInDir(dir, VendorExtensionOptions{VendorExtensions: config.VendorExtensions{
+		Enabled: true,
+		Providers: map[string]config.ProviderConfig{"fern": {
+			ExtensionName: "x-fern-pagination",
+			FieldMapping:  config.FieldMapping{RequestParams: map[string][]string{"cursor": {"cursor"}}},
+			Strategies: map[string]config.StrategyConfig{"cursor": {
+				Template:       map[string]interface{}{"results": "$response.{results_field}"},
+				RequiredFields: []string{"cursor_param", "results_field"},
+			}},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	skips := result.SkippedOperations[filepath.Join(dir, "api.yaml")]
+	if len(skips) != 1 || skips[0].Code != SkipMissingFields || skips[0].Message != "GET /users: missing required fields results_field for fern (cursor strategy)" {
+		t.Errorf("expected the missing results_field to be recorded, got %+v", skips)
+	}
+}
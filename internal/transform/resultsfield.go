@@ -0,0 +1,123 @@
+package transform
+
+import (
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Default ranking of the auto-detected {results_field}
+var (
+	DefaultPreferredResultsFields = []string{"data", "items", "results", "{resource}"}
+	DefaultIgnoredResultsFields   = []string{"errors", "warnings", "messages"}
+)
+
+// arrayField is an array property of a response schema
+type arrayField struct {
+	Name        string
+	ObjectItems bool // items are objects or $refs rather than scalars
+}
+
+// rankResultsFields orders the array fields of the responses of the operation at pathName, the
+// most likely results field first, without duplicates
+func rankResultsFields(fields []arrayField, pathName string, ranking config.ResultsRanking) []string {
+	preferred := ranking.Preferred
+	if preferred == nil {
+		preferred = DefaultPreferredResultsFields
+	}
+	ignored := ranking.Ignored
+	if ignored == nil {
+		ignored = DefaultIgnoredResultsFields
+	}
+	resource := resourceName(pathName)
+
+	// A field found in several responses ranks by its best occurrence
+	var unique []arrayField
+	seen := make(map[string]int)
+	for _, field := range fields {
+		if i, ok := seen[field.Name]; ok {
+			unique[i].ObjectItems = unique[i].ObjectItems || field.ObjectItems
+			continue
+		}
+		seen[field.Name] = len(unique)
+		unique = append(unique, field)
+	}
+
+	preference := func(name string) int {
+		for i, candidate := range preferred {
+			if candidate == "{resource}" {
+				if resource != "" && (name == resource || name == pluralWord(resource)) {
+					return i
+				}
+			} else if strings.EqualFold(name, candidate) {
+				return i
+			}
+		}
+		return len(preferred)
+	}
+	isIgnored := func(name string) bool {
+		for _, candidate := range ignored {
+			if strings.EqualFold(name, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		a, b := unique[i], unique[j]
+		if isIgnored(a.Name) != isIgnored(b.Name) {
+			return !isIgnored(a.Name)
+		}
+		if a.ObjectItems != b.ObjectItems {
+			return a.ObjectItems
+		}
+		return preference(a.Name) < preference(b.Name)
+	})
+
+	names := make([]string, 0, len(unique))
+	for _, field := range unique {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+// resourceName returns the last segment of a path that is not a parameter: /orgs/{id}/users -> users
+func resourceName(pathName string) string {
+	segments := strings.Split(strings.Trim(pathName, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] != "" && !strings.HasPrefix(segments[i], "{") {
+			return segments[i]
+		}
+	}
+	return ""
+}
+
+// hasObjectItems reports whether an array field schema holds objects or $refs
+func hasObjectItems(fieldSchema *yaml.Node, doc *yaml.Node) bool {
+	if ref := getVendorNodeValue(fieldSchema, "$ref"); ref != nil {
+		if resolved := resolveVendorRef(ref.Value, doc); resolved != nil {
+			return hasObjectItems(resolved, doc)
+		}
+		return false
+	}
+	items := getVendorNodeValue(fieldSchema, "items")
+	if items == nil || items.Kind != yaml.MappingNode {
+		return false
+	}
+	if getVendorNodeValue(items, "$ref") != nil {
+		return true
+	}
+	if typeNode := getVendorNodeValue(items, "type"); typeNode != nil {
+		return typeNode.Value == "object"
+	}
+	for _, key := range []string{"properties", "allOf", "oneOf", "anyOf"} {
+		if getVendorNodeValue(items, key) != nil {
+			return true
+		}
+	}
+	return false
+}
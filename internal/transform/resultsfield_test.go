@@ -0,0 +1,105 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/pagination"
+)
+
+func TestRankResultsFields(t *testing.T) {
+	fields := []arrayField{
+		{Name: "errors", ObjectItems: true},
+		{Name: "tags"},
+		{Name: "members", ObjectItems: true},
+		{Name: "users", ObjectItems: true},
+		{Name: "tags"},
+	}
+
+	if got, want := rankResultsFields(fields, "/orgs/{id}/users", config.ResultsRanking{}), []string{"users", "members", "tags", "errors"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := resourceName("/orgs/{id}/user/{userId}"); got != "user" {
+		t.Errorf("expected the last static segment, got %q", got)
+	}
+	// The singular resource name matches its plural field
+	if got := rankResultsFields(fields, "/user", config.ResultsRanking{})[0]; got != "users" {
+		t.Errorf("expected users for /user, got %q", got)
+	}
+
+	ranking := config.ResultsRanking{Preferred: []string{"members"}, Ignored: []string{}}
+	if got, want := rankResultsFields(fields, "/orgs/{id}/users", ranking), []string{"members", "errors", "users", "tags"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the configured ranking %v, got %v", want, got)
+	}
+}
+
+func TestBuildTemplateContextPrefersObjectArrays(t *testing.T) {
+	responses := parseYAMLToNode(t, `
+"200":
+  description: Success
+  content:
+    application/json:
+      schema:
+        type: object
+        properties:
+          warnings:
+            type: array
+            items:
+              type: string
+          errors:
+            type: array
+            items:
+              $ref: '#/components/schemas/Error'
+          entries:
+            type: array
+            items:
+              type: object
+`)
+
+	context, ranked := buildTemplateContext(pagination.DetectedPagination{Strategy: "cursor"}, config.ProviderConfig{}, nil, responses, nil, "/entries")
+	if context["results_field"] != "entries" {
+		t.Errorf("expected entries as the results field, got %q", context["results_field"])
+	}
+	if want := []string{"entries", "errors", "warnings"}; !reflect.DeepEqual(ranked, want) {
+		t.Errorf("expected the ranked alternatives %v, got %v", want, ranked)
+	}
+}
+
+func TestAddVendorExtensionConfiguredResultsField(t *testing.T) {
+	responses := parseYAMLToNode(t, `
+"200":
+  description: Success
+  content:
+    application/json:
+      schema:
+        type: object
+        properties:
+          errors:
+            type: array
+            items:
+              type: object
+          network_acls:
+            type: array
+            items:
+              type: string
+`)
+	provider := config.ProviderConfig{
+		ExtensionName: "x-pagination",
+		Strategies: map[string]config.StrategyConfig{
+			"cursor": {
+				Template:       map[string]interface{}{"results": "$response.{results_field}"},
+				RequiredFields: []string{"results_field"},
+			},
+		},
+	}
+
+	operation := parseYAMLToNode(t, "operationId: listNetworkACLs")
+	added, ranked, _ := addVendorExtension(operation, pagination.DetectedPagination{Strategy: "cursor"}, provider, nil, responses, nil, "/network-acls", "network_acls", ExtensionPlacementEnd)
+	if !added || ranked != nil {
+		t.Fatalf("expected the extension without ranked alternatives, got %v, %v", added, ranked)
+	}
+	if got := getNodeValue(getNodeValue(operation, "x-pagination"), "results").Value; got != "$response.network_acls" {
+		t.Errorf("expected the configured results field, got %q", got)
+	}
+}
@@ -0,0 +1,427 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SDK generators a starter config can be scaffolded for
+const (
+	ScaffoldFern      = "fern"
+	ScaffoldSpeakeasy = "speakeasy"
+)
+
+// scaffoldLanguages lists the SDK languages of each generator: language -> generator name
+// (Fern) or target (Speakeasy)
+var scaffoldLanguages = map[string]map[string]string{
+	ScaffoldFern: {
+		"typescript": "fernapi/fern-typescript-node-sdk",
+		"python":     "fernapi/fern-python-sdk",
+		"go":         "fernapi/fern-go-sdk",
+		"java":       "fernapi/fern-java-sdk",
+	},
+	ScaffoldSpeakeasy: {
+		"typescript": "typescript",
+		"python":     "python",
+		"go":         "go",
+		"java":       "java",
+	},
+}
+
+// scaffoldPaginationExtensions is the extension each generator reads pagination from
+var scaffoldPaginationExtensions = map[string]string{
+	ScaffoldFern:      "x-fern-pagination",
+	ScaffoldSpeakeasy: "x-speakeasy-pagination",
+}
+
+// ScaffoldOptions configures the generation of a starter SDK generator config
+type ScaffoldOptions struct {
+	Generator string   // ScaffoldFern or ScaffoldSpeakeasy
+	Languages []string // SDK languages to configure
+	SpecPath  string   // how the config refers to the spec, the input path when empty
+}
+
+// SpecProfile is what OpenMorph knows about a spec that an SDK generator config can use
+type SpecProfile struct {
+	Title      string
+	Operations int
+	Tags       []string       // declared tags, then tags only used by operations
+	Servers    []string       // "url (description)"
+	Auth       []string       // "name (type ...)"
+	Pagination map[string]int // strategy -> operations using it, "none" for unpaginated ones
+	Unmarked   int            // paginated operations without the generator's pagination extension
+}
+
+// ValidateScaffoldOptions checks the generator and languages of a scaffold
+func ValidateScaffoldOptions(opts ScaffoldOptions) error {
+	languages, ok := scaffoldLanguages[opts.Generator]
+	if !ok {
+		return fmt.Errorf("unknown generator %q (expected %s or %s)", opts.Generator, ScaffoldFern, ScaffoldSpeakeasy)
+	}
+	if len(opts.Languages) == 0 {
+		return fmt.Errorf("at least one language is required")
+	}
+	for _, language := range opts.Languages {
+		if _, ok := languages[language]; !ok {
+			return fmt.Errorf("unsupported %s language %q (expected %s)", opts.Generator, language, strings.Join(sortedKeysOf(languages), ", "))
+		}
+	}
+	return nil
+}
+
+// ScaffoldGeneratorConfig returns a starter Fern generators.yml or Speakeasy workflow.yaml for the
+// spec at inputPath, with what the spec tells about tags, servers, auth and pagination written
+// as comments at the top. It never modifies the spec.
+func ScaffoldGeneratorConfig(inputPath string, opts ScaffoldOptions) ([]byte, *SpecProfile, error) {
+	if err := ValidateScaffoldOptions(opts); err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := loadAndParseDocument(inputPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return nil, nil, fmt.Errorf("%s is not an OpenAPI document", inputPath)
+	}
+	profile := profileSpec(inputPath, root, scaffoldPaginationExtensions[opts.Generator])
+
+	specPath := opts.SpecPath
+	if specPath == "" {
+		specPath = filepath.ToSlash(inputPath)
+	}
+	var config interface{}
+	if opts.Generator == ScaffoldFern {
+		config = fernGeneratorsConfig(profile, specPath, opts.Languages)
+	} else {
+		config = speakeasyWorkflowConfig(profile, specPath, opts.Languages)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(scaffoldHeader(profile, opts.Generator, inputPath))
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return nil, nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), profile, nil
+}
+
+// profileSpec collects the tags, servers, security schemes and pagination of a document
+func profileSpec(path string, root *yaml.Node, paginationExtension string) *SpecProfile {
+	profile := &SpecProfile{Title: getStringValue(getNodeValue(root, "info"), "title")}
+
+	seenTags := make(map[string]bool)
+	addTag := func(name string) {
+		if name != "" && !seenTags[name] {
+			seenTags[name] = true
+			profile.Tags = append(profile.Tags, name)
+		}
+	}
+	if tags := getNodeValue(root, "tags"); tags != nil && tags.Kind == yaml.SequenceNode {
+		for _, tag := range tags.Content {
+			addTag(getStringValue(tag, "name"))
+		}
+	}
+
+	stats := newSpecStats(path)
+	if paths := getNodeValue(root, "paths"); paths != nil && paths.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			pathItem := paths.Content[i+1]
+			if pathItem.Kind != yaml.MappingNode {
+				continue
+			}
+			pathParams := getNodeValue(pathItem, "parameters")
+			for j := 0; j+1 < len(pathItem.Content); j += 2 {
+				operation := pathItem.Content[j+1]
+				if !isHTTPMethod(pathItem.Content[j].Value) || operation.Kind != yaml.MappingNode {
+					continue
+				}
+				profile.Operations++
+				if tags := getNodeValue(operation, "tags"); tags != nil && tags.Kind == yaml.SequenceNode {
+					for _, tag := range tags.Content {
+						addTag(tag.Value)
+					}
+				}
+
+				unpaginated := stats.Pagination["none"]
+				countPaginationStrategies(stats, paginationStrategies(operation, getNodeValue(operation, "parameters"), pathParams, root))
+				if stats.Pagination["none"] == unpaginated && getNodeValue(operation, paginationExtension) == nil {
+					profile.Unmarked++
+				}
+			}
+		}
+	}
+	profile.Pagination = stats.Pagination
+
+	profile.Servers = profileServers(root)
+	profile.Auth = profileSecuritySchemes(root)
+	return profile
+}
+
+// profileServers describes the servers of a document, or the host of a Swagger 2.0 document
+func profileServers(root *yaml.Node) []string {
+	var servers []string
+	if list := getNodeValue(root, "servers"); list != nil && list.Kind == yaml.SequenceNode {
+		for _, server := range list.Content {
+			url := getStringValue(server, "url")
+			if url == "" {
+				continue
+			}
+			if description := getStringValue(server, "description"); description != "" {
+				url += " (" + description + ")"
+			}
+			servers = append(servers, url)
+		}
+	}
+	if host := getStringValue(root, "host"); host != "" {
+		scheme := "https"
+		if schemes := getNodeValue(root, "schemes"); schemes != nil && schemes.Kind == yaml.SequenceNode && len(schemes.Content) > 0 {
+			scheme = schemes.Content[0].Value
+		}
+		servers = append(servers, scheme+"://"+host+getStringValue(root, "basePath"))
+	}
+	return servers
+}
+
+// profileSecuritySchemes describes the security schemes of a document, in name order
+func profileSecuritySchemes(root *yaml.Node) []string {
+	schemes := getNodeValue(getNodeValue(root, "components"), "securitySchemes")
+	if schemes == nil {
+		schemes = getNodeValue(root, "securityDefinitions")
+	}
+	if schemes == nil || schemes.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var auth []string
+	for i := 0; i+1 < len(schemes.Content); i += 2 {
+		name, scheme := schemes.Content[i].Value, schemes.Content[i+1]
+		description := getStringValue(scheme, "type")
+		switch description {
+		case "http":
+			description += " " + getStringValue(scheme, "scheme")
+		case "apiKey":
+			description += " in " + getStringValue(scheme, "in") + " " + getStringValue(scheme, "name")
+		case "oauth2":
+			if flows := getNodeValue(scheme, "flows"); flows != nil && flows.Kind == yaml.MappingNode {
+				var names []string
+				for j := 0; j+1 < len(flows.Content); j += 2 {
+					names = append(names, flows.Content[j].Value)
+				}
+				description += " " + strings.Join(names, ", ")
+			} else if flow := getStringValue(scheme, "flow"); flow != "" {
+				description += " " + flow
+			}
+		}
+		auth = append(auth, name+" ("+strings.TrimSpace(description)+")")
+	}
+	sort.Strings(auth)
+	return auth
+}
+
+// scaffoldHeader returns the comment block of a scaffolded config
+func scaffoldHeader(profile *SpecProfile, generator, inputPath string) string {
+	file := "generators.yml"
+	if generator == ScaffoldSpeakeasy {
+		file = ".speakeasy/workflow.yaml"
+	}
+
+	lines := []string{
+		fmt.Sprintf("Starter %s generated by `openmorph scaffold %s` from %s", file, generator, filepath.Base(inputPath)),
+		"",
+		fmt.Sprintf("Operations: %d", profile.Operations),
+		"Tags: " + listOrNone(profile.Tags),
+		"Servers: " + listOrNone(profile.Servers),
+		"Auth schemes: " + listOrNone(profile.Auth),
+		"Pagination: " + describePagination(profile.Pagination),
+	}
+
+	var notes []string
+	if profile.Unmarked > 0 {
+		notes = append(notes, fmt.Sprintf("%d paginated %s no %s: enable the %s vendor extension provider of OpenMorph so the SDK can page through the results.",
+			profile.Unmarked, pluralize(profile.Unmarked, "operation has", "operations have"), scaffoldPaginationExtensions[generator], generator))
+	}
+	if len(profile.Servers) == 0 {
+		notes = append(notes, "The spec has no servers, so SDK users will have to pass a base URL.")
+	}
+	if generator == ScaffoldFern {
+		notes = append(notes, "Pin a version for each generator before running `fern generate`.")
+	}
+	if len(notes) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, notes...)
+	}
+
+	var header strings.Builder
+	for _, line := range lines {
+		header.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+	return header.String()
+}
+
+// describePagination describes the distribution of pagination strategies
+func describePagination(strategies map[string]int) string {
+	var parts []string
+	for _, strategy := range SortedCounts(strategies) {
+		if strategy == "none" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d)", strategy, strategies[strategy]))
+	}
+	if none := strategies["none"]; none > 0 {
+		parts = append(parts, fmt.Sprintf("%d unpaginated", none))
+	}
+	return listOrNone(parts)
+}
+
+// listOrNone joins items with commas, or returns "none"
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+// pluralize returns singular for one and plural otherwise
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+// fernGenerators is a Fern generators.yml
+type fernGenerators struct {
+	API          fernAPI              `yaml:"api"`
+	DefaultGroup string               `yaml:"default-group"`
+	Groups       map[string]fernGroup `yaml:"groups"`
+}
+
+type fernAPI struct {
+	Specs []fernSpec `yaml:"specs"`
+}
+
+type fernSpec struct {
+	OpenAPI string `yaml:"openapi"`
+}
+
+type fernGroup struct {
+	Generators []fernGenerator `yaml:"generators"`
+}
+
+type fernGenerator struct {
+	Name   string                 `yaml:"name"`
+	Output fernOutput             `yaml:"output"`
+	Config map[string]interface{} `yaml:"config,omitempty"`
+}
+
+type fernOutput struct {
+	Location string `yaml:"location"`
+	Path     string `yaml:"path"`
+}
+
+// fernGeneratorsConfig returns a generators.yml with one local generator per language
+func fernGeneratorsConfig(profile *SpecProfile, specPath string, languages []string) fernGenerators {
+	client := clientName(profile.Title)
+	group := fernGroup{}
+	for _, language := range languages {
+		generator := fernGenerator{
+			Name:   scaffoldLanguages[ScaffoldFern][language],
+			Output: fernOutput{Location: "local-file-system", Path: "../sdks/" + language},
+		}
+		switch language {
+		case "typescript":
+			generator.Config = map[string]interface{}{"namespaceExport": client}
+		case "python":
+			generator.Config = map[string]interface{}{"client_class_name": client}
+		}
+		group.Generators = append(group.Generators, generator)
+	}
+	return fernGenerators{
+		API:          fernAPI{Specs: []fernSpec{{OpenAPI: specPath}}},
+		DefaultGroup: "local",
+		Groups:       map[string]fernGroup{"local": group},
+	}
+}
+
+// speakeasyWorkflow is a Speakeasy workflow.yaml
+type speakeasyWorkflow struct {
+	WorkflowVersion  string                     `yaml:"workflowVersion"`
+	SpeakeasyVersion string                     `yaml:"speakeasyVersion"`
+	Sources          map[string]speakeasySource `yaml:"sources"`
+	Targets          map[string]speakeasyTarget `yaml:"targets"`
+}
+
+type speakeasySource struct {
+	Inputs []speakeasyInput `yaml:"inputs"`
+}
+
+type speakeasyInput struct {
+	Location string `yaml:"location"`
+}
+
+type speakeasyTarget struct {
+	Target string `yaml:"target"`
+	Source string `yaml:"source"`
+	Output string `yaml:"output"`
+}
+
+// speakeasyWorkflowConfig returns a workflow.yaml with one source and a target per language
+func speakeasyWorkflowConfig(profile *SpecProfile, specPath string, languages []string) speakeasyWorkflow {
+	slug := sourceSlug(profile.Title)
+	source := slug + "-source"
+	workflow := speakeasyWorkflow{
+		WorkflowVersion:  "1.0.0",
+		SpeakeasyVersion: "latest",
+		Sources:          map[string]speakeasySource{source: {Inputs: []speakeasyInput{{Location: specPath}}}},
+		Targets:          make(map[string]speakeasyTarget),
+	}
+	for _, language := range languages {
+		workflow.Targets[slug+"-"+language] = speakeasyTarget{
+			Target: scaffoldLanguages[ScaffoldSpeakeasy][language],
+			Source: source,
+			Output: "./sdks/" + language,
+		}
+	}
+	return workflow
+}
+
+// titleWords splits an API title into its alphanumeric words
+func titleWords(title string) []string {
+	return strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// clientName turns an API title into a client class name, such as PetStoreAPI for "Pet Store API"
+func clientName(title string) string {
+	var name strings.Builder
+	for _, word := range titleWords(title) {
+		runes := []rune(word)
+		name.WriteString(strings.ToUpper(string(runes[0])) + string(runes[1:]))
+	}
+	if name.Len() == 0 || unicode.IsDigit([]rune(name.String())[0]) {
+		return "Client" + name.String()
+	}
+	return name.String()
+}
+
+// sourceSlug turns an API title into a lowercase, dash-separated name, such as pet-store-api
+func sourceSlug(title string) string {
+	words := titleWords(title)
+	if len(words) == 0 {
+		return "api"
+	}
+	return strings.ToLower(strings.Join(words, "-"))
+}
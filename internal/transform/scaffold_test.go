@@ -0,0 +1,153 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const scaffoldTestSpec = `openapi: 3.0.3
+info:
+  title: Pet Store API
+  version: 1.0.0
+servers:
+  - url: https://api.example.com
+    description: Production
+tags:
+  - name: Pets
+paths:
+  /pets:
+    get:
+      tags: [Pets, Admin]
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+  /pets/{id}:
+    get:
+      x-fern-pagination:
+        cursor: $request.cursor
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+components:
+  securitySchemes:
+    bearer:
+      type: http
+      scheme: bearer
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+`
+
+func TestScaffoldFern(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.yaml")
+	if err := os.WriteFile(path, []byte(scaffoldTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, profile, err := ScaffoldGeneratorConfig(path, ScaffoldOptions{
+		Generator: ScaffoldFern,
+		Languages: []string{"typescript", "go"},
+		SpecPath:  "../api.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(profile.Tags, ",") != "Pets,Admin" {
+		t.Errorf("expected declared tags before used ones, got %v", profile.Tags)
+	}
+	if strings.Join(profile.Auth, "; ") != "apiKey (apiKey in header X-API-Key); bearer (http bearer)" {
+		t.Errorf("unexpected auth schemes %v", profile.Auth)
+	}
+	if profile.Pagination["cursor"] != 2 || profile.Unmarked != 1 {
+		t.Errorf("expected 2 cursor operations, 1 without x-fern-pagination, got %v and %d", profile.Pagination, profile.Unmarked)
+	}
+	for _, want := range []string{
+		"# Servers: https://api.example.com (Production)",
+		"# 1 paginated operation has no x-fern-pagination",
+	} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("expected the header to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	var config struct {
+		API struct {
+			Specs []map[string]string
+		}
+		Groups map[string]struct {
+			Generators []struct {
+				Name   string
+				Config map[string]string
+			}
+		}
+	}
+	if err := yaml.Unmarshal(output, &config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.API.Specs) != 1 || config.API.Specs[0]["openapi"] != "../api.yaml" {
+		t.Errorf("expected the spec path to be used, got %v", config.API.Specs)
+	}
+	generators := config.Groups["local"].Generators
+	if len(generators) != 2 || generators[0].Name != "fernapi/fern-typescript-node-sdk" || generators[1].Name != "fernapi/fern-go-sdk" {
+		t.Fatalf("unexpected generators %+v", generators)
+	}
+	if generators[0].Config["namespaceExport"] != "PetStoreAPI" {
+		t.Errorf("expected the client to be named after the title, got %v", generators[0].Config)
+	}
+}
+
+func TestScaffoldSpeakeasy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.yaml")
+	if err := os.WriteFile(path, []byte(scaffoldTestSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, profile, err := ScaffoldGeneratorConfig(path, ScaffoldOptions{Generator: ScaffoldSpeakeasy, Languages: []string{"python"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Unmarked != 2 {
+		t.Errorf("expected both paginated operations to lack x-speakeasy-pagination, got %d", profile.Unmarked)
+	}
+
+	var workflow struct {
+		Sources map[string]struct {
+			Inputs []map[string]string
+		}
+		Targets map[string]map[string]string
+	}
+	if err := yaml.Unmarshal(output, &workflow); err != nil {
+		t.Fatal(err)
+	}
+	if inputs := workflow.Sources["pet-store-api-source"].Inputs; len(inputs) != 1 || inputs[0]["location"] != filepath.ToSlash(path) {
+		t.Errorf("expected the input path as the source, got %v", workflow.Sources)
+	}
+	if target := workflow.Targets["pet-store-api-python"]; target["target"] != "python" || target["source"] != "pet-store-api-source" {
+		t.Errorf("unexpected targets %v", workflow.Targets)
+	}
+}
+
+func TestValidateScaffoldOptions(t *testing.T) {
+	if err := ValidateScaffoldOptions(ScaffoldOptions{Generator: "openapi-generator", Languages: []string{"go"}}); err == nil {
+		t.Error("expected an unknown generator to be rejected")
+	}
+	if err := ValidateScaffoldOptions(ScaffoldOptions{Generator: ScaffoldFern, Languages: []string{"cobol"}}); err == nil {
+		t.Error("expected an unsupported language to be rejected")
+	}
+}
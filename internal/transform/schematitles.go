@@ -0,0 +1,264 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Title casings, in addition to the name casings
+const (
+	CasingTitle    = "title"    // Billing Invoice
+	CasingSentence = "sentence" // Billing invoice
+)
+
+// Word splits of schema_titles
+const (
+	SchemaTitleSplitCase       = "case"       // at separators and case changes, HTTPResponse is HTTP Response
+	SchemaTitleSplitSeparators = "separators" // only at separators such as _ - . and spaces
+)
+
+// normalizableTitlePattern matches the titles made of words and separators only, the ones
+// normalize rewrites. Titles with other punctuation are prose and left as written.
+var normalizableTitlePattern = regexp.MustCompile(`^[\p{L}\p{N}_.\- ]+$`)
+
+// SchemaTitlesOptions extends the regular Options with schema title settings
+type SchemaTitlesOptions struct {
+	Options
+	SchemaTitles config.SchemaTitles
+}
+
+// SchemaTitlesResult represents the result of schema title generation
+type SchemaTitlesResult struct {
+	Changed          bool
+	ProcessedFiles   []string
+	AddedTitles      map[string][]string // file -> list of titles added to schemas without one
+	NormalizedTitles map[string][]string // file -> list of existing titles rewritten in the casing
+	Locations        []ChangeLocation    // source positions of the titled schemas
+}
+
+// createSchemaTitlesResult creates a new SchemaTitlesResult with initialized maps
+func createSchemaTitlesResult() *SchemaTitlesResult {
+	return &SchemaTitlesResult{
+		ProcessedFiles:   []string{},
+		AddedTitles:      make(map[string][]string),
+		NormalizedTitles: make(map[string][]string),
+	}
+}
+
+// setSchemaTitlesProcessedFiles sets the processed files for a SchemaTitlesResult
+func setSchemaTitlesProcessedFiles(result *SchemaTitlesResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setSchemaTitlesChanged sets the changed flag for a SchemaTitlesResult
+func setSchemaTitlesChanged(result *SchemaTitlesResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessSchemaTitlesInDir fills in and normalizes the titles of component schemas in all OpenAPI
+// files in a directory
+func ProcessSchemaTitlesInDir(dir string, opts SchemaTitlesOptions) (*SchemaTitlesResult, error) {
+	if err := ValidateSchemaTitles(opts.SchemaTitles); err != nil {
+		return createSchemaTitlesResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepSchemaTitles,
+		opts.Options,
+		opts.SchemaTitles.Enabled,
+		false,
+		createSchemaTitlesResult,
+		func(path string, result *SchemaTitlesResult) (bool, error) {
+			return processSchemaTitlesInFile(path, opts, result)
+		},
+		setSchemaTitlesProcessedFiles,
+		setSchemaTitlesChanged,
+	)
+}
+
+// ValidateSchemaTitles checks the casing, the word split and the acronyms
+func ValidateSchemaTitles(titles config.SchemaTitles) error {
+	switch titles.Casing {
+	case "", CasingTitle, CasingSentence, CasingPascal, CasingCamel, CasingSnake, CasingKebab:
+	default:
+		return fmt.Errorf("schema_titles.casing must be %s, %s, %s, %s, %s or %s, got %q",
+			CasingTitle, CasingSentence, CasingPascal, CasingCamel, CasingSnake, CasingKebab, titles.Casing)
+	}
+	switch titles.WordSplit {
+	case "", SchemaTitleSplitCase, SchemaTitleSplitSeparators:
+	default:
+		return fmt.Errorf("schema_titles.word_split must be %s or %s, got %q",
+			SchemaTitleSplitCase, SchemaTitleSplitSeparators, titles.WordSplit)
+	}
+	for _, acronym := range titles.Acronyms {
+		if len(titleWords(acronym)) != 1 {
+			return fmt.Errorf("schema_titles.acronyms: %q must be a single word", acronym)
+		}
+	}
+	return nil
+}
+
+// processSchemaTitlesInFile fills in and normalizes the schema titles of a single file
+func processSchemaTitlesInFile(path string, opts SchemaTitlesOptions, result *SchemaTitlesResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+
+	section, prefix := getNodeValue(getNodeValue(root, "components"), "schemas"), "schemas."
+	if getNodeValue(root, "swagger") != nil {
+		section, prefix = getNodeValue(root, "definitions"), "definitions."
+	}
+	if section == nil || section.Kind != yaml.MappingNode {
+		return false, nil
+	}
+
+	t := newSchemaTitler(opts.SchemaTitles)
+	changed := false
+	for i := 0; i+1 < len(section.Content); i += 2 {
+		name, schema := section.Content[i].Value, section.Content[i+1]
+		if schema.Kind != yaml.MappingNode || getNodeValue(schema, "$ref") != nil {
+			continue
+		}
+
+		title := getNodeValue(schema, "title")
+		switch {
+		case title == nil:
+			value := t.title(name)
+			if value == "" {
+				continue
+			}
+			schema.Content = append([]*yaml.Node{newScalarNode("title"), newScalarNode(value)}, schema.Content...)
+			entry := fmt.Sprintf("%s%s: title %q added", prefix, name, value)
+			result.AddedTitles[path] = append(result.AddedTitles[path], entry)
+			result.Locations = append(result.Locations, newChangeLocation(path, StepSchemaTitles, section.Content[i], entry))
+			changed = true
+		case opts.SchemaTitles.Normalize && title.Kind == yaml.ScalarNode && normalizableTitlePattern.MatchString(title.Value):
+			value := t.title(title.Value)
+			if value == "" || value == title.Value {
+				continue
+			}
+			entry := fmt.Sprintf("%s%s: title %q normalized to %q", prefix, name, title.Value, value)
+			title.Value = value
+			result.NormalizedTitles[path] = append(result.NormalizedTitles[path], entry)
+			result.Locations = append(result.Locations, newChangeLocation(path, StepSchemaTitles, title, entry))
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// schemaTitler renders names and titles in the configured casing
+type schemaTitler struct {
+	casing    string
+	wordSplit string
+	acronyms  map[string]string // lower-case word -> configured spelling
+}
+
+// newSchemaTitler returns a titler for the schema title settings, applying the defaults
+func newSchemaTitler(titles config.SchemaTitles) schemaTitler {
+	t := schemaTitler{casing: titles.Casing, wordSplit: titles.WordSplit, acronyms: make(map[string]string)}
+	if t.casing == "" {
+		t.casing = CasingTitle
+	}
+	if t.wordSplit == "" {
+		t.wordSplit = SchemaTitleSplitCase
+	}
+	for _, acronym := range titles.Acronyms {
+		t.acronyms[strings.ToLower(acronym)] = acronym
+	}
+	return t
+}
+
+// title splits s into words and joins them in the casing. In title and sentence casing, words
+// written entirely in capitals such as HTTP are kept as they are.
+func (t schemaTitler) title(s string) string {
+	words := schemaTitleWords(s, t.wordSplit)
+	rendered := make([]string, len(words))
+	for i, word := range words {
+		lower := strings.ToLower(word)
+		acronym, isAcronym := t.acronyms[lower]
+		switch {
+		case t.casing == CasingSnake || t.casing == CasingKebab || (t.casing == CasingCamel && i == 0):
+			rendered[i] = lower
+		case isAcronym:
+			rendered[i] = acronym
+		case (t.casing == CasingTitle || t.casing == CasingSentence) && isAllCaps(word):
+			rendered[i] = word
+		case t.casing == CasingSentence && i > 0:
+			rendered[i] = lower
+		default:
+			rendered[i] = capitalize(lower)
+		}
+	}
+
+	switch t.casing {
+	case CasingSnake:
+		return strings.Join(rendered, "_")
+	case CasingKebab:
+		return strings.Join(rendered, "-")
+	case CasingPascal, CasingCamel:
+		return strings.Join(rendered, "")
+	default:
+		return strings.Join(rendered, " ")
+	}
+}
+
+// schemaTitleWords splits s into words. The case split also separates a run of capitals from the
+// word that follows it.
+func schemaTitleWords(s, wordSplit string) []string {
+	if wordSplit == SchemaTitleSplitSeparators {
+		return titleWords(s)
+	}
+	var words []string
+	for _, word := range splitWords(s) {
+		words = append(words, splitAcronym(word)...)
+	}
+	return words
+}
+
+// splitAcronym splits a run of capitals from the capitalized word that follows it, so
+// HTTPResponse becomes HTTP and Response. A trailing s stays with the capitals, as in APIs.
+func splitAcronym(word string) []string {
+	runes := []rune(word)
+	for i := 1; i+1 < len(runes); i++ {
+		if !unicode.IsUpper(runes[i-1]) || !unicode.IsUpper(runes[i]) || !unicode.IsLower(runes[i+1]) {
+			continue
+		}
+		if runes[i+1] == 's' && i+2 == len(runes) {
+			break
+		}
+		return append([]string{string(runes[:i])}, splitAcronym(string(runes[i:]))...)
+	}
+	return []string{word}
+}
+
+// isAllCaps reports whether word has more than one letter and no lower-case ones but a plural s
+func isAllCaps(word string) bool {
+	letters := 0
+	for _, r := range strings.TrimSuffix(word, "s") {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters > 1
+}
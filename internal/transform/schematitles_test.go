@@ -0,0 +1,155 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const schemaTitlesSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    billing_invoice:
+      type: object
+    HTTPResponse:
+      type: object
+    UserId:
+      type: string
+    Account:
+      title: user account
+      type: object
+    Legacy:
+      title: "Legacy (deprecated)"
+      type: object
+    Alias:
+      $ref: '#/components/schemas/Account'
+`
+
+func TestSchemaTitles(t *testing.T) {
+	tests := []struct {
+		name       string
+		titles     config.SchemaTitles
+		added      []string
+		normalized []string
+		output     []string
+	}{
+		{
+			name: "default title casing",
+			added: []string{
+				`schemas.billing_invoice: title "Billing Invoice" added`,
+				`schemas.HTTPResponse: title "HTTP Response" added`,
+				`schemas.UserId: title "User Id" added`,
+			},
+			output: []string{"billing_invoice: title: Billing Invoice type: object", "title: user account"},
+		},
+		{
+			name:   "sentence casing with acronyms and normalization",
+			titles: config.SchemaTitles{Casing: CasingSentence, Acronyms: []string{"ID"}, Normalize: true},
+			added: []string{
+				`schemas.billing_invoice: title "Billing invoice" added`,
+				`schemas.HTTPResponse: title "HTTP response" added`,
+				`schemas.UserId: title "User ID" added`,
+			},
+			normalized: []string{`schemas.Account: title "user account" normalized to "User account"`},
+			output:     []string{"title: User account", `title: "Legacy (deprecated)"`},
+		},
+		{
+			name:   "pascal casing split at separators only",
+			titles: config.SchemaTitles{Casing: CasingPascal, WordSplit: SchemaTitleSplitSeparators},
+			added: []string{
+				`schemas.billing_invoice: title "BillingInvoice" added`,
+				`schemas.HTTPResponse: title "Httpresponse" added`,
+				`schemas.UserId: title "Userid" added`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "api.yaml")
+			if err := os.WriteFile(path, []byte(schemaTitlesSpec), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.titles.Enabled = true
+			opts := SchemaTitlesOptions{SchemaTitles: tt.titles}
+			result, err := ProcessSchemaTitlesInDir(dir, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.AddedTitles[path], tt.added) {
+				t.Errorf("expected added titles %v, got %v", tt.added, result.AddedTitles[path])
+			}
+			if !reflect.DeepEqual(result.NormalizedTitles[path], tt.normalized) {
+				t.Errorf("expected normalized titles %v, got %v", tt.normalized, result.NormalizedTitles[path])
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Compare without indentation
+			flat := strings.Join(strings.Fields(string(data)), " ")
+			for _, want := range tt.output {
+				if !strings.Contains(flat, want) {
+					t.Errorf("expected %q in the output:\n%s", want, data)
+				}
+			}
+			if strings.Contains(flat, "Alias: title") {
+				t.Errorf("expected no title next to a $ref:\n%s", data)
+			}
+
+			// A second run finds every title in place
+			result, err = ProcessSchemaTitlesInDir(dir, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Changed {
+				t.Errorf("expected no changes on a second run, got %v %v", result.AddedTitles, result.NormalizedTitles)
+			}
+		})
+	}
+}
+
+func TestSchemaTitleWords(t *testing.T) {
+	tests := map[string][]string{
+		"HTTPResponse":     {"HTTP", "Response"},
+		"listAPIs":         {"list", "APIs"},
+		"billing_invoice2": {"billing", "invoice2"},
+		"userID":           {"user", "ID"},
+	}
+	for input, want := range tests {
+		if got := schemaTitleWords(input, SchemaTitleSplitCase); !reflect.DeepEqual(got, want) {
+			t.Errorf("schemaTitleWords(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestValidateSchemaTitles(t *testing.T) {
+	tests := []struct {
+		name    string
+		titles  config.SchemaTitles
+		wantErr bool
+	}{
+		{"defaults", config.SchemaTitles{}, false},
+		{"unknown casing", config.SchemaTitles{Casing: "upper"}, true},
+		{"unknown word split", config.SchemaTitles{WordSplit: "letters"}, true},
+		{"multi-word acronym", config.SchemaTitles{Acronyms: []string{"HTTP API"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchemaTitles(tt.titles)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
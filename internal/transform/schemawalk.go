@@ -0,0 +1,228 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaTarget describes where a schema sits, for matching rule conditions and reporting
+type schemaTarget struct {
+	context     string
+	name        string     // property or parameter name
+	method      string     // HTTP method of the operation, empty outside operations
+	pathName    string     // API path, empty for components
+	operation   *yaml.Node // operation the schema belongs to, nil outside operations
+	parameterIn string     // location of the parameter the schema belongs to
+	required    bool
+	property    bool // the schema is an object property
+}
+
+// schemaVisitor is called with every schema a schemaWalker finds and reports whether it changed it
+type schemaVisitor func(schema *yaml.Node, target schemaTarget) bool
+
+// walkDocumentSchemas calls visit with every inline schema of the document's operations and
+// components: parameters, request bodies, responses, component schemas and their nested schemas
+func walkDocumentSchemas(root *yaml.Node, visit schemaVisitor) bool {
+	w := &schemaWalker{visit: visit}
+	return w.walkDocument(root)
+}
+
+// schemaWalker walks the schemas of one document
+type schemaWalker struct {
+	visit schemaVisitor
+}
+
+// walkDocument visits the schemas of every operation and component
+func (w *schemaWalker) walkDocument(root *yaml.Node) bool {
+	changed := false
+	if paths := getNodeValue(root, "paths"); paths != nil && paths.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			if w.walkPathItem(paths.Content[i].Value, paths.Content[i+1]) {
+				changed = true
+			}
+		}
+	}
+
+	components := getNodeValue(root, "components")
+	sections := map[string]*yaml.Node{
+		"schemas":    getNodeValue(components, "schemas"),
+		"parameters": getNodeValue(components, "parameters"),
+	}
+	if getNodeValue(root, "swagger") != nil {
+		sections = map[string]*yaml.Node{
+			"definitions": getNodeValue(root, "definitions"),
+			"parameters":  getNodeValue(root, "parameters"),
+		}
+	}
+	for _, section := range []string{"schemas", "definitions", "parameters"} {
+		entries := sections[section]
+		if entries == nil || entries.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(entries.Content); i += 2 {
+			name, entry := entries.Content[i].Value, entries.Content[i+1]
+			context := "component " + componentKey(section, name)
+			if section == "parameters" {
+				if w.walkParameter(entry, schemaTarget{context: context}) {
+					changed = true
+				}
+			} else if w.walkSchema(entry, schemaTarget{context: context, name: name}) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// walkPathItem visits the parameter, request body and response schemas of a path item
+func (w *schemaWalker) walkPathItem(pathName string, pathItem *yaml.Node) bool {
+	changed := false
+	if w.walkParameters(getNodeValue(pathItem, "parameters"), schemaTarget{context: pathName, pathName: pathName}) {
+		changed = true
+	}
+
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		method, operation := pathItem.Content[i].Value, pathItem.Content[i+1]
+		if !isHTTPMethod(method) {
+			continue
+		}
+		target := schemaTarget{context: fmt.Sprintf("%s %s", strings.ToUpper(method), pathName), method: method, pathName: pathName, operation: operation}
+
+		if w.walkParameters(getNodeValue(operation, "parameters"), target) {
+			changed = true
+		}
+		if w.walkContent(getNodeValue(getNodeValue(operation, "requestBody"), "content"), target, " requestBody") {
+			changed = true
+		}
+		if responses := getNodeValue(operation, "responses"); responses != nil && responses.Kind == yaml.MappingNode {
+			for j := 0; j+1 < len(responses.Content); j += 2 {
+				response := responses.Content[j+1]
+				suffix := " response " + responses.Content[j].Value
+				if w.walkContent(getNodeValue(response, "content"), target, suffix) {
+					changed = true
+				}
+				if schema := getNodeValue(response, "schema"); schema != nil { // Swagger 2.0
+					responseTarget := target
+					responseTarget.context += suffix
+					if w.walkSchema(schema, responseTarget) {
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// walkParameters visits the schema of every inline parameter in a parameters list
+func (w *schemaWalker) walkParameters(params *yaml.Node, target schemaTarget) bool {
+	if params == nil || params.Kind != yaml.SequenceNode {
+		return false
+	}
+	changed := false
+	for _, param := range params.Content {
+		if w.walkParameter(param, target) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// walkParameter visits a parameter's schema, or that of its content. Swagger 2.0 non-body
+// parameters carry their schema fields on the parameter itself.
+func (w *schemaWalker) walkParameter(param *yaml.Node, target schemaTarget) bool {
+	if param == nil || param.Kind != yaml.MappingNode || getNodeValue(param, "$ref") != nil {
+		return false
+	}
+	target.name = getStringValue(param, "name")
+	target.parameterIn = getStringValue(param, "in")
+	target.required = getStringValue(param, "required") == "true"
+	target.context += " parameter " + target.name
+
+	schema := parameterSchema(param)
+	if schema == nil && getNodeValue(param, "type") != nil {
+		schema = param
+	}
+	return w.walkSchema(schema, target)
+}
+
+// parameterSchema returns the schema of a parameter, or that of its media type when it is
+// serialized through content instead
+func parameterSchema(param *yaml.Node) *yaml.Node {
+	if schema := getNodeValue(param, "schema"); schema != nil {
+		return schema
+	}
+	content := getNodeValue(param, "content")
+	if content == nil || content.Kind != yaml.MappingNode || len(content.Content) < 2 {
+		return nil
+	}
+	return getNodeValue(content.Content[1], "schema")
+}
+
+// walkContent visits the schema of every media type in a content map
+func (w *schemaWalker) walkContent(content *yaml.Node, target schemaTarget, suffix string) bool {
+	if content == nil || content.Kind != yaml.MappingNode {
+		return false
+	}
+	changed := false
+	for i := 0; i+1 < len(content.Content); i += 2 {
+		mediaTarget := target
+		mediaTarget.context += suffix + " " + content.Content[i].Value
+		if w.walkSchema(getNodeValue(content.Content[i+1], "schema"), mediaTarget) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// walkSchema visits a schema and, recursively, its properties, items and composed schemas.
+// $refs are not followed; the schemas they point at are visited where they are defined.
+func (w *schemaWalker) walkSchema(schema *yaml.Node, target schemaTarget) bool {
+	if schema == nil || schema.Kind != yaml.MappingNode || getNodeValue(schema, "$ref") != nil {
+		return false
+	}
+
+	changed := w.visit(schema, target)
+
+	nested := schemaTarget{method: target.method, pathName: target.pathName, operation: target.operation}
+	if properties := getNodeValue(schema, "properties"); properties != nil && properties.Kind == yaml.MappingNode {
+		required := make(map[string]bool)
+		if list := getNodeValue(schema, "required"); list != nil && list.Kind == yaml.SequenceNode {
+			for _, item := range list.Content {
+				required[item.Value] = true
+			}
+		}
+		for i := 0; i+1 < len(properties.Content); i += 2 {
+			name := properties.Content[i].Value
+			property := nested
+			property.context, property.name, property.required = target.context+" property "+name, name, required[name]
+			property.property = true
+			if w.walkSchema(properties.Content[i+1], property) {
+				changed = true
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		child := nested
+		child.context = target.context + " " + key
+		if w.walkSchema(getNodeValue(schema, key), child) {
+			changed = true
+		}
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		composition := getNodeValue(schema, key)
+		if composition == nil || composition.Kind != yaml.SequenceNode {
+			continue
+		}
+		for i, item := range composition.Content {
+			child := target
+			child.context = fmt.Sprintf("%s %s[%d]", target.context, key, i)
+			if w.walkSchema(item, child) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,50 @@
+package transform
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+)
+
+// ValidatePaths checks the path patterns of --paths / only_paths, which are globs unless prefixed with regex:
+func ValidatePaths(patterns []string) error {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return fmt.Errorf("only_paths: empty pattern")
+		}
+		if err := condition.ValidatePatterns([]string{pattern}, condition.Glob); err != nil {
+			return fmt.Errorf("only_paths: %v", err)
+		}
+	}
+	return nil
+}
+
+// scopeToPaths returns a view of the document root whose paths section only holds the path items
+// matching one of the patterns, or root itself when there are none. The view shares every node
+// with root, so changes made through it apply to the document.
+func scopeToPaths(root *yaml.Node, patterns []string) *yaml.Node {
+	if len(patterns) == 0 || root.Kind != yaml.MappingNode {
+		return root
+	}
+
+	view := *root
+	view.Content = make([]*yaml.Node, len(root.Content))
+	copy(view.Content, root.Content)
+	for i := 0; i+1 < len(view.Content); i += 2 {
+		paths := view.Content[i+1]
+		if view.Content[i].Value != "paths" || paths.Kind != yaml.MappingNode {
+			continue
+		}
+		scoped := *paths
+		scoped.Content = nil
+		for j := 0; j+1 < len(paths.Content); j += 2 {
+			if condition.MatchPath(paths.Content[j].Value, patterns, condition.Glob) {
+				scoped.Content = append(scoped.Content, paths.Content[j], paths.Content[j+1])
+			}
+		}
+		view.Content[i+1] = &scoped
+	}
+	return &view
+}
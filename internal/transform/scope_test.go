@@ -0,0 +1,89 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestScopeToPaths(t *testing.T) {
+	var doc yaml.Node
+	spec := "openapi: 3.0.3\npaths:\n  /users: {}\n  /users/{id}: {}\n  /orgs/{id}: {}\n  /billing: {}\n"
+	if err := yaml.Unmarshal([]byte(spec), &doc); err != nil {
+		t.Fatal(err)
+	}
+	root := getRootNode(&doc)
+
+	view := scopeToPaths(root, []string{"/users/**", "/orgs/*"})
+	var got []string
+	paths := getNodeValue(view, "paths")
+	for i := 0; i < len(paths.Content); i += 2 {
+		got = append(got, paths.Content[i].Value)
+	}
+	if strings.Join(got, ",") != "/users,/users/{id},/orgs/{id}" {
+		t.Errorf("unexpected scoped paths %v", got)
+	}
+	if len(getNodeValue(root, "paths").Content) != 8 {
+		t.Error("expected the document to keep every path")
+	}
+	if scopeToPaths(root, nil) != root {
+		t.Error("expected no patterns to return the root itself")
+	}
+}
+
+func TestPaginationLimitedToPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	operation := `    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: page
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+`
+	spec := "openapi: 3.0.3\ninfo:\n  title: Test API\n  version: 1.0.0\npaths:\n  /users:\n" + operation + "  /billing:\n" + operation
+	if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessPaginationInDir(dir, PaginationOptions{
+		Options:            Options{Paths: []string{"/users*"}},
+		PaginationPriority: []string{"cursor", "page"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result.RemovedParams["GET /users"]; !ok {
+		t.Errorf("expected GET /users to be processed, got %v", result.RemovedParams)
+	}
+	if _, ok := result.RemovedParams["GET /billing"]; ok {
+		t.Errorf("expected GET /billing to be out of scope, got %v", result.RemovedParams)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), "name: page") != 1 {
+		t.Errorf("expected only /users to lose the page parameter, got:\n%s", data)
+	}
+}
+
+func TestValidatePaths(t *testing.T) {
+	if err := ValidatePaths([]string{"/users["}); err == nil {
+		t.Error("expected an invalid pattern error")
+	}
+	if err := ValidatePaths([]string{"/users*", "/orgs/*"}); err != nil {
+		t.Errorf("expected valid patterns, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SkipCode classifies why a step left an item alone, so skips can be counted across steps and files
+type SkipCode string
+
+// Reasons the steps skip an item
+const (
+	SkipDefaultExists       SkipCode = "default_exists"          // the target already has a default or the field to fill
+	SkipLocationMismatch    SkipCode = "location_mismatch"       // the parameter location differs from the rule's
+	SkipNameMismatch        SkipCode = "name_mismatch"           // the name doesn't match the rule's pattern
+	SkipTypeMismatch        SkipCode = "type_mismatch"           // the type differs from the rule's
+	SkipFormatMismatch      SkipCode = "format_mismatch"         // the format differs from the rule's
+	SkipMissingEnum         SkipCode = "missing_enum"            // the rule requires an enum the schema doesn't have
+	SkipNotArray            SkipCode = "not_array"               // the rule requires an array schema
+	SkipNoSchema            SkipCode = "no_schema"               // there is no schema or object to set the value in
+	SkipInvalidValue        SkipCode = "invalid_value"           // the value doesn't fit the target
+	SkipInvalidRule         SkipCode = "invalid_rule"            // the rule cannot apply to any target
+	SkipProviderCriteria    SkipCode = "provider_criteria"       // the operation doesn't match the provider's criteria
+	SkipNoSuccessResponse   SkipCode = "no_success_response"     // no success response with the provider's media types
+	SkipNoPagination        SkipCode = "no_pagination"           // no pagination was detected for the provider
+	SkipMissingFields       SkipCode = "missing_required_fields" // the template context lacks required fields of the strategy
+	SkipNoTags              SkipCode = "no_tags"                 // the operations carry no tags to group by
+	SkipMixedTags           SkipCode = "mixed_tags"              // the operations of a path start with different tags
+	SkipEmptyName           SkipCode = "empty_name"              // the rename would leave no name
+	SkipNameCollision       SkipCode = "name_collision"          // the rename collides with another component
+	SkipDiscriminatorValue  SkipCode = "discriminator_value"     // a Swagger 2.0 discriminator selects the schema by its name
+	SkipExcluded            SkipCode = "excluded"                // the config or a marker opts the schema out
+	SkipUnmergeableAllOf    SkipCode = "unmergeable_allof"       // the allOf members cannot be merged into one schema
+	SkipConflictingKeywords SkipCode = "conflicting_keywords"    // the collapsed member redefines a keyword of its wrapper
+	SkipMissingComponentRef SkipCode = "missing_component_ref"   // the replacement references components the file lacks
+	SkipIgnoredFile         SkipCode = "ignored_file"            // the file is marked x-openmorph: ignore
+	SkipNotOpenAPI          SkipCode = "not_openapi"             // the file is not an OpenAPI or AsyncAPI document
+)
+
+// skipCodeDescriptions are the phrases skips are grouped under
+var skipCodeDescriptions = map[SkipCode]string{
+	SkipDefaultExists:       "default already exists",
+	SkipLocationMismatch:    "parameter location doesn't match",
+	SkipNameMismatch:        "name doesn't match pattern",
+	SkipTypeMismatch:        "type doesn't match",
+	SkipFormatMismatch:      "format doesn't match",
+	SkipMissingEnum:         "no enum found but required by rule",
+	SkipNotArray:            "not an array but required by rule",
+	SkipNoSchema:            "nothing to set the value in",
+	SkipInvalidValue:        "value doesn't fit the target",
+	SkipInvalidRule:         "rule cannot apply",
+	SkipProviderCriteria:    "doesn't match provider criteria",
+	SkipNoSuccessResponse:   "no matching success response",
+	SkipNoPagination:        "no pagination detected",
+	SkipMissingFields:       "missing required template fields",
+	SkipNoTags:              "no tags",
+	SkipMixedTags:           "operations have different first tags",
+	SkipEmptyName:           "rename would produce an empty name",
+	SkipNameCollision:       "rename collides with another component",
+	SkipDiscriminatorValue:  "selected by name by a Swagger 2.0 discriminator",
+	SkipExcluded:            "excluded from flattening",
+	SkipUnmergeableAllOf:    "allOf cannot be merged",
+	SkipConflictingKeywords: "member conflicts with its wrapper",
+	SkipMissingComponentRef: "references components the file does not define",
+	SkipIgnoredFile:         "marked x-openmorph: ignore",
+	SkipNotOpenAPI:          "not an OpenAPI or AsyncAPI document",
+}
+
+// Description returns the phrase skips with the code are grouped under
+func (c SkipCode) Description() string {
+	if description, ok := skipCodeDescriptions[c]; ok {
+		return description
+	}
+	return string(c)
+}
+
+// Skip is an item a step left alone, with the reason
+type Skip struct {
+	Message string // the item followed by the reason, such as "GET /users parameter limit: default already exists"
+	Code    SkipCode
+}
+
+func (s Skip) String() string {
+	return s.Message
+}
+
+// SkipMessages returns the messages of skips, in order
+func SkipMessages(skips []Skip) []string {
+	messages := make([]string, len(skips))
+	for i, skip := range skips {
+		messages[i] = skip.Message
+	}
+	return messages
+}
+
+// SkipMessagesByFile returns the messages of the skips of every file
+func SkipMessagesByFile(skips map[string][]Skip) map[string][]string {
+	messages := make(map[string][]string, len(skips))
+	for file, fileSkips := range skips {
+		messages[file] = SkipMessages(fileSkips)
+	}
+	return messages
+}
+
+// SkipGroup is every item skipped for one reason across the steps of a run
+type SkipGroup struct {
+	Code  SkipCode
+	Steps []string // steps that skipped items for the reason, sorted; skipped files name none
+	Items []string // "file: message", sorted
+}
+
+// SkipGroups groups the items the steps skipped by reason, most frequent first
+func (r *TransformationResults) SkipGroups() []SkipGroup {
+	groups := make(map[SkipCode]*SkipGroup)
+	steps := make(map[SkipCode]map[string]bool)
+	add := func(step, file string, skip Skip) {
+		group, ok := groups[skip.Code]
+		if !ok {
+			group = &SkipGroup{Code: skip.Code}
+			groups[skip.Code] = group
+			steps[skip.Code] = make(map[string]bool)
+		}
+		group.Items = append(group.Items, fmt.Sprintf("%s: %s", file, skip.Message))
+		if step != "" {
+			steps[skip.Code][step] = true
+		}
+	}
+	addAll := func(step string, skips map[string][]Skip) {
+		for file, fileSkips := range skips {
+			for _, skip := range fileSkips {
+				add(step, file, skip)
+			}
+		}
+	}
+
+	for _, file := range r.SkippedFiles {
+		add("", file.File, Skip{Message: file.Reason, Code: file.Code})
+	}
+	if r.FlattenResult != nil {
+		addAll(StepFlatten, r.FlattenResult.SkippedSchemas)
+	}
+	if r.VendorResult != nil {
+		addAll(StepVendorExtensions, r.VendorResult.SkippedOperations)
+	}
+	if r.DefaultsResult != nil {
+		addAll(StepDefaults, r.DefaultsResult.SkippedTargets)
+	}
+	if r.RenameResult != nil {
+		addAll(StepComponentRenames, r.RenameResult.SkippedRenames)
+	}
+	if r.ConsistencyResult != nil {
+		addAll(StepComponentConsistency, r.ConsistencyResult.SkippedComponents)
+	}
+
+	sorted := make([]SkipGroup, 0, len(groups))
+	for code, group := range groups {
+		group.Steps = sortedKeysOf(steps[code])
+		sort.Strings(group.Items)
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i].Items) != len(sorted[j].Items) {
+			return len(sorted[i].Items) > len(sorted[j].Items)
+		}
+		return sorted[i].Code < sorted[j].Code
+	})
+	return sorted
+}
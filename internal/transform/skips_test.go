@@ -0,0 +1,52 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSkipGroups(t *testing.T) {
+	results := &TransformationResults{
+		SkippedFiles: []SkippedFile{{File: "ci.yml", Reason: SkipReasonNotOpenAPI, Code: SkipNotOpenAPI}},
+		DefaultsResult: &DefaultsResult{SkippedTargets: map[string][]Skip{
+			"a.yaml": {
+				{Message: "GET /users parameter limit: default already exists", Code: SkipDefaultExists},
+				{Message: "GET /users parameter sort: no schema found", Code: SkipNoSchema},
+			},
+			"b.yaml": {{Message: "GET /orders parameter limit: default already exists", Code: SkipDefaultExists}},
+		}},
+		VendorResult: &VendorExtensionResult{SkippedOperations: map[string][]Skip{
+			"a.yaml": {{Message: "GET /health: no pagination detected for fern", Code: SkipNoPagination}},
+		}},
+	}
+
+	groups := results.SkipGroups()
+	var codes []SkipCode
+	for _, group := range groups {
+		codes = append(codes, group.Code)
+	}
+	if want := []SkipCode{SkipDefaultExists, SkipNoPagination, SkipNoSchema, SkipNotOpenAPI}; !reflect.DeepEqual(codes, want) {
+		t.Fatalf("expected groups %v, got %v", want, codes)
+	}
+
+	first := groups[0]
+	wantItems := []string{
+		"a.yaml: GET /users parameter limit: default already exists",
+		"b.yaml: GET /orders parameter limit: default already exists",
+	}
+	if !reflect.DeepEqual(first.Items, wantItems) || !reflect.DeepEqual(first.Steps, []string{StepDefaults}) {
+		t.Errorf("unexpected group %+v", first)
+	}
+	if files := groups[3]; len(files.Steps) != 0 || files.Items[0] != "ci.yml: "+SkipReasonNotOpenAPI {
+		t.Errorf("unexpected skipped files group %+v", files)
+	}
+}
+
+func TestSkipCodeDescription(t *testing.T) {
+	if got := SkipDefaultExists.Description(); got != "default already exists" {
+		t.Errorf("unexpected description %q", got)
+	}
+	if got := SkipCode("custom").Description(); got != "custom" {
+		t.Errorf("expected unknown codes to describe themselves, got %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package transform
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// sniffSize is how much of a file is read to decide whether the steps should parse it
+const sniffSize = 64 << 10
+
+// Reasons a file was skipped
+const (
+	SkipReasonIgnored    = "marked x-openmorph: ignore"
+	SkipReasonNotOpenAPI = "not an OpenAPI or AsyncAPI document"
+	skipReasonNone       = ""
+)
+
+var (
+	// documentKeyPattern matches a root-level openapi, swagger or asyncapi key in YAML, or the
+	// quoted key anywhere in JSON
+	documentKeyPattern = regexp.MustCompile(`(?m)(^["']?(openapi|swagger|asyncapi)["']?[ \t]*:|"(openapi|swagger|asyncapi)"\s*:)`)
+	// ignoreMarkerPattern matches `x-openmorph: ignore` as a root-level YAML key or a JSON member
+	ignoreMarkerPattern = regexp.MustCompile(`(?m)(^["']?x-openmorph["']?[ \t]*:[ \t]*["']?ignore["']?[ \t]*(#.*)?$|"x-openmorph"\s*:\s*"ignore")`)
+)
+
+// SkippedFile is a YAML/JSON file under the input that the OpenAPI steps did not parse
+type SkippedFile struct {
+	File   string
+	Reason string
+	Code   SkipCode // SkipIgnoredFile or SkipNotOpenAPI
+}
+
+// sniffFile reads the start of a file and returns why the OpenAPI steps should skip it, or an
+// empty reason when it looks like an OpenAPI, Swagger or AsyncAPI document. Root keys past the
+// first 64 KB are not seen.
+func sniffFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return skipReasonNone, err
+	}
+	defer f.Close()
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return skipReasonNone, err
+	}
+	head = head[:n]
+
+	switch {
+	case ignoreMarkerPattern.Match(head):
+		return SkipReasonIgnored, nil
+	case !documentKeyPattern.Match(head):
+		return SkipReasonNotOpenAPI, nil
+	default:
+		return skipReasonNone, nil
+	}
+}
+
+// fileSkipCode returns the code of a reason returned by sniffFile
+func fileSkipCode(reason string) SkipCode {
+	if reason == SkipReasonIgnored {
+		return SkipIgnoredFile
+	}
+	return SkipNotOpenAPI
+}
+
+// sniffAllows reports whether the step should parse the file. Key mappings also apply to
+// fragments without an openapi key, so they only honor the ignore marker. Files that cannot be
+// read are allowed so the step reports the error.
+func sniffAllows(step, path string) bool {
+	reason, err := sniffFile(path)
+	if err != nil {
+		return true
+	}
+	if step == StepMappings {
+		return reason != SkipReasonIgnored
+	}
+	return reason == skipReasonNone
+}
+
+// IsIgnoredFile reports whether the file carries the `x-openmorph: ignore` marker
+func IsIgnoredFile(path string) bool {
+	reason, err := sniffFile(path)
+	return err == nil && reason == SkipReasonIgnored
+}
+
+// collectSkippedFiles lists the YAML/JSON files under root, selected by the file patterns, that
+// the OpenAPI steps skip without parsing
+func collectSkippedFiles(root string, files config.FileFilter) ([]SkippedFile, error) {
+	var skipped []SkippedFile
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(IsYAML(path) || IsJSON(path)) || !IncludesFile(files, "", root, path) {
+			return nil
+		}
+		reason, err := sniffFile(path)
+		if err != nil {
+			return err
+		}
+		if reason != skipReasonNone {
+			skipped = append(skipped, SkippedFile{File: path, Reason: reason, Code: fileSkipCode(reason)})
+		}
+		return nil
+	})
+	return skipped, err
+}
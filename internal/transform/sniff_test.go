@@ -0,0 +1,99 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func writeSniffFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSniffFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"openapi.yaml", "openapi: 3.0.0\ninfo: {}\n", skipReasonNone},
+		{"swagger.json", `{"swagger": "2.0", "info": {}}`, skipReasonNone},
+		{"asyncapi.yaml", "---\nasyncapi: 3.0.0\n", skipReasonNone},
+		{"quoted.yaml", "\"openapi\": 3.1.0\n", skipReasonNone},
+		{"workflow.yml", "name: CI\non: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n", SkipReasonNotOpenAPI},
+		{"nested.yaml", "settings:\n  openapi: true\n", SkipReasonNotOpenAPI},
+		{"ignored.yaml", "openapi: 3.0.0\nx-openmorph: ignore # fixture\n", SkipReasonIgnored},
+		{"ignored.json", `{"openapi": "3.0.0", "x-openmorph": "ignore"}`, SkipReasonIgnored},
+	}
+	for _, tt := range tests {
+		path := writeSniffFile(t, dir, tt.name, tt.content)
+		got, err := sniffFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got reason %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSniffAllows(t *testing.T) {
+	dir := t.TempDir()
+	fragment := writeSniffFile(t, dir, "fragment.yaml", "type: object\nx-old: value\n")
+	ignored := writeSniffFile(t, dir, "ignored.yaml", "x-openmorph: ignore\nx-old: value\n")
+
+	if !sniffAllows(StepMappings, fragment) {
+		t.Error("expected mappings to process fragments without an openapi key")
+	}
+	if sniffAllows(StepVendorExtensions, fragment) {
+		t.Error("expected OpenAPI steps to skip fragments")
+	}
+	if sniffAllows(StepMappings, ignored) {
+		t.Error("expected mappings to skip ignored files")
+	}
+	if !sniffAllows(StepPagination, filepath.Join(dir, "missing.yaml")) {
+		t.Error("expected unreadable files to be passed on so the step reports the error")
+	}
+}
+
+func TestPipeline_SkippedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeSniffFile(t, dir, "api.yaml", "openapi: 3.0.0\ninfo:\n  title: Test\n  version: 1.0.0\nx-old: value\npaths: {}\n")
+	writeSniffFile(t, dir, "fixtures/fixture.yaml", "openapi: 3.0.0\nx-openmorph: ignore\nx-old: value\npaths: {}\n")
+	writeSniffFile(t, dir, ".github/workflows/ci.yml", "name: CI\nx-old: value\n")
+
+	cfg := &config.Config{Mappings: map[string]string{"x-old": "x-new"}}
+	results, err := NewTransformationPipeline(cfg, nil, false, false, "").ExecuteFullPipeline(dir)
+	if err != nil {
+		t.Fatalf("ExecuteFullPipeline failed: %v", err)
+	}
+
+	reasons := make(map[string]string)
+	for _, skipped := range results.SkippedFiles {
+		rel, _ := filepath.Rel(dir, skipped.File)
+		reasons[filepath.ToSlash(rel)] = skipped.Reason
+	}
+	if reasons["fixtures/fixture.yaml"] != SkipReasonIgnored || reasons[".github/workflows/ci.yml"] != SkipReasonNotOpenAPI || len(reasons) != 2 {
+		t.Errorf("unexpected skipped files %v", reasons)
+	}
+
+	fixture, err := os.ReadFile(filepath.Join(dir, "fixtures", "fixture.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(fixture), "x-old") {
+		t.Error("expected the ignored fixture to be left untouched")
+	}
+}
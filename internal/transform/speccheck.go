@@ -0,0 +1,284 @@
+package transform
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Rules of the checks openmorph validate runs
+const (
+	RuleParse           = "parse"
+	RuleStructure       = "structure"
+	RuleOperationID     = "operation-id"
+	RuleParameters      = "parameters"
+	RuleRefs            = "refs"
+	RuleExtensionSchema = "extension-schema"
+)
+
+// RuleDescriptions describes every rule of the spec validator
+var RuleDescriptions = map[string]string{
+	RuleParse:           "Document is valid YAML or JSON",
+	RuleStructure:       "Required OpenAPI fields are present and well-formed",
+	RuleOperationID:     "operationIds are unique",
+	RuleParameters:      "Parameters have a name and location, and path templates match the path parameters",
+	RuleRefs:            "Every $ref resolves",
+	RuleExtensionSchema: "Extension values match their extension_schemas",
+}
+
+// pathTemplatePattern matches the {name} segments of a path template
+var pathTemplatePattern = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// SpecIssue is a problem the spec validator found. Line and Column are 1-based; issues without a
+// known position use line 0.
+type SpecIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Rule    string `json:"rule"` // one of the Rule constants, or the name of an external validator
+	Message string `json:"message"`
+}
+
+// Position returns the issue location as file:line:column
+func (i SpecIssue) Position() string {
+	return ChangeLocation{File: i.File, Line: i.Line, Column: i.Column}.Position()
+}
+
+// SpecValidation is the outcome of validating the documents under an input
+type SpecValidation struct {
+	Files  []string    `json:"files"` // documents validated, in path order
+	Issues []SpecIssue `json:"issues"`
+}
+
+// ValidateSpecs checks every OpenAPI document under inputPath without modifying it: the document
+// parses, has the required fields, uses unique operationIds and well-formed parameters, every
+// $ref resolves, and extension values match the schemas given. Files that parse but are not
+// OpenAPI documents are left out.
+func ValidateSpecs(inputPath string, files config.FileFilter, schemas map[string]map[string]interface{}) (*SpecValidation, error) {
+	resolver := &refResolver{docs: make(map[string]*yaml.Node)}
+	validation := &SpecValidation{}
+	err := filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(IsYAML(path) || IsJSON(path)) || !IncludesFile(files, "", inputPath, path) {
+			return nil
+		}
+		if reason, err := sniffFile(path); err != nil || reason != skipReasonNone {
+			return nil
+		}
+
+		root, err := resolver.load(path)
+		if err != nil {
+			validation.Files = append(validation.Files, path)
+			validation.Issues = append(validation.Issues, SpecIssue{File: path, Rule: RuleParse, Message: err.Error()})
+			return nil
+		}
+		if !isOpenAPIDocument(root) {
+			return nil
+		}
+
+		validation.Files = append(validation.Files, path)
+		validation.Issues = append(validation.Issues, documentIssues(path, root)...)
+		walkRefs(root, func(ref *yaml.Node) {
+			if problem := resolver.check(path, ref.Value); problem != "" {
+				validation.Issues = append(validation.Issues, SpecIssue{File: path, Line: ref.Line, Column: ref.Column, Rule: RuleRefs, Message: problem})
+			}
+		})
+		for _, issue := range extensionIssues(path, root, schemas) {
+			validation.Issues = append(validation.Issues, SpecIssue{File: issue.File, Line: issue.Line, Column: issue.Column, Rule: RuleExtensionSchema, Message: issue.Message})
+		}
+		return nil
+	})
+	return validation, err
+}
+
+// specChecker collects the issues of one document
+type specChecker struct {
+	path   string
+	root   *yaml.Node
+	issues []SpecIssue
+}
+
+// add records an issue at node, or without a position when node is nil
+func (c *specChecker) add(node *yaml.Node, rule, format string, args ...interface{}) {
+	issue := SpecIssue{File: c.path, Rule: rule, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		issue.Line, issue.Column = node.Line, node.Column
+	}
+	c.issues = append(c.issues, issue)
+}
+
+// documentIssues checks the structure, operationIds and parameters of one document
+func documentIssues(path string, root *yaml.Node) []SpecIssue {
+	c := &specChecker{path: path, root: root}
+
+	version := getStringValue(root, "openapi")
+	swagger := getNodeValue(root, "swagger") != nil
+	switch {
+	case swagger && getStringValue(root, "swagger") != "2.0":
+		c.add(getNodeValue(root, "swagger"), RuleStructure, "swagger must be 2.0, got %q", getStringValue(root, "swagger"))
+	case !swagger && !strings.HasPrefix(version, "3."):
+		c.add(getNodeValue(root, "openapi"), RuleStructure, "openapi must be a 3.x version, got %q", version)
+	}
+
+	info := getNodeValue(root, "info")
+	if info == nil || info.Kind != yaml.MappingNode {
+		c.add(root, RuleStructure, "info is required")
+	} else {
+		for _, key := range []string{"title", "version"} {
+			if getStringValue(info, key) == "" {
+				c.add(info, RuleStructure, "info.%s is required", key)
+			}
+		}
+	}
+
+	// OpenAPI 3.1 documents may describe only webhooks or components
+	openapi31 := strings.HasPrefix(version, "3.1")
+	paths := getNodeValue(root, "paths")
+	switch {
+	case paths == nil && openapi31:
+		if getNodeValue(root, "webhooks") == nil && getNodeValue(root, "components") == nil {
+			c.add(root, RuleStructure, "paths, webhooks or components is required")
+		}
+	case paths == nil:
+		c.add(root, RuleStructure, "paths is required")
+	case paths.Kind != yaml.MappingNode:
+		c.add(paths, RuleStructure, "paths must be an object")
+	default:
+		c.checkPaths(paths, !openapi31)
+	}
+
+	if section, _ := parameterSection(root); section != nil && section.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(section.Content); i += 2 {
+			c.checkParameter(section.Content[i+1], "parameters."+section.Content[i].Value)
+		}
+	}
+	return c.issues
+}
+
+// checkPaths checks the path items and operations under paths
+func (c *specChecker) checkPaths(paths *yaml.Node, responsesRequired bool) {
+	operationIDs := make(map[string]string)
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		key, pathItem := paths.Content[i], paths.Content[i+1]
+		pathName := key.Value
+		if !strings.HasPrefix(pathName, "/") {
+			c.add(key, RuleStructure, "path %s must start with /", pathName)
+		}
+		if pathItem.Kind != yaml.MappingNode {
+			continue
+		}
+
+		pathParams := getNodeValue(pathItem, "parameters")
+		c.checkParameterList(pathParams, pathName)
+		templated := make(map[string]bool)
+		for _, match := range pathTemplatePattern.FindAllStringSubmatch(pathName, -1) {
+			templated[match[1]] = true
+		}
+		pathDeclared := c.declaredPathParameters(pathParams)
+		c.checkUntemplated(pathName, templated, pathDeclared)
+
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			methodKey, operation := pathItem.Content[j], pathItem.Content[j+1]
+			if !isHTTPMethod(methodKey.Value) || operation.Kind != yaml.MappingNode {
+				continue
+			}
+			label := fmt.Sprintf("%s %s", strings.ToUpper(methodKey.Value), pathName)
+
+			if id := getNodeValue(operation, "operationId"); id != nil && id.Value != "" {
+				if other, ok := operationIDs[id.Value]; ok {
+					c.add(id, RuleOperationID, "%s: operationId %q is also used by %s", label, id.Value, other)
+				} else {
+					operationIDs[id.Value] = label
+				}
+			}
+
+			responses := getNodeValue(operation, "responses")
+			switch {
+			case responses == nil && responsesRequired:
+				c.add(methodKey, RuleStructure, "%s: responses is required", label)
+			case responses != nil && (responses.Kind != yaml.MappingNode || len(responses.Content) == 0):
+				c.add(responses, RuleStructure, "%s: responses must declare at least one response", label)
+			}
+
+			params := getNodeValue(operation, "parameters")
+			c.checkParameterList(params, label)
+			declared := c.declaredPathParameters(params)
+			c.checkUntemplated(label, templated, declared)
+			for _, name := range sortedKeysOf(templated) {
+				if declared[name] == nil && pathDeclared[name] == nil {
+					c.add(methodKey, RuleParameters, "%s: path parameter {%s} is not declared", label, name)
+				}
+			}
+		}
+	}
+}
+
+// checkParameterList checks every parameter of a parameters list
+func (c *specChecker) checkParameterList(params *yaml.Node, label string) {
+	if params == nil {
+		return
+	}
+	if params.Kind != yaml.SequenceNode {
+		c.add(params, RuleParameters, "%s: parameters must be an array", label)
+		return
+	}
+	for i, param := range params.Content {
+		c.checkParameter(param, fmt.Sprintf("%s parameters[%d]", label, i))
+	}
+}
+
+// checkParameter checks that a parameter has a name and a known location, and that a path
+// parameter is required. References are checked where they point to.
+func (c *specChecker) checkParameter(param *yaml.Node, label string) {
+	if param.Kind != yaml.MappingNode || getNodeValue(param, "$ref") != nil {
+		return
+	}
+	if getStringValue(param, "name") == "" {
+		c.add(param, RuleParameters, "%s: name is required", label)
+	}
+	in := getStringValue(param, "in")
+	switch in {
+	case "query", "header", "path", "cookie":
+	case "body", "formData":
+		if getNodeValue(c.root, "swagger") == nil {
+			c.add(param, RuleParameters, "%s: in: %s is only valid in Swagger 2.0", label, in)
+		}
+	default:
+		c.add(param, RuleParameters, "%s: in must be query, header, path or cookie, got %q", label, in)
+	}
+	if in == "path" && getStringValue(param, "required") != "true" {
+		c.add(param, RuleParameters, "%s: path parameter %s must be required: true", label, getStringValue(param, "name"))
+	}
+}
+
+// declaredPathParameters returns the path parameters of a parameters list by name
+func (c *specChecker) declaredPathParameters(params *yaml.Node) map[string]*yaml.Node {
+	declared := make(map[string]*yaml.Node)
+	if params == nil || params.Kind != yaml.SequenceNode {
+		return declared
+	}
+	for _, param := range params.Content {
+		resolved := resolveLocalRef(param, c.root)
+		if getStringValue(resolved, "in") == "path" {
+			declared[getStringValue(resolved, "name")] = param
+		}
+	}
+	return declared
+}
+
+// checkUntemplated reports the declared path parameters the path has no {name} segment for
+func (c *specChecker) checkUntemplated(label string, templated map[string]bool, declared map[string]*yaml.Node) {
+	for _, name := range sortedKeysOf(declared) {
+		if !templated[name] {
+			c.add(declared[name], RuleParameters, "%s: path parameter %s is not in the path", label, name)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestValidateSpecs(t *testing.T) {
+	dir := t.TempDir()
+	spec := `openapi: 3.0.3
+info:
+  title: Test API
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+      parameters:
+        - name: userId
+          in: path
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+    delete:
+      operationId: getUser
+  /teams:
+    get:
+      x-owner: 42
+      parameters:
+        - $ref: "#/components/parameters/Limit"
+      responses:
+        "200":
+          description: OK
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: body
+`
+	files := map[string]string{
+		"api.yaml":      spec,
+		"broken.yaml":   "openapi: 3.0.3\ninfo: [\n",
+		"settings.yaml": "name: not a spec\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	schemas := map[string]map[string]interface{}{"x-owner": {"type": "string"}}
+	validation, err := ValidateSpecs(dir, config.FileFilter{}, schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(dir, "api.yaml"), filepath.Join(dir, "broken.yaml")}; !reflect.DeepEqual(validation.Files, want) {
+		t.Errorf("expected files %v, got %v", want, validation.Files)
+	}
+
+	var got []string
+	for _, issue := range validation.Issues {
+		if filepath.Base(issue.File) == "api.yaml" {
+			got = append(got, issue.Rule+": "+issue.Message)
+		} else if issue.Rule != RuleParse {
+			t.Errorf("expected a parse issue for %s, got %+v", issue.File, issue)
+		}
+	}
+	want := []string{
+		"structure: info.version is required",
+		"parameters: GET /users/{id} parameters[0]: path parameter userId must be required: true",
+		"parameters: GET /users/{id}: path parameter userId is not in the path",
+		"parameters: GET /users/{id}: path parameter {id} is not declared",
+		`operation-id: DELETE /users/{id}: operationId "getUser" is also used by GET /users/{id}`,
+		"structure: DELETE /users/{id}: responses is required",
+		"parameters: DELETE /users/{id}: path parameter {id} is not declared",
+		"parameters: parameters.Limit: in: body is only valid in Swagger 2.0",
+		"refs: unresolved $ref #/components/schemas/User",
+		"extension-schema: x-owner: expected string, got integer",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected issues:\n%v\ngot:\n%v", want, got)
+	}
+	if issue := validation.Issues[len(want)-1]; issue.Line != 24 || issue.Column != 16 {
+		t.Errorf("expected the extension schema issue at 24:16, got %+v", issue)
+	}
+}
@@ -0,0 +1,237 @@
+package transform
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/pagination"
+)
+
+// SpecStats is the inventory of one OpenAPI document, or the sum of several
+type SpecStats struct {
+	File                string         `json:"file,omitempty"`
+	Operations          int            `json:"operations"`
+	Schemas             int            `json:"schemas"`
+	Parameters          int            `json:"parameters"`              // parameter objects of path items, operations and components
+	Extensions          map[string]int `json:"extensions"`              // extension key -> occurrences
+	Pagination          map[string]int `json:"pagination"`              // strategy -> operations using it, "none" for unpaginated ones
+	MaxCompositionDepth int            `json:"max_composition_depth"`   // deepest nesting of oneOf/anyOf/allOf
+	Health              SpecHealth     `json:"health"`                  // totals are scored from the summed counts
+	Files               int            `json:"files,omitempty"`         // number of documents summed, totals only
+	SkippedFiles        []string       `json:"skipped_files,omitempty"` // YAML/JSON files that are not OpenAPI documents, totals only
+}
+
+// newSpecStats creates a SpecStats with initialized maps
+func newSpecStats(file string) *SpecStats {
+	return &SpecStats{
+		File:       file,
+		Extensions: make(map[string]int),
+		Pagination: make(map[string]int),
+		Health:     newSpecHealth(),
+	}
+}
+
+// add adds the counts of other to s
+func (s *SpecStats) add(other *SpecStats) {
+	s.Files++
+	s.Operations += other.Operations
+	s.Schemas += other.Schemas
+	s.Parameters += other.Parameters
+	for key, count := range other.Extensions {
+		s.Extensions[key] += count
+	}
+	for strategy, count := range other.Pagination {
+		s.Pagination[strategy] += count
+	}
+	if other.MaxCompositionDepth > s.MaxCompositionDepth {
+		s.MaxCompositionDepth = other.MaxCompositionDepth
+	}
+	s.Health.add(other.Health)
+	s.Health.score()
+}
+
+// CollectStats returns the inventory of every OpenAPI document under inputPath, in path order,
+// followed by their totals. It never modifies the files.
+func CollectStats(inputPath string, files config.FileFilter) ([]*SpecStats, *SpecStats, error) {
+	var stats []*SpecStats
+	total := newSpecStats("")
+	err := filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(IsYAML(path) || IsJSON(path)) || !IncludesFile(files, "", inputPath, path) {
+			return nil
+		}
+		if reason, err := sniffFile(path); err != nil || reason != skipReasonNone {
+			total.SkippedFiles = append(total.SkippedFiles, path)
+			return nil
+		}
+
+		doc, err := loadAndParseDocument(path)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", path, err)
+		}
+		root := getRootNode(doc)
+		if !isOpenAPIDocument(root) {
+			total.SkippedFiles = append(total.SkippedFiles, path)
+			return nil
+		}
+
+		fileStats := documentStats(path, root)
+		stats = append(stats, fileStats)
+		total.add(fileStats)
+		return nil
+	})
+	return stats, total, err
+}
+
+// documentStats counts the contents of one document
+func documentStats(path string, root *yaml.Node) *SpecStats {
+	stats := newSpecStats(path)
+	var operationStrategies []map[string]bool
+
+	if paths := getNodeValue(root, "paths"); paths != nil && paths.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			pathItem := paths.Content[i+1]
+			if pathItem.Kind != yaml.MappingNode {
+				continue
+			}
+			pathParams := getNodeValue(pathItem, "parameters")
+			stats.Parameters += sequenceLength(pathParams)
+			for j := 0; j+1 < len(pathItem.Content); j += 2 {
+				if !isHTTPMethod(pathItem.Content[j].Value) || pathItem.Content[j+1].Kind != yaml.MappingNode {
+					continue
+				}
+				operation := pathItem.Content[j+1]
+				stats.Operations++
+				params := getNodeValue(operation, "parameters")
+				stats.Parameters += sequenceLength(params)
+				strategies := paginationStrategies(operation, params, pathParams, root)
+				countPaginationStrategies(stats, strategies)
+				operationStrategies = append(operationStrategies, strategies)
+			}
+		}
+	}
+
+	if components := getNodeValue(root, "components"); components != nil {
+		stats.Schemas += mappingLength(getNodeValue(components, "schemas"))
+		stats.Parameters += mappingLength(getNodeValue(components, "parameters"))
+	}
+	// Swagger 2.0 keeps its components at the top level
+	if getStringValue(root, "swagger") != "" {
+		stats.Schemas += mappingLength(getNodeValue(root, "definitions"))
+		stats.Parameters += mappingLength(getNodeValue(root, "parameters"))
+	}
+
+	countExtensions(root, stats.Extensions)
+	stats.MaxCompositionDepth = compositionDepth(root)
+	stats.Health = documentHealth(root, operationStrategies)
+	return stats
+}
+
+// paginationStrategies returns the strategies an operation's parameters and response links use,
+// including the parameters inherited from its path item
+func paginationStrategies(operation, params, pathParams, root *yaml.Node) map[string]bool {
+	combined := &yaml.Node{Kind: yaml.SequenceNode}
+	if pathParams != nil && pathParams.Kind == yaml.SequenceNode {
+		combined.Content = append(combined.Content, pathParams.Content...)
+	}
+	if params != nil && params.Kind == yaml.SequenceNode {
+		combined.Content = append(combined.Content, params.Content...)
+	}
+
+	strategies := make(map[string]bool)
+	for _, detected := range pagination.DetectPaginationInParamsWithDoc(combined, root) {
+		strategies[detected.Strategy] = true
+	}
+	for _, detected := range pagination.DetectPaginationInLinks(getNodeValue(operation, "responses"), root) {
+		strategies[detected.Strategy] = true
+	}
+	return strategies
+}
+
+// countPaginationStrategies adds the strategies of an operation to the distribution
+func countPaginationStrategies(stats *SpecStats, strategies map[string]bool) {
+	if len(strategies) == 0 {
+		stats.Pagination["none"]++
+		return
+	}
+	for strategy := range strategies {
+		stats.Pagination[strategy]++
+	}
+}
+
+// countExtensions counts every x- key in the tree by name
+func countExtensions(node *yaml.Node, counts map[string]int) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			countExtensions(child, counts)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if strings.HasPrefix(node.Content[i].Value, "x-") {
+				counts[node.Content[i].Value]++
+			}
+			countExtensions(node.Content[i+1], counts)
+		}
+	}
+}
+
+// compositionDepth returns the deepest nesting of oneOf, anyOf and allOf in the tree
+func compositionDepth(node *yaml.Node) int {
+	depth := 0
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			depth = max(depth, compositionDepth(child))
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childDepth := compositionDepth(node.Content[i+1])
+			switch node.Content[i].Value {
+			case "oneOf", "anyOf", "allOf":
+				childDepth++
+			}
+			depth = max(depth, childDepth)
+		}
+	}
+	return depth
+}
+
+// sequenceLength returns the number of items of a sequence node, 0 for anything else
+func sequenceLength(node *yaml.Node) int {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return 0
+	}
+	return len(node.Content)
+}
+
+// mappingLength returns the number of keys of a mapping node, 0 for anything else
+func mappingLength(node *yaml.Node) int {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return 0
+	}
+	return len(node.Content) / 2
+}
+
+// SortedCounts returns the keys of counts ordered by descending count, then name
+func SortedCounts(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,85 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestCollectStats(t *testing.T) {
+	dir := t.TempDir()
+	spec := `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+  x-logo: logo.png
+paths:
+  /users:
+    parameters:
+      - name: tenant
+        in: header
+        schema:
+          type: string
+    get:
+      x-sdk-group: users
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+    post:
+      x-sdk-group: users
+      responses:
+        "201":
+          description: Created
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+  schemas:
+    Pet:
+      oneOf:
+        - $ref: "#/components/schemas/Cat"
+        - allOf:
+            - $ref: "#/components/schemas/Cat"
+    Cat:
+      type: object
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "settings.yaml"), []byte("name: not a spec\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	files, total, err := CollectStats(dir, config.FileFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected one document, got %d", len(files))
+	}
+
+	stats := files[0]
+	if stats.Operations != 2 || stats.Schemas != 2 || stats.Parameters != 3 || stats.MaxCompositionDepth != 2 {
+		t.Errorf("unexpected counts: %+v", stats)
+	}
+	if want := map[string]int{"x-logo": 1, "x-sdk-group": 2}; !reflect.DeepEqual(stats.Extensions, want) {
+		t.Errorf("expected extensions %v, got %v", want, stats.Extensions)
+	}
+	if want := map[string]int{"cursor": 1, "none": 1}; !reflect.DeepEqual(stats.Pagination, want) {
+		t.Errorf("expected pagination %v, got %v", want, stats.Pagination)
+	}
+	if total.Files != 1 || len(total.SkippedFiles) != 1 {
+		t.Errorf("expected one counted and one skipped file, got %+v", total)
+	}
+}
@@ -0,0 +1,271 @@
+package transform
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/pagination"
+)
+
+// StrictIssue is a problem found by strict mode. Line and Column are 1-based; issues without a
+// known position use line 0.
+type StrictIssue struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// Position returns the issue location as file:line:column
+func (i StrictIssue) Position() string {
+	return ChangeLocation{File: i.File, Line: i.Line, Column: i.Column}.Position()
+}
+
+// StrictError is returned by the pipeline in strict mode when the config or the input has problems
+// that would otherwise degrade silently
+type StrictError struct {
+	Issues []StrictIssue
+}
+
+func (e *StrictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "strict mode found %d problem(s):", len(e.Issues))
+	for _, issue := range e.Issues {
+		fmt.Fprintf(&b, "\n  %s: %s", issue.Position(), issue.Message)
+	}
+	return b.String()
+}
+
+// CheckStrict returns a *StrictError listing unknown pagination strategies, vendor strategies
+// without a template, unresolved $refs under inputPath and the $refs the pagination step would
+// follow but cannot resolve, or nil when strict mode is off or nothing was found
+func (tp *TransformationPipeline) CheckStrict(inputPath string) error {
+	if !tp.Strict {
+		return nil
+	}
+
+	issues := CheckStrictConfig(tp.Config)
+	refIssues, err := checkRefs(inputPath, tp.Config.Files, tp.KeepGoing)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, refIssues...)
+	paginationIssues, err := checkUnresolvedRefs(inputPath, tp.Config, refIssues)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, paginationIssues...)
+	if len(issues) == 0 {
+		return nil
+	}
+	return &StrictError{Issues: issues}
+}
+
+// CheckStrictConfig reports pagination strategies in pagination_priority and endpoint_pagination
+// that do not exist, and vendor extension strategies without a template. Issues point into the
+// config file the settings were loaded from.
+func CheckStrictConfig(cfg *config.Config) []StrictIssue {
+	var issues []StrictIssue
+	issue := func(message string, keys ...string) {
+		i := StrictIssue{File: cfg.Source, Message: message}
+		if cfg.Source == "" {
+			i.File = "config"
+		} else {
+			i.Line, i.Column = config.Locate(cfg.Source, keys...)
+		}
+		issues = append(issues, i)
+	}
+
+	for i, strategy := range cfg.PaginationPriority {
+		if _, ok := pagination.PaginationStrategies[strategy]; !ok {
+			issue(fmt.Sprintf("pagination_priority: unknown pagination strategy %q (supported: %s)", strategy, knownStrategies()),
+				"pagination_priority", strconv.Itoa(i))
+		}
+	}
+	for i, rule := range cfg.EndpointPagination {
+		if _, ok := pagination.PaginationStrategies[rule.Pagination]; !ok {
+			issue(fmt.Sprintf("endpoint_pagination[%d]: unknown pagination strategy %q for %s %s (supported: %s)",
+				i, rule.Pagination, rule.Method, rule.Endpoint, knownStrategies()),
+				"endpoint_pagination", strconv.Itoa(i), "pagination")
+		}
+	}
+
+	if cfg.VendorExtensions.Enabled {
+		for _, provider := range configuredProviderNames(cfg) {
+			strategies := cfg.VendorExtensions.Providers[provider].Strategies
+			for _, strategy := range sortedStrategyNames(strategies) {
+				if len(strategies[strategy].Template) == 0 {
+					issue(fmt.Sprintf("vendor_extensions.providers.%s.strategies.%s: strategy has no template", provider, strategy),
+						"vendor_extensions", "providers", provider, "strategies", strategy)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// CheckRefs reports every $ref in the OpenAPI and AsyncAPI documents under inputPath whose target
+// does not exist. Remote (URL) references are not checked.
+func CheckRefs(inputPath string, files config.FileFilter) ([]StrictIssue, error) {
+	return checkRefs(inputPath, files, false)
+}
+
+// checkRefs is CheckRefs; with skipBroken, documents that fail to parse are left out instead of
+// failing the check, for keep-going runs that report them as file errors
+func checkRefs(inputPath string, files config.FileFilter, skipBroken bool) ([]StrictIssue, error) {
+	resolver := &refResolver{docs: make(map[string]*yaml.Node)}
+
+	var issues []StrictIssue
+	err := filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(IsYAML(path) || IsJSON(path)) || !IncludesFile(files, "", inputPath, path) {
+			return nil
+		}
+		if reason, err := sniffFile(path); err != nil || reason != skipReasonNone {
+			return nil
+		}
+
+		root, err := resolver.load(path)
+		if err != nil {
+			if skipBroken {
+				return nil
+			}
+			return fmt.Errorf("error parsing %s: %w", path, err)
+		}
+		walkRefs(root, func(ref *yaml.Node) {
+			if problem := resolver.check(path, ref.Value); problem != "" {
+				issues = append(issues, StrictIssue{File: path, Line: ref.Line, Column: ref.Column, Message: problem})
+			}
+		})
+		return nil
+	})
+	return issues, err
+}
+
+// walkRefs calls visit with the value node of every $ref in the tree
+func walkRefs(node *yaml.Node, visit func(*yaml.Node)) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "$ref" && node.Content[i+1].Kind == yaml.ScalarNode {
+				visit(node.Content[i+1])
+				continue
+			}
+			walkRefs(node.Content[i+1], visit)
+		}
+		return
+	}
+	for _, item := range node.Content {
+		walkRefs(item, visit)
+	}
+}
+
+// refResolver resolves references against parsed documents, parsing each file once
+type refResolver struct {
+	docs map[string]*yaml.Node
+}
+
+// load parses the file at path and returns its root node
+func (r *refResolver) load(path string) (*yaml.Node, error) {
+	if root, ok := r.docs[path]; ok {
+		return root, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	root := getRootNode(&doc)
+	r.docs[path] = root
+	return root, nil
+}
+
+// check returns why ref, found in the file at from, cannot be resolved, or "" when it resolves
+func (r *refResolver) check(from, ref string) string {
+	if strings.Contains(ref, "://") {
+		return ""
+	}
+
+	target, pointer, _ := strings.Cut(ref, "#")
+	file := from
+	if target != "" {
+		file = filepath.Join(filepath.Dir(from), filepath.FromSlash(target))
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Sprintf("unresolved $ref %s: file %s not found", ref, file)
+		}
+	}
+	if pointer == "" || pointer == "/" {
+		return ""
+	}
+
+	root, err := r.load(file)
+	if err != nil {
+		return fmt.Sprintf("unresolved $ref %s: %v", ref, err)
+	}
+	if resolvePointer(root, pointer) == nil {
+		return fmt.Sprintf("unresolved $ref %s", ref)
+	}
+	return ""
+}
+
+// resolvePointer resolves a JSON pointer such as /components/schemas/User against root
+func resolvePointer(root *yaml.Node, pointer string) *yaml.Node {
+	if unescaped, err := url.PathUnescape(pointer); err == nil {
+		pointer = unescaped
+	}
+	node := root
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node.Kind {
+		case yaml.MappingNode:
+			node = getNodeValue(node, token)
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[index]
+		default:
+			return nil
+		}
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// knownStrategies lists the supported pagination strategy names
+func knownStrategies() string {
+	names := make([]string, 0, len(pagination.PaginationStrategies))
+	for name := range pagination.PaginationStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func sortedStrategyNames(strategies map[string]config.StrategyConfig) []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,129 @@
+package transform
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestCheckStrictConfig(t *testing.T) {
+	dir := t.TempDir()
+	source := writeSniffFile(t, dir, ".openapirc.yaml", `pagination_priority:
+  - cursor
+  - pages
+endpoint_pagination:
+  - endpoint: /users
+    method: GET
+    pagination: token
+vendor_extensions:
+  enabled: true
+  providers:
+    fern:
+      strategies:
+        cursor: {}
+`)
+	cfg := &config.Config{
+		Source:             source,
+		PaginationPriority: []string{"cursor", "pages"},
+		EndpointPagination: []config.EndpointPaginationRule{{Endpoint: "/users", Method: "GET", Pagination: "token"}},
+		VendorExtensions: config.VendorExtensions{
+			Enabled: true,
+			Providers: map[string]config.ProviderConfig{
+				"fern": {Strategies: map[string]config.StrategyConfig{"cursor": {}}},
+			},
+		},
+	}
+
+	issues := CheckStrictConfig(cfg)
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %+v", issues)
+	}
+	wantLines := []int{3, 7, 13}
+	wantMessages := []string{`unknown pagination strategy "pages"`, `unknown pagination strategy "token"`, "strategy has no template"}
+	for i, issue := range issues {
+		if issue.File != source || issue.Line != wantLines[i] {
+			t.Errorf("issue %d: expected %s line %d, got %s", i, source, wantLines[i], issue.Position())
+		}
+		if !strings.Contains(issue.Message, wantMessages[i]) {
+			t.Errorf("issue %d: expected message containing %q, got %q", i, wantMessages[i], issue.Message)
+		}
+	}
+}
+
+func TestCheckRefs(t *testing.T) {
+	dir := t.TempDir()
+	writeSniffFile(t, dir, "schemas/user.yaml", "User:\n  type: object\n")
+	spec := writeSniffFile(t, dir, "api.yaml", `openapi: 3.0.0
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Users'
+        "404":
+          $ref: '#/components/responses/Missing'
+components:
+  schemas:
+    Users:
+      type: array
+      items:
+        $ref: './schemas/user.yaml#/User'
+    Admin:
+      $ref: './schemas/user.yaml#/Admin'
+    Team:
+      $ref: './schemas/team.yaml'
+    Remote:
+      $ref: 'https://example.com/schemas.yaml#/Remote'
+`)
+
+	issues, err := CheckRefs(dir, config.FileFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 unresolved refs, got %+v", issues)
+	}
+	wantLines := []int{12, 20, 22}
+	for i, issue := range issues {
+		if issue.File != spec || issue.Line != wantLines[i] {
+			t.Errorf("issue %d: expected %s line %d, got %s (%s)", i, spec, wantLines[i], issue.Position(), issue.Message)
+		}
+	}
+	if !strings.Contains(issues[2].Message, "not found") {
+		t.Errorf("expected missing file message, got %q", issues[2].Message)
+	}
+}
+
+func TestPipelineStrictFailsBeforeWriting(t *testing.T) {
+	dir := t.TempDir()
+	content := "openapi: 3.0.0\nx-old: value\ncomponents:\n  schemas:\n    User:\n      $ref: '#/components/schemas/Missing'\n"
+	spec := writeSniffFile(t, dir, "api.yaml", content)
+
+	cfg := &config.Config{Mappings: map[string]string{"x-old": "x-new"}}
+	pipeline := NewTransformationPipeline(cfg, nil, false, false, "")
+	pipeline.Strict = true
+
+	_, err := pipeline.ExecuteFullPipeline(dir)
+	var strictErr *StrictError
+	if !errors.As(err, &strictErr) {
+		t.Fatalf("expected StrictError, got %v", err)
+	}
+	if len(strictErr.Issues) != 1 || strictErr.Issues[0].Position() != filepath.Clean(spec)+":6:13" {
+		t.Errorf("unexpected issues: %+v", strictErr.Issues)
+	}
+	if got, _ := os.ReadFile(spec); string(got) != content {
+		t.Errorf("expected the file to be left untouched, got:\n%s", got)
+	}
+
+	pipeline.Strict = false
+	if _, err := pipeline.ExecuteFullPipeline(dir); err != nil {
+		t.Fatalf("expected the pipeline to run without strict mode, got %v", err)
+	}
+}
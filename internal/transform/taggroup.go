@@ -0,0 +1,208 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Vendor extension provider modes
+const (
+	ProviderModePagination = "pagination" // add pagination metadata from the provider's strategies
+	ProviderModeTagGroup   = "tag_group"  // set an SDK grouping extension from each operation's first tag
+)
+
+// Tag group casings
+const (
+	TagCasingSnake  = CasingSnake
+	TagCasingKebab  = CasingKebab
+	TagCasingCamel  = CasingCamel
+	TagCasingPascal = CasingPascal
+)
+
+// ValidateProviderModes checks the mode of every vendor extension provider and the settings of the
+// tag_group providers
+func ValidateProviderModes(vendorExtensions config.VendorExtensions) error {
+	names := make([]string, 0, len(vendorExtensions.Providers))
+	for name := range vendorExtensions.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		provider := vendorExtensions.Providers[name]
+		switch provider.Mode {
+		case "", ProviderModePagination:
+			continue
+		case ProviderModeTagGroup:
+		default:
+			return fmt.Errorf("vendor_extensions.providers.%s.mode must be %s or %s, got %q",
+				name, ProviderModePagination, ProviderModeTagGroup, provider.Mode)
+		}
+
+		if provider.ExtensionName == "" {
+			return fmt.Errorf("vendor_extensions.providers.%s: extension_name is required", name)
+		}
+		switch provider.TargetLevel {
+		case "", "operation", "path":
+		default:
+			return fmt.Errorf("vendor_extensions.providers.%s.target_level must be operation or path for the %s mode, got %q",
+				name, ProviderModeTagGroup, provider.TargetLevel)
+		}
+		switch provider.TagGroup.Casing {
+		case "", TagCasingSnake, TagCasingKebab, TagCasingCamel, TagCasingPascal:
+		default:
+			return fmt.Errorf("vendor_extensions.providers.%s.tag_group.casing must be %s, %s, %s or %s, got %q",
+				name, TagCasingSnake, TagCasingKebab, TagCasingCamel, TagCasingPascal, provider.TagGroup.Casing)
+		}
+	}
+	return nil
+}
+
+// isTagGroupProvider reports whether a provider runs in the tag_group mode
+func isTagGroupProvider(provider config.ProviderConfig) bool {
+	return provider.Mode == ProviderModeTagGroup
+}
+
+// processTagGroupsInPaths sets the grouping extension of every tag_group provider on the matching
+// operations, or on their path items, and updates values that no longer match the tags
+func processTagGroupsInPaths(root *yaml.Node, opts VendorExtensionOptions, filePath string, result *VendorExtensionResult) bool {
+	paths := getNodeValue(root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return false
+	}
+
+	names := make([]string, 0, len(opts.VendorExtensions.Providers))
+	for name, provider := range opts.VendorExtensions.Providers {
+		if isTagGroupProvider(provider) && (len(opts.EnabledProviders) == 0 || contains(opts.EnabledProviders, name)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changed := false
+	for _, name := range names {
+		provider := opts.VendorExtensions.Providers[name]
+		if _, ok := result.OperationsTouched[name]; !ok {
+			result.OperationsTouched[name] = 0 // report providers that touched nothing too
+		}
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+			if pathItem.Kind != yaml.MappingNode {
+				continue
+			}
+			var touched int
+			if provider.TargetLevel == "path" {
+				touched = applyPathTagGroup(pathItem, paths.Content[i], pathName, name, provider, opts.Placement, filePath, result)
+			} else {
+				touched = applyOperationTagGroups(pathItem, pathName, name, provider, opts.Placement, filePath, result)
+			}
+			if touched > 0 {
+				changed = true
+				result.OperationsTouched[name] += touched
+			}
+		}
+	}
+	return changed
+}
+
+// applyOperationTagGroups sets the grouping extension on each matching operation of a path item
+// and returns how many operations changed
+func applyOperationTagGroups(pathItem *yaml.Node, pathName, name string, provider config.ProviderConfig, placement, filePath string, result *VendorExtensionResult) int {
+	touched := 0
+	for j := 0; j+1 < len(pathItem.Content); j += 2 {
+		method, operation := pathItem.Content[j].Value, pathItem.Content[j+1]
+		if !isHTTPMethod(method) || operation.Kind != yaml.MappingNode {
+			continue
+		}
+		operationKey := fmt.Sprintf("%s %s", strings.ToUpper(method), pathName)
+		if !operationMatchesProvider(method, pathName, operation, provider) {
+			addSkippedOperation(result, filePath, operationKey, SkipProviderCriteria, fmt.Sprintf("doesn't match %s provider criteria", name))
+			continue
+		}
+		tag := firstTag(operation)
+		if tag == "" {
+			addSkippedOperation(result, filePath, operationKey, SkipNoTags, fmt.Sprintf("no tags for %s", name))
+			continue
+		}
+		if setTagGroup(operation, pathItem.Content[j], operationKey, tag, provider, placement, filePath, result) {
+			touched++
+		}
+	}
+	return touched
+}
+
+// applyPathTagGroup sets the grouping extension on a path item when the first tags of its
+// matching operations agree, and returns 1 when the path item changed
+func applyPathTagGroup(pathItem, keyNode *yaml.Node, pathName, name string, provider config.ProviderConfig, placement, filePath string, result *VendorExtensionResult) int {
+	var tags []string
+	for j := 0; j+1 < len(pathItem.Content); j += 2 {
+		method, operation := pathItem.Content[j].Value, pathItem.Content[j+1]
+		if !isHTTPMethod(method) || !operationMatchesProvider(method, pathName, operation, provider) {
+			continue
+		}
+		if tag := firstTag(operation); tag != "" && !contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	switch len(tags) {
+	case 0:
+		addSkippedOperation(result, filePath, pathName, SkipNoTags, fmt.Sprintf("no tagged operations for %s", name))
+		return 0
+	case 1:
+		if !setTagGroup(pathItem, keyNode, pathName, tags[0], provider, placement, filePath, result) {
+			return 0
+		}
+		return 1
+	default:
+		addSkippedOperation(result, filePath, pathName, SkipMixedTags, fmt.Sprintf("operations have different first tags (%s) for %s", strings.Join(tags, ", "), name))
+		return 0
+	}
+}
+
+// setTagGroup sets the provider's extension on node to the group name of tag, inserting it at
+// placement or replacing a value that no longer matches, and records the change
+func setTagGroup(node, keyNode *yaml.Node, target, tag string, provider config.ProviderConfig, placement, filePath string, result *VendorExtensionResult) bool {
+	group := tagGroupName(tag, provider.TagGroup)
+	extension := fmt.Sprintf("%s: %s = %s (from tag %s)", target, provider.ExtensionName, group, tag)
+
+	existing := getNodeValue(node, provider.ExtensionName)
+	switch {
+	case existing == nil:
+		insertExtension(node, provider.ExtensionName, newScalarNode(group), placement)
+	case existing.Kind == yaml.ScalarNode && existing.Value == group:
+		return false
+	default:
+		if existing.Kind == yaml.ScalarNode {
+			extension += fmt.Sprintf(", was %s", existing.Value)
+		}
+		setMappingValue(node, provider.ExtensionName, newScalarNode(group))
+	}
+
+	addProcessedExtension(result, filePath, extension)
+	result.Locations = append(result.Locations, newChangeLocation(filePath, StepVendorExtensions, keyNode, extension))
+	return true
+}
+
+// firstTag returns the first tag of an operation, or an empty string
+func firstTag(operation *yaml.Node) string {
+	tags := getNodeValue(operation, "tags")
+	if tags == nil || tags.Kind != yaml.SequenceNode || len(tags.Content) == 0 {
+		return ""
+	}
+	return tags.Content[0].Value
+}
+
+// tagGroupName returns the group name of a tag: its override, or the tag in the configured casing
+func tagGroupName(tag string, settings config.TagGroup) string {
+	if group, ok := settings.Overrides[tag]; ok {
+		return group
+	}
+
+	return applyCasing(tag, settings.Casing)
+}
@@ -0,0 +1,171 @@
+package transform
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const tagGroupTestSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      tags: [UserAccounts, Admin]
+      responses:
+        "200":
+          description: OK
+    post:
+      tags: [UserAccounts]
+      x-fern-sdk-group-name: users
+      responses:
+        "201":
+          description: Created
+  /invoices:
+    get:
+      tags: ["Billing & Invoices"]
+      responses:
+        "200":
+          description: OK
+    delete:
+      tags: [Admin]
+      responses:
+        "204":
+          description: Deleted
+  /health:
+    get:
+      responses:
+        "200":
+          description: OK
+`
+
+func runTagGroups(t *testing.T, provider config.ProviderConfig) (*VendorExtensionResult, string, string) {
+	t.Helper()
+	dir, path := writeTestSpec(t, tagGroupTestSpec)
+	provider.Mode = ProviderModeTagGroup
+	provider.ExtensionName = "x-fern-sdk-group-name"
+	result, err := ProcessVendorExtensionsInDir(dir, VendorExtensionOptions{
+		VendorExtensions: config.VendorExtensions{
+			Enabled:   true,
+			Providers: map[string]config.ProviderConfig{"fern-groups": provider},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	return result, path, string(data)
+}
+
+// groupAt returns the x-fern-sdk-group-name under the given keys of a written document
+func groupAt(t *testing.T, content string, keys ...string) interface{} {
+	t.Helper()
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		t.Fatal(err)
+	}
+	node := doc["paths"]
+	for _, key := range keys {
+		node = node.(map[string]interface{})[key]
+	}
+	return node.(map[string]interface{})["x-fern-sdk-group-name"]
+}
+
+func TestTagGroupsAtOperationLevel(t *testing.T) {
+	result, path, content := runTagGroups(t, config.ProviderConfig{
+		TagGroup: config.TagGroup{Casing: TagCasingSnake, Overrides: map[string]string{"Admin": "administration"}},
+	})
+
+	for _, tt := range []struct {
+		path, method, want string
+	}{
+		{"/users", "get", "user_accounts"},
+		{"/users", "post", "user_accounts"},
+		{"/invoices", "get", "billing_invoices"},
+		{"/invoices", "delete", "administration"},
+	} {
+		if got := groupAt(t, content, tt.path, tt.method); got != tt.want {
+			t.Errorf("%s %s: expected group %q, got %v", tt.method, tt.path, tt.want, got)
+		}
+	}
+	if strings.Count(content, "x-fern-sdk-group-name") != 4 {
+		t.Errorf("expected the untagged operation to be left alone, got:\n%s", content)
+	}
+
+	added := strings.Join(result.AddedExtensions[path], "\n")
+	if !strings.Contains(added, "POST /users: x-fern-sdk-group-name = user_accounts (from tag UserAccounts), was users") {
+		t.Errorf("expected the stale group to be reported as updated, got:\n%s", added)
+	}
+	if result.OperationsTouched["fern-groups"] != 4 {
+		t.Errorf("expected 4 operations touched, got %v", result.OperationsTouched)
+	}
+	if skipped := strings.Join(SkipMessages(result.SkippedOperations[path]), "\n"); !strings.Contains(skipped, "GET /health: no tags for fern-groups") {
+		t.Errorf("expected the untagged operation to be skipped, got:\n%s", skipped)
+	}
+}
+
+func TestTagGroupsAtPathLevel(t *testing.T) {
+	result, path, content := runTagGroups(t, config.ProviderConfig{
+		TargetLevel: "path",
+		Methods:     []string{"get"},
+		TagGroup:    config.TagGroup{Casing: TagCasingPascal},
+	})
+
+	if got := groupAt(t, content, "/users"); got != "UserAccounts" {
+		t.Errorf("expected /users to be grouped at path level, got %v", got)
+	}
+	if got := groupAt(t, content, "/invoices"); got != "BillingInvoices" {
+		t.Errorf("expected /invoices to be grouped from its GET operation only, got %v", got)
+	}
+	if got := groupAt(t, content, "/users", "post"); got != "users" {
+		t.Errorf("expected operation-level values to be left alone, got %v", got)
+	}
+	if skipped := strings.Join(SkipMessages(result.SkippedOperations[path]), "\n"); !strings.Contains(skipped, "/health: no tagged operations for fern-groups") {
+		t.Errorf("expected /health to be skipped, got:\n%s", skipped)
+	}
+}
+
+func TestTagGroupName(t *testing.T) {
+	tests := []struct {
+		tag, casing, want string
+	}{
+		{"Billing & Invoices", TagCasingSnake, "billing_invoices"},
+		{"billing_invoices", TagCasingKebab, "billing-invoices"},
+		{"UserAccounts", TagCasingCamel, "userAccounts"},
+		{"user accounts v2", TagCasingPascal, "UserAccountsV2"},
+		{"Users", "", "Users"},
+		{"&", TagCasingSnake, "&"},
+	}
+	for _, tt := range tests {
+		if got := tagGroupName(tt.tag, config.TagGroup{Casing: tt.casing}); got != tt.want {
+			t.Errorf("tagGroupName(%q, %q) = %q, want %q", tt.tag, tt.casing, got, tt.want)
+		}
+	}
+}
+
+func TestValidateProviderModes(t *testing.T) {
+	valid := config.VendorExtensions{Providers: map[string]config.ProviderConfig{
+		"fern":   {ExtensionName: "x-fern-pagination"},
+		"groups": {Mode: ProviderModeTagGroup, ExtensionName: "x-speakeasy-group", TargetLevel: "path"},
+	}}
+	if err := ValidateProviderModes(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, provider := range []config.ProviderConfig{
+		{Mode: "tags", ExtensionName: "x-group"},
+		{Mode: ProviderModeTagGroup},
+		{Mode: ProviderModeTagGroup, ExtensionName: "x-group", TargetLevel: "schema"},
+		{Mode: ProviderModeTagGroup, ExtensionName: "x-group", TagGroup: config.TagGroup{Casing: "upper"}},
+	} {
+		invalid := config.VendorExtensions{Providers: map[string]config.ProviderConfig{"groups": provider}}
+		if err := ValidateProviderModes(invalid); err == nil {
+			t.Errorf("expected an error for %+v", provider)
+		}
+	}
+}
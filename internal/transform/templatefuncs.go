@@ -0,0 +1,121 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// templateVariable matches a {variable} of a vendor extension template, with optional filters as in
+// {results_field|singular|pascal}
+var templateVariable = regexp.MustCompile(`\{(\w+)((?:\|\s*\w+\s*)*)\}`)
+
+// templateFilters transform the value of a template variable, applied left to right
+var templateFilters = map[string]func(string) string{
+	"camel":    func(s string) string { return applyCasing(s, CasingCamel) },
+	"pascal":   func(s string) string { return applyCasing(s, CasingPascal) },
+	"snake":    func(s string) string { return applyCasing(s, CasingSnake) },
+	"kebab":    func(s string) string { return applyCasing(s, CasingKebab) },
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"plural":   pluralWord,
+	"singular": singularWord,
+	"last":     lastSegment,
+	"pointer":  jsonPointer,
+}
+
+// applyTemplateFilters applies the filters of a placeholder, written as "|camel|plural", to value
+func applyTemplateFilters(value, filters string) string {
+	for _, name := range splitFilters(filters) {
+		if filter, ok := templateFilters[name]; ok {
+			value = filter(value)
+		}
+	}
+	return value
+}
+
+// splitFilters returns the filter names of a placeholder, written as "|camel|plural"
+func splitFilters(filters string) []string {
+	var names []string
+	for _, name := range strings.Split(filters, "|")[1:] {
+		names = append(names, strings.TrimSpace(name))
+	}
+	return names
+}
+
+// ValidateTemplateFilters checks that the templates of the vendor extension strategies only use
+// known filters
+func ValidateTemplateFilters(vendorExtensions config.VendorExtensions) error {
+	for _, name := range sortedKeysOf(vendorExtensions.Providers) {
+		strategies := vendorExtensions.Providers[name].Strategies
+		for _, strategy := range sortedKeysOf(strategies) {
+			template := strategies[strategy].Template
+			for _, key := range sortedKeysOf(template) {
+				value, ok := template[key].(string)
+				if !ok {
+					continue
+				}
+				for _, match := range templateVariable.FindAllStringSubmatch(value, -1) {
+					for _, filter := range splitFilters(match[2]) {
+						if _, known := templateFilters[filter]; !known {
+							return fmt.Errorf("vendor_extensions.providers.%s.strategies.%s.template.%s: unknown filter %q in %s (expected one of %s)",
+								name, strategy, key, filter, match[0], strings.Join(sortedKeysOf(templateFilters), ", "))
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pluralWord returns the English plural of a word: item -> items, entry -> entries, box -> boxes
+func pluralWord(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case word == "":
+		return word
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return word[:len(word)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return word + "es"
+	default:
+		return word + "s"
+	}
+}
+
+// singularWord returns the English singular of a word: items -> item, entries -> entry, boxes -> box
+func singularWord(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(lower) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
+// lastSegment returns the last segment of a dotted or slashed path: data.items -> items
+func lastSegment(path string) string {
+	return path[strings.LastIndexAny(path, "./")+1:]
+}
+
+// jsonPointer turns a dotted path into a JSON pointer: data.items -> /data/items
+func jsonPointer(path string) string {
+	if strings.HasPrefix(path, "/") {
+		return path
+	}
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1")
+	}
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,55 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestTemplateFilters(t *testing.T) {
+	tests := []struct {
+		value, filters, want string
+	}{
+		{"next_cursor", "|camel", "nextCursor"},
+		{"next_cursor", "|pascal", "NextCursor"},
+		{"nextCursor", "|snake", "next_cursor"},
+		{"nextCursor", "|kebab", "next-cursor"},
+		{"cursor", "| upper ", "CURSOR"},
+		{"item", "|plural", "items"},
+		{"entry", "|plural", "entries"},
+		{"box", "|plural", "boxes"},
+		{"day", "|plural", "days"},
+		{"entries", "|singular", "entry"},
+		{"matches", "|singular", "match"},
+		{"address", "|singular", "address"},
+		{"data.items", "|last", "items"},
+		{"data.items", "|pointer", "/data/items"},
+		{"data.items", "|last|singular|pascal", "Item"},
+	}
+	for _, tt := range tests {
+		if got := applyTemplateFilters(tt.value, tt.filters); got != tt.want {
+			t.Errorf("%s%s: expected %q, got %q", tt.value, tt.filters, tt.want, got)
+		}
+	}
+}
+
+func TestValidateTemplateFilters(t *testing.T) {
+	vendorExtensions := config.VendorExtensions{Providers: map[string]config.ProviderConfig{
+		"fern": {Strategies: map[string]config.StrategyConfig{
+			"cursor": {Template: map[string]interface{}{
+				"cursor": "$request.{cursor_param|camel}",
+				"limit":  10,
+			}},
+		}},
+	}}
+	if err := ValidateTemplateFilters(vendorExtensions); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	vendorExtensions.Providers["fern"].Strategies["cursor"].Template["results"] = "$response.{results_field|titlecase}"
+	err := ValidateTemplateFilters(vendorExtensions)
+	if err == nil || !strings.Contains(err.Error(), `strategies.cursor.template.results: unknown filter "titlecase"`) {
+		t.Errorf("expected an unknown filter error, got %v", err)
+	}
+}
@@ -0,0 +1,380 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// Tenant path modes
+const (
+	TenantPathsInject = "inject"
+	TenantPathsStrip  = "strip"
+)
+
+// Defaults of the tenant path parameter
+const (
+	defaultTenantParameter = "tenantId"
+	defaultTenantComponent = "TenantId"
+)
+
+// TenantPathsOptions extends the regular Options with tenant path settings
+type TenantPathsOptions struct {
+	Options
+	TenantPaths config.TenantPaths
+}
+
+// TenantPathsResult represents the result of adding or removing the tenant path segment
+type TenantPathsResult struct {
+	Changed           bool
+	ProcessedFiles    []string
+	RewrittenPaths    map[string][]string // file -> list of paths and what they were rewritten to
+	ChangedComponents map[string][]string // file -> list of parameter components created or removed
+	Locations         []ChangeLocation    // source positions of the rewritten paths
+}
+
+// createTenantPathsResult creates a new TenantPathsResult with initialized maps
+func createTenantPathsResult() *TenantPathsResult {
+	return &TenantPathsResult{
+		ProcessedFiles:    []string{},
+		RewrittenPaths:    make(map[string][]string),
+		ChangedComponents: make(map[string][]string),
+	}
+}
+
+// setTenantPathsProcessedFiles sets the processed files for a TenantPathsResult
+func setTenantPathsProcessedFiles(result *TenantPathsResult, files []string) {
+	result.ProcessedFiles = files
+}
+
+// setTenantPathsChanged sets the changed flag for a TenantPathsResult
+func setTenantPathsChanged(result *TenantPathsResult, changed bool) {
+	result.Changed = changed
+}
+
+// ProcessTenantPathsInDir adds the tenant path segment to every path, or removes it, in all
+// OpenAPI files in a directory
+func ProcessTenantPathsInDir(dir string, opts TenantPathsOptions) (*TenantPathsResult, error) {
+	if err := ValidateTenantPaths(opts.TenantPaths); err != nil {
+		return createTenantPathsResult(), err
+	}
+
+	return processTransformInDir(
+		dir,
+		StepTenantPaths,
+		opts.Options,
+		opts.TenantPaths.Enabled,
+		false,
+		createTenantPathsResult,
+		func(path string, result *TenantPathsResult) (bool, error) {
+			return processTenantPathsInFile(path, opts, result)
+		},
+		setTenantPathsProcessedFiles,
+		setTenantPathsChanged,
+	)
+}
+
+// ValidateTenantPaths checks the mode and that the parameter and component names fit in a path
+// segment and a $ref
+func ValidateTenantPaths(tenant config.TenantPaths) error {
+	switch tenant.Mode {
+	case "", TenantPathsInject, TenantPathsStrip:
+	default:
+		return fmt.Errorf("tenant_paths.mode must be %s or %s, got %q", TenantPathsInject, TenantPathsStrip, tenant.Mode)
+	}
+	if strings.ContainsAny(tenant.Parameter, "{}/") {
+		return fmt.Errorf("tenant_paths.parameter: %q must be a bare parameter name, like tenantId", tenant.Parameter)
+	}
+	if strings.ContainsAny(tenant.Component, "/~#") {
+		return fmt.Errorf("tenant_paths.component: %q is not a valid component name", tenant.Component)
+	}
+	return nil
+}
+
+// tenantParameter returns the name of the tenant path parameter and of its component
+func tenantParameter(tenant config.TenantPaths) (string, string) {
+	name, component := tenant.Parameter, tenant.Component
+	if name == "" {
+		name = defaultTenantParameter
+	}
+	if component == "" {
+		component = defaultTenantComponent
+	}
+	return name, component
+}
+
+// processTenantPathsInFile adds or removes the tenant path segment in a single file
+func processTenantPathsInFile(path string, opts TenantPathsOptions, result *TenantPathsResult) (bool, error) {
+	doc, err := loadAndParseDocument(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := getRootNode(doc)
+	if !isOpenAPIDocument(root) {
+		return false, nil // Skip non-OpenAPI files
+	}
+	paths := getNodeValue(root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return false, nil
+	}
+
+	var changed bool
+	if opts.TenantPaths.Mode == TenantPathsStrip {
+		changed, err = stripTenantPaths(root, paths, opts.TenantPaths, path, result)
+	} else {
+		changed, err = injectTenantPaths(root, paths, opts.TenantPaths, path, result)
+	}
+	if err != nil || !changed {
+		return false, err
+	}
+
+	return writeStepDocument(opts.Options, doc, path)
+}
+
+// tenantPathRenames returns the new name of every path the rename function rewrites, by index of
+// its key under paths, and fails when a new name is already taken
+func tenantPathRenames(paths *yaml.Node, tenant config.TenantPaths, rename func(string) (string, bool)) (map[int]string, error) {
+	existing := make(map[string]bool)
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		existing[paths.Content[i].Value] = true
+	}
+
+	renames := make(map[int]string)
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathName := paths.Content[i].Value
+		if len(tenant.ExcludePaths) > 0 && condition.MatchPath(pathName, tenant.ExcludePaths, condition.Glob) {
+			continue
+		}
+		renamed, ok := rename(pathName)
+		if !ok {
+			continue
+		}
+		if existing[renamed] {
+			return nil, fmt.Errorf("tenant_paths: cannot rewrite %s to %s, the path already exists", pathName, renamed)
+		}
+		existing[renamed] = true
+		renames[i] = renamed
+	}
+	return renames, nil
+}
+
+// injectTenantPaths prefixes the paths with the tenant segment and adds a $ref to the tenant
+// parameter to their path items. Paths that already start with the segment are left alone. The
+// component is created on first use; an existing one of the same name, or one of another name
+// declaring the same path parameter, is reused as is.
+func injectTenantPaths(root, paths *yaml.Node, tenant config.TenantPaths, file string, result *TenantPathsResult) (bool, error) {
+	name, component := tenantParameter(tenant)
+	segment := "/{" + name + "}"
+	renames, err := tenantPathRenames(paths, tenant, func(pathName string) (string, bool) {
+		if pathName == segment || strings.HasPrefix(pathName, segment+"/") {
+			return "", false
+		}
+		if pathName == "/" {
+			return segment, true
+		}
+		return segment + pathName, true
+	})
+	if err != nil || len(renames) == 0 {
+		return false, err
+	}
+
+	section, refPrefix := parameterSection(root)
+	if getNodeValue(section, component) == nil {
+		if existing := findParameterComponent(section, name, "path"); existing != "" {
+			component = existing
+		} else {
+			section = ensureParameterSection(root)
+			section.Content = append(section.Content, newScalarNode(component), tenantParameterDefinition(root, tenant, name))
+			result.ChangedComponents[file] = append(result.ChangedComponents[file], "parameters."+component+" created")
+		}
+	}
+	ref := refPrefix + component
+
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		renamed, ok := renames[i]
+		if !ok {
+			continue
+		}
+		key, pathItem := paths.Content[i], paths.Content[i+1]
+		entry := fmt.Sprintf("%s -> %s", key.Value, renamed)
+		result.RewrittenPaths[file] = append(result.RewrittenPaths[file], entry)
+		result.Locations = append(result.Locations, newChangeLocation(file, StepTenantPaths, key, entry))
+		key.Value = renamed
+
+		if pathItem.Kind != yaml.MappingNode || declaresTenantParameter(root, pathItem, name) {
+			continue
+		}
+		prependPathParameterRef(pathItem, ref)
+	}
+	return true, nil
+}
+
+// tenantParameterDefinition returns the component of the tenant path parameter, with its schema
+// inlined for Swagger 2.0
+func tenantParameterDefinition(root *yaml.Node, tenant config.TenantPaths, name string) *yaml.Node {
+	definition := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	definition.Content = append(definition.Content,
+		newScalarNode("name"), newScalarNode(name),
+		newScalarNode("in"), newScalarNode("path"),
+		newScalarNode("required"), &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+	)
+	if tenant.Description != "" {
+		definition.Content = append(definition.Content, newScalarNode("description"), newScalarNode(tenant.Description))
+	}
+
+	schema := &yaml.Node{}
+	if err := schema.Encode(tenant.Schema); err != nil || len(tenant.Schema) == 0 {
+		schema = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{newScalarNode("type"), newScalarNode("string")}}
+	}
+	definition.Content = append(definition.Content, newScalarNode("schema"), schema)
+	if getNodeValue(root, "swagger") != nil {
+		inlineHeaderSchema(definition)
+	}
+	return definition
+}
+
+// declaresTenantParameter reports whether a path item, or any of its operations, declares the
+// tenant path parameter
+func declaresTenantParameter(root, pathItem *yaml.Node, name string) bool {
+	if declaresParameter(root, getNodeValue(pathItem, "parameters"), name, "path") {
+		return true
+	}
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		if isHTTPMethod(pathItem.Content[i].Value) && declaresParameter(root, getNodeValue(pathItem.Content[i+1], "parameters"), name, "path") {
+			return true
+		}
+	}
+	return false
+}
+
+// prependPathParameterRef adds a $ref parameter in front of the parameters of a path item,
+// creating them before its operations if needed
+func prependPathParameterRef(pathItem *yaml.Node, ref string) {
+	params := getNodeValue(pathItem, "parameters")
+	if params == nil || params.Kind != yaml.SequenceNode {
+		removeMappingKey(pathItem, "parameters")
+		params = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		at := len(pathItem.Content)
+		for i := 0; i+1 < len(pathItem.Content); i += 2 {
+			if isHTTPMethod(pathItem.Content[i].Value) {
+				at = i
+				break
+			}
+		}
+		content := append([]*yaml.Node{}, pathItem.Content[:at]...)
+		content = append(content, newScalarNode("parameters"), params)
+		pathItem.Content = append(content, pathItem.Content[at:]...)
+	}
+	param := &yaml.Node{
+		Kind:    yaml.MappingNode,
+		Tag:     "!!map",
+		Content: []*yaml.Node{newScalarNode("$ref"), newScalarNode(ref)},
+	}
+	params.Content = append([]*yaml.Node{param}, params.Content...)
+}
+
+// stripTenantPaths removes the tenant segment from the paths starting with it, and the tenant
+// parameter from their path items and operations. Parameter components only those declarations
+// referenced are removed too.
+func stripTenantPaths(root, paths *yaml.Node, tenant config.TenantPaths, file string, result *TenantPathsResult) (bool, error) {
+	name, _ := tenantParameter(tenant)
+	segment := "/{" + name + "}"
+	renames, err := tenantPathRenames(paths, tenant, func(pathName string) (string, bool) {
+		if pathName == segment {
+			return "/", true
+		}
+		rest, ok := strings.CutPrefix(pathName, segment+"/")
+		return "/" + rest, ok
+	})
+	if err != nil || len(renames) == 0 {
+		return false, err
+	}
+
+	removedRefs := make(map[string]bool)
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		renamed, ok := renames[i]
+		if !ok {
+			continue
+		}
+		key, pathItem := paths.Content[i], paths.Content[i+1]
+		entry := fmt.Sprintf("%s -> %s", key.Value, renamed)
+		result.RewrittenPaths[file] = append(result.RewrittenPaths[file], entry)
+		result.Locations = append(result.Locations, newChangeLocation(file, StepTenantPaths, key, entry))
+		key.Value = renamed
+
+		if pathItem.Kind != yaml.MappingNode {
+			continue
+		}
+		removeTenantParameter(root, pathItem, name, removedRefs)
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			if isHTTPMethod(pathItem.Content[j].Value) {
+				removeTenantParameter(root, pathItem.Content[j+1], name, removedRefs)
+			}
+		}
+	}
+
+	section, refPrefix := parameterSection(root)
+	if section != nil && len(removedRefs) > 0 {
+		remaining := extractComponentRefs(root)
+		for _, ref := range sortedKeysOf(removedRefs) {
+			component, ok := strings.CutPrefix(ref, refPrefix)
+			if !ok || remaining[ref] || getNodeValue(section, component) == nil {
+				continue
+			}
+			removeMappingKey(section, component)
+			result.ChangedComponents[file] = append(result.ChangedComponents[file], "parameters."+component+" removed")
+		}
+		removeEmptyParameterSection(root, section)
+	}
+	return true, nil
+}
+
+// removeEmptyParameterSection removes the reusable parameters section once it is empty, and the
+// components section it leaves empty
+func removeEmptyParameterSection(root, section *yaml.Node) {
+	if len(section.Content) > 0 {
+		return
+	}
+	if getNodeValue(root, "swagger") != nil {
+		removeMappingKey(root, "parameters")
+		return
+	}
+	components := getNodeValue(root, "components")
+	removeMappingKey(components, "parameters")
+	if len(components.Content) == 0 {
+		removeMappingKey(root, "components")
+	}
+}
+
+// removeTenantParameter removes the tenant path parameter from the parameters of a path item or
+// operation, dropping the list once empty, and records the $refs it went through
+func removeTenantParameter(root, owner *yaml.Node, name string, removedRefs map[string]bool) {
+	params := getNodeValue(owner, "parameters")
+	if params == nil || params.Kind != yaml.SequenceNode {
+		return
+	}
+
+	var kept []*yaml.Node
+	for _, param := range params.Content {
+		resolved := resolveLocalRef(param, root)
+		if getStringValue(resolved, "name") != name || getStringValue(resolved, "in") != "path" {
+			kept = append(kept, param)
+			continue
+		}
+		if ref := getStringValue(param, "$ref"); ref != "" {
+			removedRefs[ref] = true
+		}
+	}
+	if len(kept) == len(params.Content) {
+		return
+	}
+	params.Content = kept
+	if len(kept) == 0 {
+		removeMappingKey(owner, "parameters")
+	}
+}
@@ -0,0 +1,135 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const tenantPathsSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+  /health:
+    get:
+      responses:
+        "200":
+          description: OK
+`
+
+func TestTenantPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(tenantPathsSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tenant := config.TenantPaths{
+		Enabled:      true,
+		Schema:       map[string]interface{}{"type": "string", "format": "uuid"},
+		ExcludePaths: []string{"/health"},
+	}
+	result, err := ProcessTenantPathsInDir(dir, TenantPathsOptions{TenantPaths: tenant})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/users/{id} -> /{tenantId}/users/{id}"}; !reflect.DeepEqual(result.RewrittenPaths[path], want) {
+		t.Errorf("expected rewrites %v, got %v", want, result.RewrittenPaths[path])
+	}
+	if want := []string{"parameters.TenantId created"}; !reflect.DeepEqual(result.ChangedComponents[path], want) {
+		t.Errorf("expected components %v, got %v", want, result.ChangedComponents[path])
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Compare without indentation
+	flat := strings.Join(strings.Fields(string(data)), " ")
+	for _, want := range []string{
+		"/{tenantId}/users/{id}: parameters: - $ref: '#/components/parameters/TenantId' get:",
+		"/health: get:",
+		"TenantId: name: tenantId in: path required: true schema: format: uuid type: string",
+	} {
+		if !strings.Contains(flat, want) {
+			t.Errorf("expected %q in the output:\n%s", want, data)
+		}
+	}
+
+	// A second run finds every path prefixed
+	result, err = ProcessTenantPathsInDir(dir, TenantPathsOptions{TenantPaths: tenant})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed {
+		t.Errorf("expected no changes on a second run, got %v", result.RewrittenPaths)
+	}
+
+	// Stripping restores the original paths and drops the component
+	tenant.Mode = TenantPathsStrip
+	result, err = ProcessTenantPathsInDir(dir, TenantPathsOptions{TenantPaths: tenant})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"parameters.TenantId removed"}; !reflect.DeepEqual(result.ChangedComponents[path], want) {
+		t.Errorf("expected components %v, got %v", want, result.ChangedComponents[path])
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flat = strings.Join(strings.Fields(string(data)), " ")
+	if strings.Contains(flat, "tenantId") || strings.Contains(flat, "components") || !strings.Contains(flat, "/users/{id}: get:") {
+		t.Errorf("expected the tenant segment, parameter and component to be gone:\n%s", data)
+	}
+}
+
+func TestTenantPathsCollision(t *testing.T) {
+	dir := t.TempDir()
+	spec := strings.Replace(tenantPathsSpec, "  /health:", "  /{tenantId}/health:", 1)
+	if err := os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(spec+"  /health:\n    get: {}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ProcessTenantPathsInDir(dir, TenantPathsOptions{TenantPaths: config.TenantPaths{Enabled: true}})
+	if err == nil || !strings.Contains(err.Error(), "/health to /{tenantId}/health, the path already exists") {
+		t.Errorf("expected a collision error, got %v", err)
+	}
+}
+
+func TestValidateTenantPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenant  config.TenantPaths
+		wantErr bool
+	}{
+		{"defaults", config.TenantPaths{}, false},
+		{"strip", config.TenantPaths{Mode: TenantPathsStrip, Parameter: "org"}, false},
+		{"unknown mode", config.TenantPaths{Mode: "prefix"}, true},
+		{"braced parameter", config.TenantPaths{Parameter: "{tenantId}"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTenantPaths(tt.tenant)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
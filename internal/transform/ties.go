@@ -0,0 +1,136 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/pagination"
+)
+
+// PaginationTie is an operation the pagination step leaves alone, or cleans up for the first of
+// several conflicting endpoint rules, because the config does not decide its strategy
+type PaginationTie struct {
+	pagination.Tie
+	File   string
+	Line   int
+	Column int
+	Path   string
+	Method string // lowercase, as in the document
+}
+
+// Position returns the operation's location as file:line:column
+func (t PaginationTie) Position() string {
+	return ChangeLocation{File: t.File, Line: t.Line, Column: t.Column}.Position()
+}
+
+// Operation returns the operation as "GET /users"
+func (t PaginationTie) Operation() string {
+	return strings.ToUpper(t.Method) + " " + t.Path
+}
+
+// Evidence describes what was detected of each strategy, as "cursor: params cursor, size"
+func (t PaginationTie) Evidence() []string {
+	return describeEvidence(t.Detected)
+}
+
+// describeEvidence describes what was detected of each strategy, as "cursor: params cursor, size"
+func describeEvidence(detections []pagination.DetectedPagination) []string {
+	var evidence []string
+	for _, detected := range detections {
+		var found []string
+		if len(detected.Parameters) > 0 {
+			found = append(found, "params "+strings.Join(detected.Parameters, ", "))
+		}
+		if len(detected.Fields) > 0 {
+			found = append(found, "response fields "+strings.Join(detected.Fields, ", "))
+		}
+		if len(detected.Links) > 0 {
+			found = append(found, "links "+strings.Join(detected.Links, ", "))
+		}
+		evidence = append(evidence, detected.Strategy+": "+strings.Join(found, "; "))
+	}
+	return evidence
+}
+
+// Reason explains why the config does not decide the strategy
+func (t PaginationTie) Reason() string {
+	if len(t.Rules) == 0 {
+		return "none of the detected strategies is in pagination_priority"
+	}
+	rules := make([]string, 0, len(t.Rules))
+	for _, rule := range t.Rules {
+		rules = append(rules, fmt.Sprintf("%s %s -> %s", rule.Method, rule.Endpoint, rule.Pagination))
+	}
+	return "conflicting endpoint_pagination rules: " + strings.Join(rules, ", ")
+}
+
+// FindPaginationTies lists the operations of the OpenAPI documents under the input whose pagination
+// strategy the config does not decide, in document order. Nothing is reported when the pagination
+// step is off.
+func FindPaginationTies(cfg *config.Config) ([]PaginationTie, error) {
+	if len(cfg.PaginationPriority) == 0 {
+		return nil, nil
+	}
+	opts := pagination.Options{
+		Priority:      cfg.PaginationPriority,
+		EndpointRules: convertEndpointRules(cfg.EndpointPagination),
+	}
+
+	var ties []PaginationTie
+	err := walkOpenAPIDocuments(cfg.Input, func(path string, _, root *yaml.Node) error {
+		if IncludesFile(cfg.Files, StepPagination, cfg.Input, path) {
+			ties = append(ties, documentPaginationTies(path, root, cfg.OnlyPaths, opts)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look for pagination ties: %v", err)
+	}
+	return ties, nil
+}
+
+// documentPaginationTies lists the ties of the operations of a document
+func documentPaginationTies(path string, root *yaml.Node, onlyPaths []string, opts pagination.Options) []PaginationTie {
+	paths := getNodeValue(root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var ties []PaginationTie
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+		if pathItem.Kind != yaml.MappingNode || !condition.MatchPath(pathName, onlyPaths, condition.Glob) {
+			continue
+		}
+		for j := 0; j+1 < len(pathItem.Content); j += 2 {
+			methodNode, operation := pathItem.Content[j], pathItem.Content[j+1]
+			if !isHTTPMethod(methodNode.Value) {
+				continue
+			}
+			if tie := pagination.FindTie(pathItem, operation, root, pathName, methodNode.Value, opts); tie != nil {
+				ties = append(ties, PaginationTie{
+					Tie:    *tie,
+					File:   path,
+					Line:   methodNode.Line,
+					Column: methodNode.Column,
+					Path:   pathName,
+					Method: methodNode.Value,
+				})
+			}
+		}
+	}
+	return ties
+}
+
+// TieRule returns the endpoint rule that settles a tie with strategy, naming the operation exactly
+func TieRule(tie PaginationTie, strategy string) config.EndpointPaginationRule {
+	return config.EndpointPaginationRule{
+		Endpoint:   tie.Path,
+		Method:     strings.ToUpper(tie.Method),
+		Pagination: strategy,
+	}
+}
@@ -0,0 +1,67 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+func TestFindPaginationTies(t *testing.T) {
+	dir := t.TempDir()
+	spec := `openapi: 3.0.0
+info:
+  title: ties
+  version: "1"
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+        - name: page
+          in: query
+      responses:
+        "200":
+          description: ok
+  /orders:
+    get:
+      parameters:
+        - name: offset
+          in: query
+        - name: page
+          in: query
+      responses:
+        "200":
+          description: ok
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Input: dir, PaginationPriority: []string{"offset", "checkpoint"}}
+
+	ties, err := FindPaginationTies(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ties) != 1 {
+		t.Fatalf("expected only GET /users to be tied, got %+v", ties)
+	}
+	tie := ties[0]
+	if tie.Operation() != "GET /users" || tie.Line != 7 {
+		t.Errorf("unexpected tie %s at line %d", tie.Operation(), tie.Line)
+	}
+	if !reflect.DeepEqual(tie.Candidates, []string{"cursor", "page", "none"}) {
+		t.Errorf("unexpected candidates %v", tie.Candidates)
+	}
+	if want := []string{"cursor: params cursor", "page: params page"}; !reflect.DeepEqual(tie.Evidence(), want) {
+		t.Errorf("expected evidence %v, got %v", want, tie.Evidence())
+	}
+
+	cfg.EndpointPagination = []config.EndpointPaginationRule{TieRule(tie, "page")}
+	if ties, err := FindPaginationTies(cfg); err != nil || len(ties) != 0 {
+		t.Errorf("expected the saved rule to settle the tie, got %+v (%v)", ties, err)
+	}
+}
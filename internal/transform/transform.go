@@ -2,13 +2,18 @@ package transform
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/progress"
 )
 
 type Options struct {
@@ -17,6 +22,22 @@ type Options struct {
 	DryRun     bool
 	Backup     bool
 	OutputFile string
+	Context    context.Context    // parent of the per-file telemetry spans, defaults to context.Background()
+	Files      config.FileFilter  // include/exclude patterns selecting the files each step processes
+	Collisions string             // strategy for a mapping whose target key already exists, defaults to keep_existing
+	Paths      []string           // glob patterns limiting the operation-level steps to matching paths, empty for all
+	Progress   *progress.Reporter // receives the files each step processes, nil for none
+	Log        io.Writer          // receives the warnings steps print while processing, defaults to os.Stdout
+	failures   *fileErrors        // records the files that fail in a keep-going run, nil to abort on the first error
+	metrics    *fileMetrics       // records each step's duration and size change per file, nil for none
+}
+
+// logWriter returns the writer step warnings go to
+func (o Options) logWriter() io.Writer {
+	if o.Log == nil {
+		return os.Stdout
+	}
+	return o.Log
 }
 
 // KeyChange represents a change in a key's mapping.
@@ -24,11 +45,21 @@ type KeyChange struct {
 	File   string
 	OldKey string
 	NewKey string
-	Line   int // Add line number for YAML changes, 0 for JSON
+	Line   int // 1-based line of the key, 0 if unknown
+	Column int // 1-based column of the key, 0 if unknown
+	// Collision is how a target key that already existed was handled (keep_existing, overwrite or
+	// merge), empty when there was none. Under keep_existing the key is left unrenamed.
+	Collision string
 }
 
 // Dir walks a directory and transforms all YAML/JSON files.
 func Dir(dir string, opts Options) ([]string, error) {
+	changed, _, err := DirWithChanges(dir, opts)
+	return changed, err
+}
+
+// DirWithChanges is like Dir, but also returns every key change with its position.
+func DirWithChanges(dir string, opts Options) ([]string, []KeyChange, error) {
 	var changed []string
 	var allFiles []string
 	var dryRunChanges []KeyChange
@@ -40,8 +71,10 @@ func Dir(dir string, opts Options) ([]string, error) {
 			return nil
 		}
 		allFiles = append(allFiles, path)
-		if IsYAML(path) || IsJSON(path) {
-			ok, err := FileWithChanges(path, opts, &dryRunChanges)
+		if stepProcessesFile(opts, StepMappings, dir, path) {
+			ok, err := traceFile(opts, StepMappings, path, func() (bool, error) {
+				return FileWithChanges(path, opts, &dryRunChanges)
+			})
 			if err != nil {
 				return err
 			}
@@ -54,7 +87,7 @@ func Dir(dir string, opts Options) ([]string, error) {
 	if opts.DryRun && len(dryRunChanges) > 0 {
 		printDryRunSummary(dryRunChanges)
 	}
-	return changed, err
+	return changed, dryRunChanges, err
 }
 
 // FileWithChanges is like File, but collects key changes for dry-run summary.
@@ -79,8 +112,16 @@ func FileWithChanges(path string, opts Options, changes *[]KeyChange) (bool, err
 
 // processJSONFileWithChanges handles JSON file transformation
 func processJSONFileWithChanges(orig []byte, opts Options, path, outputPath string, changes *[]KeyChange) (bool, error) {
+	if hasPathMappings(opts.Mappings) || hasTargetKeys(orig, opts.Mappings) || bytes.Contains(orig, []byte(`"discriminator"`)) {
+		// Moving values between levels, resolving collisions and following renamed discriminator
+		// properties need the document tree, not text replacement
+		return processJSONTreeWithChanges(orig, opts, path, outputPath, changes)
+	}
 	patched, changed := patchJSONKeysWithChanges(orig, opts, path, changes)
 	if opts.DryRun {
+		if changed {
+			opts.metrics.plan(path, len(patched))
+		}
 		return changed, nil
 	}
 	if changed {
@@ -92,6 +133,30 @@ func processJSONFileWithChanges(orig []byte, opts Options, path, outputPath stri
 	return false, nil
 }
 
+// processJSONTreeWithChanges handles JSON file transformation on the parsed document
+func processJSONTreeWithChanges(orig []byte, opts Options, path, outputPath string, changes *[]KeyChange) (bool, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(orig, &node); err != nil {
+		return false, err
+	}
+	changed, err := transformDocumentWithChanges(getYAMLRoot(&node), opts, path, changes)
+	if err != nil || !changed {
+		return false, err
+	}
+	if opts.DryRun {
+		return writeStepDocument(opts, &node, path)
+	}
+
+	out, err := formatAsJSON(&node)
+	if err != nil {
+		return false, err
+	}
+	if opts.Backup && opts.OutputFile == "" {
+		_ = os.WriteFile(path+".bak", orig, 0600)
+	}
+	return true, os.WriteFile(outputPath, out, 0600)
+}
+
 // processYAMLFileWithChanges handles YAML file transformation
 func processYAMLFileWithChanges(orig []byte, opts Options, path, outputPath string, changes *[]KeyChange) (bool, error) {
 	var node yaml.Node
@@ -100,9 +165,9 @@ func processYAMLFileWithChanges(orig []byte, opts Options, path, outputPath stri
 	}
 
 	root := getYAMLRoot(&node)
-	changed := transformMapNodeWithChanges(root, opts, path, changes)
-	if !changed {
-		return false, nil
+	changed, err := transformDocumentWithChanges(root, opts, path, changes)
+	if err != nil || !changed {
+		return false, err
 	}
 
 	out, err := yaml.Marshal(&node)
@@ -111,6 +176,7 @@ func processYAMLFileWithChanges(orig []byte, opts Options, path, outputPath stri
 	}
 
 	if opts.DryRun {
+		opts.metrics.plan(path, len(out))
 		return !equalBytes(orig, out), nil
 	}
 
@@ -137,46 +203,163 @@ func patchJSONKeysWithChanges(orig []byte, opts Options, file string, changes *[
 	}
 	changed := false
 	patched := orig
-	for from, to := range opts.Mappings {
+	for _, from := range sortedKeysOf(opts.Mappings) {
+		to := opts.Mappings[from]
+		if isPathMapping(from, to) {
+			continue
+		}
 		// Only replace keys that are quoted and followed by a colon ("key":)
 		needle := []byte("\"" + from + "\":")
 		replacement := []byte("\"" + to + "\":")
 		if bytes.Contains(patched, needle) {
+			if changes != nil {
+				// Record every occurrence, so per-mapping counts match the YAML path
+				for start := 0; ; start += len(needle) {
+					offset := bytes.Index(patched[start:], needle)
+					if offset < 0 {
+						break
+					}
+					start += offset
+					line, column := byteOffsetPosition(patched, start)
+					*changes = append(*changes, KeyChange{File: file, OldKey: from, NewKey: to, Line: line, Column: column})
+				}
+			}
 			patched = bytes.ReplaceAll(patched, needle, replacement)
 			changed = true
-			if opts.DryRun && changes != nil {
-				*changes = append(*changes, KeyChange{File: file, OldKey: from, NewKey: to})
-			}
 		}
 	}
 	return patched, changed
 }
 
+// byteOffsetPosition converts a byte offset into a 1-based line and column
+func byteOffsetPosition(data []byte, offset int) (int, int) {
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(data[:offset], '\n')
+	return line, column
+}
+
+// transformDocumentWithChanges applies the mappings to a document and points its discriminators at
+// the properties they renamed, recording the key changes.
+func transformDocumentWithChanges(root *yaml.Node, opts Options, file string, changes *[]KeyChange) (bool, error) {
+	var keyChanges []KeyChange
+	changed, err := transformMapNodeWithChanges(root, opts, file, &keyChanges)
+	if changes != nil {
+		*changes = append(*changes, keyChanges...)
+	}
+	if err != nil || !changed {
+		return changed, err
+	}
+
+	renamed := make(map[string]string)
+	for _, change := range keyChanges {
+		if change.Collision != CollisionKeepExisting && !isPathMapping(change.OldKey, change.NewKey) {
+			renamed[change.OldKey] = change.NewKey
+		}
+	}
+	renameDiscriminatorProperties(root, renamed)
+	return true, nil
+}
+
 // transformMapNodeWithChanges is like transformMapNode, but records changes.
-func transformMapNodeWithChanges(n *yaml.Node, opts Options, file string, changes *[]KeyChange) bool {
+func transformMapNodeWithChanges(n *yaml.Node, opts Options, file string, changes *[]KeyChange) (bool, error) {
 	changed := false
 	if n.Kind == yaml.MappingNode {
+		if hasPathMappings(opts.Mappings) {
+			moved, err := applyPathMappings(n, opts, file, changes)
+			if err != nil {
+				return changed, err
+			}
+			changed = moved
+		}
 		for i := 0; i < len(n.Content); i += 2 {
 			k := n.Content[i]
 			v := n.Content[i+1]
 			if isExcludedKey(k.Value, opts.Exclude) {
 				continue
 			}
-			if applyMappingAndRecordChange(k, opts, file, changes) {
+			if target := collidingKeyIndex(n, k.Value, opts.Mappings); target >= 0 {
+				resolved, err := applyMappingCollision(n, i, target, opts, file, changes)
+				if err != nil {
+					return changed, err
+				}
+				if resolved {
+					changed = true
+					i -= 2 // The mapped pair was removed; its value now lives under the target
+					continue
+				}
+			} else if applyMappingAndRecordChange(k, opts, file, changes) {
 				changed = true
 			}
-			if transformMapNodeWithChanges(v, opts, file, changes) {
-				changed = true
+			childChanged, err := transformMapNodeWithChanges(v, opts, file, changes)
+			if err != nil {
+				return changed, err
 			}
+			changed = changed || childChanged
 		}
 	} else if n.Kind == yaml.SequenceNode {
 		for _, v := range n.Content {
-			if transformMapNodeWithChanges(v, opts, file, changes) {
-				changed = true
+			childChanged, err := transformMapNodeWithChanges(v, opts, file, changes)
+			if err != nil {
+				return changed, err
 			}
+			changed = changed || childChanged
+		}
+	}
+	return changed, nil
+}
+
+// collidingKeyIndex returns the index in n of the key that the mapping of key would collide with,
+// or -1. A target key that is itself renamed away by a mapping does not collide.
+func collidingKeyIndex(n *yaml.Node, key string, mappings map[string]string) int {
+	to, ok := mappings[key]
+	if !ok || isPathMapping(key, to) {
+		return -1
+	}
+	if _, renamed := mappings[to]; renamed {
+		return -1
+	}
+	if index := mappingKeyIndex(n, to); index < len(n.Content) {
+		return index
+	}
+	return -1
+}
+
+// applyMappingCollision resolves the mapping of the key at index i onto the existing key at index
+// target of n and reports whether the mapped pair was removed
+func applyMappingCollision(n *yaml.Node, i, target int, opts Options, file string, changes *[]KeyChange) (bool, error) {
+	k, v := n.Content[i], n.Content[i+1]
+	to := opts.Mappings[k.Value]
+	resolution := collisionResolution(opts.Collisions, n.Content[target+1], v)
+	if resolution == CollisionError {
+		return false, collisionError(file, k, k.Value, to)
+	}
+	if changes != nil {
+		*changes = append(*changes, KeyChange{File: file, OldKey: k.Value, NewKey: to, Line: k.Line, Column: k.Column, Collision: resolution})
+	}
+	if resolution == CollisionKeepExisting {
+		return false, nil
+	}
+
+	if target < i {
+		// The target was already visited, so the value it takes over is transformed here
+		if _, err := transformMapNodeWithChanges(v, opts, file, changes); err != nil {
+			return false, err
 		}
 	}
-	return changed
+	n.Content[target+1] = resolveCollision(resolution, n.Content[target+1], v)
+	n.Content = append(n.Content[:i], n.Content[i+2:]...)
+	return true, nil
+}
+
+// hasTargetKeys reports whether a JSON document contains the target key of one of the mappings
+// as well as its source key, so renaming by text replacement could produce duplicate keys
+func hasTargetKeys(data []byte, mappings map[string]string) bool {
+	for from, to := range mappings {
+		if bytes.Contains(data, []byte("\""+from+"\":")) && bytes.Contains(data, []byte("\""+to+"\":")) {
+			return true
+		}
+	}
+	return false
 }
 
 // isExcludedKey returns true if the key is in the exclude list.
@@ -197,13 +380,9 @@ func applyMappingAndRecordChange(k *yaml.Node, opts Options, file string, change
 	if opts.Mappings == nil {
 		return false
 	}
-	if to, ok := opts.Mappings[k.Value]; ok {
-		if opts.DryRun && changes != nil {
-			line := 0
-			if k.Line > 0 {
-				line = k.Line
-			}
-			*changes = append(*changes, KeyChange{File: file, OldKey: k.Value, NewKey: to, Line: line})
+	if to, ok := opts.Mappings[k.Value]; ok && !isPathMapping(k.Value, to) {
+		if changes != nil {
+			*changes = append(*changes, KeyChange{File: file, OldKey: k.Value, NewKey: to, Line: k.Line, Column: k.Column})
 		}
 		k.Value = to
 		return true
@@ -226,7 +405,8 @@ func printDryRunSummary(changes []KeyChange) {
 	fmt.Printf("Files affected: %d\n", len(files))
 	fmt.Println("────────────────────────────────────────────────────────────")
 
-	for file, fileChanges := range files {
+	for _, file := range sortedKeysOf(files) {
+		fileChanges := files[file]
 		fmt.Printf("\033[1;36mFile: %s\033[0m\n", file)
 		fmt.Println(strings.Repeat("─", 40))
 		data, err := os.ReadFile(file)
@@ -236,6 +416,10 @@ func printDryRunSummary(changes []KeyChange) {
 		}
 		lines := strings.Split(string(data), "\n")
 		for _, c := range fileChanges {
+			if c.Collision == CollisionKeepExisting {
+				fmt.Printf("\033[1;33m~ %s kept: %s already exists\033[0m\n", c.OldKey, c.NewKey)
+				continue
+			}
 			indices := findKeyLineIndices(lines, c.OldKey)
 			if len(indices) == 0 {
 				printNoMatchBlock(c)
@@ -363,6 +547,8 @@ func File(path string, opts Options) (bool, error) {
 // processTransformInDir is a generic helper to apply a transform across all OpenAPI files in a directory.
 func processTransformInDir[T any](
 	dir string,
+	step string,
+	opts Options,
 	enabled bool,
 	isConfigEmpty bool,
 	initResult func() T,
@@ -387,8 +573,10 @@ func processTransformInDir[T any](
 			return nil
 		}
 
-		if IsYAML(path) || IsJSON(path) {
-			changed, err := processFileWithResult(path, result)
+		if stepProcessesFile(opts, step, dir, path) {
+			changed, err := traceFile(opts, step, path, func() (bool, error) {
+				return processFileWithResult(path, result)
+			})
 			if err != nil {
 				return fmt.Errorf("error processing %s: %w", path, err)
 			}
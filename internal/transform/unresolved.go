@@ -0,0 +1,95 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/developerkunal/OpenMorph/internal/condition"
+	"github.com/developerkunal/OpenMorph/internal/config"
+	"github.com/developerkunal/OpenMorph/internal/pagination"
+)
+
+// UnresolvedRef is a $ref the pagination step followed but could not resolve. What it points at is
+// invisible to detection and cleanup, so the pagination of the operation may be mis-detected.
+type UnresolvedRef struct {
+	StrictIssue
+	Operation string // first operation that followed it, as "GET /users"
+}
+
+// addUnresolvedRef records a reference an operation followed, unless another operation already did
+func addUnresolvedRef(result *PaginationResult, file, pathName, method string, ref pagination.UnresolvedRef) {
+	for _, recorded := range result.UnresolvedRefs {
+		if recorded.File == file && recorded.Line == ref.Line && recorded.Column == ref.Column {
+			return
+		}
+	}
+	result.UnresolvedRefs = append(result.UnresolvedRefs, UnresolvedRef{
+		StrictIssue: StrictIssue{File: file, Line: ref.Line, Column: ref.Column, Message: ref.Reason()},
+		Operation:   strings.ToUpper(method) + " " + pathName,
+	})
+}
+
+// addDynamicRef records a $dynamicRef an operation resolved best-effort, unless another operation
+// already did
+func addDynamicRef(result *PaginationResult, file, pathName, method string, ref pagination.DynamicRef) {
+	for _, recorded := range result.DynamicRefs {
+		if recorded.File == file && recorded.Line == ref.Line && recorded.Column == ref.Column {
+			return
+		}
+	}
+	result.DynamicRefs = append(result.DynamicRefs, UnresolvedRef{
+		StrictIssue: StrictIssue{File: file, Line: ref.Line, Column: ref.Column, Message: ref.Warning()},
+		Operation:   strings.ToUpper(method) + " " + pathName,
+	})
+}
+
+// checkUnresolvedRefs reports the $refs the pagination step would follow under inputPath but could
+// not resolve, leaving out those at the positions of known issues. Nothing is reported when the
+// pagination step is off.
+func checkUnresolvedRefs(inputPath string, cfg *config.Config, known []StrictIssue) ([]StrictIssue, error) {
+	if len(cfg.PaginationPriority) == 0 {
+		return nil, nil
+	}
+	reported := make(map[string]bool, len(known))
+	for _, issue := range known {
+		reported[issue.Position()] = true
+	}
+
+	var issues []StrictIssue
+	err := walkOpenAPIDocuments(inputPath, func(path string, _, root *yaml.Node) error {
+		if !IncludesFile(cfg.Files, StepPagination, inputPath, path) {
+			return nil
+		}
+		result := &PaginationResult{}
+		paths := getNodeValue(root, "paths")
+		if paths == nil || paths.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			pathName, pathItem := paths.Content[i].Value, paths.Content[i+1]
+			if pathItem.Kind != yaml.MappingNode || !condition.MatchPath(pathName, cfg.OnlyPaths, condition.Glob) {
+				continue
+			}
+			for j := 0; j+1 < len(pathItem.Content); j += 2 {
+				if method := pathItem.Content[j].Value; isHTTPMethod(method) {
+					for _, ref := range pagination.UnresolvedRefs(pathItem, pathItem.Content[j+1], root) {
+						addUnresolvedRef(result, path, pathName, method, ref)
+					}
+				}
+			}
+		}
+		for _, ref := range result.UnresolvedRefs {
+			if !reported[ref.Position()] {
+				issues = append(issues, StrictIssue{File: ref.File, Line: ref.Line, Column: ref.Column,
+					Message: fmt.Sprintf("%s, followed by the pagination step for %s", ref.Message, ref.Operation)})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pagination references: %v", err)
+	}
+	return issues, nil
+}
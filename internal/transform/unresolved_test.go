@@ -0,0 +1,104 @@
+package transform
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const unresolvedTestSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Cursor'
+        - $ref: 'common.yaml#/components/parameters/Offset'
+        - name: page
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+  /orders:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Page'
+      responses:
+        "200":
+          description: OK
+components:
+  parameters:
+    Page:
+      name: page
+      in: query
+      schema:
+        $ref: '#/components/schemas/PageNumber'
+`
+
+func TestPaginationRecordsUnresolvedRefs(t *testing.T) {
+	dir := t.TempDir()
+	spec := writeSniffFile(t, dir, "api.yaml", unresolvedTestSpec)
+	writeSniffFile(t, dir, "common.yaml", "components:\n  parameters:\n    Offset:\n      name: offset\n      in: query\n")
+
+	result, err := ProcessPaginationInDir(dir, PaginationOptions{
+		Options:            Options{DryRun: true},
+		PaginationPriority: []string{"page", "offset"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.UnresolvedRefs) != 3 {
+		t.Fatalf("expected 3 unresolved references, got %+v", result.UnresolvedRefs)
+	}
+	first, second, third := result.UnresolvedRefs[0], result.UnresolvedRefs[1], result.UnresolvedRefs[2]
+	if first.Position() != spec+":9:17" || first.Operation != "GET /users" || first.Message != "unresolved $ref #/components/parameters/Cursor" {
+		t.Errorf("unexpected first reference %+v", first)
+	}
+	if second.Position() != spec+":10:17" || !strings.Contains(second.Message, "references to other documents are not followed") {
+		t.Errorf("unexpected second reference %+v", second)
+	}
+	if third.Position() != spec+":31:15" || third.Operation != "GET /orders" {
+		t.Errorf("unexpected third reference %+v", third)
+	}
+}
+
+func TestPipelineStrictFailsOnUnresolvedPaginationRefs(t *testing.T) {
+	dir := t.TempDir()
+	spec := writeSniffFile(t, dir, "api.yaml", unresolvedTestSpec)
+	writeSniffFile(t, dir, "common.yaml", "components:\n  parameters:\n    Offset:\n      name: offset\n      in: query\n")
+
+	cfg := &config.Config{PaginationPriority: []string{"page", "offset"}}
+	pipeline := NewTransformationPipeline(cfg, nil, true, false, "")
+	pipeline.Strict = true
+
+	err := pipeline.CheckStrict(dir)
+	var strictErr *StrictError
+	if !errors.As(err, &strictErr) {
+		t.Fatalf("expected StrictError, got %v", err)
+	}
+	// The dangling references are reported by the $ref check already, and only once
+	var positions []string
+	for _, issue := range strictErr.Issues {
+		positions = append(positions, issue.Position())
+	}
+	clean := filepath.Clean(spec)
+	want := []string{clean + ":9:17", clean + ":31:15", clean + ":10:17"}
+	if strings.Join(positions, " ") != strings.Join(want, " ") {
+		t.Errorf("expected issues at %v, got %+v", want, strictErr.Issues)
+	}
+	if last := strictErr.Issues[2].Message; !strings.HasSuffix(last, "followed by the pagination step for GET /users") {
+		t.Errorf("unexpected message %q", last)
+	}
+
+	cfg.PaginationPriority = nil
+	if err := pipeline.CheckStrict(dir); !errors.As(err, &strictErr) || len(strictErr.Issues) != 2 {
+		t.Errorf("expected only the $ref check without the pagination step, got %v", err)
+	}
+}
@@ -0,0 +1,492 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+// OutputProfileNone is the output profile that applies no vendor extensions
+const OutputProfileNone = "none"
+
+// VariantResult holds the results of one output variant
+type VariantResult struct {
+	Profile string
+	Dir     string
+	Results *TransformationResults
+}
+
+// VariantsResults aggregates the shared steps and every output variant of a multi-output run
+type VariantsResults struct {
+	Shared   *TransformationResults // steps that ran once for all variants
+	Variants []VariantResult
+}
+
+// ExecuteVariants runs the profile-independent steps once on a staged copy of the input, then
+// copies the staged result into every variant directory and runs the profile-specific steps there.
+// In dry-run mode variants are computed in temporary directories and nothing is written.
+func (tp *TransformationPipeline) ExecuteVariants(inputPath string, variants []config.OutputVariant) (*VariantsResults, error) {
+	if err := tp.validateVariants(variants); err != nil {
+		return nil, err
+	}
+
+	var results *VariantsResults
+	err := tp.withPipelineSpan(inputPath, func(pipeline *TransformationPipeline) error {
+		var err error
+		results, err = pipeline.executeVariants(inputPath, variants)
+		return err
+	})
+	return results, err
+}
+
+// executeVariants stages the input and produces every variant from it. The staged copy and the
+// variants are worked on in place, so every variant goes through the same steps as a run over the
+// input, from the shared steps to link sync, provenance stamping and the pagination invariants.
+func (tp *TransformationPipeline) executeVariants(inputPath string, variants []config.OutputVariant) (*VariantsResults, error) {
+	if err := tp.CheckStrict(inputPath); err != nil {
+		return nil, err
+	}
+
+	stageDir, err := os.MkdirTemp("", "openmorph_stage_*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(stageDir)
+
+	stageInput, err := stageInputCopy(inputPath, stageDir)
+	if err != nil {
+		return nil, err
+	}
+
+	stage := *tp
+	stage.DryRun = false
+	stage.Backup = false
+	stage.OutputFile = ""
+	run, shared, err := stage.startDirectoryRun(stageInput)
+	if err != nil {
+		return nil, err
+	}
+	err = protectStep(tp.Config.Protect, stageInput, StepMappings, shared, func() error {
+		return applyMappingsStep(stageInput, run.opts, shared)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := stage.applySharedSteps(stageInput, run.opts, shared); err != nil {
+		return nil, err
+	}
+	shared.FileErrors = run.opts.failures.list()
+	shared.FileMetrics = run.opts.metrics.list()
+	rebaseResults(shared, stageInput, inputPath)
+
+	results := &VariantsResults{Shared: shared}
+	for _, variant := range variants {
+		variantResult, err := tp.executeVariant(&stage, run, stageInput, inputPath, variant)
+		if err != nil {
+			return nil, fmt.Errorf("output %s (%s): %w", variant.Dir, variant.Profile, err)
+		}
+		results.Variants = append(results.Variants, *variantResult)
+	}
+
+	return results, nil
+}
+
+// validateVariants checks that every variant has a unique directory and a known profile
+func (tp *TransformationPipeline) validateVariants(variants []config.OutputVariant) error {
+	if len(variants) == 0 {
+		return errors.New("no outputs configured")
+	}
+	if err := ValidateFileFilter(tp.Config.Files); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for i, variant := range variants {
+		if variant.Dir == "" {
+			return fmt.Errorf("outputs[%d]: dir is required", i)
+		}
+		dir := filepath.Clean(variant.Dir)
+		if seen[dir] {
+			return fmt.Errorf("outputs[%d]: duplicate dir %s", i, variant.Dir)
+		}
+		seen[dir] = true
+
+		if variant.Profile == "" {
+			return fmt.Errorf("outputs[%d]: profile is required (a vendor provider name or %q)", i, OutputProfileNone)
+		}
+		if err := validateProfile(tp.Config, variant.Profile); err != nil {
+			return fmt.Errorf("outputs[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateProfile checks that profile is "none" or a configured vendor provider
+func validateProfile(cfg *config.Config, profile string) error {
+	switch {
+	case profile == OutputProfileNone:
+		return nil
+	case !cfg.VendorExtensions.Enabled:
+		return fmt.Errorf("profile %q requires vendor_extensions to be enabled", profile)
+	default:
+		if _, ok := cfg.VendorExtensions.Providers[profile]; !ok {
+			return fmt.Errorf("unknown profile %q (configured providers: %s)",
+				profile, strings.Join(configuredProviderNames(cfg), ", "))
+		}
+	}
+	return nil
+}
+
+// profileConfig returns a copy of cfg and the vendor providers that apply the given profile
+func profileConfig(cfg *config.Config, profile string) (*config.Config, []string) {
+	profileCfg := *cfg
+	if profile == OutputProfileNone {
+		profileCfg.VendorExtensions.Enabled = false
+		return &profileCfg, nil
+	}
+	return &profileCfg, []string{profile}
+}
+
+// configuredProviderNames returns the configured vendor provider names in sorted order
+func configuredProviderNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.VendorExtensions.Providers))
+	for name := range cfg.VendorExtensions.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// executeVariant copies the staged input into the variant's directory (or a temporary one in
+// dry-run mode) and finishes the run there with the profile-specific steps
+func (tp *TransformationPipeline) executeVariant(stage *TransformationPipeline, run *directoryRun, stageInput, inputPath string, variant config.OutputVariant) (*VariantResult, error) {
+	targetRoot := variant.Dir
+	if tp.DryRun {
+		tempDir, err := os.MkdirTemp("", "openmorph_variant_*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temp directory: %v", err)
+		}
+		defer os.RemoveAll(tempDir)
+		targetRoot = tempDir
+	}
+
+	target, err := stageInputCopy(stageInput, targetRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	variantPipeline := *stage
+	variantPipeline.Config, variantPipeline.VendorProviders = profileConfig(tp.Config, variant.Profile)
+	variantRun, err := run.rebased(&variantPipeline, stageInput, target)
+	if err != nil {
+		return nil, err
+	}
+	if tp.DryRun {
+		variantRun.originals = nil // nothing is written, so nothing is stamped
+	}
+
+	results := &TransformationResults{Changed: []string{}}
+	if err := variantPipeline.applyProfileSteps(target, variantRun.opts, results); err != nil {
+		return nil, err
+	}
+	if _, err := variantPipeline.finishDirectoryRun(target, variantRun, results); err != nil {
+		return nil, err
+	}
+	if !tp.DryRun {
+		results.FormatOutputs, err = writeOutputFormats(target, tp.Config.OutputFormats, tp.Config.Files)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if tp.DryRun {
+		rebaseResults(results, target, variantTargetPath(inputPath, variant.Dir))
+	}
+
+	return &VariantResult{Profile: variant.Profile, Dir: variant.Dir, Results: results}, nil
+}
+
+// variantTargetPath returns where the input is written inside a variant directory
+func variantTargetPath(inputPath, dir string) string {
+	if info, err := os.Stat(inputPath); err == nil && !info.IsDir() {
+		return filepath.Join(dir, filepath.Base(inputPath))
+	}
+	return dir
+}
+
+// stageInputCopy copies inputPath (a file or directory) into dir and returns the path of the copy.
+// A file is copied as dir/<name>; a directory's contents are copied into dir.
+func stageInputCopy(inputPath, dir string) (string, error) {
+	info, err := os.Stat(inputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %v", err)
+	}
+
+	if !info.IsDir() {
+		target := filepath.Join(dir, filepath.Base(inputPath))
+		if err := copyFile(inputPath, target); err != nil {
+			return "", err
+		}
+		return target, nil
+	}
+
+	err = filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(inputPath, path)
+		if err != nil {
+			return err
+		}
+		return copyFile(path, filepath.Join(dir, rel))
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to copy input: %v", err)
+	}
+	return dir, nil
+}
+
+// copyFile copies a single file, creating parent directories as needed
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", src, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %v", dst, err)
+	}
+	if err := os.WriteFile(dst, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %v", dst, err)
+	}
+	return nil
+}
+
+// rebased returns the run for a copy of its input at to, such as an output variant. The copy gets
+// its own failures and metrics; the files that already failed are left alone in it too.
+func (run *directoryRun) rebased(tp *TransformationPipeline, from, to string) (*directoryRun, error) {
+	absFrom, err := filepath.Abs(from)
+	if err != nil {
+		return nil, err
+	}
+	absTo, err := filepath.Abs(to)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := run.opts
+	opts.failures = tp.newFileErrors()
+	opts.metrics = newFileMetrics()
+	for _, failure := range run.opts.failures.list() {
+		opts.failures.leaveAlone(rebasePath(failure.File, from, to))
+	}
+	return &directoryRun{
+		opts:            opts,
+		arazzoDocuments: rebasePaths(run.arazzoDocuments, from, to),
+		before:          rebaseMapKeys(run.before, absFrom, absTo), // operation snapshots are keyed by absolute path
+		originals:       rebaseMapKeys(run.originals, from, to),
+	}, nil
+}
+
+// rebasePath replaces the from prefix of path with to
+func rebasePath(path, from, to string) string {
+	if path == from {
+		return to
+	}
+	if rel, err := filepath.Rel(from, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join(to, rel)
+	}
+	return path
+}
+
+// rebasePaths rebases every path in files
+func rebasePaths(files []string, from, to string) []string {
+	rebased := make([]string, len(files))
+	for i, file := range files {
+		rebased[i] = rebasePath(file, from, to)
+	}
+	return rebased
+}
+
+// rebaseMapKeys rebases every file key of a result map
+func rebaseMapKeys[V any](originalMap map[string][]V, from, to string) map[string][]V {
+	if len(originalMap) == 0 {
+		return originalMap
+	}
+
+	rebased := make(map[string][]V, len(originalMap))
+	for file, values := range originalMap {
+		rebased[rebasePath(file, from, to)] = values
+	}
+	return rebased
+}
+
+// rebaseResults rewrites the file paths reported by every step from a working copy to where
+// the user expects them
+func rebaseResults(results *TransformationResults, from, to string) {
+	results.Changed = rebasePaths(results.Changed, from, to)
+	for i := range results.KeyChanges {
+		results.KeyChanges[i].File = rebasePath(results.KeyChanges[i].File, from, to)
+	}
+	rebase := func(path string) string { return rebasePath(path, from, to) }
+	for i := range results.SkippedFiles {
+		results.SkippedFiles[i].File = rebase(results.SkippedFiles[i].File)
+	}
+	for i := range results.ProtectedSkips {
+		results.ProtectedSkips[i].File = rebase(results.ProtectedSkips[i].File)
+	}
+	for i := range results.DanglingRefs {
+		results.DanglingRefs[i].File = rebase(results.DanglingRefs[i].File)
+	}
+	for i := range results.EmptySchemas {
+		results.EmptySchemas[i].File = rebase(results.EmptySchemas[i].File)
+	}
+	for i := range results.PrunedContainers {
+		results.PrunedContainers[i].File = rebase(results.PrunedContainers[i].File)
+	}
+	for i := range results.InvariantViolations {
+		results.InvariantViolations[i].File = rebase(results.InvariantViolations[i].File)
+	}
+	for i := range results.FileErrors {
+		results.FileErrors[i].File = rebase(results.FileErrors[i].File)
+	}
+	for i := range results.FileMetrics {
+		results.FileMetrics[i].File = rebase(results.FileMetrics[i].File)
+	}
+	results.StampedFiles = rebasePaths(results.StampedFiles, from, to)
+	results.FormatOutputs = rebasePaths(results.FormatOutputs, from, to)
+
+	if r := results.InternalResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.RemovedItems = rebaseMapKeys(r.RemovedItems, from, to)
+		r.PrunedComponents = rebaseMapKeys(r.PrunedComponents, from, to)
+		r.SecondaryRemovals = rebaseMapKeys(r.SecondaryRemovals, from, to)
+	}
+	if r := results.PaginationResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.RemovedParams = rebaseMapKeys(r.RemovedParams, from, to)
+		r.RemovedResponses = rebaseMapKeys(r.RemovedResponses, from, to)
+		r.ModifiedSchemas = rebaseMapKeys(r.ModifiedSchemas, from, to)
+		r.MergedParams = rebaseMapKeys(r.MergedParams, from, to)
+		r.MovedParams = rebaseMapKeys(r.MovedParams, from, to)
+		r.ScrubbedSentences = rebaseMapKeys(r.ScrubbedSentences, from, to)
+		r.RemovedLinks = rebaseMapKeys(r.RemovedLinks, from, to)
+		r.PageComponents = rebaseMapKeys(r.PageComponents, from, to)
+		r.Metadata = rebaseMapKeys(r.Metadata, from, to)
+		r.Headers = rebaseMapKeys(r.Headers, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+		for i := range r.UnresolvedRefs {
+			r.UnresolvedRefs[i].File = rebase(r.UnresolvedRefs[i].File)
+		}
+		for i := range r.DynamicRefs {
+			r.DynamicRefs[i].File = rebase(r.DynamicRefs[i].File)
+		}
+	}
+	if r := results.PathVariantsResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.MergedPaths = rebaseMapKeys(r.MergedPaths, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.TenantPathsResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.RewrittenPaths = rebaseMapKeys(r.RewrittenPaths, from, to)
+		r.ChangedComponents = rebaseMapKeys(r.ChangedComponents, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.FlattenResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.FlattenedRefs = rebaseMapKeys(r.FlattenedRefs, from, to)
+		r.RemovedComponents = rebaseMapKeys(r.RemovedComponents, from, to)
+		r.SkippedSchemas = rebaseMapKeys(r.SkippedSchemas, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.EnvelopeResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.UnwrappedResponses = rebaseMapKeys(r.UnwrappedResponses, from, to)
+		r.RemovedComponents = rebaseMapKeys(r.RemovedComponents, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.InjectionResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.InjectedParams = rebaseMapKeys(r.InjectedParams, from, to)
+		r.CreatedComponents = rebaseMapKeys(r.CreatedComponents, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.VendorResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.AddedExtensions = rebaseMapKeys(r.AddedExtensions, from, to)
+		r.SkippedOperations = rebaseMapKeys(r.SkippedOperations, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.ExtensionValuesResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.ReplacedValues = rebaseMapKeys(r.ReplacedValues, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.DefaultsResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.AppliedDefaults = rebaseMapKeys(r.AppliedDefaults, from, to)
+		r.SkippedTargets = rebaseMapKeys(r.SkippedTargets, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.ConstraintsResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.NormalizedConstraints = rebaseMapKeys(r.NormalizedConstraints, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.NullabilityResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.NullabilityChanges = rebaseMapKeys(r.NullabilityChanges, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.DedupResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.MergedComponents = rebaseMapKeys(r.MergedComponents, from, to)
+	}
+	if r := results.RenameResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.RenamedComponents = rebaseMapKeys(r.RenamedComponents, from, to)
+		r.SkippedRenames = rebaseMapKeys(r.SkippedRenames, from, to)
+	}
+	if r := results.SchemaTitlesResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.AddedTitles = rebaseMapKeys(r.AddedTitles, from, to)
+		r.NormalizedTitles = rebaseMapKeys(r.NormalizedTitles, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.ConsistencyResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.HarmonizedComponents = rebaseMapKeys(r.HarmonizedComponents, from, to)
+		r.SkippedComponents = rebaseMapKeys(r.SkippedComponents, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.ExamplesResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.RepairedExamples = rebaseMapKeys(r.RepairedExamples, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.CanonicalizeResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.SortedSections = rebaseMapKeys(r.SortedSections, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.LinkResult; r != nil {
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.UpdatedLinks = rebaseMapKeys(r.UpdatedLinks, from, to)
+		r.RemovedLinks = rebaseMapKeys(r.RemovedLinks, from, to)
+		r.Locations = rebaseLocations(r.Locations, rebase)
+	}
+	if r := results.ArazzoResult; r != nil {
+		r.Documents = rebasePaths(r.Documents, from, to)
+		r.ProcessedFiles = rebasePaths(r.ProcessedFiles, from, to)
+		r.UpdatedReferences = rebaseMapKeys(r.UpdatedReferences, from, to)
+	}
+}
@@ -0,0 +1,232 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/developerkunal/OpenMorph/internal/config"
+)
+
+const variantsTestSpec = `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-internal-owner: platform
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: array
+                    items:
+                      type: object
+`
+
+func variantsTestProvider(extension string) config.ProviderConfig {
+	return config.ProviderConfig{
+		ExtensionName: extension,
+		TargetLevel:   "operation",
+		Methods:       []string{"get"},
+		FieldMapping: config.FieldMapping{
+			RequestParams: map[string][]string{
+				"cursor": {"cursor"},
+				"limit":  {"limit"},
+			},
+		},
+		Strategies: map[string]config.StrategyConfig{
+			"cursor": {
+				Template: map[string]interface{}{
+					"type":         "cursor",
+					"cursor_param": "$request.{cursor_param}",
+					"results_path": "$response.{results_field}",
+				},
+				RequiredFields: []string{"cursor_param", "results_field"},
+			},
+		},
+	}
+}
+
+func variantsTestConfig() *config.Config {
+	return &config.Config{
+		Mappings: map[string]string{"x-internal-owner": "x-owner"},
+		VendorExtensions: config.VendorExtensions{
+			Enabled: true,
+			Providers: map[string]config.ProviderConfig{
+				"fern":      variantsTestProvider("x-fern-pagination"),
+				"speakeasy": variantsTestProvider("x-speakeasy-pagination"),
+			},
+		},
+	}
+}
+
+func TestExecuteVariants(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(input, []byte(variantsTestSpec), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	variants := []config.OutputVariant{
+		{Profile: "fern", Dir: filepath.Join(dir, "out", "fern")},
+		{Profile: "speakeasy", Dir: filepath.Join(dir, "out", "speakeasy")},
+		{Profile: OutputProfileNone, Dir: filepath.Join(dir, "out", "plain")},
+	}
+
+	pipeline := NewTransformationPipeline(variantsTestConfig(), nil, false, false, "")
+	results, err := pipeline.ExecuteVariants(input, variants)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results.Shared.Changed) != 1 || results.Shared.Changed[0] != input {
+		t.Errorf("expected shared mapping change reported against the input, got %v", results.Shared.Changed)
+	}
+	if len(results.Variants) != 3 {
+		t.Fatalf("expected 3 variant results, got %d", len(results.Variants))
+	}
+
+	expectations := map[string][]string{
+		"fern":      {"x-owner: platform", "x-fern-pagination:"},
+		"speakeasy": {"x-owner: platform", "x-speakeasy-pagination:"},
+		"plain":     {"x-owner: platform"},
+	}
+	unexpected := map[string][]string{
+		"fern":      {"x-speakeasy-pagination"},
+		"speakeasy": {"x-fern-pagination"},
+		"plain":     {"x-fern-pagination", "x-speakeasy-pagination"},
+	}
+
+	for name, wants := range expectations {
+		data, err := os.ReadFile(filepath.Join(dir, "out", name, "api.yaml"))
+		if err != nil {
+			t.Fatalf("failed to read %s output: %v", name, err)
+		}
+		output := string(data)
+		for _, want := range wants {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected %s output to contain %q\n%s", name, want, output)
+			}
+		}
+		for _, unwanted := range unexpected[name] {
+			if strings.Contains(output, unwanted) {
+				t.Errorf("expected %s output not to contain %q\n%s", name, unwanted, output)
+			}
+		}
+	}
+
+	original, err := os.ReadFile(input)
+	if err != nil {
+		t.Fatalf("failed to read input: %v", err)
+	}
+	if string(original) != variantsTestSpec {
+		t.Error("expected input to be left untouched")
+	}
+}
+
+func TestExecuteVariantsFinishEveryVariant(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(input, []byte(variantsTestSpec), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	cfg := variantsTestConfig()
+	cfg.Provenance = config.Provenance{Enabled: true, Mode: ProvenanceModeExtension}
+	outDir := filepath.Join(dir, "out", "fern")
+	pipeline := NewTransformationPipeline(cfg, nil, false, false, "")
+	results, err := pipeline.ExecuteVariants(input, []config.OutputVariant{{Profile: "fern", Dir: outDir}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := filepath.Join(outDir, "api.yaml")
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(data), ProvenanceExtension+":") {
+		t.Errorf("expected the variant to be stamped with its provenance\n%s", data)
+	}
+	if stamped := results.Variants[0].Results.StampedFiles; len(stamped) != 1 || stamped[0] != output {
+		t.Errorf("expected the stamp reported against the variant path, got %v", stamped)
+	}
+	if metrics := results.Variants[0].Results.FileMetrics; len(metrics) == 0 || metrics[0].File != output {
+		t.Errorf("expected the variant's file metrics reported against the variant path, got %v", metrics)
+	}
+}
+
+func TestExecuteVariantsDryRun(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(input, []byte(variantsTestSpec), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	outDir := filepath.Join(dir, "out", "fern")
+	pipeline := NewTransformationPipeline(variantsTestConfig(), nil, true, false, "")
+	results, err := pipeline.ExecuteVariants(input, []config.OutputVariant{{Profile: "fern", Dir: outDir}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+		t.Errorf("expected dry run not to create %s", outDir)
+	}
+
+	vendorResult := results.Variants[0].Results.VendorResult
+	if vendorResult == nil || !vendorResult.Changed {
+		t.Fatal("expected dry run to report vendor extension changes")
+	}
+	if _, ok := vendorResult.AddedExtensions[filepath.Join(outDir, "api.yaml")]; !ok {
+		t.Errorf("expected results reported against the variant path, got %v", vendorResult.AddedExtensions)
+	}
+}
+
+func TestValidateVariants(t *testing.T) {
+	tests := []struct {
+		name      string
+		variants  []config.OutputVariant
+		expectErr string
+	}{
+		{"no outputs", nil, "no outputs configured"},
+		{"missing dir", []config.OutputVariant{{Profile: "fern"}}, "dir is required"},
+		{"missing profile", []config.OutputVariant{{Dir: "out"}}, "profile is required"},
+		{"unknown profile", []config.OutputVariant{{Profile: "stainless", Dir: "out"}}, "unknown profile \"stainless\""},
+		{"duplicate dir", []config.OutputVariant{{Profile: "fern", Dir: "out"}, {Profile: "none", Dir: "out/"}}, "duplicate dir"},
+		{"valid", []config.OutputVariant{{Profile: "fern", Dir: "out/fern"}, {Profile: "none", Dir: "out/plain"}}, ""},
+	}
+
+	pipeline := NewTransformationPipeline(variantsTestConfig(), nil, false, false, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pipeline.validateVariants(tt.variants)
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("expected error containing %q, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
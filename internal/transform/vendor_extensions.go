@@ -2,13 +2,14 @@ package transform
 
 import (
 	"fmt"
-	"path/filepath"
+	"path"
 	"regexp"
 	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/developerkunal/OpenMorph/internal/condition"
 	"github.com/developerkunal/OpenMorph/internal/config"
 	"github.com/developerkunal/OpenMorph/internal/pagination"
 )
@@ -17,7 +18,9 @@ import (
 type VendorExtensionOptions struct {
 	Options
 	VendorExtensions config.VendorExtensions
-	EnabledProviders []string // specific providers to apply, empty means all
+	EnabledProviders []string          // specific providers to apply, empty means all
+	Placement        string            // where extensions are inserted, see ExtensionPlacementEnd
+	ResultsFields    map[string]string // path pattern -> response property holding the results
 }
 
 // VendorExtensionResult represents the result of vendor extension processing
@@ -25,7 +28,10 @@ type VendorExtensionResult struct {
 	Changed           bool
 	ProcessedFiles    []string
 	AddedExtensions   map[string][]string // file -> list of added extensions
-	SkippedOperations map[string][]string // file -> list of skipped operations with reasons
+	SkippedOperations map[string][]Skip   // file -> list of skipped operations with reasons
+	Locations         []ChangeLocation    // source positions of added extensions
+	OperationsTouched map[string]int      // provider -> operations that received its extension
+	ResultsFields     map[string][]string // file -> auto-detected results fields picked over other array fields
 }
 
 // createVendorExtensionResult creates a new VendorExtensionResult with initialized maps
@@ -33,7 +39,9 @@ func createVendorExtensionResult() *VendorExtensionResult {
 	return &VendorExtensionResult{
 		ProcessedFiles:    []string{},
 		AddedExtensions:   make(map[string][]string),
-		SkippedOperations: make(map[string][]string),
+		SkippedOperations: make(map[string][]Skip),
+		OperationsTouched: make(map[string]int),
+		ResultsFields:     make(map[string][]string),
 	}
 }
 
@@ -51,6 +59,8 @@ func setVendorExtensionChanged(result *VendorExtensionResult, changed bool) {
 func ProcessVendorExtensionsInDir(dir string, opts VendorExtensionOptions) (*VendorExtensionResult, error) {
 	return processTransformInDir(
 		dir,
+		StepVendorExtensions,
+		opts.Options,
 		opts.VendorExtensions.Enabled,
 		len(opts.VendorExtensions.Providers) == 0,
 		createVendorExtensionResult,
@@ -80,10 +90,14 @@ func processVendorExtensionsInFile(path string, opts VendorExtensionOptions, res
 
 // processDocumentVendorExtensions processes vendor extensions in a document
 func processDocumentVendorExtensions(doc, root *yaml.Node, path string, opts VendorExtensionOptions, result *VendorExtensionResult) (bool, error) {
-	changed := processVendorExtensionsInPaths(root, opts, path, result)
+	scoped := scopeToPaths(root, opts.Paths)
+	changed := processVendorExtensionsInPaths(scoped, opts, path, result)
+	if processTagGroupsInPaths(scoped, opts, path, result) {
+		changed = true
+	}
 
 	if changed {
-		return writeVendorExtensionsDocument(doc, path, opts.DryRun)
+		return writeStepDocument(opts.Options, doc, path)
 	}
 
 	return false, nil
@@ -119,14 +133,14 @@ func processVendorOperationsInPath(pathNode *yaml.Node, pathName string, opts Ve
 	changed := false
 
 	for j := 0; j < len(pathNode.Content); j += 2 {
-		operation := pathNode.Content[j].Value
+		operationKeyNode := pathNode.Content[j]
 		operationNode := pathNode.Content[j+1]
 
-		if !isHTTPMethod(operation) {
+		if !isHTTPMethod(operationKeyNode.Value) {
 			continue
 		}
 
-		if processVendorOperation(operation, operationNode, pathName, opts, root, filePath, result) {
+		if processVendorOperation(operationKeyNode, operationNode, pathName, opts, root, filePath, result) {
 			changed = true
 		}
 	}
@@ -135,20 +149,28 @@ func processVendorOperationsInPath(pathNode *yaml.Node, pathName string, opts Ve
 }
 
 // processVendorOperation processes a single operation for vendor extensions
-func processVendorOperation(operation string, operationNode *yaml.Node, pathName string, opts VendorExtensionOptions, root *yaml.Node, filePath string, result *VendorExtensionResult) bool {
+func processVendorOperation(operationKeyNode, operationNode *yaml.Node, pathName string, opts VendorExtensionOptions, root *yaml.Node, filePath string, result *VendorExtensionResult) bool {
 	changed := false
+	operation := operationKeyNode.Value
 	operationKey := fmt.Sprintf("%s %s", strings.ToUpper(operation), pathName)
 
 	// Process each enabled provider
-	for providerName, providerConfig := range opts.VendorExtensions.Providers {
+	for _, providerName := range sortedKeysOf(opts.VendorExtensions.Providers) {
+		providerConfig := opts.VendorExtensions.Providers[providerName]
 		// Skip if specific providers are requested and this isn't one of them
 		if len(opts.EnabledProviders) > 0 && !contains(opts.EnabledProviders, providerName) {
 			continue
 		}
+		if isTagGroupProvider(providerConfig) {
+			continue // Applied by processTagGroupsInPaths
+		}
+		if _, ok := result.OperationsTouched[providerName]; !ok {
+			result.OperationsTouched[providerName] = 0 // report providers that touched nothing too
+		}
 
 		// Check if operation matches provider criteria
-		if !operationMatchesProvider(operation, pathName, providerConfig) {
-			addSkippedOperation(result, filePath, operationKey, fmt.Sprintf("doesn't match %s provider criteria", providerName))
+		if !operationMatchesProvider(operation, pathName, operationNode, providerConfig) {
+			addSkippedOperation(result, filePath, operationKey, SkipProviderCriteria, fmt.Sprintf("doesn't match %s provider criteria", providerName))
 			continue
 		}
 
@@ -156,87 +178,133 @@ func processVendorOperation(operation string, operationNode *yaml.Node, pathName
 		params := getVendorNodeValue(operationNode, "parameters")
 		responses := getVendorNodeValue(operationNode, "responses")
 
+		if len(providerConfig.MediaTypes) > 0 && !returnsMediaType(responses, providerConfig.MediaTypes, root) {
+			addSkippedOperation(result, filePath, operationKey, SkipNoSuccessResponse, fmt.Sprintf("no %s success response for %s", strings.Join(providerConfig.MediaTypes, " or "), providerName))
+			continue
+		}
+
 		detected := pagination.DetectPaginationInParamsWithDoc(params, root)
 		if len(detected) == 0 {
-			addSkippedOperation(result, filePath, operationKey, fmt.Sprintf("no pagination detected for %s", providerName))
+			addSkippedOperation(result, filePath, operationKey, SkipNoPagination, fmt.Sprintf("no pagination detected for %s", providerName))
 			continue
 		}
 
 		// Try to add vendor extension for each detected strategy
+		touched := false
 		for _, paginationInfo := range detected {
-			if addVendorExtension(operationNode, paginationInfo, providerConfig, params, responses, root) {
+			added, ranked, missing := addVendorExtension(operationNode, paginationInfo, providerConfig, params, responses, root, pathName, pagination.ResultsField(opts.ResultsFields, pathName), opts.Placement)
+			if len(missing) > 0 {
+				addSkippedOperation(result, filePath, operationKey, SkipMissingFields, fmt.Sprintf("missing required fields %s for %s (%s strategy)", strings.Join(missing, ", "), providerName, paginationInfo.Strategy))
+			}
+			if added {
 				changed = true
-				addProcessedExtension(result, filePath, fmt.Sprintf("%s: %s (%s strategy)", operationKey, providerConfig.ExtensionName, paginationInfo.Strategy))
+				touched = true
+				extension := fmt.Sprintf("%s: %s (%s strategy)", operationKey, providerConfig.ExtensionName, paginationInfo.Strategy)
+				addProcessedExtension(result, filePath, extension)
+				result.Locations = append(result.Locations, newChangeLocation(filePath, StepVendorExtensions, operationKeyNode, extension))
+				if len(ranked) > 1 {
+					result.ResultsFields[filePath] = append(result.ResultsFields[filePath],
+						fmt.Sprintf("%s (%s): %s, over %s", operationKey, providerName, ranked[0], strings.Join(ranked[1:], ", ")))
+				}
 			}
 		}
+		if touched {
+			result.OperationsTouched[providerName]++
+		}
 	}
 
 	return changed
 }
 
-// operationMatchesProvider checks if an operation matches provider criteria
-func operationMatchesProvider(operation, pathName string, config config.ProviderConfig) bool {
-	// Check HTTP methods
-	if len(config.Methods) > 0 {
-		methodMatch := false
-		for _, method := range config.Methods {
-			if strings.EqualFold(operation, method) {
-				methodMatch = true
-				break
-			}
-		}
-		if !methodMatch {
-			return false
-		}
+// operationMatchesProvider checks if an operation matches provider criteria and is not excluded
+// by it. Path patterns are globs unless prefixed with regex:.
+func operationMatchesProvider(operation, pathName string, operationNode *yaml.Node, provider config.ProviderConfig) bool {
+	return providerCondition(provider).Matches(pathName, operation, operationNode)
+}
+
+// providerCondition returns the operation condition of a provider
+func providerCondition(provider config.ProviderConfig) condition.Condition {
+	return condition.Condition{
+		Paths:      provider.PathPatterns,
+		Methods:    provider.Methods,
+		Tags:       provider.Tags,
+		Extensions: provider.Extensions,
+		Has:        provider.HasExtension,
+		Missing:    provider.MissingExtension,
+		Syntax:     condition.Glob,
+
+		ExcludePaths:   provider.ExcludePaths,
+		ExcludeMethods: provider.ExcludeMethods,
 	}
+}
 
-	// Check path patterns
-	if len(config.PathPatterns) > 0 {
-		pathMatch := false
-		for _, pattern := range config.PathPatterns {
-			if matched, _ := filepath.Match(pattern, pathName); matched {
-				pathMatch = true
-				break
-			}
-			// Also try glob-style matching
-			if globMatch(pathName, pattern) {
-				pathMatch = true
-				break
-			}
+// returnsMediaType reports whether any 2xx response of an operation has content of one of the
+// given media types. Patterns such as application/* match any subtype, and media type parameters
+// such as charset are ignored.
+func returnsMediaType(responses *yaml.Node, mediaTypes []string, root *yaml.Node) bool {
+	if responses == nil || responses.Kind != yaml.MappingNode {
+		return false
+	}
+
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		if !strings.HasPrefix(responses.Content[i].Value, "2") {
+			continue
 		}
-		if !pathMatch {
-			return false
+		response := responses.Content[i+1]
+		if ref := getVendorStringValue(response, "$ref"); ref != "" {
+			response = resolveVendorRef(ref, root)
+		}
+		content := getVendorNodeValue(response, "content")
+		if content == nil || content.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j < len(content.Content); j += 2 {
+			mediaType, _, _ := strings.Cut(content.Content[j].Value, ";")
+			mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+			for _, pattern := range mediaTypes {
+				if matched, _ := path.Match(strings.ToLower(pattern), mediaType); matched {
+					return true
+				}
+			}
 		}
 	}
-
-	return true
+	return false
 }
 
-// addVendorExtension adds a vendor extension to an operation
-func addVendorExtension(operationNode *yaml.Node, paginationInfo pagination.DetectedPagination, config config.ProviderConfig, params, responses *yaml.Node, root *yaml.Node) bool {
+// addVendorExtension adds a vendor extension at placement to the operation at pathName, returning
+// the ranked array fields when its results field was auto-detected, or the required fields of the
+// strategy the operation lacks. A configured resultsField replaces whatever the provider's field
+// mapping or auto-detection would pick.
+func addVendorExtension(operationNode *yaml.Node, paginationInfo pagination.DetectedPagination, config config.ProviderConfig, params, responses *yaml.Node, root *yaml.Node, pathName, resultsField, placement string) (bool, []string, []string) {
 	strategyConfig, exists := config.Strategies[paginationInfo.Strategy]
 	if !exists {
-		return false
+		return false, nil, nil
 	}
 
 	// Build template context
-	context := buildTemplateContext(paginationInfo, config, params, responses, root)
+	context, ranked := buildTemplateContext(paginationInfo, config, params, responses, root, pathName)
+	if resultsField != "" {
+		context["results_field"] = resultsField
+		ranked = nil
+	}
 
 	// Check if we have required fields
-	if !hasRequiredFields(context, strategyConfig.RequiredFields) {
-		return false
+	if missing := missingRequiredFields(context, strategyConfig.RequiredFields); len(missing) > 0 {
+		return false, nil, missing
 	}
 
 	// Process template with context
 	processedTemplate := processTemplate(strategyConfig.Template, context)
 
-	// Add the vendor extension to the operation
-	return addExtensionToOperation(operationNode, config.ExtensionName, processedTemplate)
+	// Add the vendor extension to the operation, keeping the template's key order
+	return addExtensionNodeToOperation(operationNode, config.ExtensionName, createYAMLNodeInOrder(processedTemplate, strategyConfig.TemplateNode), placement), ranked, nil
 }
 
-// buildTemplateContext builds the context for template processing
-func buildTemplateContext(paginationInfo pagination.DetectedPagination, config config.ProviderConfig, params, responses *yaml.Node, root *yaml.Node) map[string]string {
+// buildTemplateContext builds the context for template processing of an operation at pathName. When
+// the results field is auto-detected, the array fields it was picked from are returned too, ranked.
+func buildTemplateContext(paginationInfo pagination.DetectedPagination, config config.ProviderConfig, params, responses *yaml.Node, root *yaml.Node, pathName string) (map[string]string, []string) {
 	context := make(map[string]string)
+	var ranked []string
 
 	// Map request parameters
 	if params != nil {
@@ -267,16 +335,15 @@ func buildTemplateContext(paginationInfo pagination.DetectedPagination, config c
 
 		// Auto-detect results fields if not found in config
 		if _, hasResults := context["results_field"]; !hasResults {
-			// Look for array fields in response schemas
-			arrayFields := extractArrayFieldsFromResponses(responses, root)
-			if len(arrayFields) > 0 {
-				// Use the first array field found as the results field
-				context["results_field"] = arrayFields[0]
+			// Rank the array fields in response schemas, the most likely results first
+			ranked = rankResultsFields(extractArrayFieldsFromResponses(responses, root), pathName, config.FieldMapping.ResultsRanking)
+			if len(ranked) > 0 {
+				context["results_field"] = ranked[0]
 			}
 		}
 	}
 
-	return context
+	return context, ranked
 }
 
 // processTemplate processes a template with the given context
@@ -294,27 +361,28 @@ func processTemplate(template map[string]interface{}, context map[string]string)
 	return result
 }
 
-// substituteTemplate substitutes template variables like $request.{cursor_param}
+// substituteTemplate substitutes template variables like $request.{cursor_param}, passing their
+// values through the filters of the placeholder, as in $response.{results_field|camel}. Variables
+// missing from the context are left as written.
 func substituteTemplate(template string, context map[string]string) string {
-	// Replace $request.{param_name} and $response.{field_name}
-	re := regexp.MustCompile(`\$(request|response)\.{([^}]+)}`)
-
-	return re.ReplaceAllStringFunc(template, func(match string) string {
-		// Extract the prefix (request/response) and variable name from {variable_name}
-		parts := re.FindStringSubmatch(match)
-		if len(parts) > 2 {
-			prefix := parts[1]  // "request" or "response"
-			varName := parts[2] // "cursor_param", "results_field", etc.
-			if value, exists := context[varName]; exists {
-				return fmt.Sprintf("$%s.%s", prefix, value)
-			}
+	return templateVariable.ReplaceAllStringFunc(template, func(match string) string {
+		parts := templateVariable.FindStringSubmatch(match)
+		value, exists := context[parts[1]]
+		if !exists {
+			return match
 		}
-		return match // Return unchanged if no substitution found
+		return applyTemplateFilters(value, parts[2])
 	})
 }
 
-// addExtensionToOperation adds a vendor extension to an operation node
+// addExtensionToOperation adds a vendor extension to the end of an operation node
 func addExtensionToOperation(operationNode *yaml.Node, extensionName string, extensionValue map[string]interface{}) bool {
+	return addExtensionNodeToOperation(operationNode, extensionName, createYAMLNodeFromMap(extensionValue), ExtensionPlacementEnd)
+}
+
+// addExtensionNodeToOperation adds a vendor extension with an already built value to an operation
+// node at placement
+func addExtensionNodeToOperation(operationNode *yaml.Node, extensionName string, valueNode *yaml.Node, placement string) bool {
 	if operationNode.Kind != yaml.MappingNode {
 		return false
 	}
@@ -327,41 +395,53 @@ func addExtensionToOperation(operationNode *yaml.Node, extensionName string, ext
 		}
 	}
 
-	// Create extension key node
-	keyNode := &yaml.Node{
-		Kind:  yaml.ScalarNode,
-		Value: extensionName,
-	}
-
-	// Create extension value node
-	valueNode := createYAMLNodeFromMap(extensionValue)
-
-	// Add to operation
-	operationNode.Content = append(operationNode.Content, keyNode, valueNode)
-
+	insertExtension(operationNode, extensionName, valueNode, placement)
 	return true
 }
 
 // createYAMLNodeFromMap creates a YAML node from a map with consistent key ordering
 func createYAMLNodeFromMap(data map[string]interface{}) *yaml.Node {
+	return createYAMLNodeInOrder(data, nil)
+}
+
+// createYAMLNodeInOrder creates a YAML node from a map, writing keys in the order of the matching
+// mapping in order (the template as written in the config). Keys order does not list, and every
+// key when order is nil, follow in the pagination field order.
+func createYAMLNodeInOrder(data map[string]interface{}, order *yaml.Node) *yaml.Node {
 	node := &yaml.Node{Kind: yaml.MappingNode}
 
-	// Sort keys with custom ordering for pagination fields
+	// Keys listed in the template come first, in template order
 	var keys []string
+	templateKeys := make(map[string]*yaml.Node)
+	if order != nil && order.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(order.Content); i += 2 {
+			key := order.Content[i].Value
+			if _, ok := data[key]; ok && templateKeys[key] == nil {
+				keys = append(keys, key)
+				templateKeys[key] = order.Content[i+1]
+			}
+		}
+	}
+
+	// Sort the remaining keys with custom ordering for pagination fields
+	var rest []string
 	for key := range data {
-		keys = append(keys, key)
+		if templateKeys[key] == nil {
+			rest = append(rest, key)
+		}
 	}
-	sort.Slice(keys, func(i, j int) bool {
-		orderI := getPaginationFieldOrder(keys[i])
-		orderJ := getPaginationFieldOrder(keys[j])
+	sort.Slice(rest, func(i, j int) bool {
+		orderI := getPaginationFieldOrder(rest[i])
+		orderJ := getPaginationFieldOrder(rest[j])
 
 		// If orders are the same, sort alphabetically
 		if orderI == orderJ {
-			return keys[i] < keys[j]
+			return rest[i] < rest[j]
 		}
 
 		return orderI < orderJ
 	})
+	keys = append(keys, rest...)
 
 	// Add keys and values in sorted order
 	for _, key := range keys {
@@ -378,6 +458,9 @@ func createYAMLNodeFromMap(data map[string]interface{}) *yaml.Node {
 				Kind:  yaml.ScalarNode,
 				Value: strValue,
 			}
+		} else if mapValue, ok := value.(map[string]interface{}); ok && templateKeys[key] != nil {
+			// Nested template mappings keep their order too
+			valueNode = createYAMLNodeInOrder(mapValue, templateKeys[key])
 		} else {
 			// For more complex values, marshal and unmarshal
 			valueNode = &yaml.Node{}
@@ -468,12 +551,18 @@ func extractResponseFields(responses *yaml.Node, root *yaml.Node) []string {
 }
 
 func hasRequiredFields(context map[string]string, requiredFields []string) bool {
+	return len(missingRequiredFields(context, requiredFields)) == 0
+}
+
+// missingRequiredFields returns the required fields the template context lacks, in order
+func missingRequiredFields(context map[string]string, requiredFields []string) []string {
+	var missing []string
 	for _, field := range requiredFields {
 		if _, exists := context[field]; !exists {
-			return false
+			missing = append(missing, field)
 		}
 	}
-	return true
+	return missing
 }
 
 func addProcessedExtension(result *VendorExtensionResult, filePath, extension string) {
@@ -483,11 +572,11 @@ func addProcessedExtension(result *VendorExtensionResult, filePath, extension st
 	result.AddedExtensions[filePath] = append(result.AddedExtensions[filePath], extension)
 }
 
-func addSkippedOperation(result *VendorExtensionResult, filePath, operation, reason string) {
+func addSkippedOperation(result *VendorExtensionResult, filePath, operation string, code SkipCode, reason string) {
 	if result.SkippedOperations[filePath] == nil {
-		result.SkippedOperations[filePath] = []string{}
+		result.SkippedOperations[filePath] = []Skip{}
 	}
-	result.SkippedOperations[filePath] = append(result.SkippedOperations[filePath], fmt.Sprintf("%s: %s", operation, reason))
+	result.SkippedOperations[filePath] = append(result.SkippedOperations[filePath], Skip{Message: fmt.Sprintf("%s: %s", operation, reason), Code: code})
 }
 
 func contains(slice []string, item string) bool {
@@ -499,23 +588,6 @@ func contains(slice []string, item string) bool {
 	return false
 }
 
-func globMatch(path, pattern string) bool {
-	// Simple glob matching for patterns like "/api/v1/*"
-	if strings.HasSuffix(pattern, "*") {
-		prefix := strings.TrimSuffix(pattern, "*")
-		return strings.HasPrefix(path, prefix)
-	}
-	return path == pattern
-}
-
-func writeVendorExtensionsDocument(doc *yaml.Node, path string, dryRun bool) (bool, error) {
-	if dryRun {
-		return true, nil // Return true to indicate changes were detected, but don't write
-	}
-
-	return writeModifiedDocument(doc, path)
-}
-
 // Reuse existing helper functions from pagination.go
 func getVendorNodeValue(node *yaml.Node, key string) *yaml.Node {
 	if node == nil || node.Kind != yaml.MappingNode {
@@ -648,8 +720,8 @@ func extractFieldsFromCompositionWithDoc(composition *yaml.Node, doc *yaml.Node)
 }
 
 // extractArrayFieldsFromResponses extracts all array fields from response schemas
-func extractArrayFieldsFromResponses(responses *yaml.Node, root *yaml.Node) []string {
-	var arrayFields []string
+func extractArrayFieldsFromResponses(responses *yaml.Node, root *yaml.Node) []arrayField {
+	var arrayFields []arrayField
 
 	if responses == nil || responses.Kind != yaml.MappingNode {
 		return arrayFields
@@ -670,8 +742,8 @@ func extractArrayFieldsFromResponses(responses *yaml.Node, root *yaml.Node) []st
 }
 
 // extractArrayFieldsFromResponseWithDoc extracts array fields from a response node
-func extractArrayFieldsFromResponseWithDoc(response *yaml.Node, doc *yaml.Node) []string {
-	var arrayFields []string
+func extractArrayFieldsFromResponseWithDoc(response *yaml.Node, doc *yaml.Node) []arrayField {
+	var arrayFields []arrayField
 
 	content := getVendorNodeValue(response, "content")
 	if content == nil {
@@ -693,8 +765,8 @@ func extractArrayFieldsFromResponseWithDoc(response *yaml.Node, doc *yaml.Node)
 }
 
 // extractArrayFieldsFromSchemaWithDoc extracts array fields from a schema node
-func extractArrayFieldsFromSchemaWithDoc(schema *yaml.Node, doc *yaml.Node) []string {
-	var arrayFields []string
+func extractArrayFieldsFromSchemaWithDoc(schema *yaml.Node, doc *yaml.Node) []arrayField {
+	var arrayFields []arrayField
 
 	if schema == nil || schema.Kind != yaml.MappingNode {
 		return arrayFields
@@ -726,8 +798,8 @@ func extractArrayFieldsFromSchemaWithDoc(schema *yaml.Node, doc *yaml.Node) []st
 }
 
 // extractArrayFieldsFromProperties extracts array fields from a properties node
-func extractArrayFieldsFromProperties(properties *yaml.Node, doc *yaml.Node) []string {
-	var arrayFields []string
+func extractArrayFieldsFromProperties(properties *yaml.Node, doc *yaml.Node) []arrayField {
+	var arrayFields []arrayField
 
 	if properties == nil || properties.Kind != yaml.MappingNode {
 		return arrayFields
@@ -738,7 +810,7 @@ func extractArrayFieldsFromProperties(properties *yaml.Node, doc *yaml.Node) []s
 		fieldSchema := properties.Content[i+1]
 
 		if isArrayField(fieldSchema, doc) {
-			arrayFields = append(arrayFields, fieldName)
+			arrayFields = append(arrayFields, arrayField{Name: fieldName, ObjectItems: hasObjectItems(fieldSchema, doc)})
 		}
 	}
 
@@ -746,8 +818,8 @@ func extractArrayFieldsFromProperties(properties *yaml.Node, doc *yaml.Node) []s
 }
 
 // extractArrayFieldsFromCompositionWithDoc extracts array fields from composition schemas (oneOf, anyOf, allOf)
-func extractArrayFieldsFromCompositionWithDoc(composition *yaml.Node, doc *yaml.Node) []string {
-	var arrayFields []string
+func extractArrayFieldsFromCompositionWithDoc(composition *yaml.Node, doc *yaml.Node) []arrayField {
+	var arrayFields []arrayField
 
 	if composition == nil || composition.Kind != yaml.SequenceNode {
 		return arrayFields
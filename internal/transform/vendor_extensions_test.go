@@ -28,6 +28,17 @@ func parseYAMLToNode(t *testing.T, yamlContent string) *yaml.Node {
 	return &doc
 }
 
+// writeTestSpec writes spec to api.yaml in a fresh temporary directory and returns both paths
+func writeTestSpec(t *testing.T, spec string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	return dir, path
+}
+
 func TestProcessVendorExtensionsInDir(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -226,11 +237,40 @@ func TestOperationMatchesProvider(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name:      "excluded path",
+			operation: "get",
+			pathName:  "/api/exports/users",
+			config: config.ProviderConfig{
+				PathPatterns: []string{"/api/**"},
+				ExcludePaths: []string{"/api/exports/**"},
+			},
+			expected: false,
+		},
+		{
+			name:      "path not excluded",
+			operation: "get",
+			pathName:  "/api/users",
+			config: config.ProviderConfig{
+				ExcludePaths: []string{"/api/exports/**"},
+			},
+			expected: true,
+		},
+		{
+			name:      "excluded method",
+			operation: "POST",
+			pathName:  "/api/users/bulk",
+			config: config.ProviderConfig{
+				Methods:        []string{"*"},
+				ExcludeMethods: []string{"post"},
+			},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := operationMatchesProvider(tt.operation, tt.pathName, tt.config)
+			result := operationMatchesProvider(tt.operation, tt.pathName, nil, tt.config)
 			if result != tt.expected {
 				t.Errorf("expected %v, got %v", tt.expected, result)
 			}
@@ -332,7 +372,7 @@ func TestBuildTemplateContext(t *testing.T) {
 				responsesNode = parseYAMLToNode(t, tt.responses)
 			}
 
-			result := buildTemplateContext(tt.paginationInfo, tt.config, paramsNode, responsesNode, nil)
+			result, _ := buildTemplateContext(tt.paginationInfo, tt.config, paramsNode, responsesNode, nil, "/users")
 
 			for key, expectedValue := range tt.expected {
 				if result[key] != expectedValue {
@@ -390,6 +430,21 @@ func TestSubstituteTemplate(t *testing.T) {
 			},
 			expected: "$request.{missing_param}",
 		},
+		{
+			name:     "filters",
+			template: "$request.{cursor_param|camel} {results_field|singular|pascal}",
+			context: map[string]string{
+				"cursor_param":  "next_cursor",
+				"results_field": "user_entries",
+			},
+			expected: "$request.nextCursor UserEntry",
+		},
+		{
+			name:     "unknown variable with filter",
+			template: "{missing|upper}",
+			context:  map[string]string{},
+			expected: "{missing|upper}",
+		},
 		{
 			name:     "no substitution needed",
 			template: "static string",
@@ -546,55 +601,6 @@ func TestContains(t *testing.T) {
 	}
 }
 
-func TestGlobMatch(t *testing.T) {
-	tests := []struct {
-		name     string
-		path     string
-		pattern  string
-		expected bool
-	}{
-		{
-			name:     "wildcard pattern matches",
-			path:     "/api/v1/users",
-			pattern:  "/api/v1/*",
-			expected: true,
-		},
-		{
-			name:     "wildcard pattern does not match",
-			path:     "/other/path",
-			pattern:  "/api/v1/*",
-			expected: false,
-		},
-		{
-			name:     "exact match",
-			path:     "/api/users",
-			pattern:  "/api/users",
-			expected: true,
-		},
-		{
-			name:     "exact no match",
-			path:     "/api/users",
-			pattern:  "/api/posts",
-			expected: false,
-		},
-		{
-			name:     "partial prefix without wildcard",
-			path:     "/api/v1/users",
-			pattern:  "/api/v1",
-			expected: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := globMatch(tt.path, tt.pattern)
-			if result != tt.expected {
-				t.Errorf("expected %v, got %v", tt.expected, result)
-			}
-		})
-	}
-}
-
 func TestExtractParameterNames(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -879,17 +885,17 @@ func TestAddProcessedExtension(t *testing.T) {
 
 func TestAddSkippedOperation(t *testing.T) {
 	result := &VendorExtensionResult{
-		SkippedOperations: make(map[string][]string),
+		SkippedOperations: make(map[string][]Skip),
 	}
 
-	addSkippedOperation(result, "file1.yaml", "GET /users", "no pagination detected")
-	addSkippedOperation(result, "file1.yaml", "POST /users", "method not supported")
+	addSkippedOperation(result, "file1.yaml", "GET /users", SkipNoPagination, "no pagination detected")
+	addSkippedOperation(result, "file1.yaml", "POST /users", SkipProviderCriteria, "method not supported")
 
 	if len(result.SkippedOperations["file1.yaml"]) != 2 {
 		t.Errorf("expected 2 skipped operations for file1.yaml, got %d", len(result.SkippedOperations["file1.yaml"]))
 	}
 
-	expected := "GET /users: no pagination detected"
+	expected := Skip{Message: "GET /users: no pagination detected", Code: SkipNoPagination}
 	if result.SkippedOperations["file1.yaml"][0] != expected {
 		t.Errorf("expected %q, got %q", expected, result.SkippedOperations["file1.yaml"][0])
 	}
@@ -1004,7 +1010,7 @@ responses:
 			paramsNode := parseYAMLToNode(t, tt.paramsYAML)
 			responsesNode := parseYAMLToNode(t, tt.responsesYAML)
 
-			result := addVendorExtension(operationNode, tt.paginationInfo, tt.config, paramsNode, responsesNode, nil)
+			result, _, _ := addVendorExtension(operationNode, tt.paginationInfo, tt.config, paramsNode, responsesNode, nil, "/users", "", ExtensionPlacementEnd)
 
 			if result != tt.expectAdded {
 				t.Errorf("expected %v, got %v", tt.expectAdded, result)
@@ -1120,7 +1126,7 @@ info:
 	}
 }
 
-func TestWriteVendorExtensionsDocument(t *testing.T) {
+func TestWriteStepDocument(t *testing.T) {
 	tempDir := t.TempDir()
 	testFile := filepath.Join(tempDir, "test.yaml")
 
@@ -1166,7 +1172,7 @@ info:
 				Content: []*yaml.Node{doc},
 			}
 
-			changed, err := writeVendorExtensionsDocument(fullDoc, testFile, tt.dryRun)
+			changed, err := writeStepDocument(Options{DryRun: tt.dryRun}, fullDoc, testFile)
 
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
@@ -1231,3 +1237,157 @@ func TestConsistentPaginationOrdering(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateYAMLNodeInTemplateOrder(t *testing.T) {
+	var strategy config.StrategyConfig
+	template := `template:
+  type: cursor
+  next_cursor: $response.{next_field}
+  results: $response.{results_field}
+  cursor: $request.{cursor_param}
+  meta:
+    z: 1
+    a: 2
+`
+	if err := yaml.Unmarshal([]byte(template), &strategy); err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]interface{}{
+		"results":     "$response.items",
+		"cursor":      "$request.after",
+		"next_cursor": "$response.next",
+		"type":        "cursor",
+		"meta":        map[string]interface{}{"a": 2, "z": 1},
+		"added":       "not in template",
+	}
+	node := createYAMLNodeInOrder(data, strategy.TemplateNode)
+
+	var keys []string
+	for i := 0; i < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	if want := "type next_cursor results cursor meta added"; strings.Join(keys, " ") != want {
+		t.Errorf("expected keys in template order %q, got %q", want, strings.Join(keys, " "))
+	}
+	if meta := node.Content[9]; meta.Content[0].Value != "z" || meta.Content[2].Value != "a" {
+		t.Errorf("expected nested mapping in template order, got %v then %v", meta.Content[0].Value, meta.Content[2].Value)
+	}
+}
+
+func TestReturnsMediaType(t *testing.T) {
+	var doc yaml.Node
+	spec := `responses:
+  "200":
+    $ref: '#/components/responses/Export'
+  "400":
+    description: Bad request
+    content:
+      application/json: {}
+components:
+  responses:
+    Export:
+      description: Export
+      content:
+        text/csv; charset=utf-8: {}
+        application/vnd.api+json: {}
+`
+	if err := yaml.Unmarshal([]byte(spec), &doc); err != nil {
+		t.Fatal(err)
+	}
+	root := doc.Content[0]
+	responses := getVendorNodeValue(root, "responses")
+
+	tests := []struct {
+		mediaTypes []string
+		expected   bool
+	}{
+		{[]string{"application/json"}, false},
+		{[]string{"TEXT/CSV"}, true},
+		{[]string{"application/*"}, true},
+		{[]string{"application/octet-stream", "application/vnd.api+json"}, true},
+	}
+	for _, tt := range tests {
+		if got := returnsMediaType(responses, tt.mediaTypes, root); got != tt.expected {
+			t.Errorf("returnsMediaType(%v) = %v, want %v", tt.mediaTypes, got, tt.expected)
+		}
+	}
+}
+
+func TestVendorExtensionsMediaTypes(t *testing.T) {
+	dir := t.TempDir()
+	spec := `openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: array
+                    items:
+                      type: object
+  /users/export:
+    get:
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: CSV export
+          content:
+            text/csv:
+              schema:
+                type: string
+`
+	path := filepath.Join(dir, "api.yaml")
+	if err := os.WriteFile(path, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessVendorExtensionsInDir(dir, VendorExtensionOptions{
+		VendorExtensions: config.VendorExtensions{
+			Enabled: true,
+			Providers: map[string]config.ProviderConfig{
+				"fern": {
+					ExtensionName: "x-fern-pagination",
+					MediaTypes:    []string{"application/json"},
+					FieldMapping: config.FieldMapping{
+						RequestParams: map[string][]string{"cursor": {"cursor"}},
+					},
+					Strategies: map[string]config.StrategyConfig{
+						"cursor": {
+							Template:       map[string]interface{}{"type": "cursor", "cursor_param": "$request.{cursor_param}"},
+							RequiredFields: []string{"cursor_param"},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(result.AddedExtensions[path], "\n"); got != "GET /users: x-fern-pagination (cursor strategy)" {
+		t.Errorf("expected only GET /users to get the extension, got %q", got)
+	}
+	if got := strings.Join(SkipMessages(result.SkippedOperations[path]), "\n"); !strings.Contains(got, "GET /users/export: no application/json success response for fern") {
+		t.Errorf("expected the CSV export to be skipped, got %q", got)
+	}
+}
@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MappingCandidate is an unmapped extension key offered for mapping in the discovery wizard
+type MappingCandidate struct {
+	Key        string
+	Count      int      // number of occurrences in the specs
+	Samples    []string // a few occurrences, as "file:line:column value"
+	Suggestion string   // suggested replacement, filled in with tab
+}
+
+// DiscoverModel is the state of the mapping discovery wizard: one candidate at a time, with an input
+// for its replacement key
+type DiscoverModel struct {
+	Candidates []MappingCandidate
+	Index      int               // current candidate
+	Accepted   map[string]string // key -> replacement the user accepted
+	Quitting   bool
+	Aborted    bool // ctrl+c: nothing is saved
+	Input      textinput.Model
+	err        string // validation message for the current input
+}
+
+var (
+	sampleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// NewDiscoverModel creates the wizard for the candidates
+func NewDiscoverModel(candidates []MappingCandidate) DiscoverModel {
+	input := textinput.New()
+	input.Placeholder = "replacement key, empty to skip"
+	input.Prompt = "→ "
+	input.Focus()
+	return DiscoverModel{
+		Candidates: candidates,
+		Accepted:   make(map[string]string),
+		Input:      input,
+	}
+}
+
+func (DiscoverModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m DiscoverModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			m.Aborted = true
+			m.Quitting = true
+			return m, tea.Quit
+		case "esc":
+			m.Quitting = true
+			return m, tea.Quit
+		case "enter":
+			return m.submit()
+		case "tab":
+			if len(m.Candidates) > 0 && m.Candidates[m.Index].Suggestion != "" {
+				m.Input.SetValue(m.Candidates[m.Index].Suggestion)
+				m.Input.CursorEnd()
+			}
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	return m, cmd
+}
+
+// submit accepts the typed replacement for the current candidate, or skips it when the input is
+// empty, and moves on to the next candidate or quits after the last one
+func (m DiscoverModel) submit() (tea.Model, tea.Cmd) {
+	if len(m.Candidates) == 0 {
+		m.Quitting = true
+		return m, tea.Quit
+	}
+	value := strings.TrimSpace(m.Input.Value())
+	key := m.Candidates[m.Index].Key
+	if value != "" {
+		if value == key {
+			m.err = "the replacement must differ from the key"
+			return m, nil
+		}
+		m.Accepted[key] = value
+	}
+
+	m.err = ""
+	m.Input.Reset()
+	if m.Index == len(m.Candidates)-1 {
+		m.Quitting = true
+		return m, tea.Quit
+	}
+	m.Index++
+	return m, nil
+}
+
+func (m DiscoverModel) View() string {
+	if m.Aborted {
+		return "Aborted, no mappings saved.\n"
+	}
+	if m.Quitting {
+		return fmt.Sprintf("%d mappings accepted.\n", len(m.Accepted))
+	}
+	if len(m.Candidates) == 0 {
+		return headerStyle.Render("No unmapped extension keys found.")
+	}
+
+	candidate := m.Candidates[m.Index]
+	var b strings.Builder
+	b.WriteString(progressBarStyle.Render(fmt.Sprintf("Key %d/%d | Accepted: %d\n", m.Index+1, len(m.Candidates), len(m.Accepted))))
+	b.WriteString(headerStyle.Render(fmt.Sprintf("%s (%d occurrences)", candidate.Key, candidate.Count)))
+	b.WriteString("\n\n")
+	for _, sample := range candidate.Samples {
+		b.WriteString(sampleStyle.Render("  " + sample))
+		b.WriteString("\n")
+	}
+	if candidate.Suggestion != "" {
+		b.WriteString("\n")
+		b.WriteString(newKeyStyle.Render("Suggestion: " + candidate.Suggestion + " (tab to use)"))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.Input.View())
+	b.WriteString("\n")
+	if m.err != "" {
+		b.WriteString(errorStyle.Render(m.err))
+		b.WriteString("\n")
+	}
+	b.WriteString(footerStyle.Render("[enter] accept (empty skips)  [tab] use suggestion  [esc] finish  [ctrl+c] abort"))
+	return b.String()
+}
+
+// RunDiscover launches the mapping discovery wizard and returns the accepted key -> replacement
+// pairs, none when the user aborted
+func RunDiscover(candidates []MappingCandidate) (map[string]string, error) {
+	p := tea.NewProgram(NewDiscoverModel(candidates))
+	final, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+	model, ok := final.(DiscoverModel)
+	if !ok {
+		return nil, fmt.Errorf("unexpected model type")
+	}
+	if model.Aborted {
+		return nil, nil
+	}
+	return model.Accepted, nil
+}
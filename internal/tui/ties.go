@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TieChoice is an operation whose pagination strategy the user picks in the tie resolver
+type TieChoice struct {
+	Operation string   // e.g. "GET /users"
+	Position  string   // file:line:column of the operation
+	Reason    string   // why the config does not decide it
+	Evidence  []string // what was detected of each strategy
+	Choices   []string // strategies to pick from
+}
+
+// TieModel is the state of the tie resolver: one operation at a time, with a cursor over its
+// strategies
+type TieModel struct {
+	Ties     []TieChoice
+	Index    int            // current operation
+	Cursor   int            // highlighted strategy of the current operation
+	Picked   map[int]string // operation index -> picked strategy
+	Quitting bool
+	Aborted  bool // ctrl+c: nothing is saved
+}
+
+// NewTieModel creates the resolver for the ties
+func NewTieModel(ties []TieChoice) TieModel {
+	return TieModel{Ties: ties, Picked: make(map[int]string)}
+}
+
+func (TieModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m TieModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "ctrl+c":
+		m.Aborted = true
+		m.Quitting = true
+		return m, tea.Quit
+	case "esc", "q":
+		m.Quitting = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if len(m.Ties) > 0 && m.Cursor < len(m.Ties[m.Index].Choices)-1 {
+			m.Cursor++
+		}
+	case "enter":
+		if len(m.Ties) > 0 {
+			m.Picked[m.Index] = m.Ties[m.Index].Choices[m.Cursor]
+		}
+		return m.next()
+	case "s":
+		return m.next()
+	}
+	return m, nil
+}
+
+// next moves on to the next operation, or quits after the last one
+func (m TieModel) next() (tea.Model, tea.Cmd) {
+	if m.Index >= len(m.Ties)-1 {
+		m.Quitting = true
+		return m, tea.Quit
+	}
+	m.Index++
+	m.Cursor = 0
+	return m, nil
+}
+
+func (m TieModel) View() string {
+	if m.Aborted {
+		return "Aborted, no rules saved.\n"
+	}
+	if m.Quitting {
+		return fmt.Sprintf("%d operations resolved.\n", len(m.Picked))
+	}
+	if len(m.Ties) == 0 {
+		return headerStyle.Render("No pagination ties found.")
+	}
+
+	tie := m.Ties[m.Index]
+	var b strings.Builder
+	b.WriteString(progressBarStyle.Render(fmt.Sprintf("Operation %d/%d | Resolved: %d\n", m.Index+1, len(m.Ties), len(m.Picked))))
+	b.WriteString(headerStyle.Render(tie.Operation))
+	b.WriteString("\n")
+	b.WriteString(sampleStyle.Render("  " + tie.Position))
+	b.WriteString("\n\n")
+	b.WriteString(tie.Reason)
+	b.WriteString("\n\n")
+	for _, evidence := range tie.Evidence {
+		b.WriteString(sampleStyle.Render("  " + evidence))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	for i, choice := range tie.Choices {
+		if i == m.Cursor {
+			b.WriteString(newKeyStyle.Render("> " + choice))
+		} else {
+			b.WriteString("  " + choice)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(footerStyle.Render("[↑/↓] choose  [enter] pick  [s] skip  [esc] finish  [ctrl+c] abort"))
+	return b.String()
+}
+
+// RunTieResolver launches the tie resolver and returns the picked strategy by index of the ties,
+// none when the user aborted
+func RunTieResolver(ties []TieChoice) (map[int]string, error) {
+	p := tea.NewProgram(NewTieModel(ties))
+	final, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+	model, ok := final.(TieModel)
+	if !ok {
+		return nil, fmt.Errorf("unexpected model type")
+	}
+	if model.Aborted {
+		return nil, nil
+	}
+	return model.Picked, nil
+}
@@ -26,9 +26,17 @@ type FileDiff struct {
 type changeItem struct {
 	oldKey, newKey   string // The old and new key names
 	oldLine, newLine string // The full before/after block (may be multi-line)
+	line, column     int    // 1-based position of the key in the file, 0 if unknown
+}
+
+func (i changeItem) Title() string {
+	title := i.oldKey + " → " + i.newKey
+	if i.line > 0 {
+		title += fmt.Sprintf("  (%d:%d)", i.line, i.column)
+	}
+	return title
 }
 
-func (i changeItem) Title() string       { return i.oldKey + " → " + i.newKey }
 func (i changeItem) Description() string { return "- " + i.oldLine + "\n+ " + i.newLine }
 func (i changeItem) FilterValue() string { return i.oldKey + i.newKey + i.oldLine }
 
@@ -245,6 +253,8 @@ func extractKeyBlocks(lines []string, keyChange transform.KeyChange) []changeIte
 					newKey:  keyChange.NewKey,
 					oldLine: strings.Join(oldBlock, "\n"),
 					newLine: strings.Join(newBlock, "\n"),
+					line:    i + 1,
+					column:  strings.Index(line, variant) + 1,
 				})
 			}
 		}
@@ -255,7 +265,7 @@ func extractKeyBlocks(lines []string, keyChange transform.KeyChange) []changeIte
 func getChangeItems(f FileDiff) []list.Item {
 	if len(f.KeyChanges) == 0 {
 		return []list.Item{
-			changeItem{"(no key)", "(no key)", "(no matching line found)", "(no matching line found)"},
+			changeItem{oldKey: "(no key)", newKey: "(no key)", oldLine: "(no matching line found)", newLine: "(no matching line found)"},
 		}
 	}
 
@@ -263,7 +273,14 @@ func getChangeItems(f FileDiff) []list.Item {
 	if err != nil {
 		items := make([]list.Item, 0, len(f.KeyChanges))
 		for _, c := range f.KeyChanges {
-			items = append(items, changeItem{c.OldKey, c.NewKey, "(could not read file)", "(could not read file)"})
+			items = append(items, changeItem{
+				oldKey:  c.OldKey,
+				newKey:  c.NewKey,
+				oldLine: "(could not read file)",
+				newLine: "(could not read file)",
+				line:    c.Line,
+				column:  c.Column,
+			})
 		}
 		return items
 	}
@@ -279,6 +296,8 @@ func getChangeItems(f FileDiff) []list.Item {
 				newKey:  keyChange.NewKey,
 				oldLine: "(no matching block found)",
 				newLine: "(no matching block found)",
+				line:    keyChange.Line,
+				column:  keyChange.Column,
 			})
 		} else {
 			for _, block := range blocks {
@@ -289,7 +308,7 @@ func getChangeItems(f FileDiff) []list.Item {
 
 	if len(items) == 0 {
 		return []list.Item{
-			changeItem{"(no key)", "(no key)", "(no matching line found)", "(no matching line found)"},
+			changeItem{oldKey: "(no key)", newKey: "(no key)", oldLine: "(no matching line found)", newLine: "(no matching line found)"},
 		}
 	}
 	return items
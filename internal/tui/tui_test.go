@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -70,3 +72,85 @@ func TestModelView(t *testing.T) {
 		t.Errorf("expected non-empty view")
 	}
 }
+
+func TestChangeItemTitleIncludesPosition(t *testing.T) {
+	item := changeItem{oldKey: "x-foo", newKey: "x-bar", line: 12, column: 5}
+	if got := item.Title(); got != "x-foo → x-bar  (12:5)" {
+		t.Errorf("unexpected title %q", got)
+	}
+	item.line = 0
+	if got := item.Title(); got != "x-foo → x-bar" {
+		t.Errorf("unexpected title without position %q", got)
+	}
+}
+
+func TestDiscoverModelAcceptSkip(t *testing.T) {
+	m := NewDiscoverModel([]MappingCandidate{
+		{Key: "x-codegen-name", Count: 2, Suggestion: "x-speakeasy-name"},
+		{Key: "x-owner", Count: 1},
+		{Key: "x-team", Count: 1},
+	})
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter}) // empty input skips x-owner
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x-team-name")})
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// nolint:errcheck
+	m = model.(DiscoverModel)
+	if !m.Quitting || cmd == nil {
+		t.Error("expected the wizard to finish after the last key")
+	}
+	want := map[string]string{"x-codegen-name": "x-speakeasy-name", "x-team": "x-team-name"}
+	if len(m.Accepted) != len(want) || m.Accepted["x-codegen-name"] != want["x-codegen-name"] || m.Accepted["x-team"] != want["x-team"] {
+		t.Errorf("expected %v, got %v", want, m.Accepted)
+	}
+}
+
+func TestDiscoverModelRejectsSameKey(t *testing.T) {
+	var model tea.Model = NewDiscoverModel([]MappingCandidate{{Key: "x-a"}, {Key: "x-b"}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x-a")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// nolint:errcheck
+	m := model.(DiscoverModel)
+	if m.Index != 0 || len(m.Accepted) != 0 || m.err == "" {
+		t.Errorf("expected the same key to be rejected, got index %d, accepted %v", m.Index, m.Accepted)
+	}
+}
+
+func TestTieModelPickSkip(t *testing.T) {
+	var model tea.Model = NewTieModel([]TieChoice{
+		{Operation: "GET /users", Choices: []string{"cursor", "page", "none"}},
+		{Operation: "GET /orders", Choices: []string{"offset", "page", "none"}},
+		{Operation: "GET /teams", Choices: []string{"cursor", "offset", "none"}},
+	})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}) // stays on the last choice
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// nolint:errcheck
+	m := model.(TieModel)
+	if !m.Quitting || m.Aborted || cmd == nil {
+		t.Error("expected the resolver to finish after the last operation")
+	}
+	if want := map[int]string{0: "page", 2: "none"}; !reflect.DeepEqual(m.Picked, want) {
+		t.Errorf("expected %v, got %v", want, m.Picked)
+	}
+}
+
+func TestTieModelAbort(t *testing.T) {
+	var model tea.Model = NewTieModel([]TieChoice{{Operation: "GET /users", Choices: []string{"cursor", "none"}}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+
+	// nolint:errcheck
+	if m := model.(TieModel); !m.Aborted || !strings.Contains(m.View(), "no rules saved") {
+		t.Errorf("expected ctrl+c to abort, got %+v", m)
+	}
+}
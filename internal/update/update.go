@@ -0,0 +1,289 @@
+// Package update finds newer OpenMorph releases on GitHub and replaces the running binary with one
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LatestReleaseURL is the GitHub API endpoint describing the latest release
+const LatestReleaseURL = "https://api.github.com/repos/developerkunal/OpenMorph/releases/latest"
+
+// binaryName is the executable inside the release archives
+const binaryName = "openmorph"
+
+// requestTimeout bounds the release lookup; downloads get downloadTimeout
+const (
+	requestTimeout  = 10 * time.Second
+	downloadTimeout = 5 * time.Minute
+)
+
+// Release is a published release and its downloadable assets
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Latest fetches the latest release from url, normally LatestReleaseURL
+func Latest(url string) (*Release, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the latest release: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to look up the latest release: %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("invalid release response: %w", err)
+	}
+	if release.Tag == "" {
+		return nil, fmt.Errorf("invalid release response: no tag")
+	}
+	return &release, nil
+}
+
+// IsNewer reports whether the latest version is newer than current. Versions are compared as
+// vMAJOR.MINOR.PATCH; a current version that isn't one, such as a dev build, is never outdated.
+func IsNewer(current, latest string) bool {
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	for i := range c {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// IsRelease reports whether version is a release version rather than a development build
+func IsRelease(version string) bool {
+	_, ok := parseVersion(version)
+	return ok
+}
+
+// parseVersion parses vMAJOR.MINOR.PATCH, ignoring any pre-release or build suffix
+func parseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return parsed, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
+
+// ArchiveName returns the name of the release archive for a platform, as GoReleaser publishes it
+func ArchiveName(tag, goos, goarch string) string {
+	return fmt.Sprintf("%s_%s_%s_%s.tar.gz", binaryName, strings.TrimPrefix(tag, "v"), goos, goarch)
+}
+
+// checksumsName returns the name of the release's checksum file
+func checksumsName(tag string) string {
+	return fmt.Sprintf("%s_%s_checksums.txt", binaryName, strings.TrimPrefix(tag, "v"))
+}
+
+// Apply downloads the release archive for the running platform, verifies it against the release
+// checksums and replaces the executable at exePath with the binary inside
+func Apply(release *Release, exePath string) error {
+	archiveName := ArchiveName(release.Tag, runtime.GOOS, runtime.GOARCH)
+	archive := release.asset(archiveName)
+	if archive == nil {
+		archiveName = strings.TrimSuffix(archiveName, ".tar.gz") + ".zip"
+		if archive = release.asset(archiveName); archive == nil {
+			return fmt.Errorf("release %s has no archive for %s/%s", release.Tag, runtime.GOOS, runtime.GOARCH)
+		}
+	}
+	checksums := release.asset(checksumsName(release.Tag))
+	if checksums == nil {
+		return fmt.Errorf("release %s has no checksums file", release.Tag)
+	}
+
+	client := &http.Client{Timeout: downloadTimeout}
+	sums, err := download(client, checksums.URL)
+	if err != nil {
+		return err
+	}
+	want, err := checksumFor(sums, archiveName)
+	if err != nil {
+		return err
+	}
+	data, err := download(client, archive.URL)
+	if err != nil {
+		return err
+	}
+	if got := sha256.Sum256(data); hex.EncodeToString(got[:]) != want {
+		return fmt.Errorf("checksum mismatch for %s", archiveName)
+	}
+
+	binary, err := extractBinary(archiveName, data)
+	if err != nil {
+		return err
+	}
+	return replaceExecutable(exePath, binary)
+}
+
+// asset returns the release asset with the given name, or nil
+func (r *Release) asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// download fetches url into memory
+func download(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// checksumFor returns the sha256 listed for name in a checksums file ("<hex>  <name>" lines)
+func checksumFor(sums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// extractBinary returns the openmorph executable inside a .tar.gz or .zip archive
+func extractBinary(archiveName string, data []byte) ([]byte, error) {
+	isBinary := func(name string) bool {
+		base := filepath.Base(name)
+		return base == binaryName || base == binaryName+".exe"
+	}
+
+	if strings.HasSuffix(archiveName, ".zip") {
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive %s: %w", archiveName, err)
+		}
+		for _, file := range reader.File {
+			if !isBinary(file.Name) {
+				continue
+			}
+			rc, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer func() { _ = rc.Close() }()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("archive %s has no %s binary", archiveName, binaryName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive %s: %w", archiveName, err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("archive %s has no %s binary", archiveName, binaryName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive %s: %w", archiveName, err)
+		}
+		if header.Typeflag == tar.TypeReg && isBinary(header.Name) {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// replaceExecutable swaps the executable at path for binary. The new file is written next to it
+// and renamed into place; the old one is moved aside first, since Windows cannot overwrite a
+// running executable, and removed when possible.
+func replaceExecutable(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".openmorph-update-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s: %w", dir, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(binary); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0111); err != nil {
+		return err
+	}
+
+	old := path + ".old"
+	_ = os.Remove(old)
+	if err := os.Rename(path, old); err != nil {
+		return fmt.Errorf("cannot replace %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Rename(old, path)
+		return fmt.Errorf("cannot replace %s: %w", path, err)
+	}
+	_ = os.Remove(old)
+	return nil
+}
@@ -0,0 +1,149 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.3", "v1.2.4", true},
+		{"v1.2.3", "v1.10.0", true},
+		{"v1.2.3", "v2.0.0", true},
+		{"v1.2.3", "v1.2.3", false},
+		{"v1.3.0", "v1.2.9", false},
+		{"1.2.3", "v1.2.4", true},
+		{"v1.2.3-rc.1", "v1.2.3", false},
+		{"dev", "v9.9.9", false},
+		{"v1.2.3", "nightly", false},
+	}
+	for _, tt := range tests {
+		if got := IsNewer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+// testArchive returns a .tar.gz holding an openmorph binary with the given contents
+func testArchive(t *testing.T, contents string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, file := range []struct{ name, body string }{{"README.md", "readme"}, {"openmorph", contents}} {
+		if err := tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0755, Size: int64(len(file.body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(file.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// releaseServer serves a release v1.5.0 with an archive for the running platform. When corrupt is
+// set, the archive doesn't match its checksum.
+func releaseServer(t *testing.T, corrupt bool) *httptest.Server {
+	t.Helper()
+	archive := testArchive(t, "new binary")
+	archiveName := ArchiveName("v1.5.0", runtime.GOOS, runtime.GOARCH)
+	sum := sha256.Sum256(archive)
+	if corrupt {
+		archive = append(archive, 0)
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(Release{
+			Tag: "v1.5.0",
+			URL: "https://example.com/releases/v1.5.0",
+			Assets: []Asset{
+				{Name: archiveName, URL: server.URL + "/archive"},
+				{Name: "openmorph_1.5.0_checksums.txt", URL: server.URL + "/checksums"},
+			},
+		})
+	})
+	mux.HandleFunc("/archive", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(archive)
+	})
+	mux.HandleFunc("/checksums", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", hex.EncodeToString(sum[:]), archiveName)
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLatest(t *testing.T) {
+	server := releaseServer(t, false)
+	release, err := Latest(server.URL + "/latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.Tag != "v1.5.0" || len(release.Assets) != 2 {
+		t.Errorf("unexpected release %+v", release)
+	}
+
+	if _, err := Latest(server.URL + "/missing"); err == nil {
+		t.Error("expected an error for a missing release")
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt bool
+		want    string
+	}{
+		{"replaces the binary", false, "new binary"},
+		{"keeps the binary on a checksum mismatch", true, "old binary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := releaseServer(t, tt.corrupt)
+			release, err := Latest(server.URL + "/latest")
+			if err != nil {
+				t.Fatal(err)
+			}
+			exe := filepath.Join(t.TempDir(), "openmorph")
+			if err := os.WriteFile(exe, []byte("old binary"), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			err = Apply(release, exe)
+			if tt.corrupt != (err != nil) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			data, err := os.ReadFile(exe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, data)
+			}
+			if entries, _ := os.ReadDir(filepath.Dir(exe)); len(entries) != 1 {
+				t.Errorf("expected no leftover files, got %d entries", len(entries))
+			}
+		})
+	}
+}